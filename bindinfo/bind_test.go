@@ -295,6 +295,38 @@ func TestPrepareCacheWithBinding(t *testing.T) {
 	require.True(t, tk.MustUseIndex4ExplainFor(res, "ib(b)"), res.Rows())
 }
 
+func TestPrepareCachePointPlanWithBinding(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec(`set tidb_enable_prepared_plan_cache=1`)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, unique key idx_a(a), key idx_b(b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+
+	tk.MustExec("prepare stmt from 'select * from t where a = ?'")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1 1"))
+	require.Equal(t, "t:idx_a", tk.Session().GetSessionVars().StmtCtx.IndexNames[0])
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1 1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// the cached point plan is re-optimized after the binding is created
+	tk.MustExec("create global binding for select * from t where a = 1 using select /*+ use_index(t, idx_b) */ * from t where a = 1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1 1"))
+	require.Equal(t, "t:idx_b", tk.Session().GetSessionVars().StmtCtx.IndexNames[0])
+	tk.MustQuery("select @@last_plan_from_cache, @@last_plan_from_binding").Check(testkit.Rows("0 1"))
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1 1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// the plan cached before the binding is created is reused after the binding is dropped
+	tk.MustExec("drop global binding for select * from t where a = 1")
+	tk.MustExec("set @a = 2")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("2 2"))
+	require.Equal(t, "t:idx_a", tk.Session().GetSessionVars().StmtCtx.IndexNames[0])
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+}
+
 func TestExplain(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 
@@ -236,6 +236,32 @@ func TestResourceGroupHint(t *testing.T) {
 	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 8250 Resource control feature is disabled. Run `SET GLOBAL tidb_enable_resource_control='on'` to enable the feature"))
 }
 
+func TestShowCreateResourceGroup(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set global tidb_enable_resource_control='on'")
+
+	tk.MustExec("create resource group rg1 ru_per_sec=1000")
+	tk.MustExec("create resource group RG2 ru_per_sec=2000 priority=high burstable")
+	tk.MustExec("create resource group `rg``3` ru_per_sec=3000 query_limit=(exec_elapsed='10s', action=cooldown, watch=exact duration='1m')")
+	tk.MustQuery("show create resource group rg2").Check(testkit.Rows("RG2 CREATE RESOURCE GROUP `RG2` RU_PER_SEC=2000, PRIORITY=HIGH, BURSTABLE"))
+	tk.MustQuery("show create resource group `rg``3`").Check(testkit.Rows("rg`3 CREATE RESOURCE GROUP `rg``3` RU_PER_SEC=3000, PRIORITY=MEDIUM, QUERY_LIMIT=(EXEC_ELAPSED=\"10s\" ACTION=COOLDOWN WATCH=EXACT DURATION=\"1m0s\")"))
+
+	// The output of SHOW CREATE RESOURCE GROUP can be used to recreate the same group.
+	for _, name := range []string{"rg1", "RG2", "`rg``3`"} {
+		showSQL := "show create resource group " + name
+		rows := tk.MustQuery(showSQL).Rows()
+		require.Len(t, rows, 1)
+		createSQL := rows[0][1].(string)
+		tk.MustExec("drop resource group " + name)
+		tk.MustExec(createSQL)
+		tk.MustQuery(showSQL).Check(rows)
+	}
+
+	err := tk.QueryToErr("show create resource group not_exist")
+	require.ErrorContains(t, err, "Unknown resource group 'not_exist'")
+}
+
 func TestAlreadyExistsDefaultResourceGroup(t *testing.T) {
 	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/domain/infosync/managerAlreadyCreateSomeGroups", `return(true)`))
 	defer func() {
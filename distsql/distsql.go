@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/tracing"
 	"github.com/pingcap/tidb/util/trxevents"
@@ -77,11 +78,14 @@ func Select(ctx context.Context, sctx sessionctx.Context, kvReq *kv.Request, fie
 	}
 
 	ctx = WithSQLKvExecCounterInterceptor(ctx, sctx.GetSessionVars().StmtCtx)
+	// The statement traced by `TRACE FORMAT='cop_regions'` needs the execution info of every coprocessor
+	// response, even if tidb_enable_collect_execution_info is off.
+	_, traceCopRegions := ctx.Value(execdetails.CopRegionDetailsKey).(*execdetails.CopRegionDetails)
 	option := &kv.ClientSendOption{
 		SessionMemTracker:          sctx.GetSessionVars().MemTracker,
 		EnabledRateLimitAction:     enabledRateLimitAction,
 		EventCb:                    eventCb,
-		EnableCollectExecutionInfo: config.GetGlobalConfig().Instance.EnableCollectExecutionInfo.Load(),
+		EnableCollectExecutionInfo: config.GetGlobalConfig().Instance.EnableCollectExecutionInfo.Load() || traceCopRegions,
 	}
 
 	if kvReq.StoreType == kv.TiFlash {
@@ -101,6 +105,7 @@ func Select(ctx context.Context, sctx sessionctx.Context, kvReq *kv.Request, fie
 	// kvReq.MemTracker is used to trace and control memory usage in DistSQL layer;
 	// for selectResult, we just use the kvReq.MemTracker prepared for co-processor
 	// instead of creating a new one for simplification.
+	storeObserver, _ := ctx.Value(CopStoreObserverKey).(CopStoreObserver)
 	return &selectResult{
 		label:              "dag",
 		resp:               resp,
@@ -113,6 +118,7 @@ func Select(ctx context.Context, sctx sessionctx.Context, kvReq *kv.Request, fie
 		storeType:          kvReq.StoreType,
 		paging:             kvReq.Paging.Enable,
 		distSQLConcurrency: kvReq.Concurrency,
+		storeObserver:      storeObserver,
 	}, nil
 }
 
@@ -66,6 +66,11 @@ func (builder *RequestBuilder) Build() (*kv.Request, error) {
 			panic("request builder get staleness option fail")
 		}
 	})
+	failpoint.Inject("assertStaleReadReplicaRead", func(val failpoint.Value) {
+		if builder.IsStaleness && builder.ReplicaRead != kv.ReplicaReadType(val.(int)) {
+			panic("stale read replica read option fail")
+		}
+	})
 	err := builder.verifyTxnScope()
 	if err != nil {
 		builder.err = err
@@ -276,6 +281,7 @@ func (*RequestBuilder) getKVPriority(sv *variable.SessionVars) int {
 // SetFromSessionVars sets the following fields for "kv.Request" from session variables:
 // "Concurrency", "IsolationLevel", "NotFillCache", "TaskID", "Priority", "ReplicaRead",
 // "ResourceGroupTagger", "ResourceGroupName"
+// The "ReplicaRead" of a stale read request depends on "IsStaleness", so SetIsStaleness should be called before it.
 func (builder *RequestBuilder) SetFromSessionVars(sv *variable.SessionVars) *RequestBuilder {
 	distsqlConcurrency := sv.DistSQLScanConcurrency()
 	if builder.Request.Concurrency == 0 {
@@ -294,6 +300,10 @@ func (builder *RequestBuilder) SetFromSessionVars(sv *variable.SessionVars) *Req
 	} else {
 		builder.Request.IsolationLevel = builder.getIsolationLevel()
 	}
+	if builder.IsStaleness && sv.StaleReadPreferFollower && !replicaReadType.IsFollowerRead() {
+		// Read the historical data from the followers to offload the leaders.
+		replicaReadType = kv.ReplicaReadFollower
+	}
 	builder.Request.NotFillCache = sv.StmtCtx.NotFillCache
 	builder.Request.TaskID = sv.StmtCtx.TaskID
 	builder.Request.Priority = builder.getKVPriority(sv)
@@ -324,6 +334,14 @@ func (builder *RequestBuilder) SetConcurrency(concurrency int) *RequestBuilder {
 	return builder
 }
 
+// SetMaxConcurrency caps "Concurrency" for "kv.Request", a non-positive value means no cap.
+func (builder *RequestBuilder) SetMaxConcurrency(maxConcurrency int) *RequestBuilder {
+	if maxConcurrency > 0 && builder.Request.Concurrency > maxConcurrency {
+		builder.Request.Concurrency = maxConcurrency
+	}
+	return builder
+}
+
 // SetTiDBServerID sets "TiDBServerID" for "kv.Request"
 //
 //	ServerID is a unique id of TiDB instance among the cluster.
@@ -425,6 +443,12 @@ func (builder *RequestBuilder) SetConnID(connID uint64) *RequestBuilder {
 	return builder
 }
 
+// SetRangesPerTask sets "RangesPerTask" for "kv.Request".
+func (builder *RequestBuilder) SetRangesPerTask(rangesPerTask int) *RequestBuilder {
+	builder.Request.RangesPerTask = rangesPerTask
+	return builder
+}
+
 // TableHandleRangesToKVRanges convert table handle ranges to "KeyRanges" for multiple tables.
 func TableHandleRangesToKVRanges(sc *stmtctx.StatementContext, tid []int64, isCommonHandle bool, ranges []*ranger.Range, fb *statistics.QueryFeedback) (*kv.KeyRanges, error) {
 	if !isCommonHandle {
@@ -681,6 +681,53 @@ func TestScanLimitConcurrency(t *testing.T) {
 	}
 }
 
+func TestRequestBuilderStaleReadPreferFollower(t *testing.T) {
+	for _, tt := range []struct {
+		isStaleness    bool
+		preferFollower bool
+		replicaRead    kv.ReplicaReadType
+		expect         kv.ReplicaReadType
+	}{
+		{false, false, kv.ReplicaReadLeader, kv.ReplicaReadLeader},
+		{true, false, kv.ReplicaReadLeader, kv.ReplicaReadLeader},
+		{false, true, kv.ReplicaReadLeader, kv.ReplicaReadLeader},
+		{true, true, kv.ReplicaReadLeader, kv.ReplicaReadFollower},
+		{true, true, kv.ReplicaReadMixed, kv.ReplicaReadMixed},
+	} {
+		vars := variable.NewSessionVars(nil)
+		vars.SetReplicaRead(tt.replicaRead)
+		vars.StaleReadPreferFollower = tt.preferFollower
+		actual, err := (&RequestBuilder{}).
+			SetIsStaleness(tt.isStaleness).
+			SetFromSessionVars(vars).
+			Build()
+		require.NoError(t, err)
+		require.Equal(t, tt.expect, actual.ReplicaRead)
+	}
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	vars := variable.NewSessionVars(nil)
+	vars.Concurrency.SetDistSQLScanConcurrency(10)
+	for _, tt := range []struct {
+		maxConcurrency int
+		concurrency    int
+	}{
+		{0, 10},
+		{-1, 10},
+		{3, 3},
+		{10, 10},
+		{20, 10},
+	} {
+		actual, err := (&RequestBuilder{}).
+			SetFromSessionVars(vars).
+			SetMaxConcurrency(tt.maxConcurrency).
+			Build()
+		require.NoError(t, err)
+		require.Equal(t, tt.concurrency, actual.Concurrency)
+	}
+}
+
 func getExpectedRanges(tid int64, hrs []*handleRange) []kv.KeyRange {
 	krs := make([]kv.KeyRange, 0, len(hrs))
 	for _, hr := range hrs {
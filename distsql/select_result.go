@@ -319,6 +319,9 @@ type selectResult struct {
 	// distSQLConcurrency and paging are only for collecting information, and they don't affect the process of execution.
 	distSQLConcurrency int
 	paging             bool
+
+	// storeObserver observes the responses from every store if it's not nil.
+	storeObserver CopStoreObserver
 }
 
 func (r *selectResult) fetchResp(ctx context.Context) error {
@@ -403,6 +406,15 @@ func (r *selectResult) fetchResp(ctx context.Context) error {
 		}
 		r.partialCount++
 
+		if r.storeObserver != nil {
+			// The responses are observed no matter whether the runtime stats are collected.
+			if calleeStore, ok := resultSubset.(CopCalleeStore); ok {
+				if addr, metStoreErr := calleeStore.GetCalleeStore(); addr != "" {
+					r.storeObserver.ObserveCopResponse(addr, metStoreErr)
+				}
+			}
+		}
+
 		hasStats, ok := resultSubset.(CopRuntimeStats)
 		if ok {
 			copStats := hasStats.GetCopRuntimeStats()
@@ -514,8 +526,23 @@ func (r *selectResult) readFromChunk(ctx context.Context, chk *chunk.Chunk) erro
 	return nil
 }
 
+type copStoreObserverKeyType struct{}
+
+// CopStoreObserverKey is used to carry a CopStoreObserver in context.Context, which is passed to Select.
+var CopStoreObserverKey = copStoreObserverKeyType{}
+
+// CopStoreObserver observes the coprocessor responses from every store.
+type CopStoreObserver interface {
+	// ObserveCopResponse is called for every coprocessor response, failed indicates the request
+	// has met store errors before it's served.
+	ObserveCopResponse(storeAddr string, failed bool)
+}
+
 func (r *selectResult) updateCopRuntimeStats(ctx context.Context, copStats *copr.CopRuntimeStats, respTime time.Duration) {
 	callee := copStats.CalleeAddress
+	if details, ok := ctx.Value(execdetails.CopRegionDetailsKey).(*execdetails.CopRegionDetails); ok && callee != "" {
+		details.Record(copStats.RegionID, callee, copStats.TimeDetail.WaitTime, copStats.TimeDetail.ProcessTime, copStats.BackoffTime)
+	}
 	if r.rootPlanID <= 0 || r.ctx.GetSessionVars().StmtCtx.RuntimeStatsColl == nil || callee == "" {
 		return
 	}
@@ -651,6 +678,13 @@ type CopRuntimeStats interface {
 	GetCopRuntimeStats() *copr.CopRuntimeStats
 }
 
+// CopCalleeStore is an interface uses to get the store serving the result.
+type CopCalleeStore interface {
+	// GetCalleeStore gets the address of the store serving the result, and whether the request has met
+	// store errors before it's served.
+	GetCalleeStore() (addr string, metStoreErr bool)
+}
+
 type selectResultRuntimeStats struct {
 	copRespTime             []time.Duration
 	procKeys                []int64
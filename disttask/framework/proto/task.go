@@ -87,6 +87,7 @@ type Subtask struct {
 	StartTime   uint64
 	EndTime     time.Time
 	Meta        []byte
+	Error       []byte
 }
 
 // NewSubtask create a new subtask.
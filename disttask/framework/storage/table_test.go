@@ -213,6 +213,14 @@ func TestSubTaskTable(t *testing.T) {
 	subtasks, err = sm.GetSucceedSubtasksByStep(2, proto.StepInit)
 	require.NoError(t, err)
 	require.Len(t, subtasks, 1)
+
+	err = sm.UpdateSubtaskStateAndError(2, proto.TaskStateFailed, "mock error")
+	require.NoError(t, err)
+	subtasks, err = sm.GetSubtasksByStep(2, proto.StepInit)
+	require.NoError(t, err)
+	require.Len(t, subtasks, 1)
+	require.Equal(t, proto.TaskStateFailed, subtasks[0].State)
+	require.Equal(t, []byte("mock error"), subtasks[0].Error)
 }
 
 func TestBothGlobalAndSubTaskTable(t *testing.T) {
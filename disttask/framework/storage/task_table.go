@@ -254,6 +254,7 @@ func row2SubTask(r chunk.Row) *proto.Subtask {
 		State:       r.GetString(8),
 		Meta:        r.GetBytes(12),
 		StartTime:   r.GetUint64(10),
+		Error:       r.GetBytes(13),
 	}
 	tid, err := strconv.Atoi(r.GetString(3))
 	if err != nil {
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/pingcap/errors"
@@ -255,6 +256,116 @@ func GetTaskImportedRows(jobID int64) (uint64, error) {
 	return importedRows, nil
 }
 
+// FileStatus is the import status of a source file in a subtask.
+type FileStatus struct {
+	SubtaskID    int64
+	Path         string
+	ReadBytes    int64
+	ImportedRows uint64
+	State        string
+	Error        string
+}
+
+// GetTaskFileStatus gets the import status of each source file of a job.
+// Note: the read bytes and imported rows of a file are only available after
+// the subtask which imports it finished. If the job failed before submitting
+// its task, nil is returned.
+func GetTaskFileStatus(jobID int64) ([]*FileStatus, error) {
+	globalTaskManager, err := storage.GetTaskManager()
+	if err != nil {
+		return nil, err
+	}
+	globalTask, err := globalTaskManager.GetGlobalTaskByKey(TaskKey(jobID))
+	if err != nil || globalTask == nil {
+		return nil, err
+	}
+	subtasks, err := globalTaskManager.GetSubtasksByStep(globalTask.ID, Import)
+	if err != nil {
+		return nil, err
+	}
+	return subtasksToFileStatus(subtasks)
+}
+
+func subtasksToFileStatus(subtasks []*proto.Subtask) ([]*FileStatus, error) {
+	var statuses []*FileStatus
+	for _, subtask := range subtasks {
+		var subtaskMeta SubtaskMeta
+		if err := json.Unmarshal(subtask.Meta, &subtaskMeta); err != nil {
+			return nil, err
+		}
+		results := make(map[string]FileResult, len(subtaskMeta.FileResults))
+		for _, r := range subtaskMeta.FileResults {
+			results[r.Path] = r
+		}
+		files := mergeFileResults(chunksToFileResults(subtaskMeta.Chunks))
+		subtaskErr := string(subtask.Error)
+		// the error of a subtask is attributed to the file which causes it,
+		// if it's not caused by a file, it's attributed to all files of the subtask.
+		errFile := ""
+		for _, r := range files {
+			if isFileError(subtaskErr, r.Path) {
+				errFile = r.Path
+				break
+			}
+		}
+		for _, r := range files {
+			result := results[r.Path]
+			status := &FileStatus{
+				SubtaskID:    subtask.ID,
+				Path:         r.Path,
+				ReadBytes:    result.ReadBytes,
+				ImportedRows: result.ImportedRows,
+				State:        subtask.State,
+			}
+			if errFile == "" || errFile == r.Path {
+				status.Error = subtaskErr
+			}
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
+}
+
+// annotateFileError annotates the error with the source file which causes it,
+// so it can be attributed to the file by subtasksToFileStatus.
+func annotateFileError(err error, path string) error {
+	return errors.Annotate(err, fileErrorPrefix(path))
+}
+
+func isFileError(errMsg string, path string) bool {
+	return strings.Contains(errMsg, fileErrorPrefix(path)+": ")
+}
+
+func fileErrorPrefix(path string) string {
+	return "import file " + path
+}
+
+func chunksToFileResults(chunks []Chunk) []FileResult {
+	results := make([]FileResult, 0, len(chunks))
+	for _, chunk := range chunks {
+		results = append(results, FileResult{Path: chunk.Path})
+	}
+	return results
+}
+
+// mergeFileResults merges the results of chunks which belong to the same file,
+// the order of files is kept.
+func mergeFileResults(results []FileResult) []FileResult {
+	merged := make([]FileResult, 0, len(results))
+	fileIdx := make(map[string]int, len(results))
+	for _, r := range results {
+		idx, ok := fileIdx[r.Path]
+		if !ok {
+			fileIdx[r.Path] = len(merged)
+			merged = append(merged, r)
+			continue
+		}
+		merged[idx].ReadBytes += r.ReadBytes
+		merged[idx].ImportedRows += r.ImportedRows
+	}
+	return merged
+}
+
 // TaskKey returns the task key for a job.
 func TaskKey(jobID int64) string {
 	return fmt.Sprintf("%s/%d", proto.ImportInto, jobID)
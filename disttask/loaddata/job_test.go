@@ -0,0 +1,91 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loaddata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/disttask/framework/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeFileResults(t *testing.T) {
+	require.Empty(t, mergeFileResults(nil))
+	merged := mergeFileResults([]FileResult{
+		{Path: "b.csv", ReadBytes: 10, ImportedRows: 1},
+		{Path: "a.csv", ReadBytes: 20, ImportedRows: 2},
+		{Path: "b.csv", ReadBytes: 30, ImportedRows: 3},
+	})
+	require.Equal(t, []FileResult{
+		{Path: "b.csv", ReadBytes: 40, ImportedRows: 4},
+		{Path: "a.csv", ReadBytes: 20, ImportedRows: 2},
+	}, merged)
+}
+
+func TestSubtasksToFileStatus(t *testing.T) {
+	finishedMeta, err := json.Marshal(SubtaskMeta{
+		ID: 1,
+		Chunks: []Chunk{
+			{Path: "a.csv", Offset: 0, EndOffset: 100},
+			{Path: "a.csv", Offset: 100, EndOffset: 150},
+			{Path: "b.csv", Offset: 0, EndOffset: 60},
+		},
+		FileResults: []FileResult{
+			{Path: "a.csv", ReadBytes: 150, ImportedRows: 15},
+			{Path: "b.csv", ReadBytes: 60, ImportedRows: 6},
+		},
+	})
+	require.NoError(t, err)
+	failedMeta, err := json.Marshal(SubtaskMeta{
+		ID:     2,
+		Chunks: []Chunk{{Path: "c.csv", Offset: 0, EndOffset: 80}},
+	})
+	require.NoError(t, err)
+	fileFailedMeta, err := json.Marshal(SubtaskMeta{
+		ID: 4,
+		Chunks: []Chunk{
+			{Path: "e.csv", Offset: 0, EndOffset: 80},
+			{Path: "e.csv.1", Offset: 0, EndOffset: 80},
+		},
+	})
+	require.NoError(t, err)
+	fileErr := annotateFileError(errors.New("mock file error"), "e.csv.1")
+	pendingMeta, err := json.Marshal(SubtaskMeta{
+		ID:     3,
+		Chunks: []Chunk{{Path: "d.csv", Offset: 0, EndOffset: 80}},
+	})
+	require.NoError(t, err)
+
+	statuses, err := subtasksToFileStatus([]*proto.Subtask{
+		{ID: 11, State: proto.TaskStateSucceed, Meta: finishedMeta},
+		{ID: 12, State: proto.TaskStateFailed, Meta: failedMeta, Error: []byte("mock error")},
+		{ID: 13, State: proto.TaskStatePending, Meta: pendingMeta},
+		{ID: 14, State: proto.TaskStateFailed, Meta: fileFailedMeta, Error: []byte(fileErr.Error())},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*FileStatus{
+		{SubtaskID: 11, Path: "a.csv", ReadBytes: 150, ImportedRows: 15, State: proto.TaskStateSucceed},
+		{SubtaskID: 11, Path: "b.csv", ReadBytes: 60, ImportedRows: 6, State: proto.TaskStateSucceed},
+		{SubtaskID: 12, Path: "c.csv", State: proto.TaskStateFailed, Error: "mock error"},
+		{SubtaskID: 13, Path: "d.csv", State: proto.TaskStatePending},
+		{SubtaskID: 14, Path: "e.csv", State: proto.TaskStateFailed},
+		{SubtaskID: 14, Path: "e.csv.1", State: proto.TaskStateFailed, Error: "import file e.csv.1: mock file error"},
+	}, statuses)
+
+	_, err = subtasksToFileStatus([]*proto.Subtask{{ID: 15, Meta: []byte("invalid")}})
+	require.Error(t, err)
+}
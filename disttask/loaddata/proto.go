@@ -60,6 +60,9 @@ type SubtaskMeta struct {
 	Chunks   []Chunk
 	Checksum Checksum
 	Result   Result
+	// FileResults records the import result of each source file in this subtask,
+	// it's filled when the subtask finished.
+	FileResults []FileResult
 }
 
 // SharedVars is the shared variables between subtask and minimal tasks.
@@ -71,8 +74,9 @@ type SharedVars struct {
 	IndexEngine   *backend.OpenedEngine
 	Progress      *asyncloaddata.Progress
 
-	mu       sync.Mutex
-	Checksum *verification.KVChecksum
+	mu          sync.Mutex
+	Checksum    *verification.KVChecksum
+	FileResults []FileResult
 }
 
 // MinimalTaskMeta is the minimal task of LoadData.
@@ -98,6 +102,13 @@ type Chunk struct {
 	Timestamp    int64
 }
 
+// FileResult records the import result of a source file.
+type FileResult struct {
+	Path         string
+	ReadBytes    int64
+	ImportedRows uint64
+}
+
 // Checksum records the checksum information.
 type Checksum struct {
 	Sum  uint64
@@ -51,13 +51,19 @@ func (e *ImportMinimalTaskExecutor) Run(ctx context.Context) error {
 	})
 	chunkCheckpoint := toChunkCheckpoint(e.task.Chunk)
 	sharedVars := e.task.SharedVars
-	if err := importer.ProcessChunk(ctx, &chunkCheckpoint, sharedVars.TableImporter, sharedVars.DataEngine, sharedVars.IndexEngine, sharedVars.Progress, logger); err != nil {
-		return err
+	var chunkProgress importer.ChunkProgress
+	if err := importer.ProcessChunk(ctx, &chunkCheckpoint, sharedVars.TableImporter, sharedVars.DataEngine, sharedVars.IndexEngine, sharedVars.Progress, &chunkProgress, logger); err != nil {
+		return annotateFileError(err, e.task.Chunk.Path)
 	}
 
 	sharedVars.mu.Lock()
 	defer sharedVars.mu.Unlock()
 	sharedVars.Checksum.Add(&chunkCheckpoint.Checksum)
+	sharedVars.FileResults = append(sharedVars.FileResults, FileResult{
+		Path:         e.task.Chunk.Path,
+		ReadBytes:    chunkProgress.ReadBytes.Load(),
+		ImportedRows: chunkProgress.DeliveredRows.Load(),
+	})
 	return nil
 }
 
@@ -1078,6 +1078,10 @@ const (
 	ErrLoadDataLocalUnsupportedOption      = 8172
 	ErrLoadDataPreCheckFailed              = 8173
 	ErrBRJobNotFound                       = 8174
+	ErrBatchPointGetTooLarge               = 8175
+	ErrAdminCheckForeignKey                = 8176
+	ErrRowChecksumMismatch                 = 8177
+	ErrMemTableResultTooLarge              = 8178
 
 	// Error codes used by TiDB ddl package
 	ErrUnsupportedDDLOperation            = 8200
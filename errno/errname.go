@@ -1114,6 +1114,11 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 
 	ErrInvalidTableSample: mysql.Message("Invalid TABLESAMPLE: %s", nil),
 
+	ErrBatchPointGetTooLarge:  mysql.Message("The number of keys %d of batch point get exceeds tidb_batch_point_get_max_size %d, consider joining with a temporary table of the values instead", nil),
+	ErrAdminCheckForeignKey:   mysql.Message("Foreign key %s is inconsistent, the child row with handle %s and values %s has no parent row", nil),
+	ErrRowChecksumMismatch:    mysql.Message("Row checksum mismatch in table %s, handle %s, stored checksum %d != calculated checksum %d", nil),
+	ErrMemTableResultTooLarge: mysql.Message("The result of memory table %s exceeds tidb_memtable_max_result_bytes %d bytes, consider adding filters to the query", nil),
+
 	ErrJSONObjectKeyTooLong:        mysql.Message("TiDB does not yet support JSON objects with the key length >= 65536", nil),
 	ErrPartitionStatsMissing:       mysql.Message("Build global-level stats failed due to missing partition-level stats: %s", nil),
 	ErrPartitionColumnStatsMissing: mysql.Message("Build global-level stats failed due to missing partition-level column stats: %s, please run analyze table to refresh columns of all partitions", nil),
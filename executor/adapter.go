@@ -520,7 +520,7 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 		sctx.GetSessionVars().MemTracker.SetBytesLimit(sctx.GetSessionVars().StmtCtx.MemQuotaQuery)
 	}
 
-	e, err := a.buildExecutor()
+	e, err := a.buildExecutor(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -764,11 +764,12 @@ func (a *ExecStmt) handleNoDelay(ctx context.Context, e Executor, isPessimistic
 	}
 
 	// If the executor doesn't return any result to the client, we execute it without delay.
-	if toCheck.Schema().Len() == 0 {
+	// The DML with RETURNING clause is also executed without delay, and the modified rows are returned after that.
+	if toCheck.Schema().Len() == 0 || getDMLReturning(toCheck) != nil {
 		handled = !isExplainAnalyze
 		if isPessimistic {
-			err := a.handlePessimisticDML(ctx, toCheck)
-			return handled, nil, err
+			r, err := a.handlePessimisticDML(ctx, toCheck)
+			return handled, r, err
 		}
 		r, err := a.handleNoDelayExecutor(ctx, toCheck)
 		return handled, r, err
@@ -949,17 +950,28 @@ func (a *ExecStmt) handleNoDelayExecutor(ctx context.Context, e Executor) (sqlex
 		return nil, err
 	}
 	err = a.handleStmtForeignKeyTrigger(ctx, e)
-	return nil, err
+	if err != nil {
+		return nil, err
+	}
+	if returning := getDMLReturning(e); returning != nil {
+		return &chunkRowRecordSet{
+			rows:     returning.resultRows(),
+			fields:   colNames2ResultFields(e.Schema(), returning.names, sctx.GetSessionVars().CurrentDB),
+			e:        e,
+			execStmt: a,
+		}, nil
+	}
+	return nil, nil
 }
 
-func (a *ExecStmt) handlePessimisticDML(ctx context.Context, e Executor) (err error) {
+func (a *ExecStmt) handlePessimisticDML(ctx context.Context, e Executor) (rs sqlexec.RecordSet, err error) {
 	sctx := a.Ctx
 	// Do not activate the transaction here.
 	// When autocommit = 0 and transaction in pessimistic mode,
 	// statements like set xxx = xxx; should not active the transaction.
 	txn, err := sctx.Txn(false)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	txnCtx := sctx.GetSessionVars().TxnCtx
 	defer func() {
@@ -987,7 +999,7 @@ func (a *ExecStmt) handlePessimisticDML(ctx context.Context, e Executor) (err er
 	txnManager := sessiontxn.GetTxnManager(a.Ctx)
 	err = txnManager.OnPessimisticStmtStart(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
 		isSuccessful := err == nil
@@ -1005,9 +1017,9 @@ func (a *ExecStmt) handlePessimisticDML(ctx context.Context, e Executor) (err er
 		}
 
 		startTime := time.Now()
-		_, err = a.handleNoDelayExecutor(ctx, e)
+		rs, err = a.handleNoDelayExecutor(ctx, e)
 		if !txn.Valid() {
-			return err
+			return rs, err
 		}
 
 		if isFirstAttempt {
@@ -1024,35 +1036,36 @@ func (a *ExecStmt) handlePessimisticDML(ctx context.Context, e Executor) (err er
 				if exeerrors.ErrDeadlock.Equal(err) {
 					metrics.StatementDeadlockDetectDuration.Observe(time.Since(startTime).Seconds())
 				}
-				return err
+				return nil, err
 			}
 			continue
 		}
 		keys, err1 := txn.(pessimisticTxn).KeysNeedToLock()
 		if err1 != nil {
-			return err1
+			return nil, err1
 		}
 		keys = txnCtx.CollectUnchangedKeysForLock(keys)
 		if len(keys) == 0 {
-			return nil
+			return rs, nil
 		}
 		keys = filterTemporaryTableKeys(sctx.GetSessionVars(), keys)
 		seVars := sctx.GetSessionVars()
 		keys = filterLockTableKeys(seVars.StmtCtx, keys)
 		lockCtx, err := newLockCtx(sctx, seVars.LockWaitTimeout, len(keys))
 		if err != nil {
-			return err
+			return nil, err
 		}
 		var lockKeyStats *util.LockKeysDetails
 		ctx = context.WithValue(ctx, util.LockKeysDetailCtxKey, &lockKeyStats)
 		startLocking := time.Now()
 		err = txn.LockKeys(ctx, lockCtx, keys...)
 		a.phaseLockDurations[0] += time.Since(startLocking)
+		recordLockEvent(ctx, keys, lockKeyStats, time.Since(startLocking), err)
 		if lockKeyStats != nil {
 			seVars.StmtCtx.MergeLockKeysExecDetails(lockKeyStats)
 		}
 		if err == nil {
-			return nil
+			return rs, nil
 		}
 		e, err = a.handlePessimisticLockError(ctx, err)
 		if err != nil {
@@ -1060,7 +1073,7 @@ func (a *ExecStmt) handlePessimisticDML(ctx context.Context, e Executor) (err er
 			if exeerrors.ErrDeadlock.Equal(err) {
 				metrics.StatementDeadlockDetectDuration.Observe(time.Since(startLocking).Seconds())
 			}
-			return err
+			return nil, err
 		}
 	}
 }
@@ -1115,7 +1128,7 @@ func (a *ExecStmt) handlePessimisticLockError(ctx context.Context, lockErr error
 
 	a.resetPhaseDurations()
 
-	e, err := a.buildExecutor()
+	e, err := a.buildExecutor(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1141,7 +1154,7 @@ type pessimisticTxn interface {
 }
 
 // buildExecutor build an executor from plan, prepared statement may need additional procedure.
-func (a *ExecStmt) buildExecutor() (Executor, error) {
+func (a *ExecStmt) buildExecutor(goCtx context.Context) (Executor, error) {
 	defer func(start time.Time) { a.phaseBuildDurations[0] += time.Since(start) }(time.Now())
 	ctx := a.Ctx
 	stmtCtx := ctx.GetSessionVars().StmtCtx
@@ -1155,6 +1168,9 @@ func (a *ExecStmt) buildExecutor() (Executor, error) {
 	}
 
 	b := newExecutorBuilder(ctx, a.InfoSchema, a.Ti)
+	if ctx.GetSessionVars().EnableStreamingAggPreview {
+		b.aggPreviewHandler, _ = goCtx.Value(AggPreviewHandlerKey).(AggPreviewHandler)
+	}
 	e := b.build(a.Plan)
 	if b.err != nil {
 		return nil, errors.Trace(b.err)
@@ -1182,9 +1198,38 @@ func (a *ExecStmt) buildExecutor() (Executor, error) {
 		e = executorExec.stmtExec
 	}
 	a.isSelectForUpdate = b.hasLock && (!stmtCtx.InDeleteStmt && !stmtCtx.InUpdateStmt && !stmtCtx.InInsertStmt)
+	a.attachRURuntimeStats()
 	return e, nil
 }
 
+// attachRURuntimeStats collects the RU consumed by the statement into the RU usage of the session,
+// which is shown by the CONNECTION_RESOURCE_USAGE table.
+func (a *ExecStmt) attachRURuntimeStats() {
+	sessVars := a.Ctx.GetSessionVars()
+	if sessVars.RUUsage == nil || sessVars.InRestrictedSQL || len(sessVars.ResourceGroupName) == 0 {
+		return
+	}
+	store, ok := a.Ctx.GetStore().(interface {
+		CreateRURuntimeStats(uint64) *util.RURuntimeStats
+	})
+	if !ok {
+		return
+	}
+	// Only the statements which have activated the transaction send requests to the storage.
+	txn, err := a.Ctx.Txn(false)
+	if err != nil || !txn.Valid() {
+		return
+	}
+	// The read ts will be used to identify this SQL, so that the runtime stats could aggregate the RU stats
+	// beneath the KV storage client. It's the ts carried by the requests of the statement, which differs from
+	// the start ts of the transaction for the stale reads and the read committed statements.
+	readTS, err := sessiontxn.GetTxnManager(a.Ctx).GetStmtReadTS()
+	if err != nil {
+		return
+	}
+	sessVars.RUUsage.AttachStmtRUStats(store.CreateRURuntimeStats(readTS))
+}
+
 func (a *ExecStmt) openExecutor(ctx context.Context, e Executor) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -22,7 +22,6 @@ import (
 	"unsafe"
 
 	"github.com/pingcap/tidb/expression"
-	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
@@ -278,6 +277,9 @@ func (e *groupConcatDistinct) GetTruncated() *int32 {
 type sortRow struct {
 	buffer  *bytes.Buffer
 	byItems []*types.Datum
+	// distinctKey is the encoded arguments of the row, it's only set by groupConcatDistinctOrder
+	// to dedup the rows when merging the partial results.
+	distinctKey string
 }
 
 type topNRows struct {
@@ -370,6 +372,21 @@ func (h *topNRows) tryToAdd(row sortRow) (truncated bool, memDelta int64) {
 	return true, memDelta
 }
 
+// merge adds the rows of another topNRows which has the same order and limit.
+func (h *topNRows) merge(src *topNRows) (truncated bool, memDelta int64) {
+	for _, row := range src.rows {
+		rowTruncated, rowMemDelta := h.tryToAdd(row)
+		truncated = truncated || rowTruncated
+		memDelta += rowMemDelta
+		if h.err != nil {
+			return truncated, memDelta
+		}
+	}
+	// The rows popped from src are ordered after all the rows left in src, so they are popped from h as well.
+	h.isSepTruncated = h.isSepTruncated || src.isSepTruncated
+	return truncated, memDelta
+}
+
 func (h *topNRows) reset() {
 	h.rows = h.rows[:0]
 	h.err = nil
@@ -481,10 +498,21 @@ func (e *groupConcatOrder) UpdatePartialResult(sctx sessionctx.Context, rowsInGr
 	return memDelta, nil
 }
 
+// MergePartialResult merges the rows kept by the partial workers, they are sorted when appending the final result.
+// A row truncated by a partial worker is still long enough, because more rows are ordered before it after merging.
 func (e *groupConcatOrder) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
-	// If order by exists, the parallel hash aggregation is forbidden in executorBuilder.buildHashAgg.
-	// So MergePartialResult will not be called.
-	return 0, plannercore.ErrInternal.GenWithStack("groupConcatOrder.MergePartialResult should not be called")
+	p1, p2 := (*partialResult4GroupConcatOrder)(src), (*partialResult4GroupConcatOrder)(dst)
+	p2.topN.sctx = sctx
+	truncated, memDelta := p2.topN.merge(p1.topN)
+	if p2.topN.err != nil {
+		return memDelta, p2.topN.err
+	}
+	if truncated {
+		if err := e.handleTruncateError(sctx); err != nil {
+			return memDelta, err
+		}
+	}
+	return memDelta, nil
 }
 
 // SetTruncated will be called in `executorBuilder#buildHashAgg` with duck-type.
@@ -581,8 +609,9 @@ func (e *groupConcatDistinctOrder) UpdatePartialResult(sctx sessionctx.Context,
 		memDelta += p.valSet.Insert(joinedVal)
 		memDelta += int64(len(joinedVal))
 		sortRow := sortRow{
-			buffer:  buffer,
-			byItems: make([]*types.Datum, 0, len(e.byItems)),
+			buffer:      buffer,
+			byItems:     make([]*types.Datum, 0, len(e.byItems)),
+			distinctKey: joinedVal,
 		}
 		for _, byItem := range e.byItems {
 			d, err := byItem.Expr.Eval(row)
@@ -606,9 +635,37 @@ func (e *groupConcatDistinctOrder) UpdatePartialResult(sctx sessionctx.Context,
 }
 
 func (e *groupConcatDistinctOrder) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
-	// If order by exists, the parallel hash aggregation is forbidden in executorBuilder.buildHashAgg.
-	// So MergePartialResult will not be called.
-	return 0, plannercore.ErrInternal.GenWithStack("groupConcatDistinctOrder.MergePartialResult should not be called")
+	p1, p2 := (*partialResult4GroupConcatOrderDistinct)(src), (*partialResult4GroupConcatOrderDistinct)(dst)
+	p2.topN.sctx = sctx
+	for _, row := range p1.topN.rows {
+		if p2.valSet.Exist(row.distinctKey) {
+			continue
+		}
+		memDelta += p2.valSet.Insert(row.distinctKey)
+		memDelta += int64(len(row.distinctKey))
+		truncated, sortRowMemSize := p2.topN.tryToAdd(row)
+		memDelta += sortRowMemSize
+		if p2.topN.err != nil {
+			return memDelta, p2.topN.err
+		}
+		if truncated {
+			if err := e.handleTruncateError(sctx); err != nil {
+				return memDelta, err
+			}
+		}
+	}
+	p2.topN.isSepTruncated = p2.topN.isSepTruncated || p1.topN.isSepTruncated
+	return memDelta, nil
+}
+
+// SetTruncated will be called in `executorBuilder#buildHashAgg` with duck-type.
+func (e *groupConcatDistinctOrder) SetTruncated(t *int32) {
+	e.truncated = t
+}
+
+// GetTruncated will be called in `executorBuilder#buildHashAgg` with duck-type.
+func (e *groupConcatDistinctOrder) GetTruncated() *int32 {
+	return e.truncated
 }
 
 // GetDatumMemSize calculates the memory size of each types.Datum in sortRow.byItems.
@@ -189,6 +189,11 @@ type HashAggExec struct {
 	prepared                bool
 	executed                bool
 
+	// partialConcurrency and finalConcurrency are the numbers of the partial and final workers
+	// in the parallel execution, the final one may be reduced by the estimated number of groups.
+	partialConcurrency int
+	finalConcurrency   int
+
 	memTracker  *memory.Tracker // track memory usage.
 	diskTracker *disk.Tracker
 
@@ -208,6 +213,14 @@ type HashAggExec struct {
 	inSpillMode uint32
 	// tmpChkForSpill is the temp chunk for spilling.
 	tmpChkForSpill *chunk.Chunk
+
+	// previewInterval is the number of the input chunks between two previews of the intermediate results,
+	// 0 means no preview. It's set for the top-level aggregation when tidb_enable_streaming_agg_preview is on
+	// and the statement is executed with an AggPreviewHandler.
+	previewInterval int
+	previewHandler  AggPreviewHandler
+	// numOfInputChks is the number of the input chunks since the last preview.
+	numOfInputChks int
 	// spillAction save the Action for spilling.
 	spillAction *AggSpillDiskAction
 	// isChildDrained indicates whether the all data from child has been taken out.
@@ -356,9 +369,8 @@ func closeBaseExecutor(b *baseExecutor) {
 }
 
 func (e *HashAggExec) initForParallelExec(_ sessionctx.Context) {
-	sessionVars := e.ctx.GetSessionVars()
-	finalConcurrency := sessionVars.HashAggFinalConcurrency()
-	partialConcurrency := sessionVars.HashAggPartialConcurrency()
+	finalConcurrency := e.finalConcurrency
+	partialConcurrency := e.partialConcurrency
 	e.isChildReturnEmpty = true
 	e.finalOutputCh = make(chan *AfFinalResult, finalConcurrency+partialConcurrency+1)
 	e.inputCh = make(chan *HashAggInput, partialConcurrency)
@@ -786,11 +798,57 @@ func (w *HashAggFinalWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitGro
 func (e *HashAggExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.Reset()
 	if e.isUnparallelExec {
-		return e.unparallelExec(ctx, req)
+		if err := e.unparallelExec(ctx, req); err != nil {
+			return err
+		}
+		if e.previewHandler != nil && req.NumRows() > 0 {
+			// The results returned by Next are exact, they are the final emission.
+			return e.previewHandler(req, false)
+		}
+		return nil
 	}
 	return e.parallelExec(ctx, req)
 }
 
+// AggPreviewHandler receives the results of the hash aggregation when tidb_enable_streaming_agg_preview is on.
+// The intermediate results are emitted periodically with partial being true, and the exact results are
+// emitted at the end with partial being false.
+type AggPreviewHandler func(chk *chunk.Chunk, partial bool) error
+
+// aggPreviewInterval is the number of the input chunks between two previews of the intermediate results.
+const aggPreviewInterval = 8
+
+type aggPreviewHandlerKeyType struct{}
+
+// AggPreviewHandlerKey is used to carry an AggPreviewHandler in context.Context, which is passed to the executors.
+var AggPreviewHandlerKey = aggPreviewHandlerKeyType{}
+
+// preview emits the intermediate results of all the groups aggregated so far.
+func (e *HashAggExec) preview() error {
+	chk := newFirstChunk(e)
+	for _, groupKey := range e.groupKeys {
+		partialResults := e.getPartialResults(groupKey)
+		if len(e.PartialAggFuncs) == 0 {
+			chk.SetNumVirtualRows(chk.NumRows() + 1)
+		}
+		for i, af := range e.PartialAggFuncs {
+			if err := af.AppendFinalResult2Chunk(e.ctx, partialResults[i], chk); err != nil {
+				return err
+			}
+		}
+		if chk.IsFull() {
+			if err := e.previewHandler(chk, true); err != nil {
+				return err
+			}
+			chk = newFirstChunk(e)
+		}
+	}
+	if chk.NumRows() == 0 {
+		return nil
+	}
+	return e.previewHandler(chk, true)
+}
+
 func (e *HashAggExec) fetchChildData(ctx context.Context, waitGroup *sync.WaitGroup) {
 	var (
 		input *HashAggInput
@@ -1064,6 +1122,16 @@ func (e *HashAggExec) execute(ctx context.Context) (err error) {
 
 		failpoint.Inject("ConsumeRandomPanic", nil)
 		e.memTracker.Consume(allMemDelta)
+
+		// The groups are incomplete once the data is spilled, so the previews stop.
+		if e.previewHandler != nil && e.numOfSpilledChks == 0 && atomic.LoadUint32(&e.inSpillMode) == 0 {
+			if e.numOfInputChks++; e.numOfInputChks >= e.previewInterval {
+				e.numOfInputChks = 0
+				if err := e.preview(); err != nil {
+					return err
+				}
+			}
+		}
 	}
 }
 
@@ -1131,8 +1199,8 @@ func (e *HashAggExec) getPartialResults(groupKey string) []aggfuncs.PartialResul
 func (e *HashAggExec) initRuntimeStats() {
 	if e.runtimeStats != nil {
 		stats := &HashAggRuntimeStats{
-			PartialConcurrency: e.ctx.GetSessionVars().HashAggPartialConcurrency(),
-			FinalConcurrency:   e.ctx.GetSessionVars().HashAggFinalConcurrency(),
+			PartialConcurrency: e.partialConcurrency,
+			FinalConcurrency:   e.finalConcurrency,
 		}
 		stats.PartialStats = make([]*AggWorkerStat, 0, stats.PartialConcurrency)
 		stats.FinalStats = make([]*AggWorkerStat, 0, stats.FinalConcurrency)
@@ -1262,6 +1330,15 @@ type StreamAggExec struct {
 	// All partial results will be reset after processing one group data, and the memory usage should also be reset.
 	// We can't get memory delta from ResetPartialResult, so record the memory usage here.
 	memUsageOfInitialPartialResult int64
+
+	// cacheResult indicates the result is the same every time the executor is reopened, which happens in the
+	// inner side of apply when the agg is independent of the outer row. Then the result of the first complete
+	// execution is cached if it fits in one chunk, and the later executions return it without executing the child.
+	cacheResult bool
+	// pendingCache collects the result of the current execution, it's nil until a non-empty chunk is returned.
+	pendingCache *chunk.Chunk
+	resultCache  *chunk.Chunk
+	servingCache bool
 }
 
 // Open implements the Executor Open interface.
@@ -1272,6 +1349,13 @@ func (e *StreamAggExec) Open(ctx context.Context) error {
 		}
 	})
 
+	if e.resultCache != nil {
+		e.servingCache = true
+		e.executed = false
+		return nil
+	}
+	e.pendingCache = nil
+
 	if err := e.baseExecutor.Open(ctx); err != nil {
 		return err
 	}
@@ -1307,6 +1391,11 @@ func (e *StreamAggExec) Open(ctx context.Context) error {
 
 // Close implements the Executor Close interface.
 func (e *StreamAggExec) Close() error {
+	if e.servingCache {
+		// The children are not opened.
+		e.servingCache = false
+		return nil
+	}
 	if e.childResult != nil {
 		e.memTracker.Consume(-e.childResult.MemoryUsage() - e.memUsageOfInitialPartialResult)
 		e.childResult = nil
@@ -1318,16 +1407,51 @@ func (e *StreamAggExec) Close() error {
 // Next implements the Executor Next interface.
 func (e *StreamAggExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 	req.Reset()
+	if e.servingCache {
+		if !e.executed {
+			// The rows are copied, so the parents modifying req don't affect the cache.
+			req.Append(e.resultCache, 0, e.resultCache.NumRows())
+			e.executed = true
+		}
+		return nil
+	}
 	for !e.executed && !req.IsFull() {
 		err = e.consumeOneGroup(ctx, req)
 		if err != nil {
 			e.executed = true
+			// The execution is interrupted, the result collected is incomplete.
+			e.cacheResult = false
+			e.pendingCache = nil
 			return err
 		}
 	}
+	if e.cacheResult && e.resultCache == nil {
+		e.collectResultCache(req)
+	}
 	return nil
 }
 
+// collectResultCache collects the result returned by Next into the cache. The cache is ready only after the
+// execution completes without errors, which is known by the first empty chunk returned.
+func (e *StreamAggExec) collectResultCache(req *chunk.Chunk) {
+	switch {
+	case req.NumRows() == 0:
+		if !e.executed {
+			return
+		}
+		if e.pendingCache == nil {
+			e.pendingCache = req.CopyConstruct()
+		}
+		e.resultCache, e.pendingCache = e.pendingCache, nil
+	case e.pendingCache == nil:
+		e.pendingCache = req.CopyConstruct()
+	default:
+		// The result is larger than one chunk, don't cache it.
+		e.cacheResult = false
+		e.pendingCache = nil
+	}
+}
+
 func (e *StreamAggExec) consumeOneGroup(ctx context.Context, chk *chunk.Chunk) (err error) {
 	if e.groupChecker.isExhausted() {
 		if err = e.consumeCurGroupRowsAndFetchChild(ctx, chk); err != nil {
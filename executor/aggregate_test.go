@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/pingcap/tidb/testkit/testdata"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/stretchr/testify/require"
 )
@@ -1323,6 +1324,59 @@ func TestParallelStreamAggGroupConcat(t *testing.T) {
 	}
 }
 
+func TestParallelHashAggOrderedGroupConcat(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c varchar(10))")
+	values := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d, '%d')", i%7, i, i%13))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	tk.MustExec("set tidb_init_chunk_size = 1")
+	tk.MustExec("set tidb_max_chunk_size = 32")
+
+	sqls := []string{
+		"select /*+ HASH_AGG() */ a, group_concat(b order by b desc separator ';') from t group by a",
+		"select /*+ HASH_AGG() */ a, group_concat(distinct c order by c) from t group by a",
+		"select /*+ HASH_AGG() */ a, group_concat(distinct c order by c desc), group_concat(b, c order by c, b) from t group by a",
+	}
+	check := func() {
+		expected := make([][][]interface{}, 0, len(sqls))
+		tk.MustExec("set @@tidb_hashagg_partial_concurrency = 1")
+		tk.MustExec("set @@tidb_hashagg_final_concurrency = 1")
+		for _, sql := range sqls {
+			expected = append(expected, tk.MustQuery(sql).Sort().Rows())
+		}
+		tk.MustExec("set @@tidb_hashagg_partial_concurrency = 4")
+		tk.MustExec("set @@tidb_hashagg_final_concurrency = 4")
+		for i, sql := range sqls {
+			found := false
+			for _, row := range tk.MustQuery("explain analyze " + sql).Rows() {
+				if strings.Contains(row[0].(string), "HashAgg") {
+					found = true
+					require.Regexp(t, "partial_worker:{wall_time:.*, concurrency:4,", row[5])
+				}
+			}
+			require.True(t, found)
+			tk.MustQuery(sql).Sort().Check(expected[i])
+		}
+	}
+	check()
+	tk.MustQuery("select /*+ HASH_AGG() */ group_concat(distinct c order by c desc) from t where a = 0").Check(
+		testkit.Rows("9,8,7,6,5,4,3,2,12,11,10,1,0"))
+
+	// The truncated results are the same as the unparallel ones.
+	tk.MustExec("set @@group_concat_max_len = 20")
+	check()
+	tk.MustQuery("select /*+ HASH_AGG() */ group_concat(b order by b desc separator ';') from t where a = 0").Check(
+		testkit.Rows("994;987;980;973;966;"))
+	warnings := tk.MustQuery("show warnings").Rows()
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0][2], "Some rows were cut by GROUPCONCAT")
+}
+
 func TestIssue20658(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -1667,3 +1721,144 @@ func TestIssue26885(t *testing.T) {
 	tk.MustQuery("select * from t1").Check(testkit.Rows("b", "a", "b", "c", ""))
 	tk.MustQuery("SELECT c1 + 0, COUNT(c1) FROM t1 GROUP BY c1 order by c1;").Check(testkit.Rows("0 1", "1 1", "2 2", "3 1"))
 }
+
+func TestHashAggFinalConcurrencyByGroups(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (1, 2), (2, 3), (2, 4), (1, 5), (2, 6)")
+	tk.MustExec("analyze table t")
+	tk.MustExec("set @@tidb_hashagg_partial_concurrency = 5")
+	tk.MustExec("set @@tidb_hashagg_final_concurrency = 5")
+
+	rows := tk.MustQuery("explain analyze select /*+ HASH_AGG() */ a, count(b) from t group by a").Rows()
+	found := false
+	for _, row := range rows {
+		if !strings.Contains(row[0].(string), "HashAgg") || row[3].(string) != "root" {
+			continue
+		}
+		found = true
+		execInfo := row[5].(string)
+		require.Regexp(t, "partial_worker:{wall_time:.*, concurrency:5,", execInfo)
+		require.Regexp(t, "final_worker:{wall_time:.*, concurrency:1,", execInfo)
+	}
+	require.True(t, found)
+	tk.MustQuery("select /*+ HASH_AGG() */ a, count(b) from t group by a").Sort().Check(testkit.Rows("1 3", "2 3"))
+}
+
+func TestStreamAggResultCacheInApply(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int)")
+	tk.MustExec("create table t2(b int)")
+	tk.MustExec("insert into t1 values (0), (1), (2), (3), (4)")
+	tk.MustExec("insert into t2 values (1), (2), (3)")
+	// Disable the cache of apply, so the inner side is re-executed for every outer row.
+	tk.MustExec("set @@tidb_mem_quota_apply_cache = 0")
+
+	// innerCopTasks returns the number of the cop tasks sent by the table reader on t2, one per execution.
+	innerCopTasks := func(sql string) string {
+		rows := tk.MustQuery("explain analyze " + sql).Rows()
+		for i := 0; i < len(rows); i++ {
+			if !strings.Contains(rows[i][0].(string), "TableReader") {
+				continue
+			}
+			// The cop tasks of the table reader follow it.
+			for j := i + 1; j < len(rows) && rows[j][3].(string) != "root"; j++ {
+				if rows[j][4].(string) == "table:t2" {
+					execInfo := rows[i][5].(string)
+					start := strings.Index(execInfo, "cop_task: {num: ") + len("cop_task: {num: ")
+					return execInfo[start : start+strings.Index(execInfo[start:], ",")]
+				}
+			}
+		}
+		require.FailNow(t, "table reader on t2 is not found")
+		return ""
+	}
+
+	// The derived table doesn't depend on t1, its result is cached.
+	sql := "select a, (select /*+ NO_DECORRELATE() */ count(*) from (select /*+ STREAM_AGG() */ count(*) c from t2) x where x.c > t1.a) from t1"
+	require.True(t, tk.HasPlan(sql, "Apply"))
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("0 1", "1 1", "2 1", "3 0", "4 0"))
+	require.Equal(t, "1", innerCopTasks(sql))
+
+	// The agg depends on t1, it's executed for every outer row.
+	sql = "select a, (select /*+ NO_DECORRELATE(), STREAM_AGG() */ count(*) from t2 where t2.b < t1.a) from t1"
+	require.True(t, tk.HasPlan(sql, "Apply"))
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("0 0", "1 0", "2 1", "3 2", "4 3"))
+	require.Equal(t, "5", innerCopTasks(sql))
+}
+
+func TestHashAggStreamingPreview(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t with recursive c(n) as (select 1 union all select n + 1 from c where n < 1000) select n, n % 10 from c")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	tk.MustExec("set @@tidb_hashagg_partial_concurrency = 5")
+	tk.MustExec("set @@tidb_hashagg_final_concurrency = 5")
+	tk.MustExec("set @@tidb_enable_streaming_agg_preview = 1")
+
+	var previews, finals []string
+	handler := executor.AggPreviewHandler(func(chk *chunk.Chunk, partial bool) error {
+		for i := 0; i < chk.NumRows(); i++ {
+			row := make([]string, 0, chk.NumCols())
+			for j := 0; j < chk.NumCols(); j++ {
+				row = append(row, strconv.FormatInt(chk.GetRow(i).GetInt64(j), 10))
+			}
+			if partial {
+				previews = append(previews, strings.Join(row, " "))
+			} else {
+				finals = append(finals, strings.Join(row, " "))
+			}
+		}
+		return nil
+	})
+	ctx := context.WithValue(context.Background(), executor.AggPreviewHandlerKey, handler)
+	// The limit prevents the agg from being pushed down, so the root hash agg receives all the rows.
+	sql := "select /*+ HASH_AGG() */ count(*), b from (select * from t limit 10000) x group by b"
+	result := tk.MustQueryWithContext(ctx, sql).Sort()
+	result.Check(testkit.Rows("100 0", "100 1", "100 2", "100 3", "100 4", "100 5", "100 6", "100 7", "100 8", "100 9"))
+
+	// The intermediate counts are emitted before the agg sees all the rows.
+	require.NotEmpty(t, previews)
+	cnt, err := strconv.Atoi(strings.Fields(previews[0])[0])
+	require.NoError(t, err)
+	require.Less(t, cnt, 100)
+	// The final emission is the same as the result.
+	sort.Strings(finals)
+	result.Check(testkit.Rows(finals...))
+
+	// Only the top-level agg is previewed.
+	previews, finals = nil, nil
+	nestedSQL := "select /*+ HASH_AGG() */ count(*) from (select /*+ HASH_AGG() */ b, count(*) from (select * from t limit 10000) x group by b) y"
+	tk.MustQueryWithContext(ctx, nestedSQL).Check(testkit.Rows("10"))
+	require.Equal(t, []string{"10"}, finals)
+	for _, preview := range previews {
+		// The previews of the outer agg count the groups of the inner agg.
+		require.Len(t, strings.Fields(preview), 1)
+	}
+
+	// The agg runs in parallel without a handler, even if the variable is on.
+	hasParallelHashAgg := func() bool {
+		for _, row := range tk.MustQuery("explain analyze " + sql).Rows() {
+			if strings.Contains(row[0].(string), "HashAgg") && strings.Contains(row[5].(string), "partial_worker:") {
+				return true
+			}
+		}
+		return false
+	}
+	require.True(t, hasParallelHashAgg())
+
+	// Nothing is emitted without the variable.
+	previews, finals = nil, nil
+	tk.MustExec("set @@tidb_enable_streaming_agg_preview = 0")
+	tk.MustQueryWithContext(ctx, sql).Sort().Check(result.Rows())
+	require.Empty(t, previews)
+	require.Empty(t, finals)
+}
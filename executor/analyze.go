@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -41,12 +42,16 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/dbterror/exeerrors"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tipb/go-tipb"
 	"go.uber.org/zap"
 )
 
-var _ Executor = &AnalyzeExec{}
+var (
+	_ Executor = &AnalyzeExec{}
+	_ Executor = &AnalyzeDryRunExec{}
+)
 
 // AnalyzeExec represents Analyze executor.
 type AnalyzeExec struct {
@@ -433,6 +438,83 @@ func (e *AnalyzeExec) analyzeWorker(taskCh <-chan *analyzeTask, resultsCh chan<-
 	}
 }
 
+// AnalyzeDryRunExec reports the sampling plan of the analyze tasks without collecting any statistics.
+type AnalyzeDryRunExec struct {
+	baseExecutor
+	tasks []*analyzeTask
+	done  bool
+}
+
+// Next implements the Executor Next interface.
+func (e *AnalyzeDryRunExec) Next(_ context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+	for _, task := range e.tasks {
+		req.AppendString(0, task.job.DBName)
+		req.AppendString(1, task.job.TableName)
+		req.AppendString(2, task.job.PartitionName)
+		switch task.taskType {
+		case colTask:
+			e.appendColumnsPlan(req, task.colExec)
+		case fastTask:
+			e.appendFastPlan(req, task.fastExec)
+		default:
+			req.AppendString(3, task.job.JobInfo)
+			for i := 4; i < req.NumCols(); i++ {
+				req.AppendNull(i)
+			}
+		}
+	}
+	return nil
+}
+
+func (e *AnalyzeDryRunExec) appendColumnsPlan(req *chunk.Chunk, exec *AnalyzeColumnsExec) {
+	prepareV2AnalyzeJobInfo(exec, false)
+	req.AppendString(3, exec.job.JobInfo)
+	opts := exec.opts
+	if exec.V2Options != nil {
+		opts = exec.V2Options.FilledOpts
+	}
+	sampleSize := int64(opts[ast.AnalyzeOptNumSamples])
+	if exec.StatsVersion >= statistics.Version2 && sampleSize == 0 {
+		sampleRate := *exec.analyzePB.ColReq.SampleRate
+		req.AppendFloat64(4, sampleRate)
+		sampleSize = int64(math.Ceil(sampleRate * float64(exec.baseCount)))
+	} else {
+		req.AppendNull(4)
+	}
+	req.AppendInt64(5, sampleSize)
+	req.AppendNull(6)
+	req.AppendInt64(7, sampleSize*estimateSampleRowWidth(exec.colsInfo))
+}
+
+func (e *AnalyzeDryRunExec) appendFastPlan(req *chunk.Chunk, exec *AnalyzeFastExec) {
+	req.AppendString(3, exec.job.JobInfo)
+	req.AppendNull(4)
+	sampleSize := int64(exec.opts[ast.AnalyzeOptNumSamples])
+	req.AppendInt64(5, sampleSize)
+	req.AppendInt64(6, int64(exec.estSampStep))
+	cols := exec.colsInfo
+	for _, idx := range exec.idxsInfo {
+		for _, idxCol := range idx.Columns {
+			cols = append(cols, exec.tblInfo.Columns[idxCol.Offset])
+		}
+	}
+	req.AppendInt64(7, sampleSize*estimateSampleRowWidth(cols))
+}
+
+// estimateSampleRowWidth estimates the memory held by one sampled row of the columns.
+func estimateSampleRowWidth(cols []*model.ColumnInfo) int64 {
+	width := 0
+	for _, col := range cols {
+		width += chunk.EstimateTypeWidth(&col.FieldType)
+	}
+	return int64(width)
+}
+
 type analyzeTask struct {
 	taskType           taskType
 	idxExec            *AnalyzeIndexExec
@@ -451,6 +533,11 @@ type baseAnalyzeExec struct {
 	opts        map[ast.AnalyzeOptionType]uint64
 	job         *statistics.AnalyzeJob
 	snapshot    uint64
+	// samplesMemTracker is shared by all the sampling tasks of the analyze statement, its bytes limit is the quota of
+	// the samples held by them.
+	samplesMemTracker *memory.Tracker
+	// samplesThrottle is the action of samplesMemTracker, the tasks fetch fewer results once it's triggered.
+	samplesThrottle *analyzeSamplesThrottle
 }
 
 // AddNewAnalyzeJob records the new analyze job.
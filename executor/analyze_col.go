@@ -60,6 +60,11 @@ type AnalyzeColumnsExec struct {
 	schemaForVirtualColEval *expression.Schema
 	baseCount               int64
 	baseModifyCnt           int64
+	// sampleDumper writes the sampled rows into the table set by tidb_analyze_dump_samples, nil means no dump.
+	sampleDumper *analyzeSampleDumper
+	// oldColTopNs and oldIdxTopNs hold the existing TopN of the columns and indexes when only the histograms are rebuilt.
+	oldColTopNs map[int64]*statistics.TopN
+	oldIdxTopNs map[int64]*statistics.TopN
 
 	memTracker *memory.Tracker
 }
@@ -85,7 +90,11 @@ func (e *AnalyzeColumnsExec) toV2() *AnalyzeColumnsExecV2 {
 
 func (e *AnalyzeColumnsExec) open(ranges []*ranger.Range) error {
 	e.memTracker = memory.NewTracker(int(e.ctx.GetSessionVars().PlanID.Load()), -1)
-	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	if e.samplesMemTracker != nil {
+		e.memTracker.AttachTo(e.samplesMemTracker)
+	} else {
+		e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	}
 	e.resultHandler = &tableResultHandler{}
 	firstPartRanges, secondPartRanges := distsql.SplitRangesAcrossInt64Boundary(ranges, true, false, !hasPkHist(e.handleCols))
 	firstResult, err := e.buildResp(firstPartRanges)
@@ -116,13 +125,18 @@ func (e *AnalyzeColumnsExec) buildResp(ranges []*ranger.Range) (distsql.SelectRe
 		startTS = e.snapshot
 		isoLevel = kv.SI
 	}
+	concurrency := e.concurrency
+	if e.samplesThrottle.throttled() {
+		// The samples held by the other tasks have exceeded the quota, fetch the samples one by one.
+		concurrency = 1
+	}
 	// Always set KeepOrder of the request to be true, in order to compute
 	// correct `correlation` of columns.
 	kvReq, err := reqBuilder.
 		SetAnalyzeRequest(e.analyzePB, isoLevel).
 		SetStartTS(startTS).
 		SetKeepOrder(true).
-		SetConcurrency(e.concurrency).
+		SetConcurrency(concurrency).
 		SetMemTracker(e.memTracker).
 		SetResourceGroupName(e.ctx.GetSessionVars().ResourceGroupName).
 		Build()
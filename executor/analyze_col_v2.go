@@ -18,6 +18,7 @@ import (
 	"context"
 	"math"
 	"sort"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
@@ -41,8 +43,9 @@ import (
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/dbterror/exeerrors"
 	"github.com/pingcap/tidb/util/logutil"
-	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/mathutil"
 	"github.com/pingcap/tidb/util/ranger"
+	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/timeutil"
 	"github.com/pingcap/tipb/go-tipb"
 	"go.uber.org/zap"
@@ -260,7 +263,7 @@ func (e *AnalyzeColumnsExecV2) buildSamplingStats(
 	for i := 0; i < statsConcurrency; i++ {
 		go e.subMergeWorker(mergeResultCh, mergeTaskCh, l, i)
 	}
-	if err = readDataAndSendTask(e.ctx, e.resultHandler, mergeTaskCh, e.memTracker); err != nil {
+	if err = e.readDataAndSendTask(mergeTaskCh); err != nil {
 		return 0, nil, nil, nil, nil, getAnalyzePanicErr(err)
 	}
 
@@ -409,6 +412,9 @@ func (e *AnalyzeColumnsExecV2) buildSamplingStats(
 	if err != nil {
 		return 0, nil, nil, nil, nil, err
 	}
+	if e.sampleDumper != nil {
+		e.sampleDumper.dump(e.colsInfo, rootRowCollector.Base().Samples)
+	}
 	count = rootRowCollector.Base().Count
 	if needExtStats {
 		statsHandle := domain.GetDomain(e.ctx).StatsHandle()
@@ -776,7 +782,16 @@ workLoop:
 					e.memTracker.Release(collector.MemSize)
 				}
 			}
-			hist, topn, err := statistics.BuildHistAndTopN(e.ctx, int(e.opts[ast.AnalyzeOptNumBuckets]), int(e.opts[ast.AnalyzeOptNumTopN]), task.id, collector, task.tp, task.isColumn, e.memTracker)
+			var (
+				hist *statistics.Histogram
+				topn *statistics.TopN
+				err  error
+			)
+			if oldTopN := e.oldTopN(task.id, task.isColumn); oldTopN != nil {
+				hist, topn, err = statistics.BuildHistWithTopN(e.ctx, int(e.opts[ast.AnalyzeOptNumBuckets]), task.id, collector, task.tp, task.isColumn, e.memTracker, oldTopN)
+			} else {
+				hist, topn, err = statistics.BuildHistAndTopN(e.ctx, int(e.opts[ast.AnalyzeOptNumBuckets]), int(e.opts[ast.AnalyzeOptNumTopN]), task.id, collector, task.tp, task.isColumn, e.memTracker)
+			}
 			if err != nil {
 				resultCh <- err
 				releaseCollectorMemory()
@@ -804,6 +819,14 @@ type samplingMergeResult struct {
 	err       error
 }
 
+// oldTopN returns the existing TopN of the column or index when only the histograms are rebuilt.
+func (e *AnalyzeColumnsExecV2) oldTopN(id int64, isColumn bool) *statistics.TopN {
+	if isColumn {
+		return e.oldColTopNs[id]
+	}
+	return e.oldIdxTopNs[id]
+}
+
 type samplingBuildTask struct {
 	id               int64
 	rootRowCollector statistics.RowSampleCollector
@@ -812,8 +835,10 @@ type samplingBuildTask struct {
 	slicePos         int
 }
 
-func readDataAndSendTask(ctx sessionctx.Context, handler *tableResultHandler, mergeTaskCh chan []byte, memTracker *memory.Tracker) error {
+func (e *AnalyzeColumnsExecV2) readDataAndSendTask(mergeTaskCh chan []byte) error {
 	defer close(mergeTaskCh)
+	ctx := e.ctx
+	throttled := false
 	for {
 		failpoint.Inject("mockKillRunningV2AnalyzeJob", func() {
 			dom := domain.GetDomain(ctx)
@@ -825,15 +850,142 @@ func readDataAndSendTask(ctx sessionctx.Context, handler *tableResultHandler, me
 		failpoint.Inject("mockSlowAnalyzeV2", func() {
 			time.Sleep(1000 * time.Second)
 		})
-		data, err := handler.nextRaw(context.TODO())
+		if e.samplesThrottle.throttled() {
+			if !throttled {
+				throttled = true
+				e.appendSamplesThrottledNote()
+			}
+			// Wait for the samples in flight to be merged before fetching more, so the memory is bounded by the
+			// samples kept by the tasks rather than the ones in flight.
+			for len(mergeTaskCh) > 0 {
+				if atomic.LoadUint32(&ctx.GetSessionVars().Killed) == 1 {
+					return errors.Trace(exeerrors.ErrQueryInterrupted)
+				}
+				time.Sleep(analyzeSamplesWaitInterval)
+			}
+		}
+		data, err := e.resultHandler.nextRaw(context.TODO())
 		if err != nil {
 			return errors.Trace(err)
 		}
 		if data == nil {
 			break
 		}
-		memTracker.Consume(int64(cap(data)))
+		e.memTracker.Consume(int64(cap(data)))
 		mergeTaskCh <- data
 	}
 	return nil
 }
+
+func (e *AnalyzeColumnsExecV2) appendSamplesThrottledNote() {
+	sc := e.ctx.GetSessionVars().StmtCtx
+	if e.job.PartitionName != "" {
+		sc.AppendNote(errors.Errorf(
+			"Analyze reduces the samples in flight for table %s.%s's partition %s since the samples exceed the quota",
+			e.job.DBName,
+			e.job.TableName,
+			e.job.PartitionName,
+		))
+	} else {
+		sc.AppendNote(errors.Errorf(
+			"Analyze reduces the samples in flight for table %s.%s since the samples exceed the quota",
+			e.job.DBName,
+			e.job.TableName,
+		))
+	}
+}
+
+const (
+	// analyzeSampleDumpBatchSize is the number of the sampled rows written by one statement.
+	analyzeSampleDumpBatchSize = 256
+	// maxDumpedAnalyzeSamples bounds the number of the sampled rows dumped by one analyze task.
+	maxDumpedAnalyzeSamples = 100000
+)
+
+// analyzeSampleDumper writes the sampled rows of analyze into the table set by tidb_analyze_dump_samples,
+// so the stats built from the samples can be audited and reproduced. The columns of the dump table are
+// matched with the sampled columns by name. Failing to dump the samples doesn't fail the analyze.
+type analyzeSampleDumper struct {
+	sctx      sessionctx.Context
+	dbName    string
+	tableName string
+}
+
+func (d *analyzeSampleDumper) dump(colsInfo []*model.ColumnInfo, samples statistics.WeightedRowSampleHeap) {
+	// The generated columns can't be written, they are computed from the other columns.
+	offsets := make([]int, 0, len(colsInfo))
+	for i, col := range colsInfo {
+		if !col.IsGenerated() {
+			offsets = append(offsets, i)
+		}
+	}
+	if len(offsets) == 0 || len(samples) == 0 {
+		return
+	}
+	if len(samples) > maxDumpedAnalyzeSamples {
+		samples = samples[:maxDumpedAnalyzeSamples]
+	}
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO %n.%n (")
+	for i := range offsets {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("%n")
+	}
+	sb.WriteString(") VALUES ")
+	header := sb.String()
+	rowPlaceholder := "(" + strings.Repeat("%?, ", len(offsets)-1) + "%?)"
+
+	exec := d.sctx.(sqlexec.RestrictedSQLExecutor)
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats)
+	for start := 0; start < len(samples); start += analyzeSampleDumpBatchSize {
+		batch := samples[start:mathutil.Min(start+analyzeSampleDumpBatchSize, len(samples))]
+		sb.Reset()
+		sb.WriteString(header)
+		args := make([]interface{}, 0, 2+len(offsets)*(len(batch)+1))
+		args = append(args, d.dbName, d.tableName)
+		for _, offset := range offsets {
+			args = append(args, colsInfo[offset].Name.O)
+		}
+		for i, sample := range batch {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(rowPlaceholder)
+			for _, offset := range offsets {
+				arg, err := dumpedSampleValue(sample.Columns[offset])
+				if err != nil {
+					d.handleDumpErr(err)
+					return
+				}
+				args = append(args, arg)
+			}
+		}
+		if _, _, err := exec.ExecRestrictedSQL(ctx, []sqlexec.OptionFuncAlias{sqlexec.ExecOptionUseSessionPool}, sb.String(), args...); err != nil {
+			d.handleDumpErr(err)
+			return
+		}
+	}
+}
+
+func (d *analyzeSampleDumper) handleDumpErr(err error) {
+	logutil.BgLogger().Warn("failed to dump the analyze samples", zap.String("db", d.dbName), zap.String("table", d.tableName), zap.Error(err))
+	d.sctx.GetSessionVars().StmtCtx.AppendWarning(err)
+}
+
+// dumpedSampleValue converts the sampled datum to the argument of the statement which writes it.
+func dumpedSampleValue(d types.Datum) (interface{}, error) {
+	switch d.Kind() {
+	case types.KindNull:
+		return nil, nil
+	case types.KindInt64:
+		return d.GetInt64(), nil
+	case types.KindUint64:
+		return d.GetUint64(), nil
+	case types.KindBytes:
+		return d.GetBytes(), nil
+	default:
+		return d.ToString()
+	}
+}
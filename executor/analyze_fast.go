@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/domain"
 	executor_metrics "github.com/pingcap/tidb/executor/metrics"
 	"github.com/pingcap/tidb/kv"
@@ -41,6 +42,7 @@ import (
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/dbterror/exeerrors"
 	"github.com/pingcap/tidb/util/mathutil"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/tikv/client-go/v2/tikv"
@@ -399,6 +401,9 @@ func (e *AnalyzeFastExec) handleScanTasks(bo *tikv.Backoffer) (keysSize int, err
 	setOptionForTopSQL(e.ctx.GetSessionVars().StmtCtx, snapshot)
 	snapshot.SetOption(kv.ResourceGroupName, e.ctx.GetSessionVars().ResourceGroupName)
 	for _, t := range e.scanTasks {
+		if atomic.LoadUint32(&e.ctx.GetSessionVars().Killed) == 1 {
+			return keysSize, errors.Trace(exeerrors.ErrQueryInterrupted)
+		}
 		iter, err := snapshot.Iter(kv.Key(t.StartKey), kv.Key(t.EndKey))
 		if err != nil {
 			return keysSize, err
@@ -433,6 +438,14 @@ func (e *AnalyzeFastExec) handleSampTasks(workID int, step uint32, err *error) {
 
 	rander := rand.New(rand.NewSource(e.randSeed)) // #nosec G404
 	for i := workID; i < len(e.sampTasks); i += e.concurrency {
+		// Check the kill signal between the regions, so that a long fast analyze could be interrupted promptly.
+		if atomic.LoadUint32(&e.ctx.GetSessionVars().Killed) == 1 {
+			*err = errors.Trace(exeerrors.ErrQueryInterrupted)
+			return
+		}
+		failpoint.Inject("mockSlowFastAnalyzeSampleTask", func(val failpoint.Value) {
+			time.Sleep(time.Duration(val.(int)) * time.Millisecond)
+		})
 		task := e.sampTasks[i]
 		// randomize the estimate step in range [step - 2 * sqrt(step), step]
 		if step > 4 { // 2*sqrt(x) < x
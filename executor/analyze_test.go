@@ -438,3 +438,42 @@ func TestMergeGlobalStatsWithUnAnalyzedPartition(t *testing.T) {
 	tk.MustQuery("show warnings").Check(testkit.Rows(
 		"Note 1105 Analyze use auto adjusted sample rate 1.000000 for table test.t's partition p0"))
 }
+
+func TestAnalyzeSampleMemQuota(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec("set @@tidb_analyze_partition_concurrency = 4")
+	tk.MustExec("create table t(a int, b varchar(32), index idx(a)) partition by hash(a) partitions 8")
+	for i := 0; i < 8; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, 'a'), (%d, 'b'), (%d, 'c')", i, i+8, i+16))
+	}
+
+	countThrottledNotes := func() int {
+		cnt := 0
+		for _, row := range tk.MustQuery("show warnings").Rows() {
+			if strings.Contains(row[2].(string), "since the samples exceed the quota") {
+				cnt++
+			}
+		}
+		return cnt
+	}
+
+	tk.MustQuery("select @@tidb_analyze_sample_mem_quota").Check(testkit.Rows("-1"))
+	tk.MustExec("analyze table t")
+	require.Equal(t, 0, countThrottledNotes())
+	tk.MustExec("set @@tidb_analyze_sample_mem_quota = 1073741824")
+	tk.MustExec("analyze table t")
+	require.Equal(t, 0, countThrottledNotes())
+
+	// The analyze still succeeds when the samples exceed the quota, but the tasks are throttled.
+	tk.MustExec("set @@tidb_analyze_sample_mem_quota = 1")
+	tk.MustExec("analyze table t")
+	require.Equal(t, 8, countThrottledNotes())
+	for i := 0; i < 8; i++ {
+		tk.MustQuery(fmt.Sprintf("show stats_meta where table_name = 't' and partition_name = 'p%d'", i)).CheckAt([]int{5}, testkit.Rows("3"))
+	}
+	tk.MustQuery("show stats_meta where table_name = 't' and partition_name = 'global'").CheckAt([]int{5}, testkit.Rows("24"))
+}
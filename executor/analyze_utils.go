@@ -19,6 +19,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/config"
@@ -39,6 +40,32 @@ func getBuildStatsConcurrency(ctx sessionctx.Context) (int, error) {
 	return int(c), err
 }
 
+// analyzeSamplesWaitInterval is the interval to check whether the samples in flight have been merged, when the
+// samples of an analyze statement exceed the quota.
+const analyzeSamplesWaitInterval = 10 * time.Millisecond
+
+// analyzeSamplesThrottle is the action of the samples tracker of an analyze statement. Once the samples held by the
+// tasks exceed the quota, the tasks stop fetching the coprocessor results until their samples in flight are merged.
+type analyzeSamplesThrottle struct {
+	memory.BaseOOMAction
+	exceeded atomic.Bool
+}
+
+// Action implements the memory.ActionOnExceed interface.
+func (a *analyzeSamplesThrottle) Action(*memory.Tracker) {
+	a.exceeded.Store(true)
+}
+
+// GetPriority implements the memory.ActionOnExceed interface.
+func (*analyzeSamplesThrottle) GetPriority() int64 {
+	return memory.DefRateLimitPriority
+}
+
+// throttled returns whether the samples have exceeded the quota.
+func (a *analyzeSamplesThrottle) throttled() bool {
+	return a != nil && a.exceeded.Load()
+}
+
 var errAnalyzeWorkerPanic = errors.New("analyze worker panic")
 var errAnalyzeOOM = errors.Errorf("analyze panic due to memory quota exceeds, please try with smaller samplerate(refer to %d/count)", config.DefRowsForSampleRate)
 
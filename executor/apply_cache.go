@@ -15,7 +15,6 @@
 package executor
 
 import (
-	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/mathutil"
@@ -42,13 +41,15 @@ func applyCacheKVMem(key applyCacheKey, value *chunk.List) int64 {
 	return int64(len(key)) + value.GetMemTracker().BytesConsumed()
 }
 
-func newApplyCache(ctx sessionctx.Context) (*applyCache, error) {
+// newApplyCache creates an apply cache which evicts the least recently used entries
+// when the memory usage exceeds memCapacity.
+func newApplyCache(memCapacity int64) (*applyCache, error) {
 	// since applyCache controls the memory usage by itself, set the capacity of
 	// the underlying LRUCache to max to close its memory control
 	cache := kvcache.NewSimpleLRUCache(mathutil.MaxUint, 0.1, 0)
 	c := applyCache{
 		cache:       cache,
-		memCapacity: ctx.GetSessionVars().MemQuotaApplyCache,
+		memCapacity: memCapacity,
 		memTracker:  memory.NewTracker(memory.LabelForApplyCache, -1),
 	}
 	return &c, nil
@@ -60,12 +61,6 @@ func (c *applyCache) get(key applyCacheKey) (value kvcache.Value, ok bool) {
 	return c.cache.Get(key)
 }
 
-func (c *applyCache) put(key applyCacheKey, val kvcache.Value) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	c.cache.Put(key, val)
-}
-
 // Get gets a cache item according to cache key. It's thread-safe.
 func (c *applyCache) Get(key applyCacheKey) (*chunk.List, error) {
 	value, hit := c.get(key)
@@ -82,6 +77,13 @@ func (c *applyCache) Set(key applyCacheKey, value *chunk.List) (bool, error) {
 	if mem > c.memCapacity { // ignore this kv pair if its size is too large
 		return false, nil
 	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if oldValue, ok := c.cache.Get(key); ok {
+		// The memory of the replaced entry is released, or it's counted twice.
+		c.cache.Delete(key)
+		c.memTracker.Consume(-applyCacheKVMem(key, oldValue.(*chunk.List)))
+	}
 	for mem+c.memTracker.BytesConsumed() > c.memCapacity {
 		evictedKey, evictedValue, evicted := c.cache.RemoveOldest()
 		if !evicted {
@@ -90,7 +92,7 @@ func (c *applyCache) Set(key applyCacheKey, value *chunk.List) (bool, error) {
 		c.memTracker.Consume(-applyCacheKVMem(evictedKey.(applyCacheKey), evictedValue.(*chunk.List)))
 	}
 	c.memTracker.Consume(mem)
-	c.put(key, value)
+	c.cache.Put(key, value)
 	return true, nil
 }
 
@@ -22,14 +22,11 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
-	"github.com/pingcap/tidb/util/mock"
 	"github.com/stretchr/testify/require"
 )
 
 func TestApplyCache(t *testing.T) {
-	ctx := mock.NewContext()
-	ctx.GetSessionVars().MemQuotaApplyCache = 100
-	applyCache, err := newApplyCache(ctx)
+	applyCache, err := newApplyCache(100)
 	require.NoError(t, err)
 
 	fields := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong)}
@@ -77,3 +74,44 @@ func TestApplyCache(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, result)
 }
+
+func TestApplyCacheLRU(t *testing.T) {
+	applyCache, err := newApplyCache(200)
+	require.NoError(t, err)
+
+	fields := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong)}
+	value := make([]*chunk.List, 3)
+	key := make([][]byte, 3)
+	for i := 0; i < 3; i++ {
+		value[i] = chunk.NewList(fields, 1, 1)
+		srcChunk := chunk.NewChunkWithCapacity(fields, 1)
+		srcChunk.AppendInt64(0, int64(i))
+		value[i].AppendRow(srcChunk.GetRow(0))
+		key[i] = []byte(strings.Repeat(strconv.Itoa(i), 100))
+	}
+
+	for i := 0; i < 2; i++ {
+		ok, err := applyCache.Set(key[i], value[i])
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+	// Setting an existing key again doesn't count its memory twice.
+	ok, err := applyCache.Set(key[0], value[0])
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(200), applyCache.memTracker.BytesConsumed())
+
+	// key[0] is the most recently used one, so key[1] is evicted.
+	ok, err = applyCache.Set(key[2], value[2])
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(200), applyCache.memTracker.BytesConsumed())
+	result, err := applyCache.Get(key[1])
+	require.NoError(t, err)
+	require.Nil(t, result)
+	for _, i := range []int{0, 2} {
+		result, err = applyCache.Get(key[i])
+		require.NoError(t, err)
+		require.NotNil(t, result)
+	}
+}
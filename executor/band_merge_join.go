@@ -0,0 +1,370 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"sort"
+	"unsafe"
+
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/expression"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/memory"
+)
+
+// BandMergeJoinExec implements the band merge join algorithm for the joins whose
+// join conditions are all ranges between the columns of the two tables, like
+// `a.t BETWEEN b.lo AND b.hi`, which have no equal condition for the hash join.
+// The outer rows are sorted by the point column and the inner rows are sorted by
+// the lower bound column, the sorts are skipped if the rows are already in order.
+// The outer rows are then swept in order with a window of the inner rows, the inner
+// rows whose lower bound is not greater than the point are added into the window,
+// and the ones whose upper bound is less than the point are removed from it.
+// The rows in the window are only candidates, the joiner still evaluates all the
+// join conditions on them, so the open and closed bounds are handled exactly.
+// The rows of both children can be spilled to disk, only their sort keys are kept
+// in memory.
+type BandMergeJoinExec struct {
+	baseExecutor
+
+	joiner      joiner
+	band        *joinBand
+	outerIdx    int
+	outerFilter expression.CNFExprs
+
+	prepared  bool
+	outerSide *bandJoinSide
+	innerSide *bandJoinSide
+	// missedOuterRows are the outer rows which can't match any inner row,
+	// they are filtered by the outerFilter or have null points.
+	missedOuterRows []chunk.RowPtr
+	missedCursor    int
+	outerCursor     int
+	innerCursor     int
+
+	outerRow    chunk.Row
+	window      []chunk.Row
+	windowKeys  []chunk.Row
+	windowIter  *chunk.Iterator4Slice
+	windowReady bool
+	hasMatch    bool
+	hasNull     bool
+
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
+}
+
+// joinBand is the range join condition `lower <= point <= upper`, the point column
+// belongs to the outer table and the bound columns belong to the inner table.
+type joinBand struct {
+	// point, lower and upper are indexed by the offsets in the rows of their own tables.
+	point *expression.Column
+	lower *expression.Column
+	upper *expression.Column
+	// pointKey, lowerKey and upperKey are indexed by the offsets in the sort keys,
+	// which are [point] for the outer rows and [lower, upper] for the inner rows.
+	pointKey *expression.Column
+	lowerKey *expression.Column
+	upperKey *expression.Column
+
+	cmpPoint      expression.CompareFunc
+	cmpLower      expression.CompareFunc
+	cmpPointLower expression.CompareFunc
+	cmpPointUpper expression.CompareFunc
+}
+
+func newJoinBand(ctx sessionctx.Context, band *plannercore.JoinBand, outerSchema, innerSchema *expression.Schema) *joinBand {
+	b := &joinBand{
+		point:    resolveBandColumn(band.Point, outerSchema.ColumnIndex(band.Point)),
+		lower:    resolveBandColumn(band.Lower, innerSchema.ColumnIndex(band.Lower)),
+		upper:    resolveBandColumn(band.Upper, innerSchema.ColumnIndex(band.Upper)),
+		pointKey: resolveBandColumn(band.Point, 0),
+		lowerKey: resolveBandColumn(band.Lower, 0),
+		upperKey: resolveBandColumn(band.Upper, 1),
+	}
+	b.cmpPoint = expression.GetCmpFunction(ctx, b.point, b.point)
+	b.cmpLower = expression.GetCmpFunction(ctx, b.lower, b.lower)
+	b.cmpPointLower = expression.GetCmpFunction(ctx, b.point, b.lower)
+	b.cmpPointUpper = expression.GetCmpFunction(ctx, b.point, b.upper)
+	return b
+}
+
+func resolveBandColumn(col *expression.Column, idx int) *expression.Column {
+	newCol := col.Clone().(*expression.Column)
+	newCol.Index = idx
+	return newCol
+}
+
+// bandJoinSide holds the rows of a child of the band merge join. The rows are kept in
+// a row container which is spilled to disk when the memory quota is exceeded, and the
+// sort keys of the rows taken into the sweep are kept in memory.
+type bandJoinSide struct {
+	rowContainer *chunk.RowContainer
+	// keys[i] is the sort key of the row ptrs[i], order is the sorted offsets of them.
+	keys  *chunk.Chunk
+	ptrs  []chunk.RowPtr
+	order []int
+}
+
+func (s *bandJoinSide) numRows() int {
+	return len(s.order)
+}
+
+func (s *bandJoinSide) keyRow(i int) chunk.Row {
+	return s.keys.GetRow(s.order[i])
+}
+
+func (s *bandJoinSide) row(i int) (chunk.Row, error) {
+	return s.rowContainer.GetRow(s.ptrs[s.order[i]])
+}
+
+// sort sorts the rows by the key column, the sort is skipped if the rows are already in order.
+func (s *bandJoinSide) sort(ctx sessionctx.Context, col *expression.Column, cmpFunc expression.CompareFunc) error {
+	s.order = make([]int, len(s.ptrs))
+	for i := range s.order {
+		s.order[i] = i
+	}
+	var err error
+	less := func(i, j int) bool {
+		if err != nil {
+			return false
+		}
+		var cmp int64
+		cmp, _, err = cmpFunc(ctx, col, col, s.keyRow(i), s.keyRow(j))
+		return cmp < 0
+	}
+	if !sort.SliceIsSorted(s.order, less) && err == nil {
+		sort.SliceStable(s.order, less)
+	}
+	return err
+}
+
+// Open implements the Executor Open interface.
+func (e *BandMergeJoinExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.diskTracker = disk.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+	e.windowIter = &chunk.Iterator4Slice{}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *BandMergeJoinExec) Close() error {
+	for _, side := range []*bandJoinSide{e.outerSide, e.innerSide} {
+		if side == nil {
+			continue
+		}
+		failpoint.Inject("testBandMergeJoinRowContainerSpill", func(val failpoint.Value) {
+			if val.(bool) {
+				side.rowContainer.ActionSpill().WaitForTest()
+			}
+		})
+		if err := side.rowContainer.Close(); err != nil {
+			return err
+		}
+	}
+	e.prepared = false
+	e.outerSide, e.innerSide = nil, nil
+	e.missedOuterRows, e.window, e.windowKeys = nil, nil, nil
+	e.missedCursor, e.outerCursor, e.innerCursor = 0, 0, 0
+	e.outerRow = chunk.Row{}
+	e.windowReady, e.hasMatch, e.hasNull = false, false, false
+	e.memTracker, e.diskTracker = nil, nil
+	return e.baseExecutor.Close()
+}
+
+// Next implements the Executor Next interface.
+func (e *BandMergeJoinExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if !e.prepared {
+		if err := e.prepare(ctx); err != nil {
+			return err
+		}
+		e.prepared = true
+	}
+	for ; e.missedCursor < len(e.missedOuterRows) && !req.IsFull(); e.missedCursor++ {
+		row, err := e.outerSide.rowContainer.GetRow(e.missedOuterRows[e.missedCursor])
+		if err != nil {
+			return err
+		}
+		e.joiner.onMissMatch(false, row, req)
+	}
+	for !req.IsFull() && e.outerCursor < e.outerSide.numRows() {
+		if !e.windowReady {
+			var err error
+			if e.outerRow, err = e.outerSide.row(e.outerCursor); err != nil {
+				return err
+			}
+			if err = e.slideWindow(e.outerSide.keyRow(e.outerCursor)); err != nil {
+				return err
+			}
+			e.windowIter.Reset(e.window)
+			e.windowIter.Begin()
+			e.windowReady = true
+		}
+		for e.windowIter.Current() != e.windowIter.End() {
+			matched, isNull, err := e.joiner.tryToMatchInners(e.outerRow, e.windowIter, req)
+			if err != nil {
+				return err
+			}
+			e.hasMatch = e.hasMatch || matched
+			e.hasNull = e.hasNull || isNull
+			if req.IsFull() && e.windowIter.Current() != e.windowIter.End() {
+				return nil
+			}
+		}
+		if !e.hasMatch {
+			e.joiner.onMissMatch(e.hasNull, e.outerRow, req)
+		}
+		e.hasMatch, e.hasNull, e.windowReady = false, false, false
+		e.outerCursor++
+	}
+	return nil
+}
+
+// slideWindow moves the window of the inner rows to the point of the outer row.
+// The outer rows are visited in the order of the points, so an inner row removed
+// from the window because of its upper bound can't match the following outer rows.
+func (e *BandMergeJoinExec) slideWindow(outerKey chunk.Row) error {
+	band := e.band
+	for ; e.innerCursor < e.innerSide.numRows(); e.innerCursor++ {
+		innerKey := e.innerSide.keyRow(e.innerCursor)
+		cmp, _, err := band.cmpPointLower(e.ctx, band.pointKey, band.lowerKey, outerKey, innerKey)
+		if err != nil {
+			return err
+		}
+		if cmp < 0 {
+			break
+		}
+		innerRow, err := e.innerSide.row(e.innerCursor)
+		if err != nil {
+			return err
+		}
+		e.window = append(e.window, innerRow)
+		e.windowKeys = append(e.windowKeys, innerKey)
+	}
+	window, windowKeys := e.window[:0], e.windowKeys[:0]
+	for i, innerKey := range e.windowKeys {
+		cmp, _, err := band.cmpPointUpper(e.ctx, band.pointKey, band.upperKey, outerKey, innerKey)
+		if err != nil {
+			return err
+		}
+		if cmp <= 0 {
+			window = append(window, e.window[i])
+			windowKeys = append(windowKeys, innerKey)
+		}
+	}
+	e.window, e.windowKeys = window, windowKeys
+	return nil
+}
+
+// prepare reads all the rows of both children and sorts them for the sweep.
+// The rows with null points or bounds never satisfy the join conditions, and the
+// supported join types don't distinguish the null results from false, so they
+// are not taken into the sweep.
+func (e *BandMergeJoinExec) prepare(ctx context.Context) (err error) {
+	band := e.band
+	if e.innerSide, _, err = e.fetchSide(ctx, 1-e.outerIdx, []int{band.lower.Index, band.upper.Index}, nil); err != nil {
+		return err
+	}
+	if e.outerSide, e.missedOuterRows, err = e.fetchSide(ctx, e.outerIdx, []int{band.point.Index}, e.outerFilter); err != nil {
+		return err
+	}
+	if err = e.innerSide.sort(e.ctx, band.lowerKey, band.cmpLower); err != nil {
+		return err
+	}
+	if err = e.outerSide.sort(e.ctx, band.pointKey, band.cmpPoint); err != nil {
+		return err
+	}
+	e.memTracker.Consume(int64(len(e.innerSide.order)+len(e.outerSide.order)) * int64(unsafe.Sizeof(0)))
+	return nil
+}
+
+// fetchSide reads all the rows of the child into a bandJoinSide. The rows which are
+// filtered by the filter or have null keys are returned as the missed rows.
+func (e *BandMergeJoinExec) fetchSide(ctx context.Context, childIdx int, keyIdxs []int,
+	filter expression.CNFExprs) (side *bandJoinSide, missed []chunk.RowPtr, err error) {
+	child := e.children[childIdx]
+	keyTypes := make([]*types.FieldType, 0, len(keyIdxs))
+	for _, idx := range keyIdxs {
+		keyTypes = append(keyTypes, retTypes(child)[idx])
+	}
+	side = &bandJoinSide{
+		rowContainer: chunk.NewRowContainer(retTypes(child), e.maxChunkSize),
+		keys:         chunk.NewChunkWithCapacity(keyTypes, e.initCap),
+	}
+	side.rowContainer.GetMemTracker().AttachTo(e.memTracker)
+	side.rowContainer.GetDiskTracker().AttachTo(e.diskTracker)
+	if variable.EnableTmpStorageOnOOM.Load() {
+		actionSpill := side.rowContainer.ActionSpill()
+		failpoint.Inject("testBandMergeJoinRowContainerSpill", func(val failpoint.Value) {
+			if val.(bool) {
+				actionSpill = side.rowContainer.ActionSpillForTest()
+			}
+		})
+		e.ctx.GetSessionVars().MemTracker.FallbackOldAndSetNewAction(actionSpill)
+	}
+
+	var selected []bool
+	for {
+		chk := newFirstChunk(child)
+		if err = Next(ctx, child, chk); err != nil {
+			return side, missed, err
+		}
+		if chk.NumRows() == 0 {
+			return side, missed, nil
+		}
+		selected, err = expression.VectorizedFilter(e.ctx, filter, chunk.NewIterator4Chunk(chk), selected)
+		if err != nil {
+			return side, missed, err
+		}
+		keysMemUsage := side.keys.MemoryUsage()
+		chkIdx := uint32(side.rowContainer.NumChunks())
+		numPtrs := len(side.ptrs)
+		for j := 0; j < chk.NumRows(); j++ {
+			row := chk.GetRow(j)
+			ptr := chunk.RowPtr{ChkIdx: chkIdx, RowIdx: uint32(j)}
+			if !selected[j] || hasNullInKeys(row, keyIdxs) {
+				missed = append(missed, ptr)
+				continue
+			}
+			side.keys.AppendRowByColIdxs(row, keyIdxs)
+			side.ptrs = append(side.ptrs, ptr)
+		}
+		e.memTracker.Consume(side.keys.MemoryUsage() - keysMemUsage + int64(len(side.ptrs)-numPtrs)*rowPtrSize)
+		if err = side.rowContainer.Add(chk); err != nil {
+			return side, missed, err
+		}
+	}
+}
+
+func hasNullInKeys(row chunk.Row, keyIdxs []int) bool {
+	for _, idx := range keyIdxs {
+		if row.IsNull(idx) {
+			return true
+		}
+	}
+	return false
+}
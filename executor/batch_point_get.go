@@ -43,8 +43,10 @@ import (
 type BatchPointGetExec struct {
 	baseExecutor
 
-	tblInfo     *model.TableInfo
-	idxInfo     *model.IndexInfo
+	tblInfo *model.TableInfo
+	idxInfo *model.IndexInfo
+	// idxPrefix indicates the idxVals are the values of a prefix of the unique index columns.
+	idxPrefix   bool
 	handles     []kv.Handle
 	physIDs     []int64
 	partExpr    *tables.PartitionExpr
@@ -60,9 +62,11 @@ type BatchPointGetExec struct {
 	values      [][]byte
 	index       int
 	rowDecoder  *rowcodec.ChunkDecoder
-	keepOrder   bool
-	desc        bool
-	batchGetter kv.BatchGetter
+	// verifyChecksum indicates whether to verify the row level checksums of the rows read.
+	verifyChecksum bool
+	keepOrder      bool
+	desc           bool
+	batchGetter    kv.BatchGetter
 
 	columns []*model.ColumnInfo
 	// virtualColumnIndex records all the indices of virtual columns and sort them in definition
@@ -185,6 +189,11 @@ func (e *BatchPointGetExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	}
 	for !req.IsFull() && e.index < len(e.values) {
 		handle, val := e.handles[e.index], e.values[e.index]
+		if e.verifyChecksum {
+			if err := verifyRowChecksum(e.ctx, e.tblInfo, handle, val); err != nil {
+				return err
+			}
+		}
 		err := DecodeRowValToChunk(e.base().ctx, e.schema, e.tblInfo, handle, val, req, e.rowDecoder)
 		if err != nil {
 			return err
@@ -214,7 +223,12 @@ func (e *BatchPointGetExec) initialize(ctx context.Context) error {
 	var err error
 	batchGetter := e.batchGetter
 	rc := e.ctx.GetSessionVars().IsPessimisticReadConsistency()
-	if e.idxInfo != nil && !isCommonHandleRead(e.tblInfo, e.idxInfo) {
+	if e.idxPrefix {
+		e.handles, indexKeys, err = e.scanIndexPrefixes(ctx)
+		if err != nil {
+			return err
+		}
+	} else if e.idxInfo != nil && !isCommonHandleRead(e.tblInfo, e.idxInfo) {
 		// `SELECT a, b FROM t WHERE (a, b) IN ((1, 2), (1, 2), (2, 1), (1, 2))` should not return duplicated rows
 		dedup := make(map[hack.MutableString]struct{})
 		toFetchIndexKeys := make([]kv.Key, 0, len(e.idxVals))
@@ -457,6 +471,87 @@ func (e *BatchPointGetExec) initialize(ctx context.Context) error {
 	return nil
 }
 
+// scanIndexPrefixes scans the unique index keys prefixed by the idxVals, and returns the handles and the index keys
+// read. The prefix columns cover a unique key, so a prefix matches at most one row, and the rows of different
+// prefixes never overlap because the duplicated prefixes are removed.
+func (e *BatchPointGetExec) scanIndexPrefixes(ctx context.Context) ([]kv.Handle, []kv.Key, error) {
+	dedup := make(map[hack.MutableString]struct{})
+	prefixes := make([]kv.Key, 0, len(e.idxVals))
+	for _, idxVals := range e.idxVals {
+		// For all x, 'x IN (null)' evaluate to null, so the query get no result.
+		if datumsContainNull(idxVals) {
+			continue
+		}
+		prefix, err := EncodeUniqueIndexKey(e.ctx, e.tblInfo, e.idxInfo, idxVals, e.tblInfo.ID)
+		if err != nil {
+			if kv.ErrNotExist.Equal(err) {
+				continue
+			}
+			return nil, nil, err
+		}
+		s := hack.String(prefix)
+		if _, found := dedup[s]; found {
+			continue
+		}
+		dedup[s] = struct{}{}
+		prefixes = append(prefixes, prefix)
+	}
+
+	var (
+		handles   []kv.Handle
+		indexKeys []kv.Key
+	)
+	for _, prefix := range prefixes {
+		iter, err := e.newIndexPrefixIter(prefix)
+		if err != nil {
+			return nil, nil, err
+		}
+		for iter.Valid() && ctx.Err() == nil {
+			key, val := iter.Key().Clone(), slices.Clone(iter.Value())
+			handle, err1 := tablecodec.DecodeIndexHandle(key, val, len(e.idxInfo.Columns))
+			if err1 != nil {
+				iter.Close()
+				return nil, nil, err1
+			}
+			handles = append(handles, handle)
+			indexKeys = append(indexKeys, key)
+			if err = iter.Next(); err != nil {
+				iter.Close()
+				return nil, nil, err
+			}
+		}
+		iter.Close()
+		if err = ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return handles, indexKeys, nil
+}
+
+// newIndexPrefixIter returns the iterator of the index keys prefixed by prefix, the keys written in the
+// transaction are merged into the keys in the snapshot.
+func (e *BatchPointGetExec) newIndexPrefixIter(prefix kv.Key) (kv.Iterator, error) {
+	snapIter, err := e.snapshot.Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		return nil, err
+	}
+	if !e.txn.Valid() {
+		return snapIter, nil
+	}
+	dirtyIter, err := e.txn.GetMemBuffer().Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		snapIter.Close()
+		return nil, err
+	}
+	iter, err := driver.NewUnionIter(dirtyIter, snapIter, false)
+	if err != nil {
+		snapIter.Close()
+		dirtyIter.Close()
+		return nil, err
+	}
+	return iter, nil
+}
+
 // LockKeys locks the keys for pessimistic transaction.
 func LockKeys(ctx context.Context, sctx sessionctx.Context, lockWaitTime int64, keys ...kv.Key) error {
 	txnCtx := sctx.GetSessionVars().TxnCtx
@@ -22,10 +22,12 @@ import (
 	"time"
 
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/util/dbterror/exeerrors"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/client-go/v2/tikv"
 )
@@ -77,6 +79,59 @@ func TestBatchPointGetExec(t *testing.T) {
 	))
 }
 
+func TestBatchPointGetUniquePrefix(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	// The prefix (a, b) of the composite unique index uabc is unique because it covers the unique index ua.
+	tk.MustExec("create table t(a int, b int, c int, d int, unique key ua(a), unique key uabc(a, b, c))")
+	tk.MustExec("insert into t values (1, 1, 1, 1), (2, 2, 2, 2), (3, 3, null, 3), (null, 4, 4, 4), (null, 4, 5, 5)")
+
+	sql := "select * from t where (a, b) in ((1, 1), (3, 3), (5, 5))"
+	tk.MustQuery("explain format='brief' " + sql).Check(testkit.Rows("Batch_Point_Get 3.00 root table:t, index:uabc(a, b, c) keep order:false, desc:false, index prefix"))
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("1 1 1 1", "3 3 <nil> 3"))
+	// The duplicated prefixes return the rows once.
+	tk.MustQuery("select * from t where (a, b) in ((2, 2), (1, 1), (2, 2), (1, 1))").Sort().Check(testkit.Rows("1 1 1 1", "2 2 2 2"))
+	tk.MustQuery("select d from t where (a, b) in ((1, 2), (2, 1))").Check(testkit.Rows())
+	tk.MustQuery("select d from t where (a, b) in ((null, 4), (2, 2))").Check(testkit.Rows("2"))
+	tk.MustExec("prepare stmt from 'select * from t where (a, b) in ((?, ?), (?, ?))'")
+	tk.MustExec("set @a = 1, @b = 1, @c = 3, @d = 3")
+	tk.MustQuery("execute stmt using @a, @b, @c, @d").Sort().Check(testkit.Rows("1 1 1 1", "3 3 <nil> 3"))
+	tk.MustExec("set @a = 2, @b = 2, @c = 3, @d = 4")
+	tk.MustQuery("execute stmt using @a, @b, @c, @d").Sort().Check(testkit.Rows("2 2 2 2"))
+
+	// The rows written in the transaction are read.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (6, 6, 6, 6)")
+	tk.MustExec("delete from t where a = 1")
+	tk.MustExec("update t set d = 30 where a = 3")
+	tk.MustQuery("select * from t where (a, b) in ((1, 1), (3, 3), (6, 6))").Sort().Check(testkit.Rows("3 3 <nil> 30", "6 6 6 6"))
+	tk.MustExec("rollback")
+	tk.MustQuery("select * from t where (a, b) in ((1, 1), (3, 3), (6, 6))").Sort().Check(testkit.Rows("1 1 1 1", "3 3 <nil> 3"))
+
+	// The ranges can't be locked, so the reads with lock don't use the prefix.
+	tk.MustQuery("explain format='brief' select * from t where (a, b) in ((1, 1), (3, 3)) for update").CheckNotContain("index prefix")
+	tk.MustQuery("select * from t where (a, b) in ((1, 1), (3, 3)) for update").Sort().Check(testkit.Rows("1 1 1 1", "3 3 <nil> 3"))
+
+	// The prefix which isn't unique is a range scan planned by the optimizer.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int, unique key uab(a, b))")
+	tk.MustExec("insert into t values (1, 1, 1), (5, 5, 5), (5, 6, 6)")
+	sql = "select * from t where a in (1, 5)"
+	tk.MustQuery("explain format='brief' " + sql).CheckNotContain("Batch_Point_Get")
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("1 1 1", "5 5 5", "5 6 6"))
+	tk.MustQuery("explain format='brief' select * from t where (a, b) in ((1, 1), (5, 6))").Check(testkit.Rows("Batch_Point_Get 2.00 root table:t, index:uab(a, b) keep order:false, desc:false"))
+
+	// The prefix covering the clustered primary key is unique, and the handles are decoded from the index keys.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id varchar(10) primary key clustered, a varchar(10) collate utf8mb4_general_ci, b int, unique key uiab(id, a, b))")
+	tk.MustExec("insert into t values ('x1', 'a', 1), ('x2', 'B', 2), ('x3', 'b', null)")
+	sql = "select * from t where (id, a) in (('x1', 'A'), ('x3', 'B'), ('x2', 'c'))"
+	tk.MustQuery("explain format='brief' " + sql).Check(testkit.Rows("Batch_Point_Get 3.00 root table:t, index:uiab(id, a, b) keep order:false, desc:false, index prefix"))
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("x1 a 1", "x3 b <nil>"))
+}
+
 func TestBatchPointGetInTxn(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 
@@ -119,6 +174,30 @@ func TestBatchPointGetCache(t *testing.T) {
 	tk.MustQuery("SELECT id, token FROM test.customers WHERE id IN (28, 29);").Check(testkit.Rows("28 07j", "29 03j"))
 }
 
+func TestBatchPointGetMaxSize(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v int, unique key(v))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4)")
+	tk.MustExec("set @@tidb_batch_point_get_max_size = 3")
+
+	// The duplicated handles are not counted.
+	tk.MustQuery("select id from t where id in (1, 2, 3, 1, 2, 3)").Sort().Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select id from t where v in (1, 2, 3)").Sort().Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select id from t where v in (1, 2, 3, 3, 2, 1)").Sort().Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select id from t where v in (1, 2, 3, null)").Sort().Check(testkit.Rows("1", "2", "3"))
+
+	err := tk.ExecToErr("select id from t where id in (1, 2, 3, 4)")
+	require.True(t, exeerrors.ErrBatchPointGetTooLarge.Equal(err))
+	require.ErrorContains(t, err, "The number of keys 4 of batch point get exceeds tidb_batch_point_get_max_size 3, consider joining with a temporary table of the values instead")
+	tk.MustGetErrCode("select id from t where v in (1, 2, 3, 4)", errno.ErrBatchPointGetTooLarge)
+
+	tk.MustExec("set @@tidb_batch_point_get_max_size = default")
+	tk.MustQuery("select id from t where id in (1, 2, 3, 4)").Sort().Check(testkit.Rows("1", "2", "3", "4"))
+}
+
 func TestIssue18843(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 
@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/planner/core"
@@ -852,6 +853,7 @@ type hashJoinTestCase struct {
 	useOuterToBuild    bool
 	rawData            string
 	childrenUsedSchema [][]bool
+	radixBits          uint
 }
 
 func (tc hashJoinTestCase) columns() []*expression.Column {
@@ -864,8 +866,8 @@ func (tc hashJoinTestCase) columns() []*expression.Column {
 }
 
 func (tc hashJoinTestCase) String() string {
-	return fmt.Sprintf("(rows:%v, cols:%v, concurency:%v, joinKeyIdx: %v, disk:%v)",
-		tc.rows, tc.cols, tc.concurrency, tc.keyIdx, tc.disk)
+	return fmt.Sprintf("(rows:%v, cols:%v, concurency:%v, joinKeyIdx: %v, disk:%v, radixBits:%v)",
+		tc.rows, tc.cols, tc.concurrency, tc.keyIdx, tc.disk, tc.radixBits)
 }
 
 func defaultHashJoinTestCase(cols []*types.FieldType, joinType core.JoinType, useOuterToBuild bool) *hashJoinTestCase {
@@ -920,6 +922,7 @@ func prepare4HashJoin(testCase *hashJoinTestCase, innerExec, outerExec Executor)
 			concurrency:     uint(testCase.concurrency),
 			probeTypes:      retTypes(outerExec),
 			buildTypes:      retTypes(innerExec),
+			radixBits:       testCase.radixBits,
 		},
 		probeSideTupleFetcher: &probeSideTupleFetcher{
 			probeSideExec: outerExec,
@@ -1107,6 +1110,28 @@ func BenchmarkHashJoinExec(b *testing.B) {
 	})
 }
 
+func BenchmarkHashJoinRadix(b *testing.B) {
+	lvl := log.GetLevel()
+	log.SetLevel(zapcore.ErrorLevel)
+	defer log.SetLevel(lvl)
+
+	cols := []*types.FieldType{
+		types.NewFieldType(mysql.TypeLonglong),
+		types.NewFieldType(mysql.TypeDouble),
+	}
+
+	b.ReportAllocs()
+	for _, radixBits := range []uint{0, 4, 8} {
+		cas := defaultHashJoinTestCase(cols, 0, false)
+		cas.rows = 1000000
+		cas.keyIdx = []int{0}
+		cas.radixBits = radixBits
+		b.Run(fmt.Sprintf("%v", cas), func(b *testing.B) {
+			benchmarkHashJoinExecWithCase(b, cas)
+		})
+	}
+}
+
 func BenchmarkOuterHashJoinExec(b *testing.B) {
 	lvl := log.GetLevel()
 	log.SetLevel(zapcore.ErrorLevel)
@@ -1936,6 +1961,65 @@ func BenchmarkSortExec(b *testing.B) {
 	}
 }
 
+func benchmarkTopNExec(b *testing.B, cas *sortCase, count uint64) {
+	opt := mockDataSourceParameters{
+		schema: expression.NewSchema(cas.columns()...),
+		rows:   cas.rows,
+		ctx:    cas.ctx,
+		ndvs:   cas.ndvs,
+	}
+	dataSource := buildMockDataSource(opt)
+	exec := &TopNExec{
+		SortExec: SortExec{
+			baseExecutor: newBaseExecutor(cas.ctx, dataSource.schema, 4, dataSource),
+			ByItems:      make([]*util.ByItems, 0, len(cas.orderByIdx)),
+			schema:       dataSource.schema,
+		},
+		limit: &core.PhysicalLimit{Count: count},
+	}
+	for _, idx := range cas.orderByIdx {
+		exec.ByItems = append(exec.ByItems, &util.ByItems{Expr: cas.columns()[idx]})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		tmpCtx := context.Background()
+		chk := newFirstChunk(exec)
+		dataSource.prepareChunks()
+
+		b.StartTimer()
+		if err := exec.Open(tmpCtx); err != nil {
+			b.Fatal(err)
+		}
+		for {
+			if err := exec.Next(tmpCtx, chk); err != nil {
+				b.Fatal(err)
+			}
+			if chk.NumRows() == 0 {
+				break
+			}
+		}
+
+		if err := exec.Close(); err != nil {
+			b.Fatal(err)
+		}
+		b.StopTimer()
+	}
+}
+
+func BenchmarkTopNExec(b *testing.B) {
+	b.ReportAllocs()
+	cas := defaultSortTestCase()
+	cas.rows = 1000000
+	cas.ndvs = []int{0, 0}
+	cas.orderByIdx = []int{0, 1}
+	for _, count := range []uint64{100, 100000} {
+		b.Run(fmt.Sprintf("%v, limit:%v", cas, count), func(b *testing.B) {
+			benchmarkTopNExec(b, cas, count)
+		})
+	}
+}
+
 type limitCase struct {
 	rows                  int
 	offset                int
@@ -2135,3 +2219,23 @@ func BenchmarkAggPartialResultMapperMemoryUsage(b *testing.B) {
 func BenchmarkPipelinedRowNumberWindowFunctionExecution(b *testing.B) {
 	b.ReportAllocs()
 }
+
+func BenchmarkRowDecoderDefaultValues(b *testing.B) {
+	ctx := mock.NewContext()
+	decoder, schema, row := newRowDecoderForTest(b, ctx)
+	fieldTypes := make([]*types.FieldType, 0, schema.Len())
+	for _, col := range schema.Columns {
+		fieldTypes = append(fieldTypes, col.RetType)
+	}
+	chk := chunk.NewChunkWithCapacity(fieldTypes, 1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if chk.NumRows() == 1024 {
+			chk.Reset()
+		}
+		if err := decoder.DecodeToChunk(row, kv.IntHandle(i), chk); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -17,7 +17,10 @@ package executor
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -29,6 +32,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/encryptionpb"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/br/pkg/glue"
+	"github.com/pingcap/tidb/br/pkg/metautil"
 	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tidb/br/pkg/task"
 	"github.com/pingcap/tidb/br/pkg/task/show"
@@ -48,6 +52,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/util/mathutil"
 	"github.com/pingcap/tidb/util/printer"
 	"github.com/pingcap/tidb/util/sem"
 	"github.com/pingcap/tidb/util/sqlexec"
@@ -56,6 +61,7 @@ import (
 	"github.com/tikv/client-go/v2/oracle"
 	pd "github.com/tikv/pd/client"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
 const clearInterval = 10 * time.Minute
@@ -318,7 +324,19 @@ func (b *executorBuilder) buildBRIE(s *ast.BRIEStmt, schema *expression.Schema)
 	default:
 	}
 
-	if tidbCfg.Store != "tikv" {
+	validate := false
+	for _, opt := range s.Options {
+		if opt.Tp == ast.BRIEOptionValidate {
+			validate = true
+		}
+	}
+	if validate && s.Kind != ast.BRIEKindBackup {
+		b.err = errors.Errorf("VALIDATE is not supported by %s", s.Kind)
+		return nil
+	}
+
+	// validating a backup only reads the storage, it doesn't require tikv.
+	if tidbCfg.Store != "tikv" && !validate {
 		b.err = errors.Errorf("%s requires tikv store, not %s", s.Kind, tidbCfg.Store)
 		return nil
 	}
@@ -339,6 +357,13 @@ func (b *executorBuilder) buildBRIE(s *ast.BRIEStmt, schema *expression.Schema)
 		}
 	}
 
+	if validate {
+		return &backupValidateExec{
+			baseExecutor: newBaseExecutor(b.ctx, schema, 0),
+			cfg:          cfg,
+		}
+	}
+
 	switch {
 	case len(s.Tables) != 0:
 		tables := make([]filter.Table, 0, len(s.Tables))
@@ -468,6 +493,168 @@ type showMetaExec struct {
 	showConfig show.Config
 }
 
+// backupValidateExec represents an executor for `BACKUP ... VALIDATE`, it
+// checks the data files recorded in the backupmeta against the storage without
+// modifying the backup or the cluster.
+type backupValidateExec struct {
+	baseExecutor
+
+	cfg task.Config
+
+	validated bool
+	results   []backupValidateResult
+	cursor    int
+}
+
+const (
+	backupValidatePass = "PASS"
+	backupValidateFail = "FAIL"
+)
+
+type backupValidateResult struct {
+	name    string
+	size    uint64
+	message string
+}
+
+// Next implements the Executor Next interface.
+func (e *backupValidateExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.GrowAndReset(e.maxChunkSize)
+	if !e.validated {
+		results, err := e.validate(ctx)
+		if err != nil {
+			return err
+		}
+		e.results, e.validated = results, true
+	}
+	for ; e.cursor < len(e.results) && !req.IsFull(); e.cursor++ {
+		r := e.results[e.cursor]
+		req.AppendString(0, r.name)
+		req.AppendUint64(1, r.size)
+		if len(r.message) == 0 {
+			req.AppendString(2, backupValidatePass)
+		} else {
+			req.AppendString(2, backupValidateFail)
+		}
+		req.AppendString(3, r.message)
+	}
+	return nil
+}
+
+// validate checks the data files and the table checksums recorded in the backupmeta.
+func (e *backupValidateExec) validate(ctx context.Context) ([]backupValidateResult, error) {
+	_, strg, backupMeta, err := task.ReadBackupMeta(ctx, metautil.MetaFile, &e.cfg)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to read backupmeta")
+	}
+	reader := metautil.NewMetaReader(backupMeta, strg, &e.cfg.CipherInfo)
+
+	var (
+		tables []*metautil.Table
+		files  []*backuppb.File
+	)
+	if backupMeta.IsRawKv {
+		files = backupMeta.Files
+	} else {
+		out := make(chan *metautil.Table, 16)
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- reader.ReadSchemasFiles(ctx, out)
+			close(out)
+		}()
+		for tbl := range out {
+			tables = append(tables, tbl)
+			files = append(files, tbl.Files...)
+		}
+		if err := <-errCh; err != nil {
+			return nil, errors.Annotate(err, "failed to read schemas and files from backupmeta")
+		}
+	}
+
+	results := make([]backupValidateResult, len(files))
+	eg, ectx := errgroup.WithContext(ctx)
+	// CONCURRENCY = 0 would block all the workers, validate the files one by one instead.
+	eg.SetLimit(mathutil.Max(int(e.cfg.Concurrency), 1))
+	for i, file := range files {
+		i, file := i, file
+		eg.Go(func() error {
+			msg, err := validateBackupFile(ectx, strg, file)
+			results[i] = backupValidateResult{name: file.Name, size: file.GetSize_(), message: msg}
+			return err
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	for _, tbl := range tables {
+		if tbl.Info == nil {
+			continue
+		}
+		results = append(results, backupValidateResult{
+			name:    fmt.Sprintf("`%s`.`%s`", tbl.DB.Name.O, tbl.Info.Name.O),
+			size:    tbl.TotalBytes,
+			message: validateBackupTableChecksum(tbl),
+		})
+	}
+	return results, nil
+}
+
+// validateBackupFile checks the presence, size and sha256 of a data file, it
+// returns the reason if the file is invalid.
+func validateBackupFile(ctx context.Context, strg storage.ExternalStorage, file *backuppb.File) (string, error) {
+	exists, err := strg.FileExists(ctx, file.Name)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if !exists {
+		return "file is missing", nil
+	}
+	reader, err := strg.Open(ctx, file.Name)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			log.Warn("close backup file failed", zap.String("file", file.Name), zap.Error(err))
+		}
+	}()
+	// The file is streamed into the hash, so a large SST file isn't loaded into the memory.
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, reader)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if file.GetSize_() > 0 && uint64(size) != file.GetSize_() {
+		return fmt.Sprintf("size mismatch, recorded %d, actual %d", file.GetSize_(), size), nil
+	}
+	if len(file.Sha256) > 0 {
+		if sum := hasher.Sum(nil); !bytes.Equal(sum, file.Sha256) {
+			return fmt.Sprintf("sha256 mismatch, recorded %s, actual %s",
+				hex.EncodeToString(file.Sha256), hex.EncodeToString(sum)), nil
+		}
+	}
+	return "", nil
+}
+
+// validateBackupTableChecksum checks the checksum recorded for a table is
+// consistent with the files of the table, it returns the reason if not.
+func validateBackupTableChecksum(tbl *metautil.Table) string {
+	if tbl.NoChecksum() {
+		return ""
+	}
+	var crc64Xor, totalKvs, totalBytes uint64
+	for _, file := range tbl.Files {
+		crc64Xor ^= file.Crc64Xor
+		totalKvs += file.TotalKvs
+		totalBytes += file.TotalBytes
+	}
+	if crc64Xor != tbl.Crc64Xor || totalKvs != tbl.TotalKvs || totalBytes != tbl.TotalBytes {
+		return fmt.Sprintf("checksum mismatch, recorded (crc64xor: %d, kvs: %d, bytes: %d), files (crc64xor: %d, kvs: %d, bytes: %d)",
+			tbl.Crc64Xor, tbl.TotalKvs, tbl.TotalBytes, crc64Xor, totalKvs, totalBytes)
+	}
+	return ""
+}
+
 // BRIEExec represents an executor for BRIE statements (BACKUP, RESTORE, etc)
 type BRIEExec struct {
 	baseExecutor
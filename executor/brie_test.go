@@ -15,13 +15,21 @@
 package executor
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
+	backuppb "github.com/pingcap/kvproto/pkg/brpb"
+	"github.com/pingcap/tidb/br/pkg/metautil"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
@@ -29,6 +37,7 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/stretchr/testify/require"
@@ -139,3 +148,126 @@ func TestFetchShowBRIE(t *testing.T) {
 	globalBRIEQueue.clearTask(e.ctx.GetSessionVars().StmtCtx)
 	require.Equal(t, info2Res, fetchShowBRIEResult(t, e, brieColTypes))
 }
+
+func TestBackupValidate(t *testing.T) {
+	sctx := mock.NewContext()
+	ctx := context.Background()
+	backupDir := t.TempDir()
+
+	dbInfo := &model.DBInfo{ID: 1, Name: model.NewCIStr("test")}
+	tblInfo := &model.TableInfo{ID: 100, Name: model.NewCIStr("t")}
+	dbData, err := json.Marshal(dbInfo)
+	require.NoError(t, err)
+	tblData, err := json.Marshal(tblInfo)
+	require.NoError(t, err)
+	contents := map[string][]byte{
+		"1.sst": []byte("the first file"),
+		"2.sst": []byte("the second file"),
+	}
+	backupMeta := &backuppb.BackupMeta{
+		Schemas: []*backuppb.Schema{{
+			Db:         dbData,
+			Table:      tblData,
+			Crc64Xor:   1 ^ 2,
+			TotalKvs:   2,
+			TotalBytes: 20,
+		}},
+	}
+	for i, name := range []string{"1.sst", "2.sst"} {
+		require.NoError(t, os.WriteFile(filepath.Join(backupDir, name), contents[name], 0o644))
+		sum := sha256.Sum256(contents[name])
+		backupMeta.Files = append(backupMeta.Files, &backuppb.File{
+			Name:       name,
+			Sha256:     sum[:],
+			Size_:      uint64(len(contents[name])),
+			StartKey:   tablecodec.EncodeTablePrefix(tblInfo.ID),
+			EndKey:     tablecodec.EncodeTablePrefix(tblInfo.ID + 1),
+			Crc64Xor:   uint64(i + 1),
+			TotalKvs:   1,
+			TotalBytes: 10,
+		})
+	}
+	metaData, err := proto.Marshal(backupMeta)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(backupDir, metautil.MetaFile), metaData, 0o644))
+
+	validate := func(sql string) ([]string, error) {
+		stmt, err := parser.New().ParseOneStmt(sql, "", "")
+		require.NoError(t, err)
+		plan, _, err := core.BuildLogicalPlanForTest(ctx, sctx, stmt, infoschema.MockInfoSchema([]*model.TableInfo{core.MockSignedTable()}))
+		require.NoError(t, err)
+		b := newExecutorBuilder(sctx, nil, nil)
+		e := b.buildBRIE(stmt.(*ast.BRIEStmt), plan.Schema())
+		if b.err != nil {
+			return nil, b.err
+		}
+		require.NoError(t, e.Open(ctx))
+		defer func() {
+			require.NoError(t, e.Close())
+		}()
+		colTypes := make([]*types.FieldType, 0, plan.Schema().Len())
+		for _, col := range plan.Schema().Columns {
+			colTypes = append(colTypes, col.RetType)
+		}
+		var rows []string
+		req := newFirstChunk(e)
+		for {
+			if err := e.Next(ctx, req); err != nil {
+				return nil, err
+			}
+			if req.NumRows() == 0 {
+				return rows, nil
+			}
+			require.LessOrEqual(t, req.NumRows(), sctx.GetSessionVars().MaxChunkSize)
+			rows = append(rows, strings.Split(strings.TrimSuffix(req.ToString(colTypes), "\n"), "\n")...)
+		}
+	}
+	sql := fmt.Sprintf("BACKUP DATABASE * TO 'local://%s' VALIDATE", backupDir)
+
+	rows, err := validate(sql)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"1.sst, 14, PASS, ",
+		"2.sst, 15, PASS, ",
+		"`test`.`t`, 20, PASS, ",
+	}, rows)
+
+	// the files are validated one by one with CONCURRENCY = 0, and the results are returned across chunks.
+	maxChunkSize := sctx.GetSessionVars().MaxChunkSize
+	sctx.GetSessionVars().MaxChunkSize = 2
+	rows, err = validate(fmt.Sprintf("BACKUP DATABASE * TO 'local://%s' CONCURRENCY = 0 VALIDATE", backupDir))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"1.sst, 14, PASS, ",
+		"2.sst, 15, PASS, ",
+		"`test`.`t`, 20, PASS, ",
+	}, rows)
+	sctx.GetSessionVars().MaxChunkSize = maxChunkSize
+
+	// truncate the second file.
+	require.NoError(t, os.WriteFile(filepath.Join(backupDir, "2.sst"), contents["2.sst"][:5], 0o644))
+	rows, err = validate(sql)
+	require.NoError(t, err)
+	require.Equal(t, "2.sst, 15, FAIL, size mismatch, recorded 15, actual 5", rows[1])
+
+	// corrupt the second file without changing its size.
+	corrupted := bytes.ToUpper(contents["2.sst"])
+	require.NoError(t, os.WriteFile(filepath.Join(backupDir, "2.sst"), corrupted, 0o644))
+	rows, err = validate(sql)
+	require.NoError(t, err)
+	require.Contains(t, rows[1], "2.sst, 15, FAIL, sha256 mismatch")
+
+	// remove the first file.
+	require.NoError(t, os.Remove(filepath.Join(backupDir, "1.sst")))
+	rows, err = validate(sql)
+	require.NoError(t, err)
+	require.Equal(t, "1.sst, 14, FAIL, file is missing", rows[0])
+
+	// the backup is not modified by validating.
+	data, err := os.ReadFile(filepath.Join(backupDir, "2.sst"))
+	require.NoError(t, err)
+	require.Equal(t, corrupted, data)
+
+	_, err = validate(fmt.Sprintf("RESTORE DATABASE * FROM 'local://%s' VALIDATE", backupDir))
+	require.ErrorContains(t, err, "VALIDATE is not supported by RESTORE")
+}
@@ -18,12 +18,12 @@ import (
 	"bytes"
 	"context"
 	"math"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
-	"unsafe"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
@@ -47,6 +47,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	plannerutil "github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -59,22 +60,27 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/cteutil"
 	"github.com/pingcap/tidb/util/dbterror/exeerrors"
 	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/mathutil"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tidb/util/stringutil"
 	"github.com/pingcap/tidb/util/timeutil"
 	"github.com/pingcap/tipb/go-tipb"
 	clientkv "github.com/tikv/client-go/v2/kv"
+	"github.com/tikv/client-go/v2/oracle"
 	"github.com/tikv/client-go/v2/tikv"
 	"github.com/tikv/client-go/v2/txnkv"
 	"github.com/tikv/client-go/v2/txnkv/txnsnapshot"
 	clientutil "github.com/tikv/client-go/v2/util"
 	"golang.org/x/exp/slices"
+	"golang.org/x/sync/errgroup"
 )
 
 // executorBuilder builds an Executor from a Plan.
@@ -93,6 +99,11 @@ type executorBuilder struct {
 	inDeleteStmt     bool
 	inInsertStmt     bool
 	inSelectLockStmt bool
+	// chaosLatencyInjected indicates whether the latency of tidb_chaos_build_latency_ms has been injected.
+	chaosLatencyInjected bool
+	// inApplyInner indicates the executors being built are in the inner side of apply, which are re-executed
+	// for every outer row.
+	inApplyInner bool
 
 	// forDataReaderBuilder indicates whether the builder is used by a dataReaderBuilder.
 	// When forDataReader is true, the builder should use the dataReaderTS as the executor read ts. This is because
@@ -100,6 +111,46 @@ type executorBuilder struct {
 	// can return a correct value even if the session context has already been destroyed
 	forDataReaderBuilder bool
 	dataReaderTS         uint64
+
+	// partitionPruneCache is shared by all the dataReaderBuilders of the statement, see newDataReaderBuilder.
+	partitionPruneCache *partitionPruneCache
+
+	// snapshotTSChecker is shared by all the dataReaderBuilders of the statement too, it's nil unless the
+	// assertSnapshotTSConsistency failpoint is enabled.
+	snapshotTSChecker *snapshotTSChecker
+
+	// shuffleReceivers maps the receiver stubs of a shuffle to the receivers of the worker being built,
+	// it's only set for the builders cloned by buildShuffle.
+	shuffleReceivers map[*plannercore.PhysicalShuffleReceiverStub]*shuffleReceiver
+
+	// aggPreviewHandler receives the previews of the top-level hash aggregation of the statement,
+	// it's set when tidb_enable_streaming_agg_preview is on and the caller passes a handler.
+	aggPreviewHandler AggPreviewHandler
+}
+
+// snapshotTSChecker asserts that all the readers of a statement, no matter they read TiKV or TiFlash, read the
+// same snapshot. It's a debug assertion to catch the readers reading inconsistent data within a statement.
+type snapshotTSChecker struct {
+	mu sync.Mutex
+	ts uint64
+}
+
+// register records the snapshot ts chosen by a reader and returns an error if it differs from the one chosen by
+// the other readers of the statement.
+func (c *snapshotTSChecker) register(ts uint64) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ts == 0 {
+		c.ts = ts
+		return nil
+	}
+	if c.ts != ts {
+		return errors.Errorf("snapshot ts %d of the reader mismatches the snapshot ts %d of the statement", ts, c.ts)
+	}
+	return nil
 }
 
 // CTEStorages stores resTbl and iterInTbl for CTEExec.
@@ -112,7 +163,7 @@ type CTEStorages struct {
 
 func newExecutorBuilder(ctx sessionctx.Context, is infoschema.InfoSchema, ti *TelemetryInfo) *executorBuilder {
 	txnManager := sessiontxn.GetTxnManager(ctx)
-	return &executorBuilder{
+	b := &executorBuilder{
 		ctx:              ctx,
 		is:               is,
 		Ti:               ti,
@@ -120,6 +171,10 @@ func newExecutorBuilder(ctx sessionctx.Context, is infoschema.InfoSchema, ti *Te
 		txnScope:         txnManager.GetTxnScope(),
 		readReplicaScope: txnManager.GetReadReplicaScope(),
 	}
+	failpoint.Inject("assertSnapshotTSConsistency", func() {
+		b.snapshotTSChecker = &snapshotTSChecker{}
+	})
+	return b
 }
 
 // MockPhysicalPlan is used to return a specified executor in when build.
@@ -129,6 +184,23 @@ type MockPhysicalPlan interface {
 	GetExecutor() Executor
 }
 
+// unsupportedPlans records the plans which are known by the planner but can't be built into executors,
+// the value is the reason which is shown to the user.
+var unsupportedPlans = make(map[reflect.Type]string)
+
+// registerUnsupportedPlan marks the plan type as known but not supported by the executor.
+func registerUnsupportedPlan(p plannercore.Plan, reason string) {
+	unsupportedPlans[reflect.TypeOf(p)] = reason
+}
+
+func init() {
+	registerUnsupportedPlan(&plannercore.PhysicalExpand{}, "it can only be executed by TiFlash in MPP mode")
+	registerUnsupportedPlan(&plannercore.PhysicalSequence{}, "it can only be executed by TiFlash in MPP mode")
+	registerUnsupportedPlan(&plannercore.PhysicalExchangeSender{}, "it can only be executed by TiFlash in MPP mode")
+	registerUnsupportedPlan(&plannercore.PhysicalExchangeReceiver{}, "it can only be executed by TiFlash in MPP mode")
+	registerUnsupportedPlan(&plannercore.PhysicalCTEStorage{}, "it can only be built as a part of CTE")
+}
+
 // MockExecutorBuilder is a wrapper for executorBuilder.
 // ONLY used in test.
 type MockExecutorBuilder struct {
@@ -147,6 +219,23 @@ func (b *MockExecutorBuilder) Build(p plannercore.Plan) Executor {
 }
 
 func (b *executorBuilder) build(p plannercore.Plan) Executor {
+	if !b.chaosLatencyInjected {
+		// The latency is injected only once for a statement, no matter how many executors are built.
+		// SET statements are never delayed, so the latency can always be turned off.
+		b.chaosLatencyInjected = true
+		if _, isSet := p.(*plannercore.Set); !isSet && b.ctx.GetSessionVars().ChaosBuildLatencyMs > 0 {
+			latency := time.Duration(b.ctx.GetSessionVars().ChaosBuildLatencyMs) * time.Millisecond
+			if b.err = b.injectChaosLatency(latency); b.err != nil {
+				return nil
+			}
+		}
+	}
+	if b.aggPreviewHandler != nil {
+		if pp, ok := p.(plannercore.PhysicalPlan); ok && len(pp.Children()) > 1 {
+			// The aggregations below a join or a union don't produce the results of the statement alone.
+			b.aggPreviewHandler = nil
+		}
+	}
 	switch v := p.(type) {
 	case nil:
 		return nil
@@ -308,12 +397,33 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		if mp, ok := p.(MockPhysicalPlan); ok {
 			return mp.GetExecutor()
 		}
+		if reason, ok := unsupportedPlans[reflect.TypeOf(p)]; ok {
+			b.err = exeerrors.ErrUnknownPlan.GenWithStack("Plan %T is not supported by the executor yet, %s", p, reason)
+			return nil
+		}
 
 		b.err = exeerrors.ErrUnknownPlan.GenWithStack("Unknown Plan %T", p)
 		return nil
 	}
 }
 
+// injectChaosLatency sleeps the latency to simulate the slow startup of the statement, it's used by the chaos
+// tests. The sleep is interrupted once the statement is killed.
+func (b *executorBuilder) injectChaosLatency(latency time.Duration) error {
+	const checkInterval = 10 * time.Millisecond
+	vars := b.ctx.GetSessionVars()
+	for deadline := time.Now().Add(latency); ; {
+		if atomic.LoadUint32(&vars.Killed) == 1 {
+			return errors.Trace(exeerrors.ErrQueryInterrupted)
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil
+		}
+		time.Sleep(mathutil.Min(remaining, checkInterval))
+	}
+}
+
 func (b *executorBuilder) buildCancelDDLJobs(v *plannercore.CancelDDLJobs) Executor {
 	e := &CancelDDLJobsExec{
 		CommandDDLJobsExec: &CommandDDLJobsExec{
@@ -498,6 +608,29 @@ func (b *executorBuilder) buildCheckTable(v *plannercore.CheckTable) Executor {
 		retCh:        make(chan error, len(readerExecs)),
 		checkIndex:   v.CheckIndex,
 	}
+	if v.CheckFK {
+		tblInfo := v.Table.Meta()
+		for _, fk := range tblInfo.ForeignKeys {
+			if fk.Version < 1 {
+				continue
+			}
+			failedErr := plannercore.ErrNoReferencedRow2.FastGenByArgs(fk.String(v.DBName, tblInfo.Name.L))
+			fkCheck, err := plannercore.BuildFKCheckOnModifyChildTable(b.ctx, b.is, fk, failedErr)
+			if err != nil {
+				b.err = err
+				return nil
+			}
+			if fkCheck == nil {
+				continue
+			}
+			fkCheckExec, err := buildFKCheckExec(b.ctx, v.Table, fkCheck)
+			if err != nil {
+				b.err = err
+				return nil
+			}
+			e.fkChecks = append(e.fkChecks, fkCheckExec)
+		}
+	}
 	return e
 }
 
@@ -746,6 +879,13 @@ func (b *executorBuilder) buildLimit(v *plannercore.PhysicalLimit) Executor {
 	if b.err != nil {
 		return nil
 	}
+	if proj, ok := childExec.(*ProjectionExec); ok && v.Count > 0 {
+		// The projection doesn't need to evaluate the rows beyond the limit.
+		proj.stopAfterRows = v.Offset + v.Count
+	}
+	if v.Count > 0 {
+		b.pushLimitToTableReader(childExec, v.Offset+v.Count)
+	}
 	n := int(mathutil.Min(v.Count, uint64(b.ctx.GetSessionVars().MaxChunkSize)))
 	base := newBaseExecutor(b.ctx, v.Schema(), v.ID(), childExec)
 	base.initCap = n
@@ -768,6 +908,46 @@ func (b *executorBuilder) buildLimit(v *plannercore.PhysicalLimit) Executor {
 	return e
 }
 
+// pushLimitToTableReader pushes a limit of the first end rows into the coprocessor request of the table reader
+// under exec, so that the rows beyond the limit are not transferred from the storage. It's only done when the
+// reader simply scans and filters the table, and the operators between exec and the reader don't change the row
+// count. The coprocessor doesn't support the offset, so the rows before the offset are still discarded by LimitExec.
+func (b *executorBuilder) pushLimitToTableReader(exec Executor, end uint64) {
+	for {
+		proj, ok := exec.(*ProjectionExec)
+		if !ok {
+			break
+		}
+		exec = proj.children[0]
+	}
+	reader, ok := exec.(*TableReaderExecutor)
+	if !ok || reader.storeType != kv.TiKV || reader.batchCop || reader.dummy || len(reader.plans) == 0 {
+		return
+	}
+	for _, p := range reader.plans {
+		switch p.(type) {
+		case *plannercore.PhysicalTableScan, *plannercore.PhysicalSelection:
+		default:
+			return
+		}
+	}
+	lastPlan := reader.plans[len(reader.plans)-1]
+	limit := plannercore.PhysicalLimit{Count: end}.Init(b.ctx, lastPlan.Stats(), lastPlan.SelectBlockOffset())
+	limitPB, err := limit.ToPB(b.ctx, kv.TiKV)
+	if err != nil {
+		b.err = err
+		return
+	}
+	// The plans are shared with the cached plan, so don't append to them in place.
+	plans := make([]plannercore.PhysicalPlan, 0, len(reader.plans)+1)
+	reader.plans = append(append(plans, reader.plans...), limit)
+	reader.dagPB.Executors = append(reader.dagPB.Executors, limitPB)
+	// The range counts are incomplete once the limit is pushed down.
+	reader.feedback.Invalidate()
+	collect := false
+	reader.dagPB.CollectRangeCounts = &collect
+}
+
 func (b *executorBuilder) buildPrepare(v *plannercore.Prepare) Executor {
 	base := newBaseExecutor(b.ctx, v.Schema(), v.ID())
 	base.initCap = chunk.ZeroCapacity
@@ -826,8 +1006,14 @@ func (b *executorBuilder) buildShow(v *plannercore.PhysicalShow) Executor {
 		IfNotExists:           v.IfNotExists,
 		GlobalScope:           v.GlobalScope,
 		Extended:              v.Extended,
+		LiveStats:             v.LiveStats,
 		Extractor:             v.Extractor,
 		ImportJobID:           v.ImportJobID,
+		HealthyThreshold:      v.HealthyThreshold,
+		StatsDBFilter:         v.StatsDBFilter,
+		StatsTableFilter:      v.StatsTableFilter,
+		StatsBucketLower:      v.StatsBucketLower,
+		StatsBucketUpper:      v.StatsBucketUpper,
 	}
 	if e.Tp == ast.ShowMasterStatus {
 		// show master status need start ts.
@@ -945,6 +1131,7 @@ func (b *executorBuilder) buildInsert(v *plannercore.Insert) Executor {
 		hasRefCols:                v.NeedFillDefaultValue,
 		SelectExec:                selectExec,
 		rowLen:                    v.RowLen,
+		autoIDBatchSize:           b.ctx.GetSessionVars().AutoIDBatchSize,
 	}
 	err := ivs.initInsertColumns()
 	if err != nil {
@@ -967,6 +1154,11 @@ func (b *executorBuilder) buildInsert(v *plannercore.Insert) Executor {
 		InsertValues: ivs,
 		OnDuplicate:  append(v.OnDuplicate, v.GenCols.OnDuplicates...),
 	}
+	// The rows of `INSERT ... ON DUPLICATE KEY UPDATE` may depend on each other, so they can't be reordered.
+	if tblInfo := ivs.Table.Meta(); b.ctx.GetSessionVars().InsertSortByHandle && len(insert.OnDuplicate) == 0 &&
+		(tblInfo.PKIsHandle || tblInfo.IsCommonHandle) {
+		insert.sortByHandle = true
+	}
 	return insert
 }
 
@@ -1008,6 +1200,7 @@ func (b *executorBuilder) buildLoadData(v *plannercore.LoadData) Executor {
 		b.err = err
 		return nil
 	}
+	worker.concurrency = b.ctx.GetSessionVars().LoadDataConcurrency
 
 	return &LoadDataExec{
 		baseExecutor:   base,
@@ -1052,6 +1245,9 @@ func (b *executorBuilder) buildIndexAdvise(v *plannercore.IndexAdvise) Executor
 			Ctx:            b.ctx,
 		},
 	}
+	if feedback, ok := b.ctx.Value(IndexScanFeedbackVarKey).(*IndexScanFeedback); ok {
+		e.indexAdviseInfo.ScanFeedback = feedback.Snapshot()
+	}
 	return e
 }
 
@@ -1084,6 +1280,13 @@ func (b *executorBuilder) buildPlanReplayer(v *plannercore.PlanReplayer) Executo
 		}
 		return e
 	}
+	if v.Reset {
+		e := &PlanReplayerExec{
+			baseExecutor: newBaseExecutor(b.ctx, nil, v.ID()),
+			CaptureInfo:  &PlanReplayerCaptureInfo{Reset: true},
+		}
+		return e
+	}
 
 	e := &PlanReplayerExec{
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
@@ -1283,6 +1486,7 @@ func (b *executorBuilder) buildExplain(v *plannercore.Explain) Executor {
 			explainExec.ruRuntimeStats = store.CreateRURuntimeStats(startTS)
 		}
 		explainExec.analyzeExec = b.build(v.TargetPlan)
+		explainExec.assertOperatorRows = b.ctx.GetSessionVars().AssertOperatorRows
 	}
 	return explainExec
 }
@@ -1292,11 +1496,24 @@ func (b *executorBuilder) buildSelectInto(v *plannercore.SelectInto) Executor {
 	if b.err != nil {
 		return nil
 	}
-	return &SelectIntoExec{
+	compressTp, err := outfileCompressType(v.IntoOpt)
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	e := &SelectIntoExec{
 		baseExecutor:   newBaseExecutor(b.ctx, v.Schema(), v.ID(), child),
 		intoOpt:        v.IntoOpt,
 		LineFieldsInfo: v.LineFieldsInfo,
+		compressTp:     compressTp,
 	}
+	if v.IntoOpt.WithHeader {
+		e.header = make([]string, 0, len(v.TargetNames))
+		for _, name := range v.TargetNames {
+			e.header = append(e.header, name.ColName.O)
+		}
+	}
+	return e
 }
 
 func (b *executorBuilder) buildUnionScanExec(v *plannercore.PhysicalUnionScan) Executor {
@@ -1366,6 +1583,7 @@ func (b *executorBuilder) buildUnionScanFromReader(reader Executor, v *plannerco
 		us.conditions, us.conditionsWithVirCol = plannercore.SplitSelCondsWithVirtualColumn(v.Conditions)
 		us.columns = x.columns
 		us.table = x.table
+		us.virtualColumnIndex = x.virtualColumnIndex
 		us.handleCachedTable(b, x, sessionVars, startTS)
 	case *IndexLookUpExecutor:
 		us.desc = x.desc
@@ -1422,6 +1640,20 @@ func (us *UnionScanExec) handleCachedTable(b *executorBuilder, x bypassDataSourc
 	}
 }
 
+// synthesizeJoinDefaultValues makes the all-NULL default values of the inner side of a join, which are used when the
+// planner doesn't provide them. If tidb_warn_on_synth_join_defaults is on, a warning is appended for the outer joins,
+// because their default values are the output of the unmatched outer rows.
+func (b *executorBuilder) synthesizeJoinDefaultValues(executor string, joinType plannercore.JoinType, innerIsLeft bool, length int) []types.Datum {
+	if b.ctx.GetSessionVars().WarnOnSynthJoinDefaults && (joinType == plannercore.LeftOuterJoin || joinType == plannercore.RightOuterJoin) {
+		side := "right"
+		if innerIsLeft {
+			side = "left"
+		}
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("the default values of the %s side of %s are synthesized for %s", side, joinType, executor))
+	}
+	return make([]types.Datum, length)
+}
+
 // buildMergeJoin builds MergeJoinExec executor.
 func (b *executorBuilder) buildMergeJoin(v *plannercore.PhysicalMergeJoin) Executor {
 	leftExec := b.build(v.Children()[0])
@@ -1437,9 +1669,9 @@ func (b *executorBuilder) buildMergeJoin(v *plannercore.PhysicalMergeJoin) Execu
 	defaultValues := v.DefaultValues
 	if defaultValues == nil {
 		if v.JoinType == plannercore.RightOuterJoin {
-			defaultValues = make([]types.Datum, leftExec.Schema().Len())
+			defaultValues = b.synthesizeJoinDefaultValues("merge join", v.JoinType, true, leftExec.Schema().Len())
 		} else {
-			defaultValues = make([]types.Datum, rightExec.Schema().Len())
+			defaultValues = b.synthesizeJoinDefaultValues("merge join", v.JoinType, false, rightExec.Schema().Len())
 		}
 	}
 
@@ -1493,6 +1725,41 @@ func (b *executorBuilder) buildMergeJoin(v *plannercore.PhysicalMergeJoin) Execu
 	return e
 }
 
+// buildBandMergeJoin builds a BandMergeJoinExec for the hash join which is executed by the band merge join.
+func (b *executorBuilder) buildBandMergeJoin(v *plannercore.PhysicalHashJoin, band *plannercore.JoinBand, leftExec, rightExec Executor) Executor {
+	outerIdx := band.OuterIdx
+	innerIdx := 1 - outerIdx
+	outerFilter := v.LeftConditions
+	if outerIdx == 1 {
+		outerFilter = v.RightConditions
+	}
+	defaultValues := v.DefaultValues
+	if defaultValues == nil {
+		defaultValues = b.synthesizeJoinDefaultValues("band merge join", v.JoinType, innerIdx == 0, v.Children()[innerIdx].Schema().Len())
+	}
+	return &BandMergeJoinExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), leftExec, rightExec),
+		joiner: newJoiner(b.ctx, v.JoinType, outerIdx == 1, defaultValues, v.OtherConditions,
+			retTypes(leftExec), retTypes(rightExec), markChildrenUsedCols(v.Schema(), v.Children()[0].Schema(), v.Children()[1].Schema()), false),
+		band:        newJoinBand(b.ctx, band, v.Children()[outerIdx].Schema(), v.Children()[innerIdx].Schema()),
+		outerIdx:    outerIdx,
+		outerFilter: outerFilter,
+	}
+}
+
+// canDedupHashJoinBuildKeys checks whether only the distinct keys of the build side are needed by the hash join.
+// For the semi joins, an outer row only cares about whether there is any matched inner row, unless the other
+// conditions need to be evaluated on every matched row. The rows of the null-aware joins are kept in the null bucket
+// and the radix hash table can't be looked up before all the rows are put, so they're not deduplicated.
+func canDedupHashJoinBuildKeys(v *plannercore.PhysicalHashJoin, isNAJoin bool, radixBits uint) bool {
+	switch v.JoinType {
+	case plannercore.SemiJoin, plannercore.AntiSemiJoin, plannercore.LeftOuterSemiJoin, plannercore.AntiLeftOuterSemiJoin:
+	default:
+		return false
+	}
+	return !v.UseOuterToBuild && len(v.OtherConditions) == 0 && !isNAJoin && radixBits == 0
+}
+
 func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executor {
 	leftExec := b.build(v.Children()[0])
 	if b.err != nil {
@@ -1504,9 +1771,13 @@ func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executo
 		return nil
 	}
 
+	if band := v.GetJoinBand(); band != nil {
+		return b.buildBandMergeJoin(v, band, leftExec, rightExec)
+	}
+
 	e := &HashJoinExec{
 		baseExecutor:          newBaseExecutor(b.ctx, v.Schema(), v.ID(), leftExec, rightExec),
-		probeSideTupleFetcher: &probeSideTupleFetcher{},
+		probeSideTupleFetcher: &probeSideTupleFetcher{prefetch: b.ctx.GetSessionVars().HashJoinProbePrefetch},
 		probeWorkers:          make([]*probeWorker, v.Concurrency),
 		buildWorker:           &buildWorker{},
 		hashJoinCtx: &hashJoinCtx{
@@ -1515,6 +1786,7 @@ func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executo
 			useOuterToBuild: v.UseOuterToBuild,
 			joinType:        v.JoinType,
 			concurrency:     v.Concurrency,
+			radixBits:       uint(b.ctx.GetSessionVars().HashJoinRadixBits),
 		},
 	}
 	e.hashJoinCtx.allocPool = e.AllocPool
@@ -1550,7 +1822,7 @@ func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executo
 			leftIsBuildSide = false
 		}
 		if defaultValues == nil {
-			defaultValues = make([]types.Datum, e.probeSideTupleFetcher.probeSideExec.Schema().Len())
+			defaultValues = b.synthesizeJoinDefaultValues("hash join", v.JoinType, v.InnerChildIdx == 0, e.probeSideTupleFetcher.probeSideExec.Schema().Len())
 		}
 	} else {
 		if v.InnerChildIdx == 0 {
@@ -1564,7 +1836,7 @@ func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executo
 			leftIsBuildSide = false
 		}
 		if defaultValues == nil {
-			defaultValues = make([]types.Datum, buildSideExec.Schema().Len())
+			defaultValues = b.synthesizeJoinDefaultValues("hash join", v.JoinType, v.InnerChildIdx == 0, buildSideExec.Schema().Len())
 		}
 	}
 	probeKeyColIdx := make([]int, len(probeKeys))
@@ -1595,6 +1867,7 @@ func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executo
 		}
 	}
 	e.buildWorker.buildKeyColIdx, e.buildWorker.buildNAKeyColIdx, e.buildWorker.buildSideExec, e.buildWorker.hashJoinCtx = buildKeyColIdx, buildNAKeyColIdx, buildSideExec, e.hashJoinCtx
+	e.buildWorker.dedupBuildKeys = canDedupHashJoinBuildKeys(v, isNAJoin, e.radixBits)
 	e.hashJoinCtx.isNullAware = isNAJoin
 	executor_metrics.ExecutorCountHashJoinExec.Inc()
 
@@ -1649,7 +1922,11 @@ func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executo
 }
 
 func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor {
-	src := b.build(v.Children()[0])
+	// Only the top-level aggregation is previewed, the aggregations below it don't produce the results of
+	// the statement.
+	previewHandler := b.aggPreviewHandler
+	b.aggPreviewHandler = nil
+	src := b.buildChildWithUsedCols(v.Children()[0], aggUsedCols(v.AggFuncs, v.GroupByItems))
 	if b.err != nil {
 		return nil
 	}
@@ -1687,6 +1964,11 @@ func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor
 		}
 	}
 	for _, aggDesc := range v.AggFuncs {
+		// The partial results of the ordered group_concat keep the sort keys of the rows, so the final
+		// workers can merge them in order.
+		if aggDesc.Name == ast.AggFuncGroupConcat && len(aggDesc.OrderByItems) > 0 {
+			continue
+		}
 		if aggDesc.HasDistinct || len(aggDesc.OrderByItems) > 0 {
 			e.isUnparallelExec = true
 		}
@@ -1694,8 +1976,23 @@ func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor
 	// When we set both tidb_hashagg_final_concurrency and tidb_hashagg_partial_concurrency to 1,
 	// we do not need to parallelly execute hash agg,
 	// and this action can be a workaround when meeting some unexpected situation using parallelExec.
-	if finalCon, partialCon := sessionVars.HashAggFinalConcurrency(), sessionVars.HashAggPartialConcurrency(); finalCon <= 0 || partialCon <= 0 || finalCon == 1 && partialCon == 1 {
+	finalCon, partialCon := sessionVars.HashAggFinalConcurrency(), sessionVars.HashAggPartialConcurrency()
+	if finalCon <= 0 || partialCon <= 0 || finalCon == 1 && partialCon == 1 {
+		e.isUnparallelExec = true
+	}
+	// The intermediate results can only be previewed by the single thread execution.
+	if previewHandler != nil {
 		e.isUnparallelExec = true
+		e.previewInterval = aggPreviewInterval
+		e.previewHandler = previewHandler
+	}
+	if !e.isUnparallelExec {
+		// Each final worker merges the groups dispatched to it, if the estimated number of groups is small,
+		// most of the final workers would be idle, so we reduce the final concurrency like the projection does.
+		if estGroups := int(math.Ceil(v.StatsCount() / float64(sessionVars.MaxChunkSize))); estGroups < finalCon {
+			finalCon = mathutil.Max(estGroups, 1)
+		}
+		e.partialConcurrency, e.finalConcurrency = partialCon, finalCon
 	}
 	partialOrdinal := 0
 	for i, aggDesc := range v.AggFuncs {
@@ -1731,7 +2028,7 @@ func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor
 }
 
 func (b *executorBuilder) buildStreamAgg(v *plannercore.PhysicalStreamAgg) Executor {
-	src := b.build(v.Children()[0])
+	src := b.buildChildWithUsedCols(v.Children()[0], aggUsedCols(v.AggFuncs, v.GroupByItems))
 	if b.err != nil {
 		return nil
 	}
@@ -1739,6 +2036,7 @@ func (b *executorBuilder) buildStreamAgg(v *plannercore.PhysicalStreamAgg) Execu
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), src),
 		groupChecker: newVecGroupChecker(b.ctx, v.GroupByItems),
 		aggFuncs:     make([]aggfuncs.AggFunc, 0, len(v.AggFuncs)),
+		cacheResult:  b.inApplyInner && isIndependentOfOuterRow(v),
 	}
 
 	if len(v.GroupByItems) != 0 || aggregation.IsAllFirstRow(v.AggFuncs) {
@@ -1762,6 +2060,25 @@ func (b *executorBuilder) buildStreamAgg(v *plannercore.PhysicalStreamAgg) Execu
 	return e
 }
 
+// isIndependentOfOuterRow checks whether the result of p in the inner side of apply is the same for every outer row,
+// which requires no correlated column is used by p and its children, and no CTE storage, which may change during
+// the recursion, is read.
+func isIndependentOfOuterRow(p plannercore.PhysicalPlan) bool {
+	switch p.(type) {
+	case *plannercore.PhysicalCTE, *plannercore.PhysicalCTETable:
+		return false
+	}
+	if len(p.ExtractCorrelatedCols()) > 0 {
+		return false
+	}
+	for _, child := range p.Children() {
+		if !isIndependentOfOuterRow(child) {
+			return false
+		}
+	}
+	return true
+}
+
 func (b *executorBuilder) buildSelection(v *plannercore.PhysicalSelection) Executor {
 	childExec := b.build(v.Children()[0])
 	if b.err != nil {
@@ -1775,7 +2092,7 @@ func (b *executorBuilder) buildSelection(v *plannercore.PhysicalSelection) Execu
 }
 
 func (b *executorBuilder) buildProjection(v *plannercore.PhysicalProjection) Executor {
-	childExec := b.build(v.Children()[0])
+	childExec := b.buildChildWithUsedCols(v.Children()[0], expression.ExtractColumnsFromExpressions(nil, v.Exprs, nil))
 	if b.err != nil {
 		return nil
 	}
@@ -1819,14 +2136,57 @@ func (b *executorBuilder) buildTableDual(v *plannercore.PhysicalTableDual) Execu
 // Please notice that in RC isolation, the above two ts are the same
 func (b *executorBuilder) getSnapshotTS() (ts uint64, err error) {
 	if b.forDataReaderBuilder {
-		return b.dataReaderTS, nil
+		return b.dataReaderTS, b.snapshotTSChecker.register(b.dataReaderTS)
 	}
 
 	txnManager := sessiontxn.GetTxnManager(b.ctx)
-	if b.inInsertStmt || b.inUpdateStmt || b.inDeleteStmt || b.inSelectLockStmt {
-		return txnManager.GetStmtForUpdateTS()
+	err = b.retryTSFetch(func() error {
+		if b.inInsertStmt || b.inUpdateStmt || b.inDeleteStmt || b.inSelectLockStmt {
+			ts, err = txnManager.GetStmtForUpdateTS()
+		} else {
+			ts, err = txnManager.GetStmtReadTS()
+		}
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return ts, b.snapshotTSChecker.register(ts)
+}
+
+// tsFetchRetryBackoff is the backoff before the first retry of fetching the snapshot timestamp,
+// it doubles after each retry.
+const tsFetchRetryBackoff = 10 * time.Millisecond
+
+// retryTSFetch calls fetch and retries it at most tidb_ts_fetch_retry times with backoff when it fails with a
+// retryable error. Every call of fetch requests a new timestamp, the failed request isn't reused by the txn manager.
+// It gives up retrying once the statement is killed or the backoff would exceed the max execution time.
+func (b *executorBuilder) retryTSFetch(fetch func() error) error {
+	sessVars := b.ctx.GetSessionVars()
+	backoff := tsFetchRetryBackoff
+	for i := 0; ; i++ {
+		err := fetch()
+		if err == nil || i >= sessVars.TSFetchRetry || !isRetryableTSFetchErr(err) || atomic.LoadUint32(&sessVars.Killed) != 0 {
+			return err
+		}
+		if maxExecutionTime := getMaxExecutionTime(b.ctx); maxExecutionTime > 0 &&
+			time.Since(sessVars.StartTime)+backoff > time.Duration(maxExecutionTime)*time.Millisecond {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// isRetryableTSFetchErr checks whether the failure of fetching the timestamp is transient. The fetch isn't retried
+// once the statement is canceled or interrupted.
+func isRetryableTSFetchErr(err error) bool {
+	switch errors.Cause(err) {
+	case context.Canceled, context.DeadlineExceeded:
+		return false
 	}
-	return txnManager.GetStmtReadTS()
+	return !exeerrors.ErrQueryInterrupted.Equal(err) && !exeerrors.ErrMaxExecTimeExceeded.Equal(err) &&
+		!kv.ErrInvalidTxn.Equal(err)
 }
 
 // getSnapshot get the appropriate snapshot from txnManager and set
@@ -1836,11 +2196,14 @@ func (b *executorBuilder) getSnapshot() (kv.Snapshot, error) {
 	var err error
 
 	txnManager := sessiontxn.GetTxnManager(b.ctx)
-	if b.inInsertStmt || b.inUpdateStmt || b.inDeleteStmt || b.inSelectLockStmt {
-		snapshot, err = txnManager.GetSnapshotWithStmtForUpdateTS()
-	} else {
-		snapshot, err = txnManager.GetSnapshotWithStmtReadTS()
-	}
+	err = b.retryTSFetch(func() error {
+		if b.inInsertStmt || b.inUpdateStmt || b.inDeleteStmt || b.inSelectLockStmt {
+			snapshot, err = txnManager.GetSnapshotWithStmtForUpdateTS()
+		} else {
+			snapshot, err = txnManager.GetSnapshotWithStmtReadTS()
+		}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -1858,11 +2221,41 @@ func (b *executorBuilder) getSnapshot() (kv.Snapshot, error) {
 			},
 		})
 	}
+	isStaleness := sessVars.TxnCtx.IsStaleness || b.isStaleness
+	if isStaleness && sessVars.StaleReadPreferFollower && !replicaReadType.IsFollowerRead() {
+		// Read the historical data from the followers to offload the leaders.
+		replicaReadType = kv.ReplicaReadFollower
+		snapshot.SetOption(kv.ReplicaRead, replicaReadType)
+	}
+	failpoint.Inject("assertStaleReadReplicaRead", func(val failpoint.Value) {
+		if isStaleness && replicaReadType != kv.ReplicaReadType(val.(int)) {
+			panic("stale read replica read option fail")
+		}
+	})
 
 	return snapshot, nil
 }
 
 func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executor {
+	e := b.buildMemTableReader(v)
+	if reader, ok := e.(*MemTableReaderExec); ok {
+		if maxBytes := b.ctx.GetSessionVars().MemTableMaxResultBytes; maxBytes > 0 {
+			limiter := &memTableResultLimiter{
+				table:    v.Table,
+				maxBytes: maxBytes,
+			}
+			if retriever, ok := reader.retriever.(*memtableRetriever); ok {
+				retriever.resultLimiter = limiter
+			} else {
+				limiter.memTableRetriever = reader.retriever
+				reader.retriever = limiter
+			}
+		}
+	}
+	return e
+}
+
+func (b *executorBuilder) buildMemTableReader(v *plannercore.PhysicalMemTable) Executor {
 	switch v.DBName.L {
 	case util.MetricSchemaName.L:
 		return &MemTableReaderExec{
@@ -1910,6 +2303,24 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 					serverInfoType: diagnosticspb.ServerInfoType_SystemInfo,
 				},
 			}
+		case strings.ToLower(infoschema.TableClusterPlanCache):
+			return &MemTableReaderExec{
+				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+				table:        v.Table,
+				retriever: &instanceMetricsRetriever{
+					extractor: v.Extractor.(*plannercore.ClusterTableExtractor),
+					buildRow:  planCacheRow,
+				},
+			}
+		case strings.ToLower(infoschema.TableCoprocessorCache):
+			return &MemTableReaderExec{
+				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+				table:        v.Table,
+				retriever: &instanceMetricsRetriever{
+					extractor: v.Extractor.(*plannercore.ClusterTableExtractor),
+					buildRow:  coprocessorCacheRow,
+				},
+			}
 		case strings.ToLower(infoschema.TableClusterLog):
 			return &MemTableReaderExec{
 				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
@@ -2031,6 +2442,16 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 					extractor: v.Extractor,
 				},
 			}
+		case strings.ToLower(infoschema.TableConnectionResourceUsage):
+			return &MemTableReaderExec{
+				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+				table:        v.Table,
+				retriever: &resourceUsageRetriever{
+					table:     v.Table,
+					columns:   v.Columns,
+					extractor: v.Extractor.(*plannercore.ConnectionResourceUsageExtractor),
+				},
+			}
 		case strings.ToLower(infoschema.TableTiDBTrx),
 			strings.ToLower(infoschema.ClusterTableTiDBTrx):
 			return &MemTableReaderExec{
@@ -2041,6 +2462,33 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 					columns: v.Columns,
 				},
 			}
+		case strings.ToLower(infoschema.TableTiKVGCStatus):
+			return &MemTableReaderExec{
+				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+				table:        v.Table,
+				retriever: &tikvGCStatusRetriever{
+					table:   v.Table,
+					columns: v.Columns,
+				},
+			}
+		case strings.ToLower(infoschema.TableSessionTemporaryTables):
+			return &MemTableReaderExec{
+				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+				table:        v.Table,
+				retriever: &sessionTemporaryTablesRetriever{
+					table:   v.Table,
+					columns: v.Columns,
+				},
+			}
+		case strings.ToLower(infoschema.TableLastUpdateColumnStats):
+			return &MemTableReaderExec{
+				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+				table:        v.Table,
+				retriever: &lastUpdateColumnStatsRetriever{
+					table:   v.Table,
+					columns: v.Columns,
+				},
+			}
 		case strings.ToLower(infoschema.TableDataLockWaits):
 			return &MemTableReaderExec{
 				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
@@ -2144,6 +2592,10 @@ func (b *executorBuilder) buildSort(v *plannercore.PhysicalSort) Executor {
 	if b.err != nil {
 		return nil
 	}
+	// The redundant sort may survive in some plan shapes, skip it if the child is provably ordered.
+	if isOrderedBy(childExec, v.ByItems) {
+		return childExec
+	}
 	sortExec := SortExec{
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), childExec),
 		ByItems:      v.ByItems,
@@ -2153,6 +2605,61 @@ func (b *executorBuilder) buildSort(v *plannercore.PhysicalSort) Executor {
 	return &sortExec
 }
 
+// isOrderedBy checks whether the rows returned by the executor are already in the order of byItems.
+// It's conservative, only the index readers which keep the index order are considered.
+func isOrderedBy(e Executor, byItems []*plannerutil.ByItems) bool {
+	switch x := e.(type) {
+	case *IndexReaderExecutor:
+		if !x.keepOrder || x.index.Global {
+			return false
+		}
+		if len(x.partitions) > 1 {
+			// The results of the partitions are merged by the pushed down byItems.
+			return len(x.byItems) > 0 && byItemsHavePrefix(x.byItems, byItems)
+		}
+		is, ok := x.plans[0].(*plannercore.PhysicalIndexScan)
+		return ok && indexScanOrderedBy(is, x.desc, byItems)
+	case *IndexLookUpExecutor:
+		if !x.keepOrder || x.index.Global {
+			return false
+		}
+		if x.partitionTableMode {
+			return len(x.byItems) > 0 && byItemsHavePrefix(x.byItems, byItems)
+		}
+		is, ok := x.idxPlans[0].(*plannercore.PhysicalIndexScan)
+		return ok && indexScanOrderedBy(is, x.desc, byItems)
+	}
+	return false
+}
+
+// indexScanOrderedBy checks whether byItems are a prefix of the full length index columns in the scan direction.
+func indexScanOrderedBy(is *plannercore.PhysicalIndexScan, desc bool, byItems []*plannerutil.ByItems) bool {
+	if len(byItems) == 0 || len(byItems) > len(is.IdxCols) {
+		return false
+	}
+	for i, item := range byItems {
+		col, ok := item.Expr.(*expression.Column)
+		if !ok || item.Desc != desc || !col.Equal(nil, is.IdxCols[i]) || is.IdxColLens[i] != types.UnspecifiedLength {
+			return false
+		}
+	}
+	return true
+}
+
+// byItemsHavePrefix checks whether prefix are the leading items of byItems.
+func byItemsHavePrefix(byItems, prefix []*plannerutil.ByItems) bool {
+	if len(prefix) == 0 || len(prefix) > len(byItems) {
+		return false
+	}
+	for i, item := range prefix {
+		col, ok := item.Expr.(*expression.Column)
+		if !ok || item.Desc != byItems[i].Desc || !col.Equal(nil, byItems[i].Expr) {
+			return false
+		}
+	}
+	return true
+}
+
 func (b *executorBuilder) buildTopN(v *plannercore.PhysicalTopN) Executor {
 	childExec := b.build(v.Children()[0])
 	if b.err != nil {
@@ -2183,11 +2690,11 @@ func (b *executorBuilder) buildApply(v *plannercore.PhysicalApply) Executor {
 		outerPlan = v.Children()[0]
 	}
 	v.OuterSchema = plannercore.ExtractCorColumnsBySchema4PhysicalPlan(innerPlan, outerPlan.Schema())
-	leftChild := b.build(v.Children()[0])
+	leftChild := b.buildApplyChild(v.Children()[0], v.InnerChildIdx == 0)
 	if b.err != nil {
 		return nil
 	}
-	rightChild := b.build(v.Children()[1])
+	rightChild := b.buildApplyChild(v.Children()[1], v.InnerChildIdx == 1)
 	if b.err != nil {
 		return nil
 	}
@@ -2197,7 +2704,7 @@ func (b *executorBuilder) buildApply(v *plannercore.PhysicalApply) Executor {
 	otherConditions = append(otherConditions, v.OtherConditions...)
 	defaultValues := v.DefaultValues
 	if defaultValues == nil {
-		defaultValues = make([]types.Datum, v.Children()[v.InnerChildIdx].Schema().Len())
+		defaultValues = b.synthesizeJoinDefaultValues("apply", v.JoinType, v.InnerChildIdx == 0, v.Children()[v.InnerChildIdx].Schema().Len())
 	}
 	outerExec, innerExec := leftChild, rightChild
 	outerFilter, innerFilter := v.LeftConditions, v.RightConditions
@@ -2218,6 +2725,8 @@ func (b *executorBuilder) buildApply(v *plannercore.PhysicalApply) Executor {
 		outerSchema:  v.OuterSchema,
 		ctx:          b.ctx,
 		canUseCache:  v.CanUseCache,
+		// The apply cache evicts the least recently used entries beyond the memory quota.
+		cacheCapacity: b.ctx.GetSessionVars().MemQuotaApplyCache,
 	}
 	executor_metrics.ExecutorCounterNestedLoopApplyExec.Inc()
 
@@ -2234,7 +2743,7 @@ func (b *executorBuilder) buildApply(v *plannercore.PhysicalApply) Executor {
 				return serialExec
 			}
 			corCol := plannercore.ExtractCorColumnsBySchema4PhysicalPlan(clonedInnerPlan, outerPlan.Schema())
-			clonedInnerExec := b.build(clonedInnerPlan)
+			clonedInnerExec := b.buildApplyChild(clonedInnerPlan, true)
 			if b.err != nil {
 				b.err = nil
 				return serialExec
@@ -2249,21 +2758,33 @@ func (b *executorBuilder) buildApply(v *plannercore.PhysicalApply) Executor {
 		allExecs := append([]Executor{outerExec}, innerExecs...)
 
 		return &ParallelNestedLoopApplyExec{
-			baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), allExecs...),
-			innerExecs:   innerExecs,
-			outerExec:    outerExec,
-			outerFilter:  outerFilter,
-			innerFilter:  innerFilters,
-			outer:        v.JoinType != plannercore.InnerJoin,
-			joiners:      joiners,
-			corCols:      corCols,
-			concurrency:  v.Concurrency,
-			useCache:     v.CanUseCache,
+			baseExecutor:  newBaseExecutor(b.ctx, v.Schema(), v.ID(), allExecs...),
+			innerExecs:    innerExecs,
+			outerExec:     outerExec,
+			outerFilter:   outerFilter,
+			innerFilter:   innerFilters,
+			outer:         v.JoinType != plannercore.InnerJoin,
+			joiners:       joiners,
+			corCols:       corCols,
+			concurrency:   v.Concurrency,
+			useCache:      v.CanUseCache,
+			cacheCapacity: b.ctx.GetSessionVars().MemQuotaApplyCache,
 		}
 	}
 	return serialExec
 }
 
+// buildApplyChild builds the child of apply, the inner child is re-executed for every outer row.
+func (b *executorBuilder) buildApplyChild(p plannercore.PhysicalPlan, isInner bool) Executor {
+	if isInner && !b.inApplyInner {
+		b.inApplyInner = true
+		defer func() {
+			b.inApplyInner = false
+		}()
+	}
+	return b.build(p)
+}
+
 func (b *executorBuilder) buildMaxOneRow(v *plannercore.PhysicalMaxOneRow) Executor {
 	childExec := b.build(v.Children()[0])
 	if b.err != nil {
@@ -2379,7 +2900,11 @@ func (b *executorBuilder) buildUpdate(v *plannercore.Update) Executor {
 	if b.err != nil {
 		return nil
 	}
-	base := newBaseExecutor(b.ctx, v.Schema(), v.ID(), selExec)
+	schema := v.Schema()
+	if v.Returning != nil {
+		schema = v.Returning.Schema
+	}
+	base := newBaseExecutor(b.ctx, schema, v.ID(), selExec)
 	base.initCap = chunk.ZeroCapacity
 	var assignFlag []int
 	assignFlag, b.err = getAssignFlag(b.ctx, v, selExec.Schema().Len())
@@ -2391,6 +2916,9 @@ func (b *executorBuilder) buildUpdate(v *plannercore.Update) Executor {
 	if b.err != nil {
 		return nil
 	}
+	// Read the variable by its name rather than the field of the session variables, so the SET_VAR hint of
+	// the statement takes effect too.
+	deferUniqueCheck, _ := b.ctx.GetSessionVars().GetSystemVar(variable.TiDBDeferUniqueCheckInUpdate)
 	updateExec := &UpdateExec{
 		baseExecutor:              base,
 		OrderedList:               v.OrderedList,
@@ -2400,6 +2928,14 @@ func (b *executorBuilder) buildUpdate(v *plannercore.Update) Executor {
 		tblID2table:               tblID2table,
 		tblColPosInfos:            v.TblColPosInfos,
 		assignFlag:                assignFlag,
+		deferUniqueCheck:          variable.TiDBOptOn(deferUniqueCheck),
+	}
+	if v.Returning != nil {
+		updateExec.returning = newDMLReturning(v.Returning, b.ctx.GetSessionVars().MaxChunkSize)
+	}
+	// The updates of the foreign key cascades are not collected, they belong to the parent statement.
+	if sessVars := b.ctx.GetSessionVars(); sessVars.CollectUpdateColumnStats && !sessVars.StmtCtx.InHandleForeignKeyTrigger {
+		updateExec.colStats = b.buildUpdateColumnStats(v, tblID2table)
 	}
 	updateExec.fkChecks, b.err = buildTblID2FKCheckExecs(b.ctx, tblID2table, v.FKChecks)
 	if b.err != nil {
@@ -2412,6 +2948,28 @@ func (b *executorBuilder) buildUpdate(v *plannercore.Update) Executor {
 	return updateExec
 }
 
+// buildUpdateColumnStats builds the counters of the columns assigned by the SET clause, the assignments of the
+// generated columns are excluded.
+func (b *executorBuilder) buildUpdateColumnStats(v *plannercore.Update, tblID2table map[int64]table.Table) []updateColumnStat {
+	colStats := make([]updateColumnStat, 0, v.VirtualAssignmentsOffset)
+	for _, assign := range v.OrderedList[:v.VirtualAssignmentsOffset] {
+		tblIdx, found := v.TblColPosInfos.FindTblIdx(assign.Col.Index)
+		if !found || slices.ContainsFunc(colStats, func(s updateColumnStat) bool { return s.colIdx == assign.Col.Index }) {
+			continue
+		}
+		content := v.TblColPosInfos[tblIdx]
+		tbl := tblID2table[content.TblID]
+		stat := updateColumnStat{colIdx: assign.Col.Index}
+		stat.TableName = tbl.Meta().Name.O
+		stat.ColumnName = tbl.Cols()[assign.Col.Index-content.Start].Name.O
+		if dbInfo, ok := b.is.SchemaByTable(tbl.Meta()); ok {
+			stat.DBName = dbInfo.Name.O
+		}
+		colStats = append(colStats, stat)
+	}
+	return colStats
+}
+
 func getAssignFlag(ctx sessionctx.Context, v *plannercore.Update, schemaLen int) ([]int, error) {
 	assignFlag := make([]int, schemaLen)
 	for i := range assignFlag {
@@ -2445,7 +3003,11 @@ func (b *executorBuilder) buildDelete(v *plannercore.Delete) Executor {
 	if b.err != nil {
 		return nil
 	}
-	base := newBaseExecutor(b.ctx, v.Schema(), v.ID(), selExec)
+	schema := v.Schema()
+	if v.Returning != nil {
+		schema = v.Returning.Schema
+	}
+	base := newBaseExecutor(b.ctx, schema, v.ID(), selExec)
 	base.initCap = chunk.ZeroCapacity
 	deleteExec := &DeleteExec{
 		baseExecutor:   base,
@@ -2453,6 +3015,9 @@ func (b *executorBuilder) buildDelete(v *plannercore.Delete) Executor {
 		IsMultiTable:   v.IsMultiTable,
 		tblColPosInfos: v.TblColPosInfos,
 	}
+	if v.Returning != nil {
+		deleteExec.returning = newDMLReturning(v.Returning, b.ctx.GetSessionVars().MaxChunkSize)
+	}
 	deleteExec.fkChecks, b.err = buildTblID2FKCheckExecs(b.ctx, tblID2table, v.FKChecks)
 	if b.err != nil {
 		return nil
@@ -2466,8 +3031,10 @@ func (b *executorBuilder) buildDelete(v *plannercore.Delete) Executor {
 
 func (b *executorBuilder) updateForUpdateTS() error {
 	// GetStmtForUpdateTS will auto update the for update ts if it is necessary
-	_, err := sessiontxn.GetTxnManager(b.ctx).GetStmtForUpdateTS()
-	return err
+	return b.retryTSFetch(func() error {
+		_, err := sessiontxn.GetTxnManager(b.ctx).GetStmtForUpdateTS()
+		return err
+	})
 }
 
 func (b *executorBuilder) buildAnalyzeIndexPushdown(task plannercore.AnalyzeIndexTask, opts map[ast.AnalyzeOptionType]uint64, autoAnalyze string) *analyzeTask {
@@ -2668,6 +3235,33 @@ func (b *executorBuilder) buildAnalyzeSamplingPushdown(task plannercore.AnalyzeC
 		baseCount:               count,
 		baseModifyCnt:           modifyCount,
 	}
+	if task.HistogramOnly {
+		statsTbl, err := statsHandle.TableStatsFromStorage(task.TblInfo, task.TableID.GetStatisticsID(), true, 0)
+		if err != nil {
+			b.err = err
+			return nil
+		}
+		e.oldColTopNs = make(map[int64]*statistics.TopN)
+		e.oldIdxTopNs = make(map[int64]*statistics.TopN)
+		if statsTbl != nil {
+			for id, col := range statsTbl.Columns {
+				if col.TopN != nil {
+					e.oldColTopNs[id] = col.TopN
+				}
+			}
+			for id, idx := range statsTbl.Indices {
+				if idx.TopN != nil {
+					e.oldIdxTopNs[id] = idx.TopN
+				}
+			}
+		}
+	}
+	if target := b.ctx.GetSessionVars().AnalyzeDumpSamples; target != "" {
+		e.sampleDumper, b.err = b.buildAnalyzeSampleDumper(target)
+		if b.err != nil {
+			return nil
+		}
+	}
 	e.analyzePB.ColReq = &tipb.AnalyzeColumnsReq{
 		BucketSize:   int64(opts[ast.AnalyzeOptNumBuckets]),
 		SampleSize:   int64(opts[ast.AnalyzeOptNumSamples]),
@@ -2686,9 +3280,29 @@ func (b *executorBuilder) buildAnalyzeSamplingPushdown(task plannercore.AnalyzeC
 	return &analyzeTask{taskType: colTask, colExec: e, job: job}
 }
 
-// getAdjustedSampleRate calculate the sample rate by the table size. If we cannot get the table size. We use the 0.001 as the default sample rate.
-// From the paper "Random sampling for histogram construction: how much is enough?"'s Corollary 1 to Theorem 5,
-// for a table size n, histogram size k, maximum relative error in bin size f, and error probability gamma,
+// buildAnalyzeSampleDumper builds the dumper which writes the sampled rows into the target table,
+// the target is in the form of `db.table` or `table`.
+func (b *executorBuilder) buildAnalyzeSampleDumper(target string) (*analyzeSampleDumper, error) {
+	dbName, tableName := b.ctx.GetSessionVars().CurrentDB, target
+	if idx := strings.IndexByte(target, '.'); idx >= 0 {
+		dbName, tableName = target[:idx], target[idx+1:]
+	}
+	if _, err := b.is.TableByName(model.NewCIStr(dbName), model.NewCIStr(tableName)); err != nil {
+		return nil, err
+	}
+	// The samples are written by the internal session, so check the privilege of the current user here.
+	if user := b.ctx.GetSessionVars().User; user != nil {
+		checker := privilege.GetPrivilegeManager(b.ctx)
+		if checker != nil && !checker.RequestVerification(b.ctx.GetSessionVars().ActiveRoles, dbName, tableName, "", mysql.InsertPriv) {
+			return nil, exeerrors.ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, tableName)
+		}
+	}
+	return &analyzeSampleDumper{sctx: b.ctx, dbName: dbName, tableName: tableName}, nil
+}
+
+// getAdjustedSampleRate calculate the sample rate by the table size. If we cannot get the table size. We use the 0.001 as the default sample rate.
+// From the paper "Random sampling for histogram construction: how much is enough?"'s Corollary 1 to Theorem 5,
+// for a table size n, histogram size k, maximum relative error in bin size f, and error probability gamma,
 // the minimum random sample size is
 //
 //	r = 4 * k * ln(2*n/gamma) / f^2
@@ -2976,7 +3590,22 @@ func (b *executorBuilder) buildAnalyze(v *plannercore.Analyze) Executor {
 	if b.ctx.GetSessionVars().InRestrictedSQL {
 		autoAnalyze = "auto "
 	}
+	freshChecker, err := b.newAnalyzeFreshChecker()
+	if err != nil {
+		b.err = err
+		return nil
+	}
 	for _, task := range v.ColTasks {
+		if freshChecker != nil && !task.Incremental {
+			fresh, err := freshChecker.isFresh(&task.AnalyzeInfo, task.TblInfo, task.ColsInfo, task.Indexes)
+			if err != nil {
+				b.err = err
+				return nil
+			}
+			if fresh {
+				continue
+			}
+		}
 		if task.Incremental {
 			e.tasks = append(e.tasks, b.buildAnalyzePKIncremental(task, v.Opts))
 		} else {
@@ -2997,6 +3626,16 @@ func (b *executorBuilder) buildAnalyze(v *plannercore.Analyze) Executor {
 		}
 	}
 	for _, task := range v.IdxTasks {
+		if freshChecker != nil && !task.Incremental {
+			fresh, err := freshChecker.isFresh(&task.AnalyzeInfo, task.TblInfo, nil, []*model.IndexInfo{task.IndexInfo})
+			if err != nil {
+				b.err = err
+				return nil
+			}
+			if fresh {
+				continue
+			}
+		}
 		if task.Incremental {
 			e.tasks = append(e.tasks, b.buildAnalyzeIndexIncremental(task, v.Opts))
 		} else {
@@ -3010,9 +3649,97 @@ func (b *executorBuilder) buildAnalyze(v *plannercore.Analyze) Executor {
 			return nil
 		}
 	}
+	if v.DryRun {
+		return &AnalyzeDryRunExec{baseExecutor: e.baseExecutor, tasks: e.tasks}
+	}
+	// The samples of all the column tasks are bounded by a single tracker, rather than each task.
+	samplesMemTracker := memory.NewTracker(memory.LabelForAnalyzeSamples, b.ctx.GetSessionVars().AnalyzeSampleMemQuota)
+	samplesThrottle := &analyzeSamplesThrottle{}
+	samplesMemTracker.SetActionOnExceed(samplesThrottle)
+	samplesMemTracker.AttachTo(b.ctx.GetSessionVars().StmtCtx.MemTracker)
+	for _, task := range e.tasks {
+		if task.colExec != nil {
+			task.colExec.samplesMemTracker = samplesMemTracker
+			task.colExec.samplesThrottle = samplesThrottle
+		}
+	}
 	return e
 }
 
+// analyzeFreshChecker checks whether the stats of the analyze targets are fresh enough to skip analyzing them.
+type analyzeFreshChecker struct {
+	sctx        sessionctx.Context
+	window      time.Duration
+	modifyRatio float64
+}
+
+// newAnalyzeFreshChecker returns nil if the fresh stats are not skipped.
+func (b *executorBuilder) newAnalyzeFreshChecker() (*analyzeFreshChecker, error) {
+	sessVars := b.ctx.GetSessionVars()
+	if sessVars.AnalyzeSkipFreshStatsWindow <= 0 || sessVars.InRestrictedSQL {
+		return nil, nil
+	}
+	ratio, err := sessVars.GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBAutoAnalyzeRatio)
+	if err != nil {
+		return nil, err
+	}
+	modifyRatio, err := strconv.ParseFloat(ratio, 64)
+	if err != nil {
+		modifyRatio = variable.DefAutoAnalyzeRatio
+	}
+	return &analyzeFreshChecker{
+		sctx:        b.ctx,
+		window:      sessVars.AnalyzeSkipFreshStatsWindow,
+		modifyRatio: modifyRatio,
+	}, nil
+}
+
+// isFresh returns true if all the columns and indexes are analyzed within the window and the table is not
+// modified much since then. A note is appended when the target is skipped.
+func (c *analyzeFreshChecker) isFresh(task *plannercore.AnalyzeInfo, tblInfo *model.TableInfo, cols []*model.ColumnInfo, idxes []*model.IndexInfo) (bool, error) {
+	statsHandle := domain.GetDomain(c.sctx).StatsHandle()
+	if statsHandle == nil {
+		return false, nil
+	}
+	physicalID := task.TableID.GetStatisticsID()
+	count, modifyCount, err := statsHandle.StatsMetaCountAndModifyCount(physicalID)
+	if err != nil {
+		return false, err
+	}
+	if modifyCount > 0 && float64(modifyCount) >= float64(count)*c.modifyRatio {
+		return false, nil
+	}
+	statsTbl := statsHandle.GetPartitionStats(tblInfo, physicalID)
+	if statsTbl == nil || statsTbl.Pseudo {
+		return false, nil
+	}
+	now := time.Now()
+	isFreshVersion := func(version uint64) bool {
+		return now.Sub(oracle.GetTimeFromTS(version)) < c.window
+	}
+	for _, col := range cols {
+		colStats, ok := statsTbl.Columns[col.ID]
+		if !ok || !colStats.IsAnalyzed() || !isFreshVersion(colStats.LastUpdateVersion) {
+			return false, nil
+		}
+	}
+	for _, idx := range idxes {
+		idxStats, ok := statsTbl.Indices[idx.ID]
+		if !ok || !idxStats.IsAnalyzed() || !isFreshVersion(idxStats.LastUpdateVersion) {
+			return false, nil
+		}
+	}
+	target := "table " + task.DBName + "." + task.TableName
+	if task.PartitionName != "" {
+		target += "'s partition " + task.PartitionName
+	}
+	if len(cols) == 0 && len(idxes) == 1 {
+		target += "'s index " + idxes[0].Name.O
+	}
+	c.sctx.GetSessionVars().StmtCtx.AppendNote(errors.Errorf("Analyze skips %s because its stats are fresh", target))
+	return true, nil
+}
+
 // markChildrenUsedCols compares each child with the output schema, and mark
 // each column of the child is used by output or not.
 func markChildrenUsedCols(outputSchema *expression.Schema, childSchema ...*expression.Schema) (childrenUsed [][]bool) {
@@ -3061,6 +3788,9 @@ func (b *executorBuilder) newDataReaderBuilder(p plannercore.PhysicalPlan) (*dat
 		return nil, err
 	}
 
+	if b.partitionPruneCache == nil {
+		b.partitionPruneCache = &partitionPruneCache{results: make(map[string]partitionPruneResult)}
+	}
 	builderForDataReader := *b
 	builderForDataReader.forDataReaderBuilder = true
 	builderForDataReader.dataReaderTS = ts
@@ -3133,7 +3863,7 @@ func (b *executorBuilder) buildIndexLookUpJoin(v *plannercore.PhysicalIndexJoin)
 	}
 	defaultValues := v.DefaultValues
 	if defaultValues == nil {
-		defaultValues = make([]types.Datum, len(innerTypes))
+		defaultValues = b.synthesizeJoinDefaultValues("index join", v.JoinType, v.InnerChildIdx == 0, len(innerTypes))
 	}
 	hasPrefixCol := false
 	for _, l := range v.IdxColLens {
@@ -3245,7 +3975,7 @@ func (b *executorBuilder) buildIndexLookUpMergeJoin(v *plannercore.PhysicalIndex
 	}
 	defaultValues := v.DefaultValues
 	if defaultValues == nil {
-		defaultValues = make([]types.Datum, len(innerTypes))
+		defaultValues = b.synthesizeJoinDefaultValues("index merge join", v.JoinType, v.InnerChildIdx == 0, len(innerTypes))
 	}
 	outerKeyCols := make([]int, len(v.OuterJoinKeys))
 	for i := 0; i < len(v.OuterJoinKeys); i++ {
@@ -3331,11 +4061,16 @@ func containsLimit(execs []*tipb.Executor) bool {
 }
 
 func buildNoRangeTableReader(b *executorBuilder, v *plannercore.PhysicalTableReader) (*TableReaderExecutor, error) {
+	return buildNoRangeTableReaderForStore(b, v, v.StoreType)
+}
+
+// buildNoRangeTableReaderForStore builds the table reader which sends the table plans of v to the storeType.
+func buildNoRangeTableReaderForStore(b *executorBuilder, v *plannercore.PhysicalTableReader, storeType kv.StoreType) (*TableReaderExecutor, error) {
 	tablePlans := v.TablePlans
-	if v.StoreType == kv.TiFlash {
+	if storeType == kv.TiFlash {
 		tablePlans = []plannercore.PhysicalPlan{v.GetTablePlan()}
 	}
-	dagReq, err := builder.ConstructDAGReq(b.ctx, tablePlans, v.StoreType)
+	dagReq, err := builder.ConstructDAGReq(b.ctx, tablePlans, storeType)
 	if err != nil {
 		return nil, err
 	}
@@ -3354,6 +4089,12 @@ func buildNoRangeTableReader(b *executorBuilder, v *plannercore.PhysicalTableRea
 		tbl = pt.GetPartition(physicalTableID)
 	}
 	startTS, err := b.getSnapshotTS()
+	failpoint.Inject("mockTiFlashSnapshotTSMismatch", func() {
+		if err == nil && storeType == kv.TiFlash {
+			startTS++
+			err = b.snapshotTSChecker.register(startTS)
+		}
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -3376,10 +4117,26 @@ func buildNoRangeTableReader(b *executorBuilder, v *plannercore.PhysicalTableRea
 		corColInAccess:   b.corColInAccess(v.TablePlans[0]),
 		plans:            v.TablePlans,
 		tablePlan:        v.GetTablePlan(),
-		storeType:        v.StoreType,
+		storeType:        storeType,
 		batchCop:         v.ReadReqType == plannercore.BatchCop,
 	}
-	e.buildVirtualColumnInfo()
+	if storeType == kv.TiKV && b.ctx.GetSessionVars().CopSplitLargeRegions && ts.Table.PKIsHandle {
+		e.splitLargeRegions = true
+		e.handleHist = ts.Hist
+	}
+	// Shrink the chunks for the wide rows to keep the memory of a batch roughly constant.
+	e.chunkSizer = newChunkSizeAdjuster(e.netDataSize, v.GetTablePlan().StatsCount(), e.maxChunkSize)
+	e.initCap = mathutil.Min(e.initCap, e.chunkSizer.size)
+	// For the pure COUNT(*) pushed down, no column value is needed, so skip the virtual column setup.
+	e.countOnly = isCountOnlyDAG(dagReq, v.Schema())
+	failpoint.Inject("assertCountOnlyTableReader", func(val failpoint.Value) {
+		if !b.ctx.GetSessionVars().InRestrictedSQL && e.countOnly != val.(bool) {
+			panic("count only table reader detection fail")
+		}
+	})
+	if !e.countOnly {
+		e.buildVirtualColumnInfo()
+	}
 	if containsLimit(dagReq.Executors) {
 		e.feedback = statistics.NewQueryFeedback(0, nil, 0, ts.Desc)
 	} else {
@@ -3394,7 +4151,7 @@ func buildNoRangeTableReader(b *executorBuilder, v *plannercore.PhysicalTableRea
 		e.feedback.Invalidate()
 	}
 	e.dagPB.CollectRangeCounts = &collect
-	if v.StoreType == kv.TiDB && b.ctx.GetSessionVars().User != nil {
+	if storeType == kv.TiDB && b.ctx.GetSessionVars().User != nil {
 		// User info is used to do privilege check. It is only used in TiDB cluster memory table.
 		e.dagPB.User = &tipb.UserIdentity{
 			UserName: b.ctx.GetSessionVars().User.Username,
@@ -3409,6 +4166,60 @@ func buildNoRangeTableReader(b *executorBuilder, v *plannercore.PhysicalTableRea
 	return e, nil
 }
 
+// buildChildWithUsedCols builds the child of a plan which only uses usedCols of the child schema. If the child is
+// a table reader, the unused columns are not returned by the coprocessor to reduce the response size.
+func (b *executorBuilder) buildChildWithUsedCols(child plannercore.PhysicalPlan, usedCols []*expression.Column) Executor {
+	childExec := b.build(child)
+	if b.err != nil {
+		return nil
+	}
+	reader, ok := childExec.(*TableReaderExecutor)
+	if !ok || reader.countOnly || len(reader.virtualColumnIndex) > 0 || len(reader.byItems) > 0 {
+		return childExec
+	}
+	offsets := tableReaderOutputOffsets(expression.GetUsedList(usedCols, child.Schema()))
+	if len(offsets) < child.Schema().Len() {
+		reader.outputOffsets = offsets
+		reader.dagPB.OutputOffsets = reader.dagPB.OutputOffsets[:0]
+		for _, offset := range offsets {
+			reader.dagPB.OutputOffsets = append(reader.dagPB.OutputOffsets, uint32(offset))
+		}
+	}
+	failpoint.Inject("checkTableReaderOutputOffsets", func(val failpoint.Value) {
+		if !b.ctx.GetSessionVars().InRestrictedSQL && len(reader.dagPB.OutputOffsets) != val.(int) {
+			b.err = errors.Errorf("expect %d output offsets but got %d", val.(int), len(reader.dagPB.OutputOffsets))
+		}
+	})
+	return childExec
+}
+
+// aggUsedCols returns the columns used by the arguments and the group-by items of the aggregation.
+func aggUsedCols(aggFuncs []*aggregation.AggFuncDesc, groupByItems []expression.Expression) []*expression.Column {
+	usedCols := expression.ExtractColumnsFromExpressions(nil, groupByItems, nil)
+	for _, aggFunc := range aggFuncs {
+		usedCols = expression.ExtractColumnsFromExpressions(usedCols, aggFunc.Args, nil)
+		for _, item := range aggFunc.OrderByItems {
+			usedCols = expression.ExtractColumnsFromExpressions(usedCols, []expression.Expression{item.Expr}, nil)
+		}
+	}
+	return usedCols
+}
+
+// tableReaderOutputOffsets returns the offsets of the used columns that the coprocessor should return for the
+// table reader. At least one column is returned to keep the row count even if no column is used.
+func tableReaderOutputOffsets(used []bool) []int {
+	offsets := make([]int, 0, len(used))
+	for i, isUsed := range used {
+		if isUsed {
+			offsets = append(offsets, i)
+		}
+	}
+	if len(offsets) == 0 && len(used) > 0 {
+		offsets = append(offsets, 0)
+	}
+	return offsets
+}
+
 func (b *executorBuilder) buildMPPGather(v *plannercore.PhysicalTableReader) Executor {
 	startTs, err := b.getSnapshotTS()
 	if err != nil {
@@ -3518,6 +4329,15 @@ func (b *executorBuilder) buildTableReader(v *plannercore.PhysicalTableReader) E
 		return &TableDualExec{baseExecutor: *ret.base()}
 	}
 
+	if b.canUseHybridStoreScan(v, ts, partitions) {
+		exec, err := b.buildHybridStoreScan(v, ret, partitions)
+		if err != nil {
+			b.err = err
+			return nil
+		}
+		return exec
+	}
+
 	// Sort the partition is necessary to make the final multiple partition key ranges ordered.
 	slices.SortFunc(partitions, func(i, j table.PhysicalTable) bool {
 		return i.GetPhysicalID() < j.GetPhysicalID()
@@ -3530,6 +4350,84 @@ func (b *executorBuilder) buildTableReader(v *plannercore.PhysicalTableReader) E
 	return ret
 }
 
+// canUseHybridStoreScan checks whether the TiKV table reader over the pruned partitions of a range partitioned
+// table can read its cold partitions from TiFlash, see buildHybridStoreScan.
+func (b *executorBuilder) canUseHybridStoreScan(v *plannercore.PhysicalTableReader, ts *plannercore.PhysicalTableScan, partitions []table.PhysicalTable) bool {
+	sessVars := b.ctx.GetSessionVars()
+	if !sessVars.EnableHybridStoreScan || v.StoreType != kv.TiKV || ts.KeepOrder || len(partitions) < 2 {
+		return false
+	}
+	tblInfo := ts.Table
+	if tblInfo.Partition.Type != model.PartitionTypeRange || tblInfo.TiFlashReplica == nil || !tblInfo.TiFlashReplica.Available {
+		return false
+	}
+	if _, ok := sessVars.GetIsolationReadEngines()[kv.TiFlash]; !ok {
+		return false
+	}
+	// The union scan can't merge the dirty rows into the two readers separately.
+	for _, p := range partitions {
+		if b.ctx.HasDirtyContent(p.GetPhysicalID()) {
+			return false
+		}
+	}
+	// Only the scan and the filters can be split, the filters must be able to run on TiFlash as well.
+	for _, p := range v.TablePlans {
+		switch x := p.(type) {
+		case *plannercore.PhysicalTableScan:
+		case *plannercore.PhysicalSelection:
+			if !expression.CanExprsPushDown(sessVars.StmtCtx, x.Conditions, b.ctx.GetClient(), kv.TiFlash) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return !b.corColInDistPlan(v.TablePlans) && !b.corColInAccess(v.TablePlans[0])
+}
+
+// buildHybridStoreScan splits the pruned partitions into the hot one, which is the latest range partition and is
+// read from TiKV by hot, and the cold ones which are read from TiFlash, then unions the results of the two readers.
+func (b *executorBuilder) buildHybridStoreScan(v *plannercore.PhysicalTableReader, hot *TableReaderExecutor, partitions []table.PhysicalTable) (Executor, error) {
+	cold, err := buildNoRangeTableReaderForStore(b, v, kv.TiFlash)
+	if err != nil {
+		return nil, err
+	}
+	// TiFlash doesn't support the paging protocol.
+	cold.paging = false
+	cold.ranges = hot.ranges
+
+	defIdx := make(map[int64]int, len(partitions))
+	for i, def := range hot.table.Meta().Partition.Definitions {
+		defIdx[def.ID] = i
+	}
+	hotIdx := 0
+	for i, p := range partitions {
+		if defIdx[p.GetPhysicalID()] > defIdx[partitions[hotIdx].GetPhysicalID()] {
+			hotIdx = i
+		}
+	}
+	coldPartitions := make([]table.PhysicalTable, 0, len(partitions)-1)
+	coldPartitions = append(coldPartitions, partitions[:hotIdx]...)
+	coldPartitions = append(coldPartitions, partitions[hotIdx+1:]...)
+	slices.SortFunc(coldPartitions, func(i, j table.PhysicalTable) bool {
+		return i.GetPhysicalID() < j.GetPhysicalID()
+	})
+	hot.kvRangeBuilder = kvRangeBuilderFromRangeAndPartition{
+		sctx:       b.ctx,
+		partitions: []table.PhysicalTable{partitions[hotIdx]},
+	}
+	cold.kvRangeBuilder = kvRangeBuilderFromRangeAndPartition{
+		sctx:       b.ctx,
+		partitions: coldPartitions,
+	}
+	b.ctx.GetSessionVars().StmtCtx.IsTiFlash.Store(true)
+	// The two readers share the runtime stats of the plan, so the union doesn't collect its own.
+	return &UnionExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), 0, hot, cold),
+		concurrency:  2,
+	}, nil
+}
+
 func buildIndexRangeForEachPartition(ctx sessionctx.Context, usedPartitions []table.PhysicalTable, contentPos []int64,
 	lookUpContent []*indexJoinLookUpContent, indexRanges []*ranger.Range, keyOff2IdxOff []int, cwc *plannercore.ColWithCmpFuncManager) (map[int64][]*ranger.Range, error) {
 	contentBucket := make(map[int64][]*indexJoinLookUpContent)
@@ -3595,9 +4493,8 @@ func (builder *dataReaderBuilder) prunePartitionForInnerExecutor(tbl table.Table
 	lookUpContent []*indexJoinLookUpContent) (usedPartition []table.PhysicalTable, canPrune bool, contentPos []int64, err error) {
 	partitionTbl := tbl.(table.PartitionedTable)
 
-	// In index join, this is called by multiple goroutines simultaneously, but partitionPruning is not thread-safe.
-	// Use once.Do to avoid DATA RACE here.
-	// TODO: condition based pruning can be do in advance.
+	// In index join, this is called by multiple goroutines simultaneously, the condition based pruning result is
+	// cached by the statement and shared among them.
 	condPruneResult, err := builder.partitionPruning(partitionTbl, partitionInfo.PruningConds, partitionInfo.PartitionNames, partitionInfo.Columns, partitionInfo.ColumnNames)
 	if err != nil {
 		return nil, false, nil, err
@@ -3691,6 +4588,23 @@ func buildNoRangeIndexReader(b *executorBuilder, v *plannercore.PhysicalIndexRea
 		plans:            v.IndexPlans,
 		outputColumns:    v.OutputColumns,
 	}
+	e.chunkSizer = newChunkSizeAdjuster(e.netDataSize, v.IndexPlans[len(v.IndexPlans)-1].StatsCount(), e.maxChunkSize)
+	e.initCap = mathutil.Min(e.initCap, e.chunkSizer.size)
+	// The virtual columns computed from the index columns are at the end of the schema.
+	if numOutputCols := len(v.OutputColumns); numOutputCols < v.Schema().Len() {
+		e.columns = make([]*model.ColumnInfo, 0, v.Schema().Len())
+		for i, col := range v.Schema().Columns {
+			colInfo := plannercore.FindColumnInfoByID(is.Columns, col.ID)
+			if colInfo == nil {
+				colInfo = plannercore.FindColumnInfoByID(is.Table.Columns, col.ID)
+			}
+			e.columns = append(e.columns, colInfo)
+			if i >= numOutputCols {
+				e.virtualColumnIndex = append(e.virtualColumnIndex, i)
+				e.virtualColumnRetFieldTypes = append(e.virtualColumnRetFieldTypes, col.RetType)
+			}
+		}
+	}
 	if containsLimit(dagReq.Executors) {
 		e.feedback = statistics.NewQueryFeedback(0, nil, 0, is.Desc)
 	} else {
@@ -3700,6 +4614,18 @@ func buildNoRangeIndexReader(b *executorBuilder, v *plannercore.PhysicalIndexRea
 		}
 		e.feedback = statistics.NewQueryFeedback(tblID, is.Hist, int64(is.StatsCount()), is.Desc)
 	}
+	// The selectivity is meaningless if the limit is pushed down.
+	if b.ctx.GetSessionVars().IndexAdvisorFeedback && !containsLimit(dagReq.Executors) && is.Index != nil {
+		e.scanFeedback = &indexScanFeedbackRecorder{
+			feedback:   getIndexScanFeedback(b.ctx),
+			key:        IndexScanKey{DBName: is.DBName.L, TableName: is.Table.Name.L, IndexName: is.Index.Name.L},
+			scanPlanID: is.ID(),
+			estStats: IndexScanStats{
+				EstScannedRows: is.StatsCount(),
+				EstMatchedRows: v.IndexPlans[len(v.IndexPlans)-1].StatsCount(),
+			},
+		}
+	}
 	collect := statistics.CollectFeedback(b.ctx.GetSessionVars().StmtCtx, e.feedback, len(is.Ranges))
 	// Do not collect the feedback when the table is the partition table.
 	if collect && tbl.Meta().Partition != nil {
@@ -3894,6 +4820,7 @@ func buildNoRangeIndexLookUpReader(b *executorBuilder, v *plannercore.PhysicalIn
 		idxPlans:          v.IndexPlans,
 		tblPlans:          v.TablePlans,
 		PushedLimit:       v.PushedLimit,
+		fetchAhead:        b.ctx.GetSessionVars().IndexLookupFetchAhead,
 		idxNetDataSize:    v.GetAvgTableRowSize(),
 		avgRowSize:        v.GetAvgTableRowSize(),
 	}
@@ -4148,11 +5075,25 @@ type dataReaderBuilder struct {
 	*executorBuilder
 
 	selectResultHook // for testing
-	once             struct {
-		sync.Once
-		condPruneResult []table.PhysicalTable
-		err             error
-	}
+
+	// readers are the readers built for the inner plan of index join by plan ID, they are reused by the next calls
+	// of buildExecutorForIndexJoin with only the ranges reset. It's nil if the readers are not reused.
+	readers map[int]Executor
+}
+
+// withReaderReuse returns a copy of the builder which reuses the readers across the calls of
+// buildExecutorForIndexJoin, the copy must not be shared by the concurrent inner workers.
+func (builder *dataReaderBuilder) withReaderReuse() *dataReaderBuilder {
+	b := *builder
+	b.readers = make(map[int]Executor, 1)
+	return &b
+}
+
+// canReuseReader checks whether the reader of tbl can be reused by the next batch of index join. The readers of the
+// partitioned tables are not reused because the partitions to read are pruned for every batch.
+func (builder *dataReaderBuilder) canReuseReader(tbl table.Table) bool {
+	return builder.readers != nil &&
+		(tbl.Meta().GetPartitionInfo() == nil || !builder.ctx.GetSessionVars().StmtCtx.UseDynamicPartitionPrune())
 }
 
 type mockPhysicalIndexReader struct {
@@ -4232,7 +5173,7 @@ func (builder *dataReaderBuilder) buildUnionScanForIndexJoin(ctx context.Context
 func (builder *dataReaderBuilder) buildTableReaderForIndexJoin(ctx context.Context, v *plannercore.PhysicalTableReader,
 	lookUpContents []*indexJoinLookUpContent, indexRanges []*ranger.Range, keyOff2IdxOff []int,
 	cwc *plannercore.ColWithCmpFuncManager, canReorderHandles bool, memTracker *memory.Tracker, interruptSignal *atomic.Value) (Executor, error) {
-	e, err := buildNoRangeTableReader(builder.executorBuilder, v)
+	e, err := builder.buildNoRangeTableReaderForIndexJoin(v)
 	if !canReorderHandles {
 		// `canReorderHandles` is set to false only in IndexMergeJoin. IndexMergeJoin will trigger a dead loop problem
 		// when enabling paging(tidb/issues/35831). But IndexMergeJoin is not visible to the user and is deprecated
@@ -4438,7 +5379,35 @@ func newClosestReadAdjuster(ctx sessionctx.Context, req *kv.Request, netDataSize
 	}
 }
 
+// buildNoRangeTableReaderForIndexJoin builds the table reader of v for a batch of index join, the reader built for
+// the previous batch is reused if possible.
+func (builder *dataReaderBuilder) buildNoRangeTableReaderForIndexJoin(v *plannercore.PhysicalTableReader) (*TableReaderExecutor, error) {
+	if e, ok := builder.readers[v.ID()].(*TableReaderExecutor); ok {
+		// The feedback doesn't match the ranges of the new batch.
+		e.feedback.Invalidate()
+		return e, nil
+	}
+	e, err := buildNoRangeTableReader(builder.executorBuilder, v)
+	if err != nil {
+		return nil, err
+	}
+	if builder.canReuseReader(e.table) {
+		builder.readers[v.ID()] = e
+	}
+	return e, nil
+}
+
 func (builder *dataReaderBuilder) buildTableReaderBase(ctx context.Context, e *TableReaderExecutor, reqBuilderWithRange distsql.RequestBuilder) (*TableReaderExecutor, error) {
+	result, err := builder.buildTableReaderResult(ctx, e, reqBuilderWithRange)
+	if err != nil {
+		return nil, err
+	}
+	e.resultHandler = &tableResultHandler{}
+	e.resultHandler.open(nil, result)
+	return e, nil
+}
+
+func (builder *dataReaderBuilder) buildTableReaderResult(ctx context.Context, e *TableReaderExecutor, reqBuilderWithRange distsql.RequestBuilder) (distsql.SelectResult, error) {
 	startTS, err := builder.getSnapshotTS()
 	if err != nil {
 		return nil, err
@@ -4452,6 +5421,7 @@ func (builder *dataReaderBuilder) buildTableReaderBase(ctx context.Context, e *T
 		SetReadReplicaScope(e.readReplicaScope).
 		SetIsStaleness(e.isStaleness).
 		SetFromSessionVars(e.ctx.GetSessionVars()).
+		SetMaxConcurrency(e.ctx.GetSessionVars().CopMaxConcurrentTasks).
 		SetFromInfoSchema(e.ctx.GetInfoSchema()).
 		SetClosestReplicaReadAdjuster(newClosestReadAdjuster(e.ctx, &reqBuilderWithRange.Request, e.netDataSize)).
 		SetPaging(e.paging).
@@ -4461,21 +5431,39 @@ func (builder *dataReaderBuilder) buildTableReaderBase(ctx context.Context, e *T
 		return nil, err
 	}
 	e.kvRanges = kvReq.KeyRanges.AppendSelfTo(e.kvRanges)
-	e.resultHandler = &tableResultHandler{}
-	result, err := builder.SelectResult(ctx, builder.ctx, kvReq, retTypes(e), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
-	if err != nil {
-		return nil, err
-	}
-	e.resultHandler.open(nil, result)
-	return e, nil
+	return builder.SelectResult(ctx, builder.ctx, kvReq, retTypes(e), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
 }
 
 func (builder *dataReaderBuilder) buildTableReaderFromHandles(ctx context.Context, e *TableReaderExecutor, handles []kv.Handle, canReorderHandles bool) (*TableReaderExecutor, error) {
+	// Track the memory of the handles before sorting them and building the ranges, so a huge set of handles is
+	// caught by the memory quota of the query before it's expanded.
+	e.handlesMemTracker = memory.NewTracker(memory.LabelForTableReaderHandles, -1)
+	e.handlesMemTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	var memUsage int64
+	for _, handle := range handles {
+		memUsage += int64(handle.MemUsage())
+	}
+	e.handlesMemTracker.Consume(memUsage)
 	if canReorderHandles {
 		slices.SortFunc(handles, func(i, j kv.Handle) bool {
 			return i.Compare(j) < 0
 		})
 	}
+	batchSize := e.ctx.GetSessionVars().TableReaderHandleBatch
+	if batchSize <= 0 || len(handles) <= batchSize {
+		return builder.buildTableReaderBase(ctx, e, handlesRequestBuilder(e, handles))
+	}
+	// The handles have been sorted as a whole, so reading the batches one after another keeps the order.
+	result := &handleBatchSelectResult{builder: builder, e: e, handles: handles, batchSize: batchSize}
+	if err := result.nextBatch(ctx); err != nil {
+		return nil, err
+	}
+	e.resultHandler = &tableResultHandler{}
+	e.resultHandler.open(nil, result)
+	return e, nil
+}
+
+func handlesRequestBuilder(e *TableReaderExecutor, handles []kv.Handle) distsql.RequestBuilder {
 	var b distsql.RequestBuilder
 	if len(handles) > 0 {
 		if _, ok := handles[0].(kv.PartitionHandle); ok {
@@ -4486,7 +5474,82 @@ func (builder *dataReaderBuilder) buildTableReaderFromHandles(ctx context.Contex
 	} else {
 		b.SetKeyRanges(nil)
 	}
-	return builder.buildTableReaderBase(ctx, e, b)
+	return b
+}
+
+// handleBatchSelectResult reads the handles of a table reader batch by batch. The request of a batch is built after
+// the previous batch is drained, so only the ranges of one batch are kept in memory.
+type handleBatchSelectResult struct {
+	builder   *dataReaderBuilder
+	e         *TableReaderExecutor
+	handles   []kv.Handle
+	batchSize int
+	// result is the result of the batch being read, it's nil if all the batches are read.
+	result distsql.SelectResult
+}
+
+// nextBatch closes the result of the current batch and sends the request of the next one. The batches are taken
+// from the tail if the reader is in descending order.
+func (r *handleBatchSelectResult) nextBatch(ctx context.Context) error {
+	if r.result != nil {
+		err := r.result.Close()
+		r.result = nil
+		if err != nil {
+			return err
+		}
+	}
+	if len(r.handles) == 0 {
+		return nil
+	}
+	n := mathutil.Min(r.batchSize, len(r.handles))
+	var batch []kv.Handle
+	if r.e.desc {
+		batch, r.handles = r.handles[len(r.handles)-n:], r.handles[:len(r.handles)-n]
+	} else {
+		batch, r.handles = r.handles[:n], r.handles[n:]
+	}
+	r.e.kvRanges = r.e.kvRanges[:0]
+	result, err := r.builder.buildTableReaderResult(ctx, r.e, handlesRequestBuilder(r.e, batch))
+	if err != nil {
+		return err
+	}
+	r.result = result
+	return nil
+}
+
+// NextRaw implements the distsql.SelectResult interface.
+func (r *handleBatchSelectResult) NextRaw(ctx context.Context) ([]byte, error) {
+	for r.result != nil {
+		data, err := r.result.NextRaw(ctx)
+		if err != nil || data != nil {
+			return data, err
+		}
+		if err = r.nextBatch(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// Next implements the distsql.SelectResult interface.
+func (r *handleBatchSelectResult) Next(ctx context.Context, chk *chunk.Chunk) error {
+	chk.Reset()
+	for r.result != nil {
+		if err := r.result.Next(ctx, chk); err != nil || chk.NumRows() > 0 {
+			return err
+		}
+		if err := r.nextBatch(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements the distsql.SelectResult interface.
+func (r *handleBatchSelectResult) Close() error {
+	err := closeAll(r.result)
+	r.result, r.handles = nil, nil
+	return err
 }
 
 func (builder *dataReaderBuilder) buildTableReaderFromKvRanges(ctx context.Context, e *TableReaderExecutor, ranges []kv.KeyRange) (Executor, error) {
@@ -4497,7 +5560,7 @@ func (builder *dataReaderBuilder) buildTableReaderFromKvRanges(ctx context.Conte
 
 func (builder *dataReaderBuilder) buildIndexReaderForIndexJoin(ctx context.Context, v *plannercore.PhysicalIndexReader,
 	lookUpContents []*indexJoinLookUpContent, indexRanges []*ranger.Range, keyOff2IdxOff []int, cwc *plannercore.ColWithCmpFuncManager, memoryTracker *memory.Tracker, interruptSignal *atomic.Value) (Executor, error) {
-	e, err := buildNoRangeIndexReader(builder.executorBuilder, v)
+	e, err := builder.buildNoRangeIndexReaderForIndexJoin(v)
 	if err != nil {
 		return nil, err
 	}
@@ -4565,6 +5628,24 @@ func (builder *dataReaderBuilder) buildIndexReaderForIndexJoin(ctx context.Conte
 	return ret, err
 }
 
+// buildNoRangeIndexReaderForIndexJoin builds the index reader of v for a batch of index join, the reader built for
+// the previous batch is reused if possible.
+func (builder *dataReaderBuilder) buildNoRangeIndexReaderForIndexJoin(v *plannercore.PhysicalIndexReader) (*IndexReaderExecutor, error) {
+	if e, ok := builder.readers[v.ID()].(*IndexReaderExecutor); ok {
+		// The feedback doesn't match the ranges of the new batch.
+		e.feedback.Invalidate()
+		return e, nil
+	}
+	e, err := buildNoRangeIndexReader(builder.executorBuilder, v)
+	if err != nil {
+		return nil, err
+	}
+	if builder.canReuseReader(e.table) {
+		builder.readers[v.ID()] = e
+	}
+	return e, nil
+}
+
 func (builder *dataReaderBuilder) buildIndexLookUpReaderForIndexJoin(ctx context.Context, v *plannercore.PhysicalIndexLookUpReader,
 	lookUpContents []*indexJoinLookUpContent, indexRanges []*ranger.Range, keyOff2IdxOff []int, cwc *plannercore.ColWithCmpFuncManager, memTracker *memory.Tracker, interruptSignal *atomic.Value) (Executor, error) {
 	if builder.Ti != nil {
@@ -4830,6 +5911,7 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 			baseExecutor:   base,
 			groupChecker:   newVecGroupChecker(b.ctx, groupByItems),
 			numWindowFuncs: len(v.WindowFuncDescs),
+			excluder:       newFrameExcluder(b.ctx, v.Frame, orderByCols),
 		}
 
 		exec.windowFuncs = windowFuncs
@@ -4858,32 +5940,49 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 		}
 		return exec
 	}
-	var processor windowProcessor
-	if v.Frame == nil {
-		processor = &aggWindowProcessor{
-			windowFuncs:    windowFuncs,
-			partialResults: partialResults,
-		}
-	} else if v.Frame.Type == ast.Rows {
-		processor = &rowFrameWindowProcessor{
-			windowFuncs:    windowFuncs,
-			partialResults: partialResults,
-			start:          v.Frame.Start,
-			end:            v.Frame.End,
+	newProcessor := func(windowFuncs []aggfuncs.AggFunc, partialResults []aggfuncs.PartialResult) windowProcessor {
+		if v.Frame == nil {
+			return &aggWindowProcessor{
+				windowFuncs:    windowFuncs,
+				partialResults: partialResults,
+			}
+		} else if v.Frame.Type == ast.Rows {
+			return &rowFrameWindowProcessor{
+				windowFuncs:    windowFuncs,
+				partialResults: partialResults,
+				start:          v.Frame.Start,
+				end:            v.Frame.End,
+				excluder:       newFrameExcluder(b.ctx, v.Frame, orderByCols),
+			}
 		}
-	} else {
 		cmpResult := int64(-1)
 		if len(v.OrderBy) > 0 && v.OrderBy[0].Desc {
 			cmpResult = 1
 		}
-		processor = &rangeFrameWindowProcessor{
+		return &rangeFrameWindowProcessor{
 			windowFuncs:       windowFuncs,
 			partialResults:    partialResults,
 			start:             v.Frame.Start,
 			end:               v.Frame.End,
 			orderByCols:       orderByCols,
 			expectedCmpResult: cmpResult,
+			excluder:          newFrameExcluder(b.ctx, v.Frame, orderByCols),
+		}
+	}
+	var processor windowProcessor
+	if concurrency := b.ctx.GetSessionVars().WindowConcurrency(); b.ctx.GetSessionVars().EnableParallelWindowExec &&
+		concurrency > 1 && len(windowFuncs) > 1 {
+		// Each window function has its own processor, so they can be evaluated in parallel.
+		processors := make([]windowProcessor, 0, len(windowFuncs))
+		for i := range windowFuncs {
+			processors = append(processors, newProcessor(windowFuncs[i:i+1], partialResults[i:i+1]))
+		}
+		processor = &parallelWindowProcessor{
+			processors:  processors,
+			concurrency: concurrency,
 		}
+	} else {
+		processor = newProcessor(windowFuncs, partialResults)
 	}
 	return &WindowExec{baseExecutor: base,
 		processor:      processor,
@@ -4936,37 +6035,45 @@ func (b *executorBuilder) buildShuffle(v *plannercore.PhysicalShuffle) *ShuffleE
 		stub.SetSchema(dataSource.Schema())
 		stubs = append(stubs, stub)
 	}
+	for j := range v.DataSources {
+		v.Tails[j].SetChildren(stubs[j])
+	}
+	// The subtrees of the workers are independent, so they are built in parallel by the builders cloned
+	// from b, every one of which maps the stubs to the receivers of its own worker.
 	shuffle.workers = make([]*shuffleWorker, shuffle.concurrency)
+	var eg errgroup.Group
+	eg.SetLimit(mathutil.Min(shuffle.concurrency, b.ctx.GetSessionVars().ExecutorConcurrency))
 	for i := range shuffle.workers {
 		receivers := make([]*shuffleReceiver, len(v.DataSources))
+		workerBuilder := *b
+		workerBuilder.shuffleReceivers = make(map[*plannercore.PhysicalShuffleReceiverStub]*shuffleReceiver, len(stubs))
 		for j, dataSource := range v.DataSources {
 			receivers[j] = &shuffleReceiver{
 				baseExecutor: newBaseExecutor(b.ctx, dataSource.Schema(), stubs[j].ID()),
 			}
+			workerBuilder.shuffleReceivers[stubs[j]] = receivers[j]
 		}
-
 		w := &shuffleWorker{
 			receivers: receivers,
 		}
-
-		for j := range v.DataSources {
-			stub := stubs[j]
-			stub.Receiver = (unsafe.Pointer)(receivers[j])
-			v.Tails[j].SetChildren(stub)
-		}
-
-		w.childExec = b.build(head)
-		if b.err != nil {
-			return nil
-		}
-
 		shuffle.workers[i] = w
+		eg.Go(func() error {
+			w.childExec = workerBuilder.build(head)
+			return workerBuilder.err
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		b.err = err
+		return nil
 	}
 
 	return shuffle
 }
 
 func (b *executorBuilder) buildShuffleReceiverStub(v *plannercore.PhysicalShuffleReceiverStub) *shuffleReceiver {
+	if receiver, ok := b.shuffleReceivers[v]; ok {
+		return receiver
+	}
 	return (*shuffleReceiver)(v.Receiver)
 }
 
@@ -5026,18 +6133,46 @@ func NewRowDecoder(ctx sessionctx.Context, schema *expression.Schema, tbl *model
 			pkCols = []int64{-1}
 		}
 	}
+	// defDatums caches the default values by the column positions, so they are computed once for all the rows
+	// missing the columns.
+	defDatums := make([]*types.Datum, len(reqCols))
 	defVal := func(i int, chk *chunk.Chunk) error {
+		if d := defDatums[i]; d != nil {
+			chk.AppendDatum(i, d)
+			return nil
+		}
 		ci := getColInfoByID(tbl, reqCols[i].ID)
 		d, err := table.GetColOriginDefaultValue(ctx, ci)
 		if err != nil {
 			return err
 		}
+		if isOriginDefaultValueCacheable(ci) {
+			defDatums[i] = &d
+		}
 		chk.AppendDatum(i, &d)
 		return nil
 	}
 	return rowcodec.NewChunkDecoder(reqCols, pkCols, defVal, ctx.GetSessionVars().Location())
 }
 
+// isOriginDefaultValueCacheable checks whether the origin default value of the column is the same every time it's
+// computed by table.GetColOriginDefaultValue.
+func isOriginDefaultValueCacheable(col *model.ColumnInfo) bool {
+	defVal := col.GetOriginDefaultValue()
+	if defVal == nil {
+		// The columns without default value may report a warning or an error according to the SQL mode.
+		return !mysql.HasNotNullFlag(col.GetFlag()) && !mysql.HasNoDefaultValueFlag(col.GetFlag())
+	}
+	switch col.GetType() {
+	case mysql.TypeTimestamp, mysql.TypeDate, mysql.TypeDatetime:
+		// CURRENT_TIMESTAMP and CURRENT_DATE are evaluated by the statement.
+		if str, ok := defVal.(string); ok && (strings.EqualFold(str, ast.CurrentTimestamp) || strings.EqualFold(str, ast.CurrentDate)) {
+			return false
+		}
+	}
+	return true
+}
+
 func (b *executorBuilder) buildBatchPointGet(plan *plannercore.BatchPointGetPlan) Executor {
 	var err error
 	if err = b.validCanReadTemporaryOrCacheTable(plan.TblInfo); err != nil {
@@ -5054,20 +6189,22 @@ func (b *executorBuilder) buildBatchPointGet(plan *plannercore.BatchPointGetPlan
 
 	decoder := NewRowDecoder(b.ctx, plan.Schema(), plan.TblInfo)
 	e := &BatchPointGetExec{
-		baseExecutor: newBaseExecutor(b.ctx, plan.Schema(), plan.ID()),
-		tblInfo:      plan.TblInfo,
-		idxInfo:      plan.IndexInfo,
-		rowDecoder:   decoder,
-		keepOrder:    plan.KeepOrder,
-		desc:         plan.Desc,
-		lock:         plan.Lock,
-		waitTime:     plan.LockWaitTime,
-		partExpr:     plan.PartitionExpr,
-		partPos:      plan.PartitionColPos,
-		planPhysIDs:  plan.PartitionIDs,
-		singlePart:   plan.SinglePart,
-		partTblID:    plan.PartTblID,
-		columns:      plan.Columns,
+		baseExecutor:   newBaseExecutor(b.ctx, plan.Schema(), plan.ID()),
+		tblInfo:        plan.TblInfo,
+		idxInfo:        plan.IndexInfo,
+		idxPrefix:      plan.IndexPrefix,
+		rowDecoder:     decoder,
+		verifyChecksum: b.ctx.GetSessionVars().VerifyRowChecksumOnRead,
+		keepOrder:      plan.KeepOrder,
+		desc:           plan.Desc,
+		lock:           plan.Lock,
+		waitTime:       plan.LockWaitTime,
+		partExpr:       plan.PartitionExpr,
+		partPos:        plan.PartitionColPos,
+		planPhysIDs:    plan.PartitionIDs,
+		singlePart:     plan.SinglePart,
+		partTblID:      plan.PartTblID,
+		columns:        plan.Columns,
 	}
 
 	e.snapshot, err = b.getSnapshot()
@@ -5121,6 +6258,7 @@ func (b *executorBuilder) buildBatchPointGet(plan *plannercore.BatchPointGetPlan
 	}
 
 	var capacity int
+	maxSize := b.ctx.GetSessionVars().BatchPointGetMaxSize
 	if plan.IndexInfo != nil && !isCommonHandleRead(plan.TblInfo, plan.IndexInfo) {
 		e.idxVals = plan.IndexValues
 		capacity = len(e.idxVals)
@@ -5164,12 +6302,46 @@ func (b *executorBuilder) buildBatchPointGet(plan *plannercore.BatchPointGetPlan
 		e.handles = handles
 		capacity = len(e.handles)
 	}
+	if keyCount := capacity; keyCount > maxSize {
+		// The unique index values are deduplicated by the executor, so count the distinct keys of them.
+		if e.idxVals != nil {
+			keyCount, b.err = countDistinctUniqueIndexKeys(e.ctx, plan.TblInfo, plan.IndexInfo, e.idxVals)
+			if b.err != nil {
+				return nil
+			}
+		}
+		if keyCount > maxSize {
+			b.err = exeerrors.ErrBatchPointGetTooLarge.GenWithStackByArgs(keyCount, maxSize)
+			return nil
+		}
+	}
 	e.base().initCap = capacity
 	e.base().maxChunkSize = capacity
 	e.buildVirtualColumnInfo()
 	return e
 }
 
+// countDistinctUniqueIndexKeys counts the distinct keys of the unique index values in the same way as the
+// BatchPointGetExec deduplicates them.
+func countDistinctUniqueIndexKeys(sctx sessionctx.Context, tblInfo *model.TableInfo, idxInfo *model.IndexInfo, idxVals [][]types.Datum) (int, error) {
+	dedup := make(map[string]struct{}, len(idxVals))
+	for _, vals := range idxVals {
+		if datumsContainNull(vals) {
+			continue
+		}
+		// The equal values are always in the same partition, so the table ID is enough to tell them apart.
+		idxKey, err := EncodeUniqueIndexKey(sctx, tblInfo, idxInfo, vals, tblInfo.ID)
+		if err != nil && !kv.ErrNotExist.Equal(err) {
+			return 0, err
+		}
+		if idxKey == nil {
+			continue
+		}
+		dedup[string(idxKey)] = struct{}{}
+	}
+	return len(dedup), nil
+}
+
 func newReplicaReadAdjuster(ctx sessionctx.Context, avgRowSize float64) txnkv.ReplicaReadAdjuster {
 	return func(count int) (tikv.StoreSelectorOption, clientkv.ReplicaReadType) {
 		if int64(avgRowSize*float64(count)) >= ctx.GetSessionVars().ReplicaClosestReadThreshold {
@@ -5211,14 +6383,52 @@ func (b *executorBuilder) buildAdminResetTelemetryID(v *plannercore.AdminResetTe
 	return &AdminResetTelemetryIDExec{baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID())}
 }
 
+// TestPartitionPruningHookKey is used as the key to store a 'func()' in the context, which is called
+// every time the dataReaderBuilder actually prunes the partitions.
+var TestPartitionPruningHookKey stringutil.StringerStr = "TestPartitionPruningHookKey"
+
+// partitionPruneCache caches the condition based partition pruning results of a statement. The pruning
+// conditions don't depend on the outer rows of index join, so the result can be shared by the
+// dataReaderBuilders which run concurrently.
+type partitionPruneCache struct {
+	mu      sync.Mutex
+	results map[string]partitionPruneResult
+}
+
+type partitionPruneResult struct {
+	partitions []table.PhysicalTable
+	err        error
+}
+
 func (builder *dataReaderBuilder) partitionPruning(tbl table.PartitionedTable, conds []expression.Expression, partitionNames []model.CIStr,
 	columns []*expression.Column, columnNames types.NameSlice) ([]table.PhysicalTable, error) {
-	builder.once.Do(func() {
-		condPruneResult, err := partitionPruning(builder.executorBuilder.ctx, tbl, conds, partitionNames, columns, columnNames)
-		builder.once.condPruneResult = condPruneResult
-		builder.once.err = err
-	})
-	return builder.once.condPruneResult, builder.once.err
+	sctx := builder.executorBuilder.ctx
+	cache := builder.partitionPruneCache
+	// Both hashing the conditions and partitionPruning are not thread-safe, so they are done under the lock.
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	key := partitionPruneCacheKey(sctx.GetSessionVars().StmtCtx, tbl.Meta().ID, conds, partitionNames)
+	if res, ok := cache.results[key]; ok {
+		return res.partitions, res.err
+	}
+	if hook, ok := sctx.Value(TestPartitionPruningHookKey).(func()); ok {
+		hook()
+	}
+	partitions, err := partitionPruning(sctx, tbl, conds, partitionNames, columns, columnNames)
+	cache.results[key] = partitionPruneResult{partitions: partitions, err: err}
+	return partitions, err
+}
+
+func partitionPruneCacheKey(sc *stmtctx.StatementContext, tblID int64, conds []expression.Expression, partitionNames []model.CIStr) string {
+	key := codec.EncodeInt(nil, tblID)
+	key = codec.EncodeInt(key, int64(len(partitionNames)))
+	for _, name := range partitionNames {
+		key = codec.EncodeBytes(key, hack.Slice(name.L))
+	}
+	for _, cond := range conds {
+		key = codec.EncodeBytes(key, cond.HashCode(sc))
+	}
+	return string(key)
 }
 
 func partitionPruning(ctx sessionctx.Context, tbl table.PartitionedTable, conds []expression.Expression, partitionNames []model.CIStr,
@@ -5474,7 +6684,7 @@ func (b *executorBuilder) getCacheTable(tblInfo *model.TableInfo, startTS uint64
 }
 
 func (b *executorBuilder) buildCompactTable(v *plannercore.CompactTable) Executor {
-	if v.ReplicaKind != ast.CompactReplicaKindTiFlash && v.ReplicaKind != ast.CompactReplicaKindAll {
+	if v.ReplicaKind != ast.CompactReplicaKindTiFlash && v.ReplicaKind != ast.CompactReplicaKindAll && v.ReplicaKind != ast.CompactReplicaKindTiKV {
 		b.err = errors.Errorf("compact %v replica is not supported", strings.ToLower(string(v.ReplicaKind)))
 		return nil
 	}
@@ -5482,7 +6692,11 @@ func (b *executorBuilder) buildCompactTable(v *plannercore.CompactTable) Executo
 	store := b.ctx.GetStore()
 	tikvStore, ok := store.(tikv.Storage)
 	if !ok {
-		b.err = errors.New("compact tiflash replica can only run with tikv compatible storage")
+		if v.ReplicaKind == ast.CompactReplicaKindTiKV {
+			b.err = errors.New("compact tikv replica can only run with tikv compatible storage")
+		} else {
+			b.err = errors.New("compact tiflash replica can only run with tikv compatible storage")
+		}
 		return nil
 	}
 
@@ -5512,10 +6726,31 @@ func (b *executorBuilder) buildCompactTable(v *plannercore.CompactTable) Executo
 		b.Ti.PartitionTelemetry.UseCompactTablePartition = true
 	}
 
-	return &CompactTableTiFlashExec{
+	var tikvExec *CompactTableTiKVExec
+	if v.ReplicaKind != ast.CompactReplicaKindTiFlash {
+		tikvExec = &CompactTableTiKVExec{
+			baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+			tableInfo:    v.TableInfo,
+			partitionIDs: partitionIDs,
+			tikvStore:    tikvStore,
+			codec:        store.GetCodec(),
+			client:       newTiKVCompactClient(),
+		}
+		if v.ReplicaKind == ast.CompactReplicaKindTiKV {
+			return tikvExec
+		}
+	}
+
+	tiflashExec := &CompactTableTiFlashExec{
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
 		tableInfo:    v.TableInfo,
 		partitionIDs: partitionIDs,
 		tikvStore:    tikvStore,
 	}
+	if v.ReplicaKind == ast.CompactReplicaKindTiFlash {
+		return tiflashExec
+	}
+	return &CompactTableAllExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), tikvExec, tiflashExec),
+	}
 }
@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -277,6 +279,49 @@ select 10;`
 	}
 }
 
+func TestClusterTableSlowQueryTopN(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	srv := createRPCServer(t, dom)
+	defer srv.Stop()
+
+	var logData strings.Builder
+	for i := 0; i < 50; i++ {
+		logData.WriteString(fmt.Sprintf("# Time: 2020-02-15T18:00:%02d.000000+08:00\n", i))
+		logData.WriteString(fmt.Sprintf("# Query_time: %v\n", float64(i*37%50)/10))
+		logData.WriteString(fmt.Sprintf("select %d;\n", i))
+	}
+	fileName := filepath.Join(t.TempDir(), "tidb-slow-query-topn.log")
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Log.SlowQueryFile = fileName
+	})
+	prepareLogs(t, []string{logData.String()}, []string{fileName})
+	defer func() {
+		removeFiles(t, []string{fileName})
+	}()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use information_schema")
+
+	fullScan := tk.MustQuery("select query_time, query from cluster_slow_query order by query_time desc").Rows()
+	require.Len(t, fullScan, 50)
+	for _, limit := range []int{1, 5, 50, 100} {
+		expected := fullScan
+		if limit < len(fullScan) {
+			expected = fullScan[:limit]
+		}
+		sql := fmt.Sprintf("select query_time, query from cluster_slow_query order by query_time desc limit %d", limit)
+		// The TopN is pushed down to the slow log retriever of each instance.
+		topN := fmt.Sprintf("information_schema.cluster_slow_query.query_time:desc, offset:0, count:%d", limit)
+		tk.MustQuery("explain format='brief' "+sql).CheckAt([]int{0, 2, 4}, [][]interface{}{
+			{"TopN", "root", topN},
+			{"└─TableReader", "root", "data:TopN"},
+			{"  └─TopN", "cop[tidb]", topN},
+			{"    └─TableFullScan", "cop[tidb]", "keep order:false, stats:pseudo"},
+		})
+		tk.MustQuery(sql).Check(expected)
+	}
+}
+
 func TestSQLDigestTextRetriever(t *testing.T) {
 	store, dom := testkit.CreateMockStoreAndDomain(t)
 	srv := createRPCServer(t, dom)
@@ -22,10 +22,14 @@ import (
 	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/debugpb"
 	"github.com/pingcap/kvproto/pkg/kvrpcpb"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/store/mockstore/unistore"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/pingcap/tidb/util/syncutil"
 	"github.com/stretchr/testify/require"
@@ -71,6 +75,7 @@ func TestCompactUnknownTable(t *testing.T) {
 }
 
 func TestCompactTableNoTiFlashReplica(t *testing.T) {
+	defer executor.SetTiKVCompactClientForTest(&compactTiKVRecorder{})()
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
 
@@ -191,6 +196,7 @@ func TestCompactTableInternalError(t *testing.T) {
 
 // TestCompactTableNoRemaining: Returns NoRemaining for request #1.
 func TestCompactTableNoRemaining(t *testing.T) {
+	defer executor.SetTiKVCompactClientForTest(&compactTiKVRecorder{})()
 	mocker := newCompactRequestMocker(t)
 	store, do := testkit.CreateMockStoreAndDomain(t, withMockTiFlash(1), mocker.AsOpt())
 	tk := testkit.NewTestKit(t, store)
@@ -900,6 +906,58 @@ func TestCompactTableWithTiFlashDownAndRestore(t *testing.T) {
 	tk.MustQuery(`show warnings;`).Check(testkit.Rows())
 }
 
+func TestCompactTableTiKV(t *testing.T) {
+	recorder := &compactTiKVRecorder{}
+	defer executor.SetTiKVCompactClientForTest(recorder)()
+	store, dom := testkit.CreateMockStoreAndDomain(t, withMockTiFlash(1))
+	tk := testkit.NewTestKit(t, store)
+
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int)")
+	tk.MustExec("create table tp(a int) partition by hash(a) partitions 3")
+	is := dom.InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tp, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("tp"))
+	require.NoError(t, err)
+	defs := tp.Meta().Partition.Definitions
+	// The requests are only sent to the TiKV store, the peers in TiFlash are skipped.
+	expectedRequests := func(physicalIDs ...int64) []string {
+		var reqs []string
+		for _, id := range physicalIDs {
+			start, end := store.GetCodec().EncodeRegionRange(tablecodec.EncodeTablePrefix(id), tablecodec.EncodeTablePrefix(id+1))
+			for _, cf := range []string{"default", "write"} {
+				reqs = append(reqs, fmt.Sprintf("store1 %s z%x z%x", cf, start, end))
+			}
+		}
+		return reqs
+	}
+
+	tk.MustExec("alter table t compact tikv replica")
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	require.Equal(t, expectedRequests(tbl.Meta().ID), recorder.take())
+
+	tk.MustExec("alter table tp compact tikv replica")
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	require.Equal(t, expectedRequests(defs[0].ID, defs[1].ID, defs[2].ID), recorder.take())
+
+	tk.MustExec("alter table tp compact partition p2,p0 tikv replica")
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	require.Equal(t, expectedRequests(defs[2].ID, defs[0].ID), recorder.take())
+
+	// Compacting all the replicas compacts the TiKV replicas too.
+	tk.MustExec("alter table t compact")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 compact skipped: no tiflash replica in the table"))
+	require.Equal(t, expectedRequests(tbl.Meta().ID), recorder.take())
+	require.Equal(t, 4, recorder.closed)
+
+	// The failure is turned into a warning, and the remaining partitions in the store are skipped.
+	recorder.err = errors.New("mock compact error")
+	tk.MustExec("alter table tp compact tikv replica")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 compact on store store1 failed: mock compact error"))
+	require.Equal(t, expectedRequests(defs[0].ID)[:1], recorder.take())
+}
+
 // Code below are helper utilities for the test cases.
 
 type compactClientHandler struct {
@@ -973,3 +1031,32 @@ func (client *compactRequestMocker) AsOpt() mockstore.MockTiKVStoreOption {
 		return client
 	})
 }
+
+type compactTiKVRecorder struct {
+	mu     syncutil.Mutex
+	reqs   []string
+	err    error
+	closed int
+}
+
+func (r *compactTiKVRecorder) Compact(_ context.Context, storeAddr string, req *debugpb.CompactRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reqs = append(r.reqs, fmt.Sprintf("%s %s z%x z%x", storeAddr, req.Cf, req.FromKey[1:], req.ToKey[1:]))
+	return r.err
+}
+
+func (r *compactTiKVRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed++
+	return nil
+}
+
+func (r *compactTiKVRecorder) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reqs := r.reqs
+	r.reqs = nil
+	return reqs
+}
@@ -0,0 +1,316 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/debugpb"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/syncutil"
+	"github.com/tikv/client-go/v2/tikv"
+	"github.com/tikv/client-go/v2/tikvrpc"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var (
+	_ Executor = &CompactTableTiKVExec{}
+	_ Executor = &CompactTableAllExec{}
+)
+
+// compactTiKVColumnFamilies are the column families holding the data of a table in TiKV.
+var compactTiKVColumnFamilies = []string{"default", "write"}
+
+// tikvDataKeyPrefix is the prefix of the data keys in the RocksDB of TiKV.
+const tikvDataKeyPrefix = 'z'
+
+// TiKVCompactClient sends the requests compacting a key range of the RocksDB to the TiKV stores.
+type TiKVCompactClient interface {
+	Compact(ctx context.Context, storeAddr string, req *debugpb.CompactRequest) error
+	// Close releases the connections to the TiKV stores.
+	Close() error
+}
+
+// newTiKVCompactClient creates the client used by a CompactTableTiKVExec.
+var newTiKVCompactClient = func() TiKVCompactClient {
+	return &grpcTiKVCompactClient{conns: make(map[string]*grpc.ClientConn)}
+}
+
+// SetTiKVCompactClientForTest replaces the client sending the TiKV compaction requests, and returns a function to
+// restore it.
+func SetTiKVCompactClientForTest(client TiKVCompactClient) func() {
+	origin := newTiKVCompactClient
+	newTiKVCompactClient = func() TiKVCompactClient {
+		return client
+	}
+	return func() {
+		newTiKVCompactClient = origin
+	}
+}
+
+// grpcTiKVCompactClient sends the compaction requests through the debug service of TiKV. The connection to a store
+// is reused by all the requests sent to it.
+type grpcTiKVCompactClient struct {
+	mu    syncutil.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+func (c *grpcTiKVCompactClient) getConn(storeAddr string) (*grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if conn, ok := c.conns[storeAddr]; ok {
+		return conn, nil
+	}
+	opt := grpc.WithTransportCredentials(insecure.NewCredentials())
+	security := config.GetGlobalConfig().Security
+	if len(security.ClusterSSLCA) != 0 {
+		clusterSecurity := security.ClusterSecurity()
+		tlsConfig, err := clusterSecurity.ToTLSConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		opt = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+	conn, err := grpc.Dial(storeAddr, opt)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c.conns[storeAddr] = conn
+	return conn, nil
+}
+
+// Compact implements the TiKVCompactClient interface.
+func (c *grpcTiKVCompactClient) Compact(ctx context.Context, storeAddr string, req *debugpb.CompactRequest) error {
+	conn, err := c.getConn(storeAddr)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, compactRequestTimeout)
+	defer cancel()
+	_, err = debugpb.NewDebugClient(conn).Compact(ctx, req)
+	return errors.Trace(err)
+}
+
+// Close implements the TiKVCompactClient interface.
+func (c *grpcTiKVCompactClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var firstErr error
+	for addr, conn := range c.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = errors.Trace(err)
+		}
+		delete(c.conns, addr)
+	}
+	return firstErr
+}
+
+// CompactTableTiKVExec represents an executor for "ALTER TABLE [NAME] COMPACT TIKV REPLICA" statement.
+type CompactTableTiKVExec struct {
+	baseExecutor
+
+	tableInfo    *model.TableInfo
+	partitionIDs []int64
+	done         bool
+
+	tikvStore tikv.Storage
+	codec     tikv.Codec
+	client    TiKVCompactClient
+}
+
+// Close implements the Executor Close interface.
+func (e *CompactTableTiKVExec) Close() error {
+	terror.Log(e.client.Close())
+	return e.baseExecutor.Close()
+}
+
+// Next implements the Executor Next interface.
+func (e *CompactTableTiKVExec) Next(ctx context.Context, chk *chunk.Chunk) error {
+	chk.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+	return e.doCompact(ctx)
+}
+
+func (e *CompactTableTiKVExec) doCompact(execCtx context.Context) error {
+	// We will do a TiKV compact in this way:
+	// For each TiKV store holding the regions of the table (in parallel):
+	//     For each physical table having regions in the store (in series):
+	//         Compact the key range of the physical table in each column family.
+	tasks, err := e.buildStoreTasks(execCtx)
+	if err != nil {
+		return err
+	}
+
+	g, ctx := errgroup.WithContext(execCtx)
+	for _, task := range tasks {
+		task.ctx = ctx
+		g.Go(task.work)
+	}
+
+	_ = g.Wait() // Errors have been turned into warnings, let's simply discard them.
+	return nil
+}
+
+func (e *CompactTableTiKVExec) physicalTableIDs() []int64 {
+	if e.tableInfo.Partition == nil {
+		return []int64{e.tableInfo.ID}
+	}
+	if len(e.partitionIDs) > 0 {
+		return e.partitionIDs
+	}
+	ids := make([]int64, 0, len(e.tableInfo.Partition.Definitions))
+	for _, definition := range e.tableInfo.Partition.Definitions {
+		ids = append(ids, definition.ID)
+	}
+	return ids
+}
+
+// buildStoreTasks finds out the TiKV stores holding the regions of the physical tables to compact.
+func (e *CompactTableTiKVExec) buildStoreTasks(ctx context.Context) ([]*tikvStoreCompactTask, error) {
+	bo := tikv.NewBackofferWithVars(ctx, compactMaxBackoffSleepMs, nil)
+	regionCache := e.tikvStore.GetRegionCache()
+	// storeAddrs records the address of the TiKV stores, it's empty for the other kinds of stores.
+	storeAddrs := make(map[uint64]string)
+	taskOfStore := make(map[string]*tikvStoreCompactTask)
+	var tasks []*tikvStoreCompactTask
+	for _, physicalID := range e.physicalTableIDs() {
+		regions, err := regionCache.LoadRegionsInKeyRange(bo, tablecodec.EncodeTablePrefix(physicalID), tablecodec.EncodeTablePrefix(physicalID+1))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, region := range regions {
+			for _, peer := range region.GetMeta().Peers {
+				addr, ok := storeAddrs[peer.StoreId]
+				if !ok {
+					store, err := regionCache.PDClient().GetStore(ctx, peer.StoreId)
+					if err != nil {
+						return nil, errors.Trace(err)
+					}
+					if tikvrpc.GetStoreTypeByMeta(store) == tikvrpc.TiKV {
+						addr = store.Address
+					}
+					storeAddrs[peer.StoreId] = addr
+				}
+				if len(addr) == 0 {
+					continue
+				}
+				task, ok := taskOfStore[addr]
+				if !ok {
+					task = &tikvStoreCompactTask{parentExec: e, storeAddr: addr}
+					taskOfStore[addr] = task
+					tasks = append(tasks, task)
+				}
+				if n := len(task.physicalIDs); n == 0 || task.physicalIDs[n-1] != physicalID {
+					task.physicalIDs = append(task.physicalIDs, physicalID)
+				}
+			}
+		}
+	}
+	return tasks, nil
+}
+
+// tikvStoreCompactTask compacts the physical tables described by parentExec in a TiKV store.
+type tikvStoreCompactTask struct {
+	ctx         context.Context // Maybe cancelled by other tasks, or parentExec is killed.
+	parentExec  *CompactTableTiKVExec
+	storeAddr   string
+	physicalIDs []int64
+}
+
+func (task *tikvStoreCompactTask) work() error {
+	log.Info("Begin compacting table in a tikv store",
+		zap.String("table", task.parentExec.tableInfo.Name.O),
+		zap.Int64("table-id", task.parentExec.tableInfo.ID),
+		zap.Int64s("physical-table-id", task.physicalIDs),
+		zap.String("store-address", task.storeAddr),
+	)
+	startAt := time.Now()
+	for _, physicalID := range task.physicalIDs {
+		if err := task.compactOnePhysicalTable(physicalID); err != nil {
+			// Stop remaining partitions when error happens.
+			return nil
+		}
+	}
+	log.Info("Compact table finished in a tikv store",
+		zap.Duration("elapsed", time.Since(startAt)),
+		zap.String("table", task.parentExec.tableInfo.Name.O),
+		zap.Int64("table-id", task.parentExec.tableInfo.ID),
+		zap.Int64s("physical-table-id", task.physicalIDs),
+		zap.String("store-address", task.storeAddr),
+	)
+	return nil
+}
+
+// compactOnePhysicalTable compacts the key range of one physical table in the TiKV store. The failure is turned
+// into a warning.
+func (task *tikvStoreCompactTask) compactOnePhysicalTable(physicalID int64) error {
+	startKey, endKey := task.parentExec.codec.EncodeRegionRange(tablecodec.EncodeTablePrefix(physicalID), tablecodec.EncodeTablePrefix(physicalID+1))
+	for _, cf := range compactTiKVColumnFamilies {
+		if task.ctx.Err() != nil {
+			return task.ctx.Err()
+		}
+		err := task.parentExec.client.Compact(task.ctx, task.storeAddr, &debugpb.CompactRequest{
+			Db:      debugpb.DB_KV,
+			Cf:      cf,
+			FromKey: append([]byte{tikvDataKeyPrefix}, startKey...),
+			ToKey:   append([]byte{tikvDataKeyPrefix}, endKey...),
+			Threads: 1,
+		})
+		if err != nil {
+			warn := errors.Errorf("compact on store %s failed: %v", task.storeAddr, err)
+			task.parentExec.ctx.GetSessionVars().StmtCtx.AppendWarning(warn)
+			log.Warn("Compact table failed",
+				zap.String("table", task.parentExec.tableInfo.Name.O),
+				zap.Int64("table-id", task.parentExec.tableInfo.ID),
+				zap.Int64("physical-table-id", physicalID),
+				zap.String("store-address", task.storeAddr),
+				zap.String("cf", cf),
+				zap.Error(err))
+			return warn
+		}
+	}
+	return nil
+}
+
+// CompactTableAllExec represents an executor for "ALTER TABLE [NAME] COMPACT" statement. It compacts the TiKV
+// replicas and then the TiFlash replicas, which are done by its children.
+type CompactTableAllExec struct {
+	baseExecutor
+}
+
+// Next implements the Executor Next interface.
+func (e *CompactTableAllExec) Next(ctx context.Context, chk *chunk.Chunk) error {
+	chk.Reset()
+	for _, child := range e.children {
+		if err := Next(ctx, child, chk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
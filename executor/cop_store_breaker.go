@@ -0,0 +1,155 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/distsql"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	copStoreBreakerWindow       = 10 * time.Second
+	copStoreBreakerMinRequests  = 10
+	copStoreBreakerErrorRatio   = 0.5
+	copStoreBreakerOpenDuration = 30 * time.Second
+)
+
+// globalCopStoreBreaker is shared by all the readers of the instance, it's enabled by tidb_cop_store_breaker_enable.
+var globalCopStoreBreaker = newCopStoreBreaker(copStoreBreakerWindow, copStoreBreakerMinRequests,
+	copStoreBreakerErrorRatio, copStoreBreakerOpenDuration)
+
+// ResetGlobalCopStoreBreakerForTest resets the global breaker, so the tripped stores don't affect the other tests.
+func ResetGlobalCopStoreBreakerForTest() {
+	globalCopStoreBreaker.reset()
+}
+
+// copStoreBreaker is a circuit breaker which keeps the coprocessor requests away from the stores
+// failing repeatedly. A store is tripped when the ratio of the responses meeting store errors
+// exceeds errorRatio in a window, and the requests are steered to the other replicas until it
+// has been tripped for openDuration.
+type copStoreBreaker struct {
+	window       time.Duration
+	minRequests  int
+	errorRatio   float64
+	openDuration time.Duration
+
+	mu     sync.Mutex
+	stores map[string]*copStoreBreakerState
+}
+
+type copStoreBreakerState struct {
+	windowStart time.Time
+	requests    int
+	errors      int
+	// openUntil is zero if the breaker of the store is closed.
+	openUntil time.Time
+}
+
+func newCopStoreBreaker(window time.Duration, minRequests int, errorRatio float64, openDuration time.Duration) *copStoreBreaker {
+	return &copStoreBreaker{
+		window:       window,
+		minRequests:  minRequests,
+		errorRatio:   errorRatio,
+		openDuration: openDuration,
+		stores:       make(map[string]*copStoreBreakerState),
+	}
+}
+
+// reset forgets the responses observed and closes the breakers of all the stores.
+func (b *copStoreBreaker) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stores = make(map[string]*copStoreBreakerState)
+}
+
+// ObserveCopResponse implements the distsql.CopStoreObserver interface.
+func (b *copStoreBreaker) ObserveCopResponse(storeAddr string, failed bool) {
+	b.observe(storeAddr, failed, time.Now())
+}
+
+func (b *copStoreBreaker) observe(storeAddr string, failed bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state, ok := b.stores[storeAddr]
+	if !ok {
+		state = &copStoreBreakerState{windowStart: now}
+		b.stores[storeAddr] = state
+	}
+	if !state.openUntil.IsZero() {
+		// The responses from the tripped store are not counted until it's closed.
+		return
+	}
+	if now.Sub(state.windowStart) > b.window {
+		state.windowStart, state.requests, state.errors = now, 0, 0
+	}
+	state.requests++
+	if failed {
+		state.errors++
+	}
+	if state.requests >= b.minRequests && float64(state.errors) >= float64(state.requests)*b.errorRatio {
+		state.openUntil = now.Add(b.openDuration)
+		logutil.BgLogger().Warn("coprocessor requests keep away from the store for too many errors",
+			zap.String("store", storeAddr), zap.Int("requests", state.requests), zap.Int("errors", state.errors),
+			zap.Duration("duration", b.openDuration))
+	}
+}
+
+// trippedStores returns the addresses of the stores that should be avoided, in ascending order.
+func (b *copStoreBreaker) trippedStores(now time.Time) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var stores []string
+	for addr, state := range b.stores {
+		if state.openUntil.IsZero() {
+			continue
+		}
+		if now.After(state.openUntil) {
+			// Close the breaker, and count the responses from the beginning again.
+			*state = copStoreBreakerState{windowStart: now}
+			continue
+		}
+		stores = append(stores, addr)
+	}
+	slices.Sort(stores)
+	return stores
+}
+
+// adjustRequest lets the tasks of the request whose leaders are on the tripped stores be served by the replicas
+// on the other stores, the other tasks still read from the leaders. Only the requests reading from the leader
+// are adjusted, the others have chosen the replicas on purpose.
+func (b *copStoreBreaker) adjustRequest(req *kv.Request) {
+	if req.StoreType != kv.TiKV || req.ReplicaRead != kv.ReplicaReadLeader || req.IsStaleness {
+		return
+	}
+	req.AvoidStores = b.trippedStores(time.Now())
+}
+
+// withCopStoreBreaker adjusts the request by the breaker, and attaches the breaker to the context to
+// observe the responses of the request. The request and the context are unchanged if the breaker is disabled.
+func withCopStoreBreaker(ctx context.Context, req *kv.Request) context.Context {
+	if !variable.EnableCopStoreBreaker.Load() {
+		return ctx
+	}
+	globalCopStoreBreaker.adjustRequest(req)
+	return context.WithValue(ctx, distsql.CopStoreObserverKey, globalCopStoreBreaker)
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/kv"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopStoreBreakerTrip(t *testing.T) {
+	b := newCopStoreBreaker(time.Second, 4, 0.5, 10*time.Second)
+	now := time.Now()
+
+	// too few requests to trip the store.
+	for i := 0; i < 3; i++ {
+		b.observe("store1", true, now)
+	}
+	require.Empty(t, b.trippedStores(now))
+
+	// the error ratio of store2 is under the threshold.
+	for i := 0; i < 10; i++ {
+		b.observe("store2", i%4 == 3, now)
+	}
+	b.observe("store1", false, now)
+	require.Equal(t, []string{"store1"}, b.trippedStores(now))
+
+	// the errors out of the window are not counted.
+	for i := 0; i < 3; i++ {
+		b.observe("store3", true, now)
+	}
+	b.observe("store3", true, now.Add(2*time.Second))
+	require.Equal(t, []string{"store1"}, b.trippedStores(now.Add(2*time.Second)))
+
+	req := &kv.Request{StoreType: kv.TiKV, ReplicaRead: kv.ReplicaReadLeader}
+	b.adjustRequest(req)
+	// only the tasks whose leaders are on the tripped store are served by the other replicas.
+	require.Equal(t, kv.ReplicaReadLeader, req.ReplicaRead)
+	require.Equal(t, []string{"store1"}, req.AvoidStores)
+	for _, req := range []*kv.Request{
+		{StoreType: kv.TiFlash, ReplicaRead: kv.ReplicaReadLeader},
+		{StoreType: kv.TiKV, ReplicaRead: kv.ReplicaReadFollower},
+		{StoreType: kv.TiKV, ReplicaRead: kv.ReplicaReadLeader, IsStaleness: true},
+	} {
+		b.adjustRequest(req)
+		require.Empty(t, req.AvoidStores)
+	}
+
+	// the store is closed after the open duration, and it's counted from the beginning again.
+	later := time.Now().Add(2 * time.Minute)
+	require.Empty(t, b.trippedStores(later))
+	for i := 0; i < 3; i++ {
+		b.observe("store1", true, later)
+	}
+	require.Empty(t, b.trippedStores(later))
+	b.observe("store1", true, later)
+	require.Equal(t, []string{"store1"}, b.trippedStores(later))
+}
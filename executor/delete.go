@@ -49,6 +49,8 @@ type DeleteExec struct {
 	fkChecks map[int64][]*FKCheckExec
 	// fkCascades contains the foreign key cascade. the map is tableID -> []*FKCascadeExec
 	fkCascades map[int64][]*FKCascadeExec
+	// returning is used for single table delete with RETURNING clause, it's evaluated on the deleted rows.
+	returning *dmlReturning
 }
 
 // Next implements the Executor Next interface.
@@ -140,6 +142,11 @@ func (e *DeleteExec) deleteSingleTableByChunk(ctx context.Context) error {
 			if err != nil {
 				return err
 			}
+			if e.returning != nil {
+				if err = e.returning.appendRow(chunkRow); err != nil {
+					return err
+				}
+			}
 			rowCount++
 		}
 		chk = chunk.Renew(chk, e.maxChunkSize)
@@ -282,6 +289,9 @@ func (e *DeleteExec) Close() error {
 func (e *DeleteExec) Open(ctx context.Context) error {
 	e.memTracker = memory.NewTracker(e.id, -1)
 	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	if e.returning != nil {
+		e.returning.rows.GetMemTracker().AttachTo(e.memTracker)
+	}
 
 	return e.children[0].Open(ctx)
 }
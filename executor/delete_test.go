@@ -15,12 +15,17 @@
 package executor_test
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDeleteLockKey(t *testing.T) {
@@ -128,3 +133,81 @@ func TestIssue21200(t *testing.T) {
 	tk.MustExec("execute stmt using @a")
 	tk.MustQuery("select * from t").Check(testkit.Rows("2"))
 }
+
+func TestDeleteReturning(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int primary key, a int, b int as (a * 2) virtual, c int as (a + 1) stored)")
+	tk.MustExec("insert into t(id, a) values (1, 10), (2, 20), (3, 30)")
+	tk.MustQuery("delete from t where id = 1 returning id, a, b, c").Check(testkit.Rows("1 10 20 11"))
+	tk.MustQuery("delete from t where a > 100 returning *").Check(testkit.Rows())
+	tk.MustQuery("delete from t where id > 1 order by id desc returning t.*, a + b as d").
+		Check(testkit.Rows("3 30 60 31 90", "2 20 40 21 60"))
+	tk.MustQuery("select * from t").Check(testkit.Rows())
+
+	// The rows are returned in the pessimistic transaction.
+	tk.MustExec("insert into t(id, a) values (1, 10), (2, 20)")
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("delete from t where id = 2 returning id * 10").Check(testkit.Rows("20"))
+	tk.MustQuery("select id from t").Check(testkit.Rows("1"))
+	tk.MustExec("rollback")
+	tk.MustQuery("select id from t").Check(testkit.Rows("1", "2"))
+
+	rs, err := tk.Exec("delete from t returning a as x, id")
+	require.NoError(t, err)
+	require.Equal(t, "x", rs.Fields()[0].ColumnAsName.O)
+	require.Equal(t, "id", rs.Fields()[1].ColumnAsName.O)
+	require.NoError(t, rs.Close())
+
+	tk.MustGetErrCode("delete from t returning (select 1)", errno.ErrNotSupportedYet)
+	tk.MustGetErrCode("delete from t returning d", errno.ErrBadField)
+}
+
+func TestTTLExpiryDelete(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	ttlCtx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnTTL)
+
+	for _, ddl := range []string{
+		"create table %s (id int primary key clustered, b int, c varchar(10), key(b), unique key(c))",
+		"create table %s (id int primary key nonclustered, b int, c varchar(10), key(b), unique key(c))",
+		"create table %s (id varchar(10) primary key clustered, b int, c varchar(10), key(b), unique key(c))",
+		"create table %s (id int, b int, c varchar(10), key(b), unique key(c, id)) partition by hash(id) partitions 4",
+	} {
+		for _, tbl := range []string{"t_ttl", "t_normal"} {
+			tk.MustExec("drop table if exists " + tbl)
+			tk.MustExec(fmt.Sprintf(ddl, tbl))
+			for i := 1; i <= 10; i++ {
+				tk.MustExec(fmt.Sprintf("insert into %s values ('%d', %d, 'c%d')", tbl, i, i%3, i))
+			}
+		}
+		// The TTL expiry removes the same rows and index entries as the normal delete.
+		_, err := tk.Session().ExecuteInternal(ttlCtx, "delete low_priority from test.t_ttl where id in ('2', '3', '5', '7', '11') and b < 2")
+		require.NoError(t, err)
+		ttlAffected := tk.Session().AffectedRows()
+		tk.MustExec("delete low_priority from test.t_normal where id in ('2', '3', '5', '7', '11') and b < 2")
+		require.Equal(t, tk.Session().AffectedRows(), ttlAffected)
+		require.Equal(t, uint64(2), ttlAffected)
+
+		expected := tk.MustQuery("select * from t_normal order by c").Rows()
+		tk.MustQuery("select * from t_ttl order by c").Check(expected)
+		tk.MustQuery("select id from t_ttl use index(b) where b < 2 order by c").Check(
+			tk.MustQuery("select id from t_normal use index(b) where b < 2 order by c").Rows())
+		tk.MustExec("admin check table t_ttl")
+	}
+
+	// The TTL expiry over a table with foreign keys cascades like the normal delete.
+	tk.MustExec("drop table if exists t_child, t_ttl")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("create table t_ttl (id int primary key)")
+	tk.MustExec("create table t_child (id int primary key, pid int, foreign key (pid) references t_ttl(id) on delete cascade)")
+	tk.MustExec("insert into t_ttl values (1), (2), (3)")
+	tk.MustExec("insert into t_child values (1, 1), (2, 2), (3, 3)")
+	_, err := tk.Session().ExecuteInternal(ttlCtx, "delete low_priority from test.t_ttl where id in (1, 2)")
+	require.NoError(t, err)
+	tk.MustQuery("select * from t_ttl").Check(testkit.Rows("3"))
+	tk.MustQuery("select * from t_child").Check(testkit.Rows("3 3"))
+}
@@ -191,9 +191,20 @@ type IndexReaderExecutor struct {
 	columns []*model.ColumnInfo
 	// outputColumns are only required by union scan.
 	outputColumns []*expression.Column
+	// virtualColumnIndex records the indices of the virtual columns which are not stored in the index but computed
+	// from the index columns, they are at the end of the schema and not returned by the coprocessor.
+	virtualColumnIndex []int
+	// virtualColumnRetFieldTypes records the RetFieldTypes of virtual columns.
+	virtualColumnRetFieldTypes []*types.FieldType
+	// idxChk is used to read the columns returned by the coprocessor when there are virtual columns to compute.
+	idxChk *chunk.Chunk
+	// chunkSizer limits the rows of a chunk for the wide rows, nil means the chunk is always full-sized.
+	chunkSizer *chunkSizeAdjuster
 
 	feedback *statistics.QueryFeedback
 	paging   bool
+	// scanFeedback records the observed selectivity of the index scan for the index advisor, nil means not recording.
+	scanFeedback *indexScanFeedbackRecorder
 
 	keepOrder bool
 	desc      bool
@@ -235,6 +246,9 @@ func (e *IndexReaderExecutor) Close() (err error) {
 		return nil
 	}
 	e.ctx.StoreQueryFeedback(e.feedback)
+	if e.scanFeedback != nil {
+		e.scanFeedback.close(e.ctx)
+	}
 	return err
 }
 
@@ -245,11 +259,46 @@ func (e *IndexReaderExecutor) Next(ctx context.Context, req *chunk.Chunk) error
 		return nil
 	}
 
-	err := e.result.Next(ctx, req)
+	if len(e.virtualColumnIndex) == 0 {
+		requiredRows := req.RequiredRows()
+		e.chunkSizer.limit(req)
+		err := e.result.Next(ctx, req)
+		e.chunkSizer.adjust(req, requiredRows)
+		if err != nil {
+			e.feedback.Invalidate()
+		} else if e.scanFeedback != nil {
+			e.scanFeedback.matchedRows += int64(req.NumRows())
+		}
+		return err
+	}
+
+	if e.idxChk == nil {
+		e.idxChk = chunk.NewChunkWithCapacity(e.outputFieldTypes(), e.maxChunkSize)
+	}
+	e.idxChk.SetRequiredRows(req.RequiredRows(), e.maxChunkSize)
+	e.chunkSizer.limit(e.idxChk)
+	err := e.result.Next(ctx, e.idxChk)
+	e.chunkSizer.adjust(e.idxChk, req.RequiredRows())
 	if err != nil {
 		e.feedback.Invalidate()
+		return err
 	}
-	return err
+	if e.scanFeedback != nil {
+		e.scanFeedback.matchedRows += int64(e.idxChk.NumRows())
+	}
+	req.Reset()
+	for i := 0; i < e.idxChk.NumCols(); i++ {
+		if err := req.SwapColumn(i, e.idxChk, i); err != nil {
+			return err
+		}
+	}
+	return table.FillVirtualColumnValue(e.virtualColumnRetFieldTypes, e.virtualColumnIndex, e.schema.Columns, e.columns, e.ctx, req)
+}
+
+// outputFieldTypes returns the field types of the columns returned by the coprocessor.
+func (e *IndexReaderExecutor) outputFieldTypes() []*types.FieldType {
+	fieldTypes := retTypes(e)
+	return fieldTypes[:len(fieldTypes)-len(e.virtualColumnIndex)]
 }
 
 // TODO: cleanup this method.
@@ -369,6 +418,9 @@ func (e *IndexReaderExecutor) open(ctx context.Context, kvRanges []kv.KeyRange)
 		e.dagPB.CollectExecutionSummaries = &collExec
 	}
 	e.kvRanges = kvRanges
+	if e.scanFeedback != nil {
+		e.scanFeedback.open(e.ctx)
+	}
 	// Treat temporary table as dummy table, avoid sending distsql request to TiKV.
 	// In a test case IndexReaderExecutor is mocked and e.table is nil.
 	// Avoid sending distsql request to TIKV.
@@ -392,7 +444,7 @@ func (e *IndexReaderExecutor) open(ctx context.Context, kvRanges []kv.KeyRange)
 			e.feedback.Invalidate()
 			return err
 		}
-		e.result, err = e.SelectResult(ctx, e.ctx, kvReq, retTypes(e), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
+		e.result, err = e.SelectResult(ctx, e.ctx, kvReq, e.outputFieldTypes(), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
 		if err != nil {
 			e.feedback.Invalidate()
 			return err
@@ -409,7 +461,7 @@ func (e *IndexReaderExecutor) open(ctx context.Context, kvRanges []kv.KeyRange)
 		}
 		var results []distsql.SelectResult
 		for _, kvReq := range kvReqs {
-			result, err := e.SelectResult(ctx, e.ctx, kvReq, retTypes(e), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
+			result, err := e.SelectResult(ctx, e.ctx, kvReq, e.outputFieldTypes(), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
 			if err != nil {
 				e.feedback.Invalidate()
 				return err
@@ -482,6 +534,9 @@ type IndexLookUpExecutor struct {
 	colLens         []int
 	// PushedLimit is used to skip the preceding and tailing handles when Limit is sunk into IndexLookUpReader.
 	PushedLimit *plannercore.PushedDownLimit
+	// fetchAhead is the number of lookup tasks which can be fetched ahead of the result consumer,
+	// LookupTableTaskChannelSize is used if it's not set.
+	fetchAhead int
 
 	stats *IndexLookUpRunTimeStats
 
@@ -590,7 +645,11 @@ func (e *IndexLookUpExecutor) open(ctx context.Context) error {
 	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
 
 	e.finished = make(chan struct{})
-	e.resultCh = make(chan *lookupTableTask, atomic.LoadInt32(&LookupTableTaskChannelSize))
+	fetchAhead := int(atomic.LoadInt32(&LookupTableTaskChannelSize))
+	if e.fetchAhead > 0 {
+		fetchAhead = e.fetchAhead
+	}
+	e.resultCh = make(chan *lookupTableTask, fetchAhead)
 
 	var err error
 	if e.corColInIdxSide {
@@ -727,7 +786,7 @@ func (e *IndexLookUpExecutor) startIndexWorker(ctx context.Context, workCh chan<
 				worker.syncErr(err)
 				break
 			}
-			result, err := distsql.SelectWithRuntimeStats(ctx, e.ctx, kvReq, tps, e.feedback, getPhysicalPlanIDs(e.idxPlans), idxID)
+			result, err := distsql.SelectWithRuntimeStats(withCopStoreBreaker(ctx, kvReq), e.ctx, kvReq, tps, e.feedback, getPhysicalPlanIDs(e.idxPlans), idxID)
 			if err != nil {
 				worker.syncErr(err)
 				break
@@ -290,6 +290,44 @@ func TestPushLimitDownIndexLookUpReader(t *testing.T) {
 	tk.MustQuery("select * from tbl use index(idx_b_c) where b > 1 and c > 1 limit 2,1").Check(testkit.Rows("4 4 4"))
 }
 
+func TestIndexLookUpFetchAhead(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int, key idx_b(b))")
+	vals := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		vals = append(vals, fmt.Sprintf("(%d, %d, %d)", i, rand.Intn(100), i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(vals, ","))
+	// Small lookup tasks to make the index worker fetch many tasks ahead.
+	tk.MustExec("set @@tidb_index_lookup_size = 10")
+	tk.MustQuery("select @@tidb_index_lookup_fetch_ahead").Check(testkit.Rows("50"))
+
+	queries := []string{
+		"select * from t use index(idx_b) where b > 10 order by a",
+		"select * from t use index(idx_b) where b > 10 order by b, a",
+		"select /*+ order_index(t, idx_b) */ * from t where b > 10 order by b limit 100",
+		"select a from t use index(idx_b) where b < 90 and c > 100 order by a",
+	}
+	expected := make([][][]interface{}, 0, len(queries))
+	for _, query := range queries {
+		expected = append(expected, tk.MustQuery(query).Rows())
+	}
+	for _, depth := range []int{1, 2, 7, 1024} {
+		tk.MustExec(fmt.Sprintf("set @@tidb_index_lookup_fetch_ahead = %d", depth))
+		for i, query := range queries {
+			require.True(t, tk.HasPlan(query, "IndexLookUp"), query)
+			tk.MustQuery(query).Check(expected[i])
+		}
+	}
+
+	tk.MustExec("set @@tidb_index_lookup_fetch_ahead = 0")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1292 Truncated incorrect tidb_index_lookup_fetch_ahead value: '0'"))
+	tk.MustQuery("select @@tidb_index_lookup_fetch_ahead").Check(testkit.Rows("1"))
+}
+
 func TestPartitionTableIndexLookUpReader(t *testing.T) {
 	failpoint.Enable("github.com/pingcap/tidb/planner/core/forceDynamicPrune", `return(true)`)
 	defer failpoint.Disable("github.com/pingcap/tidb/planner/core/forceDynamicPrune")
@@ -481,6 +519,48 @@ func TestCoprocessorPagingSize(t *testing.T) {
 	require.Equal(t, rpcNum, uint64(1))
 }
 
+func TestCopMaxConcurrentTasks(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 1), (25000, 2), (50000, 3), (75000, 4)")
+	tk.MustQuery("split table t between (0) and (100000) regions 10").Check(testkit.Rows("9 1"))
+	tk.MustExec("set @@tidb_enable_chunk_rpc = on")
+	tk.MustExec("set @@tidb_distsql_scan_concurrency = 8")
+
+	var maxConcurrency int
+	ctx := context.WithValue(context.Background(), "CheckSelectRequestHook", func(req *kv.Request) {
+		if req.Concurrency > maxConcurrency {
+			maxConcurrency = req.Concurrency
+		}
+	})
+	getMaxConcurrency := func(sql string) int {
+		maxConcurrency = 0
+		tk.MustQueryWithContext(ctx, sql)
+		return maxConcurrency
+	}
+
+	// Falls back to tidb_distsql_scan_concurrency by default.
+	tk.MustQuery("select @@tidb_cop_max_concurrent_tasks").Check(testkit.Rows("0"))
+	require.Equal(t, 8, getMaxConcurrency("select * from t"))
+
+	tk.MustExec("set @@tidb_cop_max_concurrent_tasks = 2")
+	require.Equal(t, 2, getMaxConcurrency("select * from t"))
+	tk.MustQuery("select * from t").Sort().Check(testkit.Rows("1 1", "25000 2", "50000 3", "75000 4"))
+
+	// The inner table reader of the index join is capped too.
+	sql := "select /*+ INL_JOIN(t2) */ t1.a, t2.a from t t1 join t t2 on t1.b = t2.a"
+	tk.MustQuery("explain " + sql).CheckContain("IndexJoin")
+	require.Equal(t, 2, getMaxConcurrency(sql))
+	tk.MustQuery(sql).Check(testkit.Rows("1 1"))
+
+	// The cap doesn't raise the concurrency.
+	tk.MustExec("set @@tidb_cop_max_concurrent_tasks = 100")
+	require.Equal(t, 8, getMaxConcurrency("select * from t"))
+}
+
 func TestAdaptiveClosestRead(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 
@@ -699,3 +779,280 @@ func TestCoprocessorBatchByStore(t *testing.T) {
 		}
 	}
 }
+
+func TestCopStoreBreaker(t *testing.T) {
+	store := testkit.CreateMockStore(t, mockstore.WithClusterInspector(func(c testutils.Cluster) {
+		mockstore.BootstrapWithMultiStores(c, 3)
+	}))
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b int, index ib(b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	// count the responses from the beginning, the ones during bootstrap are not taken into account.
+	executor.ResetGlobalCopStoreBreakerForTest()
+	defer executor.ResetGlobalCopStoreBreakerForTest()
+	tk.MustQuery("select @@global.tidb_cop_store_breaker_enable").Check(testkit.Rows("0"))
+	tk.MustExec("set @@global.tidb_cop_store_breaker_enable = 1")
+	defer tk.MustExec("set @@global.tidb_cop_store_breaker_enable = default")
+
+	servingStores := func(sql string) []string {
+		rows := tk.MustQuery("trace format='cop_regions' " + sql).Rows()
+		stores := make([]string, 0, len(rows))
+		for _, row := range rows {
+			stores = append(stores, row[1].(string))
+		}
+		return stores
+	}
+	stores := servingStores("select * from t")
+	require.Len(t, stores, 1)
+	leader := stores[0]
+
+	// the leader store is flapping, the requests are served by the other replicas after it's tripped.
+	// The responses are observed even if the execution info is not collected.
+	fpName := "github.com/pingcap/tidb/store/copr/mockCopStoreErr"
+	tk.MustExec("set @@tidb_enable_collect_execution_info = 0")
+	require.NoError(t, failpoint.Enable(fpName, fmt.Sprintf(`return("%s")`, leader)))
+	for i := 0; i < 10; i++ {
+		tk.MustQuery("select * from t").Check(testkit.Rows("1 1", "2 2", "3 3"))
+	}
+	require.NoError(t, failpoint.Disable(fpName))
+	tk.MustExec("set @@tidb_enable_collect_execution_info = 1")
+	for _, sql := range []string{
+		"select * from t",
+		// IndexReader
+		"select b from t use index(ib) where b > 1",
+		// IndexLookUp
+		"select * from t use index(ib) where b > 1",
+	} {
+		for i := 0; i < 5; i++ {
+			for _, addr := range servingStores(sql) {
+				require.NotEqual(t, leader, addr, sql)
+			}
+		}
+	}
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1", "2 2", "3 3"))
+	tk.MustQuery("select * from t use index(ib) where b > 1").Check(testkit.Rows("2 2", "3 3"))
+
+	// the requests read from the leader if the breaker is disabled, even though the store is tripped.
+	tk.MustExec("set @@global.tidb_cop_store_breaker_enable = 0")
+	require.Equal(t, []string{leader}, servingStores("select * from t"))
+	tk.MustExec("set @@global.tidb_cop_store_breaker_enable = 1")
+	require.NotEqual(t, []string{leader}, servingStores("select * from t"))
+
+	// the requests reading from the followers on purpose are not affected.
+	tk.MustExec("set @@tidb_replica_read = 'follower'")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1", "2 2", "3 3"))
+
+	// the requests go back to the leader once the breaker is reset.
+	executor.ResetGlobalCopStoreBreakerForTest()
+	tk.MustExec("set @@tidb_replica_read = 'leader'")
+	require.Equal(t, []string{leader}, servingStores("select * from t"))
+
+	// a follower store is flapping, the requests still read from the leader since it's not tripped.
+	tk.MustExec("set @@tidb_replica_read = 'follower'")
+	stores = servingStores("select * from t")
+	require.Len(t, stores, 1)
+	follower := stores[0]
+	require.NotEqual(t, leader, follower)
+	require.NoError(t, failpoint.Enable(fpName, fmt.Sprintf(`return("%s")`, follower)))
+	for i := 0; i < 60; i++ {
+		tk.MustQuery("select * from t").Check(testkit.Rows("1 1", "2 2", "3 3"))
+	}
+	require.NoError(t, failpoint.Disable(fpName))
+	tk.MustExec("set @@tidb_replica_read = 'leader'")
+	for i := 0; i < 10; i++ {
+		require.Equal(t, []string{leader}, servingStores("select * from t"))
+	}
+}
+
+func TestTableReaderHandleBatch(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b int, c int, key(b))")
+	tk.MustExec("create table t2(a int, b int, key(b))")
+	tk.MustExec("create table tp(a int primary key, b int, key(b)) partition by hash(a) partitions 4")
+	values := make([]string, 0, 3000)
+	for i := 0; i < 3000; i++ {
+		// b is in the reverse order of a, so the handles of an index are out of order.
+		values = append(values, fmt.Sprintf("(%d, %d, %d)", i, 3000-i, i%7))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	tk.MustExec("insert into t2 select a, b from t")
+	tk.MustExec("insert into tp select a, b from t")
+	tk.MustExec("analyze table t, t2, tp")
+	tk.MustExec("set @@tidb_index_lookup_size = 2000")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+
+	queries := []string{
+		"select /*+ use_index(t, b) */ * from t where b > 10",
+		"select /*+ use_index(t, b) */ * from t where b > 10 order by b desc",
+		"select /*+ use_index(tp, b) */ * from tp where b > 10",
+		"select /*+ inl_join(t) */ t.a, t.c from t2 join t on t2.b = t.a where t2.b > 20",
+		"select /*+ inl_merge_join(t) */ t.a, t.c from t2 join t on t2.a = t.a where t2.b > 20 order by t2.a",
+		"select /*+ use_index_merge(t, primary, b) */ * from t where a < 1500 or b < 1000",
+	}
+	expected := make([][][]interface{}, 0, len(queries))
+	tk.MustExec("set @@tidb_table_reader_handle_batch = 0")
+	for _, query := range queries {
+		expected = append(expected, tk.MustQuery(query).Sort().Rows())
+	}
+	tk.MustQuery("select /*+ use_index(t, b) */ a from t where b > 10 order by b desc limit 3").Check(testkit.Rows("0", "1", "2"))
+
+	// the handles of a table reader are read in batches of 100.
+	tk.MustExec("set @@tidb_table_reader_handle_batch = 100")
+	for i, query := range queries {
+		tk.MustQuery(query).Sort().Check(expected[i])
+	}
+	// the order is kept across the batches.
+	rows := tk.MustQuery("select /*+ inl_merge_join(t) */ t.a from t2 join t on t2.a = t.a where t2.b > 20 order by t2.a").Rows()
+	require.Len(t, rows, 2980)
+	for i, row := range rows {
+		require.Equal(t, strconv.Itoa(i), row[0])
+	}
+	tk.MustQuery("select /*+ use_index(t, b) */ a from t where b > 10 order by b desc limit 3").Check(testkit.Rows("0", "1", "2"))
+	// every batch of a lookup task is sent as a cop task of the table side.
+	copTaskNum := regexp.MustCompile(`cop_task: {num: (\d+)`)
+	rows = tk.MustQuery("explain analyze " + queries[0]).Rows()
+	num, err := strconv.Atoi(copTaskNum.FindStringSubmatch(rows[2][5].(string))[1])
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, num, 30)
+
+	tk.MustExec("set @@tidb_table_reader_handle_batch = 1")
+	tk.MustQuery(queries[0]).Sort().Check(expected[0])
+}
+
+func TestIndexReaderComputeVirtualColumn(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, a int, b int, c int as (a + b) virtual, d varchar(10), key idx(a, b))")
+	tk.MustExec("insert into t(id, a, b, d) values (1, 1, 10, 'x'), (2, 2, 20, 'y'), (3, 3, NULL, 'z'), (4, 4, 40, 'w')")
+
+	sql := "select a, c from t use index(idx) where a > 1"
+	tk.MustExec("set @@tidb_opt_index_reader_virtual_column = off")
+	require.False(t, tk.HasPlan(sql, "IndexReader"))
+	tk.MustExec("set @@tidb_opt_index_reader_virtual_column = on")
+	require.True(t, tk.HasPlan(sql, "IndexReader"))
+	require.False(t, tk.HasPlan(sql, "TableRowIDScan"))
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("2 22", "3 <nil>", "4 44"))
+	tk.MustQuery("select c from t use index(idx) order by a").Check(testkit.Rows("11", "22", "<nil>", "44"))
+	tk.MustQuery("select c, id from t use index(idx) where c > 20").Sort().Check(testkit.Rows("22 2", "44 4"))
+	tk.MustQuery("select sum(c) from t use index(idx)").Check(testkit.Rows("77"))
+
+	// The column which is not stored in the index still needs the table lookup.
+	require.False(t, tk.HasPlan("select c, d from t use index(idx)", "IndexReader"))
+	tk.MustQuery("select c, d from t use index(idx) where a < 3").Sort().Check(testkit.Rows("11 x", "22 y"))
+
+	// Read the virtual column computed by the index reader through the union scan.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t(id, a, b, d) values (5, 5, 50, 'v')")
+	tk.MustExec("update t set b = 30 where id = 3")
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("2 22", "3 33", "4 44", "5 55"))
+	tk.MustExec("rollback")
+}
+
+func TestCopSplitLargeRegions(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, v int)")
+	var sb strings.Builder
+	sb.WriteString("insert into t values ")
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(fmt.Sprintf("(%d, %d)", i, i*2))
+	}
+	tk.MustExec(sb.String())
+	tk.MustExec("analyze table t")
+	tk.MustExec("set @@tidb_distsql_scan_concurrency = 4")
+	// Every page of a paging request is counted as a cop task.
+	tk.MustExec("set @@tidb_enable_paging = off")
+
+	copTaskNum := regexp.MustCompile(`cop_task: {num: (\d+)`)
+	getCopTaskNum := func(sql string) int {
+		rows := tk.MustQuery("explain analyze " + sql).Rows()
+		num, err := strconv.Atoi(copTaskNum.FindStringSubmatch(rows[0][5].(string))[1])
+		require.NoError(t, err)
+		return num
+	}
+	queries := []string{
+		"select * from t",
+		"select * from t order by id",
+		"select * from t order by id desc",
+		"select * from t where id > 100 and id <= 900 order by id",
+	}
+	expected := make([][][]interface{}, 0, len(queries))
+	for _, query := range queries {
+		expected = append(expected, tk.MustQuery(query).Rows())
+	}
+	// The whole table is located in one region, so it's scanned by one cop task.
+	require.Equal(t, 1, getCopTaskNum(queries[0]))
+
+	tk.MustExec("set @@tidb_cop_split_large_regions = on")
+	require.Equal(t, 4, getCopTaskNum(queries[0]))
+	require.Greater(t, getCopTaskNum(queries[3]), 1)
+	tk.MustQuery(queries[0]).Sort().Check(tk.MustQuery(queries[0] + " order by id").Sort().Rows())
+	// The order is kept across the sub-ranges.
+	for i, query := range queries[1:] {
+		tk.MustQuery(query).Check(expected[i+1])
+	}
+	tk.MustQuery("select count(*), sum(v) from t").Check(testkit.Rows("1000 999000"))
+	tk.MustExec("set @@tidb_distsql_scan_concurrency = 1")
+	require.Equal(t, 1, getCopTaskNum(queries[0]))
+}
+
+func TestIndexLookUpPushDownIndexCoveredTableConds(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int primary key, a varchar(10), b varchar(10), c int, key idx(a, b(2)))")
+	tk.MustExec("insert into t values (1, 'a', 'aaa', 1), (2, 'b', NULL, 2), (3, 'c', 'ccc', 3), (4, 'd', NULL, 4), (5, 'e', 'eee', 5), " +
+		"(6, 'f', 'fff', 6), (7, 'g', 'ggg', 7), (8, 'h', 'hhh', 8)")
+	tk.MustExec("set @@tidb_opt_prefix_index_single_scan = on")
+
+	// tableLookUpRows returns the number of the rows looked up from the table.
+	tableLookUpRows := func(sql string) string {
+		for _, row := range tk.MustQuery("explain analyze " + sql).Rows() {
+			if strings.Contains(row[0].(string), "TableRowIDScan") {
+				return row[2].(string)
+			}
+		}
+		require.FailNow(t, "TableRowIDScan is not found")
+		return ""
+	}
+
+	sql := "select * from t use index(idx) where b is null and c > 0"
+	// The filter covered by the index is evaluated by the index side, so the non-matching rows aren't looked up.
+	tk.MustQuery("explain format = 'brief' "+sql).CheckAt([]int{0, 4}, [][]interface{}{
+		{"IndexLookUp", ""},
+		{"├─Selection(Build)", "isnull(test.t.b)"},
+		{"│ └─IndexFullScan", "keep order:false, stats:pseudo"},
+		{"└─Selection(Probe)", "gt(test.t.c, 0)"},
+		{"  └─TableRowIDScan", "keep order:false, stats:pseudo"},
+	})
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("2 b <nil> 2", "4 d <nil> 4"))
+	require.Equal(t, "2", tableLookUpRows(sql))
+
+	// The filter on the prefix column is kept in the table side if tidb_opt_prefix_index_single_scan is off.
+	tk.MustExec("set @@tidb_opt_prefix_index_single_scan = off")
+	tk.MustQuery("explain format = 'brief' " + sql).CheckNotContain("Selection(Build)")
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("2 b <nil> 2", "4 d <nil> 4"))
+	require.Equal(t, "8", tableLookUpRows(sql))
+	tk.MustExec("set @@tidb_opt_prefix_index_single_scan = on")
+
+	// The condition on the handle is also evaluated by the index side.
+	sql = "select * from t use index(idx) where id > 6 and c > 0 and a < 'z'"
+	tk.MustQuery("explain format = 'brief' " + sql).CheckContain("Selection(Build)")
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("7 g ggg 7", "8 h hhh 8"))
+
+	// The condition which needs the full value of the prefix column is evaluated after the table lookups.
+	sql = "select * from t use index(idx) where b = 'ccc'"
+	tk.MustQuery("explain format = 'brief' " + sql).CheckNotContain("Selection(Build)")
+	tk.MustQuery(sql).Check(testkit.Rows("3 c ccc 3"))
+	require.Equal(t, "8", tableLookUpRows(sql))
+}
@@ -947,7 +947,8 @@ func getTableName(is infoschema.InfoSchema, id int64) string {
 
 // CheckTableExec represents a check table executor.
 // It is built from the "admin check table" statement, and it checks if the
-// index matches the records in the table.
+// index matches the records in the table. When fkChecks is not empty, it also
+// checks every foreign key value of the records has a parent row.
 type CheckTableExec struct {
 	baseExecutor
 
@@ -955,6 +956,7 @@ type CheckTableExec struct {
 	table      table.Table
 	indexInfos []*model.IndexInfo
 	srcs       []*IndexLookUpExecutor
+	fkChecks   []*FKCheckExec
 	done       bool
 	is         infoschema.InfoSchema
 	exitCh     chan struct{}
@@ -1031,11 +1033,21 @@ func (e *CheckTableExec) handlePanic(r interface{}) {
 
 // Next implements the Executor Next interface.
 func (e *CheckTableExec) Next(ctx context.Context, req *chunk.Chunk) error {
-	if e.done || len(e.srcs) == 0 {
+	if e.done {
 		return nil
 	}
 	defer func() { e.done = true }()
 
+	if err := e.checkIndices(ctx); err != nil {
+		return err
+	}
+	return e.checkForeignKeys(ctx)
+}
+
+func (e *CheckTableExec) checkIndices(ctx context.Context) error {
+	if len(e.srcs) == 0 {
+		return nil
+	}
 	idxNames := make([]string, 0, len(e.indexInfos))
 	for _, idx := range e.indexInfos {
 		if idx.MVIndex {
@@ -1116,6 +1128,59 @@ func (e *CheckTableExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	}
 }
 
+// checkForeignKeys scans the records of the table in batches, and checks the foreign key values
+// of every batch have parent rows.
+func (e *CheckTableExec) checkForeignKeys(ctx context.Context) error {
+	if len(e.fkChecks) == 0 {
+		return nil
+	}
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	sc := e.ctx.GetSessionVars().StmtCtx
+	tbls := []table.Table{e.table}
+	if pi := e.table.Meta().GetPartitionInfo(); pi != nil {
+		tbls = make([]table.Table, 0, len(pi.Definitions))
+		for _, def := range pi.Definitions {
+			tbls = append(tbls, e.table.(table.PartitionedTable).GetPartition(def.ID))
+		}
+	}
+	handles := make([]kv.Handle, 0, e.maxChunkSize)
+	rows := make([][]types.Datum, 0, e.maxChunkSize)
+	checkBatch := func() error {
+		for _, fkc := range e.fkChecks {
+			offset, err := fkc.checkParentRowsExist(ctx, sc, txn, rows)
+			if err != nil {
+				return err
+			}
+			if offset >= 0 {
+				vals, err := fkc.fetchFKValues(rows[offset])
+				if err != nil {
+					return err
+				}
+				return exeerrors.ErrAdminCheckForeignKey.GenWithStackByArgs(fkc.FK.Name.O, handles[offset].String(), types.DatumsToStrNoErr(vals))
+			}
+		}
+		handles, rows = handles[:0], rows[:0]
+		return nil
+	}
+	for _, tbl := range tbls {
+		err = tables.IterRecords(tbl, e.ctx, tbl.Cols(), func(h kv.Handle, rec []types.Datum, _ []*table.Column) (bool, error) {
+			handles = append(handles, h)
+			rows = append(rows, rec)
+			if len(rows) < e.maxChunkSize {
+				return true, nil
+			}
+			return true, checkBatch()
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return checkBatch()
+}
+
 func (e *CheckTableExec) checkTableRecord(ctx context.Context, idxOffset int) error {
 	idxInfo := e.indexInfos[idxOffset]
 	txn, err := e.ctx.Txn(true)
@@ -1376,13 +1441,28 @@ func doLockKeys(ctx context.Context, se sessionctx.Context, lockCtx *tikvstore.L
 	keys = filterLockTableKeys(sessVars.StmtCtx, keys)
 	var lockKeyStats *tikvutil.LockKeysDetails
 	ctx = context.WithValue(ctx, tikvutil.LockKeysDetailCtxKey, &lockKeyStats)
+	startLocking := time.Now()
 	err = txn.LockKeys(tikvutil.SetSessionID(ctx, se.GetSessionVars().ConnectionID), lockCtx, keys...)
+	recordLockEvent(ctx, keys, lockKeyStats, time.Since(startLocking), err)
 	if lockKeyStats != nil {
 		sctx.MergeLockKeysExecDetails(lockKeyStats)
 	}
 	return err
 }
 
+// recordLockEvent records the attempt to lock the keys if the statement is traced in the 'locks' format.
+func recordLockEvent(ctx context.Context, keys []kv.Key, stats *tikvutil.LockKeysDetails, lockTime time.Duration, err error) {
+	events, ok := ctx.Value(execdetails.LockEventsKey).(*execdetails.LockEvents)
+	if !ok || len(keys) == 0 {
+		return
+	}
+	rawKeys := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		rawKeys = append(rawKeys, key)
+	}
+	events.Record(rawKeys, stats, lockTime, err)
+}
+
 func filterTemporaryTableKeys(vars *variable.SessionVars, keys []kv.Key) []kv.Key {
 	txnCtx := vars.TxnCtx
 	if txnCtx == nil || txnCtx.TemporaryTables == nil {
@@ -2236,6 +2316,10 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 			sc.InShowWarning = true
 			sc.SetWarnings(vars.StmtCtx.GetWarnings())
 		}
+		if stmt.Tp == ast.ShowProfile {
+			sc.PrevPlan = vars.StmtCtx.GetPlan()
+			sc.PrevRuntimeStatsColl = vars.StmtCtx.RuntimeStatsColl
+		}
 	case *ast.SplitRegionStmt:
 		sc.IgnoreTruncate.Store(false)
 		sc.IgnoreZeroInDate = true
@@ -2269,10 +2353,10 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 		sc.PrevAffectedRows = -1
 	}
 	if globalConfig.Instance.EnableCollectExecutionInfo.Load() {
-		// In ExplainFor case, RuntimeStatsColl should not be reset for reuse,
-		// because ExplainFor need to display the last statement information.
+		// In ExplainFor and ShowProfile case, RuntimeStatsColl should not be reset for reuse,
+		// because they need to display the last statement information.
 		reuseObj := vars.StmtCtx.RuntimeStatsColl
-		if _, ok := s.(*ast.ExplainForStmt); ok {
+		if _, ok := s.(*ast.ExplainForStmt); ok || sc.PrevRuntimeStatsColl != nil {
 			reuseObj = nil
 		}
 		sc.RuntimeStatsColl = execdetails.NewRuntimeStatsColl(reuseObj)
@@ -555,3 +555,45 @@ func TestDeadlocksTable(t *testing.T) {
 			id2+"/2022-06-11 02:03:04.987654/1/203/<nil>/<nil>/<nil>/<nil>/201",
 		))
 }
+
+func TestTSFetchRetry(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, v int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+
+	// Fail the next tso request of the statements executed with ctx, the reader requests a new ts when it retries.
+	fpName := "github.com/pingcap/tidb/sessiontxn/isolation/mockOracleFutureErr"
+	ctx := failpoint.WithHook(context.Background(), func(_ context.Context, name string) bool {
+		return name == fpName
+	})
+	failNextTSORequest := func() {
+		require.NoError(t, failpoint.Enable(fpName, "1*return"))
+	}
+	defer func() {
+		require.NoError(t, failpoint.Disable(fpName))
+	}()
+
+	tk.MustQuery("select @@tidb_ts_fetch_retry").Check(testkit.Rows("2"))
+	failNextTSORequest()
+	tk.MustQueryWithContext(ctx, "select * from t where v > 0").Sort().Check(testkit.Rows("1 1", "2 2"))
+	failNextTSORequest()
+	tk.MustExecWithContext(ctx, "update t set v = v + 1 where v > 0")
+	tk.MustQuery("select * from t where v > 0").Sort().Check(testkit.Rows("1 2", "2 3"))
+
+	// The ts of every statement of a read committed transaction is retried too.
+	tk.MustExec("set tx_isolation = 'READ-COMMITTED'")
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select * from t where v > 0").Sort().Check(testkit.Rows("1 2", "2 3"))
+	failNextTSORequest()
+	tk.MustQueryWithContext(ctx, "select * from t where v > 0").Sort().Check(testkit.Rows("1 2", "2 3"))
+	tk.MustExec("commit")
+	tk.MustExec("set tx_isolation = 'REPEATABLE-READ'")
+
+	tk.MustExec("set @@tidb_ts_fetch_retry = 0")
+	failNextTSORequest()
+	_, err := tk.ExecWithContext(ctx, "select * from t where v > 0")
+	require.ErrorContains(t, err, "mock oracle future error")
+	tk.MustQueryWithContext(ctx, "select * from t where v > 0").Sort().Check(testkit.Rows("1 2", "2 3"))
+}
@@ -16,25 +16,37 @@ package executor
 
 import (
 	"context"
+	"fmt"
 	"runtime"
 	"strconv"
 	"testing"
 	"time"
 	"unsafe"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	plannerutil "github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/dbterror/exeerrors"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/ranger"
+	"github.com/pingcap/tidb/util/rowcodec"
 	"github.com/pingcap/tidb/util/tableutil"
+	"github.com/pingcap/tipb/go-tipb"
 	"github.com/stretchr/testify/require"
 )
 
@@ -104,6 +116,93 @@ func TestSlowQueryRuntimeStats(t *testing.T) {
 	require.Equal(t, "initialize: 2ms, read_file: 2s, parse_log: {time:200ms, concurrency:15}, total_file: 4, read_file: 4, read_size: 2 GB", stats.String())
 }
 
+type mockBatchRetriever struct {
+	dummyCloser
+	batches [][][]types.Datum
+}
+
+func (r *mockBatchRetriever) retrieve(context.Context, sessionctx.Context) ([][]types.Datum, error) {
+	if len(r.batches) == 0 {
+		return nil, nil
+	}
+	rows := r.batches[0]
+	r.batches = r.batches[1:]
+	return rows, nil
+}
+
+func TestMemTableResultLimiter(t *testing.T) {
+	row := types.MakeDatums(1, "abcdefghij")
+	rowBytes := types.EstimatedMemUsage(row, 1)
+	batch := [][]types.Datum{row, row}
+	limiter := &memTableResultLimiter{
+		memTableRetriever: &mockBatchRetriever{batches: [][][]types.Datum{batch, batch, batch}},
+		table:             &model.TableInfo{Name: model.NewCIStr("t")},
+		maxBytes:          rowBytes * 5,
+	}
+	ctx := mock.NewContext()
+	// The rows are accumulated across the batches.
+	for i := 0; i < 2; i++ {
+		rows, err := limiter.retrieve(context.Background(), ctx)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+	}
+	_, err := limiter.retrieve(context.Background(), ctx)
+	require.True(t, exeerrors.ErrMemTableResultTooLarge.Equal(err))
+	require.EqualError(t, err, fmt.Sprintf("[executor:8178]The result of memory table t exceeds tidb_memtable_max_result_bytes %d bytes, consider adding filters to the query", rowBytes*5))
+}
+
+func TestMemTableResultLimiterWhileProducing(t *testing.T) {
+	schemas := make([]*model.DBInfo, 0, 10)
+	for i := 0; i < 10; i++ {
+		schemas = append(schemas, &model.DBInfo{Name: model.NewCIStr(fmt.Sprintf("db%d", i))})
+	}
+	rowBytes := types.EstimatedMemUsage(types.MakeDatums(infoschema.CatalogVal, "db0", mysql.DefaultCharset, mysql.DefaultCollationName, nil, nil), 1)
+	limiter := &memTableResultLimiter{
+		table:    &model.TableInfo{Name: model.NewCIStr("SCHEMATA")},
+		maxBytes: rowBytes * 3,
+	}
+	retriever := &memtableRetriever{resultLimiter: limiter}
+	// The production stops once the rows exceed the limit, rather than after all the rows are produced.
+	err := retriever.setDataFromSchemata(mock.NewContext(), schemas)
+	require.True(t, exeerrors.ErrMemTableResultTooLarge.Equal(err))
+	require.Equal(t, 4, limiter.checkedRows)
+	require.Nil(t, retriever.rows)
+
+	limiter = &memTableResultLimiter{
+		table:    &model.TableInfo{Name: model.NewCIStr("SCHEMATA")},
+		maxBytes: rowBytes * 10,
+	}
+	retriever = &memtableRetriever{resultLimiter: limiter}
+	require.NoError(t, retriever.setDataFromSchemata(mock.NewContext(), schemas))
+	require.NoError(t, limiter.checkRows(retriever.rows))
+	require.Len(t, retriever.rows, 10)
+	require.Equal(t, 10, limiter.checkedRows)
+}
+
+func TestAnalyzeSamplesThrottle(t *testing.T) {
+	var nilThrottle *analyzeSamplesThrottle
+	require.False(t, nilThrottle.throttled())
+
+	samplesMemTracker := memory.NewTracker(memory.LabelForAnalyzeSamples, 100)
+	throttle := &analyzeSamplesThrottle{}
+	samplesMemTracker.SetActionOnExceed(throttle)
+	taskTracker := memory.NewTracker(1, -1)
+	taskTracker.AttachTo(samplesMemTracker)
+
+	// The tasks aren't throttled until the samples held by them exceed the quota.
+	taskTracker.Consume(60)
+	require.False(t, throttle.throttled())
+	taskTracker.Consume(40)
+	require.True(t, throttle.throttled())
+
+	// The quota isn't limited by default.
+	samplesMemTracker = memory.NewTracker(memory.LabelForAnalyzeSamples, -1)
+	throttle = &analyzeSamplesThrottle{}
+	samplesMemTracker.SetActionOnExceed(throttle)
+	samplesMemTracker.Consume(1 << 30)
+	require.False(t, throttle.throttled())
+}
+
 // Test whether the actual buckets in Golang Map is same with the estimated number.
 // The test relies the implement of Golang Map. ref https://github.com/golang/go/blob/go1.13/src/runtime/map.go#L114
 func TestAggPartialResultMapperB(t *testing.T) {
@@ -351,3 +450,307 @@ func TestSortSpillDisk(t *testing.T) {
 	err = exec.Close()
 	require.NoError(t, err)
 }
+
+type unknownPhysicalPlan struct {
+	plannercore.PhysicalPlan
+}
+
+func TestBuildSortOnOrderedIndexReader(t *testing.T) {
+	ctx := mock.NewContext()
+	cols := []*expression.Column{
+		{UniqueID: 1, Index: 0, RetType: types.NewFieldType(mysql.TypeLonglong)},
+		{UniqueID: 2, Index: 1, RetType: types.NewFieldType(mysql.TypeLonglong)},
+	}
+	is := &plannercore.PhysicalIndexScan{
+		IdxCols:    cols,
+		IdxColLens: []int{types.UnspecifiedLength, types.UnspecifiedLength},
+	}
+	reader := &IndexReaderExecutor{
+		baseExecutor: newBaseExecutor(ctx, expression.NewSchema(cols...), 0),
+		index:        &model.IndexInfo{},
+		plans:        []plannercore.PhysicalPlan{is},
+		keepOrder:    true,
+	}
+	buildSort := func(byItems ...*plannerutil.ByItems) Executor {
+		sort := plannercore.PhysicalSort{ByItems: byItems}.Init(ctx, nil, 0)
+		sort.SetChildren(buildMockDataPhysicalPlan(ctx, reader))
+		b := newExecutorBuilder(ctx, nil, nil)
+		exec := b.build(sort)
+		require.NoError(t, b.err)
+		return exec
+	}
+
+	// The index reader keeps the index order, so no SortExec is built.
+	require.Same(t, reader, buildSort(&plannerutil.ByItems{Expr: cols[0]}))
+	require.Same(t, reader, buildSort(&plannerutil.ByItems{Expr: cols[0]}, &plannerutil.ByItems{Expr: cols[1]}))
+	// The order doesn't match the index order.
+	require.IsType(t, &SortExec{}, buildSort(&plannerutil.ByItems{Expr: cols[1]}))
+	require.IsType(t, &SortExec{}, buildSort(&plannerutil.ByItems{Expr: cols[1]}, &plannerutil.ByItems{Expr: cols[0]}))
+	require.IsType(t, &SortExec{}, buildSort(&plannerutil.ByItems{Expr: cols[0], Desc: true}))
+	require.IsType(t, &SortExec{}, buildSort(&plannerutil.ByItems{Expr: expression.NewOne()}))
+
+	// The descending index scan.
+	reader.desc = true
+	require.Same(t, reader, buildSort(&plannerutil.ByItems{Expr: cols[0], Desc: true}))
+	require.IsType(t, &SortExec{}, buildSort(&plannerutil.ByItems{Expr: cols[0]}))
+	reader.desc = false
+
+	// The prefix index doesn't keep the order of the full values.
+	is.IdxColLens[0] = 4
+	require.IsType(t, &SortExec{}, buildSort(&plannerutil.ByItems{Expr: cols[0]}))
+	is.IdxColLens[0] = types.UnspecifiedLength
+
+	// The index reader doesn't keep order.
+	reader.keepOrder = false
+	require.IsType(t, &SortExec{}, buildSort(&plannerutil.ByItems{Expr: cols[0]}))
+}
+
+func TestBuildParallelWindowProcessor(t *testing.T) {
+	ctx := mock.NewContext()
+	vars := ctx.GetSessionVars()
+	require.NoError(t, vars.SetSystemVar(variable.TiDBEnablePipelinedWindowFunction, "0"))
+	require.NoError(t, vars.SetSystemVar(variable.TiDBWindowConcurrency, "4"))
+	cols := []*expression.Column{
+		{Index: 0, RetType: types.NewFieldType(mysql.TypeDouble)},
+		{Index: 1, RetType: types.NewFieldType(mysql.TypeLonglong)},
+	}
+	schema := expression.NewSchema(cols...)
+	dataSource := buildMockDataSource(mockDataSourceParameters{schema: schema, rows: 10, ctx: ctx})
+	buildProcessor := func(funcs int) windowProcessor {
+		exec := buildWindowExecutor(ctx, ast.AggFuncSum, funcs, nil, dataSource, schema, cols[1:], 1, true)
+		require.IsType(t, &WindowExec{}, exec)
+		return exec.(*WindowExec).processor
+	}
+
+	// The parallel evaluation is off by default.
+	require.False(t, vars.EnableParallelWindowExec)
+	require.IsType(t, &aggWindowProcessor{}, buildProcessor(2))
+
+	require.NoError(t, vars.SetSystemVar(variable.TiDBEnableParallelWindowFunction, "1"))
+	processor := buildProcessor(2)
+	require.IsType(t, &parallelWindowProcessor{}, processor)
+	require.Len(t, processor.(*parallelWindowProcessor).processors, 2)
+	// A single window function isn't evaluated in parallel.
+	require.IsType(t, &aggWindowProcessor{}, buildProcessor(1))
+	// Neither is it when the concurrency is 1.
+	require.NoError(t, vars.SetSystemVar(variable.TiDBWindowConcurrency, "1"))
+	require.IsType(t, &aggWindowProcessor{}, buildProcessor(2))
+}
+
+func TestBuildUnsupportedPlan(t *testing.T) {
+	ctx := mock.NewContext()
+	b := newExecutorBuilder(ctx, nil, nil)
+
+	// The plan is known by the planner but not supported by the executor.
+	require.Nil(t, b.build(&plannercore.PhysicalExpand{}))
+	require.True(t, exeerrors.ErrUnknownPlan.Equal(b.err))
+	require.EqualError(t, b.err, "[executor:8114]Plan *core.PhysicalExpand is not supported by the executor yet, it can only be executed by TiFlash in MPP mode")
+
+	b = newExecutorBuilder(ctx, nil, nil)
+	require.Nil(t, b.build(&unknownPhysicalPlan{}))
+	require.True(t, exeerrors.ErrUnknownPlan.Equal(b.err))
+	require.EqualError(t, b.err, "[executor:8114]Unknown Plan *executor.unknownPhysicalPlan")
+}
+
+// newRowDecoderForTest returns a decoder of a table whose columns except a and b are added after the row is written,
+// and the encoded row.
+func newRowDecoderForTest(t testing.TB, ctx *mock.Context) (*rowcodec.ChunkDecoder, *expression.Schema, []byte) {
+	newCol := func(id int64, name string, tp byte, originDefault interface{}) *model.ColumnInfo {
+		col := &model.ColumnInfo{ID: id, Name: model.NewCIStr(name), Offset: int(id - 1), State: model.StatePublic}
+		col.FieldType = *types.NewFieldType(tp)
+		if tp == mysql.TypeVarchar {
+			col.SetFlen(10)
+			col.SetCharset(mysql.DefaultCharset)
+			col.SetCollate(mysql.DefaultCollationName)
+		}
+		if tp == mysql.TypeDatetime {
+			col.SetDecimal(0)
+		}
+		require.NoError(t, col.SetOriginDefaultValue(originDefault))
+		return col
+	}
+	tblInfo := &model.TableInfo{ID: 1, Name: model.NewCIStr("t"), PKIsHandle: true}
+	tblInfo.Columns = []*model.ColumnInfo{
+		newCol(1, "a", mysql.TypeLonglong, nil),
+		newCol(2, "b", mysql.TypeLonglong, nil),
+		newCol(3, "c", mysql.TypeLonglong, "10"),
+		newCol(4, "d", mysql.TypeVarchar, "abc"),
+		newCol(5, "e", mysql.TypeDatetime, "2023-01-02 03:04:05"),
+		newCol(6, "f", mysql.TypeDatetime, ast.CurrentTimestamp),
+		newCol(7, "g", mysql.TypeLonglong, nil),
+	}
+	tblInfo.Columns[0].AddFlag(mysql.PriKeyFlag | mysql.NotNullFlag)
+	schema := expression.NewSchema()
+	for _, col := range tblInfo.Columns {
+		schema.Append(&expression.Column{ID: col.ID, UniqueID: col.ID, RetType: &col.FieldType})
+	}
+	var encoder rowcodec.Encoder
+	row, err := encoder.Encode(ctx.GetSessionVars().StmtCtx, []int64{2}, []types.Datum{types.NewIntDatum(2)}, nil)
+	require.NoError(t, err)
+	return NewRowDecoder(ctx, schema, tblInfo), schema, row
+}
+
+func TestRowDecoderDefaultValues(t *testing.T) {
+	ctx := mock.NewContext()
+	decoder, schema, row := newRowDecoderForTest(t, ctx)
+	fieldTypes := make([]*types.FieldType, 0, schema.Len())
+	for _, col := range schema.Columns {
+		fieldTypes = append(fieldTypes, col.RetType)
+	}
+	chk := chunk.NewChunkWithCapacity(fieldTypes, 3)
+	for i := 1; i <= 3; i++ {
+		// CURRENT_TIMESTAMP is evaluated for every row.
+		require.NoError(t, ctx.GetSessionVars().SetSystemVar(variable.Timestamp, strconv.Itoa(1672628645+i)))
+		require.NoError(t, decoder.DecodeToChunk(row, kv.IntHandle(i), chk))
+	}
+	require.Equal(t, 3, chk.NumRows())
+	for i := 0; i < chk.NumRows(); i++ {
+		r := chk.GetRow(i)
+		require.Equal(t, int64(i+1), r.GetInt64(0))
+		require.Equal(t, int64(2), r.GetInt64(1))
+		require.Equal(t, int64(10), r.GetInt64(2))
+		require.Equal(t, "abc", r.GetString(3))
+		require.Equal(t, "2023-01-02 03:04:05", r.GetTime(4).String())
+		require.True(t, r.IsNull(6))
+		if i > 0 {
+			require.Equal(t, 1, r.GetTime(5).Compare(chk.GetRow(i-1).GetTime(5)))
+		}
+	}
+
+	require.True(t, isOriginDefaultValueCacheable(&model.ColumnInfo{FieldType: *types.NewFieldType(mysql.TypeLonglong)}))
+	col := &model.ColumnInfo{FieldType: *types.NewFieldType(mysql.TypeLonglong)}
+	col.AddFlag(mysql.NotNullFlag)
+	require.False(t, isOriginDefaultValueCacheable(col))
+	col = &model.ColumnInfo{FieldType: *types.NewFieldType(mysql.TypeTimestamp), OriginDefaultValue: "current_timestamp"}
+	require.False(t, isOriginDefaultValueCacheable(col))
+	col = &model.ColumnInfo{FieldType: *types.NewFieldType(mysql.TypeDate), OriginDefaultValue: ast.CurrentDate}
+	require.False(t, isOriginDefaultValueCacheable(col))
+	col = &model.ColumnInfo{FieldType: *types.NewFieldType(mysql.TypeTimestamp), OriginDefaultValue: "2023-01-02 03:04:05"}
+	require.True(t, isOriginDefaultValueCacheable(col))
+}
+
+func TestChunkSizeAdjuster(t *testing.T) {
+	require.Equal(t, 1024, adaptiveChunkSize(0, 1024))
+	require.Equal(t, 1024, adaptiveChunkSize(100, 1024))
+	require.Equal(t, 512, adaptiveChunkSize(16*1024, 1024))
+	require.Equal(t, 1, adaptiveChunkSize(64*1024*1024, 1024))
+
+	// The narrow rows are read in full-sized chunks.
+	narrow := newChunkSizeAdjuster(100*10000, 10000, 1024)
+	require.Equal(t, 1024, narrow.size)
+	// The wide rows are read in the chunks bounded by the target size.
+	wide := newChunkSizeAdjuster(1024*1024*10000, 10000, 1024)
+	require.Equal(t, 8, wide.size)
+	// Without the row count, the chunk size is not limited.
+	require.Equal(t, 1024, newChunkSizeAdjuster(0, 0, 1024).size)
+
+	fieldTypes := []*types.FieldType{types.NewFieldType(mysql.TypeBlob)}
+	chk := chunk.New(fieldTypes, 1024, 1024)
+	narrow.limit(chk)
+	require.Equal(t, 1024, chk.RequiredRows())
+
+	// The size is shrunk after reading the rows wider than estimated.
+	chk.SetRequiredRows(100, 1024)
+	narrow.limit(chk)
+	require.Equal(t, 100, chk.RequiredRows())
+	wideValue := make([]byte, 256*1024)
+	for i := 0; i < 100; i++ {
+		chk.AppendBytes(0, wideValue)
+	}
+	narrow.adjust(chk, 100)
+	require.Equal(t, 100, chk.RequiredRows())
+	// About 256KB per row.
+	require.Equal(t, 31, narrow.size)
+	chk.Reset()
+	narrow.limit(chk)
+	require.Equal(t, narrow.size, chk.RequiredRows())
+
+	// The size is restored after reading the narrow rows, though the buffers of the wide rows are reused.
+	chk.Reset()
+	for i := 0; i < 1024; i++ {
+		chk.AppendBytes(0, []byte("a"))
+	}
+	narrow.adjust(chk, 1024)
+	require.Equal(t, 1024, narrow.size)
+
+	// A nil adjuster does nothing.
+	var nilAdjuster *chunkSizeAdjuster
+	nilAdjuster.limit(chk)
+	nilAdjuster.adjust(chk, 1024)
+	require.Equal(t, 1024, chk.RequiredRows())
+}
+
+func TestIsCountOnlyDAG(t *testing.T) {
+	tblScan := &tipb.Executor{Tp: tipb.ExecType_TypeTableScan, TblScan: &tipb.TableScan{}}
+	colRef := &tipb.Expr{Tp: tipb.ExprType_ColumnRef}
+	countOf := func(arg *tipb.Expr) *tipb.Expr {
+		return &tipb.Expr{Tp: tipb.ExprType_Count, Children: []*tipb.Expr{arg}}
+	}
+	aggOf := func(aggFuncs ...*tipb.Expr) *tipb.Executor {
+		return &tipb.Executor{Tp: tipb.ExecType_TypeAggregation, Aggregation: &tipb.Aggregation{AggFunc: aggFuncs, Child: tblScan}}
+	}
+	schemaOf := func(n int) *expression.Schema {
+		cols := make([]*expression.Column, 0, n)
+		for i := 0; i < n; i++ {
+			cols = append(cols, &expression.Column{UniqueID: int64(i), RetType: types.NewFieldType(mysql.TypeLonglong)})
+		}
+		return expression.NewSchema(cols...)
+	}
+
+	one := &tipb.Expr{Tp: tipb.ExprType_Int64}
+	str := &tipb.Expr{Tp: tipb.ExprType_String}
+	countOne := aggOf(countOf(one), countOf(str))
+	// The list-based executors sent to TiKV.
+	require.True(t, isCountOnlyDAG(&tipb.DAGRequest{Executors: []*tipb.Executor{tblScan, countOne}}, schemaOf(2)))
+	// The tree-based executors sent to TiFlash.
+	require.True(t, isCountOnlyDAG(&tipb.DAGRequest{RootExecutor: countOne}, schemaOf(2)))
+
+	require.False(t, isCountOnlyDAG(&tipb.DAGRequest{}, schemaOf(1)))
+	require.False(t, isCountOnlyDAG(&tipb.DAGRequest{RootExecutor: tblScan}, schemaOf(1)))
+	require.False(t, isCountOnlyDAG(&tipb.DAGRequest{RootExecutor: countOne}, schemaOf(3)))
+	require.False(t, isCountOnlyDAG(&tipb.DAGRequest{RootExecutor: aggOf(countOf(colRef))}, schemaOf(1)))
+	colInFunc := &tipb.Expr{Tp: tipb.ExprType_ScalarFunc, Children: []*tipb.Expr{one, colRef}}
+	require.False(t, isCountOnlyDAG(&tipb.DAGRequest{RootExecutor: aggOf(countOf(colInFunc))}, schemaOf(1)))
+	distinctCount := countOf(one)
+	distinctCount.HasDistinct = true
+	require.False(t, isCountOnlyDAG(&tipb.DAGRequest{RootExecutor: aggOf(distinctCount)}, schemaOf(1)))
+	sum := &tipb.Expr{Tp: tipb.ExprType_Sum, Children: []*tipb.Expr{one}}
+	require.False(t, isCountOnlyDAG(&tipb.DAGRequest{RootExecutor: aggOf(sum)}, schemaOf(1)))
+	groupBy := aggOf(countOf(one))
+	groupBy.Aggregation.GroupBy = []*tipb.Expr{colRef}
+	require.False(t, isCountOnlyDAG(&tipb.DAGRequest{RootExecutor: groupBy}, schemaOf(1)))
+}
+
+func TestIsRetryableTSFetchErr(t *testing.T) {
+	require.True(t, isRetryableTSFetchErr(errors.New("mock oracle future error")))
+	require.False(t, isRetryableTSFetchErr(context.Canceled))
+	require.False(t, isRetryableTSFetchErr(errors.Trace(context.DeadlineExceeded)))
+	require.False(t, isRetryableTSFetchErr(exeerrors.ErrQueryInterrupted))
+	require.False(t, isRetryableTSFetchErr(exeerrors.ErrMaxExecTimeExceeded.GenWithStackByArgs()))
+	require.False(t, isRetryableTSFetchErr(kv.ErrInvalidTxn))
+}
+
+func TestTopNCompactionMemTracker(t *testing.T) {
+	cas := defaultSortTestCase()
+	cas.rows = 10000
+	ds := buildMockDataSourceWithIndex(mockDataSourceParameters{
+		schema: expression.NewSchema(cas.columns()...),
+		rows:   cas.rows,
+		ctx:    cas.ctx,
+		ndvs:   cas.ndvs,
+	}, []int{0})
+	// The data source is in ascending order, so every row replaces the top of the heap.
+	byItems := []*plannerutil.ByItems{{Expr: cas.columns()[0], Desc: true}}
+	exec := buildTopNExec(cas.ctx, 0, 100, byItems, ds).(*TopNExec)
+	ds.prepareChunks()
+	require.NoError(t, exec.Open(context.Background()))
+	chk := newFirstChunk(exec)
+	require.NoError(t, exec.Next(context.Background(), chk))
+	require.Equal(t, 100, chk.NumRows())
+
+	// The chunks of the old rows are kept by the compaction, but they are released from the tracker.
+	require.NotNil(t, exec.spareRowChunks)
+	require.Greater(t, exec.spareRowChunks.GetMemTracker().BytesConsumed(), int64(0))
+	require.Equal(t, []*memory.Tracker{exec.rowChunks.GetMemTracker()}, exec.memTracker.GetChildrenForTest())
+	require.NoError(t, exec.Close())
+}
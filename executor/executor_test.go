@@ -25,6 +25,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -64,6 +65,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/dbterror"
+	"github.com/pingcap/tidb/util/dbterror/exeerrors"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/replayer"
@@ -219,6 +221,37 @@ func TestPlanReplayerCapture(t *testing.T) {
 	require.NotNil(t, task)
 }
 
+// usePlanReplayerTempDir makes the plan replayer files of the test written into a temporary directory.
+func usePlanReplayerTempDir(t *testing.T) {
+	restore := config.RestoreFunc()
+	t.Cleanup(restore)
+	logFile := filepath.Join(t.TempDir(), "tidb.log")
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Log.File.Filename = logFile
+	})
+}
+
+func TestPlanReplayerCaptureReset(t *testing.T) {
+	usePlanReplayerTempDir(t)
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("plan replayer capture reset")
+	require.Equal(t, uint64(0), tk.Session().AffectedRows())
+
+	tk.MustExec("plan replayer capture '123' '123'")
+	tk.MustExec("plan replayer capture '123' '456'")
+	tk.MustExec("plan replayer capture '456' '789'")
+	require.Len(t, dom.GetPlanReplayerHandle().GetTasks(), 3)
+	tk.MustExec("plan replayer capture reset")
+	require.Equal(t, uint64(3), tk.Session().AffectedRows())
+	tk.MustQuery("select count(*) from mysql.plan_replayer_task").Check(testkit.Rows("0"))
+	require.Empty(t, dom.GetPlanReplayerHandle().GetTasks())
+
+	// The task can be registered again after reset.
+	tk.MustExec("plan replayer capture '123' '123'")
+	tk.MustQuery("select sql_digest, plan_digest from mysql.plan_replayer_task").Check(testkit.Rows("123 123"))
+}
+
 func TestPlanReplayerContinuesCapture(t *testing.T) {
 	store, dom := testkit.CreateMockStoreAndDomain(t)
 	tk := testkit.NewTestKit(t, store)
@@ -6522,3 +6555,213 @@ func TestProcessInfoOfSubQuery(t *testing.T) {
 	tk2.MustQuery("select 1 from information_schema.processlist where TxnStart != '' and info like 'select%sleep% from t%'").Check(testkit.Rows("1"))
 	wg.Wait()
 }
+
+func TestCountOnlyTableReader(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int as (a + b) virtual)")
+	tk.MustExec("insert into t(a, b) values (1, 1), (2, 2), (3, NULL), (NULL, 4)")
+
+	fpName := "github.com/pingcap/tidb/executor/assertCountOnlyTableReader"
+	require.NoError(t, failpoint.Enable(fpName, "return(true)"))
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("4"))
+	tk.MustQuery("select count(1) from t where a > 1").Check(testkit.Rows("2"))
+	tk.MustQuery("select count(*), count(1) from t where b is not null").Check(testkit.Rows("3 3"))
+	tk.MustQuery("select count('x') from t").Check(testkit.Rows("4"))
+	tk.MustQuery("select count(*) from t where a > 10").Check(testkit.Rows("0"))
+	require.NoError(t, failpoint.Disable(fpName))
+
+	// The table columns are read.
+	require.NoError(t, failpoint.Enable(fpName, "return(false)"))
+	defer func() {
+		require.NoError(t, failpoint.Disable(fpName))
+	}()
+	tk.MustQuery("select count(*) from t where c > 2").Check(testkit.Rows("1"))
+	tk.MustQuery("select count(a), count(c) from t").Check(testkit.Rows("3 2"))
+	tk.MustQuery("select count(*), sum(a) from t").Check(testkit.Rows("4 6"))
+	tk.MustQuery("select count(*) from t group by a is null order by 1").Check(testkit.Rows("1", "3"))
+}
+
+func TestTableReaderOutputOffsetsForPushedAgg(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int)")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 1), (3, NULL, 2), (NULL, 4, 2)")
+	tk.MustExec("set @@tidb_allow_mpp = 0")
+
+	fpName := "github.com/pingcap/tidb/executor/checkTableReaderOutputOffsets"
+	// The partial count(*) is the only output, the handle is not returned.
+	require.NoError(t, failpoint.Enable(fpName, `return(1)`))
+	tk.MustQuery("select /*+ hash_agg() */ count(*) from t").Check(testkit.Rows("4"))
+	// The partial results of count and sum plus the group-by column.
+	require.NoError(t, failpoint.Enable(fpName, `return(3)`))
+	tk.MustQuery("select /*+ hash_agg() */ count(a), sum(b) from t group by c order by c").Check(testkit.Rows("2 3", "1 4"))
+	// The partial avg is made up of a count and a sum.
+	require.NoError(t, failpoint.Enable(fpName, `return(4)`))
+	tk.MustQuery("select /*+ hash_agg() */ c, avg(a), max(b) from t group by c order by c").Check(testkit.Rows("1 1.5000 2", "2 3.0000 4"))
+	// The columns only used by the pushed down selection are not returned.
+	require.NoError(t, failpoint.Enable(fpName, `return(1)`))
+	tk.MustQuery("select b from t where a > 1 and c > 0").Sort().Check(testkit.Rows("2", "<nil>"))
+	tk.MustQuery("select a + 1 from t where b > 1").Sort().Check(testkit.Rows("3", "<nil>"))
+	require.NoError(t, failpoint.Enable(fpName, `return(2)`))
+	tk.MustQuery("select c, a * 2 from t where b is not null").Sort().Check(testkit.Rows("1 2", "1 4", "2 <nil>"))
+	require.NoError(t, failpoint.Disable(fpName))
+	tk.MustQuery("explain format='brief' select b from t where a > 1 and c > 0").Check(testkit.Rows(
+		"Projection 1111.11 root  test.t.b",
+		"└─TableReader 1111.11 root  data:Selection",
+		"  └─Selection 1111.11 cop[tikv]  gt(test.t.a, 1), gt(test.t.c, 0)",
+		"    └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+}
+
+func BenchmarkCountStarTableReader(b *testing.B) {
+	store := testkit.CreateMockStore(b)
+	tk := testkit.NewTestKit(b, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b varchar(64), c double)")
+	for i := 0; i < 100; i++ {
+		values := make([]string, 0, 100)
+		for j := 0; j < 100; j++ {
+			values = append(values, fmt.Sprintf("(%d, 'abcdefghijklmnopqrstuvwxyz', %d.5)", i*100+j, j))
+		}
+		tk.MustExec("insert into t values " + strings.Join(values, ","))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tk.MustQuery("select count(*) from t").Check(testkit.Rows("10000"))
+	}
+	b.StopTimer()
+}
+
+func TestProjectionStopAtLimit(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int)")
+	values := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		values = append(values, fmt.Sprintf("(%d)", i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+
+	// The limit can't be pushed below the projection assigning the user variable,
+	// the projection shouldn't evaluate the rows beyond the limit.
+	sql := "select @a := @a + 1 from t limit 3"
+	require.True(t, tk.HasPlan(sql, "Limit"))
+	tk.MustExec("set @a = 0")
+	tk.MustQuery(sql).Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select @a").Check(testkit.Rows("3"))
+
+	tk.MustExec("set @a = 0")
+	tk.MustQuery("select @a := @a + 1 from t limit 2, 3").Check(testkit.Rows("3", "4", "5"))
+	tk.MustQuery("select @a").Check(testkit.Rows("5"))
+
+	tk.MustExec("set @a = 0")
+	require.Len(t, tk.MustQuery("select @a := @a + 1 from t limit 200").Rows(), 100)
+	tk.MustQuery("select @a").Check(testkit.Rows("100"))
+}
+
+func TestLimitPushDownToTableReader(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	values := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d)", i, i%2))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	tk.MustExec("set @@tidb_init_chunk_size=32, @@tidb_max_chunk_size=32")
+
+	readerActRows := func(sql string) string {
+		for _, row := range tk.MustQuery("explain analyze " + sql).Rows() {
+			if strings.Contains(row[0].(string), "TableReader") {
+				return row[2].(string)
+			}
+		}
+		require.FailNow(t, "no table reader in the plan", sql)
+		return ""
+	}
+
+	// The limit can't be pushed down by the planner through the projection assigning the user variable,
+	// the reader under the projection only returns the rows up to the end of the limit.
+	sql := "select @a := @a + 1 from t limit 2, 3"
+	require.True(t, tk.HasPlan(sql, "Limit"))
+	require.Equal(t, "5", readerActRows(sql))
+	tk.MustExec("set @a = 0")
+	tk.MustQuery(sql).Check(testkit.Rows("3", "4", "5"))
+
+	// The pushed-down filter is evaluated before the pushed limit.
+	sql = "select @a := @a + a from t where b = 1 limit 1, 2"
+	require.Equal(t, "3", readerActRows(sql))
+	tk.MustExec("set @a = 0")
+	tk.MustQuery(sql).Check(testkit.Rows("4", "9"))
+
+	// The reader isn't limited when the operator between the limit and the reader changes the row count.
+	tk.MustExec("set @b = -1")
+	sql = "select @a := @a + a from t where a > @b limit 3"
+	require.True(t, tk.HasPlan(sql, "Selection"))
+	require.NotEqual(t, "3", readerActRows(sql))
+}
+
+func TestAdaptiveChunkSizeForWideRows(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists narrow, wide")
+	tk.MustExec("create table narrow(a int primary key, b int, key(b))")
+	tk.MustExec("create table wide(a int primary key, b longblob, c varchar(64), key(c))")
+	narrowValues := make([]string, 0, 3000)
+	for i := 0; i < 3000; i++ {
+		narrowValues = append(narrowValues, fmt.Sprintf("(%d, %d)", i, i%10))
+	}
+	tk.MustExec("insert into narrow values " + strings.Join(narrowValues, ","))
+	for i := 0; i < 64; i++ {
+		tk.MustExec(fmt.Sprintf("insert into wide values (%d, repeat('x', %d), repeat('y', %d))", i, 256*1024+i, i))
+	}
+	tk.MustExec("analyze table narrow, wide")
+
+	// The rows read in the smaller chunks are the same as the rows read in the full-sized ones.
+	tk.MustQuery("select count(*), sum(a), sum(b) from (select a, b from narrow) t").Check(testkit.Rows("3000 4498500 13500"))
+	tk.MustQuery("select count(b), sum(b) from narrow use index(b)").Check(testkit.Rows("3000 13500"))
+	tk.MustQuery("select count(*), sum(a), sum(length(b)) from (select a, b from wide) t").Check(testkit.Rows(
+		fmt.Sprintf("64 2016 %d", 64*256*1024+2016)))
+	tk.MustQuery("select a, length(b) from wide where a >= 62 order by a").Check(testkit.Rows(
+		fmt.Sprintf("62 %d", 256*1024+62), fmt.Sprintf("63 %d", 256*1024+63)))
+	tk.MustQuery("select count(c), sum(length(c)) from wide use index(c)").Check(testkit.Rows("64 2016"))
+}
+
+func TestChaosBuildLatency(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int)")
+	tk.MustExec("insert into t values (1)")
+
+	tk.MustExec("set @@tidb_chaos_build_latency_ms = 200")
+	start := time.Now()
+	tk.MustQuery("select * from t").Check(testkit.Rows("1"))
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+
+	// The delayed statement can be killed.
+	tk.MustExec("set @@tidb_chaos_build_latency_ms = 3600000")
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		atomic.StoreUint32(&tk.Session().GetSessionVars().Killed, 1)
+	}()
+	start = time.Now()
+	err := tk.ExecToErr("select * from t")
+	require.True(t, exeerrors.ErrQueryInterrupted.Equal(err))
+	require.Less(t, time.Since(start), time.Minute)
+	atomic.StoreUint32(&tk.Session().GetSessionVars().Killed, 0)
+
+	tk.MustExec("set @@tidb_chaos_build_latency_ms = default")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1"))
+}
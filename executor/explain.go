@@ -48,6 +48,8 @@ type ExplainExec struct {
 	ruRuntimeStats *clientutil.RURuntimeStats
 	rows           [][]string
 	cursor         int
+	// assertOperatorRows maps the operator ids to their expected actual rows, set by tidb_assert_operator_rows.
+	assertOperatorRows map[string]int64
 }
 
 // Open implements the Executor Open interface.
@@ -145,12 +147,53 @@ func (e *ExplainExec) generateExplainInfo(ctx context.Context) (rows [][]string,
 	if err = e.executeAnalyzeExec(ctx); err != nil {
 		return nil, err
 	}
+	if err = e.checkOperatorRows(); err != nil {
+		return nil, err
+	}
 	if err = e.explain.RenderResult(); err != nil {
 		return nil, err
 	}
 	return e.explain.Rows, nil
 }
 
+// checkOperatorRows checks the actual rows of the operators against the expectations in tidb_assert_operator_rows.
+func (e *ExplainExec) checkOperatorRows() error {
+	if len(e.assertOperatorRows) == 0 || !e.executed {
+		return nil
+	}
+	flat := core.FlattenPhysicalPlan(e.explain.TargetPlan, true)
+	if flat == nil {
+		return nil
+	}
+	coll := e.ctx.GetSessionVars().StmtCtx.RuntimeStatsColl
+	checked := make(map[string]struct{}, len(e.assertOperatorRows))
+	for _, tree := range append([]core.FlatPlanTree{flat.Main}, flat.CTEs...) {
+		for _, op := range tree {
+			id := op.Origin.ExplainID().String()
+			expected, ok := e.assertOperatorRows[id]
+			if !ok {
+				continue
+			}
+			checked[id] = struct{}{}
+			actual := int64(0)
+			if coll != nil && coll.ExistsCopStats(op.Origin.ID()) {
+				actual = coll.GetCopStats(op.Origin.ID()).GetActRows()
+			} else if coll != nil && coll.ExistsRootStats(op.Origin.ID()) {
+				actual = coll.GetRootStats(op.Origin.ID()).GetActRows()
+			}
+			if actual != expected {
+				return errors.Errorf("the actual rows of operator %s is %d, but %d is expected", id, actual, expected)
+			}
+		}
+	}
+	for id := range e.assertOperatorRows {
+		if _, ok := checked[id]; !ok {
+			return errors.Errorf("operator %s is not found in the plan", id)
+		}
+	}
+	return nil
+}
+
 // getAnalyzeExecToExecutedNoDelay gets the analyze DML executor to execute in handleNoDelay function.
 // For explain analyze insert/update/delete statement, the analyze executor should be executed in handleNoDelay
 // function and then commit transaction if needed.
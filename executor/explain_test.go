@@ -702,3 +702,33 @@ func TestExplainFormatPlanCache(t *testing.T) {
 		tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
 	}
 }
+
+func TestAssertOperatorRows(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4)")
+	tk.MustGetErrCode("set @@tidb_assert_operator_rows = 'x'", errno.ErrWrongValueForVar)
+
+	// Collect the operator ids, like `Selection_10`, by the operator names.
+	ids := make(map[string]string)
+	for _, row := range tk.MustQuery("explain select count(*) from t where a > 1").Rows() {
+		id := strings.TrimLeft(row[0].(string), "└─│ ")
+		ids[id[:strings.LastIndexByte(id, '_')]] = id
+	}
+	tk.MustExec(fmt.Sprintf(`set @@tidb_assert_operator_rows = '{"%s": 3, "%s": 1}'`, ids["Selection"], ids["TableReader"]))
+	tk.MustQuery("explain analyze select count(*) from t where a > 1")
+
+	tk.MustExec(fmt.Sprintf(`set @@tidb_assert_operator_rows = '{"%s": 4}'`, ids["Selection"]))
+	err := tk.QueryToErr("explain analyze select count(*) from t where a > 1")
+	require.EqualError(t, err, fmt.Sprintf("the actual rows of operator %s is 3, but 4 is expected", ids["Selection"]))
+	tk.MustExec(`set @@tidb_assert_operator_rows = '{"Sort_100": 1}'`)
+	err = tk.QueryToErr("explain analyze select count(*) from t where a > 1")
+	require.EqualError(t, err, "operator Sort_100 is not found in the plan")
+
+	// The expectations are not checked by the statements without EXPLAIN ANALYZE.
+	tk.MustQuery("select count(*) from t where a > 1").Check(testkit.Rows("3"))
+	tk.MustExec("set @@tidb_assert_operator_rows = default")
+	tk.MustQuery("explain analyze select count(*) from t where a > 1")
+}
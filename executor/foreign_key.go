@@ -589,6 +589,65 @@ func (fkc FKCheckExec) checkRows(ctx context.Context, sc *stmtctx.StatementConte
 	return nil
 }
 
+// checkParentRowsExist checks the foreign key values of the child rows have parent rows, it returns the
+// offset of the first row without parent row, or -1 if all the rows have.
+func (fkc *FKCheckExec) checkParentRowsExist(ctx context.Context, sc *stmtctx.StatementContext, txn kv.Transaction, rows [][]types.Datum) (int, error) {
+	fkCheckKeys := make([]*fkCheckKey, len(rows))
+	prefetchKeys := make([]kv.Key, 0, len(rows))
+	for i, row := range rows {
+		vals, err := fkc.fetchFKValues(row)
+		if err != nil {
+			return -1, err
+		}
+		if fkc.hasNullValue(vals) {
+			continue
+		}
+		key, isPrefix, err := fkc.buildCheckKeyFromFKValue(sc, vals)
+		if err != nil {
+			return -1, err
+		}
+		fkCheckKeys[i] = &fkCheckKey{key, isPrefix}
+		if !isPrefix {
+			prefetchKeys = append(prefetchKeys, key)
+		}
+	}
+	if len(prefetchKeys) > 0 {
+		err := fkc.prefetchKeys(ctx, txn, prefetchKeys)
+		if err != nil {
+			return -1, err
+		}
+	}
+	memBuffer := txn.GetMemBuffer()
+	snap := txn.GetSnapshot()
+	snap.SetOption(kv.ScanBatchSize, 2)
+	defer func() {
+		snap.SetOption(kv.ScanBatchSize, txnsnapshot.DefaultScanBatchSize)
+	}()
+	for i, fkCheckKey := range fkCheckKeys {
+		if fkCheckKey == nil {
+			continue
+		}
+		var exist bool
+		if fkCheckKey.isPrefix {
+			_, value, err := fkc.getIndexKeyValueInTable(ctx, memBuffer, snap, fkCheckKey.k)
+			if err != nil {
+				return -1, err
+			}
+			exist = len(value) > 0
+		} else {
+			_, err := txn.Get(ctx, fkCheckKey.k)
+			if err != nil && !kv.IsErrNotFound(err) {
+				return -1, err
+			}
+			exist = err == nil
+		}
+		if !exist {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
 func (b *executorBuilder) buildTblID2FKCascadeExecs(tblID2Table map[int64]table.Table, tblID2FKCascades map[int64][]*plannercore.FKCascade) (map[int64][]*FKCascadeExec, error) {
 	fkCascadesMap := make(map[int64][]*FKCascadeExec)
 	for tid, tbl := range tblID2Table {
@@ -15,6 +15,7 @@
 package executor
 
 import (
+	"bytes"
 	"fmt"
 	"hash"
 	"hash/fnv"
@@ -33,6 +34,7 @@ import (
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/set"
 )
 
 // hashContext keeps the needed hash context of a db table in hash join.
@@ -116,6 +118,13 @@ type hashRowContainer struct {
 	// chkBuf buffer the data reads from the disk if rowContainer is spilled.
 	chkBuf                *chunk.Chunk
 	chkBufSizeForOneProbe int64
+
+	// distinctKeys indicates only one row of each join key is put into the rowContainer and the hash table, it's
+	// used when only the existence of the matched rows matters, e.g. the build side of the semi joins.
+	distinctKeys bool
+	// distinctKeySet keeps the encoded join keys put, so the keys are compared without reading the rows back from
+	// the rowContainer, which may be spilled to disk.
+	distinctKeySet set.StringSetWithMemoryUsage
 }
 
 func newHashRowContainer(sCtx sessionctx.Context, hCtx *hashContext, allTypes []*types.FieldType) *hashRowContainer {
@@ -169,9 +178,14 @@ func (c *hashRowContainer) GetAllMatchedRows(probeHCtx *hashContext, probeSideRo
 			}
 		})
 	matched = matched[:0]
+	c.resetChkBufForNAAJ()
 	if len(innerPtrs) == 0 {
 		return matched, nil
 	}
+	// all the build rows are collected here, track the memory so that the row data can be spilled in time.
+	innerPtrsSize := int64(cap(innerPtrs)) * rowPtrSize
+	c.memTracker.Consume(innerPtrsSize)
+	defer c.memTracker.Consume(-innerPtrsSize)
 	// all built bucket rows come from hash table, their bitmap are all nil (doesn't contain any null). so
 	// we could only use the probe null bits to filter valid rows.
 	if probeKeyNullBits != nil && len(probeHCtx.naKeyColIdx) > 1 {
@@ -198,7 +212,7 @@ func (c *hashRowContainer) GetAllMatchedRows(probeHCtx *hashContext, probeSideRo
 	}
 	var mayMatchedRow chunk.Row
 	for _, ptr := range innerPtrs {
-		mayMatchedRow, c.chkBuf, err = c.rowContainer.GetRowAndAppendToChunk(ptr, c.chkBuf)
+		mayMatchedRow, err = c.getRowForNAAJ(ptr)
 		if err != nil {
 			return nil, err
 		}
@@ -227,6 +241,9 @@ const rowSize = int64(unsafe.Sizeof(chunk.Row{}))
 // rowPtrSize is the size of RowPtr.
 const rowPtrSize = int64(unsafe.Sizeof(chunk.RowPtr{}))
 
+// naEntrySize is the size of naEntry and the pointer to it in the null bucket.
+const naEntrySize = int64(unsafe.Sizeof(naEntry{})) + int64(unsafe.Sizeof(&naEntry{}))
+
 // GetMatchedRowsAndPtrs get matched rows and Ptrs from probeRow. It can be called
 // in multiple goroutines while each goroutine should keep its own
 // h and buf.
@@ -298,8 +315,9 @@ func (c *hashRowContainer) GetNullBucketRows(probeHCtx *hashContext, probeSideRo
 		mayMatchedRow chunk.Row
 	)
 	matched = matched[:0]
+	c.resetChkBufForNAAJ()
 	for _, nullEntry := range c.hashNANullBucket.entries {
-		mayMatchedRow, c.chkBuf, err = c.rowContainer.GetRowAndAppendToChunk(nullEntry.ptr, c.chkBuf)
+		mayMatchedRow, err = c.getRowForNAAJ(nullEntry.ptr)
 		if err != nil {
 			return nil, err
 		}
@@ -374,6 +392,31 @@ func (c *hashRowContainer) GetNullBucketRows(probeHCtx *hashContext, probeSideRo
 	return matched, err
 }
 
+// resetChkBufForNAAJ releases the chunk buffer used by the last NAAJ bucket matching. The rows matched
+// by the last call are never referenced again once the next bucket of the same probe row is fetched.
+func (c *hashRowContainer) resetChkBufForNAAJ() {
+	c.chkBuf = nil
+	c.memTracker.Consume(-c.chkBufSizeForOneProbe)
+	c.chkBufSizeForOneProbe = 0
+}
+
+// getRowForNAAJ gets the row pointed by ptr. If the rowContainer is spilled, the row is read into the chunk
+// buffer, whose memory is tracked until the buffer is reset. Unlike the normal EQ bucket, the NAAJ null bucket
+// and the all-rows matching may read a large part of the build side from the disk in one probe.
+func (c *hashRowContainer) getRowForNAAJ(ptr chunk.RowPtr) (row chunk.Row, err error) {
+	lastChkBuf := c.chkBuf
+	row, c.chkBuf, err = c.rowContainer.GetRowAndAppendToChunk(ptr, c.chkBuf)
+	if err != nil {
+		return row, err
+	}
+	if lastChkBuf != nil && c.chkBuf != lastChkBuf {
+		lastChkBufSize := lastChkBuf.MemoryUsage()
+		c.chkBufSizeForOneProbe += lastChkBufSize
+		c.memTracker.Consume(lastChkBufSize)
+	}
+	return row, nil
+}
+
 // matchJoinKey checks if join keys of buildRow and probeRow are logically equal.
 func (c *hashRowContainer) matchJoinKey(buildRow, probeRow chunk.Row, probeHCtx *hashContext) (ok bool, err error) {
 	if len(c.hCtx.naKeyColIdx) > 0 {
@@ -406,6 +449,9 @@ func (c *hashRowContainer) PutChunkSelected(chk *chunk.Chunk, selected, ignoreNu
 	start := time.Now()
 	defer func() { c.stat.buildTableElapse += time.Since(start) }()
 
+	if c.distinctKeys {
+		return c.putChunkDistinctKeys(chk, selected, ignoreNulls)
+	}
 	chkIdx := uint32(c.rowContainer.NumChunks())
 	err := c.rowContainer.Add(chk)
 	if err != nil {
@@ -446,6 +492,7 @@ func (c *hashRowContainer) PutChunkSelected(chk *chunk.Chunk, selected, ignoreNu
 			}
 		}
 	}
+	var nullBucketMemDelta int64
 	for i := 0; i < numRows; i++ {
 		if isNAAJ {
 			if selected != nil && !selected[i] {
@@ -455,7 +502,10 @@ func (c *hashRowContainer) PutChunkSelected(chk *chunk.Chunk, selected, ignoreNu
 				// collect the null rows to slice.
 				rowPtr := chunk.RowPtr{ChkIdx: chkIdx, RowIdx: uint32(i)}
 				// do not directly ref the null bits map here, because the bit map will be reset and reused in next batch of chunk data.
-				c.hashNANullBucket.entries = append(c.hashNANullBucket.entries, &naEntry{rowPtr, c.hCtx.naColNullBitMap[i].Clone()})
+				nullBitMap := c.hCtx.naColNullBitMap[i].Clone()
+				c.hashNANullBucket.entries = append(c.hashNANullBucket.entries, &naEntry{rowPtr, nullBitMap})
+				// the null bucket stays in memory even if the row data is spilled, so its memory should be tracked.
+				nullBucketMemDelta += naEntrySize + nullBitMap.BytesConsumed()
 			} else {
 				// insert the not-null rows to hash table.
 				key := c.hCtx.hashVals[i].Sum64()
@@ -471,7 +521,57 @@ func (c *hashRowContainer) PutChunkSelected(chk *chunk.Chunk, selected, ignoreNu
 			c.hashTable.Put(key, rowPtr)
 		}
 	}
-	c.GetMemTracker().Consume(c.hashTable.GetAndCleanMemoryDelta())
+	c.GetMemTracker().Consume(c.hashTable.GetAndCleanMemoryDelta() + nullBucketMemDelta)
+	return nil
+}
+
+// putChunkDistinctKeys is like PutChunkSelected, but only puts the rows whose join keys are not put before.
+func (c *hashRowContainer) putChunkDistinctKeys(chk *chunk.Chunk, selected, ignoreNulls []bool) error {
+	var memDelta int64
+	if c.distinctKeySet.StringSet == nil {
+		c.distinctKeySet, memDelta = set.NewStringSetWithMemoryUsage()
+	}
+	numRows := chk.NumRows()
+	hCtx := c.hCtx
+	hCtx.initHash(numRows)
+	for keyIdx, colIdx := range hCtx.keyColIdx {
+		ignoreNull := len(ignoreNulls) > keyIdx && ignoreNulls[keyIdx]
+		err := codec.HashChunkSelected(c.sc, hCtx.hashVals, chk, hCtx.allTypes[keyIdx], colIdx, hCtx.buf, hCtx.hasNull, selected, ignoreNull)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	var keyBuf bytes.Buffer
+	distinctChk := c.rowContainer.AllocChunk()
+	distinctRows := make([]int, 0, numRows)
+	for i := 0; i < numRows; i++ {
+		if (selected != nil && !selected[i]) || hCtx.hasNull[i] {
+			continue
+		}
+		// The key is encoded in the same way as it's hashed.
+		keyBuf.Reset()
+		row := chk.GetRow(i)
+		if err := codec.HashChunkRow(c.sc, &keyBuf, row, hCtx.allTypes, hCtx.keyColIdx, hCtx.buf); err != nil {
+			return errors.Trace(err)
+		}
+		if c.distinctKeySet.Exist(string(keyBuf.Bytes())) {
+			continue
+		}
+		key := keyBuf.String()
+		memDelta += c.distinctKeySet.Insert(key) + int64(len(key))
+		distinctChk.AppendRow(row)
+		distinctRows = append(distinctRows, i)
+	}
+	if len(distinctRows) > 0 {
+		chkIdx := uint32(c.rowContainer.NumChunks())
+		if err := c.rowContainer.Add(distinctChk); err != nil {
+			return err
+		}
+		for j, i := range distinctRows {
+			c.hashTable.Put(hCtx.hashVals[i].Sum64(), chunk.RowPtr{ChkIdx: chkIdx, RowIdx: uint32(j)})
+		}
+	}
+	c.GetMemTracker().Consume(c.hashTable.GetAndCleanMemoryDelta() + memDelta)
 	return nil
 }
 
@@ -517,6 +617,14 @@ func (c *hashRowContainer) ActionSpill() memory.ActionOnExceed {
 	return c.rowContainer.ActionSpill()
 }
 
+// finishBuild is called after all the build side rows are put into the hashRowContainer.
+func (c *hashRowContainer) finishBuild() {
+	if ht, ok := c.hashTable.(*radixHashTable); ok {
+		ht.build()
+		c.GetMemTracker().Consume(ht.GetAndCleanMemoryDelta())
+	}
+}
+
 const (
 	initialEntrySliceLen = 64
 	maxEntrySliceLen     = 8192
@@ -699,3 +807,86 @@ func (ht *concurrentMapHashTable) GetAndCleanMemoryDelta() int64 {
 	}
 	return memDelta
 }
+
+// radixEntry is a build side row buffered in a partition of the radixHashTable.
+type radixEntry struct {
+	hashKey uint64
+	ptr     chunk.RowPtr
+}
+
+const radixEntrySize = int64(unsafe.Sizeof(radixEntry{}))
+
+// radixHashTable partitions the rows by the low bits of their hash keys. The rows are
+// buffered in their partitions while putting, and the small hash table of every partition
+// is built at once by build after all the rows are put, so that the hash table being built
+// or probed is more likely to stay in the CPU cache.
+// Put is not thread-safe, Get and Iter can only be called after build.
+type radixHashTable struct {
+	mask       uint64
+	pending    [][]radixEntry
+	partitions []*unsafeHashTable
+	length     uint64
+	memDelta   int64 // the memory delta of the radixHashTable since the last calling GetAndCleanMemoryDelta()
+}
+
+// newRadixHashTable creates a radixHashTable with 2^radixBits partitions.
+func newRadixHashTable(radixBits uint) *radixHashTable {
+	numPartitions := 1 << radixBits
+	return &radixHashTable{
+		mask:       uint64(numPartitions - 1),
+		pending:    make([][]radixEntry, numPartitions),
+		partitions: make([]*unsafeHashTable, numPartitions),
+	}
+}
+
+// Put buffers the key/rowPtr pair in the partition of the key.
+func (ht *radixHashTable) Put(hashKey uint64, rowPtr chunk.RowPtr) {
+	p := hashKey & ht.mask
+	oldCap := cap(ht.pending[p])
+	ht.pending[p] = append(ht.pending[p], radixEntry{hashKey: hashKey, ptr: rowPtr})
+	if newCap := cap(ht.pending[p]); newCap != oldCap {
+		ht.memDelta += int64(newCap-oldCap) * radixEntrySize
+	}
+	ht.length++
+}
+
+// build builds the hash table of every partition from the buffered rows.
+func (ht *radixHashTable) build() {
+	for p, entries := range ht.pending {
+		partition := newUnsafeHashTable(len(entries))
+		for _, e := range entries {
+			partition.Put(e.hashKey, e.ptr)
+		}
+		ht.partitions[p] = partition
+		ht.memDelta += partition.GetAndCleanMemoryDelta() - int64(cap(entries))*radixEntrySize
+		ht.pending[p] = nil
+	}
+}
+
+// Get gets the values of the "key" from the partition of the key.
+func (ht *radixHashTable) Get(hashKey uint64) (rowPtrs []chunk.RowPtr) {
+	partition := ht.partitions[hashKey&ht.mask]
+	if partition == nil {
+		return nil
+	}
+	return partition.Get(hashKey)
+}
+
+// Len returns the number of rowPtrs in the radixHashTable.
+func (ht *radixHashTable) Len() uint64 { return ht.length }
+
+// GetAndCleanMemoryDelta gets and cleans the memDelta of the radixHashTable.
+func (ht *radixHashTable) GetAndCleanMemoryDelta() int64 {
+	memDelta := ht.memDelta
+	ht.memDelta = 0
+	return memDelta
+}
+
+// Iter gets the every value of the hash table partition by partition.
+func (ht *radixHashTable) Iter(traverse func(key uint64, e *entry)) {
+	for _, partition := range ht.partitions {
+		if partition != nil {
+			partition.Iter(traverse)
+		}
+	}
+}
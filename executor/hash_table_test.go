@@ -23,6 +23,7 @@ import (
 
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/bitmap"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/memory"
@@ -180,3 +181,178 @@ func TestConcurrentMapHashTableMemoryUsage(t *testing.T) {
 	require.Equal(t, mapMemoryExpected+entryMemoryExpected, m.GetAndCleanMemoryDelta())
 	require.Equal(t, int64(0), m.GetAndCleanMemoryDelta())
 }
+
+func TestHashRowContainerDistinctKeys(t *testing.T) {
+	h := &hashCollision{count: 0}
+	hashFuncCollision := func() hash.Hash64 {
+		return h
+	}
+	for _, hashFunc := range []func() hash.Hash64{fnv.New64, hashFuncCollision} {
+		for _, spill := range []bool{false, true} {
+			testHashRowContainerDistinctKeys(t, hashFunc, spill)
+		}
+	}
+}
+
+func testHashRowContainerDistinctKeys(t *testing.T, hashFunc func() hash.Hash64, spill bool) {
+	sctx := mock.NewContext()
+	numRows, numKeys := 30, 3
+	colTypes := []*types.FieldType{
+		types.NewFieldTypeBuilder().SetType(mysql.TypeLonglong).BuildP(),
+		types.NewFieldTypeBuilder().SetType(mysql.TypeLonglong).BuildP(),
+	}
+	// The build side is full of the duplicated keys.
+	newBuildChunk := func() *chunk.Chunk {
+		chk := chunk.NewChunkWithCapacity(colTypes, numRows)
+		for i := 0; i < numRows; i++ {
+			chk.AppendInt64(0, int64(i%numKeys))
+			chk.AppendInt64(1, int64(i))
+		}
+		return chk
+	}
+	newRowContainer := func(distinctKeys bool) *hashRowContainer {
+		hCtx := &hashContext{
+			allTypes:  colTypes[:1],
+			keyColIdx: []int{0},
+		}
+		hCtx.hasNull = make([]bool, numRows)
+		for i := 0; i < numRows; i++ {
+			hCtx.hashVals = append(hCtx.hashVals, hashFunc())
+		}
+		rowContainer := newHashRowContainer(sctx, hCtx, colTypes)
+		rowContainer.distinctKeys = distinctKeys
+		tracker := rowContainer.GetMemTracker()
+		tracker.SetLabel(memory.LabelForBuildSideResult)
+		if spill {
+			tracker.SetBytesLimit(1)
+			rowContainer.rowContainer.ActionSpillForTest().Action(tracker)
+		}
+		require.NoError(t, rowContainer.PutChunk(newBuildChunk(), nil))
+		require.NoError(t, rowContainer.PutChunk(newBuildChunk(), nil))
+		rowContainer.ActionSpill().(*chunk.SpillDiskAction).WaitForTest()
+		require.Equal(t, spill, rowContainer.alreadySpilledSafeForTest())
+		return rowContainer
+	}
+
+	rowContainer := newRowContainer(false)
+	require.Equal(t, uint64(2*numRows), rowContainer.Len())
+	require.Equal(t, 2*numRows, rowContainer.rowContainer.NumRow())
+	require.NoError(t, rowContainer.Close())
+
+	rowContainer = newRowContainer(true)
+	require.Equal(t, uint64(numKeys), rowContainer.Len())
+	// The rows of the duplicated keys are not kept, and no chunk is added for the build chunk full of them.
+	require.Equal(t, numKeys, rowContainer.rowContainer.NumRow())
+	require.Equal(t, 1, rowContainer.NumChunks())
+	require.Equal(t, numKeys, rowContainer.distinctKeySet.Count())
+	// Each key still matches its first row.
+	probeChk := chunk.NewChunkWithCapacity(colTypes, numKeys)
+	for i := 0; i < numKeys; i++ {
+		probeChk.AppendInt64(0, int64(i))
+		probeChk.AppendInt64(1, 0)
+	}
+	probeCtx := &hashContext{
+		allTypes:  colTypes[:1],
+		keyColIdx: []int{0},
+	}
+	for i := 0; i < numKeys; i++ {
+		// The i-th row of the build chunks has the key i.
+		probeKey := rowContainer.hCtx.hashVals[i].Sum64()
+		matched, _, err := rowContainer.GetMatchedRowsAndPtrs(probeKey, probeChk.GetRow(i), probeCtx, nil, nil, false)
+		require.NoError(t, err)
+		require.Len(t, matched, 1)
+		require.Equal(t, int64(i), matched[0].GetInt64(1))
+	}
+	require.NoError(t, rowContainer.Close())
+}
+
+func TestHashRowContainerForNAAJ(t *testing.T) {
+	for _, spill := range []bool{false, true} {
+		testHashRowContainerForNAAJ(t, spill)
+	}
+}
+
+func testHashRowContainerForNAAJ(t *testing.T, spill bool) {
+	sctx := mock.NewContext()
+	numRows := 10
+	colTypes := []*types.FieldType{
+		types.NewFieldTypeBuilder().SetType(mysql.TypeLonglong).BuildP(),
+		types.NewFieldTypeBuilder().SetType(mysql.TypeLonglong).BuildP(),
+	}
+	// the NA join key is null for every third row.
+	buildChk := func(base int) *chunk.Chunk {
+		chk := chunk.NewChunkWithCapacity(colTypes, numRows)
+		for i := 0; i < numRows; i++ {
+			if i%3 == 0 {
+				chk.AppendNull(0)
+			} else {
+				chk.AppendInt64(0, int64(base+i))
+			}
+			chk.AppendInt64(1, int64(base+i))
+		}
+		return chk
+	}
+	hCtx := &hashContext{
+		allTypes:    colTypes[:1],
+		naKeyColIdx: []int{0},
+	}
+	rowContainer := newHashRowContainer(sctx, hCtx, colTypes)
+	tracker := rowContainer.GetMemTracker()
+	tracker.SetLabel(memory.LabelForBuildSideResult)
+	if spill {
+		tracker.SetBytesLimit(1)
+		rowContainer.rowContainer.ActionSpillForTest().Action(tracker)
+	}
+	require.NoError(t, rowContainer.PutChunk(buildChk(0), nil))
+	require.NoError(t, rowContainer.PutChunk(buildChk(numRows), nil))
+	rowContainer.ActionSpill().(*chunk.SpillDiskAction).WaitForTest()
+	require.Equal(t, spill, rowContainer.alreadySpilledSafeForTest())
+	require.Len(t, rowContainer.hashNANullBucket.entries, 8)
+	require.Equal(t, uint64(12), rowContainer.Len())
+
+	// the null bucket is kept in memory and tracked even if the row data is spilled.
+	nullChk := chunk.NewChunkWithCapacity(colTypes, numRows)
+	for i := 0; i < numRows; i++ {
+		nullChk.AppendNull(0)
+		nullChk.AppendInt64(1, int64(30*(i+1)))
+	}
+	memUsage := tracker.BytesConsumed() - rowContainer.rowContainer.GetMemTracker().BytesConsumed()
+	require.NoError(t, rowContainer.PutChunk(nullChk, nil))
+	require.Len(t, rowContainer.hashNANullBucket.entries, 18)
+	require.Equal(t, uint64(12), rowContainer.Len())
+	require.GreaterOrEqual(t, tracker.BytesConsumed()-rowContainer.rowContainer.GetMemTracker().BytesConsumed()-memUsage, int64(numRows)*naEntrySize)
+
+	probeCtx := &hashContext{
+		allTypes:    colTypes[:1],
+		naKeyColIdx: []int{0},
+	}
+	probeChk := chunk.NewChunkWithCapacity(colTypes, 2)
+	probeChk.AppendInt64(0, 1)
+	probeChk.AppendInt64(1, 1)
+	probeChk.AppendNull(0)
+	probeChk.AppendInt64(1, 2)
+
+	// a not-null probe key matches all the rows in the null bucket.
+	matched, err := rowContainer.GetNullBucketRows(probeCtx, probeChk.GetRow(0), nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, matched, 18)
+	for _, row := range matched {
+		require.True(t, row.IsNull(0))
+		require.Equal(t, int64(0), row.GetInt64(1)%int64(numRows)%3)
+	}
+	// a null probe key matches all the rows in the hash table.
+	probeKeyNullBits := bitmap.NewConcurrentBitmap(1)
+	probeKeyNullBits.UnsafeSet(0)
+	matched, err = rowContainer.GetAllMatchedRows(probeCtx, probeChk.GetRow(1), probeKeyNullBits, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, matched, 12)
+	sum := int64(0)
+	for _, row := range matched {
+		require.False(t, row.IsNull(0))
+		require.Equal(t, row.GetInt64(0), row.GetInt64(1))
+		sum += row.GetInt64(1)
+	}
+	// 1+2+4+5+7+8 + 11+12+14+15+17+18
+	require.Equal(t, int64(114), sum)
+	require.NoError(t, rowContainer.Close())
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/util/syncutil"
 	"github.com/tikv/client-go/v2/tikv"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -111,10 +112,20 @@ type chunkProcessor struct {
 	dataWriter    backend.EngineWriter
 	indexWriter   backend.EngineWriter
 
-	encoder     kvEncoder
-	kvCodec     tikv.Codec
-	progress    *asyncloaddata.Progress
-	startOffset int64
+	encoder  kvEncoder
+	kvCodec  tikv.Codec
+	progress *asyncloaddata.Progress
+	// chunkProgress is the progress of this chunk only, it's optional.
+	chunkProgress *ChunkProgress
+	startOffset   int64
+}
+
+// ChunkProgress records how much data of a chunk has been delivered to the engines.
+type ChunkProgress struct {
+	// ReadBytes is the size of the source data which has been delivered.
+	ReadBytes atomic.Int64
+	// DeliveredRows is the number of rows which has been delivered.
+	DeliveredRows atomic.Uint64
 }
 
 func (p *chunkProcessor) process(ctx context.Context) (err error) {
@@ -225,6 +236,7 @@ func (p *chunkProcessor) deliverLoop(ctx context.Context) error {
 	kvBatch := newDeliverKVBatch(p.kvCodec)
 
 	prevOffset, currOffset := p.startOffset, p.startOffset
+	var rowCnt uint64
 	for {
 	outer:
 		for kvBatch.size() < MinDeliverBytes {
@@ -237,6 +249,7 @@ func (p *chunkProcessor) deliverLoop(ctx context.Context) error {
 					kvBatch.add(row.kvs)
 					currOffset = row.offset
 				}
+				rowCnt += uint64(len(kvPacket))
 			case <-ctx.Done():
 				return ctx.Err()
 			}
@@ -269,7 +282,12 @@ func (p *chunkProcessor) deliverLoop(ctx context.Context) error {
 		}
 
 		p.progress.EncodeFileSize.Add(currOffset - prevOffset)
+		if p.chunkProgress != nil {
+			p.chunkProgress.ReadBytes.Add(currOffset - prevOffset)
+			p.chunkProgress.DeliveredRows.Add(rowCnt)
+		}
 		prevOffset = currOffset
+		rowCnt = 0
 
 		p.chunkInfo.Checksum.Add(kvBatch.dataChecksum)
 		p.chunkInfo.Checksum.Add(kvBatch.indexChecksum)
@@ -25,6 +25,7 @@ import (
 )
 
 // ProcessChunk processes a chunk, and write kv pairs to dataEngine and indexEngine.
+// chunkProgress is optional, if it's not nil, the progress of this chunk is added to it.
 func ProcessChunk(
 	ctx context.Context,
 	chunk *checkpoints.ChunkCheckpoint,
@@ -32,6 +33,7 @@ func ProcessChunk(
 	dataEngine,
 	indexEngine *backend.OpenedEngine,
 	progress *asyncloaddata.Progress,
+	chunkProgress *ChunkProgress,
 	logger *zap.Logger,
 ) error {
 	// if the key are ordered, LocalWrite can optimize the writing.
@@ -94,6 +96,7 @@ func ProcessChunk(
 		encoder:       encoder,
 		kvCodec:       tableImporter.kvStore.GetCodec(),
 		progress:      progress,
+		chunkProgress: chunkProgress,
 		diskQuotaLock: tableImporter.diskQuotaLock,
 	}
 	// todo: process in parallel
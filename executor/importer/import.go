@@ -90,6 +90,10 @@ const (
 	analyzeTableOption        = "analyze_table"
 	recordErrorsOption        = "record_errors"
 	detachedOption            = "detached"
+	// resumeTokenOption is only used by LOAD DATA.
+	resumeTokenOption = "resume_token"
+	// maxResumeTokenLen is the length of resume_token column of mysql.tidb_load_data_checkpoints.
+	maxResumeTokenLen = 64
 )
 
 var (
@@ -182,6 +186,9 @@ type Plan struct {
 	SplitFile         bool
 	MaxRecordedErrors int64
 	Detached          bool
+	// ResumeToken identifies a resumable LOAD DATA, the committed position of
+	// each source file is checkpointed under it. Empty means not resumable.
+	ResumeToken string
 
 	// used for checksum in physical mode
 	DistSQLScanConcurrency int
@@ -295,6 +302,11 @@ func NewPlanFromLoadDataPlan(userSctx sessionctx.Context, plan *plannercore.Load
 		nullDef = append(nullDef, string([]byte{lineFieldsInfo.FieldsEscapedBy[0], 'N'}))
 	}
 
+	resumeToken, err := getResumeToken(userSctx, plan)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Plan{
 		DBName: plan.Table.Schema.O,
 		DBID:   plan.Table.DBInfo.ID,
@@ -310,11 +322,39 @@ func NewPlanFromLoadDataPlan(userSctx sessionctx.Context, plan *plannercore.Load
 		SQLMode:          userSctx.GetSessionVars().SQLMode,
 		Charset:          charset,
 		ImportantSysVars: getImportantSysVars(userSctx),
+		ResumeToken:      resumeToken,
 
 		DistSQLScanConcurrency: userSctx.GetSessionVars().DistSQLScanConcurrency(),
 	}, nil
 }
 
+// getResumeToken gets the resume token of LOAD DATA from its options.
+func getResumeToken(userSctx sessionctx.Context, plan *plannercore.LoadData) (string, error) {
+	var token string
+	found := false
+	for _, opt := range plan.Options {
+		if opt.Name != resumeTokenOption {
+			continue
+		}
+		if found {
+			return "", exeerrors.ErrDuplicateOption.FastGenByArgs(opt.Name)
+		}
+		found = true
+		if opt.Value == nil || opt.Value.GetType().GetType() != mysql.TypeVarString {
+			return "", exeerrors.ErrInvalidOptionVal.FastGenByArgs(opt.Name)
+		}
+		val, isNull, err := opt.Value.EvalString(userSctx, chunk.Row{})
+		if err != nil || isNull || val == "" || len(val) > maxResumeTokenLen {
+			return "", exeerrors.ErrInvalidOptionVal.FastGenByArgs(opt.Name)
+		}
+		token = val
+	}
+	if found && plan.FileLocRef == ast.FileLocClient {
+		return "", exeerrors.ErrLoadDataUnsupportedOption.FastGenByArgs(resumeTokenOption, "LOAD DATA LOCAL")
+	}
+	return token, nil
+}
+
 // NewImportPlan creates a new import into plan.
 func NewImportPlan(userSctx sessionctx.Context, plan *plannercore.ImportInto, tbl table.Table) (*Plan, error) {
 	var format string
@@ -986,6 +1026,60 @@ func (e *LoadDataController) GetLoadDataReaderInfos() []LoadDataReaderInfo {
 func (e *LoadDataController) GetParser(
 	ctx context.Context,
 	dataFileInfo LoadDataReaderInfo,
+) (parser mydump.Parser, err error) {
+	parser, err = e.newParser(ctx, dataFileInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	// handle IGNORE N LINES
+	ignoreOneLineFn := parser.ReadRow
+	if csvParser, ok := parser.(*mydump.CSVParser); ok {
+		ignoreOneLineFn = func() error {
+			_, _, err3 := csvParser.ReadUntilTerminator()
+			return err3
+		}
+	}
+
+	ignoreLineCnt := e.IgnoreLines
+	for ignoreLineCnt > 0 {
+		err = ignoreOneLineFn()
+		if err != nil {
+			if errors.Cause(err) == io.EOF {
+				return parser, nil
+			}
+			terror.Log(parser.Close())
+			return nil, err
+		}
+
+		ignoreLineCnt--
+	}
+	return parser, nil
+}
+
+// GetParserAt returns a parser for the data file which starts at the given
+// position, see mydump.Parser.Pos for its meaning. The lines before the position
+// are regarded as handled, so IGNORE N LINES is not applied again.
+func (e *LoadDataController) GetParserAt(
+	ctx context.Context,
+	dataFileInfo LoadDataReaderInfo,
+	pos, rowID int64,
+) (mydump.Parser, error) {
+	parser, err := e.newParser(ctx, dataFileInfo)
+	if err != nil {
+		return nil, err
+	}
+	if err = parser.SetPos(pos, rowID); err != nil {
+		terror.Log(parser.Close())
+		return nil, exeerrors.ErrLoadDataCantRead.GenWithStackByArgs(
+			err.Error(), "Failed to seek to the checkpoint of the data file")
+	}
+	return parser, nil
+}
+
+func (e *LoadDataController) newParser(
+	ctx context.Context,
+	dataFileInfo LoadDataReaderInfo,
 ) (parser mydump.Parser, err error) {
 	reader, err2 := dataFileInfo.Opener(ctx)
 	if err2 != nil {
@@ -1038,28 +1132,6 @@ func (e *LoadDataController) GetParser(
 		return nil, exeerrors.ErrLoadDataWrongFormatConfig.GenWithStack(err.Error())
 	}
 	parser.SetLogger(litlog.Logger{Logger: logutil.Logger(ctx)})
-
-	// handle IGNORE N LINES
-	ignoreOneLineFn := parser.ReadRow
-	if csvParser, ok := parser.(*mydump.CSVParser); ok {
-		ignoreOneLineFn = func() error {
-			_, _, err3 := csvParser.ReadUntilTerminator()
-			return err3
-		}
-	}
-
-	ignoreLineCnt := e.IgnoreLines
-	for ignoreLineCnt > 0 {
-		err = ignoreOneLineFn()
-		if err != nil {
-			if errors.Cause(err) == io.EOF {
-				return parser, nil
-			}
-			return nil, err
-		}
-
-		ignoreLineCnt--
-	}
 	return parser, nil
 }
 
@@ -17,6 +17,7 @@ package executor
 import (
 	"context"
 	"strings"
+	"sync"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser"
@@ -25,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
+	"golang.org/x/exp/slices"
 )
 
 // IndexAdviseExec represents a index advise executor.
@@ -74,6 +76,9 @@ type IndexAdviseInfo struct {
 	Ctx       sessionctx.Context
 	StmtNodes [][]ast.StmtNode
 	Result    *IndexAdvice
+	// ScanFeedback is the index scan statistics observed in the session, the advisor prefers
+	// the observed selectivities of the existing indexes to the estimated ones.
+	ScanFeedback map[IndexScanKey]IndexScanStats
 }
 
 func (e *IndexAdviseInfo) getStmtNodes(data []byte) error {
@@ -124,13 +129,123 @@ func (e *IndexAdviseInfo) GetIndexAdvice(ctx context.Context, data []byte) error
 	if err := e.prepareInfo(data); err != nil {
 		return err
 	}
-	// TODO: Finish the index advise process. It will be done in another PR.
+	// TODO: Advise the new indexes for the workload. It will be done in another PR.
+	e.Result = &IndexAdvice{Items: e.adviseByScanFeedback()}
 	return nil
 }
 
+// indexAdviseMinSelectivity is the selectivity below which the scans on an index are considered inefficient,
+// most of the scanned entries are discarded by the filters pushed down to the index.
+const indexAdviseMinSelectivity = 0.1
+
+// adviseByScanFeedback finds the inefficient indexes scanned by the workload. The selectivity observed in the
+// execution is preferred to the estimated one, the estimated one is used only if the scans are not observed.
+func (e *IndexAdviseInfo) adviseByScanFeedback() []IndexAdviceItem {
+	tables := e.workloadTables()
+	var items []IndexAdviceItem
+	for key, stats := range e.ScanFeedback {
+		if _, ok := tables[indexAdviseTableKey{dbName: key.DBName, tableName: key.TableName}]; !ok {
+			continue
+		}
+		item := IndexAdviceItem{IndexScanKey: key, Observed: stats.ScannedRows > 0}
+		if item.Observed {
+			item.Selectivity = stats.Selectivity()
+		} else {
+			item.Selectivity = stats.EstSelectivity()
+		}
+		if item.Selectivity < indexAdviseMinSelectivity {
+			items = append(items, item)
+		}
+	}
+	slices.SortFunc(items, func(a, b IndexAdviceItem) bool {
+		if a.Selectivity != b.Selectivity {
+			return a.Selectivity < b.Selectivity
+		}
+		if a.DBName != b.DBName {
+			return a.DBName < b.DBName
+		}
+		if a.TableName != b.TableName {
+			return a.TableName < b.TableName
+		}
+		return a.IndexName < b.IndexName
+	})
+	if e.MaxIndexNum == nil {
+		return items
+	}
+	// Keep the most inefficient indexes if there are too many.
+	perTable := make(map[indexAdviseTableKey]uint64)
+	perDB := make(map[string]uint64)
+	j := 0
+	for _, item := range items {
+		tblKey := indexAdviseTableKey{dbName: item.DBName, tableName: item.TableName}
+		if perTable[tblKey] >= e.MaxIndexNum.PerTable || perDB[item.DBName] >= e.MaxIndexNum.PerDB {
+			continue
+		}
+		perTable[tblKey]++
+		perDB[item.DBName]++
+		items[j] = item
+		j++
+	}
+	return items[:j]
+}
+
+type indexAdviseTableKey struct {
+	dbName    string
+	tableName string
+}
+
+// workloadTables returns the tables referenced by the statements of the workload.
+func (e *IndexAdviseInfo) workloadTables() map[indexAdviseTableKey]struct{} {
+	collector := &indexAdviseTableCollector{
+		currentDB: strings.ToLower(e.Ctx.GetSessionVars().CurrentDB),
+		tables:    make(map[indexAdviseTableKey]struct{}),
+	}
+	for _, stmtNodes := range e.StmtNodes {
+		for _, stmtNode := range stmtNodes {
+			stmtNode.Accept(collector)
+		}
+	}
+	return collector.tables
+}
+
+type indexAdviseTableCollector struct {
+	currentDB string
+	tables    map[indexAdviseTableKey]struct{}
+}
+
+// Enter implements the ast.Visitor interface.
+func (c *indexAdviseTableCollector) Enter(in ast.Node) (ast.Node, bool) {
+	if tn, ok := in.(*ast.TableName); ok {
+		dbName := tn.Schema.L
+		if dbName == "" {
+			dbName = c.currentDB
+		}
+		c.tables[indexAdviseTableKey{dbName: dbName, tableName: tn.Name.L}] = struct{}{}
+	}
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (c *indexAdviseTableCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
 // IndexAdvice represents the index advice.
 type IndexAdvice struct {
-	// TODO: Define index advice data structure and implements the ResultSet interface. It will be done in another PR
+	// TODO: Implement the ResultSet interface. It will be done in another PR.
+
+	// Items are the existing indexes scanned inefficiently by the workload, the most inefficient ones come first.
+	Items []IndexAdviceItem
+}
+
+// IndexAdviceItem is an index whose scans discard most of the scanned entries, an index led by
+// the filtered columns is worth considering.
+type IndexAdviceItem struct {
+	IndexScanKey
+	// Selectivity is the ratio of the matched entries to the scanned entries of the index.
+	Selectivity float64
+	// Observed indicates the selectivity is observed in the execution rather than estimated.
+	Observed bool
 }
 
 // IndexAdviseVarKeyType is a dummy type to avoid naming collision in context.
@@ -143,3 +258,124 @@ func (k IndexAdviseVarKeyType) String() string {
 
 // IndexAdviseVarKey is a variable key for index advise.
 const IndexAdviseVarKey IndexAdviseVarKeyType = 0
+
+// IndexScanFeedbackVarKeyType is a dummy type to avoid naming collision in context.
+type IndexScanFeedbackVarKeyType int
+
+// String defines a Stringer function for debugging and pretty printing.
+func (k IndexScanFeedbackVarKeyType) String() string {
+	return "index_scan_feedback_var"
+}
+
+// IndexScanFeedbackVarKey is a variable key for the index scan feedback of a session.
+const IndexScanFeedbackVarKey IndexScanFeedbackVarKeyType = 0
+
+// IndexScanKey identifies the index an index scan works on.
+type IndexScanKey struct {
+	DBName    string
+	TableName string
+	IndexName string
+}
+
+// IndexScanStats is the statistics of the scans on an index.
+type IndexScanStats struct {
+	// ScannedRows is the number of the index entries scanned.
+	ScannedRows int64
+	// MatchedRows is the number of the index entries left after the pushed down filters.
+	MatchedRows int64
+	// EstScannedRows and EstMatchedRows are the estimations of the optimizer.
+	EstScannedRows float64
+	EstMatchedRows float64
+}
+
+// Selectivity returns the observed selectivity of the pushed down filters on the index.
+func (s IndexScanStats) Selectivity() float64 {
+	if s.ScannedRows == 0 {
+		return 1
+	}
+	return float64(s.MatchedRows) / float64(s.ScannedRows)
+}
+
+// EstSelectivity returns the estimated selectivity of the pushed down filters on the index.
+func (s IndexScanStats) EstSelectivity() float64 {
+	if s.EstScannedRows <= 0 {
+		return 1
+	}
+	return s.EstMatchedRows / s.EstScannedRows
+}
+
+// IndexScanFeedback collects the index scan statistics observed by the index readers of a session.
+type IndexScanFeedback struct {
+	mu    sync.Mutex
+	stats map[IndexScanKey]IndexScanStats
+}
+
+// getIndexScanFeedback returns the IndexScanFeedback of the session, it's created if not exists.
+func getIndexScanFeedback(sctx sessionctx.Context) *IndexScanFeedback {
+	if feedback, ok := sctx.Value(IndexScanFeedbackVarKey).(*IndexScanFeedback); ok {
+		return feedback
+	}
+	feedback := &IndexScanFeedback{stats: make(map[IndexScanKey]IndexScanStats)}
+	sctx.SetValue(IndexScanFeedbackVarKey, feedback)
+	return feedback
+}
+
+func (f *IndexScanFeedback) record(key IndexScanKey, stats IndexScanStats) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	total := f.stats[key]
+	total.ScannedRows += stats.ScannedRows
+	total.MatchedRows += stats.MatchedRows
+	total.EstScannedRows += stats.EstScannedRows
+	total.EstMatchedRows += stats.EstMatchedRows
+	f.stats[key] = total
+}
+
+// Snapshot returns a copy of the collected index scan statistics.
+func (f *IndexScanFeedback) Snapshot() map[IndexScanKey]IndexScanStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stats := make(map[IndexScanKey]IndexScanStats, len(f.stats))
+	for key, s := range f.stats {
+		stats[key] = s
+	}
+	return stats
+}
+
+// indexScanFeedbackRecorder records the statistics of the index scan of an IndexReaderExecutor.
+type indexScanFeedbackRecorder struct {
+	feedback   *IndexScanFeedback
+	key        IndexScanKey
+	scanPlanID int
+	estStats   IndexScanStats
+
+	matchedRows int64
+	// scannedRowsBefore is the rows scanned by the previous executions of the
+	// same plan in the statement, like the inner side of the index join.
+	scannedRowsBefore int64
+}
+
+func (r *indexScanFeedbackRecorder) scannedRows(sctx sessionctx.Context) int64 {
+	statsColl := sctx.GetSessionVars().StmtCtx.RuntimeStatsColl
+	if statsColl == nil {
+		return 0
+	}
+	if copStats := statsColl.GetCopStats(r.scanPlanID); copStats != nil {
+		return copStats.GetActRows()
+	}
+	return 0
+}
+
+func (r *indexScanFeedbackRecorder) open(sctx sessionctx.Context) {
+	r.matchedRows = 0
+	r.scannedRowsBefore = r.scannedRows(sctx)
+}
+
+func (r *indexScanFeedbackRecorder) close(sctx sessionctx.Context) {
+	stats := r.estStats
+	// Only the estimations are recorded if the scan isn't observed, like the execution summaries are not collected.
+	if scannedRows := r.scannedRows(sctx) - r.scannedRowsBefore; scannedRows > 0 {
+		stats.ScannedRows, stats.MatchedRows = scannedRows, r.matchedRows
+	}
+	r.feedback.record(r.key, stats)
+}
@@ -15,6 +15,7 @@
 package executor_test
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -198,3 +199,80 @@ txn_dt date default null
 	tk.MustExec("set @@session.tidb_enable_inl_join_inner_multi_pattern='OFF'")
 	tk.MustQuery(sql).Check(testkit.Rows("1 2022-12-01 123 1 2022-12-01 123 1 <nil>"))
 }
+
+func TestIndexAdviseScanFeedback(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int, index idx_ab(a, b))")
+	for i := 0; i < 100; i++ {
+		tk.MustExec("insert into t values (?, ?, ?)", i, i%4, i)
+	}
+	ctx := tk.Session().(sessionctx.Context)
+	defer ctx.SetValue(executor.IndexScanFeedbackVarKey, nil)
+	q := "select a, b from t use index(idx_ab) where a >= 0 and b = 1"
+	require.Len(t, tk.MustQuery(q).Rows(), 25)
+	require.Nil(t, ctx.Value(executor.IndexScanFeedbackVarKey))
+
+	tk.MustExec("set @@tidb_index_advisor_feedback = on")
+	require.Len(t, tk.MustQuery(q).Rows(), 25)
+	require.Len(t, tk.MustQuery(q).Rows(), 25)
+	// The limit is pushed down, the scan isn't recorded.
+	require.Len(t, tk.MustQuery("select a, b from t use index(idx_ab) where a >= 0 and b = 1 limit 1").Rows(), 1)
+
+	tk.MustExec("index advise local infile '/tmp/index_advise.sql'")
+	ia, ok := ctx.Value(executor.IndexAdviseVarKey).(*executor.IndexAdviseInfo)
+	defer ctx.SetValue(executor.IndexAdviseVarKey, nil)
+	require.True(t, ok)
+	require.Len(t, ia.ScanFeedback, 1)
+	stats := ia.ScanFeedback[executor.IndexScanKey{DBName: "test", TableName: "t", IndexName: "idx_ab"}]
+	require.Equal(t, int64(200), stats.ScannedRows)
+	require.Equal(t, int64(50), stats.MatchedRows)
+	require.Equal(t, 0.25, stats.Selectivity())
+	// The table has no statistics, the observed selectivity differs from the pseudo estimation.
+	require.NotEqual(t, stats.Selectivity(), stats.EstSelectivity())
+}
+
+func TestIndexAdviseByObservedSelectivity(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int, index idx_ab(a, b))")
+	for i := 0; i < 100; i++ {
+		tk.MustExec("insert into t values (?, 1, ?)", i, i)
+	}
+	tk.MustExec("set @@tidb_index_advisor_feedback = on")
+	ctx := tk.Session().(sessionctx.Context)
+	defer ctx.SetValue(executor.IndexScanFeedbackVarKey, nil)
+	q := "select a, b from t use index(idx_ab) where a >= 0 and b = 1"
+	workload := []byte(q + ";\nselect * from t2;\n")
+	advise := func() []executor.IndexAdviceItem {
+		tk.MustExec("index advise local infile '/tmp/index_advise.sql'")
+		ia, ok := ctx.Value(executor.IndexAdviseVarKey).(*executor.IndexAdviseInfo)
+		require.True(t, ok)
+		ctx.SetValue(executor.IndexAdviseVarKey, nil)
+		require.NoError(t, ia.GetIndexAdvice(context.Background(), workload))
+		return ia.Result.Items
+	}
+
+	// The scan isn't observed, the pseudo estimation of `b = 1` makes idx_ab inefficient.
+	tk.MustExec("set @@tidb_enable_collect_execution_info = off")
+	require.Len(t, tk.MustQuery(q).Rows(), 100)
+	items := advise()
+	require.Len(t, items, 1)
+	require.Equal(t, executor.IndexScanKey{DBName: "test", TableName: "t", IndexName: "idx_ab"}, items[0].IndexScanKey)
+	require.False(t, items[0].Observed)
+	require.Less(t, items[0].Selectivity, 0.1)
+
+	// All the scanned entries match `b = 1` in the execution, idx_ab isn't advised any more.
+	tk.MustExec("set @@tidb_enable_collect_execution_info = on")
+	require.Len(t, tk.MustQuery(q).Rows(), 100)
+	require.Empty(t, advise())
+
+	// The indexes on the tables out of the workload are not advised.
+	ctx.SetValue(executor.IndexScanFeedbackVarKey, nil)
+	tk.MustExec("set @@tidb_enable_collect_execution_info = off")
+	require.Len(t, tk.MustQuery(q).Rows(), 100)
+	workload = []byte("select * from t2;\n")
+	require.Empty(t, advise())
+}
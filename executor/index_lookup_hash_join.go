@@ -441,6 +441,8 @@ func (e *IndexNestedLoopHashJoin) newInnerWorker(taskCh chan *indexHashJoinTask,
 		rowIter:           chunk.NewIterator4Slice([]chunk.Row{}).(*chunk.Iterator4Slice),
 	}
 	iw.memTracker.AttachTo(e.memTracker)
+	// Every inner worker reuses its own inner reader across the tasks.
+	iw.readerBuilder = e.innerCtx.readerBuilder.withReaderReuse()
 	if len(copiedRanges) != 0 {
 		// We should not consume this memory usage in `iw.memTracker`. The
 		// memory usage of inner worker will be reset the end of iw.handleTask.
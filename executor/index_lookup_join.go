@@ -233,6 +233,8 @@ func (e *IndexLookUpJoin) newInnerWorker(taskCh chan *lookUpJoinTask) *innerWork
 		memTracker:    memory.NewTracker(memory.LabelForIndexJoinInnerWorker, -1),
 	}
 	iw.memTracker.AttachTo(e.memTracker)
+	// Every inner worker reuses its own inner reader across the tasks.
+	iw.readerBuilder = e.innerCtx.readerBuilder.withReaderReuse()
 	if len(copiedRanges) != 0 {
 		// We should not consume this memory usage in `iw.memTracker`. The
 		// memory usage of inner worker will be reset the end of iw.handleTask.
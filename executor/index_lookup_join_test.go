@@ -19,9 +19,11 @@ import (
 	"fmt"
 	"math/rand"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/stretchr/testify/require"
 )
@@ -493,3 +495,118 @@ func TestPartitionTableIndexJoinAndIndexReader(t *testing.T) {
 		tk.MustQuery("select /*+ TIDB_INLJ(t1, t2) */ t1.a from t t1, t t2 where t1.a=t2.b and " + cond).Sort().Check(result)
 	}
 }
+
+func TestIndexJoinPartitionPruningOnce(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_partition_prune_mode='dynamic'")
+	tk.MustExec("create table t (a int, b int, key(a)) partition by hash(a) partitions 16")
+	tk.MustExec("create table tnormal (a int, b int)")
+	nRows := 256
+	values := make([]string, 0, nRows)
+	for i := 0; i < nRows; i++ {
+		values = append(values, fmt.Sprintf("(%v, %v)", i, i))
+	}
+	tk.MustExec(fmt.Sprintf("insert into t values %v", strings.Join(values, ", ")))
+	tk.MustExec(fmt.Sprintf("insert into tnormal values %v", strings.Join(values, ", ")))
+	tk.MustExec("analyze table t, tnormal")
+	tk.MustExec("set @@tidb_index_join_batch_size = 8")
+
+	var pruneCnt atomic.Int64
+	tk.Session().SetValue(executor.TestPartitionPruningHookKey, func() {
+		pruneCnt.Add(1)
+	})
+	defer tk.Session().SetValue(executor.TestPartitionPruningHookKey, nil)
+
+	sql := "select /*+ INL_JOIN(t) */ count(t.b) from tnormal, t where tnormal.b = t.a and t.a < 100"
+	tk.MustQuery(sql).Check(testkit.Rows("100"))
+	require.Equal(t, int64(1), pruneCnt.Load())
+
+	// The inner side is built by a new dataReaderBuilder for every batch under the union scan,
+	// the pruning result is still shared by them.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (1000, 1000)")
+	require.True(t, tk.HasPlan(sql, "UnionScan"))
+	pruneCnt.Store(0)
+	tk.MustQuery(sql).Check(testkit.Rows("100"))
+	require.Equal(t, int64(1), pruneCnt.Load())
+	tk.MustExec("rollback")
+}
+
+func TestIndexJoinReuseInnerReader(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table outer_t (a int, b int, c varchar(10))")
+	tk.MustExec("create table inner_pk (a int primary key, b int)")
+	tk.MustExec("create table inner_idx (a int, b int, key(a, b))")
+	tk.MustExec("create table inner_ch (a varchar(10), b int, primary key(a) clustered)")
+	tk.MustExec("create table inner_part (a int, b int, key(a)) partition by hash(a) partitions 4")
+	nRows := 200
+	values := make([]string, 0, nRows)
+	strValues := make([]string, 0, nRows)
+	for i := 0; i < nRows; i++ {
+		values = append(values, fmt.Sprintf("(%v, %v)", i, i%7))
+		strValues = append(strValues, fmt.Sprintf("('%v', %v)", i, i%7))
+	}
+	tk.MustExec(fmt.Sprintf("insert into outer_t(a, b) values %v", strings.Join(values, ", ")))
+	tk.MustExec("update outer_t set c = cast(a as char)")
+	tk.MustExec(fmt.Sprintf("insert into inner_pk values %v", strings.Join(values, ", ")))
+	tk.MustExec(fmt.Sprintf("insert into inner_idx values %v", strings.Join(values, ", ")))
+	tk.MustExec(fmt.Sprintf("insert into inner_ch values %v", strings.Join(strValues, ", ")))
+	tk.MustExec(fmt.Sprintf("insert into inner_part values %v", strings.Join(values, ", ")))
+	tk.MustExec("analyze table outer_t, inner_pk, inner_idx, inner_ch, inner_part")
+	// Many batches are processed by every inner worker with the reused reader.
+	tk.MustExec("set @@tidb_index_join_batch_size = 4")
+	tk.MustExec("set @@tidb_index_lookup_join_concurrency = 2")
+
+	for _, mode := range []string{"static", "dynamic"} {
+		tk.MustExec(fmt.Sprintf("set @@tidb_partition_prune_mode = '%s'", mode))
+		for _, hint := range []string{"INL_JOIN", "INL_HASH_JOIN"} {
+			for _, tbl := range []string{"inner_pk", "inner_idx", "inner_part"} {
+				sql := fmt.Sprintf("select /*+ %s(%s) */ count(*), sum(%s.b) from outer_t, %s where outer_t.a = %s.a and outer_t.b < 5",
+					hint, tbl, tbl, tbl, tbl)
+				require.True(t, tk.HasPlan(sql, "IndexJoin") || tk.HasPlan(sql, "IndexHashJoin"), sql)
+				tk.MustQuery(sql).Check(testkit.Rows("144 286"))
+			}
+			sql := fmt.Sprintf("select /*+ %s(inner_ch) */ count(*), sum(inner_ch.b) from outer_t, inner_ch where outer_t.c = inner_ch.a and outer_t.b < 5", hint)
+			tk.MustQuery(sql).Check(testkit.Rows("144 286"))
+		}
+	}
+
+	// The rows of the reused reader are not mixed between the batches.
+	tk.MustQuery("select /*+ INL_JOIN(inner_pk) */ outer_t.a, inner_pk.b from outer_t join inner_pk on outer_t.a = inner_pk.a where outer_t.a % 50 = 1 order by outer_t.a").
+		Check(testkit.Rows("1 1", "51 2", "101 3", "151 4"))
+}
+
+func BenchmarkIndexJoinHighFanOut(b *testing.B) {
+	store := testkit.CreateMockStore(b)
+
+	tk := testkit.NewTestKit(b, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table outer_t (a int)")
+	tk.MustExec("create table inner_t (a int primary key, b int)")
+	nRows := 10000
+	for i := 0; i < nRows; i += 1000 {
+		values := make([]string, 0, 1000)
+		for j := i; j < i+1000; j++ {
+			values = append(values, fmt.Sprintf("(%v, %v)", j, j))
+		}
+		tk.MustExec(fmt.Sprintf("insert into inner_t values %v", strings.Join(values, ", ")))
+		tk.MustExec(fmt.Sprintf("insert into outer_t select a from inner_t where a >= %v and a < %v", i, i+1000))
+	}
+	tk.MustExec("analyze table outer_t, inner_t")
+	// Every outer batch looks up a few points, so the cost of building the inner reader dominates.
+	tk.MustExec("set @@tidb_index_join_batch_size = 8")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tk.MustQuery("select /*+ INL_JOIN(inner_t) */ count(*) from outer_t, inner_t where outer_t.a = inner_t.a").
+			Check(testkit.Rows(fmt.Sprint(nRows)))
+	}
+	b.StopTimer()
+}
@@ -15,7 +15,9 @@
 package executor
 
 import (
+	"container/heap"
 	"context"
+	stderrors "errors"
 	"fmt"
 	"runtime/trace"
 	"sync"
@@ -28,16 +30,17 @@ import (
 	"github.com/pingcap/tidb/parser/terror"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/channel"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/ranger"
 	"go.uber.org/zap"
-	"golang.org/x/exp/slices"
 )
 
 // IndexLookUpMergeJoin realizes IndexLookUpJoin by merge join
@@ -52,6 +55,7 @@ type IndexLookUpMergeJoin struct {
 	baseExecutor
 
 	resultCh   <-chan *lookUpMergeJoinTask
+	innerCh    <-chan *lookUpMergeJoinTask
 	cancelFunc context.CancelFunc
 	workerWg   *sync.WaitGroup
 
@@ -72,8 +76,9 @@ type IndexLookUpMergeJoin struct {
 	// lastColHelper store the information for last col if there's complicated filter like col > x_col and col < x_col + 100.
 	lastColHelper *plannercore.ColWithCmpFuncManager
 
-	memTracker *memory.Tracker // track memory usage
-	prepared   bool
+	memTracker  *memory.Tracker // track memory usage
+	diskTracker *disk.Tracker   // track disk usage of the spilled outer rows
+	prepared    bool
 }
 
 type outerMergeCtx struct {
@@ -83,6 +88,12 @@ type outerMergeCtx struct {
 	filter        expression.CNFExprs
 	needOuterSort bool
 	compareFuncs  []expression.CompareFunc
+
+	// sortKeyCols, sortDesc and sortCmpFuncs are used to sort the outer rows
+	// when needOuterSort, see initOuterSortKeys.
+	sortKeyCols  []int
+	sortDesc     []bool
+	sortCmpFuncs []chunk.CompareFunc
 }
 
 type innerMergeCtx struct {
@@ -101,6 +112,10 @@ type lookUpMergeJoinTask struct {
 	outerResult   *chunk.List
 	outerMatch    [][]bool
 	outerOrderIdx []chunk.RowPtr
+	// outerSorted keeps the outer rows instead of outerResult when needOuterSort.
+	// Each container is a sorted run which might be spilled to disk under memory
+	// pressure, and the runs are merged when iterating the outer rows.
+	outerSorted []*chunk.SortedRowContainer
 
 	innerResult *chunk.Chunk
 	innerIter   chunk.Iterator
@@ -131,6 +146,9 @@ type outerMergeWorker struct {
 	innerCh  chan<- *lookUpMergeJoinTask
 
 	parentMemTracker *memory.Tracker
+	// spillAction spills the sorted run being filled when needOuterSort, it's
+	// registered once for the worker.
+	spillAction *outerSortSpillAction
 }
 
 type innerMergeWorker struct {
@@ -163,9 +181,56 @@ func (e *IndexLookUpMergeJoin) Open(ctx context.Context) error {
 	}
 	e.memTracker = memory.NewTracker(e.id, -1)
 	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	if e.outerMergeCtx.needOuterSort {
+		e.diskTracker = disk.NewTracker(e.id, -1)
+		e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+		e.initOuterSortKeys()
+	}
 	return nil
 }
 
+// initOuterSortKeys initializes the keys to sort the outer rows. The outer rows are
+// compared by the compare functions of join keys and lastColHelper, the same as the
+// order of inner rows, so the key columns passed by SortedRowContainer are ignored.
+func (e *IndexLookUpMergeJoin) initOuterSortKeys() {
+	omc := &e.outerMergeCtx
+	keyLen := len(e.innerMergeCtx.keyOff2KeyOffOrderByIdx)
+	omc.sortKeyCols = make([]int, 0, keyLen+1)
+	omc.sortCmpFuncs = make([]chunk.CompareFunc, 0, keyLen+1)
+	for _, keyOff := range e.innerMergeCtx.keyOff2KeyOffOrderByIdx {
+		joinKey, cmpFunc := omc.joinKeys[keyOff], omc.compareFuncs[keyOff]
+		omc.sortKeyCols = append(omc.sortKeyCols, joinKey.Index)
+		omc.sortCmpFuncs = append(omc.sortCmpFuncs, func(rowI chunk.Row, _ int, rowJ chunk.Row, _ int) int {
+			cmp, _, err := cmpFunc(e.ctx, joinKey, joinKey, rowI, rowJ)
+			terror.Log(err)
+			return int(cmp)
+		})
+	}
+	if lastColHelper := e.lastColHelper; lastColHelper != nil {
+		omc.sortKeyCols = append(omc.sortKeyCols, -1)
+		omc.sortCmpFuncs = append(omc.sortCmpFuncs, func(rowI chunk.Row, _ int, rowJ chunk.Row, _ int) int {
+			return lastColHelper.CompareRow(rowI, rowJ)
+		})
+	}
+	omc.sortDesc = make([]bool, len(omc.sortKeyCols))
+	for i := range omc.sortDesc {
+		omc.sortDesc[i] = e.innerMergeCtx.desc
+	}
+}
+
+func (omc *outerMergeCtx) lessRow(rowI, rowJ chunk.Row) bool {
+	for i, cmpFunc := range omc.sortCmpFuncs {
+		cmp := cmpFunc(rowI, omc.sortKeyCols[i], rowJ, omc.sortKeyCols[i])
+		if omc.sortDesc[i] {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp < 0
+		}
+	}
+	return false
+}
+
 func (e *IndexLookUpMergeJoin) startWorkers(ctx context.Context) {
 	// TODO: consider another session currency variable for index merge join.
 	// Because its parallelization is not complete.
@@ -188,6 +253,7 @@ func (e *IndexLookUpMergeJoin) startWorkers(ctx context.Context) {
 	workerCtx, cancelFunc := context.WithCancel(ctx)
 	e.cancelFunc = cancelFunc
 	innerCh := make(chan *lookUpMergeJoinTask, concurrency)
+	e.innerCh = innerCh
 	e.workerWg.Add(1)
 	go e.newOuterWorker(resultCh, innerCh).run(workerCtx, e.workerWg, e.cancelFunc)
 	e.workerWg.Add(concurrency)
@@ -306,9 +372,19 @@ func (omw *outerMergeWorker) run(ctx context.Context, wg *sync.WaitGroup, cancel
 		close(omw.innerCh)
 		wg.Done()
 	}()
+	if omw.needOuterSort && variable.EnableTmpStorageOnOOM.Load() {
+		omw.spillAction = &outerSortSpillAction{}
+		omw.ctx.GetSessionVars().MemTracker.FallbackOldAndSetNewAction(omw.spillAction)
+		defer omw.spillAction.SetFinished()
+	}
 	for {
 		task, err := omw.buildTask(ctx)
+		if omw.spillAction != nil {
+			// The runs handed to the inner workers are not spilled by the worker.
+			omw.spillAction.setRowContainer(nil)
+		}
 		if err != nil {
+			task.closeOuterSorted()
 			task.doneErr = err
 			close(task.results)
 			omw.pushToChan(ctx, task, omw.resultCh)
@@ -320,6 +396,7 @@ func (omw *outerMergeWorker) run(ctx context.Context, wg *sync.WaitGroup, cancel
 		}
 
 		if finished := omw.pushToChan(ctx, task, omw.innerCh); finished {
+			task.closeOuterSorted()
 			return
 		}
 
@@ -366,18 +443,82 @@ func (omw *outerMergeWorker) buildTask(ctx context.Context) (*lookUpMergeJoinTas
 			break
 		}
 
-		task.outerResult.Add(execChk)
+		if omw.needOuterSort {
+			if err = omw.addOuterSortedRows(task, execChk); err != nil {
+				return task, err
+			}
+		} else {
+			task.outerResult.Add(execChk)
+			task.memTracker.Consume(execChk.MemoryUsage())
+		}
 		requiredRows -= execChk.NumRows()
-		task.memTracker.Consume(execChk.MemoryUsage())
 	}
 
-	if task.outerResult.Len() == 0 {
+	if task.outerResult.Len() == 0 && len(task.outerSorted) == 0 {
 		return nil, nil
 	}
 
 	return task, nil
 }
 
+// addOuterSortedRows adds the outer rows into the last sorted run of the task. If
+// the last run has been sorted and spilled to disk, a new run is started.
+func (omw *outerMergeWorker) addOuterSortedRows(task *lookUpMergeJoinTask, chk *chunk.Chunk) error {
+	if n := len(task.outerSorted); n > 0 {
+		err := task.outerSorted[n-1].Add(chk)
+		if !stderrors.Is(err, chunk.ErrCannotAddBecauseSorted) {
+			return err
+		}
+	}
+	rowContainer := chunk.NewSortedRowContainer(omw.rowTypes, omw.executor.base().maxChunkSize, omw.sortDesc, omw.sortKeyCols, omw.sortCmpFuncs)
+	rowContainer.GetMemTracker().AttachTo(task.memTracker)
+	rowContainer.GetMemTracker().SetLabel(memory.LabelForRowChunks)
+	if omw.spillAction != nil {
+		rowContainer.GetDiskTracker().AttachTo(omw.lookup.diskTracker)
+		rowContainer.GetDiskTracker().SetLabel(memory.LabelForRowChunks)
+		omw.spillAction.setRowContainer(rowContainer)
+	}
+	task.outerSorted = append(task.outerSorted, rowContainer)
+	return rowContainer.Add(chk)
+}
+
+// outerSortSpillAction implements memory.ActionOnExceed for the outer worker of
+// IndexLookUpMergeJoin. It sorts and spills the run being filled by the worker
+// to disk, so the action is registered once instead of once per sorted run.
+type outerSortSpillAction struct {
+	memory.BaseOOMAction
+	mu           sync.Mutex
+	rowContainer *chunk.SortedRowContainer
+}
+
+func (a *outerSortSpillAction) setRowContainer(rowContainer *chunk.SortedRowContainer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rowContainer = rowContainer
+}
+
+// Action sorts and spills the run being filled, and calls the fallback action if
+// there is no such run or the run has been spilled.
+func (a *outerSortSpillAction) Action(t *memory.Tracker) {
+	a.mu.Lock()
+	rowContainer := a.rowContainer
+	a.mu.Unlock()
+	if rowContainer == nil {
+		if fallback := a.GetFallback(); fallback != nil {
+			fallback.Action(t)
+		}
+		return
+	}
+	spillAction := rowContainer.ActionSpill()
+	spillAction.SetFallback(a.GetFallback())
+	spillAction.Action(t)
+}
+
+// GetPriority implements the memory.ActionOnExceed interface.
+func (*outerSortSpillAction) GetPriority() int64 {
+	return memory.DefSpillPriority
+}
+
 func (omw *outerMergeWorker) increaseBatchSize() {
 	if omw.batchSize < omw.maxBatchSize {
 		omw.batchSize *= 2
@@ -419,27 +560,6 @@ func (imw *innerMergeWorker) run(ctx context.Context, wg *sync.WaitGroup, cancel
 }
 
 func (imw *innerMergeWorker) handleTask(ctx context.Context, task *lookUpMergeJoinTask) (err error) {
-	numOuterChks := task.outerResult.NumChunks()
-	if imw.outerMergeCtx.filter != nil {
-		task.outerMatch = make([][]bool, numOuterChks)
-		for i := 0; i < numOuterChks; i++ {
-			chk := task.outerResult.GetChunk(i)
-			task.outerMatch[i] = make([]bool, chk.NumRows())
-			task.outerMatch[i], err = expression.VectorizedFilter(imw.ctx, imw.outerMergeCtx.filter, chunk.NewIterator4Chunk(chk), task.outerMatch[i])
-			if err != nil {
-				return err
-			}
-		}
-	}
-	task.memTracker.Consume(int64(cap(task.outerMatch)))
-	task.outerOrderIdx = make([]chunk.RowPtr, 0, task.outerResult.Len())
-	for i := 0; i < numOuterChks; i++ {
-		numRow := task.outerResult.GetChunk(i).NumRows()
-		for j := 0; j < numRow; j++ {
-			task.outerOrderIdx = append(task.outerOrderIdx, chunk.RowPtr{ChkIdx: uint32(i), RowIdx: uint32(j)})
-		}
-	}
-	task.memTracker.Consume(int64(cap(task.outerOrderIdx)))
 	failpoint.Inject("IndexMergeJoinMockOOM", func(val failpoint.Value) {
 		if val.(bool) {
 			panic("OOM test index merge join doesn't hang here.")
@@ -449,24 +569,11 @@ func (imw *innerMergeWorker) handleTask(ctx context.Context, task *lookUpMergeJo
 	// Because the necessary condition of merge join is both outer and inner keep order of join keys.
 	// In this case, we need sort the outer side.
 	if imw.outerMergeCtx.needOuterSort {
-		slices.SortFunc(task.outerOrderIdx, func(idxI, idxJ chunk.RowPtr) bool {
-			rowI, rowJ := task.outerResult.GetRow(idxI), task.outerResult.GetRow(idxJ)
-			var cmp int64
-			var err error
-			for _, keyOff := range imw.keyOff2KeyOffOrderByIdx {
-				joinKey := imw.outerMergeCtx.joinKeys[keyOff]
-				cmp, _, err = imw.outerMergeCtx.compareFuncs[keyOff](imw.ctx, joinKey, joinKey, rowI, rowJ)
-				terror.Log(err)
-				if cmp != 0 {
-					break
-				}
-			}
-			if cmp != 0 || imw.nextColCompareFilters == nil {
-				return (cmp < 0 && !imw.desc) || (cmp > 0 && imw.desc)
-			}
-			cmp = int64(imw.nextColCompareFilters.CompareRow(rowI, rowJ))
-			return (cmp < 0 && !imw.desc) || (cmp > 0 && imw.desc)
-		})
+		defer task.closeOuterSorted()
+		// The runs except the last one have been sorted when they were spilled.
+		task.outerSorted[len(task.outerSorted)-1].Sort()
+	} else if err = imw.initOuterOrderIdx(task); err != nil {
+		return err
 	}
 	dLookUpKeys, err := imw.constructDatumLookupKeys(task)
 	if err != nil {
@@ -496,6 +603,106 @@ func (imw *innerMergeWorker) handleTask(ctx context.Context, task *lookUpMergeJo
 	return err
 }
 
+// initOuterOrderIdx filters the outer rows kept in outerResult and initializes their order.
+func (imw *innerMergeWorker) initOuterOrderIdx(task *lookUpMergeJoinTask) (err error) {
+	numOuterChks := task.outerResult.NumChunks()
+	if imw.outerMergeCtx.filter != nil {
+		task.outerMatch = make([][]bool, numOuterChks)
+		for i := 0; i < numOuterChks; i++ {
+			chk := task.outerResult.GetChunk(i)
+			task.outerMatch[i] = make([]bool, chk.NumRows())
+			task.outerMatch[i], err = expression.VectorizedFilter(imw.ctx, imw.outerMergeCtx.filter, chunk.NewIterator4Chunk(chk), task.outerMatch[i])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	task.memTracker.Consume(int64(cap(task.outerMatch)))
+	task.outerOrderIdx = make([]chunk.RowPtr, 0, task.outerResult.Len())
+	for i := 0; i < numOuterChks; i++ {
+		numRow := task.outerResult.GetChunk(i).NumRows()
+		for j := 0; j < numRow; j++ {
+			task.outerOrderIdx = append(task.outerOrderIdx, chunk.RowPtr{ChkIdx: uint32(i), RowIdx: uint32(j)})
+		}
+	}
+	task.memTracker.Consume(int64(cap(task.outerOrderIdx)))
+	return nil
+}
+
+// outerRowIter iterates the outer rows of a task in the order of join keys.
+type outerRowIter struct {
+	imw  *innerMergeWorker
+	task *lookUpMergeJoinTask
+	// cursor is the position in task.outerOrderIdx when the outer rows are kept in outerResult.
+	cursor int
+	// merge merges the sorted runs when the outer rows are kept in outerSorted.
+	merge *multiWayMerge
+}
+
+func (imw *innerMergeWorker) newOuterRowIter(task *lookUpMergeJoinTask) (*outerRowIter, error) {
+	iter := &outerRowIter{imw: imw, task: task}
+	if !imw.outerMergeCtx.needOuterSort {
+		return iter, nil
+	}
+	iter.merge = &multiWayMerge{imw.outerMergeCtx.lessRow, make([]partitionPointer, 0, len(task.outerSorted))}
+	for i, rowContainer := range task.outerSorted {
+		if rowContainer.NumRow() == 0 {
+			continue
+		}
+		row, err := rowContainer.GetSortedRow(0)
+		if err != nil {
+			return nil, err
+		}
+		iter.merge.elements = append(iter.merge.elements, partitionPointer{row: row, partitionID: i})
+	}
+	heap.Init(iter.merge)
+	return iter, nil
+}
+
+// next returns the next outer row and whether it passes the outer filter,
+// ok is false if all the outer rows have been iterated.
+func (iter *outerRowIter) next() (row chunk.Row, match bool, ok bool, err error) {
+	if iter.merge == nil {
+		if iter.cursor >= len(iter.task.outerOrderIdx) {
+			return row, false, false, nil
+		}
+		idx := iter.task.outerOrderIdx[iter.cursor]
+		iter.cursor++
+		match = iter.task.outerMatch == nil || iter.task.outerMatch[idx.ChkIdx][idx.RowIdx]
+		return iter.task.outerResult.GetRow(idx), match, true, nil
+	}
+	if iter.merge.Len() == 0 {
+		return row, false, false, nil
+	}
+	partitionPtr := iter.merge.elements[0]
+	row = partitionPtr.row
+	partitionPtr.consumed++
+	rowContainer := iter.task.outerSorted[partitionPtr.partitionID]
+	if partitionPtr.consumed >= rowContainer.NumRow() {
+		heap.Remove(iter.merge, 0)
+	} else {
+		if partitionPtr.row, err = rowContainer.GetSortedRow(partitionPtr.consumed); err != nil {
+			return row, false, false, err
+		}
+		iter.merge.elements[0] = partitionPtr
+		heap.Fix(iter.merge, 0)
+	}
+	match = true
+	if filter := iter.imw.outerMergeCtx.filter; filter != nil {
+		if match, _, err = expression.EvalBool(iter.imw.ctx, filter, row); err != nil {
+			return row, false, false, err
+		}
+	}
+	return row, match, true, nil
+}
+
+func (task *lookUpMergeJoinTask) closeOuterSorted() {
+	for _, rowContainer := range task.outerSorted {
+		terror.Log(rowContainer.Close())
+	}
+	task.outerSorted = nil
+}
+
 func (imw *innerMergeWorker) fetchNewChunkWhenFull(ctx context.Context, task *lookUpMergeJoinTask, chk **chunk.Chunk) (continueJoin bool) {
 	if !(*chk).IsFull() {
 		return true
@@ -546,10 +753,20 @@ func (imw *innerMergeWorker) doMergeJoin(ctx context.Context, task *lookUpMergeJ
 	}
 	noneInnerRowsRemain := task.innerResult.NumRows() == 0
 
-	for _, outerIdx := range task.outerOrderIdx {
-		outerRow := task.outerResult.GetRow(outerIdx)
+	outerIter, err := imw.newOuterRowIter(task)
+	if err != nil {
+		return err
+	}
+	for {
+		outerRow, outerMatch, ok, err := outerIter.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
 		hasMatch, hasNull, cmpResult := false, false, initCmpResult
-		if task.outerMatch != nil && !task.outerMatch[outerIdx.ChkIdx][outerIdx.RowIdx] {
+		if !outerMatch {
 			goto missMatch
 		}
 		// If it has iterated out all inner rows and the inner rows with same key is empty,
@@ -632,10 +849,23 @@ func (imw *innerMergeWorker) compare(outerRow, innerRow chunk.Row) (int, error)
 }
 
 func (imw *innerMergeWorker) constructDatumLookupKeys(task *lookUpMergeJoinTask) ([]*indexJoinLookUpContent, error) {
-	numRows := len(task.outerOrderIdx)
-	dLookUpKeys := make([]*indexJoinLookUpContent, 0, numRows)
-	for i := 0; i < numRows; i++ {
-		dLookUpKey, err := imw.constructDatumLookupKey(task, task.outerOrderIdx[i])
+	outerIter, err := imw.newOuterRowIter(task)
+	if err != nil {
+		return nil, err
+	}
+	var dLookUpKeys []*indexJoinLookUpContent
+	for {
+		outerRow, outerMatch, ok, err := outerIter.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		if !outerMatch {
+			continue
+		}
+		dLookUpKey, err := imw.constructDatumLookupKey(outerRow)
 		if err != nil {
 			return nil, err
 		}
@@ -648,11 +878,7 @@ func (imw *innerMergeWorker) constructDatumLookupKeys(task *lookUpMergeJoinTask)
 	return dLookUpKeys, nil
 }
 
-func (imw *innerMergeWorker) constructDatumLookupKey(task *lookUpMergeJoinTask, idx chunk.RowPtr) (*indexJoinLookUpContent, error) {
-	if task.outerMatch != nil && !task.outerMatch[idx.ChkIdx][idx.RowIdx] {
-		return nil, nil
-	}
-	outerRow := task.outerResult.GetRow(idx)
+func (imw *innerMergeWorker) constructDatumLookupKey(outerRow chunk.Row) (*indexJoinLookUpContent, error) {
 	sc := imw.ctx.GetSessionVars().StmtCtx
 	keyLen := len(imw.keyCols)
 	dLookupKey := make([]types.Datum, 0, keyLen)
@@ -686,7 +912,7 @@ func (imw *innerMergeWorker) constructDatumLookupKey(task *lookUpMergeJoinTask,
 		}
 		dLookupKey = append(dLookupKey, innerValue)
 	}
-	return &indexJoinLookUpContent{keys: dLookupKey, row: task.outerResult.GetRow(idx)}, nil
+	return &indexJoinLookUpContent{keys: dLookupKey, row: outerRow}, nil
 }
 
 func (imw *innerMergeWorker) dedupDatumLookUpKeys(lookUpContents []*indexJoinLookUpContent) []*indexJoinLookUpContent {
@@ -731,7 +957,15 @@ func (e *IndexLookUpMergeJoin) Close() error {
 	// resultCh is the main thread get the results, used by main thread and inner worker.
 	// cancelFunc control the outer worker and outer worker close the task channel.
 	e.workerWg.Wait()
+	// The tasks which are not handled by inner workers still hold their outer rows.
+	if e.innerCh != nil {
+		for task := range e.innerCh {
+			task.closeOuterSorted()
+		}
+		e.innerCh = nil
+	}
 	e.memTracker = nil
+	e.diskTracker = nil
 	e.prepared = false
 	return e.baseExecutor.Close()
 }
@@ -15,6 +15,7 @@
 package executor_test
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -228,3 +229,42 @@ func TestIssue24473AndIssue25669(t *testing.T) {
 	tk.MustQuery("SELECT /*+ inl_merge_join (t2,t3) */ t2.a,t3.a FROM x t2 inner join x t3 on t2.a = t3.a;").Sort().Check(
 		testkit.Rows("b b", "x x", "x x", "x x", "x x", "y y"))
 }
+
+func TestIndexLookUpMergeJoinOuterSortSpill(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (id int primary key, b int, pad varchar(100))")
+	tk.MustExec("create table t2 (a int, b int, index idx(a))")
+	values := make([]string, 0, 5000)
+	for i := 0; i < 5000; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d, '%s')", i, i*7%1000, strings.Repeat("x", 100)))
+	}
+	tk.MustExec("insert into t1 values " + strings.Join(values, ","))
+	values = values[:0]
+	for i := 0; i < 1000; i += 3 {
+		values = append(values, fmt.Sprintf("(%d, %d)", i, i))
+	}
+	tk.MustExec("insert into t2 values " + strings.Join(values, ","))
+	tk.MustExec("set @@tidb_index_lookup_join_concurrency = 1")
+	tk.MustExec("set @@tidb_index_join_batch_size = 5000")
+
+	// The outer side is ordered by t1.id, so it must be sorted by t1.b before merging.
+	query := "select /*+ INL_MERGE_JOIN(t2) */ t1.id, t1.b, t2.b from t1 left join t2 on t1.b = t2.a where t1.b > 10 order by t1.id"
+	hashJoinQuery := strings.Replace(query, "INL_MERGE_JOIN", "HASH_JOIN", 1)
+	expected := tk.MustQuery(hashJoinQuery).Rows()
+	tk.MustQuery(query).Check(expected)
+
+	tk.MustExec("set @@tidb_mem_quota_query = 200000")
+	tk.MustQuery(query).Check(expected)
+	rows := tk.MustQuery("explain analyze " + query).Rows()
+	var found bool
+	for _, row := range rows {
+		if strings.Contains(row[0].(string), plancodec.TypeIndexMergeJoin) {
+			found = true
+			require.NotEqual(t, "N/A", row[len(row)-1].(string))
+		}
+	}
+	require.True(t, found)
+}
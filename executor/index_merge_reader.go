@@ -149,7 +149,7 @@ type indexMergeTableTask struct {
 	// parTblIdx are only used in indexMergeProcessWorker.fetchLoopIntersection.
 	parTblIdx int
 
-	// partialPlanID are only used for indexMergeProcessWorker.fetchLoopUnionWithOrderByAndPushedLimit.
+	// partialPlanID are only used for indexMergeProcessWorker.fetchLoopUnionWithOrderBy.
 	partialPlanID int
 }
 
@@ -251,27 +251,48 @@ func (e *IndexMergeReaderExecutor) buildKeyRangesForTable(tbl table.Table) (rang
 
 func (e *IndexMergeReaderExecutor) startWorkers(ctx context.Context) error {
 	exitCh := make(chan struct{})
+	var exitOnce sync.Once
+	// stopPartialWorkers may be called by both the process worker and the error handling below.
+	stopPartialWorkers := func() {
+		exitOnce.Do(func() { close(exitCh) })
+	}
 	workCh := make(chan *indexMergeTableTask, 1)
 	fetchCh := make(chan *indexMergeTableTask, len(e.keyRanges))
+	// The ordered union merges the partial streams, so every partial worker has its own fetch channel.
+	var partialFetchChs []chan *indexMergeTableTask
+	if e.isOrderedUnion() {
+		partialFetchChs = make([]chan *indexMergeTableTask, len(e.partialPlans))
+		for i := range partialFetchChs {
+			partialFetchChs[i] = make(chan *indexMergeTableTask, 1)
+		}
+	}
 
-	e.startIndexMergeProcessWorker(ctx, workCh, fetchCh)
+	e.startIndexMergeProcessWorker(ctx, workCh, fetchCh, partialFetchChs, stopPartialWorkers)
 
 	var err error
 	for i := 0; i < len(e.partialPlans); i++ {
+		partialFetchCh := fetchCh
+		if partialFetchChs != nil {
+			partialFetchCh = partialFetchChs[i]
+		}
 		e.idxWorkerWg.Add(1)
 		if e.indexes[i] != nil {
-			err = e.startPartialIndexWorker(ctx, exitCh, fetchCh, i)
+			err = e.startPartialIndexWorker(ctx, exitCh, partialFetchCh, i)
 		} else {
-			err = e.startPartialTableWorker(ctx, exitCh, fetchCh, i)
+			err = e.startPartialTableWorker(ctx, exitCh, partialFetchCh, i)
 		}
 		if err != nil {
 			e.idxWorkerWg.Done()
+			// The partial workers which are not started close their fetch channels here.
+			for j := i; j < len(partialFetchChs); j++ {
+				close(partialFetchChs[j])
+			}
 			break
 		}
 	}
 	go e.waitPartialWorkersAndCloseFetchChan(fetchCh)
 	if err != nil {
-		close(exitCh)
+		stopPartialWorkers()
 		return err
 	}
 	e.startIndexMergeTableScanWorker(ctx, workCh)
@@ -284,10 +305,17 @@ func (e *IndexMergeReaderExecutor) waitPartialWorkersAndCloseFetchChan(fetchCh c
 	close(fetchCh)
 }
 
-func (e *IndexMergeReaderExecutor) startIndexMergeProcessWorker(ctx context.Context, workCh chan<- *indexMergeTableTask, fetch <-chan *indexMergeTableTask) {
+// isOrderedUnion returns whether the union type IndexMerge should output the rows in the order of byItems.
+func (e *IndexMergeReaderExecutor) isOrderedUnion() bool {
+	return !e.isIntersection && e.keepOrder && len(e.byItems) != 0
+}
+
+func (e *IndexMergeReaderExecutor) startIndexMergeProcessWorker(ctx context.Context, workCh chan<- *indexMergeTableTask, fetch <-chan *indexMergeTableTask,
+	partialFetchChs []chan *indexMergeTableTask, stopPartialWorkers func()) {
 	idxMergeProcessWorker := &indexMergeProcessWorker{
-		indexMerge: e,
-		stats:      e.stats,
+		indexMerge:         e,
+		stats:              e.stats,
+		stopPartialWorkers: stopPartialWorkers,
 	}
 	e.processWorkerWg.Add(1)
 	go func() {
@@ -296,8 +324,8 @@ func (e *IndexMergeReaderExecutor) startIndexMergeProcessWorker(ctx context.Cont
 			func() {
 				if e.isIntersection {
 					idxMergeProcessWorker.fetchLoopIntersection(ctx, fetch, workCh, e.resultCh, e.finished)
-				} else if e.pushedLimit != nil && len(e.byItems) != 0 {
-					idxMergeProcessWorker.fetchLoopUnionWithOrderByAndPushedLimit(ctx, fetch, workCh, e.resultCh, e.finished)
+				} else if e.isOrderedUnion() {
+					idxMergeProcessWorker.fetchLoopUnionWithOrderBy(ctx, partialFetchChs, workCh, e.resultCh, e.finished)
 				} else {
 					idxMergeProcessWorker.fetchLoopUnion(ctx, fetch, workCh, e.resultCh, e.finished)
 				}
@@ -336,6 +364,10 @@ func (e *IndexMergeReaderExecutor) startPartialIndexWorker(ctx context.Context,
 	go func() {
 		defer trace.StartRegion(ctx, "IndexMergePartialIndexWorker").End()
 		defer e.idxWorkerWg.Done()
+		if e.isOrderedUnion() {
+			// The fetch channel is only used by this worker in the ordered union.
+			defer close(fetchCh)
+		}
 		util.WithRecovery(
 			func() {
 				failpoint.Inject("testIndexMergePanicPartialIndexWorker", nil)
@@ -407,7 +439,7 @@ func (e *IndexMergeReaderExecutor) startPartialIndexWorker(ctx context.Context,
 						syncErr(ctx, e.finished, fetchCh, err)
 						return
 					}
-					result, err := distsql.SelectWithRuntimeStats(ctx, e.ctx, kvReq, tps, e.feedbacks[workID], getPhysicalPlanIDs(e.partialPlans[workID]), e.getPartitalPlanID(workID))
+					result, err := distsql.SelectWithRuntimeStats(withCopStoreBreaker(ctx, kvReq), e.ctx, kvReq, tps, e.feedbacks[workID], getPhysicalPlanIDs(e.partialPlans[workID]), e.getPartitalPlanID(workID))
 					if err != nil {
 						syncErr(ctx, e.finished, fetchCh, err)
 						return
@@ -451,6 +483,10 @@ func (e *IndexMergeReaderExecutor) startPartialTableWorker(ctx context.Context,
 	go func() {
 		defer trace.StartRegion(ctx, "IndexMergePartialTableWorker").End()
 		defer e.idxWorkerWg.Done()
+		if e.isOrderedUnion() {
+			// The fetch channel is only used by this worker in the ordered union.
+			defer close(fetchCh)
+		}
 		util.WithRecovery(
 			func() {
 				failpoint.Inject("testIndexMergePanicPartialTableWorker", nil)
@@ -510,10 +546,12 @@ func (e *IndexMergeReaderExecutor) startPartialTableWorker(ctx context.Context,
 					}
 				}()
 				for parTblIdx, tbl := range tbls {
-					// check if this executor is closed
+					// check if this executor is closed or the partial workers are stopped
 					select {
 					case <-ctx.Done():
 						return
+					case <-exitCh:
+						return
 					case <-e.finished:
 						return
 					default:
@@ -887,34 +925,40 @@ func (e *IndexMergeReaderExecutor) Close() error {
 type indexMergeProcessWorker struct {
 	indexMerge *IndexMergeReaderExecutor
 	stats      *IndexMergeRuntimeStat
+	// stopPartialWorkers cancels the outstanding requests of the partial workers
+	// once the union has collected enough handles for the pushed limit.
+	stopPartialWorkers func()
 }
 
-type rowIdx struct {
-	partialID int
-	taskID    int
-	rowID     int
+// orderedPartialStream is the handle stream of one partial plan, it's used by
+// indexMergeProcessWorker.fetchLoopUnionWithOrderBy to merge the ordered partial
+// streams. task is the task being merged and rowID is the next row in it.
+type orderedPartialStream struct {
+	fetchCh <-chan *indexMergeTableTask
+	task    *indexMergeTableTask
+	rowID   int
+	memUsed int64
 }
 
-type handleHeap struct {
-	requiredCnt uint64
-	taskMap     map[int][]*indexMergeTableTask
-
-	idx         []rowIdx
+// orderedMergeHeap is a min-heap of partial streams ordered by their next rows,
+// the order is decided by byItems.
+type orderedMergeHeap struct {
+	streams     []*orderedPartialStream
 	compareFunc []chunk.CompareFunc
 	byItems     []*plannerutil.ByItems
 }
 
-func (h handleHeap) Len() int {
-	return len(h.idx)
+func (h orderedMergeHeap) Len() int {
+	return len(h.streams)
 }
 
-func (h handleHeap) Less(i, j int) bool {
-	rowI := h.taskMap[h.idx[i].partialID][h.idx[i].taskID].idxRows.GetRow(h.idx[i].rowID)
-	rowJ := h.taskMap[h.idx[j].partialID][h.idx[j].taskID].idxRows.GetRow(h.idx[j].rowID)
+func (h orderedMergeHeap) Less(i, j int) bool {
+	rowI := h.streams[i].task.idxRows.GetRow(h.streams[i].rowID)
+	rowJ := h.streams[j].task.idxRows.GetRow(h.streams[j].rowID)
 
-	for i, compFunc := range h.compareFunc {
-		cmp := compFunc(rowI, i, rowJ, i)
-		if !h.byItems[i].Desc {
+	for k, compFunc := range h.compareFunc {
+		cmp := compFunc(rowI, k, rowJ, k)
+		if h.byItems[k].Desc {
 			cmp = -cmp
 		}
 		if cmp < 0 {
@@ -923,35 +967,32 @@ func (h handleHeap) Less(i, j int) bool {
 			return false
 		}
 	}
-	return false
+	// Make the merge stable, rows of the same order are output by the partial plan order.
+	return h.streams[i].task.partialPlanID < h.streams[j].task.partialPlanID
 }
 
-func (h handleHeap) Swap(i, j int) {
-	h.idx[i], h.idx[j] = h.idx[j], h.idx[i]
+func (h orderedMergeHeap) Swap(i, j int) {
+	h.streams[i], h.streams[j] = h.streams[j], h.streams[i]
 }
 
-func (h *handleHeap) Push(x interface{}) {
-	idx := x.(rowIdx)
-	h.idx = append(h.idx, idx)
+func (h *orderedMergeHeap) Push(x interface{}) {
+	h.streams = append(h.streams, x.(*orderedPartialStream))
 }
 
-func (h *handleHeap) Pop() interface{} {
-	idxRet := h.idx[len(h.idx)-1]
-	h.idx = h.idx[:len(h.idx)-1]
-	return idxRet
+func (h *orderedMergeHeap) Pop() interface{} {
+	ret := h.streams[len(h.streams)-1]
+	h.streams = h.streams[:len(h.streams)-1]
+	return ret
 }
 
-func (w *indexMergeProcessWorker) NewHandleHeap(taskMap map[int][]*indexMergeTableTask) *handleHeap {
+func (w *indexMergeProcessWorker) newOrderedMergeHeap(partialCnt int) *orderedMergeHeap {
 	compareFuncs := make([]chunk.CompareFunc, 0, len(w.indexMerge.byItems))
 	for _, item := range w.indexMerge.byItems {
 		keyType := item.Expr.GetType()
 		compareFuncs = append(compareFuncs, chunk.GetCompareFunc(keyType))
 	}
-	requiredCnt := w.indexMerge.pushedLimit.Count + w.indexMerge.pushedLimit.Offset
-	return &handleHeap{
-		requiredCnt: requiredCnt,
-		taskMap:     taskMap,
-		idx:         make([]rowIdx, 0, requiredCnt),
+	return &orderedMergeHeap{
+		streams:     make([]*orderedPartialStream, 0, partialCnt),
 		compareFunc: compareFuncs,
 		byItems:     w.indexMerge.byItems,
 	}
@@ -976,7 +1017,13 @@ func (w *indexMergeProcessWorker) pruneTableWorkerTaskIdxRows(task *indexMergeTa
 	}
 }
 
-func (w *indexMergeProcessWorker) fetchLoopUnionWithOrderByAndPushedLimit(ctx context.Context, fetchCh <-chan *indexMergeTableTask,
+// fetchLoopUnionWithOrderBy merges the ordered handle streams of all partial plans
+// by byItems. All the partial workers fetch concurrently and send their tasks to
+// their own fetchCh, so the merge only waits for a partial plan when the next row
+// of that plan is required. A handle returned by several partial plans is only
+// output once. If the limit is pushed down, the merge stops after Offset+Count
+// distinct handles are found.
+func (w *indexMergeProcessWorker) fetchLoopUnionWithOrderBy(ctx context.Context, fetchChs []chan *indexMergeTableTask,
 	workCh chan<- *indexMergeTableTask, resultCh chan<- *indexMergeTableTask, finished <-chan struct{}) {
 	memTracker := memory.NewTracker(w.indexMerge.id, -1)
 	memTracker.AttachTo(w.indexMerge.memTracker)
@@ -990,99 +1037,140 @@ func (w *indexMergeProcessWorker) fetchLoopUnionWithOrderByAndPushedLimit(ctx co
 		}()
 	}
 
+	var offset, required uint64
+	if limit := w.indexMerge.pushedLimit; limit != nil {
+		offset, required = limit.Offset, limit.Count
+		if required == 0 {
+			return
+		}
+	}
+
+	mergeHeap := w.newOrderedMergeHeap(len(fetchChs))
+	for _, fetchCh := range fetchChs {
+		stream := &orderedPartialStream{fetchCh: fetchCh}
+		if !w.nextOrderedTask(ctx, stream, memTracker, resultCh, finished) {
+			return
+		}
+		if stream.task != nil {
+			mergeHeap.streams = append(mergeHeap.streams, stream)
+		}
+	}
+	heap.Init(mergeHeap)
+
+	batchSize := w.indexMerge.ctx.GetSessionVars().IndexLookupSize
 	distinctHandles := kv.NewHandleMap()
-	taskMap := make(map[int][]*indexMergeTableTask)
-	uselessMap := make(map[int]struct{})
-	taskHeap := w.NewHandleHeap(taskMap)
-	memTracker.Consume(int64(taskHeap.requiredCnt) * int64(unsafe.Sizeof(rowIdx{0, 0, 0})))
+	fhs := make([]kv.Handle, 0, batchSize)
+	var skipped, output uint64
+	for mergeHeap.Len() > 0 {
+		stream := mergeHeap.streams[0]
+		h := stream.task.handles[stream.rowID]
+		if _, ok := distinctHandles.Get(h); !ok {
+			distinctHandles.Set(h, true)
+			memTracker.Consume(int64(h.MemUsage()))
+			if skipped < offset {
+				skipped++
+			} else {
+				fhs = append(fhs, h)
+				output++
+			}
+		}
+		if required > 0 && output >= required {
+			w.stopPartialWorkers()
+			break
+		}
+		if len(fhs) >= batchSize {
+			if !w.sendOrderedTask(ctx, fhs, workCh, resultCh, finished) {
+				return
+			}
+			fhs = make([]kv.Handle, 0, batchSize)
+		}
 
-	for task := range fetchCh {
+		stream.rowID++
+		if stream.rowID >= len(stream.task.handles) {
+			if !w.nextOrderedTask(ctx, stream, memTracker, resultCh, finished) {
+				return
+			}
+			if stream.task == nil {
+				heap.Pop(mergeHeap)
+				continue
+			}
+		}
+		heap.Fix(mergeHeap, 0)
+	}
+	if len(fhs) > 0 {
+		w.sendOrderedTask(ctx, fhs, workCh, resultCh, finished)
+	}
+}
+
+// nextOrderedTask receives the next non-empty task of the partial stream, the
+// stream.task is set to nil if the partial plan has no more handles. It returns
+// false if the process worker should stop.
+func (w *indexMergeProcessWorker) nextOrderedTask(ctx context.Context, stream *orderedPartialStream, memTracker *memory.Tracker,
+	resultCh chan<- *indexMergeTableTask, finished <-chan struct{}) bool {
+	memTracker.Consume(-stream.memUsed)
+	stream.task, stream.rowID, stream.memUsed = nil, 0, 0
+	for {
+		var task *indexMergeTableTask
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return false
+		case <-finished:
+			return false
+		case task, ok = <-stream.fetchCh:
+			if !ok {
+				return true
+			}
+		}
 		select {
 		case err := <-task.doneCh:
 			// If got error from partialIndexWorker/partialTableWorker, stop processing.
 			if err != nil {
 				syncErr(ctx, finished, resultCh, err)
-				return
+				return false
 			}
 		default:
 		}
-		if _, ok := uselessMap[task.partialPlanID]; ok {
+		if len(task.handles) == 0 {
 			continue
 		}
-		if _, ok := taskMap[task.partialPlanID]; !ok {
-			taskMap[task.partialPlanID] = make([]*indexMergeTableTask, 0)
-		}
 		w.pruneTableWorkerTaskIdxRows(task)
-		taskMap[task.partialPlanID] = append(taskMap[task.partialPlanID], task)
-		for i, h := range task.handles {
-			if _, ok := distinctHandles.Get(h); !ok {
-				distinctHandles.Set(h, true)
-				heap.Push(taskHeap, rowIdx{task.partialPlanID, len(taskMap[task.partialPlanID]) - 1, i})
-				if taskHeap.Len() > int(taskHeap.requiredCnt) {
-					top := heap.Pop(taskHeap).(rowIdx)
-					if top.partialID == task.partialPlanID && top.taskID == len(taskMap[task.partialPlanID])-1 && top.rowID == i {
-						uselessMap[task.partialPlanID] = struct{}{}
-						task.handles = task.handles[:i]
-						break
-					}
-				}
-			}
-			memTracker.Consume(int64(h.MemUsage()))
-		}
-		memTracker.Consume(task.idxRows.MemoryUsage())
-		if len(uselessMap) == len(w.indexMerge.partialPlans) {
-			// consume reset tasks
-			go func() {
-				for range fetchCh {
-				}
-			}()
-			break
-		}
+		stream.task = task
+		stream.memUsed = task.idxRows.MemoryUsage() + int64(cap(task.handles)*8)
+		memTracker.Consume(stream.memUsed)
+		return true
 	}
+}
 
-	needCount := mathutil.Max(0, taskHeap.Len()-int(w.indexMerge.pushedLimit.Offset))
-	if needCount == 0 {
-		return
-	}
-	fhs := make([]kv.Handle, needCount)
-	for i := needCount - 1; i >= 0; i-- {
-		idx := heap.Pop(taskHeap).(rowIdx)
-		fhs[i] = taskMap[idx.partialID][idx.taskID].handles[idx.rowID]
+// sendOrderedTask sends the handles to the table scan workers, the table scan
+// workers keep the rows in the handle order by indexOrder.
+func (w *indexMergeProcessWorker) sendOrderedTask(ctx context.Context, handles []kv.Handle,
+	workCh chan<- *indexMergeTableTask, resultCh chan<- *indexMergeTableTask, finished <-chan struct{}) bool {
+	// Save the index order.
+	indexOrder := kv.NewHandleMap()
+	for i, h := range handles {
+		indexOrder.Set(h, i)
 	}
-
-	batchSize := w.indexMerge.ctx.GetSessionVars().IndexLookupSize
-	tasks := make([]*indexMergeTableTask, 0, len(fhs)/batchSize+1)
-	for len(fhs) > 0 {
-		l := mathutil.Min(len(fhs), batchSize)
-		// Save the index order.
-		indexOrder := kv.NewHandleMap()
-		for i, h := range fhs[:l] {
-			indexOrder.Set(h, i)
-		}
-		tasks = append(tasks, &indexMergeTableTask{
-			lookupTableTask: lookupTableTask{
-				handles:    fhs[:l],
-				indexOrder: indexOrder,
-				doneCh:     make(chan error, 1),
-			},
-		})
-		fhs = fhs[l:]
+	task := &indexMergeTableTask{
+		lookupTableTask: lookupTableTask{
+			handles:    handles,
+			indexOrder: indexOrder,
+			doneCh:     make(chan error, 1),
+		},
 	}
-	for _, task := range tasks {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-finished:
+		return false
+	case workCh <- task:
 		select {
 		case <-ctx.Done():
-			return
+			return false
 		case <-finished:
-			return
-		case workCh <- task:
-			select {
-			case <-ctx.Done():
-				return
-			case <-finished:
-				return
-			case resultCh <- task:
-				continue
-			}
+			return false
+		case resultCh <- task:
+			return true
 		}
 	}
 }
@@ -1098,6 +1186,16 @@ func (w *indexMergeProcessWorker) fetchLoopUnion(ctx context.Context, fetchCh <-
 	defer close(workCh)
 	failpoint.Inject("testIndexMergePanicProcessWorkerUnion", nil)
 
+	// remaining is the number of distinct handles still required by the pushed limit,
+	// every handle is output as a row since there are no filters on the table side.
+	var remaining uint64
+	limited := w.indexMerge.pushedLimit != nil
+	if limited {
+		remaining = w.indexMerge.pushedLimit.Offset + w.indexMerge.pushedLimit.Count
+		if remaining == 0 {
+			return
+		}
+	}
 	distinctHandles := make(map[int64]*kv.HandleMap)
 	for {
 		var ok bool
@@ -1143,6 +1241,12 @@ func (w *indexMergeProcessWorker) fetchLoopUnion(ctx context.Context, fetchCh <-
 			if _, ok := hMap.Get(h); !ok {
 				fhs = append(fhs, h)
 				hMap.Set(h, true)
+				if limited {
+					remaining--
+					if remaining == 0 {
+						break
+					}
+				}
 			}
 		}
 		if len(fhs) == 0 {
@@ -1181,6 +1285,10 @@ func (w *indexMergeProcessWorker) fetchLoopUnion(ctx context.Context, fetchCh <-
 			case resultCh <- task:
 			}
 		}
+		if limited && remaining == 0 {
+			w.stopPartialWorkers()
+			return
+		}
 	}
 }
 
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/deadlock"
 	"github.com/pingcap/kvproto/pkg/kvrpcpb"
+	"github.com/pingcap/kvproto/pkg/metapb"
 	rmpb "github.com/pingcap/kvproto/pkg/resource_manager"
 	"github.com/pingcap/tidb/ddl/label"
 	"github.com/pingcap/tidb/ddl/placement"
@@ -76,9 +77,11 @@ import (
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/stringutil"
 	"github.com/pingcap/tidb/util/syncutil"
+	"github.com/tikv/client-go/v2/oracle"
 	"github.com/tikv/client-go/v2/tikv"
 	"github.com/tikv/client-go/v2/tikvrpc"
 	"github.com/tikv/client-go/v2/txnkv/txnlock"
+	tikvutil "github.com/tikv/client-go/v2/util"
 	"go.uber.org/zap"
 	"golang.org/x/exp/slices"
 )
@@ -92,6 +95,8 @@ type memtableRetriever struct {
 	retrieved   bool
 	initialized bool
 	extractor   plannercore.MemTablePredicateExtractor
+	// resultLimiter checks the rows of the tables in all the schemas while they're produced.
+	resultLimiter *memTableResultLimiter
 }
 
 // retrieve implements the infoschemaRetriever interface
@@ -111,15 +116,15 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 		var err error
 		switch e.table.Name.O {
 		case infoschema.TableSchemata:
-			e.setDataFromSchemata(sctx, dbs)
+			err = e.setDataFromSchemata(sctx, dbs)
 		case infoschema.TableStatistics:
-			e.setDataForStatistics(sctx, dbs)
+			err = e.setDataForStatistics(sctx, dbs)
 		case infoschema.TableTables:
 			err = e.setDataFromTables(ctx, sctx, dbs)
 		case infoschema.TableReferConst:
 			err = e.setDataFromReferConst(ctx, sctx, dbs)
 		case infoschema.TableSequences:
-			e.setDataFromSequences(sctx, dbs)
+			err = e.setDataFromSequences(sctx, dbs)
 		case infoschema.TablePartitions:
 			err = e.setDataFromPartitions(ctx, sctx, dbs)
 		case infoschema.TableClusterInfo:
@@ -127,9 +132,9 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 		case infoschema.TableAnalyzeStatus:
 			err = e.setDataForAnalyzeStatus(ctx, sctx)
 		case infoschema.TableTiDBIndexes:
-			e.setDataFromIndexes(sctx, dbs)
+			err = e.setDataFromIndexes(sctx, dbs)
 		case infoschema.TableViews:
-			e.setDataFromViews(sctx, dbs)
+			err = e.setDataFromViews(sctx, dbs)
 		case infoschema.TableEngines:
 			e.setDataFromEngines()
 		case infoschema.TableCharacterSets:
@@ -137,7 +142,7 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 		case infoschema.TableCollations:
 			e.setDataFromCollations()
 		case infoschema.TableKeyColumn:
-			e.setDataFromKeyColumnUsage(sctx, dbs)
+			err = e.setDataFromKeyColumnUsage(sctx, dbs)
 		case infoschema.TableMetricTables:
 			e.setDataForMetricTables(sctx)
 		case infoschema.TableProfiling:
@@ -155,13 +160,13 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 		case infoschema.TableTiDBHotRegions:
 			err = e.setDataForTiDBHotRegions(sctx)
 		case infoschema.TableConstraints:
-			e.setDataFromTableConstraints(sctx, dbs)
+			err = e.setDataFromTableConstraints(sctx, dbs)
 		case infoschema.TableSessionVar:
 			e.rows, err = infoschema.GetDataFromSessionVariables(ctx, sctx)
 		case infoschema.TableTiDBServersInfo:
 			err = e.setDataForServersInfo(sctx)
 		case infoschema.TableTiFlashReplica:
-			e.dataForTableTiFlashReplica(sctx, dbs)
+			err = e.dataForTableTiFlashReplica(sctx, dbs)
 		case infoschema.TableTiKVStoreStatus:
 			err = e.dataForTiKVStoreStatus(sctx)
 		case infoschema.TableClientErrorsSummaryGlobal,
@@ -191,6 +196,10 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 		case infoschema.TableResourceGroups:
 			err = e.setDataFromResourceGroups()
 		}
+		if err == nil {
+			// The rows of the other tables are checked once they're all produced.
+			err = e.resultLimiter.checkRows(e.rows)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -308,11 +317,14 @@ func (e *memtableRetriever) setDataForUserAttributes(ctx context.Context, sctx s
 	return nil
 }
 
-func (e *memtableRetriever) setDataFromSchemata(ctx sessionctx.Context, schemas []*model.DBInfo) {
+func (e *memtableRetriever) setDataFromSchemata(ctx sessionctx.Context, schemas []*model.DBInfo) error {
 	checker := privilege.GetPrivilegeManager(ctx)
 	rows := make([][]types.Datum, 0, len(schemas))
 
 	for _, schema := range schemas {
+		if err := e.resultLimiter.checkRows(rows); err != nil {
+			return err
+		}
 		charset := mysql.DefaultCharset
 		collation := mysql.DefaultCollationName
 
@@ -342,18 +354,23 @@ func (e *memtableRetriever) setDataFromSchemata(ctx sessionctx.Context, schemas
 		rows = append(rows, record)
 	}
 	e.rows = rows
+	return nil
 }
 
-func (e *memtableRetriever) setDataForStatistics(ctx sessionctx.Context, schemas []*model.DBInfo) {
+func (e *memtableRetriever) setDataForStatistics(ctx sessionctx.Context, schemas []*model.DBInfo) error {
 	checker := privilege.GetPrivilegeManager(ctx)
 	for _, schema := range schemas {
 		for _, table := range schema.Tables {
+			if err := e.resultLimiter.checkRows(e.rows); err != nil {
+				return err
+			}
 			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, table.Name.L, "", mysql.AllPrivMask) {
 				continue
 			}
 			e.setDataForStatisticsInTable(schema, table)
 		}
 	}
+	return nil
 }
 
 func (e *memtableRetriever) setDataForStatisticsInTable(schema *model.DBInfo, table *model.TableInfo) {
@@ -446,6 +463,9 @@ func (e *memtableRetriever) setDataFromReferConst(ctx context.Context, sctx sess
 	var rows [][]types.Datum
 	for _, schema := range schemas {
 		for _, table := range schema.Tables {
+			if err := e.resultLimiter.checkRows(rows); err != nil {
+				return err
+			}
 			if !table.IsBaseTable() {
 				continue
 			}
@@ -497,6 +517,9 @@ func (e *memtableRetriever) setDataFromTables(ctx context.Context, sctx sessionc
 	}
 	for _, schema := range schemas {
 		for _, table := range schema.Tables {
+			if err := e.resultLimiter.checkRows(rows); err != nil {
+				return err
+			}
 			collation := table.Collate
 			if collation == "" {
 				collation = mysql.DefaultCollationName
@@ -876,6 +899,9 @@ func (e *memtableRetriever) setDataFromPartitions(ctx context.Context, sctx sess
 	createTimeTp := mysql.TypeDatetime
 	for _, schema := range schemas {
 		for _, table := range schema.Tables {
+			if err := e.resultLimiter.checkRows(rows); err != nil {
+				return err
+			}
 			if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, schema.Name.L, table.Name.L, "", mysql.SelectPriv) {
 				continue
 			}
@@ -1023,11 +1049,14 @@ func (e *memtableRetriever) setDataFromPartitions(ctx context.Context, sctx sess
 	return nil
 }
 
-func (e *memtableRetriever) setDataFromIndexes(ctx sessionctx.Context, schemas []*model.DBInfo) {
+func (e *memtableRetriever) setDataFromIndexes(ctx sessionctx.Context, schemas []*model.DBInfo) error {
 	checker := privilege.GetPrivilegeManager(ctx)
 	var rows [][]types.Datum
 	for _, schema := range schemas {
 		for _, tb := range schema.Tables {
+			if err := e.resultLimiter.checkRows(rows); err != nil {
+				return err
+			}
 			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, tb.Name.L, "", mysql.AllPrivMask) {
 				continue
 			}
@@ -1105,13 +1134,17 @@ func (e *memtableRetriever) setDataFromIndexes(ctx sessionctx.Context, schemas [
 		}
 	}
 	e.rows = rows
+	return nil
 }
 
-func (e *memtableRetriever) setDataFromViews(ctx sessionctx.Context, schemas []*model.DBInfo) {
+func (e *memtableRetriever) setDataFromViews(ctx sessionctx.Context, schemas []*model.DBInfo) error {
 	checker := privilege.GetPrivilegeManager(ctx)
 	var rows [][]types.Datum
 	for _, schema := range schemas {
 		for _, table := range schema.Tables {
+			if err := e.resultLimiter.checkRows(rows); err != nil {
+				return err
+			}
 			if !table.IsView() {
 				continue
 			}
@@ -1142,6 +1175,7 @@ func (e *memtableRetriever) setDataFromViews(ctx sessionctx.Context, schemas []*
 		}
 	}
 	e.rows = rows
+	return nil
 }
 
 func (e *memtableRetriever) dataForTiKVStoreStatus(ctx sessionctx.Context) (err error) {
@@ -1388,11 +1422,14 @@ func (e *memtableRetriever) dataForTiDBClusterInfo(ctx sessionctx.Context) error
 	return nil
 }
 
-func (e *memtableRetriever) setDataFromKeyColumnUsage(ctx sessionctx.Context, schemas []*model.DBInfo) {
+func (e *memtableRetriever) setDataFromKeyColumnUsage(ctx sessionctx.Context, schemas []*model.DBInfo) error {
 	checker := privilege.GetPrivilegeManager(ctx)
 	rows := make([][]types.Datum, 0, len(schemas)) // The capacity is not accurate, but it is not a big problem.
 	for _, schema := range schemas {
 		for _, table := range schema.Tables {
+			if err := e.resultLimiter.checkRows(rows); err != nil {
+				return err
+			}
 			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, table.Name.L, "", mysql.AllPrivMask) {
 				continue
 			}
@@ -1401,6 +1438,7 @@ func (e *memtableRetriever) setDataFromKeyColumnUsage(ctx sessionctx.Context, sc
 		}
 	}
 	e.rows = rows
+	return nil
 }
 
 func (e *memtableRetriever) setDataForClusterProcessList(ctx sessionctx.Context) error {
@@ -1748,11 +1786,14 @@ func (e *memtableRetriever) setDataForHotRegionByMetrics(metrics []helper.HotTab
 }
 
 // setDataFromTableConstraints constructs data for table information_schema.constraints.See https://dev.mysql.com/doc/refman/5.7/en/table-constraints-table.html
-func (e *memtableRetriever) setDataFromTableConstraints(ctx sessionctx.Context, schemas []*model.DBInfo) {
+func (e *memtableRetriever) setDataFromTableConstraints(ctx sessionctx.Context, schemas []*model.DBInfo) error {
 	checker := privilege.GetPrivilegeManager(ctx)
 	var rows [][]types.Datum
 	for _, schema := range schemas {
 		for _, tbl := range schema.Tables {
+			if err := e.resultLimiter.checkRows(rows); err != nil {
+				return err
+			}
 			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, tbl.Name.L, "", mysql.AllPrivMask) {
 				continue
 			}
@@ -1806,6 +1847,7 @@ func (e *memtableRetriever) setDataFromTableConstraints(ctx sessionctx.Context,
 		}
 	}
 	e.rows = rows
+	return nil
 }
 
 // tableStorageStatsRetriever is used to read slow log data.
@@ -2188,11 +2230,14 @@ func (e *memtableRetriever) setDataForServersInfo(ctx sessionctx.Context) error
 	return nil
 }
 
-func (e *memtableRetriever) setDataFromSequences(ctx sessionctx.Context, schemas []*model.DBInfo) {
+func (e *memtableRetriever) setDataFromSequences(ctx sessionctx.Context, schemas []*model.DBInfo) error {
 	checker := privilege.GetPrivilegeManager(ctx)
 	var rows [][]types.Datum
 	for _, schema := range schemas {
 		for _, table := range schema.Tables {
+			if err := e.resultLimiter.checkRows(rows); err != nil {
+				return err
+			}
 			if !table.IsSequence() {
 				continue
 			}
@@ -2216,14 +2261,18 @@ func (e *memtableRetriever) setDataFromSequences(ctx sessionctx.Context, schemas
 		}
 	}
 	e.rows = rows
+	return nil
 }
 
 // dataForTableTiFlashReplica constructs data for table tiflash replica info.
-func (e *memtableRetriever) dataForTableTiFlashReplica(ctx sessionctx.Context, schemas []*model.DBInfo) {
+func (e *memtableRetriever) dataForTableTiFlashReplica(ctx sessionctx.Context, schemas []*model.DBInfo) error {
 	var rows [][]types.Datum
 	var tiFlashStores map[int64]helper.StoreStat
 	for _, schema := range schemas {
 		for _, tbl := range schema.Tables {
+			if err := e.resultLimiter.checkRows(rows); err != nil {
+				return err
+			}
 			if tbl.TiFlashReplica == nil {
 				continue
 			}
@@ -2259,6 +2308,7 @@ func (e *memtableRetriever) dataForTableTiFlashReplica(ctx sessionctx.Context, s
 		}
 	}
 	e.rows = rows
+	return nil
 }
 
 func (e *memtableRetriever) setDataForClientErrorsSummary(ctx sessionctx.Context, tableName string) error {
@@ -2531,6 +2581,289 @@ func (e *tidbTrxTableRetriever) retrieve(ctx context.Context, sctx sessionctx.Co
 	return res, nil
 }
 
+// resourceUsageRetriever is the memtable retriever for the CONNECTION_RESOURCE_USAGE table.
+type resourceUsageRetriever struct {
+	dummyCloser
+	batchRetrieverHelper
+	table       *model.TableInfo
+	columns     []*model.ColumnInfo
+	extractor   *plannercore.ConnectionResourceUsageExtractor
+	processes   []*util.ProcessInfo
+	initialized bool
+}
+
+func (e *resourceUsageRetriever) retrieve(_ context.Context, sctx sessionctx.Context) ([][]types.Datum, error) {
+	if e.retrieved {
+		return nil, nil
+	}
+
+	if !e.initialized {
+		e.initialized = true
+
+		sm := sctx.GetSessionManager()
+		if sm == nil || e.extractor.SkipRequest {
+			e.retrieved = true
+			return nil, nil
+		}
+
+		loginUser := sctx.GetSessionVars().User
+		hasProcessPriv := hasPriv(sctx, mysql.ProcessPriv)
+		pl := sm.ShowProcessList()
+		var connIDs map[uint64]struct{}
+		if len(e.extractor.ConnectionIDs) > 0 {
+			connIDs = make(map[uint64]struct{}, len(e.extractor.ConnectionIDs))
+			for _, id := range e.extractor.ConnectionIDs {
+				connIDs[id] = struct{}{}
+			}
+		}
+		e.processes = make([]*util.ProcessInfo, 0, len(pl))
+		for _, pi := range pl {
+			if connIDs != nil {
+				if _, ok := connIDs[pi.ID]; !ok {
+					continue
+				}
+			}
+			// If you have the PROCESS privilege, you can see all connections.
+			// Otherwise, you can see only your own connections.
+			if !hasProcessPriv && loginUser != nil && pi.User != loginUser.Username {
+				continue
+			}
+			e.processes = append(e.processes, pi)
+		}
+		slices.SortFunc(e.processes, func(i, j *util.ProcessInfo) bool {
+			return i.ID < j.ID
+		})
+
+		e.batchRetrieverHelper.totalRows = len(e.processes)
+		e.batchRetrieverHelper.batchSize = 1024
+	}
+
+	var res [][]types.Datum
+	err := e.nextBatch(func(start, end int) error {
+		res = make([][]types.Datum, 0, end-start)
+		for _, pi := range e.processes[start:end] {
+			var stmtRU, totalRU float64
+			if pi.RUUsage != nil {
+				stmtRU, totalRU = pi.RUUsage.StmtRU(), pi.RUUsage.TotalRU()
+			}
+			var maxMem int64
+			if pi.MemTracker != nil {
+				maxMem = pi.MemTracker.MaxConsumed()
+			}
+			res = append(res, types.MakeDatums(
+				pi.ID,                // ID
+				pi.User,              // USER
+				pi.Host,              // HOST
+				pi.ResourceGroupName, // RESOURCE_GROUP
+				stmtRU,               // STMT_RU
+				totalRU,              // TOTAL_RU
+				maxMem,               // STMT_MAX_MEM
+			))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return adjustColumns(res, e.columns, e.table), nil
+}
+
+// gcStatusKeys are the keys of the GC status which the GC worker saves in mysql.tidb.
+var gcStatusKeys = []string{
+	"tikv_gc_safe_point",
+	"tikv_gc_last_run_time",
+	"tikv_gc_life_time",
+	"tikv_gc_run_interval",
+	"tikv_gc_leader_desc",
+}
+
+// tikvGCStatusRetriever is the memtable retriever for the TIKV_GC_STATUS table.
+type tikvGCStatusRetriever struct {
+	dummyCloser
+	table     *model.TableInfo
+	columns   []*model.ColumnInfo
+	retrieved bool
+}
+
+func (e *tikvGCStatusRetriever) retrieve(ctx context.Context, sctx sessionctx.Context) ([][]types.Datum, error) {
+	if e.retrieved {
+		return nil, nil
+	}
+	e.retrieved = true
+	if !hasPriv(sctx, mysql.ProcessPriv) {
+		return nil, plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+	tikvStore, ok := sctx.GetStore().(tikv.Storage)
+	if !ok {
+		return nil, errors.Errorf("%T is not a TiKV store instance", sctx.GetStore())
+	}
+	pdClient := tikvStore.GetRegionCache().PDClient()
+	if pdClient == nil {
+		return nil, errors.New("pd unavailable")
+	}
+	stores, err := pdClient.GetAllStores(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Only the TiKV stores which are up run GC with the safe point.
+	upStores := 0
+	for _, store := range stores {
+		if store.GetState() == metapb.StoreState_Up && tikvrpc.GetStoreTypeByMeta(store) == tikvrpc.TiKV {
+			upStores++
+		}
+	}
+	// The GC worker saves the safe point after updating it in PD, read it rather than the PD one to avoid
+	// touching the GC state.
+	var storeSafePoint uint64
+	if str, err := tikvStore.GetSafePointKV().Get(tikv.GcSavedSafePoint); err != nil {
+		return nil, errors.Trace(err)
+	} else if str != "" {
+		if storeSafePoint, err = strconv.ParseUint(str, 10, 64); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	kctx := kv.WithInternalSourceType(ctx, kv.InternalTxnGC)
+	chunkRows, _, err := exec.ExecRestrictedSQL(kctx, nil, "SELECT HIGH_PRIORITY variable_name, variable_value FROM mysql.tidb WHERE variable_name IN (%?)", gcStatusKeys)
+	if err != nil {
+		return nil, err
+	}
+	status := make(map[string]string, len(chunkRows))
+	for _, row := range chunkRows {
+		status[row.GetString(0)] = row.GetString(1)
+	}
+	tz := sctx.GetSessionVars().Location()
+	gcTime := func(key string) interface{} {
+		t, err := tikvutil.CompatibleParseGCTime(status[key])
+		if err != nil {
+			return nil
+		}
+		return types.NewTime(types.FromGoTime(t.In(tz)), mysql.TypeDatetime, 0)
+	}
+	gcValue := func(key string) interface{} {
+		if v, ok := status[key]; ok {
+			return v
+		}
+		return nil
+	}
+	var storeSafePointTime interface{}
+	if storeSafePoint > 0 {
+		storeSafePointTime = types.NewTime(types.FromGoTime(oracle.GetTimeFromTS(storeSafePoint).In(tz)), mysql.TypeDatetime, 0)
+	}
+
+	rows := [][]types.Datum{types.MakeDatums(
+		gcTime("tikv_gc_safe_point"),    // SAFE_POINT
+		storeSafePointTime,              // STORE_SAFE_POINT
+		gcTime("tikv_gc_last_run_time"), // LAST_RUN_TIME
+		gcValue("tikv_gc_life_time"),    // LIFE_TIME
+		gcValue("tikv_gc_run_interval"), // RUN_INTERVAL
+		gcValue("tikv_gc_leader_desc"),  // LEADER
+		upStores,                        // UP_STORES
+	)}
+	return adjustColumns(rows, e.columns, e.table), nil
+}
+
+// sessionTemporaryTablesRetriever is the memtable retriever for the SESSION_TEMPORARY_TABLES table.
+type sessionTemporaryTablesRetriever struct {
+	dummyCloser
+	table     *model.TableInfo
+	columns   []*model.ColumnInfo
+	retrieved bool
+}
+
+func (e *sessionTemporaryTablesRetriever) retrieve(_ context.Context, sctx sessionctx.Context) ([][]types.Datum, error) {
+	if e.retrieved {
+		return nil, nil
+	}
+	e.retrieved = true
+	sessVars := sctx.GetSessionVars()
+	if sessVars.LocalTemporaryTables == nil {
+		return nil, nil
+	}
+	localTempTables := sessVars.LocalTemporaryTables.(*infoschema.SessionTables)
+	tempTableData := sessVars.TemporaryTableData
+
+	schemas := localTempTables.SchemaNames()
+	slices.SortFunc(schemas, func(i, j model.CIStr) bool {
+		return i.L < j.L
+	})
+	rows := make([][]types.Datum, 0, localTempTables.Count())
+	for _, schema := range schemas {
+		tbls := localTempTables.SchemaTables(schema)
+		slices.SortFunc(tbls, func(i, j table.Table) bool {
+			return i.Meta().Name.L < j.Meta().Name.L
+		})
+		for _, tbl := range tbls {
+			tblInfo := tbl.Meta()
+			var rowCount, memUsage int64
+			if tempTableData != nil {
+				var err error
+				if rowCount, err = countTemporaryTableRows(tempTableData, tblInfo.ID); err != nil {
+					return nil, err
+				}
+				memUsage = tempTableData.GetTableSize(tblInfo.ID)
+			}
+			rows = append(rows, types.MakeDatums(
+				schema.O,       // TABLE_SCHEMA
+				tblInfo.Name.O, // TABLE_NAME
+				tblInfo.ID,     // TIDB_TABLE_ID
+				rowCount,       // TABLE_ROWS
+				memUsage,       // MEMORY_USAGE
+			))
+		}
+	}
+	return adjustColumns(rows, e.columns, e.table), nil
+}
+
+// countTemporaryTableRows counts the committed rows of the local temporary table.
+func countTemporaryTableRows(tempTableData variable.TemporaryTableData, tblID int64) (int64, error) {
+	start := tablecodec.GenTableRecordPrefix(tblID)
+	iter, err := tempTableData.Iter(start, start.PrefixNext())
+	if err != nil {
+		return 0, err
+	}
+	defer iter.Close()
+	var count int64
+	for iter.Valid() {
+		// The deleted rows are kept in the memory buffer with empty values.
+		if len(iter.Value()) > 0 {
+			count++
+		}
+		if err = iter.Next(); err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+// lastUpdateColumnStatsRetriever is the memtable retriever for the LAST_UPDATE_COLUMN_STATS table.
+type lastUpdateColumnStatsRetriever struct {
+	dummyCloser
+	table     *model.TableInfo
+	columns   []*model.ColumnInfo
+	retrieved bool
+}
+
+func (e *lastUpdateColumnStatsRetriever) retrieve(_ context.Context, sctx sessionctx.Context) ([][]types.Datum, error) {
+	if e.retrieved {
+		return nil, nil
+	}
+	e.retrieved = true
+	stats := sctx.GetSessionVars().LastUpdateColumnStats
+	rows := make([][]types.Datum, 0, len(stats))
+	for _, stat := range stats {
+		rows = append(rows, types.MakeDatums(
+			stat.DBName,       // TABLE_SCHEMA
+			stat.TableName,    // TABLE_NAME
+			stat.ColumnName,   // COLUMN_NAME
+			stat.AssignedRows, // ASSIGNED_ROWS
+			stat.ChangedRows,  // CHANGED_ROWS
+		))
+	}
+	return adjustColumns(rows, e.columns, e.table), nil
+}
+
 // dataLockWaitsTableRetriever is the memtable retriever for the DATA_LOCK_WAITS table.
 type dataLockWaitsTableRetriever struct {
 	dummyCloser
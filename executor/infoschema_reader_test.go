@@ -25,17 +25,21 @@ import (
 
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/kvrpcpb"
+	rmpb "github.com/pingcap/kvproto/pkg/resource_manager"
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/pingcap/tidb/util/stringutil"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
 	"github.com/tikv/client-go/v2/tikv"
 	"github.com/tikv/client-go/v2/tikvrpc"
+	rmclient "github.com/tikv/pd/client/resource_group/controller"
 )
 
 func TestInspectionTables(t *testing.T) {
@@ -910,3 +914,145 @@ func newGetTiFlashSystemTableRequestMocker(t *testing.T) *getTiFlashSystemTableR
 		t:        t,
 	}
 }
+
+type mockRUInterceptor struct{}
+
+func (mockRUInterceptor) OnRequestWait(context.Context, string, rmclient.RequestInfo) (*rmpb.Consumption, *rmpb.Consumption, error) {
+	return &rmpb.Consumption{RRU: 1}, nil, nil
+}
+
+func (mockRUInterceptor) OnResponse(string, rmclient.RequestInfo, rmclient.ResponseInfo) (*rmpb.Consumption, error) {
+	return &rmpb.Consumption{RRU: 1}, nil
+}
+
+func TestConnectionResourceUsage(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set global tidb_enable_resource_control = 'on'")
+	tikv.EnableResourceControl()
+	tikv.SetResourceControlInterceptor(mockRUInterceptor{})
+	defer func() {
+		tikv.SetResourceControlInterceptor(nil)
+		tikv.DisableResourceControl()
+		tk.MustExec("set global tidb_enable_resource_control = default")
+	}()
+	tk.MustExec("create resource group rg1 ru_per_sec = 1000")
+
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+	tk1.MustExec("set resource group rg1")
+	sm := &testkit.MockSessionManager{
+		Conn: map[uint64]session.Session{
+			tk.Session().GetSessionVars().ConnectionID:  tk.Session(),
+			tk1.Session().GetSessionVars().ConnectionID: tk1.Session(),
+		},
+	}
+	tk.Session().SetSessionManager(sm)
+	connID := tk1.Session().GetSessionVars().ConnectionID
+
+	tk1.MustExec("create table t(a int primary key, b int)")
+	tk1.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	tk1.MustQuery("select * from t t1 join t t2 on t1.a = t2.b order by t1.b").Check(testkit.Rows("1 1 1 1", "2 2 2 2", "3 3 3 3"))
+	rows := tk.MustQuery(fmt.Sprintf("select resource_group, stmt_ru, total_ru, stmt_max_mem from information_schema.connection_resource_usage where id = %d", connID)).Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, "rg1", rows[0][0])
+	stmtRU, err := strconv.ParseFloat(rows[0][1].(string), 64)
+	require.NoError(t, err)
+	require.Greater(t, stmtRU, 0.0)
+	totalRU, err := strconv.ParseFloat(rows[0][2].(string), 64)
+	require.NoError(t, err)
+	require.Greater(t, totalRU, stmtRU)
+	maxMem, err := strconv.ParseInt(rows[0][3].(string), 10, 64)
+	require.NoError(t, err)
+	require.Greater(t, maxMem, int64(0))
+
+	// The read committed statement reads with its own ts instead of the start ts of the transaction.
+	tk1.MustExec("set tx_isolation = 'READ-COMMITTED'")
+	tk1.MustExec("begin pessimistic")
+	tk1.MustQuery("select * from t where b = 1").Check(testkit.Rows("1 1"))
+	tk1.MustQuery("select * from t where b = 2").Check(testkit.Rows("2 2"))
+	rows = tk.MustQuery(fmt.Sprintf("select stmt_ru from information_schema.connection_resource_usage where id = %d", connID)).Rows()
+	stmtRU, err = strconv.ParseFloat(rows[0][0].(string), 64)
+	require.NoError(t, err)
+	require.Greater(t, stmtRU, 0.0)
+	tk1.MustExec("commit")
+
+	// The session without resource group doesn't collect RU.
+	tk.MustQuery(fmt.Sprintf("select stmt_ru, total_ru from information_schema.connection_resource_usage where id = %d", tk.Session().GetSessionVars().ConnectionID)).
+		Check(testkit.Rows("0 0"))
+	tk.MustQuery("select count(*) from information_schema.connection_resource_usage").Check(testkit.Rows("2"))
+	tk.MustQuery("select count(*) from information_schema.connection_resource_usage where id = 'a'").Check(testkit.Rows("0"))
+	tk.MustQuery(fmt.Sprintf("explain select * from information_schema.connection_resource_usage where id = %d", connID)).
+		CheckContain(fmt.Sprintf("ids:[%d]", connID))
+}
+
+func TestTiKVGCStatus(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set time_zone = '+00:00'")
+	tk.MustExec("delete from mysql.tidb where variable_name in ('tikv_gc_safe_point', 'tikv_gc_last_run_time', 'tikv_gc_life_time', 'tikv_gc_run_interval', 'tikv_gc_leader_desc')")
+	tk.MustExec(`insert into mysql.tidb values
+		('tikv_gc_safe_point', '20260101-00:00:00.000 +0000', ''),
+		('tikv_gc_last_run_time', '20260101-00:10:00.000 +0800', ''),
+		('tikv_gc_life_time', '10m0s', ''),
+		('tikv_gc_run_interval', '10m0s', ''),
+		('tikv_gc_leader_desc', 'host:mock, pid:1', '')`)
+
+	// The GC safe point isn't touched by reading the table.
+	safePointKV := store.(tikv.Storage).GetSafePointKV()
+	require.NoError(t, safePointKV.Put(tikv.GcSavedSafePoint, ""))
+	tk.MustQuery("select safe_point, store_safe_point, up_stores from information_schema.tikv_gc_status").
+		Check(testkit.Rows("2026-01-01 00:00:00 <nil> 1"))
+	safePoint := oracle.GoTimeToTS(time.Date(2025, 12, 31, 23, 50, 0, 0, time.UTC))
+	require.NoError(t, safePointKV.Put(tikv.GcSavedSafePoint, strconv.FormatUint(safePoint, 10)))
+	tk.MustQuery("select * from information_schema.tikv_gc_status").
+		Check(testkit.Rows("2026-01-01 00:00:00 2025-12-31 23:50:00 2025-12-31 16:10:00 10m0s 10m0s host:mock, pid:1 1"))
+	pdSafePoint, err := store.(tikv.Storage).GetRegionCache().PDClient().UpdateGCSafePoint(context.Background(), 0)
+	require.NoError(t, err)
+	require.Zero(t, pdSafePoint)
+
+	// The values which are not recorded by the GC worker are NULL.
+	tk.MustExec("delete from mysql.tidb where variable_name in ('tikv_gc_last_run_time', 'tikv_gc_leader_desc')")
+	tk.MustQuery("select last_run_time, leader from information_schema.tikv_gc_status").Check(testkit.Rows("<nil> <nil>"))
+
+	// The PROCESS privilege is required.
+	tk.MustExec("create user 'gc_status'@'%'")
+	tk1 := testkit.NewTestKit(t, store)
+	require.NoError(t, tk1.Session().Auth(&auth.UserIdentity{Username: "gc_status", Hostname: "%"}, nil, nil, nil))
+	err = tk1.QueryToErr("select * from information_schema.tikv_gc_status")
+	require.ErrorContains(t, err, "you need (at least one of) the PROCESS privilege(s) for this operation")
+	tk.MustExec("grant process on *.* to 'gc_status'@'%'")
+	require.NotEmpty(t, tk1.MustQuery("select * from information_schema.tikv_gc_status").Rows())
+}
+
+func TestSessionTemporaryTables(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustQuery("select * from information_schema.session_temporary_tables").Check(testkit.Rows())
+
+	tk.MustExec("create temporary table tmp1(id int primary key, v varchar(64))")
+	tk.MustExec("create temporary table tmp2(id int)")
+	tk.MustExec("create global temporary table gtmp(id int) on commit delete rows")
+	tk.MustQuery("select table_schema, table_name, table_rows, memory_usage from information_schema.session_temporary_tables").
+		Check(testkit.Rows("test tmp1 0 0", "test tmp2 0 0"))
+
+	tk.MustExec("insert into tmp1 values (1, 'a'), (2, 'b')")
+	tk.MustQuery("select table_rows from information_schema.session_temporary_tables where table_name = 'tmp1'").Check(testkit.Rows("2"))
+	size := tk.MustQuery("select memory_usage from information_schema.session_temporary_tables where table_name = 'tmp1'").Rows()[0][0].(string)
+	require.NotEqual(t, "0", size)
+
+	// The memory usage grows with the inserted rows.
+	tk.MustExec("insert into tmp1 select id + 2, repeat('x', 64) from tmp1")
+	tk.MustQuery("select table_rows from information_schema.session_temporary_tables where table_name = 'tmp1'").Check(testkit.Rows("4"))
+	tk.MustQuery(fmt.Sprintf("select memory_usage > %s from information_schema.session_temporary_tables where table_name = 'tmp1'", size)).Check(testkit.Rows("1"))
+	tk.MustExec("delete from tmp1 where id > 2")
+	tk.MustQuery("select table_rows from information_schema.session_temporary_tables where table_name = 'tmp1'").Check(testkit.Rows("2"))
+
+	// Only the temporary tables of the current session are shown.
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustQuery("select * from information_schema.session_temporary_tables").Check(testkit.Rows())
+
+	tk.MustExec("drop temporary table tmp1")
+	tk.MustQuery("select table_name, table_rows from information_schema.session_temporary_tables").Check(testkit.Rows("tmp2 0"))
+}
@@ -15,6 +15,7 @@
 package executor
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"fmt"
@@ -34,11 +35,13 @@ import (
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/stringutil"
 	"github.com/pingcap/tidb/util/tracing"
 	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
 )
 
 // InsertExec represents an insert executor.
@@ -49,6 +52,9 @@ type InsertExec struct {
 	curInsertVals  chunk.MutRow
 	row4Update     []types.Datum
 
+	// sortByHandle indicates whether to sort the rows of each batch by their handles before writing them.
+	sortByHandle bool
+
 	Priority mysql.PriorityEnum
 }
 
@@ -92,6 +98,13 @@ func (e *InsertExec) exec(ctx context.Context, rows [][]types.Datum) error {
 	} else {
 		e.collectRuntimeStatsEnabled()
 		start := time.Now()
+		if e.sortByHandle {
+			// The rows without any duplicate key can be written in any order, so we write them in the order
+			// of their handles to make the writes of a batch as local as possible.
+			if err := e.sortRowsByHandle(rows); err != nil {
+				return err
+			}
+		}
 		for i, row := range rows {
 			var err error
 			sizeHintStep := int(sessVars.ShardAllocateStep)
@@ -116,6 +129,49 @@ func (e *InsertExec) exec(ctx context.Context, rows [][]types.Datum) error {
 	return nil
 }
 
+// sortRowsByHandle sorts the rows by their handles. The rows with the same handle keep their original order.
+func (e *InsertExec) sortRowsByHandle(rows [][]types.Datum) error {
+	if len(rows) <= 1 {
+		return nil
+	}
+	tblInfo := e.Table.Meta()
+	if tblInfo.PKIsHandle {
+		offset := tblInfo.GetPkColInfo().Offset
+		// The unsigned handles are encoded as int64 too, so comparing them as int64 keeps the order of the keys.
+		slices.SortStableFunc(rows, func(i, j []types.Datum) bool {
+			return i[offset].GetInt64() < j[offset].GetInt64()
+		})
+		return nil
+	}
+	pkIdx := tables.FindPrimaryIndex(tblInfo)
+	sc := e.ctx.GetSessionVars().StmtCtx
+	type keyedRow struct {
+		key []byte
+		row []types.Datum
+	}
+	keyedRows := make([]keyedRow, 0, len(rows))
+	pkDts := make([]types.Datum, 0, len(pkIdx.Columns))
+	for _, row := range rows {
+		pkDts = pkDts[:0]
+		for _, idxCol := range pkIdx.Columns {
+			pkDts = append(pkDts, row[idxCol.Offset])
+		}
+		tablecodec.TruncateIndexValues(tblInfo, pkIdx, pkDts)
+		key, err := codec.EncodeKey(sc, nil, pkDts...)
+		if err != nil {
+			return err
+		}
+		keyedRows = append(keyedRows, keyedRow{key: key, row: row})
+	}
+	slices.SortStableFunc(keyedRows, func(i, j keyedRow) bool {
+		return bytes.Compare(i.key, j.key) < 0
+	})
+	for i := range keyedRows {
+		rows[i] = keyedRows[i].row
+	}
+	return nil
+}
+
 func prefetchUniqueIndices(ctx context.Context, txn kv.Transaction, rows []toBeCheckedRow) (map[string][]byte, error) {
 	r, ctx := tracing.StartRegionEx(ctx, "prefetchUniqueIndices")
 	defer r.End()
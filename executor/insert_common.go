@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
@@ -44,7 +45,9 @@ import (
 	"github.com/pingcap/tidb/util/dbterror/exeerrors"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/mathutil"
 	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/stringutil"
 	"github.com/pingcap/tidb/util/tracing"
 	"github.com/tikv/client-go/v2/txnkv/txnsnapshot"
 	"go.uber.org/zap"
@@ -88,6 +91,15 @@ type InsertValues struct {
 	lazyFillAutoID bool
 	memTracker     *memory.Tracker
 
+	// autoIDBatchSize is the max number of the auto IDs pre-allocated at a time, it's set by tidb_auto_id_batch_size.
+	autoIDBatchSize int
+	// autoIDRowsLeft is the number of the rows left in the values lists or the current chunk of the select result.
+	// The pre-allocated auto IDs are bounded by it, so no IDs are wasted unless some rows are given explicit IDs.
+	autoIDRowsLeft int
+	// autoIncIDs and autoRandIDs are the pre-allocated auto IDs which are not used yet.
+	autoIncIDs  autoIDRange
+	autoRandIDs autoIDRange
+
 	rowLen int
 
 	stats *InsertRuntimeStat
@@ -213,6 +225,7 @@ func insertRows(ctx context.Context, base insertCommon) (err error) {
 	memTracker := e.memTracker
 	for i, list := range e.Lists {
 		e.rowCount++
+		e.autoIDRowsLeft = len(e.Lists) - i
 		var row []types.Datum
 		row, err = evalRowFunc(ctx, list, i)
 		if err != nil {
@@ -466,6 +479,7 @@ func insertRowsFromSelect(ctx context.Context, base insertCommon) error {
 		}
 		chkMemUsage := chk.MemoryUsage()
 		memTracker.Consume(chkMemUsage)
+		e.autoIDRowsLeft = chk.NumRows()
 		for innerChunkRow := iter.Begin(); innerChunkRow != iter.End(); innerChunkRow = iter.Next() {
 			innerRow := innerChunkRow.GetDatumRow(fields)
 			e.rowCount++
@@ -473,6 +487,7 @@ func insertRowsFromSelect(ctx context.Context, base insertCommon) error {
 			if err != nil {
 				return err
 			}
+			e.autoIDRowsLeft--
 			extraColsInSel = append(extraColsInSel, innerRow[e.rowLen:])
 			rows = append(rows, row)
 			if batchInsert && e.rowCount%uint64(batchSize) == 0 {
@@ -867,6 +882,7 @@ func (e *InsertValues) lazyAdjustAutoIncrementDatum(ctx context.Context, rows []
 				processedIdx++
 				cnt++
 			}
+			e.onAutoIDAlloc(cnt)
 			// AllocBatchAutoIncrementValue allocates batch N consecutive autoIDs.
 			// The max value can be derived from adding the increment value to min for cnt-1 times.
 			min, increment, err := table.AllocBatchAutoIncrementValue(ctx, e.Table, e.ctx, cnt)
@@ -930,6 +946,8 @@ func (e *InsertValues) adjustAutoIncrementDatum(ctx context.Context, d types.Dat
 		if err != nil {
 			return types.Datum{}, err
 		}
+		// Only the pre-allocated IDs from the explicit one on may conflict with it.
+		e.autoIncIDs.truncate(recordID)
 		e.ctx.GetSessionVars().StmtCtx.InsertID = uint64(recordID)
 		retryInfo.AddAutoIncrementID(recordID)
 		return d, nil
@@ -938,7 +956,7 @@ func (e *InsertValues) adjustAutoIncrementDatum(ctx context.Context, d types.Dat
 	// Change NULL to auto id.
 	// Change value 0 to auto id, if NoAutoValueOnZero SQL mode is not set.
 	if d.IsNull() || e.ctx.GetSessionVars().SQLMode&mysql.ModeNoAutoValueOnZero == 0 {
-		recordID, err = table.AllocAutoIncrementValue(ctx, e.Table, e.ctx)
+		recordID, err = e.allocAutoIncrementID(ctx)
 		if e.handleErr(c, &d, 0, err) != nil {
 			return types.Datum{}, err
 		}
@@ -957,6 +975,68 @@ func (e *InsertValues) adjustAutoIncrementDatum(ctx context.Context, d types.Dat
 	return d, nil
 }
 
+// TestAutoIDAllocHookKey is used as the key to store a 'func(n int)' in the context, which is called
+// every time the InsertValues allocates n auto IDs from the allocator.
+var TestAutoIDAllocHookKey stringutil.StringerStr = "TestAutoIDAllocHookKey"
+
+// autoIDRange is a range of the pre-allocated auto IDs: next, next+step, ... and cnt IDs are left.
+type autoIDRange struct {
+	next int64
+	step int64
+	cnt  int
+}
+
+func (r *autoIDRange) pop() (int64, bool) {
+	if r.cnt <= 0 {
+		return 0, false
+	}
+	id := r.next
+	r.next += r.step
+	r.cnt--
+	return id, true
+}
+
+// truncate drops the IDs which are not less than id.
+func (r *autoIDRange) truncate(id int64) {
+	if r.cnt <= 0 || id < r.next {
+		return
+	}
+	if k := (id - r.next) / r.step; k < int64(r.cnt) {
+		r.cnt = int(k)
+	}
+}
+
+// autoIDBatchCount returns the number of the auto IDs to allocate at a time.
+func (e *InsertValues) autoIDBatchCount() int {
+	return mathutil.Max(1, mathutil.Min(e.autoIDBatchSize, e.autoIDRowsLeft))
+}
+
+func (e *InsertValues) onAutoIDAlloc(n int) {
+	if hook, ok := e.ctx.Value(TestAutoIDAllocHookKey).(func(int)); ok {
+		hook(n)
+	}
+}
+
+// allocAutoIncrementID allocates an auto-increment id, it pre-allocates the ids for the following rows if possible.
+func (e *InsertValues) allocAutoIncrementID(ctx context.Context) (int64, error) {
+	if id, ok := e.autoIncIDs.pop(); ok {
+		return id, nil
+	}
+	if n := e.autoIDBatchCount(); n > 1 {
+		e.onAutoIDAlloc(n)
+		firstID, increment, err := table.AllocBatchAutoIncrementValue(ctx, e.Table, e.ctx, n)
+		if err == nil {
+			e.autoIncIDs = autoIDRange{next: firstID, step: increment, cnt: n}
+			id, _ := e.autoIncIDs.pop()
+			return id, nil
+		}
+		// The batch may exceed the range of the column while the rows inserted actually don't, e.g. some of them
+		// have explicit values, so fall back to allocating one id.
+	}
+	e.onAutoIDAlloc(1)
+	return table.AllocAutoIncrementValue(ctx, e.Table, e.ctx)
+}
+
 func getAutoRecordID(d types.Datum, target *types.FieldType, isInsert bool) (int64, error) {
 	var recordID int64
 	switch target.GetType() {
@@ -1009,6 +1089,8 @@ func (e *InsertValues) adjustAutoRandomDatum(ctx context.Context, d types.Datum,
 		if err != nil {
 			return types.Datum{}, err
 		}
+		// Only the pre-allocated IDs from the incremental part of the explicit one on may conflict with it.
+		e.autoRandIDs.truncate(e.autoRandomIncrementalID(recordID, &c.FieldType))
 		e.ctx.GetSessionVars().StmtCtx.InsertID = uint64(recordID)
 		err = setDatumAutoIDAndCast(e.ctx, &d, recordID, c)
 		if err != nil {
@@ -1041,20 +1123,22 @@ func (e *InsertValues) adjustAutoRandomDatum(ctx context.Context, d types.Datum,
 }
 
 // allocAutoRandomID allocates a random id for primary key column. It assumes tableInfo.AutoRandomBits > 0.
+// The incremental parts of the ids are pre-allocated for the following rows if possible.
 func (e *InsertValues) allocAutoRandomID(ctx context.Context, fieldType *types.FieldType) (int64, error) {
-	alloc := e.Table.Allocators(e.ctx).Get(autoid.AutoRandomType)
 	tableInfo := e.Table.Meta()
-	increment := e.ctx.GetSessionVars().AutoIncrementIncrement
-	offset := e.ctx.GetSessionVars().AutoIncrementOffset
-	_, autoRandomID, err := alloc.Alloc(ctx, 1, int64(increment), int64(offset))
-	if err != nil {
-		return 0, err
-	}
 	shardFmt := autoid.NewShardIDFormat(fieldType, tableInfo.AutoRandomBits, tableInfo.AutoRandomRangeBits)
+	autoRandomID, ok := e.autoRandIDs.pop()
+	if !ok {
+		var err error
+		autoRandomID, err = e.allocAutoRandomIncrementalIDs(ctx, e.autoIDBatchCount())
+		if err != nil {
+			return 0, err
+		}
+	}
 	if shardFmt.IncrementalMask()&autoRandomID != autoRandomID {
 		return 0, autoid.ErrAutoRandReadFailed
 	}
-	_, err = e.ctx.Txn(true)
+	_, err := e.ctx.Txn(true)
 	if err != nil {
 		return 0, err
 	}
@@ -1062,17 +1146,39 @@ func (e *InsertValues) allocAutoRandomID(ctx context.Context, fieldType *types.F
 	return shardFmt.Compose(currentShard, autoRandomID), nil
 }
 
+// allocAutoRandomIncrementalIDs allocates n incremental parts of the auto random ids, keeps the ones
+// following the first in autoRandIDs and returns the first.
+func (e *InsertValues) allocAutoRandomIncrementalIDs(ctx context.Context, n int) (int64, error) {
+	alloc := e.Table.Allocators(e.ctx).Get(autoid.AutoRandomType)
+	increment := int64(e.ctx.GetSessionVars().AutoIncrementIncrement)
+	offset := int64(e.ctx.GetSessionVars().AutoIncrementOffset)
+	e.onAutoIDAlloc(n)
+	min, _, err := alloc.Alloc(ctx, uint64(n), increment, offset)
+	if err != nil {
+		if n > 1 {
+			// Like allocAutoIncrementID, the batch may exceed the range while the rows inserted actually don't.
+			return e.allocAutoRandomIncrementalIDs(ctx, 1)
+		}
+		return 0, err
+	}
+	e.autoRandIDs = autoIDRange{next: autoid.SeekToFirstAutoIDSigned(min, increment, offset), step: increment, cnt: n}
+	id, _ := e.autoRandIDs.pop()
+	return id, nil
+}
+
 func (e *InsertValues) rebaseAutoRandomID(ctx context.Context, recordID int64, fieldType *types.FieldType) error {
 	if recordID < 0 {
 		return nil
 	}
 	alloc := e.Table.Allocators(e.ctx).Get(autoid.AutoRandomType)
-	tableInfo := e.Table.Meta()
+	return alloc.Rebase(ctx, e.autoRandomIncrementalID(recordID, fieldType), true)
+}
 
+// autoRandomIncrementalID returns the incremental part of the auto random id.
+func (e *InsertValues) autoRandomIncrementalID(recordID int64, fieldType *types.FieldType) int64 {
+	tableInfo := e.Table.Meta()
 	shardFmt := autoid.NewShardIDFormat(fieldType, tableInfo.AutoRandomBits, tableInfo.AutoRandomRangeBits)
-	autoRandomID := shardFmt.IncrementalMask() & recordID
-
-	return alloc.Rebase(ctx, autoRandomID, true)
+	return shardFmt.IncrementalMask() & recordID
 }
 
 func (e *InsertValues) adjustImplicitRowID(ctx context.Context, d types.Datum, hasValue bool, c *table.Column) (types.Datum, error) {
@@ -1368,15 +1474,21 @@ func (e *InsertValues) addRecordWithAutoIDHint(ctx context.Context, row []types.
 	if !vars.ConstraintCheckInPlace {
 		vars.PresumeKeyNotExists = true
 	}
+	var h kv.Handle
 	if reserveAutoIDCount > 0 {
-		_, err = e.Table.AddRecord(e.ctx, row, table.WithCtx(ctx), table.WithReserveAutoIDHint(reserveAutoIDCount))
+		h, err = e.Table.AddRecord(e.ctx, row, table.WithCtx(ctx), table.WithReserveAutoIDHint(reserveAutoIDCount))
 	} else {
-		_, err = e.Table.AddRecord(e.ctx, row, table.WithCtx(ctx))
+		h, err = e.Table.AddRecord(e.ctx, row, table.WithCtx(ctx))
 	}
 	vars.PresumeKeyNotExists = false
 	if err != nil {
 		return err
 	}
+	failpoint.Inject("recordInsertHandles", func() {
+		if record, ok := ctx.Value(InsertHandlesRecorderKey{}).(func(kv.Handle)); ok {
+			record(h)
+		}
+	})
 	vars.StmtCtx.AddAffectedRows(1)
 	if e.lastInsertID != 0 {
 		vars.SetLastInsertID(e.lastInsertID)
@@ -1392,6 +1504,9 @@ func (e *InsertValues) addRecordWithAutoIDHint(ctx context.Context, row []types.
 	return nil
 }
 
+// InsertHandlesRecorderKey is for test, its value in the context records the handles in the order they are written.
+type InsertHandlesRecorderKey struct{}
+
 // CreateSession will be assigned by session package.
 var CreateSession func(ctx sessionctx.Context) (sessionctx.Context, error)
 
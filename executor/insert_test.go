@@ -15,6 +15,7 @@
 package executor_test
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"strconv"
@@ -25,12 +26,16 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/testkit/external"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/tikv"
 )
 
 func TestInsertOnDuplicateKey(t *testing.T) {
@@ -915,6 +920,73 @@ func TestAutoRandomIDExplicit(t *testing.T) {
 	tk.MustExec(`drop table ar`)
 }
 
+func TestAutoIDBatchAlloc(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec(`use test`)
+	tk.MustExec(`create table ar (id bigint key clustered auto_random, v int)`)
+	tk.MustExec(`create table ai (id bigint key auto_increment, v int)`)
+
+	var allocs []int
+	tk.Session().SetValue(executor.TestAutoIDAllocHookKey, func(n int) {
+		allocs = append(allocs, n)
+	})
+	defer tk.Session().SetValue(executor.TestAutoIDAllocHookKey, nil)
+
+	values := make([]string, 0, 300)
+	for i := 0; i < 300; i++ {
+		values = append(values, fmt.Sprintf("(%d)", i))
+	}
+	// The ids are allocated one by one by default.
+	tk.MustQuery("select @@tidb_auto_id_batch_size").Check(testkit.Rows("1"))
+	tk.MustExec("insert into ar(v) values " + strings.Join(values[:10], ","))
+	require.Equal(t, []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, allocs)
+	tk.MustExec("delete from ar")
+
+	allocs = allocs[:0]
+	tk.MustExec("set @@tidb_auto_id_batch_size = 128")
+	tk.MustExec("insert into ar(v) values " + strings.Join(values, ","))
+	require.Equal(t, []int{128, 128, 44}, allocs)
+	tk.MustQuery("select count(distinct id), count(*) from ar").Check(testkit.Rows("300 300"))
+
+	// The auto-increment ids of INSERT ... SELECT are allocated in batches too.
+	allocs = allocs[:0]
+	tk.MustExec("insert into ai(v) select v from ar order by v")
+	require.Less(t, len(allocs), 10)
+	tk.MustQuery("select count(distinct id), min(id), max(id) from ai").Check(testkit.Rows("300 1 300"))
+
+	// The pre-allocated ids less than the explicit one are still used, and the ones from it on are dropped.
+	tk.MustExec("delete from ai")
+	tk.MustExec("insert into ai select if(v = 10, 320, null), v from ar order by v")
+	tk.MustQuery("select count(distinct id), count(*) from ai").Check(testkit.Rows("300 300"))
+	tk.MustQuery("select min(id), count(*) from ai where id < 320").Check(testkit.Rows("301 19"))
+	tk.MustQuery("select v from ai where id = 319").Check(testkit.Rows("19"))
+
+	// So are the auto random ids, whose incremental parts are compared.
+	explicitValues := make([]string, 0, 300)
+	for i := 0; i < 300; i++ {
+		if i == 10 {
+			explicitValues = append(explicitValues, fmt.Sprintf("(%d, %d)", 350, i))
+			continue
+		}
+		explicitValues = append(explicitValues, fmt.Sprintf("(null, %d)", i))
+	}
+	tk.MustExec("set @@allow_auto_random_explicit_insert = true")
+	tk.MustExec("delete from ar")
+	tk.MustExec("insert into ar values " + strings.Join(explicitValues, ","))
+	incremental := "id & ((1 << 58) - 1)"
+	tk.MustQuery(fmt.Sprintf("select count(distinct %s), count(*) from ar", incremental)).Check(testkit.Rows("300 300"))
+	tk.MustQuery(fmt.Sprintf("select min(%[1]s), count(*) from ar where %[1]s < 350", incremental)).Check(testkit.Rows("311 39"))
+	tk.MustQuery(fmt.Sprintf("select v from ar where %s = 349", incremental)).Check(testkit.Rows("39"))
+
+	// Allocate the ids one by one.
+	allocs = allocs[:0]
+	tk.MustExec("set @@tidb_auto_id_batch_size = 1")
+	tk.MustExec("insert into ar(v) values " + strings.Join(values, ","))
+	require.Len(t, allocs, 300)
+	tk.MustQuery("select count(distinct id), count(*) from ar").Check(testkit.Rows("600 600"))
+}
+
 func TestInsertErrorMsg(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -1575,3 +1647,115 @@ func TestInsertLock(t *testing.T) {
 		})
 	}
 }
+
+func TestInsertSortByHandle(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec(`use test`)
+	tk.MustExec(`set @@tidb_insert_sort_by_handle = 1`)
+	tk.Session().GetSessionVars().EnableClusteredIndex = variable.ClusteredIndexDefModeOn
+
+	tk.MustExec(`create table src(a int, b varchar(10))`)
+	tk.MustExec(`insert into src values (3, 'c'), (1, 'a'), (-2, 'x'), (2, 'b')`)
+
+	// The int handle.
+	tk.MustExec(`create table t1(id int primary key auto_increment, b varchar(10))`)
+	tk.MustExec(`insert into t1 select a, b from src`)
+	tk.MustQuery(`select * from t1`).Check(testkit.Rows("-2 x", "1 a", "2 b", "3 c"))
+	tk.MustExec(`insert into t1(b) select b from src order by a desc`)
+	tk.MustQuery(`select last_insert_id()`).Check(testkit.Rows("4"))
+	tk.MustQuery(`select * from t1 where id > 3`).Check(testkit.Rows("4 c", "5 b", "6 a", "7 x"))
+	tk.MustGetErrCode(`insert into t1 values (9, 'y'), (8, 'z'), (9, 'w')`, errno.ErrDupEntry)
+	tk.MustQuery(`select count(*) from t1`).Check(testkit.Rows("8"))
+
+	// The unsigned int handle.
+	tk.MustExec(`create table t2(id bigint unsigned primary key, b varchar(10))`)
+	handles := execAndRecordInsertHandles(t, tk, `insert into t2 values (18446744073709551615, 'max'), (1, 'a'), (9223372036854775808, 'mid')`)
+	// The unsigned handles are written in the order of their encoded keys.
+	require.Equal(t, []string{"-9223372036854775808", "-1", "1"}, handlesToStrings(handles))
+	tk.MustQuery(`select * from t2 order by id`).Check(testkit.Rows("1 a", "9223372036854775808 mid", "18446744073709551615 max"))
+
+	// The common handle.
+	tk.MustExec(`create table t3(a varchar(10), b int, c int, primary key(a, b), unique key(c))`)
+	handles = execAndRecordInsertHandles(t, tk, `insert into t3 values ('b', 2, 1), ('a', 3, 2), ('b', 1, 3), ('a', 1, 4)`)
+	require.Equal(t, []string{"{a, 1}", "{a, 3}", "{b, 1}", "{b, 2}"}, handlesToStrings(handles))
+	tk.MustQuery(`select * from t3`).Check(testkit.Rows("a 1 4", "a 3 2", "b 1 3", "b 2 1"))
+	tk.MustGetErrCode(`insert into t3 values ('c', 2, 5), ('c', 1, 5)`, errno.ErrDupEntry)
+
+	// The rows of `INSERT IGNORE` and `ON DUPLICATE KEY UPDATE` are not reordered.
+	tk.MustExec(`insert ignore into t3 values ('d', 2, 6), ('d', 1, 6)`)
+	tk.MustQuery(`select * from t3 where c = 6`).Check(testkit.Rows("d 2 6"))
+	tk.MustExec(`insert into t3 values ('e', 2, 7), ('e', 1, 7) on duplicate key update c = 8`)
+	tk.MustQuery(`select * from t3 where c > 6`).Check(testkit.Rows("e 2 8"))
+
+	// The batch insert sorts each batch.
+	tk.MustExec(`set @@tidb_batch_insert = 1, @@tidb_dml_batch_size = 2`)
+	tk.MustExec(`create table t4(id int primary key, b varchar(10))`)
+	tk.MustExec(`insert into t4 select a, b from src`)
+	tk.MustQuery(`select * from t4`).Check(testkit.Rows("-2 x", "1 a", "2 b", "3 c"))
+}
+
+func TestInsertSortByHandleRegionSwitches(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec(`use test`)
+	tk.MustExec(`create table src(a int)`)
+	values := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		// A permutation of [0, 10000) with the step 10.
+		values = append(values, fmt.Sprintf("(%d)", i*383%1000*10))
+	}
+	tk.MustExec(`insert into src values ` + strings.Join(values, ","))
+
+	// countRegionSwitches counts how many times the consecutive writes of the insert go to different regions.
+	countRegionSwitches := func(tbl string) int {
+		tk.MustExec(fmt.Sprintf(`create table %s(id int primary key, b int)`, tbl))
+		tk.MustQuery(fmt.Sprintf(`split table %s between (0) and (10000) regions 10`, tbl)).Check(testkit.Rows("9 1"))
+		handles := execAndRecordInsertHandles(t, tk, fmt.Sprintf(`insert into %s select a, a from src`, tbl))
+		require.Len(t, handles, 1000)
+		tblInfo := external.GetTableByName(t, tk, "test", tbl).Meta()
+		cache := store.(tikv.Storage).GetRegionCache()
+		bo := tikv.NewBackofferWithVars(context.Background(), 1000, nil)
+		switches := 0
+		var lastRegion uint64
+		for i, h := range handles {
+			loc, err := cache.LocateKey(bo, tablecodec.EncodeRowKeyWithHandle(tblInfo.ID, h))
+			require.NoError(t, err)
+			if i > 0 && loc.Region.GetID() != lastRegion {
+				switches++
+			}
+			lastRegion = loc.Region.GetID()
+		}
+		return switches
+	}
+
+	unsorted := countRegionSwitches("t1")
+	tk.MustExec(`set @@tidb_insert_sort_by_handle = 1`)
+	sorted := countRegionSwitches("t2")
+	// The sorted rows go through the regions one by one.
+	require.Equal(t, 9, sorted)
+	require.Greater(t, unsorted, 500)
+	tk.MustQuery(`select count(*), min(id), max(id) from t2`).Check(testkit.Rows("1000 0 9990"))
+}
+
+// execAndRecordInsertHandles executes the insert statement and returns the handles in the order they are written.
+func execAndRecordInsertHandles(t *testing.T, tk *testkit.TestKit, sql string) []kv.Handle {
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/executor/recordInsertHandles", "return"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/executor/recordInsertHandles"))
+	}()
+	var handles []kv.Handle
+	ctx := context.WithValue(context.Background(), executor.InsertHandlesRecorderKey{}, func(h kv.Handle) {
+		handles = append(handles, h)
+	})
+	tk.MustExecWithContext(ctx, sql)
+	return handles
+}
+
+func handlesToStrings(handles []kv.Handle) []string {
+	strs := make([]string, 0, len(handles))
+	for _, h := range handles {
+		strs = append(strs, h.String())
+	}
+	return strs
+}
@@ -70,6 +70,9 @@ type hashJoinCtx struct {
 	isNullAware        bool
 	memTracker         *memory.Tracker // track memory usage.
 	diskTracker        *disk.Tracker   // track disk usage.
+	// radixBits is the number of the hash key bits to partition the build side rows,
+	// 0 means the build side is not partitioned. See radixHashTable.
+	radixBits uint
 }
 
 // probeSideTupleFetcher reads tuples from probeSideExec and send them to probeWorkers.
@@ -80,6 +83,29 @@ type probeSideTupleFetcher struct {
 	probeChkResourceCh chan *probeChkResource
 	probeResultChs     []chan *chunk.Chunk
 	requiredRows       int64
+	// prefetch is the number of the probe side chunks read ahead by probeSidePrefetcher,
+	// 0 means the probe side is read only when a probe worker gives its chunk back.
+	prefetch int
+}
+
+// probeSidePrefetcher reads the probe side chunks ahead into a bounded queue in a background
+// goroutine, so the latency of a slow probe side reader is hidden while the probe workers
+// process the current chunks.
+type probeSidePrefetcher struct {
+	fetcher      *probeSideTupleFetcher
+	maxChunkSize int
+	// freeCh holds the empty chunks to be filled by the prefetcher.
+	freeCh chan *chunk.Chunk
+	// resultCh is the queue of the prefetched chunks.
+	resultCh chan *probeSidePrefetchResult
+	doneCh   chan struct{}
+	wg       util.WaitGroupWrapper
+}
+
+type probeSidePrefetchResult struct {
+	chk      *chunk.Chunk
+	memUsage int64
+	err      error
 }
 
 type probeWorker struct {
@@ -105,6 +131,9 @@ type probeWorker struct {
 	probeChkResourceCh   chan *probeChkResource
 	joinChkResourceCh    chan *chunk.Chunk
 	probeResultCh        chan *chunk.Chunk
+	// radixOrder and radixCounts are reused to order the probe side rows by partitions in radix mode.
+	radixOrder  []int
+	radixCounts []int
 }
 
 type buildWorker struct {
@@ -112,6 +141,8 @@ type buildWorker struct {
 	buildSideExec    Executor
 	buildKeyColIdx   []int
 	buildNAKeyColIdx []int
+	// dedupBuildKeys indicates only the distinct build keys are put into the hash table, see hashRowContainer.distinctKeys.
+	dedupBuildKeys bool
 }
 
 // HashJoinExec implements the hash join algorithm.
@@ -230,6 +261,12 @@ func (e *HashJoinExec) Open(ctx context.Context) error {
 // fetchProbeSideChunks get chunks from fetches chunks from the big table in a background goroutine
 // and sends the chunks to multiple channels which will be read by multiple join workers.
 func (fetcher *probeSideTupleFetcher) fetchProbeSideChunks(ctx context.Context, maxChunkSize int) {
+	var prefetcher *probeSidePrefetcher
+	if fetcher.prefetch > 0 {
+		prefetcher = newProbeSidePrefetcher(fetcher, maxChunkSize)
+		prefetcher.start(ctx)
+		defer prefetcher.stop()
+	}
 	hasWaitedForBuild := false
 	for {
 		if fetcher.finished.Load() {
@@ -247,11 +284,18 @@ func (fetcher *probeSideTupleFetcher) fetchProbeSideChunks(ctx context.Context,
 			}
 		}
 		probeSideResult := probeSideResource.chk
-		if fetcher.isOuterJoin {
-			required := int(atomic.LoadInt64(&fetcher.requiredRows))
-			probeSideResult.SetRequiredRows(required, maxChunkSize)
+		var err error
+		if prefetcher != nil {
+			// The prefetched chunk is sent to the probe worker, and the chunk given back by
+			// the probe worker is reused by the prefetcher.
+			probeSideResult, err = prefetcher.next(probeSideResult)
+		} else {
+			if fetcher.isOuterJoin {
+				required := int(atomic.LoadInt64(&fetcher.requiredRows))
+				probeSideResult.SetRequiredRows(required, maxChunkSize)
+			}
+			err = Next(ctx, fetcher.probeSideExec, probeSideResult)
 		}
-		err := Next(ctx, fetcher.probeSideExec, probeSideResult)
 		failpoint.Inject("ConsumeRandomPanic", nil)
 		if err != nil {
 			fetcher.joinResultCh <- &hashjoinWorkerResult{
@@ -288,6 +332,102 @@ func (fetcher *probeSideTupleFetcher) fetchProbeSideChunks(ctx context.Context,
 	}
 }
 
+func newProbeSidePrefetcher(fetcher *probeSideTupleFetcher, maxChunkSize int) *probeSidePrefetcher {
+	p := &probeSidePrefetcher{
+		fetcher:      fetcher,
+		maxChunkSize: maxChunkSize,
+		freeCh:       make(chan *chunk.Chunk, fetcher.prefetch),
+		resultCh:     make(chan *probeSidePrefetchResult, fetcher.prefetch),
+		doneCh:       make(chan struct{}),
+	}
+	for i := 0; i < fetcher.prefetch; i++ {
+		p.freeCh <- newFirstChunk(fetcher.probeSideExec)
+	}
+	return p
+}
+
+func (p *probeSidePrefetcher) start(ctx context.Context) {
+	p.wg.RunWithRecover(func() {
+		defer trace.StartRegion(ctx, "HashJoinProbeSidePrefetcher").End()
+		p.prefetchChunks(ctx)
+	}, p.handlePrefetcherPanic)
+}
+
+func (p *probeSidePrefetcher) prefetchChunks(ctx context.Context) {
+	for {
+		var chk *chunk.Chunk
+		select {
+		case <-p.doneCh:
+			return
+		case <-p.fetcher.closeCh:
+			return
+		case chk = <-p.freeCh:
+		}
+		if p.fetcher.isOuterJoin {
+			required := int(atomic.LoadInt64(&p.fetcher.requiredRows))
+			chk.SetRequiredRows(required, p.maxChunkSize)
+		}
+		result := &probeSidePrefetchResult{chk: chk}
+		result.err = Next(ctx, p.fetcher.probeSideExec, chk)
+		if result.err == nil {
+			result.memUsage = chk.MemoryUsage()
+			p.fetcher.memTracker.Consume(result.memUsage)
+		}
+		if !p.sendResult(result) {
+			p.fetcher.memTracker.Consume(-result.memUsage)
+			return
+		}
+		if result.err != nil || chk.NumRows() == 0 {
+			return
+		}
+	}
+}
+
+func (p *probeSidePrefetcher) sendResult(result *probeSidePrefetchResult) bool {
+	select {
+	case <-p.doneCh:
+		return false
+	case <-p.fetcher.closeCh:
+		return false
+	case p.resultCh <- result:
+		return true
+	}
+}
+
+func (p *probeSidePrefetcher) handlePrefetcherPanic(r interface{}) {
+	if r != nil {
+		p.sendResult(&probeSidePrefetchResult{err: errors.Errorf("%v", r)})
+	}
+}
+
+// next returns the next prefetched chunk, and the given empty chunk is reused to prefetch the following ones.
+func (p *probeSidePrefetcher) next(chk *chunk.Chunk) (*chunk.Chunk, error) {
+	var result *probeSidePrefetchResult
+	select {
+	case <-p.fetcher.closeCh:
+		chk.Reset()
+		return chk, nil
+	case result = <-p.resultCh:
+	}
+	p.fetcher.memTracker.Consume(-result.memUsage)
+	p.freeCh <- chk
+	return result.chk, result.err
+}
+
+// stop stops the prefetcher and releases the memory of the chunks left in the queue.
+func (p *probeSidePrefetcher) stop() {
+	close(p.doneCh)
+	p.wg.Wait()
+	for {
+		select {
+		case result := <-p.resultCh:
+			p.fetcher.memTracker.Consume(-result.memUsage)
+		default:
+			return
+		}
+	}
+}
+
 func (fetcher *probeSideTupleFetcher) wait4BuildSide() (emptyBuild bool, err error) {
 	select {
 	case <-fetcher.closeCh:
@@ -1013,7 +1153,12 @@ func (w *probeWorker) join2Chunk(probeSideChk *chunk.Chunk, hCtx *hashContext, j
 		}
 	}
 
-	for i := range selected {
+	probeOrder := w.probeRowOrder(hCtx, numRows)
+	for idx := range selected {
+		i := idx
+		if probeOrder != nil {
+			i = probeOrder[idx]
+		}
 		killed := atomic.LoadUint32(&w.hashJoinCtx.sessCtx.GetSessionVars().Killed) == 1
 		failpoint.Inject("killedInJoin2Chunk", func(val failpoint.Value) {
 			if val.(bool) {
@@ -1069,6 +1214,40 @@ func (w *probeWorker) join2Chunk(probeSideChk *chunk.Chunk, hCtx *hashContext, j
 	return true, joinResult
 }
 
+// probeRowOrder returns the order to probe the rows of the probe side chunk in radix mode, the rows are
+// grouped by the partitions of their hash keys, so that the hash table is probed a partition at a time.
+// It returns nil if the radix mode is off.
+func (w *probeWorker) probeRowOrder(hCtx *hashContext, numRows int) []int {
+	if w.hashJoinCtx.radixBits == 0 {
+		return nil
+	}
+	numPartitions := 1 << w.hashJoinCtx.radixBits
+	mask := uint64(numPartitions - 1)
+	if cap(w.radixCounts) < numPartitions+1 {
+		w.radixCounts = make([]int, numPartitions+1)
+	}
+	counts := w.radixCounts[:numPartitions+1]
+	for p := range counts {
+		counts[p] = 0
+	}
+	for i := 0; i < numRows; i++ {
+		counts[hCtx.hashVals[i].Sum64()&mask+1]++
+	}
+	for p := 1; p <= numPartitions; p++ {
+		counts[p] += counts[p-1]
+	}
+	if cap(w.radixOrder) < numRows {
+		w.radixOrder = make([]int, numRows)
+	}
+	order := w.radixOrder[:numRows]
+	for i := 0; i < numRows; i++ {
+		p := hCtx.hashVals[i].Sum64() & mask
+		order[counts[p]] = i
+		counts[p]++
+	}
+	return order
+}
+
 // join2ChunkForOuterHashJoin joins chunks when using the outer to build a hash table (refer to outer hash join)
 func (w *probeWorker) join2ChunkForOuterHashJoin(probeSideChk *chunk.Chunk, hCtx *hashContext, joinResult *hashjoinWorkerResult) (ok bool, _ *hashjoinWorkerResult) {
 	hCtx.initHash(probeSideChk.NumRows())
@@ -1079,7 +1258,12 @@ func (w *probeWorker) join2ChunkForOuterHashJoin(probeSideChk *chunk.Chunk, hCtx
 			return false, joinResult
 		}
 	}
-	for i := 0; i < probeSideChk.NumRows(); i++ {
+	probeOrder := w.probeRowOrder(hCtx, probeSideChk.NumRows())
+	for idx := 0; idx < probeSideChk.NumRows(); idx++ {
+		i := idx
+		if probeOrder != nil {
+			i = probeOrder[idx]
+		}
 		killed := atomic.LoadUint32(&w.hashJoinCtx.sessCtx.GetSessionVars().Killed) == 1
 		failpoint.Inject("killedInJoin2ChunkForOuterHashJoin", func(val failpoint.Value) {
 			if val.(bool) {
@@ -1119,6 +1303,10 @@ func (e *HashJoinExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 			naKeyColIdx: e.buildWorker.buildNAKeyColIdx,
 		}
 		e.rowContainer = newHashRowContainer(e.ctx, hCtx, retTypes(e.buildWorker.buildSideExec))
+		if e.radixBits > 0 {
+			e.rowContainer.hashTable = newRadixHashTable(e.radixBits)
+		}
+		e.rowContainer.distinctKeys = e.buildWorker.dedupBuildKeys
 		// we shallow copies rowContainer for each probe worker to avoid lock contention
 		for i := uint(0); i < e.concurrency; i++ {
 			if i == 0 {
@@ -1248,6 +1436,7 @@ func (w *buildWorker) buildHashTableForList(buildSideResultCh <-chan *chunk.Chun
 			return err
 		}
 	}
+	rowContainer.finishBuild()
 	return nil
 }
 
@@ -1267,6 +1456,7 @@ type NestedLoopApplyExec struct {
 
 	cache              *applyCache
 	canUseCache        bool
+	cacheCapacity      int64
 	cacheHitCounter    int
 	cacheAccessCounter int
 
@@ -1328,7 +1518,7 @@ func (e *NestedLoopApplyExec) Open(ctx context.Context) error {
 	e.innerList.GetMemTracker().AttachTo(e.memTracker)
 
 	if e.canUseCache {
-		e.cache, err = newApplyCache(e.ctx)
+		e.cache, err = newApplyCache(e.cacheCapacity)
 		if err != nil {
 			return err
 		}
@@ -23,6 +23,8 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/stretchr/testify/require"
 )
 
@@ -110,6 +112,94 @@ func TestJoinExec(t *testing.T) {
 	}
 }
 
+// slowDataSource is a mockDataSource which takes a while to return each chunk.
+type slowDataSource struct {
+	*mockDataSource
+	delay time.Duration
+}
+
+func (s *slowDataSource) Next(ctx context.Context, req *chunk.Chunk) error {
+	time.Sleep(s.delay)
+	return s.mockDataSource.Next(ctx, req)
+}
+
+// slowJoiner is a joiner which takes a while to process every rowsPerDelay probe rows.
+type slowJoiner struct {
+	joiner
+	delay        time.Duration
+	rowsPerDelay int
+	rows         int
+}
+
+func (j *slowJoiner) tryToMatchInners(outer chunk.Row, inners chunk.Iterator, chk *chunk.Chunk, opt ...NAAJType) (bool, bool, error) {
+	if j.rows%j.rowsPerDelay == 0 {
+		time.Sleep(j.delay)
+	}
+	j.rows++
+	return j.joiner.tryToMatchInners(outer, inners, chk, opt...)
+}
+
+func TestHashJoinProbePrefetch(t *testing.T) {
+	colTypes := []*types.FieldType{
+		types.NewFieldType(mysql.TypeLonglong),
+		types.NewFieldType(mysql.TypeDouble),
+	}
+	casTest := defaultHashJoinTestCase(colTypes, 0, false)
+	casTest.concurrency = 1
+	casTest.rows = 8 * casTest.ctx.GetSessionVars().MaxChunkSize
+	delay := 30 * time.Millisecond
+
+	runTest := func(prefetch int) time.Duration {
+		opt := mockDataSourceParameters{
+			rows:   casTest.rows,
+			ctx:    casTest.ctx,
+			schema: expression.NewSchema(casTest.columns()...),
+			genDataFunc: func(row int, typ *types.FieldType) interface{} {
+				if typ.GetType() == mysql.TypeDouble {
+					return float64(row)
+				}
+				return int64(row)
+			},
+		}
+		buildSide := buildMockDataSource(opt)
+		opt.schema = expression.NewSchema(casTest.columns()...)
+		probeSide := &slowDataSource{mockDataSource: buildMockDataSource(opt), delay: delay}
+		buildSide.prepareChunks()
+		probeSide.prepareChunks()
+
+		exec := prepare4HashJoin(casTest, buildSide, probeSide)
+		exec.probeSideTupleFetcher.prefetch = prefetch
+		// The probe worker takes as long to process a chunk as the probe side reader takes to read it.
+		exec.probeWorkers[0].joiner = &slowJoiner{
+			joiner:       exec.probeWorkers[0].joiner,
+			delay:        delay,
+			rowsPerDelay: casTest.ctx.GetSessionVars().MaxChunkSize,
+		}
+		ctx := context.Background()
+		chk := newFirstChunk(exec)
+		start := time.Now()
+		require.NoError(t, exec.Open(ctx))
+		rows := 0
+		for {
+			require.NoError(t, exec.Next(ctx, chk))
+			if chk.NumRows() == 0 {
+				break
+			}
+			rows += chk.NumRows()
+		}
+		elapsed := time.Since(start)
+		require.NoError(t, exec.Close())
+		require.Equal(t, casTest.rows, rows)
+		return elapsed
+	}
+
+	withoutPrefetch := runTest(0)
+	withPrefetch := runTest(2)
+	// Without prefetching, the probe side is read only after the probe worker gives the
+	// previous chunk back. With prefetching, reading and probing overlap.
+	require.Less(t, withPrefetch, withoutPrefetch)
+}
+
 func TestHashJoinRuntimeStats(t *testing.T) {
 	stats := &hashJoinRuntimeStats{
 		fetchAndBuildHashTable: 2 * time.Second,
@@ -146,3 +236,54 @@ func TestIndexJoinRuntimeStats(t *testing.T) {
 	stats.Merge(stats.Clone())
 	require.Equal(t, "inner:{total:10s, concurrency:5, task:32, construct:200ms, fetch:600ms, build:500ms, join:300ms}, probe:2s", stats.String())
 }
+
+type countingOOMAction struct {
+	memory.BaseOOMAction
+	triggered int
+}
+
+func (a *countingOOMAction) Action(*memory.Tracker) {
+	a.triggered++
+}
+
+func (*countingOOMAction) GetPriority() int64 {
+	return memory.DefLogPriority
+}
+
+func TestIndexMergeJoinOuterSortSpillAction(t *testing.T) {
+	fields := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong)}
+	chk := chunk.NewChunkWithCapacity(fields, 20)
+	for i := 0; i < 20; i++ {
+		chk.AppendInt64(0, int64(20-i))
+	}
+	tracker := memory.NewTracker(memory.LabelForSimpleTask, 1)
+	fallback := &countingOOMAction{}
+	action := &outerSortSpillAction{}
+	tracker.FallbackOldAndSetNewAction(fallback)
+	tracker.FallbackOldAndSetNewAction(action)
+
+	// The fallback is triggered if there is no run being filled.
+	tracker.Consume(1)
+	require.Equal(t, 1, fallback.triggered)
+	tracker.Consume(-1)
+
+	// The run being filled is sorted and spilled.
+	run := chunk.NewSortedRowContainer(fields, 20, []bool{false}, []int{0}, []chunk.CompareFunc{chunk.GetCompareFunc(fields[0])})
+	defer func() {
+		require.NoError(t, run.Close())
+	}()
+	run.GetMemTracker().AttachTo(tracker)
+	runAction := run.ActionSpillForTest()
+	action.setRowContainer(run)
+	require.NoError(t, run.Add(chk))
+	runAction.WaitForTest()
+	require.True(t, run.AlreadySpilledSafeForTest())
+	require.Equal(t, 1, fallback.triggered)
+	row, err := run.GetSortedRow(0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), row.GetInt64(0))
+
+	// The fallback is triggered once the run has been spilled.
+	tracker.Consume(1)
+	require.Equal(t, 2, fallback.triggered)
+}
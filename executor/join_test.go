@@ -15,10 +15,12 @@
 package executor_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/pingcap/tidb/testkit"
 	"github.com/pingcap/tidb/testkit/testdata"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNaturalJoin(t *testing.T) {
@@ -98,3 +100,48 @@ func TestTiDBNAAJ(t *testing.T) {
 	tk.MustQuery("select ( table1 . a , table1 . b ) NOT IN ( SELECT 3 , 2 UNION  SELECT 9, 2 ) AS field2 from t as table1 order by field2;").Check(testkit.Rows(
 		"0", "0", "1", "1", "1", "1", "1", "1", "1", "1", "1", "1", "1", "1", "1", "1"))
 }
+
+func TestNAAJWithSpill(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int, c varchar(255))")
+	// the NA join keys of both sides contain nulls, and the build side is large enough to be spilled.
+	tk.MustExec("insert into t1 with recursive cte(n) as (select 1 union all select n + 1 from cte where n < 300) " +
+		"select if(n % 7 = 0, null, n * 10), n from cte")
+	tk.MustExec("set @@cte_max_recursion_depth = 5000")
+	tk.MustExec("insert into t2 with recursive cte(n) as (select 1 union all select n + 1 from cte where n < 4000) " +
+		"select if(n % 997 = 0, null, n), n % 100, repeat('x', 200) from cte")
+
+	sqls := []string{
+		// NA-AntiLeftOuterSemiJoin
+		"select t1.a, t1.a not in (select a from t2) from t1",
+		"select t1.a, t1.b, t1.a not in (select a from t2 where t2.b < t1.b) from t1",
+		// NA-AntiSemiJoin
+		"select * from t1 where t1.a not in (select a from t2 where t2.b < t1.b)",
+		"select * from t1 where t1.a not in (select a from t2 where t2.c > 'x')",
+		"select * from t1 where t1.a not in (select a from t2 where t2.b >= 50)",
+	}
+	tk.MustExec("set @@session.tidb_enable_null_aware_anti_join = 0")
+	expected := make([][]string, 0, len(sqls))
+	for _, sql := range sqls {
+		expected = append(expected, testdata.ConvertRowsToStrings(tk.MustQuery(sql).Sort().Rows()))
+	}
+
+	tk.MustExec("set @@session.tidb_enable_null_aware_anti_join = 1")
+	tk.MustExec("set global tidb_mem_oom_action = 'LOG'")
+	defer tk.MustExec("set global tidb_mem_oom_action = default")
+	tk.MustExec("set @@tidb_mem_quota_query = 100000")
+	for i, sql := range sqls {
+		spilled := false
+		for _, row := range tk.MustQuery("explain analyze " + sql).Rows() {
+			if strings.Contains(row[6].(string), "Null-aware") {
+				spilled = row[8].(string) != "N/A" && row[8].(string) != "0 Bytes"
+			}
+		}
+		require.True(t, spilled, sql)
+		tk.MustQuery(sql).Sort().Check(testkit.Rows(expected[i]...))
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/br/pkg/lightning/mydump"
 	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor/asyncloaddata"
 	"github.com/pingcap/tidb/executor/importer"
 	"github.com/pingcap/tidb/expression"
@@ -37,6 +38,7 @@ import (
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/sessiontxn"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
@@ -92,6 +94,11 @@ type LoadDataWorker struct {
 	planInfo   planInfo
 
 	table table.Table
+	// checkpointer is not nil only when the LOAD DATA is resumable.
+	checkpointer *loadDataCheckpointer
+	// concurrency is the max number of the source files which are read and
+	// encoded concurrently, it only takes effect when there are multiple files.
+	concurrency int
 }
 
 func setNonRestrictiveFlags(stmtCtx *stmtctx.StatementContext) {
@@ -132,6 +139,13 @@ func NewLoadDataWorker(
 			GenColExprs: plan.GenCols.Exprs,
 		},
 	}
+	if controller.ResumeToken != "" {
+		is := sessiontxn.GetTxnManager(userSctx).GetTxnInfoSchema()
+		loadDataWorker.checkpointer, err = newLoadDataCheckpointer(is, controller.ResumeToken)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return loadDataWorker, nil
 }
 
@@ -160,35 +174,50 @@ func (e *LoadDataWorker) LoadLocal(ctx context.Context, r io.ReadCloser) error {
 func (e *LoadDataWorker) load(ctx context.Context, readerInfos []importer.LoadDataReaderInfo) error {
 	group, groupCtx := errgroup.WithContext(ctx)
 
-	encoder, committer, err := initEncodeCommitWorkers(e)
+	encoders, committers, err := initEncodeCommitWorkers(e, e.encodeConcurrency(len(readerInfos)))
 	if err != nil {
 		return err
 	}
+	defer e.releaseWorkerSessions(ctx, committers)
 
 	// main goroutine -> readerInfoCh -> processOneStream goroutines
 	readerInfoCh := make(chan importer.LoadDataReaderInfo, 1)
-	// processOneStream goroutines -> commitTaskCh -> commitWork goroutines
-	commitTaskCh := make(chan commitTask, taskQueueSize)
-	// commitWork goroutines -> done -> UpdateJobProgress goroutine
 
 	// TODO: support explicit transaction and non-autocommit
-	if err = sessiontxn.NewTxn(groupCtx, e.UserSctx); err != nil {
-		return err
+	for _, committer := range committers {
+		if err = sessiontxn.NewTxn(groupCtx, committer.ctx); err != nil {
+			return finishTxns(ctx, committers, err)
+		}
 	}
-
-	// processOneStream goroutines.
-	group.Go(func() error {
-		err2 := encoder.processStream(groupCtx, readerInfoCh, commitTaskCh)
-		if err2 == nil {
-			close(commitTaskCh)
+	if e.checkpointer != nil {
+		checkpoints, err2 := e.loadCheckpoints(readerInfos)
+		if err2 != nil {
+			return finishTxns(ctx, committers, err2)
 		}
-		return err2
-	})
-	// commitWork goroutines.
-	group.Go(func() error {
-		failpoint.Inject("BeforeCommitWork", nil)
-		return committer.commitWork(groupCtx, commitTaskCh)
-	})
+		for _, encoder := range encoders {
+			encoder.checkpoints = checkpoints
+		}
+	}
+
+	annotateErrPath := len(readerInfos) > 1
+	for i := range encoders {
+		encoder, committer := encoders[i], committers[i]
+		// processOneStream goroutine -> commitTaskCh -> commitWork goroutine.
+		// All the tasks of a file are committed by the same commitWorker in
+		// order, so the checkpoint of the file never goes backwards.
+		commitTaskCh := make(chan commitTask, taskQueueSize)
+		group.Go(func() error {
+			err2 := encoder.processStream(groupCtx, readerInfoCh, commitTaskCh, annotateErrPath)
+			if err2 == nil {
+				close(commitTaskCh)
+			}
+			return err2
+		})
+		group.Go(func() error {
+			failpoint.Inject("BeforeCommitWork", nil)
+			return committer.commitWork(groupCtx, commitTaskCh)
+		})
+	}
 
 sendReaderInfoLoop:
 	for _, info := range readerInfos {
@@ -199,11 +228,70 @@ sendReaderInfoLoop:
 		}
 	}
 	close(readerInfoCh)
-	err = group.Wait()
-	e.setResult(encoder.exprWarnings)
+	err = finishTxns(ctx, committers, group.Wait())
+	if err == nil && e.checkpointer != nil {
+		// The checkpoints are useless after all the source files are loaded. If
+		// they fail to be cleared, loading with the same token again is a no-op.
+		if err2 := e.checkpointer.clear(ctx, e.UserSctx); err2 != nil {
+			logutil.Logger(ctx).Warn("clear load data checkpoints failed",
+				zap.String("resumeToken", e.controller.ResumeToken), zap.Error(err2))
+		}
+	}
+	e.mergeWorkerResults(committers)
+	e.setResult(encoders[0].exprWarnings)
+	return err
+}
+
+// finishTxns commits the transactions of the commitWorkers after all of them
+// succeeded, otherwise rolls them back.
+func finishTxns(ctx context.Context, committers []*commitWorker, err error) error {
+	for _, committer := range committers {
+		if err != nil {
+			background := context.Background()
+			committer.ctx.StmtRollback(background, false)
+			committer.ctx.RollbackTxn(background)
+			continue
+		}
+		if err = committer.ctx.CommitTxn(ctx); err != nil {
+			logutil.Logger(ctx).Error("commit error refresh", zap.Error(err))
+		}
+	}
 	return err
 }
 
+// encodeConcurrency returns the number of the encodeWorkers for the source
+// files, only multiple files can be encoded concurrently.
+func (e *LoadDataWorker) encodeConcurrency(fileCnt int) int {
+	if e.concurrency < fileCnt {
+		fileCnt = e.concurrency
+	}
+	if fileCnt < 1 {
+		return 1
+	}
+	return fileCnt
+}
+
+// loadCheckpoints reads the committed positions of the source files, files
+// which have not been checkpointed are absent from the result. It fails if any
+// of the files can't be checkpointed.
+func (e *LoadDataWorker) loadCheckpoints(readerInfos []importer.LoadDataReaderInfo) (map[string]loadDataCheckpoint, error) {
+	checkpoints := make(map[string]loadDataCheckpoint, len(readerInfos))
+	for _, info := range readerInfos {
+		path := info.Remote.Path
+		if err := e.checkpointer.checkPath(path); err != nil {
+			return nil, err
+		}
+		cp, found, err := e.checkpointer.load(e.UserSctx, path)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			checkpoints[path] = cp
+		}
+	}
+	return checkpoints, nil
+}
+
 func (e *LoadDataWorker) setResult(colAssignExprWarnings []stmtctx.SQLWarn) {
 	stmtCtx := e.UserSctx.GetSessionVars().StmtCtx
 	numWarnings := uint64(stmtCtx.WarningCount())
@@ -230,37 +318,171 @@ func (e *LoadDataWorker) setResult(colAssignExprWarnings []stmtctx.SQLWarn) {
 	stmtCtx.SetWarnings(warns)
 }
 
-func initEncodeCommitWorkers(e *LoadDataWorker) (*encodeWorker, *commitWorker, error) {
-	insertValues, err2 := createInsertValues(e)
-	if err2 != nil {
-		return nil, nil, err2
+// initEncodeCommitWorkers creates encoderCnt encodeWorkers, the i-th
+// commitWorker commits the rows encoded by the i-th encodeWorker. Each pair of
+// them runs on its own session, the first one runs on the user session.
+func initEncodeCommitWorkers(e *LoadDataWorker, encoderCnt int) (_ []*encodeWorker, _ []*commitWorker, err error) {
+	encoders := make([]*encodeWorker, 0, encoderCnt)
+	committers := make([]*commitWorker, 0, encoderCnt)
+	defer func() {
+		if err != nil {
+			e.releaseWorkerSessions(context.Background(), committers)
+		}
+	}()
+	for i := 0; i < encoderCnt; i++ {
+		sctx := e.UserSctx
+		if i > 0 {
+			if sctx, err = e.newWorkerSession(); err != nil {
+				return nil, nil, err
+			}
+		}
+		insertValues, err2 := createInsertValues(e, sctx)
+		if err2 != nil {
+			if i > 0 {
+				clearSysSession(context.Background(), sctx)
+			}
+			return nil, nil, err2
+		}
+		committers = append(committers, &commitWorker{
+			InsertValues: insertValues,
+			controller:   e.controller,
+			checkpointer: e.checkpointer,
+		})
+		colAssignExprs, exprWarnings, err2 := e.controller.CreateColAssignExprs(sctx)
+		if err2 != nil {
+			return nil, nil, err2
+		}
+		enc := &encodeWorker{
+			InsertValues:   insertValues,
+			controller:     e.controller,
+			colAssignExprs: colAssignExprs,
+			exprWarnings:   exprWarnings,
+			killed:         &e.UserSctx.GetSessionVars().Killed,
+		}
+		enc.resetBatch()
+		encoders = append(encoders, enc)
 	}
-	colAssignExprs, exprWarnings, err2 := e.controller.CreateColAssignExprs(insertValues.ctx)
-	if err2 != nil {
-		return nil, nil, err2
+	return encoders, committers, nil
+}
+
+// newWorkerSession gets a session from the system session pool and sets it up
+// like the user session, so the rows are encoded and written as the user
+// session does.
+func (e *LoadDataWorker) newWorkerSession() (sessionctx.Context, error) {
+	res, err := domain.GetDomain(e.UserSctx).SysSessionPool().Get()
+	if err != nil {
+		return nil, err
 	}
-	enc := &encodeWorker{
-		InsertValues:   insertValues,
-		controller:     e.controller,
-		colAssignExprs: colAssignExprs,
-		exprWarnings:   exprWarnings,
-		killed:         &e.UserSctx.GetSessionVars().Killed,
+	sctx := res.(sessionctx.Context)
+	if err = e.initWorkerSession(sctx); err != nil {
+		clearSysSession(context.Background(), sctx)
+		return nil, err
 	}
-	enc.resetBatch()
-	com := &commitWorker{
-		InsertValues: insertValues,
-		controller:   e.controller,
+	return sctx, nil
+}
+
+func (e *LoadDataWorker) initWorkerSession(sctx sessionctx.Context) error {
+	userVars := e.UserSctx.GetSessionVars()
+	vars := sctx.GetSessionVars()
+	for name, val := range e.controller.ImportantSysVars {
+		if sv := variable.GetSysVar(name); sv == nil || sv.ReadOnly || !sv.HasSessionScope() {
+			continue
+		}
+		if err := vars.SetSystemVar(name, val); err != nil {
+			return err
+		}
+	}
+	vars.SQLMode = userVars.SQLMode
+	vars.CurrentDB = userVars.CurrentDB
+	vars.AllowWriteRowID = userVars.AllowWriteRowID
+	vars.AutoIncrementIncrement = userVars.AutoIncrementIncrement
+	vars.AutoIncrementOffset = userVars.AutoIncrementOffset
+	vars.ShardAllocateStep = userVars.ShardAllocateStep
+	vars.TxnMode = userVars.TxnMode
+	vars.ConstraintCheckInPlace = userVars.ConstraintCheckInPlace
+	// the column assignments may read the user variables which are set before
+	// the LOAD DATA.
+	collector := &userVarCollector{}
+	for _, assign := range e.controller.ColumnAssignments {
+		assign.Expr.Accept(collector)
+	}
+	for _, name := range collector.names {
+		if val, ok := userVars.GetUserVarVal(name); ok {
+			vars.SetUserVarVal(name, val)
+		}
+		if tp, ok := userVars.GetUserVarType(name); ok {
+			vars.SetUserVarType(name, tp)
+		}
+	}
+
+	if err := ResetContextOfStmt(sctx, &ast.LoadDataStmt{}); err != nil {
+		return err
+	}
+	if !e.controller.Restrictive {
+		setNonRestrictiveFlags(vars.StmtCtx)
+	}
+	vars.StmtCtx.MemTracker.AttachTo(userVars.StmtCtx.MemTracker)
+	return nil
+}
+
+// releaseWorkerSessions closes the sessions of the commitWorkers except the
+// user session. The sessions are not put back to the pool because their
+// variables are changed.
+func (e *LoadDataWorker) releaseWorkerSessions(ctx context.Context, committers []*commitWorker) {
+	for _, committer := range committers {
+		if committer.ctx == e.UserSctx {
+			continue
+		}
+		committer.ctx.GetSessionVars().StmtCtx.MemTracker.Detach()
+		clearSysSession(ctx, committer.ctx)
+	}
+}
+
+// mergeWorkerResults adds the rows and warnings of the worker sessions to the
+// user session, which reports the result of the LOAD DATA.
+func (e *LoadDataWorker) mergeWorkerResults(committers []*commitWorker) {
+	stmtCtx := e.UserSctx.GetSessionVars().StmtCtx
+	for _, committer := range committers {
+		if committer.ctx == e.UserSctx {
+			continue
+		}
+		workerStmtCtx := committer.ctx.GetSessionVars().StmtCtx
+		stmtCtx.AddRecordRows(workerStmtCtx.RecordRows())
+		stmtCtx.AddCopiedRows(workerStmtCtx.CopiedRows())
+		stmtCtx.AddDeletedRows(workerStmtCtx.DeletedRows())
+		stmtCtx.AddAffectedRows(workerStmtCtx.AffectedRows())
+		stmtCtx.AppendWarnings(workerStmtCtx.GetWarnings())
+		if stmtCtx.InsertID == 0 {
+			stmtCtx.InsertID = workerStmtCtx.InsertID
+		}
+	}
+}
+
+// userVarCollector collects the names of the user variables in an expression.
+type userVarCollector struct {
+	names []string
+}
+
+// Enter implements ast.Visitor interface.
+func (c *userVarCollector) Enter(n ast.Node) (ast.Node, bool) {
+	if v, ok := n.(*ast.VariableExpr); ok && !v.IsSystem {
+		c.names = append(c.names, strings.ToLower(v.Name))
 	}
-	return enc, com, nil
+	return n, false
 }
 
-// createInsertValues creates InsertValues from userSctx.
-func createInsertValues(e *LoadDataWorker) (insertVal *InsertValues, err error) {
+// Leave implements ast.Visitor interface.
+func (c *userVarCollector) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+// createInsertValues creates InsertValues on sctx.
+func createInsertValues(e *LoadDataWorker, sctx sessionctx.Context) (insertVal *InsertValues, err error) {
 	insertColumns := e.controller.InsertColumns
 	hasExtraHandle := false
 	for _, col := range insertColumns {
 		if col.Name.L == model.ExtraHandleName.L {
-			if !e.UserSctx.GetSessionVars().AllowWriteRowID {
+			if !sctx.GetSessionVars().AllowWriteRowID {
 				return nil, errors.Errorf("load data statement for _tidb_rowid are not supported")
 			}
 			hasExtraHandle = true
@@ -268,7 +490,7 @@ func createInsertValues(e *LoadDataWorker) (insertVal *InsertValues, err error)
 		}
 	}
 	ret := &InsertValues{
-		baseExecutor:   newBaseExecutor(e.UserSctx, nil, e.planInfo.ID),
+		baseExecutor:   newBaseExecutor(sctx, nil, e.planInfo.ID),
 		Table:          e.table,
 		Columns:        e.planInfo.Columns,
 		GenExprs:       e.planInfo.GenColExprs,
@@ -294,20 +516,29 @@ type encodeWorker struct {
 	exprWarnings []stmtctx.SQLWarn
 	killed       *uint32
 	rows         [][]types.Datum
+	// checkpoints is the committed position of source files of a resumable
+	// LOAD DATA, keyed by file path.
+	checkpoints map[string]loadDataCheckpoint
 }
 
 // commitTask is used for passing data from processStream goroutine to commitWork goroutine.
 type commitTask struct {
 	cnt  uint64
 	rows [][]types.Datum
+	// path and checkpoint are the source file and the position after the last
+	// row of this task, they are only used by resumable LOAD DATA.
+	path       string
+	checkpoint loadDataCheckpoint
 }
 
 // processStream always trys to build a parser from channel and process it. When
-// it returns nil, it means all data is read.
+// it returns nil, it means all data is read. If annotateErrPath is true, the
+// returned error is annotated with the path of the failed file.
 func (w *encodeWorker) processStream(
 	ctx context.Context,
 	inCh <-chan importer.LoadDataReaderInfo,
 	outCh chan<- commitTask,
+	annotateErrPath bool,
 ) error {
 	for {
 		select {
@@ -317,13 +548,30 @@ func (w *encodeWorker) processStream(
 			if !ok {
 				return nil
 			}
-			dataParser, err := w.controller.GetParser(ctx, readerInfo)
-			if err != nil {
-				return err
+			var (
+				dataParser mydump.Parser
+				path       string
+				err        error
+			)
+			if readerInfo.Remote != nil {
+				path = readerInfo.Remote.Path
+			}
+			if cp, ok := w.checkpoints[path]; ok {
+				logutil.Logger(ctx).Info("resume load data from checkpoint",
+					zap.String("path", path), zap.Int64("offset", cp.offset), zap.Int64("rowID", cp.rowID))
+				dataParser, err = w.controller.GetParserAt(ctx, readerInfo, cp.offset, cp.rowID)
+			} else {
+				dataParser, err = w.controller.GetParser(ctx, readerInfo)
+			}
+			if err == nil {
+				err = w.processOneStream(ctx, dataParser, path, outCh)
+				terror.Log(dataParser.Close())
 			}
-			err = w.processOneStream(ctx, dataParser, outCh)
-			terror.Log(dataParser.Close())
 			if err != nil {
+				if annotateErrPath && errors.Cause(err) != context.Canceled {
+					logutil.Logger(ctx).Warn("load data file failed", zap.String("path", path), zap.Error(err))
+					err = errors.Annotatef(err, "failed to load file %s", path)
+				}
 				return err
 			}
 		}
@@ -335,6 +583,7 @@ func (w *encodeWorker) processStream(
 func (w *encodeWorker) processOneStream(
 	ctx context.Context,
 	parser mydump.Parser,
+	path string,
 	outCh chan<- commitTask,
 ) (err error) {
 	defer func() {
@@ -358,6 +607,7 @@ func (w *encodeWorker) processOneStream(
 		if w.curBatchCnt == 0 {
 			return
 		}
+		offset, rowID := parser.Pos()
 
 	TrySendTask:
 		select {
@@ -370,8 +620,10 @@ func (w *encodeWorker) processOneStream(
 			}
 			goto TrySendTask
 		case outCh <- commitTask{
-			cnt:  w.curBatchCnt,
-			rows: w.rows,
+			cnt:        w.curBatchCnt,
+			rows:       w.rows,
+			path:       path,
+			checkpoint: loadDataCheckpoint{offset: offset, rowID: rowID},
 		}:
 		}
 		// reset rows buffer, will reallocate buffer but NOT reuse
@@ -513,10 +765,14 @@ func (w *encodeWorker) parserData2TableData(
 type commitWorker struct {
 	*InsertValues
 	controller *importer.LoadDataController
+	// checkpointer is not nil only when the LOAD DATA is resumable, then each
+	// task is committed in its own transaction together with its checkpoint.
+	checkpointer *loadDataCheckpointer
 }
 
 // commitWork commit batch sequentially. When returns nil, it means the job is
-// finished.
+// finished, the transaction is committed by finishTxns after all the workers
+// are finished.
 func (w *commitWorker) commitWork(ctx context.Context, inCh <-chan commitTask) (err error) {
 	defer func() {
 		r := recover()
@@ -526,16 +782,6 @@ func (w *commitWorker) commitWork(ctx context.Context, inCh <-chan commitTask) (
 				zap.Stack("stack"))
 			err = errors.Errorf("%v", r)
 		}
-
-		if err != nil {
-			background := context.Background()
-			w.ctx.StmtRollback(background, false)
-			w.ctx.RollbackTxn(background)
-		} else {
-			if err = w.ctx.CommitTxn(ctx); err != nil {
-				logutil.Logger(ctx).Error("commit error refresh", zap.Error(err))
-			}
-		}
 	}()
 
 	var (
@@ -549,10 +795,20 @@ func (w *commitWorker) commitWork(ctx context.Context, inCh <-chan commitTask) (
 			if !ok {
 				return nil
 			}
+			failpoint.Inject("commitWorkErrAfterTasks", func(val failpoint.Value) {
+				if taskCnt >= uint64(val.(int)) {
+					failpoint.Return(errors.New("mock commit work error"))
+				}
+			})
 			start := time.Now()
 			if err = w.commitOneTask(ctx, task); err != nil {
 				return err
 			}
+			if w.checkpointer != nil {
+				if err = w.commitCheckpointedTxn(ctx); err != nil {
+					return err
+				}
+			}
 			taskCnt++
 			logutil.Logger(ctx).Info("commit one task success",
 				zap.Duration("commit time usage", time.Since(start)),
@@ -574,10 +830,26 @@ func (w *commitWorker) commitOneTask(ctx context.Context, task commitTask) error
 	failpoint.Inject("commitOneTaskErr", func() {
 		failpoint.Return(errors.New("mock commit one task error"))
 	})
+	if w.checkpointer != nil {
+		if err = w.checkpointer.save(ctx, w.ctx, task.path, task.checkpoint); err != nil {
+			logutil.Logger(ctx).Error("commit error save checkpoint", zap.Error(err))
+			return err
+		}
+	}
 	w.ctx.StmtCommit(ctx)
 	return nil
 }
 
+// commitCheckpointedTxn commits the rows and checkpoint of the finished task,
+// and begins a new transaction for the next task.
+func (w *commitWorker) commitCheckpointedTxn(ctx context.Context) error {
+	if err := w.ctx.CommitTxn(ctx); err != nil {
+		logutil.Logger(ctx).Error("commit error checkpointed txn", zap.Error(err))
+		return err
+	}
+	return sessiontxn.NewTxn(ctx, w.ctx)
+}
+
 func (w *commitWorker) checkAndInsertOneBatch(ctx context.Context, rows [][]types.Datum, cnt uint64) error {
 	if w.stats != nil && w.stats.BasicRuntimeStats != nil {
 		// Since this method will not call by executor Next,
@@ -646,10 +918,11 @@ func (e *LoadDataWorker) TestLoadLocal(parser mydump.Parser) error {
 		return err
 	}
 	setNonRestrictiveFlags(e.UserSctx.GetSessionVars().StmtCtx)
-	encoder, committer, err := initEncodeCommitWorkers(e)
+	encoders, committers, err := initEncodeCommitWorkers(e, 1)
 	if err != nil {
 		return err
 	}
+	encoder, committer := encoders[0], committers[0]
 
 	ctx := context.Background()
 	err = sessiontxn.NewTxn(ctx, e.UserSctx)
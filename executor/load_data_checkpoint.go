@@ -0,0 +1,155 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+const (
+	// loadDataCheckpointTable is the system table which records the committed
+	// position of each source file of a resumable LOAD DATA.
+	loadDataCheckpointTable = "tidb_load_data_checkpoints"
+	// loadDataCheckpointMaxPathLen is the length of file_path column of
+	// mysql.tidb_load_data_checkpoints.
+	loadDataCheckpointMaxPathLen = 512
+)
+
+// loadDataCheckpoint is the committed position of one source file.
+type loadDataCheckpoint struct {
+	offset int64
+	rowID  int64
+}
+
+// loadDataCheckpointer reads and writes the checkpoints of a resumable LOAD
+// DATA. The checkpoint is written in the same transaction as the rows it
+// covers, so a batch and its checkpoint are either both committed or both
+// rolled back, which makes resuming idempotent.
+type loadDataCheckpointer struct {
+	token string
+	tbl   table.Table
+}
+
+func newLoadDataCheckpointer(is infoschema.InfoSchema, token string) (*loadDataCheckpointer, error) {
+	tbl, err := is.TableByName(model.NewCIStr(mysql.SystemDB), model.NewCIStr(loadDataCheckpointTable))
+	if err != nil {
+		return nil, err
+	}
+	if !tbl.Meta().IsCommonHandle {
+		return nil, errors.Errorf("%s.%s should use clustered primary key", mysql.SystemDB, loadDataCheckpointTable)
+	}
+	return &loadDataCheckpointer{token: token, tbl: tbl}, nil
+}
+
+// checkPath returns an error if the path of the source file is too long to be
+// checkpointed, it's checked before loading any data.
+func (*loadDataCheckpointer) checkPath(path string) error {
+	if utf8.RuneCountInString(path) > loadDataCheckpointMaxPathLen {
+		return exeerrors.ErrLoadDataInvalidURI.FastGenByArgs(fmt.Sprintf(
+			"the path of file %s is longer than %d characters, which can't be checkpointed by resume_token", path, loadDataCheckpointMaxPathLen))
+	}
+	return nil
+}
+
+// row builds the datums of a checkpoint record, the columns are ordered as
+// they are defined in CreateLoadDataCheckpoints.
+func (c *loadDataCheckpointer) row(path string, cp loadDataCheckpoint, updateTime types.Time) []types.Datum {
+	cols := c.tbl.Meta().Columns
+	return []types.Datum{
+		types.NewCollationStringDatum(c.token, cols[0].GetCollate()),
+		types.NewCollationStringDatum(path, cols[1].GetCollate()),
+		types.NewIntDatum(cp.offset),
+		types.NewIntDatum(cp.rowID),
+		types.NewTimeDatum(updateTime),
+	}
+}
+
+func (c *loadDataCheckpointer) handle(sctx sessionctx.Context, row []types.Datum) (kv.Handle, error) {
+	tblInfo := c.tbl.Meta()
+	pkIdx := tables.FindPrimaryIndex(tblInfo)
+	pkDts := make([]types.Datum, 0, len(pkIdx.Columns))
+	for _, idxCol := range pkIdx.Columns {
+		pkDts = append(pkDts, row[idxCol.Offset])
+	}
+	tablecodec.TruncateIndexValues(tblInfo, pkIdx, pkDts)
+	handleBytes, err := codec.EncodeKey(sctx.GetSessionVars().StmtCtx, nil, pkDts...)
+	if err != nil {
+		return nil, err
+	}
+	return kv.NewCommonHandle(handleBytes)
+}
+
+// load returns the checkpoint of the source file, found is false if the file
+// has no checkpoint yet.
+func (c *loadDataCheckpointer) load(sctx sessionctx.Context, path string) (cp loadDataCheckpoint, found bool, err error) {
+	h, err := c.handle(sctx, c.row(path, loadDataCheckpoint{}, types.ZeroTimestamp))
+	if err != nil {
+		return cp, false, err
+	}
+	row, err := tables.RowWithCols(c.tbl, sctx, h, c.tbl.Cols())
+	if err != nil {
+		if kv.IsErrNotFound(err) {
+			return cp, false, nil
+		}
+		return cp, false, err
+	}
+	return loadDataCheckpoint{offset: row[2].GetInt64(), rowID: row[3].GetInt64()}, true, nil
+}
+
+// save writes the checkpoint of the source file into the current transaction.
+func (c *loadDataCheckpointer) save(ctx context.Context, sctx sessionctx.Context, path string, cp loadDataCheckpoint) error {
+	now := types.NewTime(types.FromGoTime(time.Now().UTC()), mysql.TypeTimestamp, types.MaxFsp)
+	newRow := c.row(path, cp, now)
+	h, err := c.handle(sctx, newRow)
+	if err != nil {
+		return err
+	}
+	oldRow, err := tables.RowWithCols(c.tbl, sctx, h, c.tbl.Cols())
+	if err != nil {
+		if !kv.IsErrNotFound(err) {
+			return err
+		}
+		_, err = c.tbl.AddRecord(sctx, newRow)
+		return err
+	}
+	touched := []bool{false, false, true, true, true}
+	return c.tbl.UpdateRecord(ctx, sctx, h, oldRow, newRow, touched)
+}
+
+// clear removes the checkpoints of the token after all the source files are
+// loaded, so they don't pile up in the system table.
+func (c *loadDataCheckpointer) clear(ctx context.Context, sctx sessionctx.Context) error {
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalLoadData)
+	_, _, err := sctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(ctx, nil,
+		"DELETE FROM %n.%n WHERE resume_token = %?", mysql.SystemDB, loadDataCheckpointTable, c.token)
+	return err
+}
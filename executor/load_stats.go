@@ -16,14 +16,14 @@ package executor
 
 import (
 	"context"
-	"encoding/json"
+	"io"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/sessionctx"
-	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
 )
 
 var _ Executor = &LoadStatsExec{}
@@ -76,16 +76,16 @@ func (e *LoadStatsExec) Open(_ context.Context) error {
 	return nil
 }
 
-// Update updates the stats of the corresponding table according to the data.
-func (e *LoadStatsInfo) Update(data []byte) error {
-	jsonTbl := &handle.JSONTable{}
-	if err := json.Unmarshal(data, jsonTbl); err != nil {
-		return errors.Trace(err)
-	}
+// UpdateFromReader updates the stats of the corresponding table according to the data read from r.
+// The data is decoded as a stream, and the memory used is tracked by the memory tracker of the session.
+func (e *LoadStatsInfo) UpdateFromReader(r io.Reader) error {
 	do := domain.GetDomain(e.Ctx)
 	h := do.StatsHandle()
 	if h == nil {
 		return errors.New("Load Stats: handle is nil")
 	}
-	return h.LoadStatsFromJSON(e.Ctx.GetInfoSchema().(infoschema.InfoSchema), jsonTbl)
+	memTracker := memory.NewTracker(memory.LabelForLoadStats, -1)
+	memTracker.AttachTo(e.Ctx.GetSessionVars().MemTracker)
+	defer memTracker.Detach()
+	return h.LoadStatsFromJSONReader(e.Ctx.GetInfoSchema().(infoschema.InfoSchema), r, memTracker)
 }
@@ -32,6 +32,7 @@ import (
 	"github.com/pingcap/sysutil"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
@@ -43,9 +44,12 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/dbterror/exeerrors"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/pdapi"
 	"github.com/pingcap/tidb/util/set"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 	"golang.org/x/exp/slices"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -67,6 +71,50 @@ type memTableRetriever interface {
 	getRuntimeStats() execdetails.RuntimeStats
 }
 
+// memTableResultLimiter limits the total bytes of the rows retrieved by a memory table reader, the query fails with
+// an error instead of running out of memory once the limit is exceeded. The rows of the tables in all the schemas are
+// checked while they're produced, the other retrievers are wrapped and checked batch by batch.
+type memTableResultLimiter struct {
+	memTableRetriever
+	table    *model.TableInfo
+	maxBytes int64
+	consumed int64
+	// checkedRows is the number of the produced rows which have been checked by checkRows.
+	checkedRows int
+}
+
+// retrieve implements the memTableRetriever interface
+func (l *memTableResultLimiter) retrieve(ctx context.Context, sctx sessionctx.Context) ([][]types.Datum, error) {
+	rows, err := l.memTableRetriever.retrieve(ctx, sctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.consume(rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// checkRows checks the rows which are appended to the produced rows since the last check.
+func (l *memTableResultLimiter) checkRows(produced [][]types.Datum) error {
+	if l == nil || l.checkedRows >= len(produced) {
+		return nil
+	}
+	rows := produced[l.checkedRows:]
+	l.checkedRows = len(produced)
+	return l.consume(rows)
+}
+
+func (l *memTableResultLimiter) consume(rows [][]types.Datum) error {
+	for _, row := range rows {
+		l.consumed += types.EstimatedMemUsage(row, 1)
+	}
+	if l.consumed > l.maxBytes {
+		return exeerrors.ErrMemTableResultTooLarge.GenWithStackByArgs(l.table.Name.O, l.maxBytes)
+	}
+	return nil
+}
+
 // MemTableReaderExec executes memTable information retrieving from the MemTable components
 type MemTableReaderExec struct {
 	baseExecutor
@@ -317,6 +365,157 @@ func (e *clusterServerInfoRetriever) retrieve(ctx context.Context, sctx sessionc
 	return infoschema.FetchClusterServerInfoWithoutPrivilegeCheck(ctx, sctx, serversInfo, e.serverInfoType, true)
 }
 
+// instanceMetricsRetriever is used to read the metrics of every tidb instance from its status address,
+// each instance is turned into a row by buildRow.
+type instanceMetricsRetriever struct {
+	dummyCloser
+	extractor *plannercore.ClusterTableExtractor
+	buildRow  func(address string, families map[string]*dto.MetricFamily) []types.Datum
+	retrieved bool
+}
+
+// retrieve implements the memTableRetriever interface
+func (e *instanceMetricsRetriever) retrieve(_ context.Context, sctx sessionctx.Context) ([][]types.Datum, error) {
+	if !hasPriv(sctx, mysql.ProcessPriv) {
+		return nil, plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+	if e.extractor.SkipRequest || e.retrieved {
+		return nil, nil
+	}
+	e.retrieved = true
+	serversInfo, err := infoschema.GetClusterServerInfo(sctx)
+	failpoint.Inject("mockClusterPlanCacheServerInfo", func(val failpoint.Value) {
+		if s := val.(string); len(s) > 0 {
+			// erase the error
+			serversInfo, err = parseFailpointServerInfo(s), nil
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	serversInfo = infoschema.FilterClusterServerInfo(serversInfo, set.NewStringSet("tidb"), e.extractor.Instances)
+
+	type result struct {
+		idx int
+		row []types.Datum
+		err error
+	}
+	wg := sync.WaitGroup{}
+	ch := make(chan result, len(serversInfo))
+	for i, srv := range serversInfo {
+		if len(srv.StatusAddr) == 0 {
+			sctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("%s node %s does not contain status address", srv.ServerType, srv.Address))
+			continue
+		}
+		wg.Add(1)
+		go func(index int, address, statusAddr string) {
+			util.WithRecovery(func() {
+				defer wg.Done()
+				families, err := fetchInstanceMetrics(statusAddr)
+				if err != nil {
+					ch <- result{idx: index, err: err}
+					return
+				}
+				ch <- result{idx: index, row: e.buildRow(address, families)}
+			}, nil)
+		}(i, srv.Address, srv.StatusAddr)
+	}
+	wg.Wait()
+	close(ch)
+
+	// Keep the original order to make the result more stable
+	results := make([]result, 0, len(serversInfo))
+	for result := range ch {
+		if result.err != nil {
+			sctx.GetSessionVars().StmtCtx.AppendWarning(result.err)
+			continue
+		}
+		results = append(results, result)
+	}
+	slices.SortFunc(results, func(i, j result) bool { return i.idx < j.idx })
+	rows := make([][]types.Datum, 0, len(results))
+	for _, result := range results {
+		rows = append(rows, result.row)
+	}
+	return rows, nil
+}
+
+// fetchInstanceMetrics reads the metrics of the tidb instance from its status address.
+func fetchInstanceMetrics(statusAddr string) (map[string]*dto.MetricFamily, error) {
+	url := fmt.Sprintf("%s://%s/metrics", util.InternalHTTPSchema(), statusAddr)
+	resp, err := util.InternalHTTPClient().Get(url)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() {
+		terror.Log(resp.Body.Close())
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("request %s failed: %s", url, resp.Status)
+	}
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	return families, errors.Trace(err)
+}
+
+// sumMetric sums the counters or the gauges of the metric whose type label is accepted by match,
+// all of them are summed if match is nil.
+func sumMetric(families map[string]*dto.MetricFamily, name string, match func(tp string) bool) float64 {
+	var total float64
+	for _, m := range families[name].GetMetric() {
+		if match != nil {
+			matched := false
+			for _, label := range m.GetLabel() {
+				if label.GetName() == metrics.LblType && match(label.GetValue()) {
+					matched = true
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+		if m.GetCounter() != nil {
+			total += m.GetCounter().GetValue()
+		} else {
+			total += m.GetGauge().GetValue()
+		}
+	}
+	return total
+}
+
+// planCacheRow builds the row of CLUSTER_PLAN_CACHE from the plan cache metrics of the tidb instance.
+func planCacheRow(address string, families map[string]*dto.MetricFamily) []types.Datum {
+	hits := sumMetric(families, "tidb_server_plan_cache_total", nil)
+	// The statements which can't use the plan cache are not the plan cache lookups.
+	misses := sumMetric(families, "tidb_server_plan_cache_miss_total", func(tp string) bool { return tp != "non-prepared-unsupported" })
+	hitRate := types.NewDatum(nil)
+	if hits+misses > 0 {
+		hitRate.SetFloat64(hits / (hits + misses))
+	}
+	return []types.Datum{
+		types.NewStringDatum(address),
+		types.NewIntDatum(int64(sumMetric(families, "tidb_server_plan_cache_instance_plan_num_total", nil))),
+		types.NewIntDatum(int64(sumMetric(families, "tidb_server_plan_cache_instance_memory_usage", nil))),
+		hitRate,
+		types.NewIntDatum(int64(sumMetric(families, "tidb_server_plan_cache_eviction_total", nil))),
+	}
+}
+
+// coprocessorCacheRow builds the row of COPROCESSOR_CACHE from the coprocessor cache metrics of the tidb instance.
+func coprocessorCacheRow(address string, families map[string]*dto.MetricFamily) []types.Datum {
+	ofType := func(name, tp string) types.Datum {
+		return types.NewIntDatum(int64(sumMetric(families, name, func(t string) bool { return t == tp })))
+	}
+	return []types.Datum{
+		types.NewStringDatum(address),
+		ofType("tidb_distsql_copr_cache_usage", "size"),
+		ofType("tidb_distsql_copr_cache_usage", "entries"),
+		ofType("tidb_distsql_copr_cache", "hit"),
+		ofType("tidb_distsql_copr_cache", "miss"),
+		ofType("tidb_distsql_copr_cache", "evict"),
+	}
+}
+
 func parseFailpointServerInfo(s string) []infoschema.ServerInfo {
 	servers := strings.Split(s, ";")
 	serversInfo := make([]infoschema.ServerInfo, 0, len(servers))
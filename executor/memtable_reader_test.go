@@ -20,6 +20,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -30,8 +31,11 @@ import (
 	"github.com/pingcap/fn"
 	"github.com/pingcap/sysutil"
 	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/pingcap/tidb/util/pdapi"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	pmodel "github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
@@ -446,6 +450,112 @@ type testServer struct {
 	logFile string
 }
 
+func TestTiDBClusterPlanCache(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_prepared_plan_cache=1")
+	tk.MustExec("create table t(a int)")
+	tk.MustExec("prepare stmt from 'select * from t where a = ?'")
+	tk.MustExec("set @a = 1")
+	tk.MustExec("execute stmt using @a")
+	tk.MustExec("execute stmt using @a")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// mock the status servers of the tidb instances exposing the plan cache metrics
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.PlanCacheCounter, metrics.PlanCacheMissCounter, metrics.PlanCacheInstanceMemoryUsage,
+		metrics.PlanCacheInstancePlanNumCounter, metrics.PlanCacheEvictionCounter)
+	router := mux.NewRouter()
+	router.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	var addresses, servers []string
+	for i := 0; i < 2; i++ {
+		server := httptest.NewServer(router)
+		defer server.Close()
+		address := strings.TrimPrefix(server.URL, "http://")
+		addresses = append(addresses, address)
+		servers = append(servers, strings.Join([]string{"tidb", address, address}, ","))
+	}
+	// the plan cache of the other node types is not read
+	servers = append(servers, strings.Join([]string{"tikv", "127.0.0.1:20160", "127.0.0.1:20180"}, ","))
+
+	fpName := "github.com/pingcap/tidb/executor/mockClusterPlanCacheServerInfo"
+	require.NoError(t, failpoint.Enable(fpName, fmt.Sprintf(`return("%s")`, strings.Join(servers, ";"))))
+	defer func() { require.NoError(t, failpoint.Disable(fpName)) }()
+
+	sql := "select instance, entry_count > 0, memory_usage > 0, hit_rate > 0, eviction_count >= 0 from information_schema.cluster_plan_cache"
+	tk.MustQuery(sql).Check(testkit.Rows(
+		addresses[0]+" 1 1 1 1",
+		addresses[1]+" 1 1 1 1",
+	))
+	require.Len(t, tk.Session().GetSessionVars().StmtCtx.GetWarnings(), 0)
+
+	// the predicate on the instance is pushed down
+	sql = fmt.Sprintf("select instance from information_schema.cluster_plan_cache where instance = '%s'", addresses[1])
+	explain := tk.MustQuery("explain " + sql).Rows()
+	require.Contains(t, explain[len(explain)-1][4], fmt.Sprintf(`instances:["%s"]`, addresses[1]))
+	tk.MustQuery(sql).Check(testkit.Rows(addresses[1]))
+	tk.MustQuery("select * from information_schema.cluster_plan_cache where instance = '127.0.0.1:20160'").Check(testkit.Rows())
+}
+
+func TestTiDBCoprocessorCache(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key)")
+	tk.MustExec("insert into t values (1), (2), (3)")
+
+	// mock the status server of the tidb instance exposing the coprocessor cache metrics
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.DistSQLCoprCacheCounter, metrics.DistSQLCoprCacheUsage)
+	router := mux.NewRouter()
+	router.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	server := httptest.NewServer(router)
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+	servers := []string{
+		strings.Join([]string{"tidb", address, address}, ","),
+		strings.Join([]string{"tikv", "127.0.0.1:20160", "127.0.0.1:20180"}, ","),
+	}
+	fpName := "github.com/pingcap/tidb/executor/mockClusterPlanCacheServerInfo"
+	require.NoError(t, failpoint.Enable(fpName, fmt.Sprintf(`return("%s")`, strings.Join(servers, ";"))))
+	defer func() { require.NoError(t, failpoint.Disable(fpName)) }()
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/store/mockstore/unistore/cophandler/mockCopCacheInUnistore", `return(123)`))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/store/mockstore/unistore/cophandler/mockCopCacheInUnistore"))
+	}()
+
+	cacheStats := func() (hits, misses, entries int64) {
+		rows := tk.MustQuery("select instance, hit_count, miss_count, entry_count from information_schema.coprocessor_cache").Rows()
+		require.Len(t, rows, 1)
+		require.Equal(t, address, rows[0][0])
+		hits, err := strconv.ParseInt(rows[0][1].(string), 10, 64)
+		require.NoError(t, err)
+		misses, err = strconv.ParseInt(rows[0][2].(string), 10, 64)
+		require.NoError(t, err)
+		entries, err = strconv.ParseInt(rows[0][3].(string), 10, 64)
+		require.NoError(t, err)
+		return hits, misses, entries
+	}
+
+	sql := "select * from t where a > 1"
+	tk.MustQuery(sql).Check(testkit.Rows("2", "3"))
+	hits, misses, _ := cacheStats()
+	// the identical scans are served by the coprocessor cache
+	require.Eventually(t, func() bool {
+		tk.MustQuery(sql).Check(testkit.Rows("2", "3"))
+		newHits, newMisses, entries := cacheStats()
+		return newHits > hits && newMisses >= misses && entries > 0
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// the predicate on the instance is pushed down
+	sql = fmt.Sprintf("select instance from information_schema.coprocessor_cache where instance = '%s'", address)
+	explain := tk.MustQuery("explain " + sql).Rows()
+	require.Contains(t, explain[len(explain)-1][4], fmt.Sprintf(`instances:["%s"]`, address))
+	tk.MustQuery(sql).Check(testkit.Rows(address))
+	tk.MustQuery("select * from information_schema.coprocessor_cache where instance = '127.0.0.1:20160'").Check(testkit.Rows())
+}
+
 func TestTiDBClusterLog(t *testing.T) {
 	testServers := createClusterGRPCServer(t)
 	defer func() {
@@ -902,3 +1012,20 @@ func TestTiDBClusterLogError(t *testing.T) {
 	err = tk.QueryToErr("select * from information_schema.cluster_log where time>='2019/08/26 06:18:13.011' and time<'2019/08/26 16:18:13.011'")
 	require.EqualError(t, err, "denied to scan full logs (use `SELECT * FROM cluster_log WHERE message LIKE '%'` explicitly if intentionally)")
 }
+
+func TestMemTableMaxResultBytes(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b varchar(10))")
+
+	q := "select * from information_schema.columns where table_schema = 'test'"
+	tk.MustQuery(q).Check(testkit.Rows(
+		"def test t a 1 <nil> YES int <nil> <nil> 11 0 <nil> <nil> <nil> int(11)   select,insert,update,references  ",
+		"def test t b 2 <nil> YES varchar 10 40 <nil> <nil> <nil> utf8mb4 utf8mb4_bin varchar(10)   select,insert,update,references  "))
+	tk.MustExec("set @@tidb_memtable_max_result_bytes = 1024")
+	require.EqualError(t, tk.QueryToErr(q), "[executor:8178]The result of memory table COLUMNS exceeds tidb_memtable_max_result_bytes 1024 bytes, consider adding filters to the query")
+	require.EqualError(t, tk.QueryToErr("select * from information_schema.tables"), "[executor:8178]The result of memory table TABLES exceeds tidb_memtable_max_result_bytes 1024 bytes, consider adding filters to the query")
+	tk.MustExec("set @@tidb_memtable_max_result_bytes = 0")
+	require.Len(t, tk.MustQuery(q).Rows(), 2)
+}
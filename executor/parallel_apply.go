@@ -80,6 +80,7 @@ type ParallelNestedLoopApplyExec struct {
 	// fields about cache
 	cache              *applyCache
 	useCache           bool
+	cacheCapacity      int64
 	cacheHitCounter    int64
 	cacheAccessCounter int64
 
@@ -122,7 +123,7 @@ func (e *ParallelNestedLoopApplyExec) Open(ctx context.Context) error {
 	}
 
 	if e.useCache {
-		if e.cache, err = newApplyCache(e.ctx); err != nil {
+		if e.cache, err = newApplyCache(e.cacheCapacity); err != nil {
 			return err
 		}
 		e.cache.GetMemTracker().AttachTo(e.memTracker)
@@ -593,6 +593,33 @@ func TestApplyCacheRatio(t *testing.T) {
 	require.False(t, checkRatio(""))
 }
 
+func TestApplyCacheEviction(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+	values := make([]string, 0, 200)
+	for i := 0; i < 200; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d)", i%50, i))
+	}
+	tk.MustExec("insert into t1 values " + strings.Join(values, ","))
+	tk.MustExec("insert into t2 values " + strings.Join(values[:100], ","))
+	sql := "select a, b, (select count(*) from t2 where t2.a < t1.a) from t1 order by b"
+
+	tk.MustExec("set tidb_mem_quota_apply_cache = 0")
+	expected := tk.MustQuery(sql).Rows()
+	for _, parallel := range []string{"off", "on"} {
+		tk.MustExec("set tidb_enable_parallel_apply = " + parallel)
+		// The quota only holds a few entries, so the least recently used ones are evicted.
+		for _, quota := range []string{"1024", "33554432"} {
+			tk.MustExec("set tidb_mem_quota_apply_cache = " + quota)
+			tk.MustQuery(sql).Check(expected)
+		}
+	}
+}
+
 func TestApplyGoroutinePanic(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -105,6 +105,55 @@ partition p2 values less than (10))`)
 	tk.MustQuery("select /*+ use_index(i_c, i_id) */ * from pt where id = 4 or c < 7").Sort().Check(testkit.Rows("0 0", "2 2", "4 4", "6 6"))
 }
 
+func TestForceStaticPartitionPrune(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustQuery("select @@tidb_force_static_partition_prune").Check(testkit.Rows("0"))
+	tk.MustExec(`create table pt (id int, c int, key i_id(id), key i_c(c)) partition by range (c) (
+partition p0 values less than (4),
+partition p1 values less than (7),
+partition p2 values less than (10))`)
+	tk.MustExec("insert into pt values (0, 0), (2, 2), (4, 4), (6, 6), (7, 7), (9, 9), (null, null)")
+	tk.MustExec("analyze table pt")
+	tk.MustExec("set @@tidb_enable_index_merge = 1")
+
+	queries := []string{
+		// Table reader
+		"select * from pt",
+		"select * from pt where c < 2 or c >= 9",
+		// Index reader
+		"select c from pt where c > 1",
+		// Index lookup
+		"select /*+ use_index(pt, i_id) */ * from pt where id < 10",
+		// Index merge
+		"select /*+ use_index_merge(pt, i_c, i_id) */ * from pt where id = 4 or c < 7",
+	}
+	isStatic := func(sql string) bool {
+		for _, row := range tk.MustQuery("explain format = 'brief' " + sql).Rows() {
+			if strings.Contains(row[0].(string), "PartitionUnion") {
+				return true
+			}
+		}
+		return false
+	}
+	for _, sql := range queries {
+		tk.MustExec("set @@tidb_force_static_partition_prune = 0")
+		require.False(t, isStatic(sql), sql)
+		expected := tk.MustQuery(sql).Sort().Rows()
+
+		tk.MustExec("set @@tidb_force_static_partition_prune = 1")
+		require.True(t, isStatic(sql), sql)
+		tk.MustQuery(sql).Sort().Check(expected)
+	}
+	// The query on the other tables is not affected.
+	tk.MustExec("create table t (a int, key(a))")
+	tk.MustExec("insert into t values (1), (2)")
+	tk.MustQuery("select * from t").Sort().Check(testkit.Rows("1", "2"))
+}
+
 func TestPartitionIndexJoin(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 
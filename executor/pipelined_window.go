@@ -43,6 +43,8 @@ type PipelinedWindowExec struct {
 	start              *core.FrameBound
 	end                *core.FrameBound
 	groupChecker       *vecGroupChecker
+	// excluder is nil if the frame doesn't exclude any rows.
+	excluder *frameExcluder
 
 	// childResult stores the child chunk. Note that even if remaining is 0, e.rows might still references rows in data[0].chk after returned it to upper executor, since there is no guarantee what the upper executor will do to the returned chunk, it might destroy the data (as in the benchmark test, it reused the chunk to pull data, and it will be chk.Reset(), causing panicking). So dataIdx, accumulated and dropped are added to ensure that chunk will only be returned if there is no row reference.
 	childResult *chunk.Chunk
@@ -90,9 +92,12 @@ func (e *PipelinedWindowExec) Open(ctx context.Context) (err error) {
 	e.data = make([]dataInfo, 0)
 	e.dataIdx = 0
 	e.slidingWindowFuncs = make([]aggfuncs.SlidingWindowAggFunc, len(e.windowFuncs))
-	for i, windowFunc := range e.windowFuncs {
-		if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
-			e.slidingWindowFuncs[i] = slidingWindowAggFunc
+	// The excluded rows break the continuity of the frame, so the window can't be slid.
+	if e.excluder == nil {
+		for i, windowFunc := range e.windowFuncs {
+			if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
+				e.slidingWindowFuncs[i] = slidingWindowAggFunc
+			}
 		}
 	}
 	e.rows = make([]chunk.Row, 0)
@@ -379,9 +384,17 @@ func (e *PipelinedWindowExec) produce(ctx sessionctx.Context, chk *chunk.Chunk,
 			}
 		} else {
 			e.emptyFrame = false
+			frameRows := e.getRows(start, end)
+			if e.excluder != nil {
+				frameRows, err = e.excluder.getFrameRows(ctx, e.getRow, e.curRowIdx, start, end)
+				if err != nil {
+					return
+				}
+			}
 			for i, wf := range e.windowFuncs {
 				slidingWindowAggFunc := e.slidingWindowFuncs[i]
-				if e.lastStartRow != start || e.lastEndRow != end {
+				// The excluded rows differ from row to row even if the frame is not moved.
+				if e.lastStartRow != start || e.lastEndRow != end || e.excluder != nil {
 					if slidingWindowAggFunc != nil && e.initializedSlidingWindow {
 						err = slidingWindowAggFunc.Slide(ctx, e.getRow, e.lastStartRow, e.lastEndRow, start-e.lastStartRow, end-e.lastEndRow, e.partialResults[i])
 					} else {
@@ -393,7 +406,7 @@ func (e *PipelinedWindowExec) produce(ctx sessionctx.Context, chk *chunk.Chunk,
 						}
 						// TODO(zhifeng): track memory usage here
 						wf.ResetPartialResult(e.partialResults[i])
-						_, err = wf.UpdatePartialResult(ctx, e.getRows(start, end), e.partialResults[i])
+						_, err = wf.UpdatePartialResult(ctx, frameRows, e.partialResults[i])
 					}
 				}
 				if err != nil {
@@ -18,7 +18,6 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -32,9 +31,9 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/sessiontxn"
-	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/replayer"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"go.uber.org/zap"
@@ -56,6 +55,8 @@ type PlanReplayerCaptureInfo struct {
 	SQLDigest  string
 	PlanDigest string
 	Remove     bool
+	// Reset indicates all the capture tasks are removed.
+	Reset bool
 }
 
 // PlanReplayerDumpInfo indicates dump info
@@ -75,6 +76,9 @@ func (e *PlanReplayerExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		return nil
 	}
 	if e.CaptureInfo != nil {
+		if e.CaptureInfo.Reset {
+			return e.resetCaptureTasks(ctx)
+		}
 		if e.CaptureInfo.Remove {
 			return e.removeCaptureTask(ctx)
 		}
@@ -125,6 +129,31 @@ func (e *PlanReplayerExec) removeCaptureTask(ctx context.Context) error {
 	return nil
 }
 
+// resetCaptureTasks removes all the capture tasks, the number of the removed tasks is reported as the affected rows.
+func (e *PlanReplayerExec) resetCaptureTasks(ctx context.Context) error {
+	ctx1 := kv.WithInternalSourceType(ctx, kv.InternalTxnStats)
+	// The delete runs in a system session, so its affected rows are exactly the removed tasks.
+	sysSession, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(ctx1, sysSession)
+	_, err = sysSession.(sqlexec.SQLExecutor).ExecuteInternal(ctx1, "delete from mysql.plan_replayer_task")
+	if err != nil {
+		logutil.BgLogger().Warn("reset mysql.plan_replayer_task failed",
+			zap.Error(err))
+		return err
+	}
+	e.ctx.GetSessionVars().StmtCtx.AddAffectedRows(sysSession.GetSessionVars().StmtCtx.AffectedRows())
+	err = domain.GetDomain(e.ctx).GetPlanReplayerHandle().CollectPlanReplayerTask()
+	if err != nil {
+		logutil.BgLogger().Warn("collect task failed", zap.Error(err))
+	}
+	logutil.BgLogger().Info("reset plan replayer capture tasks success")
+	e.endFlag = true
+	return nil
+}
+
 func (e *PlanReplayerExec) registerCaptureTask(ctx context.Context) error {
 	ctx1 := kv.WithInternalSourceType(ctx, kv.InternalTxnStats)
 	exists, err := domain.CheckPlanReplayerTaskExists(ctx1, e.ctx, e.CaptureInfo.SQLDigest, e.CaptureInfo.PlanDigest)
@@ -434,27 +463,22 @@ func createSchemaAndItems(ctx sessionctx.Context, f *zip.File) error {
 }
 
 func loadStats(ctx sessionctx.Context, f *zip.File) error {
-	jsonTbl := &handle.JSONTable{}
 	r, err := f.Open()
 	if err != nil {
 		return errors.AddStack(err)
 	}
 	//nolint: errcheck
 	defer r.Close()
-	buf := new(bytes.Buffer)
-	_, err = buf.ReadFrom(r)
-	if err != nil {
-		return errors.AddStack(err)
-	}
-	if err := json.Unmarshal(buf.Bytes(), jsonTbl); err != nil {
-		return errors.AddStack(err)
-	}
 	do := domain.GetDomain(ctx)
 	h := do.StatsHandle()
 	if h == nil {
 		return errors.New("plan replayer: hanlde is nil")
 	}
-	return h.LoadStatsFromJSON(ctx.GetInfoSchema().(infoschema.InfoSchema), jsonTbl)
+	// Decode the stats as a stream like LOAD STATS, so the memory usage is bounded for the large stats files.
+	memTracker := memory.NewTracker(memory.LabelForLoadStats, -1)
+	memTracker.AttachTo(ctx.GetSessionVars().MemTracker)
+	defer memTracker.Detach()
+	return h.LoadStatsFromJSONReader(ctx.GetInfoSchema().(infoschema.InfoSchema), r, memTracker)
 }
 
 // Update updates the data of the corresponding table.
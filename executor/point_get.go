@@ -17,6 +17,7 @@ package executor
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
@@ -35,6 +36,7 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/dbterror"
+	"github.com/pingcap/tidb/util/dbterror/exeerrors"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/logutil/consistency"
 	"github.com/pingcap/tidb/util/rowcodec"
@@ -100,6 +102,15 @@ func (b *executorBuilder) buildPointGet(p *plannercore.PointGetPlan) Executor {
 		b.err = err
 		return nil
 	}
+	replicaReadFollower := b.canPointGetReadFollower(p, snapshotTS)
+	if replicaReadFollower {
+		e.snapshot.SetOption(kv.ReplicaRead, kv.ReplicaReadFollower)
+	}
+	failpoint.Inject("assertPointGetFollowerRead", func(val failpoint.Value) {
+		if !e.ctx.GetSessionVars().InRestrictedSQL && val.(bool) != replicaReadFollower {
+			panic("point get follower read option fail")
+		}
+	})
 	if p.TblInfo.TableCacheStatusType == model.TableCacheStatusEnable {
 		if cacheTable := b.getCacheTable(p.TblInfo, snapshotTS); cacheTable != nil {
 			e.snapshot = cacheTableSnapshot{e.snapshot, cacheTable}
@@ -113,6 +124,28 @@ func (b *executorBuilder) buildPointGet(p *plannercore.PointGetPlan) Executor {
 	return e
 }
 
+// canPointGetReadFollower checks whether the point get reads from the followers by tidb_point_get_follower_read.
+// Only the non-locking reads of the sessions reading from the leaders are eligible. The snapshot shared with the
+// explicit transaction is not changed, otherwise the other reads of the transaction would read from the followers too.
+func (b *executorBuilder) canPointGetReadFollower(p *plannercore.PointGetPlan, snapshotTS uint64) bool {
+	sessVars := b.ctx.GetSessionVars()
+	if !sessVars.PointGetFollowerRead || sessVars.GetReplicaRead() != kv.ReplicaReadLeader {
+		return false
+	}
+	if p.Lock || b.inInsertStmt || b.inUpdateStmt || b.inDeleteStmt || b.inSelectLockStmt {
+		return false
+	}
+	// Same as tidb_replica_read, the reads checking the ts of the read committed transactions read from the leaders.
+	if sessVars.StmtCtx.RCCheckTS || sessVars.RcWriteCheckTS || p.TblInfo.TempTableType != model.TempTableNone {
+		return false
+	}
+	if !sessVars.InTxn() {
+		return true
+	}
+	txnCtx := sessVars.TxnCtx
+	return !(txnCtx.StartTS == txnCtx.GetForUpdateTS() && txnCtx.StartTS == snapshotTS)
+}
+
 // PointGetExecutor executes point select query.
 type PointGetExecutor struct {
 	baseExecutor
@@ -133,6 +166,8 @@ type PointGetExecutor struct {
 	lock             bool
 	lockWaitTime     int64
 	rowDecoder       *rowcodec.ChunkDecoder
+	// verifyChecksum indicates whether to verify the row level checksum of the row read.
+	verifyChecksum bool
 
 	columns []*model.ColumnInfo
 	// virtualColumnIndex records all the indices of virtual columns and sort them in definition
@@ -162,6 +197,7 @@ func (e *PointGetExecutor) Init(p *plannercore.PointGetPlan) {
 		e.lockWaitTime = 0
 	}
 	e.rowDecoder = decoder
+	e.verifyChecksum = e.ctx.GetSessionVars().VerifyRowChecksumOnRead
 	e.partInfo = p.PartitionInfo
 	e.columns = p.Columns
 	e.buildVirtualColumnInfo()
@@ -333,6 +369,11 @@ func (e *PointGetExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
 		}
 		return nil
 	}
+	if e.verifyChecksum {
+		if err = verifyRowChecksum(e.ctx, e.tblInfo, e.handle, val); err != nil {
+			return err
+		}
+	}
 	err = DecodeRowValToChunk(e.base().ctx, e.schema, e.tblInfo, e.handle, val, req, e.rowDecoder)
 	if err != nil {
 		return err
@@ -587,6 +628,61 @@ func DecodeRowValToChunk(sctx sessionctx.Context, schema *expression.Schema, tbl
 	return decodeOldRowValToChunk(sctx, schema, tblInfo, handle, rowVal, chk)
 }
 
+// verifyRowChecksum verifies the row level checksum of the row value against the
+// decoded columns. The rows in the old format or without checksums are skipped.
+func verifyRowChecksum(sctx sessionctx.Context, tblInfo *model.TableInfo, handle kv.Handle, rowVal []byte) error {
+	failpoint.Inject("corruptRowValue", func(val failpoint.Value) {
+		// flip a byte of the row value, val is the position counted from the end.
+		corrupted := make([]byte, len(rowVal))
+		copy(corrupted, rowVal)
+		corrupted[len(corrupted)-val.(int)] ^= 0x1
+		rowVal = corrupted
+	})
+	if !rowcodec.IsNewFormat(rowVal) {
+		return nil
+	}
+	checksumDecoder := rowcodec.NewDatumMapDecoder(nil, nil)
+	if _, err := checksumDecoder.DecodeToDatumMap(rowVal, nil); err != nil {
+		return err
+	}
+	expected, ok := checksumDecoder.GetChecksum()
+	if !ok {
+		return nil
+	}
+	cols := make([]*table.Column, 0, len(tblInfo.Columns))
+	for _, col := range tblInfo.Columns {
+		// The checksums of the rows written during DDL depend on the non-public columns, and the checksum of
+		// a TIMESTAMP value depends on the time zone of the writer, such rows can't be verified by the reader.
+		if col.State != model.StatePublic || col.GetType() == mysql.TypeTimestamp {
+			return nil
+		}
+		if col.IsGenerated() && !col.GeneratedStored {
+			continue
+		}
+		cols = append(cols, table.ToColumn(col))
+	}
+	datums, _, err := tables.DecodeRawRowData(sctx, tblInfo, handle, cols, rowVal)
+	if err != nil {
+		return err
+	}
+	row := rowcodec.RowData{Cols: make([]rowcodec.ColData, 0, len(cols))}
+	for i, col := range cols {
+		row.Cols = append(row.Cols, rowcodec.ColData{ColumnInfo: col.ColumnInfo, Datum: &datums[i]})
+	}
+	sort.Sort(row)
+	checksum, err := row.Checksum()
+	if err != nil {
+		return err
+	}
+	if checksum == expected {
+		return nil
+	}
+	if extra, ok := checksumDecoder.GetExtraChecksum(); ok && checksum == extra {
+		return nil
+	}
+	return exeerrors.ErrRowChecksumMismatch.GenWithStackByArgs(tblInfo.Name.O, handle.String(), expected, checksum)
+}
+
 func decodeOldRowValToChunk(sctx sessionctx.Context, schema *expression.Schema, tblInfo *model.TableInfo, handle kv.Handle,
 	rowVal []byte, chk *chunk.Chunk) error {
 	pkCols := tables.TryGetCommonPkColumnIds(tblInfo)
@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/errno"
@@ -35,6 +36,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/dbterror/exeerrors"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/client-go/v2/tikv"
 )
@@ -842,3 +844,91 @@ func TestPointGetIssue40194(t *testing.T) {
 	tk2.MustExec("update t1 set v=v+1")
 	tk.MustQuery("execute s").Check(testkit.Rows("1 11"))
 }
+
+func TestPointGetVerifyRowChecksum(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b decimal(10, 2), c datetime, d enum('x', 'y'), e int, f int as (a + 1), g varchar(10) not null)")
+	tk.MustExec("create table ct (a varchar(10), b int, c varchar(10) not null, primary key(a, b) clustered)")
+	// the rows written before enabling the checksum have no checksums.
+	tk.MustExec("insert into t(a, b, c, d, e, g) values (1, 1.5, '2023-01-01 10:00:00', 'x', null, 'a')")
+
+	tk.MustExec("set global tidb_enable_row_level_checksum = on")
+	defer tk.MustExec("set global tidb_enable_row_level_checksum = default")
+	tk = testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("insert into t(a, b, c, d, e, g) values (2, 2.5, '2023-01-02 10:00:00', 'y', 2, 'bb'), (3, null, null, null, 3, 'ccc')")
+	tk.MustExec("insert into ct values ('a', 1, 'aa'), ('b', 2, 'bb')")
+
+	tk.MustExec("set @@tidb_verify_row_checksum_on_read = on")
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 1.50 2023-01-01 10:00:00 x <nil> 2 a"))
+	tk.MustQuery("select * from t where a = 2").Check(testkit.Rows("2 2.50 2023-01-02 10:00:00 y 2 3 bb"))
+	tk.MustQuery("select * from t where a in (2, 3)").Check(testkit.Rows(
+		"2 2.50 2023-01-02 10:00:00 y 2 3 bb", "3 <nil> <nil> <nil> 3 4 ccc"))
+	tk.MustQuery("select * from ct where a = 'a' and b = 1").Check(testkit.Rows("a 1 aa"))
+
+	// flip the last byte of the column data, which is followed by the 5 bytes of the checksum.
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/executor/corruptRowValue", "return(6)"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/executor/corruptRowValue"))
+	}()
+	for _, sql := range []string{
+		"select * from t where a = 2",
+		"select * from t where a in (1, 2)",
+		"select * from ct where a = 'b' and b = 2",
+	} {
+		err := tk.QueryToErr(sql)
+		require.True(t, terror.ErrorEqual(err, exeerrors.ErrRowChecksumMismatch), "sql: %s, err: %v", sql, err)
+	}
+	// the rows without checksums are not verified.
+	tk.MustQuery("select a from t where a = 1").Check(testkit.Rows("1"))
+
+	tk.MustExec("set @@tidb_verify_row_checksum_on_read = off")
+	tk.MustQuery("select a from t where a = 2").Check(testkit.Rows("2"))
+}
+
+func TestPointGetFollowerRead(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int, unique key(b))")
+	tk.MustExec("insert into t values (1, 10), (2, 20)")
+
+	fpName := "github.com/pingcap/tidb/executor/assertPointGetFollowerRead"
+	defer func() {
+		require.NoError(t, failpoint.Disable(fpName))
+	}()
+	// The point gets read from the leader by default.
+	require.NoError(t, failpoint.Enable(fpName, "return(false)"))
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 10"))
+
+	tk.MustExec("set @@tidb_point_get_follower_read = on")
+	require.NoError(t, failpoint.Enable(fpName, "return(true)"))
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 10"))
+	tk.MustQuery("select * from t where b = 20").Check(testkit.Rows("2 20"))
+	// The read committed transactions read with the new snapshots.
+	tk.MustExec("set @@tx_isolation = 'READ-COMMITTED'")
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select * from t where a = 2").Check(testkit.Rows("2 20"))
+	tk.MustExec("commit")
+	tk.MustExec("set @@tx_isolation = default")
+
+	// The locking reads and the reads of the DML statements are not eligible.
+	require.NoError(t, failpoint.Enable(fpName, "return(false)"))
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select * from t where a = 1 for update").Check(testkit.Rows("1 10"))
+	tk.MustExec("update t set b = 11 where a = 1")
+	tk.MustExec("commit")
+	tk.MustExec("delete from t where a = 2")
+	// The reads sharing the snapshot of the explicit transaction are not eligible.
+	for _, mode := range []string{"pessimistic", "optimistic"} {
+		tk.MustExec("begin " + mode)
+		tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 11"))
+		tk.MustExec("commit")
+	}
+	// The explicit replica read of the session is kept.
+	tk.MustExec("set @@tidb_replica_read = 'follower'")
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 11"))
+	tk.MustExec("set @@tidb_replica_read = 'leader'")
+}
@@ -79,6 +79,13 @@ type ProjectionExec struct {
 
 	calculateNoDelay bool
 	prepared         bool
+
+	// stopAfterRows is set when a limit is right above this projection, the
+	// limit needs at most stopAfterRows rows, so the rows beyond it are not
+	// evaluated, even in the middle of a chunk. 0 means no limit.
+	stopAfterRows uint64
+	// producedRows is the number of rows fetched from the child to evaluate.
+	producedRows uint64
 }
 
 // Open implements the Executor Open interface.
@@ -97,6 +104,7 @@ func (e *ProjectionExec) Open(ctx context.Context) error {
 func (e *ProjectionExec) open(_ context.Context) error {
 	e.prepared = false
 	e.parentReqRows = int64(e.maxChunkSize)
+	e.producedRows = 0
 
 	if e.memTracker != nil {
 		e.memTracker.Reset()
@@ -190,6 +198,9 @@ func (e *ProjectionExec) isUnparallelExec() bool {
 }
 
 func (e *ProjectionExec) unParallelExecute(ctx context.Context, chk *chunk.Chunk) error {
+	if e.limitReached() {
+		return nil
+	}
 	// transmit the requiredRows
 	e.childResult.SetRequiredRows(chk.RequiredRows(), e.maxChunkSize)
 	mSize := e.childResult.MemoryUsage()
@@ -202,10 +213,27 @@ func (e *ProjectionExec) unParallelExecute(ctx context.Context, chk *chunk.Chunk
 	if e.childResult.NumRows() == 0 {
 		return nil
 	}
+	e.truncateToLimit(e.childResult)
 	err = e.evaluatorSuit.Run(e.ctx, e.childResult, chk)
 	return err
 }
 
+// limitReached returns whether the limit above has got all the rows it needs.
+func (e *ProjectionExec) limitReached() bool {
+	return e.stopAfterRows > 0 && e.producedRows >= e.stopAfterRows
+}
+
+// truncateToLimit truncates the rows fetched from the child which are beyond
+// the limit above, so that they are not evaluated.
+func (e *ProjectionExec) truncateToLimit(input *chunk.Chunk) {
+	numRows := uint64(input.NumRows())
+	if e.stopAfterRows > 0 && e.producedRows+numRows > e.stopAfterRows {
+		numRows = e.stopAfterRows - e.producedRows
+		input.TruncateTo(int(numRows))
+	}
+	e.producedRows += numRows
+}
+
 func (e *ProjectionExec) parallelExecute(ctx context.Context, chk *chunk.Chunk) error {
 	atomic.StoreInt64(&e.parentReqRows, int64(chk.RequiredRows()))
 	if !e.prepared {
@@ -382,6 +410,12 @@ func (f *projectionInputFetcher) run(ctx context.Context) {
 
 		f.globalOutputCh <- output
 
+		if f.proj.limitReached() {
+			output.done <- nil
+			f.proj.memTracker.Consume(-input.chk.MemoryUsage())
+			return
+		}
+
 		requiredRows := atomic.LoadInt64(&f.proj.parentReqRows)
 		input.chk.SetRequiredRows(int(requiredRows), f.proj.maxChunkSize)
 		mSize := input.chk.MemoryUsage()
@@ -393,6 +427,7 @@ func (f *projectionInputFetcher) run(ctx context.Context) {
 			f.proj.memTracker.Consume(-input.chk.MemoryUsage())
 			return
 		}
+		f.proj.truncateToLimit(input.chk)
 
 		targetWorker.inputCh <- input
 		targetWorker.outputCh <- output
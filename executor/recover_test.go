@@ -495,7 +495,7 @@ func TestFlashbackSchema(t *testing.T) {
 	tk.MustExec("drop table if exists t_flashback")
 	tk.MustExec("create table t_flashback (a int)")
 
-	timeBeforeDrop, _, safePointSQL, resetGC := MockGC(tk)
+	timeBeforeDrop, timeAfterDrop, safePointSQL, resetGC := MockGC(tk)
 	defer resetGC()
 
 	// if GC safe point is not exists in mysql.tidb
@@ -556,6 +556,15 @@ func TestFlashbackSchema(t *testing.T) {
 	newTk.MustExec("flashback schema t_recover")
 
 	tk.MustExec("drop user 'testflashbackschema'@'localhost';")
+
+	// Test flashback database which is dropped before the GC safe point.
+	tk.MustExec("create database test_flashback_gc")
+	tk.MustExec("create table test_flashback_gc.t (a int)")
+	tk.MustExec("drop database test_flashback_gc")
+	tk.MustExec(fmt.Sprintf(safePointSQL, timeAfterDrop))
+	tk.MustContainErrMsg("flashback database test_flashback_gc", "Can't find dropped database 'test_flashback_gc' in GC safe point")
+	tk.MustContainErrMsg("flashback database test_flashback_gc to test_flashback_gc2", "Can't find dropped database 'test_flashback_gc' in GC safe point")
+	tk.MustQuery("show databases like 'test_flashback_gc%'").Check(testkit.Rows())
 }
 
 // MockGC is used to make GC work in the test environment.
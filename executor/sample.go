@@ -22,10 +22,12 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/mathutil"
 	decoder "github.com/pingcap/tidb/util/rowDecoder"
 	"github.com/pingcap/tidb/util/tracing"
 	"github.com/tikv/client-go/v2/tikv"
@@ -397,3 +399,148 @@ func (s *sampleSyncer) sync() error {
 	}
 	return nil
 }
+
+const (
+	// liveStatsSampleRegions is the max number of regions sampled for an index by `SHOW INDEX ... WITH LIVE STATS`.
+	liveStatsSampleRegions = 16
+	// liveStatsSampleKeysPerRegion is the max number of keys scanned from the beginning of a sampled region.
+	liveStatsSampleKeysPerRegion = 4096
+)
+
+// indexRegionSampler estimates the live cardinality of the columns of an index by scanning the beginning
+// of a few regions of it, which is used by `SHOW INDEX ... WITH LIVE STATS`.
+type indexRegionSampler struct {
+	ctx         sessionctx.Context
+	startTS     uint64
+	physicalIDs []int64
+	// idxInfo is nil if the sampled index is the integer primary key, which is the handle of the table.
+	idxInfo *model.IndexInfo
+	// isRecord means the keys of the index are the record keys, that is, the index is the clustered primary key.
+	isRecord bool
+	// realtimeCount is the row count maintained by the stats, which is used if the regions are not all scanned.
+	realtimeCount int64
+}
+
+func (s *indexRegionSampler) columnCount() int {
+	if s.idxInfo == nil {
+		return 1
+	}
+	return len(s.idxInfo.Columns)
+}
+
+// estimateCardinality returns the estimated cardinality of each column of the index.
+func (s *indexRegionSampler) estimateCardinality() ([]int64, error) {
+	ranges, err := s.splitIndexRanges()
+	if err != nil {
+		return nil, err
+	}
+	picked := pickRangesEvenly(ranges, liveStatsSampleRegions)
+	valueCounts := make([]map[string]uint64, s.columnCount())
+	for i := range valueCounts {
+		valueCounts[i] = make(map[string]uint64)
+	}
+	snap := s.ctx.GetStore().GetSnapshot(kv.Version{Ver: s.startTS})
+	setOptionForTopSQL(s.ctx.GetSessionVars().StmtCtx, snap)
+	var sampleSize uint64
+	allScanned := len(picked) == len(ranges)
+	for _, r := range picked {
+		finished, err := s.scanRange(snap, r, func(values [][]byte) {
+			sampleSize++
+			for i, val := range values {
+				valueCounts[i][string(val)]++
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		allScanned = allScanned && finished
+	}
+	rowCount := sampleSize
+	if !allScanned {
+		rowCount = mathutil.Max(uint64(s.realtimeCount), sampleSize*uint64(len(ranges))/uint64(len(picked)))
+	}
+	cardinality := make([]int64, 0, len(valueCounts))
+	for _, counts := range valueCounts {
+		var onlyOnceItems uint64
+		for _, cnt := range counts {
+			if cnt == 1 {
+				onlyOnceItems++
+			}
+		}
+		ndv := statistics.EstimateNDVBySample(sampleSize, uint64(len(counts)), onlyOnceItems, rowCount)
+		cardinality = append(cardinality, int64(ndv))
+	}
+	return cardinality, nil
+}
+
+func (s *indexRegionSampler) splitIndexRanges() ([]kv.KeyRange, error) {
+	var ranges []kv.KeyRange
+	for _, pid := range s.physicalIDs {
+		start := tablecodec.GenTableRecordPrefix(pid)
+		if !s.isRecord {
+			start = tablecodec.EncodeTableIndexPrefix(pid, s.idxInfo.ID)
+		}
+		rs, err := splitIntoMultiRanges(s.ctx.GetStore(), start, start.PrefixNext())
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, rs...)
+	}
+	sortRanges(ranges, false)
+	return ranges, nil
+}
+
+// pickRangesEvenly picks at most count ranges which are spread evenly.
+func pickRangesEvenly(ranges []kv.KeyRange, count int) []kv.KeyRange {
+	if len(ranges) <= count {
+		return ranges
+	}
+	picked := make([]kv.KeyRange, 0, count)
+	for i := 0; i < count; i++ {
+		picked = append(picked, ranges[i*len(ranges)/count])
+	}
+	return picked
+}
+
+// scanRange scans at most liveStatsSampleKeysPerRegion keys from the beginning of the range, and calls fn with the
+// encoded values of the index columns of each key. finished is true if all the keys of the range are scanned.
+func (s *indexRegionSampler) scanRange(snap kv.Snapshot, r kv.KeyRange, fn func(values [][]byte)) (finished bool, err error) {
+	it, err := snap.Iter(r.StartKey, r.EndKey)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	defer it.Close()
+	for scanned := 0; it.Valid(); scanned++ {
+		if scanned >= liveStatsSampleKeysPerRegion {
+			return false, nil
+		}
+		values, err := s.cutKey(it.Key())
+		if err != nil {
+			return false, err
+		}
+		fn(values)
+		if err = it.Next(); err != nil {
+			return false, errors.Trace(err)
+		}
+	}
+	return true, nil
+}
+
+func (s *indexRegionSampler) cutKey(key kv.Key) ([][]byte, error) {
+	if !s.isRecord {
+		values, _, err := tablecodec.CutIndexKeyNew(key, len(s.idxInfo.Columns))
+		return values, err
+	}
+	handle, err := tablecodec.DecodeRowKey(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !handle.IsInt() {
+		values := make([][]byte, 0, handle.NumCols())
+		for i := 0; i < handle.NumCols(); i++ {
+			values = append(values, handle.EncodedCol(i))
+		}
+		return values, nil
+	}
+	return [][]byte{handle.Encoded()}, nil
+}
@@ -17,17 +17,26 @@ package executor
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
 	"context"
+	"io"
 	"math"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/util/intest"
 )
 
 // SelectIntoExec represents a SelectInto executor.
@@ -45,6 +54,29 @@ type SelectIntoExec struct {
 	dstFile   *os.File
 	chk       *chunk.Chunk
 	started   bool
+
+	// header is the column names written as the first line of the OUTFILE,
+	// it is nil unless `WITH HEADER` is specified.
+	header []string
+
+	// extWriter is the writer of the OUTFILE on the external storage, it is
+	// nil if the OUTFILE is a local file.
+	extWriter *externalFileWriter
+
+	// compressTp is the codec to compress the OUTFILE, compressWriter is the
+	// writer compressing the data, it is nil if the OUTFILE isn't compressed.
+	compressTp     storage.CompressType
+	compressWriter io.WriteCloser
+}
+
+// externalFileWriter adapts a storage.ExternalFileWriter to io.Writer.
+type externalFileWriter struct {
+	ctx context.Context
+	w   storage.ExternalFileWriter
+}
+
+func (w *externalFileWriter) Write(p []byte) (int, error) {
+	return w.w.Write(w.ctx, p)
 }
 
 // Open implements the Executor Open interface.
@@ -54,19 +86,109 @@ func (s *SelectIntoExec) Open(ctx context.Context) error {
 		return errors.New("unsupported SelectInto type")
 	}
 
-	// MySQL-compatible behavior: allow files to be group-readable
-	f, err := os.OpenFile(s.intoOpt.FileName, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0640) // #nosec G302
-	if err != nil {
-		return errors.Trace(err)
+	if u, err := storage.ParseRawURL(s.intoOpt.FileName); err == nil && !storage.IsLocal(u) {
+		if err := s.openExternalFile(ctx, u); err != nil {
+			return err
+		}
+	} else {
+		// MySQL-compatible behavior: allow files to be group-readable
+		f, err := os.OpenFile(s.intoOpt.FileName, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0640) // #nosec G302
+		if err != nil {
+			return errors.Trace(err)
+		}
+		s.started = true
+		s.dstFile = f
+	}
+	if err := s.initWriter(); err != nil {
+		return err
 	}
-	s.started = true
-	s.dstFile = f
-	s.writer = bufio.NewWriter(s.dstFile)
 	s.chk = tryNewCacheChunk(s.children[0])
 	s.lineBuf = make([]byte, 0, 1024)
 	s.fieldBuf = make([]byte, 0, 64)
 	s.escapeBuf = make([]byte, 0, 64)
-	return s.baseExecutor.Open(ctx)
+	if err := s.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	return s.dumpHeader()
+}
+
+// openExternalFile opens the OUTFILE on the external storage such as S3 and GCS,
+// the credentials are passed by the query parameters of the URL like BRIE.
+func (s *SelectIntoExec) openExternalFile(ctx context.Context, u *url.URL) error {
+	fileName := strings.Trim(u.Path, "/")
+	if len(fileName) == 0 {
+		return errors.Errorf("the file name of OUTFILE '%s' should not be empty", u.Redacted())
+	}
+	u.Path = ""
+	b, err := storage.ParseBackendFromURL(u, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	opt := &storage.ExternalStorageOptions{}
+	if intest.InTest {
+		opt.NoCredentials = true
+	}
+	store, err := storage.New(ctx, b, opt)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	exists, err := store.FileExists(ctx, fileName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if exists {
+		return exeerrors.ErrFileExists.GenWithStackByArgs(s.intoOpt.FileName)
+	}
+	w, err := store.Create(ctx, fileName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.started = true
+	s.extWriter = &externalFileWriter{ctx: ctx, w: w}
+	return nil
+}
+
+// initWriter composes the buffered writer of the OUTFILE, the data is
+// compressed before being written to the file if compressTp is set.
+func (s *SelectIntoExec) initWriter() error {
+	var w io.Writer = s.dstFile
+	if s.extWriter != nil {
+		w = s.extWriter
+	}
+	switch s.compressTp {
+	case storage.Gzip:
+		s.compressWriter = gzip.NewWriter(w)
+	case storage.Zstd:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		s.compressWriter = zw
+	case storage.Snappy:
+		s.compressWriter = snappy.NewBufferedWriter(w)
+	}
+	if s.compressWriter != nil {
+		w = s.compressWriter
+	}
+	s.writer = bufio.NewWriter(w)
+	return nil
+}
+
+// outfileCompressType returns the codec to compress the OUTFILE. The file is
+// compressed only if COMPRESSION is specified, whatever its extension is.
+func outfileCompressType(intoOpt *ast.SelectIntoOption) (storage.CompressType, error) {
+	if len(intoOpt.Compression) == 0 {
+		return storage.NoCompression, nil
+	}
+	switch strings.ToLower(intoOpt.Compression) {
+	case "gzip":
+		return storage.Gzip, nil
+	case "zstd":
+		return storage.Zstd, nil
+	case "snappy":
+		return storage.Snappy, nil
+	}
+	return storage.NoCompression, errors.Errorf("unsupported compression '%s' of OUTFILE, only gzip, zstd and snappy are supported", intoOpt.Compression)
 }
 
 // Next implements the Executor Next interface.
@@ -120,6 +242,31 @@ func (s *SelectIntoExec) escapeField(f []byte) []byte {
 	return s.escapeBuf
 }
 
+// dumpHeader writes the column names as the first line of the OUTFILE, the
+// names are enclosed and escaped in the same way as the string fields.
+func (s *SelectIntoExec) dumpHeader() error {
+	if s.header == nil {
+		return nil
+	}
+	s.lineBuf = s.lineBuf[:0]
+	s.enclosed = len(s.FieldsEnclosedBy) > 0
+	for i, name := range s.header {
+		if i != 0 {
+			s.lineBuf = append(s.lineBuf, s.FieldsTerminatedBy...)
+		}
+		if s.enclosed {
+			s.lineBuf = append(s.lineBuf, s.FieldsEnclosedBy[0])
+		}
+		s.lineBuf = append(s.lineBuf, s.escapeField([]byte(name))...)
+		if s.enclosed {
+			s.lineBuf = append(s.lineBuf, s.FieldsEnclosedBy[0])
+		}
+	}
+	s.lineBuf = append(s.lineBuf, s.LinesTerminatedBy...)
+	_, err := s.writer.Write(s.lineBuf)
+	return errors.Trace(err)
+}
+
 func (s *SelectIntoExec) dumpToOutfile() error {
 	encloseFlag := false
 	var encloseByte byte
@@ -216,7 +363,17 @@ func (s *SelectIntoExec) Close() error {
 		return nil
 	}
 	err1 := s.writer.Flush()
-	err2 := s.dstFile.Close()
+	if s.compressWriter != nil {
+		if err := s.compressWriter.Close(); err1 == nil {
+			err1 = err
+		}
+	}
+	var err2 error
+	if s.extWriter != nil {
+		err2 = s.extWriter.w.Close(s.extWriter.ctx)
+	} else {
+		err2 = s.dstFile.Close()
+	}
 	err3 := s.baseExecutor.Close()
 	if err1 != nil {
 		return errors.Trace(err1)
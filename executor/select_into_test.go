@@ -15,13 +15,17 @@
 package executor_test
 
 import (
+	"compress/gzip"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/testkit"
@@ -290,3 +294,82 @@ func TestYearType(t *testing.T) {
 	tk.MustExec(fmt.Sprintf("select * from t into outfile '%v' fields terminated by ',' optionally enclosed by '\"' lines terminated by '\\n';", outfile))
 	cmpAndRm("2010\n2011\n2012\n2030\n", outfile, t)
 }
+
+func TestSelectIntoOutfileWithHeader(t *testing.T) {
+	outfile := randomSelectFilePath("TestSelectIntoOutfileWithHeader")
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, name varchar(20))")
+	tk.MustExec(`insert into t values (1, 'a,b'), (2, NULL)`)
+
+	tk.MustExec(fmt.Sprintf("select * from t order by id into outfile %q with header", outfile))
+	cmpAndRm("id\tname\n1\ta,b\n2\t\\N\n", outfile, t)
+
+	tk.MustExec(fmt.Sprintf("select id, name as `x\"y` from t order by id into outfile %q fields terminated by ',' optionally enclosed by '\"' lines terminated by '\\n' with header", outfile))
+	// the column names are enclosed and escaped like the string fields
+	cmpAndRm("\"id\",\"x\\\"y\"\n1,\"a,b\"\n2,\\N\n", outfile, t)
+
+	tk.MustExec(fmt.Sprintf("select id, name from t order by id into outfile %q fields terminated by ',' lines terminated by '\\n' with header", outfile))
+	// read the header back and make sure it matches the output columns
+	content, err := os.ReadFile(outfile)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	require.Len(t, lines, 3)
+	require.Equal(t, []string{"id", "name"}, strings.Split(lines[0], ","))
+	require.Equal(t, "1,a\\,b", lines[1])
+	require.NoError(t, os.Remove(outfile))
+
+	tk.MustExec(fmt.Sprintf("select * from t where id > 10 into outfile %q with header", outfile))
+	// the header is written even if no row is selected
+	cmpAndRm("id\tname\n", outfile, t)
+}
+
+func TestSelectIntoOutfileCompression(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int, name varchar(20))")
+	tk.MustExec(`insert into t values (1, 'a,b'), (2, NULL)`)
+
+	// readCompressedAndRm decompresses the outfile and removes it.
+	readCompressedAndRm := func(outfile string, newReader func(io.Reader) (io.Reader, error)) string {
+		f, err := os.Open(outfile)
+		require.NoError(t, err)
+		r, err := newReader(f)
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		require.NoError(t, os.Remove(outfile))
+		return string(content)
+	}
+	gzipReader := func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }
+	zstdReader := func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) }
+	snappyReader := func(r io.Reader) (io.Reader, error) { return snappy.NewReader(r), nil }
+
+	outfile := randomSelectFilePath("TestSelectIntoOutfileCompression")
+	tk.MustExec(fmt.Sprintf("select * from t order by id into outfile %q compression 'gzip'", outfile))
+	require.Equal(t, "1\ta,b\n2\t\\N\n", readCompressedAndRm(outfile, gzipReader))
+
+	// the header and the format options compose with the compression
+	tk.MustExec(fmt.Sprintf("select * from t order by id into outfile %q fields terminated by ',' optionally enclosed by '\"' with header compression 'ZSTD'", outfile))
+	require.Equal(t, "\"id\",\"name\"\n1,\"a,b\"\n2,\\N\n", readCompressedAndRm(outfile, zstdReader))
+
+	tk.MustExec(fmt.Sprintf("select * from t order by id into outfile %q with header compression 'snappy'", outfile))
+	require.Equal(t, "id\tname\n1\ta,b\n2\t\\N\n", readCompressedAndRm(outfile, snappyReader))
+
+	// the file without the compression option is unchanged, whatever its extension is
+	tk.MustExec(fmt.Sprintf("select * from t order by id into outfile %q", outfile))
+	cmpAndRm("1\ta,b\n2\t\\N\n", outfile, t)
+	tk.MustExec(fmt.Sprintf("select * from t order by id into outfile %q", outfile+".gz"))
+	cmpAndRm("1\ta,b\n2\t\\N\n", outfile+".gz", t)
+	tk.MustExec(fmt.Sprintf("select * from t order by id into outfile %q compression 'gzip'", outfile+".zst"))
+	require.Equal(t, "1\ta,b\n2\t\\N\n", readCompressedAndRm(outfile+".zst", gzipReader))
+
+	tk.MustGetErrMsg(fmt.Sprintf("select * from t into outfile %q compression 'lz4'", outfile),
+		"unsupported compression 'lz4' of OUTFILE, only gzip, zstd and snappy are supported")
+	_, err := os.Stat(outfile)
+	require.True(t, os.IsNotExist(err))
+}
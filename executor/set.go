@@ -48,64 +48,96 @@ type SetExecutor struct {
 }
 
 // Next implements the Executor Next interface.
+// The assignments are applied all-or-nothing: they are checked before any of them is applied,
+// and the applied ones are rolled back if a later one fails.
 func (e *SetExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.Reset()
 	if e.done {
 		return nil
 	}
 	e.done = true
-	sessionVars := e.ctx.GetSessionVars()
 	for _, v := range e.vars {
-		// Variable is case insensitive, we use lower case.
-		if v.Name == ast.SetNames || v.Name == ast.SetCharset {
-			// This is set charset stmt.
-			if v.IsDefault {
-				err := e.setCharset(mysql.DefaultCharset, "", v.Name == ast.SetNames)
-				if err != nil {
-					return err
-				}
-				continue
-			}
-			dt, err := v.Expr.(*expression.Constant).Eval(chunk.Row{})
-			if err != nil {
-				return err
-			}
-			cs := dt.GetString()
-			var co string
-			if v.ExtendValue != nil {
-				co = v.ExtendValue.Value.GetString()
-			}
-			err = e.setCharset(cs, co, v.Name == ast.SetNames)
-			if err != nil {
-				return err
-			}
-			continue
-		}
-		name := strings.ToLower(v.Name)
-		if !v.IsSystem {
-			// Set user variable.
-			value, err := v.Expr.Eval(chunk.Row{})
-			if err != nil {
-				return err
-			}
-			if value.IsNull() {
-				sessionVars.UnsetUserVar(name)
-			} else {
-				sessionVars.SetUserVarVal(name, value)
-				sessionVars.SetUserVarType(name, v.Expr.GetType())
+		if err := e.validateVarAssignment(ctx, v); err != nil {
+			return err
+		}
+	}
+	undoLog := make([]func() error, 0, len(e.vars))
+	// oldGlobals are the values of the global variables before being assigned, they're restored together.
+	oldGlobals := make(map[string]string)
+	for _, v := range e.vars {
+		undo, globals, err := e.saveVarAssignment(ctx, v)
+		if err != nil {
+			e.rollbackVarAssignments(ctx, undoLog, oldGlobals)
+			return err
+		}
+		isCharset := v.Name == ast.SetNames || v.Name == ast.SetCharset
+		if isCharset {
+			// Set charset changes several variables one by one, any of them may have been changed on error.
+			undoLog = append(undoLog, undo)
+		}
+		if err = e.applyVarAssignment(ctx, v); err != nil {
+			e.rollbackVarAssignments(ctx, undoLog, oldGlobals)
+			return err
+		}
+		if !isCharset && undo != nil {
+			undoLog = append(undoLog, undo)
+		}
+		for name, value := range globals {
+			// Keep the value before the first assignment if the variable is assigned several times.
+			if _, ok := oldGlobals[name]; !ok {
+				oldGlobals[name] = value
 			}
-			continue
 		}
+	}
+	return nil
+}
 
-		if err := e.setSysVariable(ctx, name, v); err != nil {
+func (e *SetExecutor) applyVarAssignment(ctx context.Context, v *expression.VarAssignment) error {
+	sessionVars := e.ctx.GetSessionVars()
+	// Variable is case insensitive, we use lower case.
+	if v.Name == ast.SetNames || v.Name == ast.SetCharset {
+		// This is set charset stmt.
+		if v.IsDefault {
+			return e.setCharset(mysql.DefaultCharset, "", v.Name == ast.SetNames)
+		}
+		dt, err := v.Expr.(*expression.Constant).Eval(chunk.Row{})
+		if err != nil {
 			return err
 		}
+		cs := dt.GetString()
+		var co string
+		if v.ExtendValue != nil {
+			co = v.ExtendValue.Value.GetString()
+		}
+		return e.setCharset(cs, co, v.Name == ast.SetNames)
 	}
-	return nil
+	name := strings.ToLower(v.Name)
+	if !v.IsSystem {
+		// Set user variable.
+		value, err := v.Expr.Eval(chunk.Row{})
+		if err != nil {
+			return err
+		}
+		if value.IsNull() {
+			sessionVars.UnsetUserVar(name)
+		} else {
+			sessionVars.SetUserVarVal(name, value)
+			sessionVars.SetUserVarType(name, v.Expr.GetType())
+		}
+		return nil
+	}
+	return e.setSysVariable(ctx, name, v)
 }
 
-func (e *SetExecutor) setSysVariable(ctx context.Context, name string, v *expression.VarAssignment) error {
+// validateVarAssignment checks the system variable assignment before any assignment is applied.
+// The value is left to be validated when being applied, because the validation of some variables
+// depends on the others, which may be changed by the assignments before it.
+func (e *SetExecutor) validateVarAssignment(ctx context.Context, v *expression.VarAssignment) error {
+	if v.Name == ast.SetNames || v.Name == ast.SetCharset || !v.IsSystem {
+		return nil
+	}
 	sessionVars := e.ctx.GetSessionVars()
+	name := strings.ToLower(v.Name)
 	sysVar := variable.GetSysVar(name)
 	if sysVar == nil {
 		if variable.IsRemovedSysVar(name) {
@@ -129,12 +161,6 @@ func (e *SetExecutor) setSysVariable(ctx context.Context, name string, v *expres
 		}
 	}
 
-	if sysVar.IsNoop && !variable.EnableNoopVariables.Load() {
-		// The variable is a noop. For compatibility we allow it to still
-		// be changed, but we append a warning since users might be expecting
-		// something that's not going to happen.
-		sessionVars.StmtCtx.AppendWarning(exeerrors.ErrSettingNoopVariable.GenWithStackByArgs(sysVar.Name))
-	}
 	if sysVar.HasInstanceScope() && !v.IsGlobal && sessionVars.EnableLegacyInstanceScope {
 		// For backward compatibility we will change the v.IsGlobal to true,
 		// and append a warning saying this will not be supported in future.
@@ -142,6 +168,120 @@ func (e *SetExecutor) setSysVariable(ctx context.Context, name string, v *expres
 		sessionVars.StmtCtx.AppendWarning(exeerrors.ErrInstanceScope.GenWithStackByArgs(sysVar.Name))
 	}
 
+	if !v.IsGlobal && sessionVars.InTxn() {
+		if name == variable.TxnIsolationOneShot ||
+			name == variable.TiDBTxnReadTS {
+			return errors.Trace(exeerrors.ErrCantChangeTxCharacteristics)
+		}
+		if name == variable.TiDBSnapshot && sessionVars.TxnCtx.IsStaleness {
+			return errors.Trace(exeerrors.ErrCantChangeTxCharacteristics)
+		}
+	}
+
+	return nil
+}
+
+// saveVarAssignment saves the current value of the variable, and returns a function to restore it. For the global
+// variable, the values of it and its aliases are returned instead, so they can be restored together.
+func (e *SetExecutor) saveVarAssignment(ctx context.Context, v *expression.VarAssignment) (undo func() error, globals map[string]string, err error) {
+	sessionVars := e.ctx.GetSessionVars()
+	if v.Name == ast.SetNames || v.Name == ast.SetCharset {
+		names := append([]string{variable.CharacterSetConnection, variable.CollationConnection}, variable.SetCharsetVariables...)
+		oldValues := make([]string, len(names))
+		for i, name := range names {
+			if oldValues[i], err = sessionVars.GetSessionOrGlobalSystemVar(ctx, name); err != nil {
+				return nil, nil, err
+			}
+		}
+		return func() error {
+			for i, name := range names {
+				if err := sessionVars.SetSystemVarWithoutValidation(name, oldValues[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, nil, nil
+	}
+	name := strings.ToLower(v.Name)
+	if !v.IsSystem {
+		oldValue, hasValue := sessionVars.GetUserVarVal(name)
+		oldType, hasType := sessionVars.GetUserVarType(name)
+		return func() error {
+			sessionVars.UnsetUserVar(name)
+			if hasValue {
+				sessionVars.SetUserVarVal(name, oldValue)
+			}
+			if hasType {
+				sessionVars.SetUserVarType(name, oldType)
+			}
+			return nil
+		}, nil, nil
+	}
+	sysVar := variable.GetSysVar(name)
+	if sysVar == nil {
+		// The removed variable is ignored, nothing to restore.
+		return nil, nil, nil
+	}
+	if v.IsGlobal {
+		globals = make(map[string]string, 1+len(sysVar.Aliases))
+		for _, globalName := range append([]string{sysVar.Name}, sysVar.Aliases...) {
+			if globals[globalName], err = sessionVars.GlobalVarsAccessor.GetGlobalSysVar(globalName); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, globals, nil
+	}
+	oldValue, err := sessionVars.GetSessionOrGlobalSystemVar(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return func() error {
+		if err := sessionVars.SetSystemVarWithoutValidation(name, oldValue); err != nil {
+			return err
+		}
+		switch name {
+		case variable.TiDBSnapshot:
+			return e.loadSnapshotInfoSchemaIfNeeded(name, sessionVars.SnapshotTS)
+		case variable.TiDBTxnReadTS:
+			return e.loadSnapshotInfoSchemaIfNeeded(name, sessionVars.TxnReadTS.PeakTxnReadTS())
+		}
+		return nil
+	}, nil, nil
+}
+
+// rollbackVarAssignments restores the applied assignments in the reverse order. The global variables are restored
+// in one transaction, so either all or none of them are restored.
+func (e *SetExecutor) rollbackVarAssignments(ctx context.Context, undoLog []func() error, oldGlobals map[string]string) {
+	sessionVars := e.ctx.GetSessionVars()
+	for i := len(undoLog) - 1; i >= 0; i-- {
+		if err := undoLog[i](); err != nil {
+			logutil.BgLogger().Warn("failed to rollback set variable", zap.Uint64("conn", sessionVars.ConnectionID), zap.Error(err))
+		}
+	}
+	if len(oldGlobals) == 0 {
+		return
+	}
+	if err := sessionVars.GlobalVarsAccessor.SetGlobalSysVarsOnly(ctx, oldGlobals); err != nil {
+		logutil.BgLogger().Warn("failed to rollback set global variables", zap.Uint64("conn", sessionVars.ConnectionID), zap.Error(err))
+	}
+}
+
+func (e *SetExecutor) setSysVariable(ctx context.Context, name string, v *expression.VarAssignment) error {
+	sessionVars := e.ctx.GetSessionVars()
+	sysVar := variable.GetSysVar(name)
+	if sysVar == nil {
+		if variable.IsRemovedSysVar(name) {
+			return nil // removed vars permit parse-but-ignore
+		}
+		return variable.ErrUnknownSystemVar.GenWithStackByArgs(name)
+	}
+
+	if sysVar.IsNoop && !variable.EnableNoopVariables.Load() {
+		// The variable is a noop. For compatibility we allow it to still
+		// be changed, but we append a warning since users might be expecting
+		// something that's not going to happen.
+		sessionVars.StmtCtx.AppendWarning(exeerrors.ErrSettingNoopVariable.GenWithStackByArgs(sysVar.Name))
+	}
 	if v.IsGlobal {
 		valStr, err := e.getVarValue(ctx, v, sysVar)
 		if err != nil {
@@ -182,15 +322,6 @@ func (e *SetExecutor) setSysVariable(ctx context.Context, name string, v *expres
 			sessionVars.TxnReadTS.SetTxnReadTS(oldSnapshotTS)
 		}
 	}
-	if sessionVars.InTxn() {
-		if name == variable.TxnIsolationOneShot ||
-			name == variable.TiDBTxnReadTS {
-			return errors.Trace(exeerrors.ErrCantChangeTxCharacteristics)
-		}
-		if name == variable.TiDBSnapshot && sessionVars.TxnCtx.IsStaleness {
-			return errors.Trace(exeerrors.ErrCantChangeTxCharacteristics)
-		}
-	}
 	err = sessionVars.SetSystemVar(name, valStr)
 	if err != nil {
 		return err
@@ -1448,6 +1448,36 @@ func TestValidateSetVar(t *testing.T) {
 	tk.MustQuery("select @@global.allow_auto_random_explicit_insert;").Check(testkit.Rows("1"))
 }
 
+func TestSetVarAllOrNothing(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set @a = 1, @@tidb_max_chunk_size = 1024, @@sql_select_limit = 100")
+	tk.MustExec("set global tidb_mem_oom_action = 'LOG'")
+	defer tk.MustExec("set global tidb_mem_oom_action = default")
+
+	checkUnchanged := func() {
+		tk.MustQuery("select @a, @b, @@tidb_max_chunk_size, @@sql_select_limit, @@character_set_client, @@global.tidb_mem_oom_action").
+			Check(testkit.Rows("1 <nil> 1024 100 utf8mb4 LOG"))
+	}
+	// the invalid value is reported when it's applied, the assignments before it are rolled back.
+	tk.MustGetErrCode("set @a = 2, @b = 3, @@tidb_max_chunk_size = 64, @@sql_select_limit = 'abc'", errno.ErrWrongTypeForVar)
+	checkUnchanged()
+	tk.MustGetErrMsg("set names gbk, @@tidb_max_chunk_size = 64, @@global.tidb_mem_oom_action = 'CANCEL', @@sql_mode = 'NOT_A_MODE'", "ERROR 1231 (42000): Variable 'sql_mode' can't be set to the value of 'NOT_A_MODE'")
+	checkUnchanged()
+	// the unknown variable is reported before any assignment is applied.
+	tk.MustGetErrCode("set @a = 2, @@global.tidb_mem_oom_action = 'CANCEL', @@sql_select_limit = 200, @@not_exist_var = 1", errno.ErrUnknownSystemVariable)
+	checkUnchanged()
+	// the global variables and their aliases are restored in the storage together.
+	tk.MustGetErrCode("set @@global.tidb_prepared_plan_cache_size = 50, @@global.tidb_mem_oom_action = 'CANCEL', @@sql_select_limit = 'abc'", errno.ErrWrongTypeForVar)
+	checkUnchanged()
+	tk.MustQuery("select variable_name, variable_value from mysql.global_variables where variable_name in " +
+		"('tidb_prepared_plan_cache_size', 'tidb_session_plan_cache_size', 'tidb_mem_oom_action') order by variable_name").
+		Check(testkit.Rows("tidb_mem_oom_action LOG", "tidb_prepared_plan_cache_size 100", "tidb_session_plan_cache_size 100"))
+	// the assignments can still depend on the ones before them.
+	tk.MustExec("set @a = 2, @b = @a + 1, @@sql_select_limit = @b")
+	tk.MustQuery("select @a, @b, @@sql_select_limit").Check(testkit.Rows("2 3 3"))
+}
+
 func TestSelectGlobalVar(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
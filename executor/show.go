@@ -16,6 +16,7 @@ package executor
 
 import (
 	"bytes"
+	"container/heap"
 	"context"
 	gjson "encoding/json"
 	"fmt"
@@ -58,6 +59,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/store/helper"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
@@ -104,13 +106,27 @@ type ShowExec struct {
 
 	result *chunk.Chunk
 	cursor int
+	// showTablesDB is the schema of SHOW TABLES, its tables are scanned chunk by chunk in Next
+	// rather than materialized into result up front. showTablesLast is the name of the last
+	// table returned, and showTablesDone is set after the last batch.
+	showTablesDB   *model.DBInfo
+	showTablesLast string
+	showTablesDone bool
 
 	Full        bool
 	IfNotExists bool // Used for `show create database if not exists`
 	GlobalScope bool // GlobalScope is used by show variables
 	Extended    bool // Used for `show extended columns from ...`
-
-	ImportJobID *int64
+	LiveStats   bool // Used for `show index from ... with live stats`
+
+	ImportJobID      *int64
+	HealthyThreshold *int64 // Used for `show stats_healthy where healthy < N`
+	// StatsDBFilter and StatsTableFilter are used for `show stats_locked where db_name = 'x' and table_name = 'y'`.
+	StatsDBFilter    string
+	StatsTableFilter string
+	// StatsBucketLower and StatsBucketUpper are the inclusive bounds used for `show stats_buckets where bucket_id between a and b`.
+	StatsBucketLower *int64
+	StatsBucketUpper *int64
 }
 
 type showTableRegionRowItem struct {
@@ -141,6 +157,10 @@ func (e *ShowExec) Next(ctx context.Context, req *chunk.Chunk) error {
 			}
 		}
 	}
+	if e.showTablesDB != nil {
+		e.nextShowTables(req)
+		return nil
+	}
 	if e.cursor >= e.result.NumRows() {
 		return nil
 	}
@@ -250,6 +270,9 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 		return e.fetchShowPlugins()
 	case ast.ShowProfiles:
 		// empty result
+	case ast.ShowProfile:
+		e.fetchShowProfile()
+		return nil
 	case ast.ShowMasterStatus:
 		return e.fetchShowMasterStatus()
 	case ast.ShowPrivileges:
@@ -526,50 +549,120 @@ func (e *ShowExec) fetchShowTables() error {
 	if !e.is.SchemaExists(e.DBName) {
 		return exeerrors.ErrBadDB.GenWithStackByArgs(e.DBName)
 	}
-	// sort for tables
-	schemaTables := e.is.SchemaTables(e.DBName)
-	tableNames := make([]string, 0, len(schemaTables))
-	activeRoles := e.ctx.GetSessionVars().ActiveRoles
-	var (
-		tableTypes        = make(map[string]string)
-		fieldPatternsLike collate.WildcardPattern
-		fieldFilter       string
-	)
+	dbInfo, _ := e.is.SchemaByName(e.DBName)
+	// The rows are generated lazily, so widen the result columns here for the longest value.
+	nameLen, typeLen := 0, 0
+	for _, tbl := range dbInfo.Tables {
+		if !e.showTableVisible(tbl) {
+			continue
+		}
+		nameLen = mathutil.Max(nameLen, len(tbl.Name.O))
+		typeLen = mathutil.Max(typeLen, len(e.tableType(tbl)))
+	}
+	e.showTablesDB = dbInfo
+	e.widenColumn(0, nameLen)
+	if e.Full {
+		e.widenColumn(1, typeLen)
+	}
+	return nil
+}
 
+// showTableVisible reports whether the table is returned by SHOW TABLES.
+func (e *ShowExec) showTableVisible(tbl *model.TableInfo) bool {
+	// Test with mysql.AllPrivMask means any privilege would be OK.
+	// TODO: Should consider column privileges, which also make a table visible.
+	checker := privilege.GetPrivilegeManager(e.ctx)
+	if checker != nil && !checker.RequestVerification(e.ctx.GetSessionVars().ActiveRoles, e.DBName.O, tbl.Name.O, "", mysql.AllPrivMask) {
+		return false
+	}
 	if e.Extractor != nil {
-		fieldFilter = e.Extractor.Field()
-		fieldPatternsLike = e.Extractor.FieldPatternLike()
+		if fieldFilter := e.Extractor.Field(); fieldFilter != "" && tbl.Name.L != fieldFilter {
+			return false
+		}
+		if fieldPatternsLike := e.Extractor.FieldPatternLike(); fieldPatternsLike != nil && !fieldPatternsLike.DoMatch(tbl.Name.L) {
+			return false
+		}
+	}
+	return true
+}
+
+// showTablesHeap is a max-heap of tables ordered by name, it keeps the smallest names of a batch.
+type showTablesHeap []*model.TableInfo
+
+func (h showTablesHeap) Len() int           { return len(h) }
+func (h showTablesHeap) Less(i, j int) bool { return h[i].Name.O > h[j].Name.O }
+func (h showTablesHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *showTablesHeap) Push(x interface{}) {
+	*h = append(*h, x.(*model.TableInfo))
+}
+
+func (h *showTablesHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// nextShowTables appends the rows of the next batch of tables to req. The tables of the schema
+// are scanned again for every batch, only the batch itself is kept in memory.
+func (e *ShowExec) nextShowTables(req *chunk.Chunk) {
+	if e.showTablesDone {
+		return
 	}
-	for _, v := range schemaTables {
-		// Test with mysql.AllPrivMask means any privilege would be OK.
-		// TODO: Should consider column privileges, which also make a table visible.
-		if checker != nil && !checker.RequestVerification(activeRoles, e.DBName.O, v.Meta().Name.O, "", mysql.AllPrivMask) {
+	batchSize := req.Capacity()
+	batch := make(showTablesHeap, 0, batchSize)
+	for _, tbl := range e.showTablesDB.Tables {
+		if e.cursor > 0 && tbl.Name.O <= e.showTablesLast {
 			continue
-		} else if fieldFilter != "" && v.Meta().Name.L != fieldFilter {
+		}
+		if len(batch) == batchSize && tbl.Name.O >= batch[0].Name.O {
 			continue
-		} else if fieldPatternsLike != nil && !fieldPatternsLike.DoMatch(v.Meta().Name.L) {
+		}
+		if !e.showTableVisible(tbl) {
 			continue
 		}
-		tableNames = append(tableNames, v.Meta().Name.O)
-		if v.Meta().IsView() {
-			tableTypes[v.Meta().Name.O] = "VIEW"
-		} else if v.Meta().IsSequence() {
-			tableTypes[v.Meta().Name.O] = "SEQUENCE"
-		} else if util.IsSystemView(e.DBName.L) {
-			tableTypes[v.Meta().Name.O] = "SYSTEM VIEW"
-		} else {
-			tableTypes[v.Meta().Name.O] = "BASE TABLE"
+		heap.Push(&batch, tbl)
+		if len(batch) > batchSize {
+			heap.Pop(&batch)
 		}
 	}
-	slices.Sort(tableNames)
-	for _, v := range tableNames {
+	slices.SortFunc(batch, func(i, j *model.TableInfo) bool {
+		return i.Name.O < j.Name.O
+	})
+	for _, tbl := range batch {
+		req.AppendString(0, tbl.Name.O)
 		if e.Full {
-			e.appendRow([]interface{}{v, tableTypes[v]})
-		} else {
-			e.appendRow([]interface{}{v})
+			req.AppendString(1, e.tableType(tbl))
 		}
 	}
-	return nil
+	e.cursor += len(batch)
+	if len(batch) > 0 {
+		e.showTablesLast = batch[len(batch)-1].Name.O
+	}
+	e.showTablesDone = len(batch) < batchSize
+}
+
+func (e *ShowExec) tableType(tbl *model.TableInfo) string {
+	switch {
+	case tbl.IsView():
+		return "VIEW"
+	case tbl.IsSequence():
+		return "SEQUENCE"
+	case util.IsSystemView(e.DBName.L):
+		return "SYSTEM VIEW"
+	default:
+		return "BASE TABLE"
+	}
+}
+
+// widenColumn enlarges the flen of the varchar column to hold the value of the given length.
+func (e *ShowExec) widenColumn(colIdx int, valLen int) {
+	retType := e.Schema().Columns[colIdx].RetType
+	if types.IsTypeVarchar(retType.GetType()) && retType.GetFlen() < valLen {
+		retType.SetFlen(valLen)
+	}
 }
 
 func (e *ShowExec) fetchShowTableStatus(ctx context.Context) error {
@@ -749,6 +842,13 @@ func (e *ShowExec) fetchShowIndex() error {
 		if ok {
 			ndv = colStats.NDV
 		}
+		if e.LiveStats {
+			cardinality, err := e.liveIndexCardinality(tb.Meta(), nil, statsTbl)
+			if err != nil {
+				return err
+			}
+			ndv = cardinality[0]
+		}
 		e.appendRow([]interface{}{
 			tb.Meta().Name.O, // Table
 			0,                // Non_unique
@@ -777,6 +877,13 @@ func (e *ShowExec) fetchShowIndex() error {
 		if tb.Meta().IsCommonHandle && idxInfo.Primary {
 			isClustered = "YES"
 		}
+		var liveCardinality []int64
+		if e.LiveStats {
+			liveCardinality, err = e.liveIndexCardinality(tb.Meta(), idxInfo, statsTbl)
+			if err != nil {
+				return err
+			}
+		}
 		for i, col := range idxInfo.Columns {
 			nonUniq := 1
 			if idx.Meta().Unique {
@@ -811,6 +918,9 @@ func (e *ShowExec) fetchShowIndex() error {
 			if ok {
 				ndv = colStats.NDV
 			}
+			if e.LiveStats {
+				ndv = liveCardinality[i]
+			}
 
 			e.appendRow([]interface{}{
 				tb.Meta().Name.O,       // Table
@@ -835,6 +945,33 @@ func (e *ShowExec) fetchShowIndex() error {
 	return nil
 }
 
+// liveIndexCardinality estimates the cardinality of the columns of an index by sampling a few regions of it.
+// idxInfo is nil for the integer primary key.
+func (e *ShowExec) liveIndexCardinality(tblInfo *model.TableInfo, idxInfo *model.IndexInfo, statsTbl *statistics.Table) ([]int64, error) {
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return nil, err
+	}
+	physicalIDs := []int64{tblInfo.ID}
+	if pi := tblInfo.GetPartitionInfo(); pi != nil && (idxInfo == nil || !idxInfo.Global) {
+		physicalIDs = physicalIDs[:0]
+		for _, def := range pi.Definitions {
+			physicalIDs = append(physicalIDs, def.ID)
+		}
+	}
+	sampler := &indexRegionSampler{
+		ctx:         e.ctx,
+		startTS:     txn.StartTS(),
+		physicalIDs: physicalIDs,
+		idxInfo:     idxInfo,
+		isRecord:    idxInfo == nil || (tblInfo.IsCommonHandle && idxInfo.Primary),
+	}
+	if !statsTbl.Pseudo {
+		sampler.realtimeCount = statsTbl.RealtimeCount
+	}
+	return sampler.estimateCardinality()
+}
+
 // fetchShowCharset gets all charset information and fill them into e.rows.
 // See http://dev.mysql.com/doc/refman/5.7/en/show-character-set.html
 func (e *ShowExec) fetchShowCharset() error {
@@ -1531,8 +1668,8 @@ func ConstructResultOfShowCreatePlacementPolicy(policyInfo *model.PolicyInfo) st
 }
 
 // constructResultOfShowCreateResourceGroup constructs the result for show create resource group.
-func constructResultOfShowCreateResourceGroup(resourceGroup *model.ResourceGroupInfo) string {
-	return fmt.Sprintf("CREATE RESOURCE GROUP `%s` %s", resourceGroup.Name.O, resourceGroup.ResourceGroupSettings.String())
+func constructResultOfShowCreateResourceGroup(resourceGroup *model.ResourceGroupInfo, sqlMode mysql.SQLMode) string {
+	return fmt.Sprintf("CREATE RESOURCE GROUP %s %s", stringutil.Escape(resourceGroup.Name.O, sqlMode), resourceGroup.ResourceGroupSettings.String())
 }
 
 // fetchShowCreateDatabase composes show create database result.
@@ -1574,8 +1711,8 @@ func (e *ShowExec) fetchShowCreateResourceGroup() error {
 	if !found {
 		return infoschema.ErrResourceGroupNotExists.GenWithStackByArgs(e.ResourceGroupName.O)
 	}
-	showCreate := constructResultOfShowCreateResourceGroup(group)
-	e.appendRow([]interface{}{e.ResourceGroupName.O, showCreate})
+	showCreate := constructResultOfShowCreateResourceGroup(group, e.ctx.GetSessionVars().SQLMode)
+	e.appendRow([]interface{}{group.Name.O, showCreate})
 	return nil
 }
 
@@ -1837,6 +1974,48 @@ func (e *ShowExec) fetchShowProcedureStatus() error {
 	return nil
 }
 
+// fetchShowProfile shows the cumulative time and rows of every operator of the previous statement.
+func (e *ShowExec) fetchShowProfile() {
+	sc := e.ctx.GetSessionVars().StmtCtx
+	p, ok := sc.PrevPlan.(plannercore.Plan)
+	if !ok || sc.PrevRuntimeStatsColl == nil {
+		return
+	}
+	flat := plannercore.FlattenPhysicalPlan(p, true)
+	if flat == nil {
+		return
+	}
+	ops := flat.Main
+	for _, cte := range flat.CTEs {
+		ops = append(ops, cte...)
+	}
+	for _, op := range ops {
+		id := op.Origin.ID()
+		var (
+			duration time.Duration
+			rows     int64
+		)
+		if op.IsRoot {
+			if !sc.PrevRuntimeStatsColl.ExistsRootStats(id) {
+				continue
+			}
+			basic, _ := sc.PrevRuntimeStatsColl.GetRootStats(id).MergeStats()
+			if basic == nil {
+				continue
+			}
+			duration, rows = time.Duration(basic.GetTime()), basic.GetActRows()
+		} else {
+			if !sc.PrevRuntimeStatsColl.ExistsCopStats(id) {
+				continue
+			}
+			copStats := sc.PrevRuntimeStatsColl.GetCopStats(id)
+			_, duration, _, _, _, _ = copStats.MergeBasicStats()
+			rows = copStats.GetActRows()
+		}
+		e.appendRow([]interface{}{op.Origin.ExplainID().String(), duration.Seconds(), rows})
+	}
+}
+
 func (e *ShowExec) fetchShowPlugins() error {
 	tiPlugins := plugin.GetAll()
 	for _, ps := range tiPlugins {
@@ -2238,10 +2417,44 @@ func handleImportJobInfo(info *importer.JobInfo, result *chunk.Chunk) error {
 	return nil
 }
 
+// handleImportJobFileStatus fills the result with the columns of handleImportJobInfo followed by
+// {"File_Path", "Subtask_ID", "File_Read_Bytes", "File_Imported_Rows", "File_Status", "File_Error"},
+// one row per source file. If the files of the job are unknown, like the job failed before submitting
+// its task, one row with NULL file columns is filled.
+func handleImportJobFileStatus(info *importer.JobInfo, result *chunk.Chunk) error {
+	statuses, err := loaddata.GetTaskFileStatus(info.ID)
+	if err != nil {
+		return err
+	}
+	const fileColOffset = 13
+	if len(statuses) == 0 {
+		if err := handleImportJobInfo(info, result); err != nil {
+			return err
+		}
+		for i := fileColOffset; i < result.NumCols(); i++ {
+			result.AppendNull(i)
+		}
+		return nil
+	}
+	for _, status := range statuses {
+		if err := handleImportJobInfo(info, result); err != nil {
+			return err
+		}
+		result.AppendString(fileColOffset, status.Path)
+		result.AppendInt64(fileColOffset+1, status.SubtaskID)
+		result.AppendInt64(fileColOffset+2, status.ReadBytes)
+		result.AppendUint64(fileColOffset+3, status.ImportedRows)
+		result.AppendString(fileColOffset+4, status.State)
+		result.AppendString(fileColOffset+5, status.Error)
+	}
+	return nil
+}
+
 // fetchShowImportJobs fills the result with the schema:
 // {"Job_ID", "Data_Source", "Target_Table", "Table_ID",
 // "Phase", "Status", "Source_File_Size", "Imported_Rows",
-// "Result_Message", "Create_Time", "Start_Time", "End_Time", "Created_By"}
+// "Result_Message", "Create_Time", "Start_Time", "End_Time", "Created_By"},
+// and the file columns of handleImportJobFileStatus for `SHOW IMPORT JOB <ID>`.
 func (e *ShowExec) fetchShowImportJobs(ctx context.Context) error {
 	var hasSuperPriv bool
 	if pm := privilege.GetPrivilegeManager(e.ctx); pm != nil {
@@ -2262,7 +2475,7 @@ func (e *ShowExec) fetchShowImportJobs(ctx context.Context) error {
 		}); err != nil {
 			return err
 		}
-		return handleImportJobInfo(info, e.result)
+		return handleImportJobFileStatus(info, e.result)
 	}
 	var infos []*importer.JobInfo
 	if err = globalTaskManager.WithNewSession(func(se sessionctx.Context) error {
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/mathutil"
 	"github.com/tikv/client-go/v2/oracle"
 	"golang.org/x/exp/slices"
 )
@@ -159,7 +160,13 @@ func (e *ShowExec) fetchShowStatsLocked() error {
 	h := do.StatsHandle()
 	dbs := do.InfoSchema().AllSchemas()
 	for _, db := range dbs {
+		if e.StatsDBFilter != "" && db.Name.L != e.StatsDBFilter {
+			continue
+		}
 		for _, tbl := range db.Tables {
+			if e.StatsTableFilter != "" && tbl.Name.L != e.StatsTableFilter {
+				continue
+			}
 			pi := tbl.GetPartitionInfo()
 			if pi == nil || e.ctx.GetSessionVars().IsDynamicPartitionPruneEnabled() {
 				partitionName := ""
@@ -427,7 +434,14 @@ func (e *ShowExec) bucketsToRows(dbName, tblName, partitionName, colName string,
 	if numOfCols > 0 {
 		isIndex = 1
 	}
-	for i := 0; i < hist.Len(); i++ {
+	start, end := 0, hist.Len()
+	if e.StatsBucketLower != nil && *e.StatsBucketLower > 0 {
+		start = int(mathutil.Min(*e.StatsBucketLower, int64(end)))
+	}
+	if e.StatsBucketUpper != nil && *e.StatsBucketUpper < int64(end) {
+		end = int(mathutil.Max(*e.StatsBucketUpper+1, int64(start)))
+	}
+	for i := start; i < end; i++ {
 		lowerBoundStr, err := statistics.ValueToString(e.ctx.GetSessionVars(), hist.GetLower(i), numOfCols, idxColumnTypes)
 		if err != nil {
 			return errors.Trace(err)
@@ -498,6 +512,9 @@ func (e *ShowExec) appendTableForStatsHealthy(dbName, tblName, partitionName str
 	if !ok {
 		return
 	}
+	if e.HealthyThreshold != nil && healthy >= *e.HealthyThreshold {
+		return
+	}
 	e.appendRow([]interface{}{
 		dbName,
 		tblName,
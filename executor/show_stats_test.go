@@ -62,6 +62,32 @@ func TestShowStatsLocked(t *testing.T) {
 	require.Equal(t, "t", result.Rows()[0][1])
 }
 
+func TestShowStatsLockedFilter(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database db1")
+	tk.MustExec("create database db2")
+	tk.MustExec("create table db1.t (a int)")
+	tk.MustExec("create table db2.t (a int)")
+	tk.MustExec("create table db2.t1 (a int)")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'static'")
+	tk.MustExec("create table db2.tp (a int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20))")
+	tk.MustExec("lock stats db1.t, db2.t, db2.t1, db2.tp")
+
+	tk.MustQuery("show stats_locked where db_name like 'db%'").Sort().Check(testkit.Rows(
+		"db1 t  locked", "db2 t  locked", "db2 t1  locked", "db2 tp p0 locked", "db2 tp p1 locked"))
+	tk.MustQuery("show stats_locked where table_name = 't'").Sort().Check(testkit.Rows("db1 t  locked", "db2 t  locked"))
+	tk.MustQuery("show stats_locked where 'tp' = table_name and partition_name = 'p1'").Check(testkit.Rows("db2 tp p1 locked"))
+	tk.MustQuery("show stats_locked where db_name = 'db1' and table_name = 't1'").Check(testkit.Rows())
+	// The conditions which can't be pushed down are still evaluated.
+	tk.MustQuery("show stats_locked where db_name = 'db2' or table_name = 't'").Sort().Check(testkit.Rows(
+		"db1 t  locked", "db2 t  locked", "db2 t1  locked", "db2 tp p0 locked", "db2 tp p1 locked"))
+
+	tk.MustExec("unlock stats db2.t")
+	tk.MustQuery("show stats_locked where db_name = 'db2' and table_name = 't'").Check(testkit.Rows())
+}
+
 func TestShowStatsHistograms(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 
@@ -138,6 +164,39 @@ func TestShowStatsBuckets(t *testing.T) {
 	result.Check(testkit.Rows("test t  idx 1 0 1 1 (2020-01-01 00:00:00, 1) (2020-01-01 00:00:00, 1) 0"))
 }
 
+func TestShowStatsBucketsRange(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version=1")
+	tk.MustExec("create table t (a int, key idx(a))")
+	tk.MustExec("insert into t values (0), (1), (2), (3), (4), (5), (6), (7), (8), (9)")
+	tk.MustExec("analyze table t with 10 buckets, 0 topn")
+
+	bucketIDs := func(where string) []string {
+		rows := tk.MustQuery("show stats_buckets where column_name = 'idx' and " + where).Rows()
+		ids := make([]string, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row[5].(string))
+		}
+		return ids
+	}
+	require.Len(t, tk.MustQuery("show stats_buckets where column_name = 'idx'").Rows(), 10)
+	require.Equal(t, []string{"3", "4", "5"}, bucketIDs("bucket_id between 3 and 5"))
+	tk.MustQuery("show stats_buckets where column_name = 'idx' and bucket_id = 4").Check(testkit.Rows("test t  idx 1 4 5 1 4 4 0"))
+	require.Equal(t, []string{"7", "8", "9"}, bucketIDs("bucket_id > 6"))
+	require.Equal(t, []string{"0", "1"}, bucketIDs("2 > bucket_id"))
+	require.Equal(t, []string{"2", "3"}, bucketIDs("bucket_id >= 2 and bucket_id <= 3"))
+	require.Equal(t, []string{"8", "9"}, bucketIDs("bucket_id between 8 and 100"))
+	require.Empty(t, bucketIDs("bucket_id between 5 and 3"))
+	require.Empty(t, bucketIDs("bucket_id < 0"))
+	// The conditions which can't be pushed down are still evaluated.
+	require.Equal(t, []string{"1", "3"}, bucketIDs("bucket_id between 1 and 3 and bucket_id != 2"))
+	require.Equal(t, []string{"0", "9"}, bucketIDs("bucket_id not between 1 and 8"))
+	require.Equal(t, []string{"0", "9"}, bucketIDs("(bucket_id = 0 or bucket_id = 9)"))
+}
+
 func TestShowStatsHasNullValue(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 
@@ -28,9 +28,11 @@ import (
 
 	"github.com/ngaut/pools"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/distsql"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/errno"
 	executor_metrics "github.com/pingcap/tidb/executor/metrics"
 	"github.com/pingcap/tidb/expression"
@@ -2536,6 +2538,9 @@ func (e *SimpleExec) executeSetPwd(ctx context.Context, s *ast.SetPwdStmt) error
 }
 
 func (e *SimpleExec) executeKillStmt(ctx context.Context, s *ast.KillStmt) error {
+	if s.Digest != "" {
+		return e.executeKillQueryByDigest(ctx, s.Digest)
+	}
 	if x, ok := s.Expr.(*ast.FuncCallExpr); ok {
 		if x.FnName.L == ast.ConnectionID {
 			sm := e.ctx.GetSessionManager()
@@ -2551,7 +2556,7 @@ func (e *SimpleExec) executeKillStmt(ctx context.Context, s *ast.KillStmt) error
 			if sm == nil {
 				return nil
 			}
-			sm.Kill(s.ConnectionID, s.Query, false)
+			e.killLocalConn(ctx, sm, s)
 		} else {
 			err := errors.New("Invalid operation. Please use 'KILL TIDB [CONNECTION | QUERY] [connectionID | CONNECTION_ID()]' instead")
 			e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
@@ -2566,7 +2571,7 @@ func (e *SimpleExec) executeKillStmt(ctx context.Context, s *ast.KillStmt) error
 	if e.IsFromRemote {
 		logutil.BgLogger().Info("Killing connection in current instance redirected from remote TiDB", zap.Uint64("conn", s.ConnectionID), zap.Bool("query", s.Query),
 			zap.String("sourceAddr", e.ctx.GetSessionVars().SourceAddr.IP.String()))
-		sm.Kill(s.ConnectionID, s.Query, false)
+		e.killLocalConn(ctx, sm, s)
 		return nil
 	}
 
@@ -2587,21 +2592,176 @@ func (e *SimpleExec) executeKillStmt(ctx context.Context, s *ast.KillStmt) error
 	}
 
 	if gcid.ServerID != sm.ServerID() {
+		if s.Force {
+			e.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.New("KILL FORCE is not supported for remote connection, the connection is killed gracefully"))
+		}
 		if err := killRemoteConn(ctx, e.ctx, &gcid, s.Query); err != nil {
 			err1 := errors.New("KILL remote connection failed: " + err.Error())
 			e.ctx.GetSessionVars().StmtCtx.AppendWarning(err1)
 		}
 	} else {
-		sm.Kill(s.ConnectionID, s.Query, false)
+		e.killLocalConn(ctx, sm, s)
 	}
 
 	return nil
 }
 
+// killForceGracePeriod is how long "KILL ... FORCE" waits for the connection to terminate before closing it.
+var killForceGracePeriod = 3 * time.Second
+
+// killLocalConn kills the connection or the query in the current instance. For "KILL ... FORCE", it waits for
+// the connection to terminate, and closes its network connection if it is still running after the grace period.
+func (e *SimpleExec) killLocalConn(ctx context.Context, sm util.SessionManager, s *ast.KillStmt) {
+	sm.Kill(s.ConnectionID, s.Query, false)
+	if !s.Force {
+		return
+	}
+	gracePeriod := killForceGracePeriod
+	failpoint.Inject("mockKillForceGracePeriod", func(val failpoint.Value) {
+		gracePeriod = time.Duration(val.(int)) * time.Millisecond
+	})
+	timer := time.NewTimer(gracePeriod)
+	defer timer.Stop()
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		pi, ok := sm.GetProcessInfo(s.ConnectionID)
+		if !ok || pi == nil || pi.Command == mysql.ComSleep {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			logutil.Logger(ctx).Warn("connection is not terminated in the grace period, force to close it",
+				zap.Uint64("conn", s.ConnectionID), zap.Duration("gracePeriod", gracePeriod))
+			sm.KillForce(s.ConnectionID)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// executeKillQueryByDigest terminates the running queries whose SQL digest is digest, and reports the number of the
+// terminated queries as the affected rows.
+func (e *SimpleExec) executeKillQueryByDigest(ctx context.Context, digest string) error {
+	sm := e.ctx.GetSessionManager()
+	if sm == nil {
+		return nil
+	}
+	digest = strings.ToLower(digest)
+	var killed uint64
+	for _, pi := range sm.ShowProcessList() {
+		if pi.Command == mysql.ComSleep || pi.Digest != digest || pi.ID == e.ctx.GetSessionVars().ConnectionID {
+			continue
+		}
+		sm.Kill(pi.ID, true, false)
+		killed++
+	}
+	// Without global kill, the connection IDs of the other instances may conflict with the local ones.
+	if config.GetGlobalConfig().EnableGlobalKill && !e.IsFromRemote {
+		remoteKilled, err := e.killRemoteQueriesByDigest(ctx, sm.ServerID(), digest)
+		if err != nil {
+			err1 := errors.New("KILL remote queries failed: " + err.Error())
+			e.ctx.GetSessionVars().StmtCtx.AppendWarning(err1)
+		}
+		killed += remoteKilled
+	}
+	logutil.BgLogger().Info("Killed queries by digest", zap.String("digest", digest), zap.Uint64("count", killed))
+	e.ctx.GetSessionVars().StmtCtx.AddAffectedRows(killed)
+	return nil
+}
+
+// killRemoteQueriesByDigest terminates the running queries whose SQL digest is digest in the other instances, which
+// are found in the cluster processlist.
+func (e *SimpleExec) killRemoteQueriesByDigest(ctx context.Context, localServerID uint64, digest string) (uint64, error) {
+	rows, err := e.fetchRemoteQueriesByDigest(ctx, digest)
+	if err != nil {
+		return 0, err
+	}
+	var killed uint64
+	for _, row := range rows {
+		gcid, isTruncated, err := globalconn.ParseConnID(row.GetUint64(0))
+		if err != nil || isTruncated || gcid.ServerID == localServerID {
+			continue
+		}
+		// The planner only checks the privileges against the local queries.
+		if user, host := row.GetString(1), row.GetString(2); !e.canKillQueryOf(user, host) {
+			logutil.BgLogger().Info("skip killing the remote query without the privilege", zap.Uint64("conn", gcid.ToConnID()),
+				zap.String("user", user), zap.String("host", host))
+			continue
+		}
+		if err := killRemoteConn(ctx, e.ctx, &gcid, true); err != nil {
+			return killed, err
+		}
+		killed++
+	}
+	return killed, nil
+}
+
+// fetchRemoteQueriesByDigest returns the id, user and host of the running queries whose SQL digest is digest in the
+// cluster processlist.
+func (e *SimpleExec) fetchRemoteQueriesByDigest(ctx context.Context, digest string) ([]chunk.Row, error) {
+	failpoint.Inject("mockRemoteQueriesByDigest", func(val failpoint.Value) {
+		// The queries are mocked as "id,user,host" separated by ";".
+		queries := strings.Split(val.(string), ";")
+		fieldTypes := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong), types.NewFieldType(mysql.TypeVarchar), types.NewFieldType(mysql.TypeVarchar)}
+		chk := chunk.NewChunkWithCapacity(fieldTypes, len(queries))
+		for _, query := range queries {
+			fields := strings.Split(query, ",")
+			id, _ := strconv.ParseUint(fields[0], 10, 64)
+			chk.AppendUint64(0, id)
+			chk.AppendString(1, fields[1])
+			chk.AppendString(2, fields[2])
+		}
+		rows := make([]chunk.Row, 0, chk.NumRows())
+		for i := 0; i < chk.NumRows(); i++ {
+			rows = append(rows, chk.GetRow(i))
+		}
+		failpoint.Return(rows, nil)
+	})
+	serversInfo, err := infosync.GetAllServerInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(serversInfo) <= 1 {
+		// There are no other instances.
+		return nil, nil
+	}
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnOthers)
+	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+	rows, _, err := exec.ExecRestrictedSQL(ctx, nil,
+		"SELECT id, user, host FROM information_schema.cluster_processlist WHERE digest = %? AND command != 'Sleep'", digest)
+	return rows, err
+}
+
+// canKillQueryOf checks whether the current user can kill the queries of user@host, which is the same as the
+// privilege check of KILL in the planner: killing the queries of the other users requires SUPER or CONNECTION_ADMIN,
+// and killing the queries of the RESTRICTED_USER_ADMIN users requires RESTRICTED_CONNECTION_ADMIN if SEM is enabled.
+func (e *SimpleExec) canKillQueryOf(user, host string) bool {
+	checker := privilege.GetPrivilegeManager(e.ctx)
+	loginUser := e.ctx.GetSessionVars().User
+	if checker == nil || loginUser == nil {
+		return true
+	}
+	activeRoles := e.ctx.GetSessionVars().ActiveRoles
+	if user != loginUser.Username && !checker.RequestDynamicVerification(activeRoles, "CONNECTION_ADMIN", false) {
+		return false
+	}
+	if sem.IsEnabled() && checker.RequestDynamicVerificationWithUser("RESTRICTED_USER_ADMIN", false, &auth.UserIdentity{Username: user, Hostname: host}) &&
+		!checker.RequestDynamicVerification(activeRoles, "RESTRICTED_CONNECTION_ADMIN", false) {
+		return false
+	}
+	return true
+}
+
 func killRemoteConn(ctx context.Context, sctx sessionctx.Context, gcid *globalconn.GCID, query bool) error {
 	if gcid.ServerID == 0 {
 		return errors.New("Unexpected ZERO ServerID. Please file a bug to the TiDB Team")
 	}
+	failpoint.Inject("mockKillRemoteConn", func() {
+		failpoint.Return(nil)
+	})
 
 	killExec := &tipb.Executor{
 		Tp:   tipb.ExecType_TypeKill,
@@ -18,15 +18,20 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/server"
 	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/globalconn"
+	"github.com/pingcap/tidb/util/sem"
 	"github.com/stretchr/testify/require"
 )
 
@@ -84,6 +89,101 @@ func TestKillStmt(t *testing.T) {
 	// remote kill is tested in `tests/globalkilltest`
 }
 
+type killRecordSessionManager struct {
+	testkit.MockSessionManager
+	killedQueries []uint64
+}
+
+// Kill implements the SessionManager.Kill interface.
+func (sm *killRecordSessionManager) Kill(connID uint64, query bool, _ bool) {
+	if query {
+		sm.killedQueries = append(sm.killedQueries, connID)
+	}
+}
+
+func TestKillQueryByDigest(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create user 'killer'")
+	_, digest := parser.NormalizeDigest("select * from t where a = 1")
+	_, otherDigest := parser.NormalizeDigest("select * from t where b = 1")
+	sm := &killRecordSessionManager{
+		MockSessionManager: testkit.MockSessionManager{
+			PS: []*util.ProcessInfo{
+				{ID: 101, User: "root", Host: "%", Command: mysql.ComQuery, Digest: digest.String()},
+				{ID: 102, User: "u1", Host: "%", Command: mysql.ComQuery, Digest: digest.String()},
+				{ID: 103, User: "u1", Host: "%", Command: mysql.ComQuery, Digest: otherDigest.String()},
+				{ID: 104, User: "u2", Host: "%", Command: mysql.ComStmtExecute, Digest: digest.String()},
+				{ID: 105, User: "u2", Host: "%", Command: mysql.ComSleep, Digest: digest.String()},
+			},
+		},
+	}
+	tk.Session().SetSessionManager(sm)
+
+	tk.MustExec(fmt.Sprintf("kill tidb query digest '%s'", strings.ToUpper(digest.String())))
+	require.Equal(t, uint64(3), tk.Session().AffectedRows())
+	require.ElementsMatch(t, []uint64{101, 102, 104}, sm.killedQueries)
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+
+	sm.killedQueries = nil
+	tk.MustExec("kill query digest 'not_a_running_digest'")
+	require.Equal(t, uint64(0), tk.Session().AffectedRows())
+	require.Empty(t, sm.killedQueries)
+
+	// Only the users with the SUPER or CONNECTION_ADMIN privilege can kill queries by digest.
+	tk2 := testkit.NewTestKit(t, store)
+	require.NoError(t, tk2.Session().Auth(&auth.UserIdentity{Username: "killer", Hostname: "%"}, nil, nil, nil))
+	tk2.Session().SetSessionManager(sm)
+	tk2.MustGetErrMsg(fmt.Sprintf("kill query digest '%s'", otherDigest.String()),
+		"[planner:1227]Access denied; you need (at least one of) the SUPER or CONNECTION_ADMIN privilege(s) for this operation")
+	require.Empty(t, sm.killedQueries)
+	tk.MustExec("grant connection_admin on *.* to 'killer'")
+	tk2.MustExec(fmt.Sprintf("kill query digest '%s'", otherDigest.String()))
+	require.Equal(t, uint64(1), tk2.Session().AffectedRows())
+	require.Equal(t, []uint64{103}, sm.killedQueries)
+}
+
+func TestKillRemoteQueryByDigestPrivilege(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create user 'killer', 'u1', 'restricted'")
+	tk.MustExec("grant connection_admin on *.* to 'killer'")
+	tk.MustExec("grant restricted_user_admin on *.* to 'restricted'")
+	_, digest := parser.NormalizeDigest("select * from t where a = 1")
+	sm := &killRecordSessionManager{MockSessionManager: testkit.MockSessionManager{SerID: 1}}
+
+	// The queries of u1, restricted and killer are running in the instance whose server ID is 2.
+	var queries []string
+	for i, user := range []string{"u1", "restricted", "killer"} {
+		gcid := globalconn.GCID{ServerID: 2, LocalConnID: uint64(i + 1), Is64bits: true}
+		queries = append(queries, fmt.Sprintf("%d,%s,%%", gcid.ToConnID(), user))
+	}
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/executor/mockRemoteQueriesByDigest", fmt.Sprintf(`return("%s")`, strings.Join(queries, ";"))))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/executor/mockRemoteQueriesByDigest"))
+	}()
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/executor/mockKillRemoteConn", "return"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/executor/mockKillRemoteConn"))
+	}()
+
+	tk2 := testkit.NewTestKit(t, store)
+	require.NoError(t, tk2.Session().Auth(&auth.UserIdentity{Username: "killer", Hostname: "%"}, nil, nil, nil))
+	tk2.Session().SetSessionManager(sm)
+	killSQL := fmt.Sprintf("kill query digest '%s'", digest.String())
+	tk2.MustExec(killSQL)
+	require.Equal(t, uint64(3), tk2.Session().AffectedRows())
+
+	// With SEM enabled, the queries of the RESTRICTED_USER_ADMIN users are skipped without RESTRICTED_CONNECTION_ADMIN.
+	sem.Enable()
+	defer sem.Disable()
+	tk2.MustExec(killSQL)
+	require.Equal(t, uint64(2), tk2.Session().AffectedRows())
+	tk.MustExec("grant restricted_connection_admin on *.* to 'killer'")
+	tk2.MustExec(killSQL)
+	require.Equal(t, uint64(3), tk2.Session().AffectedRows())
+}
+
 func TestUserAttributes(t *testing.T) {
 	store, _ := testkit.CreateMockStoreAndDomain(t)
 	rootTK := testkit.NewTestKit(t, store)
@@ -152,6 +252,11 @@ func TestSetResourceGroup(t *testing.T) {
 	tk.MustExec("CREATE RESOURCE GROUP rg2 ru_per_sec = 200")
 	tk.MustExec("SET RESOURCE GROUP `rg2`")
 	tk.MustQuery("SELECT CURRENT_RESOURCE_GROUP()").Check(testkit.Rows("rg2"))
+	// The nonexistent group is rejected and the active group of the session is kept.
+	tk.MustContainErrMsg("SET RESOURCE GROUP rg3", "Unknown resource group 'rg3'")
+	tk.MustQuery("SELECT CURRENT_RESOURCE_GROUP()").Check(testkit.Rows("rg2"))
+	tk.MustExec("SET RESOURCE GROUP `RG1`")
+	tk.MustQuery("SELECT CURRENT_RESOURCE_GROUP()").Check(testkit.Rows("rg1"))
 	tk.MustExec("SET RESOURCE GROUP ``")
 	tk.MustQuery("SELECT CURRENT_RESOURCE_GROUP()").Check(testkit.Rows(""))
 
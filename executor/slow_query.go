@@ -16,6 +16,7 @@ package executor
 
 import (
 	"bufio"
+	"container/heap"
 	"context"
 	"fmt"
 	"io"
@@ -76,6 +77,10 @@ type slowQueryRetriever struct {
 	lastFetchSize int64
 	cancel        context.CancelFunc
 	wg            sync.WaitGroup
+
+	// queryTimeIdx is the offset of the query_time column in outputCols, it is -1 if it's not retrieved.
+	queryTimeIdx int
+	topNFetched  bool
 }
 
 func (e *slowQueryRetriever) retrieve(ctx context.Context, sctx sessionctx.Context) ([][]types.Datum, error) {
@@ -87,6 +92,9 @@ func (e *slowQueryRetriever) retrieve(ctx context.Context, sctx sessionctx.Conte
 		ctx, e.cancel = context.WithCancel(ctx)
 		e.initializeAsyncParsing(ctx, sctx)
 	}
+	if e.extractor.Limit > 0 && e.queryTimeIdx >= 0 {
+		return e.dataForSlowLogTopN(ctx, sctx)
+	}
 	return e.dataForSlowLog(ctx, sctx)
 }
 
@@ -98,7 +106,11 @@ func (e *slowQueryRetriever) initialize(ctx context.Context, sctx sessionctx.Con
 	}
 	// initialize column value factories.
 	e.columnValueFactoryMap = make(map[string]slowQueryColumnValueFactory, len(e.outputCols))
+	e.queryTimeIdx = -1
 	for idx, col := range e.outputCols {
+		if col.Name.O == variable.SlowLogQueryTimeStr {
+			e.queryTimeIdx = idx
+		}
 		if col.Name.O == util.ClusterTableInstanceColumnName {
 			e.instanceFactory, err = getInstanceColumnValueFactory(sctx, idx)
 			if err != nil {
@@ -247,6 +259,86 @@ func (e *slowQueryRetriever) dataForSlowLog(ctx context.Context, sctx sessionctx
 	}
 }
 
+// dataForSlowLogTopN drains all the parsed slow logs and only keeps the slowest
+// `e.extractor.Limit` queries, which are returned in the descending order of query_time.
+func (e *slowQueryRetriever) dataForSlowLogTopN(ctx context.Context, sctx sessionctx.Context) ([][]types.Datum, error) {
+	if e.topNFetched {
+		e.memConsume(-e.lastFetchSize)
+		e.lastFetchSize = 0
+		return nil, nil
+	}
+	e.topNFetched = true
+	h := &slowQueryTopNHeap{queryTimeIdx: e.queryTimeIdx}
+	var heapSize int64
+	for {
+		rows, err := e.dataForSlowLog(ctx, sctx)
+		if err != nil {
+			e.memConsume(-heapSize)
+			return nil, err
+		}
+		if rows == nil {
+			break
+		}
+		for _, row := range rows {
+			if uint64(h.Len()) < e.extractor.Limit {
+				heap.Push(h, row)
+				heapSize += types.EstimatedMemUsage(row, 1)
+				e.memConsume(types.EstimatedMemUsage(row, 1))
+				continue
+			}
+			if !h.lessThan(h.rows[0], row) {
+				continue
+			}
+			evicted := h.rows[0]
+			h.rows[0] = row
+			heap.Fix(h, 0)
+			delta := types.EstimatedMemUsage(row, 1) - types.EstimatedMemUsage(evicted, 1)
+			heapSize += delta
+			e.memConsume(delta)
+		}
+	}
+	result := make([][]types.Datum, h.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(h).([]types.Datum)
+	}
+	e.lastFetchSize = heapSize
+	return result, nil
+}
+
+// slowQueryTopNHeap is a min-heap of slow log rows ordered by query_time,
+// the fastest query among the kept ones is on the top and evicted first.
+type slowQueryTopNHeap struct {
+	rows         [][]types.Datum
+	queryTimeIdx int
+}
+
+func (h *slowQueryTopNHeap) lessThan(lhs, rhs []types.Datum) bool {
+	return lhs[h.queryTimeIdx].GetFloat64() < rhs[h.queryTimeIdx].GetFloat64()
+}
+
+func (h *slowQueryTopNHeap) Len() int {
+	return len(h.rows)
+}
+
+func (h *slowQueryTopNHeap) Less(i, j int) bool {
+	return h.lessThan(h.rows[i], h.rows[j])
+}
+
+func (h *slowQueryTopNHeap) Swap(i, j int) {
+	h.rows[i], h.rows[j] = h.rows[j], h.rows[i]
+}
+
+func (h *slowQueryTopNHeap) Push(x interface{}) {
+	h.rows = append(h.rows, x.([]types.Datum))
+}
+
+func (h *slowQueryTopNHeap) Pop() interface{} {
+	n := len(h.rows)
+	x := h.rows[n-1]
+	h.rows = h.rows[:n-1]
+	return x
+}
+
 type slowLogChecker struct {
 	// Below fields is used to check privilege.
 	hasProcessPriv bool
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime/pprof"
 	"strings"
 	"testing"
@@ -38,6 +39,7 @@ import (
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slices"
 )
 
 func parseLog(retriever *slowQueryRetriever, sctx sessionctx.Context, reader *bufio.Reader) ([][]types.Datum, error) {
@@ -661,6 +663,54 @@ select 9;`
 	}
 }
 
+func TestSlowQueryRetrieverTopN(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "tidb-slow-topn.log")
+	var slowLog strings.Builder
+	for i := 0; i < 100; i++ {
+		// make the query time out of order, and some of them are equal.
+		slowLog.WriteString(fmt.Sprintf("# Time: 2020-02-15T18:00:%02d.000000+08:00\n", i%60))
+		slowLog.WriteString(fmt.Sprintf("# Query_time: %v\n", float64(i*37%50)/10))
+		slowLog.WriteString(fmt.Sprintf("select %d;\n", i))
+	}
+	prepareLogs(t, []string{slowLog.String()}, []string{fileName})
+	defer func() {
+		removeFiles([]string{fileName})
+	}()
+	sctx := mock.NewContext()
+	sctx.GetSessionVars().SlowQueryFile = fileName
+
+	retrieveAll := func(limit uint64) []float64 {
+		retriever, err := newSlowQueryRetriever()
+		require.NoError(t, err)
+		retriever.extractor = &plannercore.SlowQueryExtractor{Limit: limit}
+		var queryTimes []float64
+		for {
+			rows, err := retriever.retrieve(context.Background(), sctx)
+			require.NoError(t, err)
+			if rows == nil {
+				break
+			}
+			for _, row := range rows {
+				queryTimes = append(queryTimes, row[retriever.queryTimeIdx].GetFloat64())
+			}
+		}
+		require.NoError(t, retriever.close())
+		return queryTimes
+	}
+
+	all := retrieveAll(0)
+	require.Len(t, all, 100)
+	slices.SortFunc(all, func(a, b float64) bool { return a > b })
+	for _, limit := range []uint64{1, 3, 10, 99, 100, 200} {
+		topN := retrieveAll(limit)
+		expected := all
+		if limit < uint64(len(all)) {
+			expected = all[:limit]
+		}
+		require.Equal(t, expected, topN, "limit: %v", limit)
+	}
+}
+
 func TestCancelParseSlowLog(t *testing.T) {
 	fileName := "tidb-slow-2020-02-14T19-04-05.01.log"
 	slowLog := `# Time: 2019-04-28T15:24:04.309074+08:00
@@ -24,6 +24,7 @@ import (
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/mathutil"
@@ -317,6 +318,10 @@ type TopNExec struct {
 	rowChunks *chunk.List
 	// rowPointer store the chunk index and row index for each row.
 	rowPtrs []chunk.RowPtr
+	// spareRowChunks and spareRowPtrs are swapped with rowChunks and rowPtrs by the compaction,
+	// so that the memory of the evicted rows is reused by the next compaction.
+	spareRowChunks *chunk.List
+	spareRowPtrs   []chunk.RowPtr
 
 	chkHeap *topNChunkHeap
 }
@@ -428,11 +433,22 @@ func (e *TopNExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	return nil
 }
 
+// newRowChunks creates a list to store the candidate rows, its chunks are allocated from the AllocPool.
+func (e *TopNExec) newRowChunks() *chunk.List {
+	sessVars := e.ctx.GetSessionVars()
+	rowChunks := chunk.NewListWithAlloc(retTypes(e), e.initCap, e.maxChunkSize,
+		func(fieldTypes []*types.FieldType, capacity, maxChunkSize int) *chunk.Chunk {
+			return sessVars.GetNewChunkWithCapacity(fieldTypes, capacity, maxChunkSize, e.AllocPool)
+		})
+	rowChunks.GetMemTracker().SetLabel(memory.LabelForRowChunks)
+	return rowChunks
+}
+
 func (e *TopNExec) loadChunksUntilTotalLimit(ctx context.Context) error {
 	e.chkHeap = &topNChunkHeap{e}
-	e.rowChunks = chunk.NewList(retTypes(e), e.initCap, e.maxChunkSize)
+	e.rowChunks = e.newRowChunks()
 	e.rowChunks.GetMemTracker().AttachTo(e.memTracker)
-	e.rowChunks.GetMemTracker().SetLabel(memory.LabelForRowChunks)
+	e.spareRowChunks, e.spareRowPtrs = nil, nil
 	for uint64(e.rowChunks.Len()) < e.totalLimit {
 		srcChk := tryNewCacheChunk(e.children[0])
 		// adjust required rows by total limit
@@ -503,19 +519,23 @@ func (e *TopNExec) processChildChk(childRowChk *chunk.Chunk) error {
 // If we don't do compaction, in a extreme case like the child data is already ascending sorted
 // but we want descending top N, then we will keep all data in memory.
 // But if data is distributed randomly, this function will be called log(n) times.
+// The rows are compacted into the spare chunks, and the chunks of the old rows are kept as the spare
+// chunks of the next compaction, so the memory isn't reallocated by every compaction.
 func (e *TopNExec) doCompaction() error {
-	newRowChunks := chunk.NewList(retTypes(e), e.initCap, e.maxChunkSize)
-	newRowPtrs := make([]chunk.RowPtr, 0, e.rowChunks.Len())
+	if e.spareRowChunks == nil {
+		e.spareRowChunks = e.newRowChunks()
+		e.spareRowPtrs = make([]chunk.RowPtr, 0, len(e.rowPtrs))
+		e.memTracker.Consume(int64(8 * cap(e.spareRowPtrs)))
+	}
+	newRowChunks, newRowPtrs := e.spareRowChunks, e.spareRowPtrs[:0]
 	for _, rowPtr := range e.rowPtrs {
 		newRowPtr := newRowChunks.AppendRow(e.rowChunks.GetRow(rowPtr))
 		newRowPtrs = append(newRowPtrs, newRowPtr)
 	}
-	newRowChunks.GetMemTracker().SetLabel(memory.LabelForRowChunks)
+	// The old chunks are released from the tracker, they are only kept to be reused by the next compaction.
 	e.memTracker.ReplaceChild(e.rowChunks.GetMemTracker(), newRowChunks.GetMemTracker())
-	e.rowChunks = newRowChunks
-
-	e.memTracker.Consume(int64(-8 * len(e.rowPtrs)))
-	e.memTracker.Consume(int64(8 * len(newRowPtrs)))
-	e.rowPtrs = newRowPtrs
+	e.rowChunks.Reset()
+	e.spareRowChunks, e.rowChunks = e.rowChunks, newRowChunks
+	e.spareRowPtrs, e.rowPtrs = e.rowPtrs, newRowPtrs
 	return nil
 }
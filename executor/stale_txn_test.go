@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/ddl/placement"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessiontxn"
 	"github.com/pingcap/tidb/sessiontxn/staleread"
 	"github.com/pingcap/tidb/testkit"
@@ -342,6 +343,80 @@ func TestStaleReadKVRequest(t *testing.T) {
 	require.Len(t, rows6, 0)
 }
 
+func TestStaleReadPreferFollower(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	// For mocktikv, safe point is not initialized, we manually insert it for snapshot to use.
+	safePointName := "tikv_gc_safe_point"
+	safePointValue := "20160102-15:04:05 -0700"
+	safePointComment := "All versions after safe point can be accessed. (DO NOT EDIT)"
+	updateSafePoint := fmt.Sprintf(`INSERT INTO mysql.tidb VALUES ('%[1]s', '%[2]s', '%[3]s')
+	ON DUPLICATE KEY
+	UPDATE variable_value = '%[2]s', comment = '%[3]s'`, safePointName, safePointValue, safePointComment)
+	tk.MustExec(updateSafePoint)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key)")
+	tk.MustExec("insert into t values (1), (2), (3)")
+	time.Sleep(2 * time.Second)
+
+	fpName := "github.com/pingcap/tidb/executor/assertStaleReadReplicaRead"
+	copFpName := "github.com/pingcap/tidb/distsql/assertStaleReadReplicaRead"
+	enableFailpoints := func(replicaRead kv.ReplicaReadType) {
+		require.NoError(t, failpoint.Enable(fpName, fmt.Sprintf("return(%d)", replicaRead)))
+		require.NoError(t, failpoint.Enable(copFpName, fmt.Sprintf("return(%d)", replicaRead)))
+	}
+	defer func() {
+		require.NoError(t, failpoint.Disable(fpName))
+		require.NoError(t, failpoint.Disable(copFpName))
+	}()
+	sqls := []string{
+		"select * from t as of timestamp now(3) where id = 1",
+		"select * from t as of timestamp now(3) where id in (1, 2)",
+		"select * from t as of timestamp now(3) where id > 1",
+		"select count(*) from t as of timestamp now(3)",
+		"select /*+ INL_JOIN(t2) */ * from t as of timestamp now(3) join t t2 as of timestamp now(3) on t.id = t2.id",
+	}
+	txnSQLs := []string{
+		"select * from t where id = 1",
+		"select * from t where id > 1",
+	}
+	// the stale read follows the replica read of the session by default.
+	tk.MustExec("set @@tidb_replica_read='leader'")
+	enableFailpoints(kv.ReplicaReadLeader)
+	for _, sql := range sqls {
+		tk.MustQuery(sql)
+	}
+	tk.MustExec(`START TRANSACTION READ ONLY AS OF TIMESTAMP NOW(3)`)
+	for _, sql := range txnSQLs {
+		tk.MustQuery(sql)
+	}
+	tk.MustExec("commit")
+
+	// the stale read prefers the followers when enabled.
+	tk.MustExec("set @@tidb_stale_read_prefer_follower=1")
+	enableFailpoints(kv.ReplicaReadFollower)
+	for _, sql := range sqls {
+		tk.MustQuery(sql)
+	}
+	tk.MustExec(`START TRANSACTION READ ONLY AS OF TIMESTAMP NOW(3)`)
+	for _, sql := range txnSQLs {
+		tk.MustQuery(sql)
+	}
+	tk.MustExec("commit")
+	// the non-stale read is not affected.
+	enableFailpoints(kv.ReplicaReadLeader)
+	tk.MustQuery("select * from t where id = 1")
+
+	// the explicit replica read of the session is kept.
+	tk.MustExec("set @@tidb_replica_read='leader-and-follower'")
+	enableFailpoints(kv.ReplicaReadMixed)
+	for _, sql := range sqls {
+		tk.MustQuery(sql)
+	}
+	tk.MustExec("set @@tidb_replica_read='leader'")
+}
+
 func TestStalenessAndHistoryRead(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -490,6 +565,59 @@ func TestTimeBoundedStalenessTxn(t *testing.T) {
 	require.NoError(t, failpoint.Disable("tikvclient/injectSafeTS"))
 }
 
+func TestBoundedStalenessReadPicksFreshReplica(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key)")
+	require.NoError(t, failpoint.Enable("tikvclient/injectTxnScope", `return("sh")`))
+	defer func() {
+		require.NoError(t, failpoint.Disable("tikvclient/injectTxnScope"))
+	}()
+
+	currentTS := func() uint64 {
+		time.Sleep(10 * time.Millisecond)
+		tk.MustExec("begin")
+		ts := tk.Session().GetSessionVars().TxnCtx.StartTS
+		tk.MustExec("commit")
+		time.Sleep(10 * time.Millisecond)
+		return ts
+	}
+	tk.MustExec("insert into t values (1)")
+	ts1 := currentTS()
+	tk.MustExec("alter table t add column c int default 0")
+	tk.MustExec("insert into t values (2, 2)")
+	ts2 := currentTS()
+
+	// The replicas out of the read replica scope lag behind, tidb_bounded_staleness resolves the ts by them.
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/expression/injectSafeTS", fmt.Sprintf("return(%v)", ts1)))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/expression/injectSafeTS"))
+	}()
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/sessiontxn/staleread/injectBoundedStalenessSafeTS",
+		fmt.Sprintf("return(%v)", ts2)))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/sessiontxn/staleread/injectBoundedStalenessSafeTS"))
+	}()
+	boundedRead := "select * from t as of timestamp tidb_bounded_staleness(now(3) - interval 20 second, now(3))"
+	tk.MustQuery(boundedRead).Check(testkit.Rows("1"))
+	tk.MustQuery(boundedRead + " where id = 2").Check(testkit.Rows())
+
+	// The closest replicas don't lag behind, so a fresher ts in the window is picked, the schema is read at it too.
+	tk.MustExec("set @@tidb_replica_read = 'closest-replicas'")
+	tk.MustQuery(boundedRead).Sort().Check(testkit.Rows("1 0", "2 2"))
+	tk.MustQuery(boundedRead + " where id = 2").Check(testkit.Rows("2 2"))
+	tk.MustQuery(boundedRead + " where id in (1, 2)").Sort().Check(testkit.Rows("1 0", "2 2"))
+	tk.MustQuery(boundedRead + " where c = 2").Check(testkit.Rows("2 2"))
+
+	// The exact stale read is not affected.
+	exactTime := oracle.GetTimeFromTS(ts1).Format("2006-01-02 15:04:05.000")
+	tk.MustQuery(fmt.Sprintf("select id from t as of timestamp '%s'", exactTime)).Check(testkit.Rows("1"))
+
+	// The ts resolved by tidb_bounded_staleness is kept if the fresher ts is out of the window.
+	tk.MustQuery(fmt.Sprintf("select * from t as of timestamp tidb_bounded_staleness(now(3) - interval 20 second, '%s')", exactTime)).Check(testkit.Rows("1"))
+}
+
 func TestStalenessTransactionSchemaVer(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
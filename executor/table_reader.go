@@ -37,7 +37,9 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/mathutil"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/stringutil"
@@ -57,6 +59,7 @@ type selectResultHook struct {
 
 func (sr selectResultHook) SelectResult(ctx context.Context, sctx sessionctx.Context, kvReq *kv.Request,
 	fieldTypes []*types.FieldType, fb *statistics.QueryFeedback, copPlanIDs []int, rootPlanID int) (distsql.SelectResult, error) {
+	ctx = withCopStoreBreaker(ctx, kvReq)
 	if sr.selectResultFunc == nil {
 		return distsql.SelectWithRuntimeStats(ctx, sctx, kvReq, fieldTypes, fb, copPlanIDs, rootPlanID)
 	}
@@ -105,6 +108,8 @@ type TableReaderExecutor struct {
 
 	memTracker       *memory.Tracker
 	selectResultHook // for testing
+	// handlesMemTracker tracks the memory of the handles if the reader is built from handles.
+	handlesMemTracker *memory.Tracker
 
 	keepOrder bool
 	desc      bool
@@ -123,6 +128,18 @@ type TableReaderExecutor struct {
 	virtualColumnRetFieldTypes []*types.FieldType
 	// batchCop indicates whether use super batch coprocessor request, only works for TiFlash engine.
 	batchCop bool
+	// countOnly indicates the coprocessor only returns the partial results of COUNT(*) without group by,
+	// so there is no column value to decode or virtual column to fill.
+	countOnly bool
+	// outputOffsets are the offsets of the schema columns returned by the coprocessor, the other columns are not
+	// used by the parent and are filled with NULL. nil means all the columns are returned.
+	outputOffsets []int
+	// chunkSizer limits the rows of a chunk for the wide rows, nil means the chunk is always full-sized.
+	chunkSizer *chunkSizeAdjuster
+	// splitLargeRegions indicates whether to split the range located in a single region into sub-ranges by
+	// handleHist, which is the histogram of the int handle, so the region can be scanned by parallel cop tasks.
+	splitLargeRegions bool
+	handleHist        *statistics.Histogram
 
 	// If dummy flag is set, this is not a real TableReader, it just provides the KV ranges for UnionScan.
 	// Used by the temporary table, cached table.
@@ -258,12 +275,24 @@ func (e *TableReaderExecutor) Next(ctx context.Context, req *chunk.Chunk) error
 		}
 		return tableName
 	}), e.ranges)
-	if err := e.resultHandler.nextChunk(ctx, req); err != nil {
+	requiredRows := req.RequiredRows()
+	e.chunkSizer.limit(req)
+	var err error
+	if e.outputOffsets != nil {
+		err = e.nextPrunedChunk(ctx, req)
+	} else {
+		err = e.resultHandler.nextChunk(ctx, req)
+	}
+	e.chunkSizer.adjust(req, requiredRows)
+	if err != nil {
 		e.feedback.Invalidate()
 		return err
 	}
 
-	err := table.FillVirtualColumnValue(e.virtualColumnRetFieldTypes, e.virtualColumnIndex, e.schema.Columns, e.columns, e.ctx, req)
+	if e.countOnly {
+		return nil
+	}
+	err = table.FillVirtualColumnValue(e.virtualColumnRetFieldTypes, e.virtualColumnIndex, e.schema.Columns, e.columns, e.ctx, req)
 	if err != nil {
 		return err
 	}
@@ -271,6 +300,43 @@ func (e *TableReaderExecutor) Next(ctx context.Context, req *chunk.Chunk) error
 	return nil
 }
 
+// nextPrunedChunk reads the columns in outputOffsets into req, the other columns are filled with NULL to keep the
+// rows of the chunk aligned.
+func (e *TableReaderExecutor) nextPrunedChunk(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	prunedChk := req.Prune(e.outputOffsets)
+	if err := e.resultHandler.nextChunk(ctx, prunedChk); err != nil {
+		return err
+	}
+	// The decoder may swap the columns of prunedChk instead of appending to them.
+	for j, offset := range e.outputOffsets {
+		req.SetCol(offset, prunedChk.Column(j))
+	}
+	for i, j := 0, 0; i < req.NumCols(); i++ {
+		if j < len(e.outputOffsets) && e.outputOffsets[j] == i {
+			j++
+			continue
+		}
+		col := req.Column(i)
+		for k := 0; k < prunedChk.NumRows(); k++ {
+			col.AppendNull()
+		}
+	}
+	return nil
+}
+
+// resultFieldTypes returns the field types of the columns returned by the coprocessor.
+func (e *TableReaderExecutor) resultFieldTypes() []*types.FieldType {
+	if e.outputOffsets == nil {
+		return retTypes(e)
+	}
+	fieldTypes := make([]*types.FieldType, 0, len(e.outputOffsets))
+	for _, offset := range e.outputOffsets {
+		fieldTypes = append(fieldTypes, e.retFieldTypes[offset])
+	}
+	return fieldTypes
+}
+
 // Close implements the Executor Close interface.
 func (e *TableReaderExecutor) Close() error {
 	var err error
@@ -278,6 +344,10 @@ func (e *TableReaderExecutor) Close() error {
 		err = e.resultHandler.Close()
 	}
 	e.kvRanges = e.kvRanges[:0]
+	if e.handlesMemTracker != nil {
+		e.handlesMemTracker.Detach()
+		e.handlesMemTracker = nil
+	}
 	if e.dummy {
 		return nil
 	}
@@ -297,7 +367,7 @@ func (e *TableReaderExecutor) buildResp(ctx context.Context, ranges []*ranger.Ra
 			}
 			var results []distsql.SelectResult
 			for _, kvReq := range kvReqs {
-				result, err := e.SelectResult(ctx, e.ctx, kvReq, retTypes(e), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
+				result, err := e.SelectResult(ctx, e.ctx, kvReq, e.resultFieldTypes(), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
 				if err != nil {
 					return nil, err
 				}
@@ -310,7 +380,7 @@ func (e *TableReaderExecutor) buildResp(ctx context.Context, ranges []*ranger.Ra
 		if err != nil {
 			return nil, err
 		}
-		result, err := e.SelectResult(ctx, e.ctx, kvReq, retTypes(e), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
+		result, err := e.SelectResult(ctx, e.ctx, kvReq, e.resultFieldTypes(), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
 		if err != nil {
 			return nil, err
 		}
@@ -325,7 +395,7 @@ func (e *TableReaderExecutor) buildResp(ctx context.Context, ranges []*ranger.Ra
 		}
 		var results []distsql.SelectResult
 		for _, kvReq := range kvReqs {
-			result, err := e.SelectResult(ctx, e.ctx, kvReq, retTypes(e), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
+			result, err := e.SelectResult(ctx, e.ctx, kvReq, e.resultFieldTypes(), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
 			if err != nil {
 				return nil, err
 			}
@@ -346,7 +416,7 @@ func (e *TableReaderExecutor) buildResp(ctx context.Context, ranges []*ranger.Ra
 	})
 	e.kvRanges = kvReq.KeyRanges.AppendSelfTo(e.kvRanges)
 
-	result, err := e.SelectResult(ctx, e.ctx, kvReq, retTypes(e), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
+	result, err := e.SelectResult(ctx, e.ctx, kvReq, e.resultFieldTypes(), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
 	if err != nil {
 		return nil, err
 	}
@@ -374,6 +444,7 @@ func (e *TableReaderExecutor) buildKVReqSeparately(ctx context.Context, ranges [
 			SetTxnScope(e.txnScope).
 			SetReadReplicaScope(e.readReplicaScope).
 			SetFromSessionVars(e.ctx.GetSessionVars()).
+			SetMaxConcurrency(e.ctx.GetSessionVars().CopMaxConcurrentTasks).
 			SetFromInfoSchema(e.ctx.GetInfoSchema()).
 			SetMemTracker(e.memTracker).
 			SetStoreType(e.storeType).
@@ -416,6 +487,7 @@ func (e *TableReaderExecutor) buildKVReqForPartitionTableScan(ctx context.Contex
 		SetTxnScope(e.txnScope).
 		SetReadReplicaScope(e.readReplicaScope).
 		SetFromSessionVars(e.ctx.GetSessionVars()).
+		SetMaxConcurrency(e.ctx.GetSessionVars().CopMaxConcurrentTasks).
 		SetFromInfoSchema(e.ctx.GetInfoSchema()).
 		SetMemTracker(e.memTracker).
 		SetStoreType(e.storeType).
@@ -433,6 +505,7 @@ func (e *TableReaderExecutor) buildKVReqForPartitionTableScan(ctx context.Contex
 func (e *TableReaderExecutor) buildKVReq(ctx context.Context, ranges []*ranger.Range) (*kv.Request, error) {
 	var builder distsql.RequestBuilder
 	var reqBuilder *distsql.RequestBuilder
+	var rangesPerTask int
 	if e.kvRangeBuilder != nil {
 		kvRange, err := e.kvRangeBuilder.buildKeyRange(ranges)
 		if err != nil {
@@ -440,6 +513,16 @@ func (e *TableReaderExecutor) buildKVReq(ctx context.Context, ranges []*ranger.R
 		}
 		reqBuilder = builder.SetPartitionKeyRanges(kvRange)
 	} else {
+		if e.splitLargeRegions {
+			subRanges, err := e.splitRangeInLargeRegion(ranges)
+			if err != nil {
+				return nil, err
+			}
+			if subRanges != nil {
+				// Send each sub-range by its own cop task.
+				ranges, rangesPerTask = subRanges, 1
+			}
+		}
 		reqBuilder = builder.SetHandleRanges(e.ctx.GetSessionVars().StmtCtx, getPhysicalTableID(e.table), e.table.Meta() != nil && e.table.Meta().IsCommonHandle, ranges, e.feedback)
 	}
 	if e.table != nil && e.table.Type().IsClusterTable() {
@@ -466,16 +549,90 @@ func (e *TableReaderExecutor) buildKVReq(ctx context.Context, ranges []*ranger.R
 		SetReadReplicaScope(e.readReplicaScope).
 		SetIsStaleness(e.isStaleness).
 		SetFromSessionVars(e.ctx.GetSessionVars()).
+		SetMaxConcurrency(e.ctx.GetSessionVars().CopMaxConcurrentTasks).
 		SetFromInfoSchema(sessiontxn.GetTxnManager(e.ctx).GetTxnInfoSchema()).
 		SetMemTracker(e.memTracker).
 		SetStoreType(e.storeType).
 		SetAllowBatchCop(e.batchCop).
 		SetClosestReplicaReadAdjuster(newClosestReadAdjuster(e.ctx, &reqBuilder.Request, e.netDataSize)).
 		SetPaging(e.paging).
-		SetConnID(e.ctx.GetSessionVars().ConnectionID)
+		SetConnID(e.ctx.GetSessionVars().ConnectionID).
+		SetRangesPerTask(rangesPerTask)
 	return reqBuilder.Build()
 }
 
+// splitRangeInLargeRegion splits the handle range located in a single region into at most DistSQLScanConcurrency
+// sub-ranges by the bucket bounds of the handle histogram, so that each sub-range covers roughly the same number
+// of rows. It returns nil if the range is not split.
+func (e *TableReaderExecutor) splitRangeInLargeRegion(ranges []*ranger.Range) ([]*ranger.Range, error) {
+	n := e.ctx.GetSessionVars().DistSQLScanConcurrency()
+	hg := e.handleHist
+	if e.dummy || len(ranges) != 1 || n <= 1 || hg == nil || hg.Len() == 0 ||
+		(e.feedback != nil && e.feedback.Valid.Load()) || ranges[0].IsPoint(e.ctx) {
+		return nil, nil
+	}
+	sc := e.ctx.GetSessionVars().StmtCtx
+	kvRanges, err := distsql.TableHandleRangesToKVRanges(sc, []int64{getPhysicalTableID(e.table)}, false, ranges, nil)
+	if err != nil {
+		return nil, err
+	}
+	kvRange := kvRanges.FirstPartitionRange()[0]
+	regionRanges, err := splitIntoMultiRanges(e.ctx.GetStore(), kvRange.StartKey, kvRange.EndKey)
+	if err != nil {
+		return nil, err
+	}
+	// The ranges across multiple regions are already sent by multiple cop tasks.
+	if len(regionRanges) != 1 {
+		return nil, nil
+	}
+
+	ran := ranges[0]
+	totalCount := hg.Buckets[hg.Len()-1].Count
+	points := make([]types.Datum, 0, n-1)
+	for i, k := 0, int64(1); i < hg.Len() && k < int64(n); i++ {
+		if hg.Buckets[i].Count < totalCount*k/int64(n) {
+			continue
+		}
+		for k < int64(n) && hg.Buckets[i].Count >= totalCount*k/int64(n) {
+			k++
+		}
+		upper := hg.GetUpper(i)
+		cmpLow, err := upper.Compare(sc, &ran.LowVal[0], collate.GetBinaryCollator())
+		if err != nil {
+			return nil, err
+		}
+		cmpHigh, err := upper.Compare(sc, &ran.HighVal[0], collate.GetBinaryCollator())
+		if err != nil {
+			return nil, err
+		}
+		if cmpLow > 0 && cmpHigh < 0 {
+			points = append(points, *upper)
+		}
+	}
+	if len(points) == 0 {
+		return nil, nil
+	}
+	subRanges := make([]*ranger.Range, 0, len(points)+1)
+	lowVal, lowExclude := ran.LowVal, ran.LowExclude
+	for _, point := range points {
+		subRanges = append(subRanges, &ranger.Range{
+			LowVal:     lowVal,
+			LowExclude: lowExclude,
+			HighVal:    []types.Datum{point},
+			Collators:  ran.Collators,
+		})
+		lowVal, lowExclude = []types.Datum{point}, true
+	}
+	subRanges = append(subRanges, &ranger.Range{
+		LowVal:      lowVal,
+		LowExclude:  lowExclude,
+		HighVal:     ran.HighVal,
+		HighExclude: ran.HighExclude,
+		Collators:   ran.Collators,
+	})
+	return subRanges, nil
+}
+
 func buildVirtualColumnIndex(schema *expression.Schema, columns []*model.ColumnInfo) []int {
 	virtualColumnIndex := make([]int, 0, len(columns))
 	for i, col := range schema.Columns {
@@ -490,6 +647,46 @@ func buildVirtualColumnIndex(schema *expression.Schema, columns []*model.ColumnI
 	return virtualColumnIndex
 }
 
+// isCountOnlyDAG checks whether the root executor of the DAG request is an aggregation without group by,
+// whose aggregate functions are all COUNT(*) or COUNT(constant). The response of such a request only
+// carries the partial counts and none of the table columns.
+func isCountOnlyDAG(dagReq *tipb.DAGRequest, schema *expression.Schema) bool {
+	// The executors sent to TiFlash are tree-based, and the ones sent to TiKV are list-based.
+	exec := dagReq.RootExecutor
+	if exec == nil {
+		if len(dagReq.Executors) == 0 {
+			return false
+		}
+		exec = dagReq.Executors[len(dagReq.Executors)-1]
+	}
+	if exec.Tp != tipb.ExecType_TypeAggregation && exec.Tp != tipb.ExecType_TypeStreamAgg {
+		return false
+	}
+	agg := exec.Aggregation
+	if agg == nil || len(agg.GroupBy) > 0 || len(agg.AggFunc) == 0 || len(agg.AggFunc) != schema.Len() {
+		return false
+	}
+	for _, aggFunc := range agg.AggFunc {
+		if aggFunc.Tp != tipb.ExprType_Count || aggFunc.HasDistinct || len(aggFunc.Children) != 1 || !isConstantPBExpr(aggFunc.Children[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isConstantPBExpr checks whether the expression refers to no column.
+func isConstantPBExpr(expr *tipb.Expr) bool {
+	if expr.Tp == tipb.ExprType_ColumnRef {
+		return false
+	}
+	for _, child := range expr.Children {
+		if !isConstantPBExpr(child) {
+			return false
+		}
+	}
+	return true
+}
+
 // buildVirtualColumnInfo saves virtual column indices and sort them in definition order
 func (e *TableReaderExecutor) buildVirtualColumnInfo() {
 	e.virtualColumnIndex, e.virtualColumnRetFieldTypes = buildVirtualColumnInfo(e.Schema(), e.columns)
@@ -568,3 +765,61 @@ func (tr *tableResultHandler) Close() error {
 	tr.optionalResult, tr.result = nil, nil
 	return err
 }
+
+// adaptiveChunkTargetBytes is the target memory usage of a chunk read by the
+// table and index readers.
+const adaptiveChunkTargetBytes = 8 * 1024 * 1024
+
+// adaptiveChunkSize returns how many rows of avgRowSize bytes fit in
+// adaptiveChunkTargetBytes, bounded by [1, maxSize].
+func adaptiveChunkSize(avgRowSize float64, maxSize int) int {
+	if avgRowSize <= 0 {
+		return maxSize
+	}
+	size := adaptiveChunkTargetBytes / avgRowSize
+	if size >= float64(maxSize) {
+		return maxSize
+	}
+	return mathutil.Max(int(size), 1)
+}
+
+// chunkSizeAdjuster limits the rows read into a chunk by the readers, so the
+// memory of a batch keeps roughly constant no matter how wide the rows are.
+// The initial size is estimated by the planner and is adjusted by the row
+// width observed in the chunks read.
+type chunkSizeAdjuster struct {
+	size    int
+	maxSize int
+}
+
+// newChunkSizeAdjuster creates a chunkSizeAdjuster from the estimated net data
+// size and row count of the reader.
+func newChunkSizeAdjuster(netDataSize, rowCount float64, maxSize int) *chunkSizeAdjuster {
+	a := &chunkSizeAdjuster{size: maxSize, maxSize: maxSize}
+	if rowCount >= 1 {
+		a.size = adaptiveChunkSize(netDataSize/rowCount, maxSize)
+	}
+	return a
+}
+
+// limit shrinks the required rows of chk to the adaptive chunk size.
+func (a *chunkSizeAdjuster) limit(chk *chunk.Chunk) {
+	if a == nil || a.size >= chk.RequiredRows() {
+		return
+	}
+	chk.SetRequiredRows(a.size, a.maxSize)
+}
+
+// adjust restores the required rows of chk and updates the adaptive chunk size
+// with the row width observed in chk.
+func (a *chunkSizeAdjuster) adjust(chk *chunk.Chunk, requiredRows int) {
+	if a == nil {
+		return
+	}
+	chk.SetRequiredRows(requiredRows, requiredRows)
+	if chk.NumRows() == 0 {
+		return
+	}
+	// The buffers of chk may be reused from the wider rows, so only the data held by the rows is counted.
+	a.size = adaptiveChunkSize(float64(chk.DataSize())/float64(chk.NumRows()), a.maxSize)
+}
@@ -18,8 +18,10 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -721,6 +723,43 @@ func TestAnalyzeIndex(t *testing.T) {
 	}()
 }
 
+func TestAnalyzeNamedIndexes(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t2")
+	tk.MustExec("create table t (a int, b int, c int, index idx_a(a), index idx_b(b), index idx_c(c))")
+	tk.MustExec("create table t2 like t")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 2), (3, 3, 3)")
+	tk.MustExec("insert into t2 select * from t")
+	tk.MustExec("set @@tidb_analyze_version=1")
+
+	analyzedStats := func(tbl string) []string {
+		rows := tk.MustQuery(fmt.Sprintf("show stats_histograms where db_name = 'test' and table_name = '%s'", tbl)).Rows()
+		names := make([]string, 0, len(rows))
+		for _, row := range rows {
+			// Column_name and Is_index
+			names = append(names, fmt.Sprintf("%v %v", row[3], row[4]))
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	tk.MustExec("analyze table t index idx_a, idx_c")
+	require.Equal(t, []string{"idx_a 1", "idx_c 1"}, analyzedStats("t"))
+
+	tk.MustExec("analyze table t index idx_b")
+	require.Equal(t, []string{"idx_a 1", "idx_b 1", "idx_c 1"}, analyzedStats("t"))
+
+	tk.MustGetErrCode("analyze table t2 index idx_a, idx_d", errno.ErrAnalyzeMissIndex)
+	require.Empty(t, analyzedStats("t2"))
+
+	// All the indexes are analyzed when no index name is given.
+	tk.MustExec("analyze table t2 index")
+	require.Equal(t, []string{"idx_a 1", "idx_b 1", "idx_c 1"}, analyzedStats("t2"))
+}
+
 func TestAnalyzeIncremental(t *testing.T) {
 	store, dom := testkit.CreateMockStoreAndDomain(t)
 
@@ -3239,3 +3278,216 @@ func TestAnalyzeColumnsSkipMVIndexJsonCol(t *testing.T) {
 	require.True(t, stats.Indices[tblInfo.Indices[0].ID].IsStatsInitialized())
 	require.False(t, stats.Indices[tblInfo.Indices[1].ID].IsStatsInitialized())
 }
+
+func TestAnalyzeSkipFreshStats(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	h := dom.StatsHandle()
+	tk.MustExec("create table t(a int, b int, index idx(b)) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (11, 11), (12, 12)")
+	tk.MustExec("create table t1(a int, b int, index ia(a), index ib(b))")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2)")
+	require.NoError(t, h.DumpStatsDeltaToKV(handle.DumpAll))
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("analyze table t, t1")
+	is := dom.InfoSchema()
+	require.NoError(t, h.Update(is))
+
+	skipNotes := func() []string {
+		var notes []string
+		for _, row := range tk.MustQuery("show warnings").Rows() {
+			if msg := row[2].(string); strings.Contains(msg, "because its stats are fresh") {
+				notes = append(notes, msg)
+			}
+		}
+		return notes
+	}
+
+	// Only the partition modified a lot is analyzed again.
+	tk.MustExec("insert into t values (3, 3), (4, 4), (5, 5)")
+	require.NoError(t, h.DumpStatsDeltaToKV(handle.DumpAll))
+	require.NoError(t, h.Update(is))
+	tk.MustExec("set @@tidb_analyze_skip_fresh_stats_window = '1h'")
+	tk.MustExec("analyze table t")
+	require.Equal(t, []string{"Analyze skips table test.t's partition p1 because its stats are fresh"}, skipNotes())
+	require.NoError(t, h.Update(is))
+	tk.MustQuery("show stats_meta where table_name = 't' and partition_name = 'p0'").CheckAt([]int{5}, testkit.Rows("5"))
+
+	// All the partitions are fresh now.
+	tk.MustExec("analyze table t")
+	require.Equal(t, []string{
+		"Analyze skips table test.t's partition p0 because its stats are fresh",
+		"Analyze skips table test.t's partition p1 because its stats are fresh",
+	}, skipNotes())
+
+	// The columns which are never analyzed are not fresh.
+	tk.MustExec("alter table t1 add column c int")
+	tk.MustExec("analyze table t1 all columns")
+	require.Empty(t, skipNotes())
+	require.NoError(t, h.Update(dom.InfoSchema()))
+	tk.MustExec("analyze table t1 all columns")
+	require.Equal(t, []string{"Analyze skips table test.t1 because its stats are fresh"}, skipNotes())
+
+	// The stats out of the window are not fresh.
+	tk.MustExec("set @@tidb_analyze_skip_fresh_stats_window = '1ns'")
+	tk.MustExec("analyze table t")
+	require.Empty(t, skipNotes())
+	tk.MustExec("set @@tidb_analyze_skip_fresh_stats_window = default")
+	tk.MustExec("analyze table t")
+	require.Empty(t, skipNotes())
+}
+
+func TestKillFastAnalyze(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b int, index idx(b))")
+	tk.MustExec("split table t between (0) and (100) regions 20")
+	for i := 0; i < 100; i += 5 {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d)", i, i))
+	}
+	tk.MustExec("set @@tidb_enable_fast_analyze = 1")
+	tk.MustExec("set @@tidb_analyze_version = 1")
+	tk.MustExec("set @@tidb_build_stats_concurrency = 1")
+
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/executor/mockSlowFastAnalyzeSampleTask", "return(200)"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/executor/mockSlowFastAnalyzeSampleTask"))
+	}()
+	for _, sql := range []string{"analyze table t", "analyze table t index idx"} {
+		sessVars := tk.Session().GetSessionVars()
+		timer := time.AfterFunc(500*time.Millisecond, func() {
+			atomic.StoreUint32(&sessVars.Killed, 1)
+		})
+		start := time.Now()
+		err := tk.ExecToErr(sql)
+		timer.Stop()
+		atomic.StoreUint32(&sessVars.Killed, 0)
+		require.True(t, exeerrors.ErrQueryInterrupted.Equal(err), "%v", err)
+		// Sampling all the 20 regions takes 4 seconds, the analyze should be interrupted before it.
+		require.Less(t, time.Since(start), 2*time.Second)
+	}
+
+	// No histogram is written by the interrupted analyze.
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	require.NoError(t, dom.StatsHandle().Update(dom.InfoSchema()))
+	require.True(t, dom.StatsHandle().GetTableStats(tbl.Meta()).Pseudo)
+	tk.MustQuery("show stats_histograms where table_name = 't'").Check(testkit.Rows())
+}
+
+func TestAnalyzeDumpSamples(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("create table t (a int primary key, b varchar(20), c int as (a + 1), key idx_b(b))")
+	tk.MustExec("insert into t (a, b) values (1, 'x'), (2, 'y'), (3, null)")
+	tk.MustExec("create table samples (a int, b varchar(20), c int)")
+
+	// The samples are not dumped by default.
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select count(*) from samples").Check(testkit.Rows("0"))
+	buckets := tk.MustQuery("show stats_buckets where table_name = 't'").Rows()
+
+	tk.MustExec("set @@tidb_analyze_dump_samples = 'samples'")
+	tk.MustExec("analyze table t")
+	// The generated column is not dumped.
+	tk.MustQuery("select a, b, c from samples order by a").Check(testkit.Rows("1 x <nil>", "2 y <nil>", "3 <nil> <nil>"))
+	// The stats are not affected by the dump.
+	tk.MustQuery("show stats_buckets where table_name = 't'").Check(buckets)
+
+	tk.MustExec("truncate table samples")
+	tk.MustExec("set @@tidb_analyze_dump_samples = 'test.samples'")
+	tk.MustExec("analyze table t with 2 samples")
+	tk.MustQuery("select count(*) from samples").Check(testkit.Rows("2"))
+
+	// Failing to dump the samples doesn't fail the analyze.
+	tk.MustExec("create table bad_samples (a int)")
+	tk.MustExec("set @@tidb_analyze_dump_samples = 'bad_samples'")
+	tk.MustExec("analyze table t")
+	require.Contains(t, fmt.Sprint(tk.MustQuery("show warnings").Rows()), "Unknown column 'b'")
+	tk.MustQuery("select count(*) from bad_samples").Check(testkit.Rows("0"))
+
+	tk.MustExec("set @@tidb_analyze_dump_samples = 'not_exist'")
+	tk.MustGetErrCode("analyze table t", errno.ErrNoSuchTable)
+}
+
+func TestAnalyzeHistogramOnly(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, index ia(a))")
+	tk.MustExec("insert into t values (1, 1), (1, 1), (1, 1), (1, 1), (2, 2), (3, 3), (4, 4), (5, 5)")
+	tk.MustExec("set @@tidb_analyze_version = 1")
+	tk.MustGetErrMsg("analyze table t histogram only", "Only the analyze version 2 supports analyzing the histogram only")
+
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("analyze table t with 1 topn, 4 buckets")
+	topn := tk.MustQuery("show stats_topn where table_name = 't'").Sort().Rows()
+	require.Len(t, topn, 3)
+
+	// The TopN is kept while the histograms are rebuilt from the new data.
+	tk.MustExec("delete from t where a = 1")
+	tk.MustExec("insert into t values (6, 6), (7, 7), (8, 8), (8, 8), (8, 8), (8, 8), (8, 8)")
+	tk.MustExec("analyze table t histogram only with 4 buckets")
+	tk.MustQuery("show stats_topn where table_name = 't'").Sort().Check(topn)
+	tk.MustQuery("select max(cast(upper_bound as signed)) from mysql.stats_buckets where is_index = 0 and hist_id = 1 and " +
+		"table_id = (select tidb_table_id from information_schema.tables where table_schema = 'test' and table_name = 't')").Check(testkit.Rows("8"))
+
+	// The TopN is rebuilt by the normal analyze.
+	tk.MustExec("analyze table t with 1 topn, 4 buckets")
+	require.NotEqual(t, topn, tk.MustQuery("show stats_topn where table_name = 't'").Sort().Rows())
+}
+
+func TestAnalyzeDryRun(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	statsHandle := domain.GetDomain(tk.Session().(sessionctx.Context)).StatsHandle()
+	tk.MustExec("create table t(a int, b varchar(10), index ia(a))")
+	require.NoError(t, statsHandle.HandleDDLEvent(<-statsHandle.DDLEventCh()))
+	is := tk.Session().(sessionctx.Context).GetInfoSchema().(infoschema.InfoSchema)
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tk.MustExec(fmt.Sprintf("update mysql.stats_meta set count = 220000 where table_id=%d", tbl.Meta().ID))
+	require.NoError(t, statsHandle.Update(is))
+
+	// The dry run reports the sampling plan without collecting any statistics.
+	result := tk.MustQuery("analyze table t dry run")
+	result.Check(testkit.Rows(
+		"test t  analyze table all columns with 256 buckets, 500 topn, 0.5 samplerate 0.5 110000 <nil> 2860000"))
+	plan := result.Rows()
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1105 Analyze use auto adjusted sample rate 0.500000 for table test.t"))
+	tk.MustQuery("select count(*) from mysql.analyze_jobs").Check(testkit.Rows("0"))
+	tk.MustQuery("select count from mysql.stats_meta where table_id = ?", tbl.Meta().ID).Check(testkit.Rows("220000"))
+
+	// The real analyze uses the same sampling plan.
+	tk.MustExec("analyze table t")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1105 Analyze use auto adjusted sample rate 0.500000 for table test.t"))
+	tk.MustQuery("select job_info from mysql.analyze_jobs").Check(testkit.Rows(plan[0][3].(string)))
+
+	tk.MustQuery("analyze table t with 100 samples dry run").Check(testkit.Rows(
+		"test t  analyze table all columns with 256 buckets, 500 topn, 100 samples <nil> 100 <nil> 2600"))
+
+	tk.MustExec("create table pt(a int) partition by hash(a) partitions 2")
+	tk.MustExec("insert into pt values (1), (2), (3)")
+	plan = tk.MustQuery("analyze table pt partition p1 dry run").Rows()
+	require.Len(t, plan, 1)
+	require.Equal(t, "p1", plan[0][2])
+	tk.MustExec("analyze table pt partition p1")
+	tk.MustQuery("select job_info from mysql.analyze_jobs where partition_name = 'p1'").Check(testkit.Rows(plan[0][3].(string)))
+	tk.MustQuery("analyze table pt with 0.1 samplerate dry run").Check(testkit.Rows(
+		"test pt p0 analyze table all columns with 256 buckets, 500 topn, 0.1 samplerate 0.1 0 <nil> 0",
+		"test pt p1 analyze table all columns with 256 buckets, 500 topn, 0.1 samplerate 0.1 1 <nil> 16"))
+
+	// The fast analyze reports the estimated sample step.
+	jobs := tk.MustQuery("select count(*) from mysql.analyze_jobs").Rows()
+	tk.MustExec("set @@tidb_analyze_version = 1")
+	tk.MustExec("set @@tidb_enable_fast_analyze = 1")
+	tk.MustQuery("analyze table pt partition p1 with 1 samples dry run").Check(testkit.Rows(
+		"test pt p1 fast analyze columns <nil> 1 2 8"))
+	tk.MustQuery("select count(*) from mysql.analyze_jobs").Check(jobs)
+}
@@ -2865,3 +2865,31 @@ func TestForeignKeyAndSessionVariable(t *testing.T) {
 	tk.MustQuery("select * from t1").Check(testkit.Rows())
 	tk.MustQuery("select * from t2").Check(testkit.Rows())
 }
+
+func TestAdminCheckTableForeignKey(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set @@foreign_key_checks=1")
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (id int key, b int, index(b))")
+	tk.MustExec("create table t2 (id int key, a int, b int, constraint fk_a foreign key (a) references t1(id), constraint fk_b foreign key (b) references t1(b))")
+	tk.MustExec("insert into t1 values (1, 10), (2, 20)")
+	tk.MustExec("insert into t2 values (1, 1, 10), (2, 2, null), (3, null, 20)")
+	tk.MustExec("set @@tidb_admin_check_foreign_key=1")
+	tk.MustExec("admin check table t2")
+
+	// Introduce the orphaned rows.
+	tk.MustExec("set @@foreign_key_checks=0")
+	tk.MustExec("insert into t2 values (4, 3, null)")
+	tk.MustExec("set @@foreign_key_checks=1")
+	tk.MustGetErrMsg("admin check table t2", "[executor:8176]Foreign key fk_a is inconsistent, the child row with handle 4 and values 3 has no parent row")
+	tk.MustExec("delete from t2 where id = 4")
+	tk.MustExec("set @@foreign_key_checks=0")
+	tk.MustExec("insert into t2 values (5, 1, 30)")
+	tk.MustExec("set @@foreign_key_checks=1")
+	tk.MustGetErrMsg("admin check table t2", "[executor:8176]Foreign key fk_b is inconsistent, the child row with handle 5 and values 30 has no parent row")
+
+	// The foreign keys aren't checked by default.
+	tk.MustExec("set @@tidb_admin_check_foreign_key=0")
+	tk.MustExec("admin check table t2")
+}
@@ -1132,3 +1132,129 @@ func TestOrderByWithLimit(t *testing.T) {
 		}
 	}
 }
+
+func TestOrderByWithLimitAcrossBatches(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec("drop table if exists tpk, tpkhash")
+	tk.MustExec("create table tpk(a int, b int, c int, d int auto_increment, primary key(d), index idx_ac(a, c), index idx_bc(b, c))")
+	tk.MustExec("create table tpkhash(a int, b int, c int, d int auto_increment, primary key(d), index idx_ac(a, c), index idx_bc(b, c)) PARTITION BY HASH (`d`) PARTITIONS 4")
+	tk.MustExec("analyze table tpk")
+	tk.MustExec("analyze table tpkhash")
+
+	// Few distinct values, so many rows are returned by both partial plans and the order by column has many ties.
+	valueSlice := make([]*valueStruct, 0, 2000)
+	vals := make([]string, 0, 2000)
+	for i := 0; i < 2000; i++ {
+		a := rand.Intn(4)
+		b := rand.Intn(4)
+		c := rand.Intn(64)
+		vals = append(vals, fmt.Sprintf("(%v, %v, %v)", a, b, c))
+		valueSlice = append(valueSlice, &valueStruct{a, b, c})
+	}
+	valInserted := strings.Join(vals, ",")
+	tk.MustExec(fmt.Sprintf("insert into tpk(a,b,c) values %s", valInserted))
+	tk.MustExec(fmt.Sprintf("insert into tpkhash(a,b,c) values %s", valInserted))
+
+	// Small batches make the ordered merge output the handles in many table tasks.
+	tk.MustExec("set tidb_index_lookup_size = 8")
+	tk.MustExec("set tidb_max_chunk_size = 32")
+	tk.MustExec("set tidb_partition_prune_mode = `dynamic-only`")
+	for i := 0; i < 20; i++ {
+		a := rand.Intn(4)
+		b := rand.Intn(4)
+		limit := rand.Intn(1000) + 1
+		for _, desc := range []bool{false, true} {
+			order := "c"
+			if desc {
+				order = "c desc"
+			}
+			for _, tbl := range []string{"tpk", "tpkhash"} {
+				query := fmt.Sprintf("select /*+ use_index_merge(%s, idx_ac, idx_bc) */ c, d from %s where a = %v or b = %v order by %s limit %v", tbl, tbl, a, b, order, limit)
+				require.True(t, tk.HasPlan(query, "IndexMerge"))
+				require.False(t, tk.HasPlan(query, "TopN"))
+				res := tk.MustQuery(query).Rows()
+
+				sliceRes := getResult(valueSlice, a, b, limit, desc)
+				require.Equal(t, len(sliceRes), len(res), query)
+				handles := make(map[string]struct{}, len(res))
+				for j := range sliceRes {
+					require.Equal(t, fmt.Sprintf("%v", sliceRes[j].c), res[j][0], query)
+					handles[res[j][1].(string)] = struct{}{}
+				}
+				// The same handle returned by both partial plans is only output once.
+				require.Len(t, handles, len(res), query)
+			}
+		}
+	}
+}
+
+func TestOrderByWithLimitPartialError(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int, d int auto_increment, primary key(d), index idx_ac(a, c), index idx_bc(b, c))")
+	tk.MustExec("insert into t(a, b, c) values (1, 1, 1), (1, 2, 2), (2, 1, 3), (2, 2, 4)")
+	query := "select /*+ use_index_merge(t, idx_ac, idx_bc) */ * from t where a = 1 or b = 1 order by c limit 10"
+	require.True(t, tk.HasPlan(query, "IndexMerge"))
+	tk.MustQuery(query).Check(testkit.Rows("1 1 1 1", "1 2 2 2", "2 1 3 3"))
+
+	fp := "github.com/pingcap/tidb/executor/testIndexMergeErrorPartialIndexWorker"
+	require.NoError(t, failpoint.Enable(fp, fmt.Sprintf(`return("%s")`, fp)))
+	defer func() {
+		require.NoError(t, failpoint.Disable(fp))
+	}()
+	for i := 0; i < 10; i++ {
+		err := tk.QueryToErr(query)
+		require.ErrorContains(t, err, fp)
+	}
+}
+
+func TestUnionWithLimit(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int, d int auto_increment, primary key(d), index idx_a(a), index idx_b(b))")
+	vals := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		vals = append(vals, fmt.Sprintf("(%v, %v, %v)", i%100, rand.Intn(100), i))
+	}
+	tk.MustExec("insert into t(a, b, c) values " + strings.Join(vals, ","))
+	tk.MustExec("analyze table t")
+
+	for _, limit := range []string{"1", "3", "2, 5", "200"} {
+		query := fmt.Sprintf("select /*+ use_index_merge(t, idx_a, idx_b) */ a, b, d from t where a < 90 or b < 50 limit %s", limit)
+		rows := tk.MustQuery("explain " + query).Rows()
+		require.Contains(t, fmt.Sprintf("%v", rows), "type: union, limit embedded(offset:0, count:")
+
+		res := tk.MustQuery(query).Rows()
+		count, offset := 0, 0
+		if parts := strings.Split(limit, ", "); len(parts) == 2 {
+			offset, _ = strconv.Atoi(parts[0])
+			count, _ = strconv.Atoi(parts[1])
+		} else {
+			count, _ = strconv.Atoi(parts[0])
+		}
+		require.Len(t, res, count, query)
+		// The handles at the stop boundary are deduplicated exactly.
+		handles := make(map[string]struct{}, len(res))
+		for _, r := range res {
+			a, _ := strconv.Atoi(r[0].(string))
+			b, _ := strconv.Atoi(r[1].(string))
+			require.True(t, a < 90 || b < 50, query)
+			handles[r[2].(string)] = struct{}{}
+		}
+		require.Len(t, handles, len(res), query)
+
+		// Both partial plans return Offset+Count handles, but the table side only looks up the required ones.
+		for _, r := range tk.MustQuery("explain analyze " + query).Rows() {
+			if strings.Contains(r[0].(string), "TableRowIDScan") {
+				require.Equal(t, strconv.Itoa(offset+count), r[2], query)
+			}
+		}
+	}
+}
@@ -1611,6 +1611,44 @@ func TestHashJoin(t *testing.T) {
 	require.Equal(t, "5", outerActRows)
 }
 
+func TestHashJoinRadix(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+	tk.MustExec("insert into t1 values(1,1),(2,2),(3,3),(4,4),(5,5),(null,6),(7,7),(7,8)")
+	tk.MustExec("insert into t2 values(1,1),(1,2),(3,3),(5,5),(null,5),(7,7),(9,9)")
+	for i := 0; i < 5; i++ {
+		tk.MustExec("insert into t1 select a+10, b+10 from t1")
+		tk.MustExec("insert into t2 select a+10, b+10 from t2")
+	}
+	tk.MustExec("set @@tidb_max_chunk_size=32")
+
+	sqls := []string{
+		"select /*+ hash_join(t1, t2) */ * from t1 join t2 on t1.a = t2.a",
+		"select /*+ hash_join(t1, t2) */ * from t1 join t2 on t1.a = t2.a and t1.b < t2.b",
+		"select /*+ hash_join(t1, t2) */ * from t1 left join t2 on t1.a = t2.a",
+		"select /*+ hash_join(t1, t2) */ * from t1 right join t2 on t1.a = t2.a",
+		"select /*+ hash_join_build(t1) */ * from t1 left join t2 on t1.a = t2.a",
+		"select /*+ hash_join_build(t2) */ * from t1 right join t2 on t1.a = t2.a",
+		"select * from t1 where exists (select /*+ hash_join(t1, t2) */ 1 from t2 where t1.a = t2.a)",
+		"select * from t1 where not exists (select /*+ hash_join(t1, t2) */ 1 from t2 where t1.a = t2.a)",
+		"select * from t1 where t1.a in (select /*+ hash_join(t1, t2) */ t2.a from t2)",
+		"select * from t1 where t1.a not in (select /*+ hash_join(t1, t2) */ t2.a from t2 where t2.a is not null)",
+		"select t1.a, t1.a in (select /*+ hash_join(t1, t2) */ t2.a from t2) from t1",
+	}
+	for _, sql := range sqls {
+		tk.MustExec("set @@tidb_hash_join_radix_bits=0")
+		expected := tk.MustQuery(sql).Sort().Rows()
+		for _, radixBits := range []int{1, 4, 8} {
+			tk.MustExec(fmt.Sprintf("set @@tidb_hash_join_radix_bits=%d", radixBits))
+			tk.MustQuery(sql).Sort().Check(expected)
+		}
+	}
+}
+
 func TestJoinDifferentDecimals(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -2829,3 +2867,208 @@ func TestOuterJoin(t *testing.T) {
 		),
 	)
 }
+
+func TestWarnOnSynthJoinDefaults(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1(a int, b int, key(a))")
+	tk.MustExec("create table t2(a int, b int, key(a))")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2)")
+	tk.MustExec("insert into t2 values (1, 1)")
+
+	// off by default.
+	tk.MustQuery("select /*+ hash_join(t1, t2) */ t1.a, t2.b from t1 left join t2 on t1.a = t2.a order by t1.a").Check(testkit.Rows("1 1", "2 <nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+
+	tk.MustExec("set @@tidb_warn_on_synth_join_defaults = 1")
+	tests := []struct {
+		sql  string
+		warn string
+	}{
+		{
+			"select /*+ hash_join(t1, t2) */ t1.a, t2.b from t1 left join t2 on t1.a = t2.a order by t1.a",
+			"the default values of the right side of left outer join are synthesized for hash join",
+		},
+		{
+			"select /*+ merge_join(t1, t2) */ t1.a, t2.b from t2 right join t1 on t1.a = t2.a order by t1.a",
+			"the default values of the left side of right outer join are synthesized for merge join",
+		},
+		{
+			"select /*+ inl_join(t2) */ t1.a, t2.b from t1 left join t2 on t1.a = t2.a order by t1.a",
+			"the default values of the right side of left outer join are synthesized for index join",
+		},
+		{
+			"select /*+ inl_merge_join(t2) */ t1.a, t2.b from t1 left join t2 on t1.a = t2.a order by t1.a",
+			"the default values of the right side of left outer join are synthesized for index merge join",
+		},
+		{
+			"select t1.a, (select t2.b from t2 where t2.a = t1.a and t2.b > t1.b - 1 limit 1) from t1 order by t1.a",
+			"the default values of the right side of left outer join are synthesized for apply",
+		},
+	}
+	for _, tt := range tests {
+		tk.MustQuery(tt.sql).Check(testkit.Rows("1 1", "2 <nil>"))
+		tk.MustQuery("show warnings").CheckContain(tt.warn)
+	}
+
+	// the inner joins don't output the default values.
+	tk.MustQuery("select /*+ hash_join(t1, t2) */ t1.a, t2.b from t1 join t2 on t1.a = t2.a").Check(testkit.Rows("1 1"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+}
+
+func TestBandJoin(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(id int, t int, s varchar(10), lo int, hi int)")
+	tk.MustExec("create table t2(id int, t int, s varchar(10), lo int, hi int, slo varchar(10), shi varchar(10))")
+	nullOrInt := func(v int) string {
+		if v%7 == 0 {
+			return "null"
+		}
+		return fmt.Sprintf("%d", v%50)
+	}
+	for i := 0; i < 200; i++ {
+		lo := rand.Intn(50)
+		tk.MustExec(fmt.Sprintf("insert into t1 values(%d, %s, '%c', %s, %d)", i, nullOrInt(rand.Intn(60)), 'a'+rand.Intn(26), nullOrInt(lo), lo+rand.Intn(5)))
+		lo = rand.Intn(50)
+		tk.MustExec(fmt.Sprintf("insert into t2 values(%d, %s, '%c', %s, %s, '%c', '%c')", i, nullOrInt(rand.Intn(60)), 'a'+rand.Intn(26), nullOrInt(lo), nullOrInt(lo+rand.Intn(5)), 'a'+rand.Intn(13), 'm'+rand.Intn(13)))
+	}
+	// Make the results span several chunks.
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	tk.MustExec("set @@tidb_init_chunk_size = 1")
+
+	queries := []string{
+		"select t1.id, t2.id from t1 join t2 on t1.t between t2.lo and t2.hi",
+		"select t1.id, t2.id from t1 join t2 on t1.t > t2.lo and t1.t < t2.hi",
+		"select t1.id, t2.id from t1 join t2 on t2.lo < t1.t and t1.t <= t2.hi",
+		"select t1.id, t2.id from t1 join t2 on t2.t >= t1.lo and t2.t < t1.hi",
+		"select t1.id, t2.id from t1 join t2 on t1.t between t2.lo and t2.hi and t1.lo <= t2.t",
+		"select t1.id, t2.id from t1 join t2 on t1.s between t2.slo and t2.shi",
+		"select t1.id, t2.id from t1 left join t2 on t1.t between t2.lo and t2.hi",
+		"select t1.id, t2.id from t1 left join t2 on t1.t between t2.lo and t2.hi and t1.id > 100",
+		"select t1.id, t2.id from t1 right join t2 on t2.t >= t1.lo and t2.t <= t1.hi",
+		"select t1.id from t1 where exists (select 1 from t2 where t1.t between t2.lo and t2.hi)",
+		"select t1.id from t1 where not exists (select 1 from t2 where t1.t > t2.lo and t1.t < t2.hi)",
+		"select t1.id, exists (select 1 from t2 where t1.t between t2.lo and t2.hi) from t1",
+	}
+	for _, q := range queries {
+		tk.MustExec("set @@tidb_enable_band_join = off")
+		expected := tk.MustQuery(q).Sort().Rows()
+		tk.MustExec("set @@tidb_enable_band_join = on")
+		tk.MustQuery(q).Sort().Check(expected)
+	}
+
+	hashJoinExecInfo := func(q string) string {
+		for _, row := range tk.MustQuery("explain analyze " + q).Rows() {
+			if strings.Contains(row[0].(string), "HashJoin") {
+				return row[5].(string)
+			}
+		}
+		require.FailNow(t, "no hash join in the plan")
+		return ""
+	}
+	hashJoinOperatorInfo := func(q string) string {
+		for _, row := range tk.MustQuery("explain " + q).Rows() {
+			if strings.Contains(row[0].(string), "HashJoin") {
+				return row[4].(string)
+			}
+		}
+		require.FailNow(t, "no hash join in the plan")
+		return ""
+	}
+	q := "select t1.id, t2.id from t1 join t2 on t1.t between t2.lo and t2.hi"
+	tk.MustExec("set @@tidb_enable_band_join = off")
+	require.Contains(t, hashJoinExecInfo(q), "build_hash_table")
+	require.Contains(t, hashJoinOperatorInfo(q), "CARTESIAN inner join")
+	tk.MustExec("set @@tidb_enable_band_join = on")
+	require.NotContains(t, hashJoinExecInfo(q), "build_hash_table")
+	require.Contains(t, hashJoinOperatorInfo(q), "band merge inner join")
+	require.Contains(t, hashJoinOperatorInfo("select t1.id, t2.id from t1 left join t2 on t1.t between t2.lo and t2.hi"), "band merge left outer join")
+	// The join with equal conditions is still executed by the hash join.
+	q = "select t1.id, t2.id from t1 join t2 on t1.id = t2.id and t1.t between t2.lo and t2.hi"
+	require.Contains(t, hashJoinExecInfo(q), "build_hash_table")
+	require.NotContains(t, hashJoinOperatorInfo(q), "band merge")
+	// The join conditions which aren't ranges between the columns are not executed by the band merge join.
+	q = "select t1.id, t2.id from t1 join t2 on t1.t between t2.lo and t2.hi + 1"
+	require.Contains(t, hashJoinExecInfo(q), "build_hash_table")
+	require.Contains(t, hashJoinOperatorInfo(q), "CARTESIAN inner join")
+}
+
+func TestBandJoinSpill(t *testing.T) {
+	restore := config.RestoreFunc()
+	defer restore()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.TempStoragePath = t.TempDir()
+	})
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/executor/testBandMergeJoinRowContainerSpill", "return(true)"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/executor/testBandMergeJoinRowContainerSpill"))
+	}()
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	defer tk.MustExec("SET GLOBAL tidb_mem_oom_action = DEFAULT")
+	tk.MustExec("SET GLOBAL tidb_mem_oom_action='LOG'")
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(id int, t int)")
+	tk.MustExec("create table t2(id int, lo int, hi int)")
+	for i := 0; i < 100; i++ {
+		lo := rand.Intn(50)
+		tk.MustExec(fmt.Sprintf("insert into t1 values(%d, %d)", i, rand.Intn(60)))
+		tk.MustExec(fmt.Sprintf("insert into t2 values(%d, %d, %d)", i, lo, lo+rand.Intn(5)))
+	}
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+
+	q := "select t1.id, t2.id from t1 left join t2 on t1.t between t2.lo and t2.hi"
+	expected := tk.MustQuery(q).Sort().Rows()
+	tk.MustExec("set @@tidb_enable_band_join = on")
+	tk.MustExec("set @@tidb_mem_quota_query = 1")
+	// The rows of both children are spilled to disk, the result is still the same.
+	tk.MustQuery(q).Sort().Check(expected)
+	require.Greater(t, tk.Session().GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), int64(0))
+	require.Equal(t, int64(0), tk.Session().GetSessionVars().StmtCtx.DiskTracker.BytesConsumed())
+}
+
+func TestHashJoinDedupSemiJoinBuildKeys(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1(id int, a int, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+	tk.MustExec("insert into t1 values (1, 1, 1), (2, 2, 5), (3, 3, 1), (4, null, 1), (5, 4, 9)")
+	// The build side is full of the duplicated keys.
+	tk.MustExec("insert into t2 values (1, 1), (2, 2), (null, 3)")
+	for i := 0; i < 6; i++ {
+		tk.MustExec("insert into t2 select a, b + 1 from t2")
+	}
+
+	queries := []string{
+		"select /*+ hash_join(t1, t2) */ id from t1 where exists (select 1 from t2 where t1.a = t2.a)",
+		"select /*+ hash_join(t1, t2) */ id from t1 where not exists (select 1 from t2 where t1.a = t2.a)",
+		"select /*+ hash_join(t1, t2) */ id, exists (select 1 from t2 where t1.a = t2.a) from t1",
+		"select /*+ hash_join(t1, t2) */ id, not exists (select 1 from t2 where t1.a = t2.a) from t1",
+		"select /*+ hash_join(t1, t2) */ id, a in (select a from t2) from t1",
+		"select /*+ hash_join(t1, t2) */ id, a not in (select a from t2) from t1",
+		"select /*+ hash_join(t1, t2) */ id from t1 where a not in (select a from t2)",
+		"select /*+ hash_join(t1, t2) */ id from t1 where a not in (select a from t2 where a is not null)",
+		// The other conditions are evaluated on every matched row.
+		"select /*+ hash_join(t1, t2) */ id from t1 where exists (select 1 from t2 where t1.a = t2.a and t1.b < t2.b)",
+		"select /*+ hash_join(t1, t2) */ id, exists (select 1 from t2 where t1.a = t2.a and t1.b > t2.b) from t1",
+	}
+	for _, q := range queries {
+		// The build keys aren't deduplicated when the radix hash table is used.
+		tk.MustExec("set @@tidb_hash_join_radix_bits = 2")
+		expected := tk.MustQuery(q).Sort().Rows()
+		tk.MustExec("set @@tidb_hash_join_radix_bits = 0")
+		tk.MustQuery(q).Sort().Check(expected)
+	}
+	tk.MustQuery("select /*+ hash_join(t1, t2) */ id from t1 where exists (select 1 from t2 where t1.a = t2.a)").
+		Sort().Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select /*+ hash_join(t1, t2) */ id from t1 where not exists (select 1 from t2 where t1.a = t2.a)").
+		Sort().Check(testkit.Rows("3", "4", "5"))
+	tk.MustQuery("select /*+ hash_join(t1, t2) */ id from t1 where exists (select 1 from t2 where t1.a = t2.a and t1.b < t2.b)").
+		Sort().Check(testkit.Rows("1", "2"))
+}
@@ -223,3 +223,59 @@ func (s *mockGCSSuite) TestMixedCompression() {
 		"8 test8", "9 test9",
 	))
 }
+
+func (s *mockGCSSuite) TestConcurrentLoadFiles() {
+	s.tk.MustExec("DROP DATABASE IF EXISTS multi_load;")
+	s.tk.MustExec("CREATE DATABASE multi_load;")
+	s.tk.MustExec("CREATE TABLE multi_load.t3 (i INT PRIMARY KEY, s varchar(32));")
+
+	const fileCnt, rowCnt = 8, 50
+	for i := 0; i < fileCnt; i++ {
+		buf := make([][]byte, 0, rowCnt)
+		for j := 0; j < rowCnt; j++ {
+			buf = append(buf, []byte(fmt.Sprintf("%d,%d", i*rowCnt+j, j)))
+		}
+		s.server.CreateObject(fakestorage.Object{
+			ObjectAttrs: fakestorage.ObjectAttrs{
+				BucketName: "test-multi-load",
+				Name:       fmt.Sprintf("concurrent-dir/%03d.csv", i),
+			},
+			Content: bytes.Join(buf, []byte("\n")),
+		})
+	}
+
+	s.tk.MustExec("SET SESSION tidb_load_data_concurrency = 4;")
+	defer s.tk.MustExec("SET SESSION tidb_load_data_concurrency = default;")
+	// the user variable set before the LOAD DATA is seen by all the workers.
+	s.tk.MustExec("SET @prefix = 's';")
+	sql := fmt.Sprintf(`LOAD DATA INFILE 'gs://test-multi-load/concurrent-dir/*.csv?endpoint=%s'
+		INTO TABLE multi_load.t3 fields terminated by ',' (i, @v) SET s = concat(@prefix, @v) WITH batch_size = 7;`, gcsEndpoint)
+	s.tk.MustExec(sql)
+	s.Equal("Records: 400  Deleted: 0  Skipped: 0  Warnings: 0", s.tk.Session().LastMessage())
+	s.tk.MustQuery("SELECT count(*), min(i), max(i) FROM multi_load.t3;").Check(testkit.Rows("400 0 399"))
+	// the user variable of each row is not overwritten by the other files.
+	s.tk.MustQuery("SELECT count(*) FROM multi_load.t3 WHERE s != concat('s', i % 50);").Check(testkit.Rows("0"))
+
+	// the failure is attributed to the file which contains the bad row.
+	s.server.CreateObject(fakestorage.Object{
+		ObjectAttrs: fakestorage.ObjectAttrs{
+			BucketName: "test-multi-load",
+			Name:       "concurrent-dir/bad/001.csv",
+		},
+		Content: []byte("1000,a\n1001,b"),
+	})
+	s.server.CreateObject(fakestorage.Object{
+		ObjectAttrs: fakestorage.ObjectAttrs{
+			BucketName: "test-multi-load",
+			Name:       "concurrent-dir/bad/002.csv",
+		},
+		Content: []byte("1002,c\nxyz,d"),
+	})
+	s.tk.MustExec("TRUNCATE TABLE multi_load.t3;")
+	sql = fmt.Sprintf(`LOAD DATA INFILE 'gs://test-multi-load/concurrent-dir/bad/*.csv?endpoint=%s'
+		INTO TABLE multi_load.t3 fields terminated by ',';`, gcsEndpoint)
+	err := s.tk.ExecToErr(sql)
+	require.ErrorContains(s.T(), err, "failed to load file concurrent-dir/bad/002.csv")
+	// the rows of the other files are rolled back too.
+	s.tk.MustQuery("SELECT count(*) FROM multi_load.t3;").Check(testkit.Rows("0"))
+}
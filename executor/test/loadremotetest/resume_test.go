@@ -0,0 +1,106 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadremotetest
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/fsouza/fake-gcs-server/fakestorage"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/testkit"
+)
+
+func (s *mockGCSSuite) TestResumeLoadData() {
+	s.tk.MustExec("DROP DATABASE IF EXISTS load_resume;")
+	s.tk.MustExec("CREATE DATABASE load_resume;")
+	s.tk.MustExec("CREATE TABLE load_resume.t (i INT, s varchar(32));")
+
+	// each file has 2500 rows, which are committed in several batches.
+	for f := 0; f < 2; f++ {
+		var content bytes.Buffer
+		content.WriteString("i,s\n")
+		for i := f * 2500; i < (f+1)*2500; i++ {
+			content.WriteString(fmt.Sprintf("%d,test%d\n", i, i))
+		}
+		s.server.CreateObject(fakestorage.Object{
+			ObjectAttrs: fakestorage.ObjectAttrs{
+				BucketName: "test-load-resume",
+				Name:       fmt.Sprintf("resume.%d.csv", f),
+			},
+			Content: content.Bytes(),
+		})
+	}
+
+	sql := fmt.Sprintf(`LOAD DATA INFILE 'gs://test-load-resume/resume.*.csv?endpoint=%s' INTO TABLE load_resume.t
+		FIELDS TERMINATED BY ',' LINES TERMINATED BY '\n' IGNORE 1 LINES WITH resume_token='resume-1';`, gcsEndpoint)
+
+	// the 5th batch fails, the 4 batches before it are committed with their checkpoints.
+	s.NoError(failpoint.Enable("github.com/pingcap/tidb/executor/commitWorkErrAfterTasks", "return(4)"))
+	err := s.tk.ExecToErr(sql)
+	s.NoError(failpoint.Disable("github.com/pingcap/tidb/executor/commitWorkErrAfterTasks"))
+	s.ErrorContains(err, "mock commit work error")
+	s.tk.MustQuery("SELECT count(*) = count(distinct i), count(*) > 0, count(*) < 5000 FROM load_resume.t;").
+		Check(testkit.Rows("1 1 1"))
+	s.tk.MustQuery("SELECT count(*) FROM mysql.tidb_load_data_checkpoints WHERE resume_token = 'resume-1';").
+		Check(testkit.Rows("2"))
+
+	// resume from the checkpoints, no row is missing or duplicated.
+	s.tk.MustExec(sql)
+	s.tk.MustQuery("SELECT count(*), count(distinct i), min(i), max(i) FROM load_resume.t;").
+		Check(testkit.Rows("5000 5000 0 4999"))
+
+	// all files are committed, the checkpoints of the token are removed.
+	s.tk.MustQuery("SELECT count(*) FROM mysql.tidb_load_data_checkpoints WHERE resume_token = 'resume-1';").
+		Check(testkit.Rows("0"))
+	// so loading again with the same token starts from the beginning.
+	s.tk.MustExec("TRUNCATE TABLE load_resume.t;")
+	s.tk.MustExec(sql)
+	s.tk.MustQuery("SELECT count(*), count(distinct i) FROM load_resume.t;").Check(testkit.Rows("5000 5000"))
+	s.tk.MustQuery("SELECT count(*) FROM mysql.tidb_load_data_checkpoints WHERE resume_token = 'resume-1';").
+		Check(testkit.Rows("0"))
+
+	// without resume token, the data is loaded from the start.
+	s.tk.MustExec("TRUNCATE TABLE load_resume.t;")
+	s.tk.MustExec(fmt.Sprintf(`LOAD DATA INFILE 'gs://test-load-resume/resume.*.csv?endpoint=%s' INTO TABLE load_resume.t
+		FIELDS TERMINATED BY ',' LINES TERMINATED BY '\n' IGNORE 1 LINES;`, gcsEndpoint))
+	s.tk.MustQuery("SELECT count(*), count(distinct i) FROM load_resume.t;").Check(testkit.Rows("5000 5000"))
+
+	// a new token doesn't share the checkpoints of another one.
+	s.tk.MustExec("TRUNCATE TABLE load_resume.t;")
+	s.tk.MustExec(fmt.Sprintf(`LOAD DATA INFILE 'gs://test-load-resume/resume.*.csv?endpoint=%s' INTO TABLE load_resume.t
+		FIELDS TERMINATED BY ',' LINES TERMINATED BY '\n' IGNORE 1 LINES WITH resume_token='resume-2';`, gcsEndpoint))
+	s.tk.MustQuery("SELECT count(*), count(distinct i) FROM load_resume.t;").Check(testkit.Rows("5000 5000"))
+
+	s.tk.MustContainErrMsg(fmt.Sprintf(`LOAD DATA INFILE 'gs://test-load-resume/resume.*.csv?endpoint=%s' INTO TABLE load_resume.t
+		WITH resume_token='';`, gcsEndpoint), "Invalid option value for resume_token")
+
+	// the path which is too long to be checkpointed is rejected before loading any data.
+	longName := strings.Repeat("a", 512) + ".csv"
+	s.server.CreateObject(fakestorage.Object{
+		ObjectAttrs: fakestorage.ObjectAttrs{
+			BucketName: "test-load-resume",
+			Name:       longName,
+		},
+		Content: []byte("1,test1\n"),
+	})
+	s.tk.MustExec("TRUNCATE TABLE load_resume.t;")
+	s.tk.MustContainErrMsg(fmt.Sprintf(`LOAD DATA INFILE 'gs://test-load-resume/%s?endpoint=%s' INTO TABLE load_resume.t
+		FIELDS TERMINATED BY ',' WITH resume_token='resume-3';`, longName, gcsEndpoint), "longer than 512 characters")
+	s.tk.MustQuery("SELECT count(*) FROM load_resume.t;").Check(testkit.Rows("0"))
+	s.tk.MustQuery("SELECT count(*) FROM mysql.tidb_load_data_checkpoints WHERE resume_token = 'resume-3';").
+		Check(testkit.Rows("0"))
+}
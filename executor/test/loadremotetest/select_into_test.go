@@ -0,0 +1,56 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadremotetest
+
+import (
+	"fmt"
+
+	"github.com/fsouza/fake-gcs-server/fakestorage"
+	"github.com/pingcap/tidb/testkit"
+)
+
+func (s *mockGCSSuite) TestSelectIntoOutfile() {
+	s.server.CreateBucketWithOpts(fakestorage.CreateBucketOpts{Name: "test-select-into"})
+	s.tk.MustExec("DROP DATABASE IF EXISTS select_into;")
+	s.tk.MustExec("CREATE DATABASE select_into;")
+	s.tk.MustExec("CREATE TABLE select_into.t (i INT, s varchar(32), d decimal(10, 2));")
+	s.tk.MustExec(`INSERT INTO select_into.t VALUES (1, 'a', 1.1), (2, 'b,"c', NULL), (3, NULL, 3.3);`)
+
+	s.tk.MustExec(fmt.Sprintf(`SELECT * FROM select_into.t ORDER BY i INTO OUTFILE 'gs://test-select-into/t.csv?endpoint=%s'
+		FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '"' LINES TERMINATED BY '\n';`, gcsEndpoint))
+	s.Equal(uint64(3), s.tk.Session().GetSessionVars().StmtCtx.AffectedRows())
+	obj, err := s.server.GetObject("test-select-into", "t.csv")
+	s.NoError(err)
+	s.Equal("1,\"a\",1.10\n2,\"b,\\\"c\",\\N\n3,\\N,3.30\n", string(obj.Content))
+
+	// the OUTFILE can be loaded back by LOAD DATA.
+	s.tk.MustExec("CREATE TABLE select_into.t2 LIKE select_into.t;")
+	s.tk.MustExec(fmt.Sprintf(`LOAD DATA INFILE 'gs://test-select-into/t.csv?endpoint=%s' INTO TABLE select_into.t2
+		FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '"' LINES TERMINATED BY '\n';`, gcsEndpoint))
+	s.tk.MustQuery("SELECT * FROM select_into.t2 ORDER BY i;").Check(testkit.Rows(
+		"1 a 1.10", `2 b,"c <nil>`, "3 <nil> 3.30"))
+
+	// empty result set still creates the OUTFILE.
+	s.tk.MustExec(fmt.Sprintf(`SELECT * FROM select_into.t WHERE i > 10 INTO OUTFILE 'gs://test-select-into/empty.csv?endpoint=%s';`, gcsEndpoint))
+	obj, err = s.server.GetObject("test-select-into", "empty.csv")
+	s.NoError(err)
+	s.Empty(obj.Content)
+
+	// the existing OUTFILE is not overwritten.
+	s.tk.MustContainErrMsg(fmt.Sprintf(`SELECT * FROM select_into.t INTO OUTFILE 'gs://test-select-into/t.csv?endpoint=%s';`, gcsEndpoint),
+		"File 'gs://test-select-into/t.csv?endpoint="+gcsEndpoint+"' already exists")
+	s.tk.MustContainErrMsg(fmt.Sprintf(`SELECT * FROM select_into.t INTO OUTFILE 'gs://test-select-into/?endpoint=%s';`, gcsEndpoint),
+		"the file name of OUTFILE")
+}
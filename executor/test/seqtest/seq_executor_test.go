@@ -635,6 +635,33 @@ func TestShowStatsHealthy(t *testing.T) {
 	tk.MustQuery("show stats_healthy").Check(testkit.Rows("test t  0"))
 }
 
+func TestShowStatsHealthyWithThreshold(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2, t3")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("create table t3 (a int)")
+	tk.MustExec("insert into t2 values (1), (2)")
+	tk.MustExec("insert into t3 values (1), (2), (3), (4), (5), (6), (7), (8), (9), (10)")
+	tk.MustExec("analyze table t1, t2, t3")
+	tk.MustExec("insert into t2 values (3), (4), (5), (6), (7), (8), (9), (10)")
+	tk.MustExec("insert into t3 values (11), (12)")
+	do, _ := session.GetDomain(store)
+	h := do.StatsHandle()
+	require.NoError(t, h.DumpStatsDeltaToKV(handle.DumpAll))
+	require.NoError(t, h.Update(do.InfoSchema()))
+
+	tk.MustQuery("show stats_healthy where db_name = 'test'").Sort().Check(testkit.Rows("test t1  100", "test t2  0", "test t3  80"))
+	tk.MustQuery("show stats_healthy where healthy < 80").Check(testkit.Rows("test t2  0"))
+	tk.MustQuery("show stats_healthy where healthy <= 80").Sort().Check(testkit.Rows("test t2  0", "test t3  80"))
+	tk.MustQuery("show stats_healthy where 100 > healthy and db_name = 'test'").Sort().Check(testkit.Rows("test t2  0", "test t3  80"))
+	tk.MustQuery("show stats_healthy where healthy < 100 and healthy > 0").Check(testkit.Rows("test t3  80"))
+	tk.MustQuery("show stats_healthy where healthy < 0").Check(testkit.Rows())
+}
+
 // TestIndexDoubleReadClose checks that when a index double read returns before reading all the rows, the goroutine doesn't
 // leak. For testing distsql with multiple regions, we need to manually split a mock TiKV.
 func TestIndexDoubleReadClose(t *testing.T) {
@@ -648,6 +675,7 @@ func TestIndexDoubleReadClose(t *testing.T) {
 	atomic.StoreInt32(&executor.LookupTableTaskChannelSize, 1)
 	tk := testkit.NewTestKit(t, store)
 	tk.MustExec("set @@tidb_index_lookup_size = '10'")
+	tk.MustExec("set @@tidb_index_lookup_fetch_ahead = 1")
 	tk.MustExec("use test")
 	tk.MustExec("create table dist (id int primary key, c_idx int, c_col int, index (c_idx))")
 
@@ -17,6 +17,7 @@ package showtest
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -2080,3 +2081,126 @@ func TestShowBindingDigestField(t *testing.T) {
 	result = tk.MustQuery("show global bindings;")
 	require.Equal(t, len(result.Rows()), 0)
 }
+
+func TestShowIndexWithLiveStats(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b int, c varchar(10), index ib(b), index ibc(b, c))")
+	tk.MustExec("create table tc(a varchar(10), b int, primary key(a, b) clustered)")
+
+	values := make([]string, 0, 1000)
+	for i := 0; i < 10; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d, 'x')", i, i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	tk.MustExec("insert into tc select c, a from t")
+	tk.MustExec("analyze table t, tc")
+	// Make the stats stale.
+	values = values[:0]
+	for i := 10; i < 1000; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d, '%d')", i, i, i%5))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	tk.MustExec("insert into tc select c, a from t where a >= 10")
+
+	tk.MustQuery("show index from t").CheckAt([]int{2, 4, 6}, testkit.Rows(
+		"PRIMARY a 10",
+		"ib b 10",
+		"ibc b 10",
+		"ibc c 1",
+	))
+	tk.MustQuery("show index from t with live stats").CheckAt([]int{2, 4, 6}, testkit.Rows(
+		"PRIMARY a 1000",
+		"ib b 1000",
+		"ibc b 1000",
+		"ibc c 6",
+	))
+	tk.MustQuery("show index from tc").CheckAt([]int{2, 4, 6}, testkit.Rows(
+		"PRIMARY a 1",
+		"PRIMARY b 10",
+	))
+	tk.MustQuery("show index from tc with live stats").CheckAt([]int{2, 4, 6}, testkit.Rows(
+		"PRIMARY a 6",
+		"PRIMARY b 1000",
+	))
+
+	tk.MustExec("create table tp(a int, b int, index ia(a)) partition by hash(b) partitions 4")
+	tk.MustExec("insert into tp select b, a from t")
+	tk.MustQuery("show index from tp with live stats where key_name = 'ia'").CheckAt([]int{2, 4, 6}, testkit.Rows("ia a 1000"))
+}
+
+func TestShowTablesInBatches(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database show_many")
+	tk.MustExec("use show_many")
+	const tableCount = 200
+	for i := tableCount - 1; i >= 0; i-- {
+		tk.MustExec(fmt.Sprintf("create table t_%03d (a int)", i))
+	}
+	tk.MustExec("create view v_with_a_much_longer_name as select 1")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+
+	rs, err := tk.Exec("show full tables")
+	require.NoError(t, err)
+	names := make([]string, 0, tableCount+1)
+	numChunks := 0
+	chk := rs.NewChunk(nil)
+	for {
+		require.NoError(t, rs.Next(context.Background(), chk))
+		if chk.NumRows() == 0 {
+			break
+		}
+		require.LessOrEqual(t, chk.NumRows(), 32)
+		numChunks++
+		for i := 0; i < chk.NumRows(); i++ {
+			names = append(names, string(chk.GetRow(i).GetBytes(0)))
+		}
+	}
+	// The result columns are widened for the longest values before the first batch is returned.
+	require.Equal(t, len("v_with_a_much_longer_name"), rs.Fields()[0].Column.GetFlen())
+	require.Equal(t, len("BASE TABLE"), rs.Fields()[1].Column.GetFlen())
+	require.NoError(t, rs.Close())
+	require.Greater(t, numChunks, 1)
+	require.Len(t, names, tableCount+1)
+	for i := 0; i < tableCount; i++ {
+		require.Equal(t, fmt.Sprintf("t_%03d", i), names[i])
+	}
+	require.Equal(t, "v_with_a_much_longer_name", names[tableCount])
+
+	tk.MustQuery("show tables like 't_19%'").Check(testkit.Rows(
+		"t_190", "t_191", "t_192", "t_193", "t_194", "t_195", "t_196", "t_197", "t_198", "t_199"))
+	tk.MustQuery("show full tables where Table_type = 'VIEW'").Check(testkit.Rows("v_with_a_much_longer_name VIEW"))
+}
+
+func TestShowProfile(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int)")
+	tk.MustExec("insert into t values (1), (2), (3)")
+
+	tk.MustQuery("select /*+ stream_agg() */ count(*) from t where a > 1").Check(testkit.Rows("2"))
+	rows := tk.MustQuery("show profile").Rows()
+	require.NotEmpty(t, rows)
+	operators := make([]string, 0, len(rows))
+	durations := make([]float64, 0, len(rows))
+	for _, row := range rows {
+		operators = append(operators, row[0].(string))
+		duration, err := strconv.ParseFloat(row[1].(string), 64)
+		require.NoError(t, err)
+		durations = append(durations, duration)
+	}
+	require.True(t, strings.HasPrefix(operators[0], "StreamAgg"), operators)
+	require.Equal(t, "1", rows[0][2])
+	require.True(t, strings.HasPrefix(operators[1], "TableReader"), operators)
+	require.Greater(t, durations[0], 0.0)
+	require.Greater(t, durations[1], 0.0)
+
+	tk.MustQuery("select /*+ stream_agg() */ count(*) from t where a > 1").Check(testkit.Rows("2"))
+	require.Len(t, tk.MustQuery("show profile limit 1").Rows(), 1)
+	tk.MustGetErrMsg("show profile for query 1", "[planner:1235]This version of TiDB doesn't yet support 'SHOW PROFILE FOR QUERY'")
+}
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"fmt"
 	"math/rand"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -34,6 +35,7 @@ import (
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/store/mockstore/unistore"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/pingcap/tidb/testkit/external"
 	"github.com/pingcap/tidb/util/dbterror/exeerrors"
@@ -42,6 +44,7 @@ import (
 	"github.com/pingcap/tidb/util/tiflashcompute"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/client-go/v2/testutils"
+	"github.com/tikv/client-go/v2/tikvrpc"
 )
 
 // withMockTiFlash sets the mockStore to have N TiFlash stores (naming as tiflash0, tiflash1, ...).
@@ -1709,3 +1712,127 @@ func TestMppStoreCntWithErrors(t *testing.T) {
 	require.Nil(t, failpoint.Disable(mppStoreCountSetLastUpdateTimeP2))
 	require.Nil(t, failpoint.Disable(mppStoreCountPDError))
 }
+
+func TestHybridStoreScan(t *testing.T) {
+	store := testkit.CreateMockStore(t, withMockTiFlash(2))
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec(`create table t (a int, b int) partition by range(a) (
+		partition p0 values less than (100), partition p1 values less than (200),
+		partition p2 values less than (300), partition p3 values less than (maxvalue))`)
+	tk.MustExec("alter table t set tiflash replica 1")
+	tb := external.GetTableByName(t, tk, "test", "t")
+	err := domain.GetDomain(tk.Session()).DDL().UpdateTableReplicaInfo(tk.Session(), tb.Meta().ID, true)
+	require.NoError(t, err)
+	vals := make([]string, 0, 400)
+	for i := 0; i < 400; i++ {
+		vals = append(vals, fmt.Sprintf("(%v, %v)", i, i%10))
+	}
+	tk.MustExec("insert into t values " + strings.Join(vals, ", "))
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec("set @@tidb_allow_mpp = 0")
+
+	// Record the partitions scanned by each store type.
+	partitionNames := make(map[int64]string)
+	for _, def := range tb.Meta().Partition.Definitions {
+		partitionNames[def.ID] = def.Name.L
+	}
+	var mu sync.Mutex
+	scanned := make(map[tikvrpc.EndpointType]map[string]struct{})
+	hook := func(req *tikvrpc.Request) {
+		if req.Type != tikvrpc.CmdCop {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, r := range req.Cop().Ranges {
+			if name, ok := partitionNames[tablecodec.DecodeTableID(r.Start)]; ok {
+				if scanned[req.StoreTp] == nil {
+					scanned[req.StoreTp] = make(map[string]struct{})
+				}
+				scanned[req.StoreTp][name] = struct{}{}
+			}
+		}
+	}
+	unistore.UnistoreRPCClientSendHook.Store(&hook)
+	defer unistore.UnistoreRPCClientSendHook.Store(nil)
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/store/mockstore/unistore/unistoreRPCClientSendHook", `return(true)`))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/store/mockstore/unistore/unistoreRPCClientSendHook"))
+	}()
+	checkScanned := func(sql string, tikvPartitions, tiflashPartitions []string) {
+		mu.Lock()
+		scanned = make(map[tikvrpc.EndpointType]map[string]struct{})
+		mu.Unlock()
+		tk.MustQuery(sql)
+		mu.Lock()
+		defer mu.Unlock()
+		for tp, expected := range map[tikvrpc.EndpointType][]string{tikvrpc.TiKV: tikvPartitions, tikvrpc.TiFlash: tiflashPartitions} {
+			actual := make([]string, 0, len(scanned[tp]))
+			for name := range scanned[tp] {
+				actual = append(actual, name)
+			}
+			sort.Strings(actual)
+			require.Equal(t, expected, actual, sql)
+		}
+	}
+
+	for _, sql := range []string{
+		"select /*+ read_from_storage(tikv[t]) */ * from t",
+		"select /*+ read_from_storage(tikv[t]) */ * from t where b = 3 or a < 0",
+		"select /*+ read_from_storage(tikv[t]) */ a from t where a between 150 and 250",
+		"select /*+ read_from_storage(tikv[t]) */ a from t where a >= 300 and b > 5",
+		"select /*+ read_from_storage(tikv[t]) */ a, b from t where a > 95 order by a limit 3",
+	} {
+		tk.MustExec("set @@tidb_enable_hybrid_store_scan = 0")
+		expected := tk.MustQuery(sql).Sort().Rows()
+		tk.MustExec("set @@tidb_enable_hybrid_store_scan = 1")
+		tk.MustQuery(sql).Sort().Check(expected)
+	}
+
+	tk.MustExec("set @@tidb_enable_hybrid_store_scan = 1")
+	// The latest partition is read from TiKV and the others are read from TiFlash.
+	checkScanned("select /*+ read_from_storage(tikv[t]) */ * from t where b = 3 or a < 0",
+		[]string{"p3"}, []string{"p0", "p1", "p2"})
+	checkScanned("select /*+ read_from_storage(tikv[t]) */ a from t where a between 150 and 250",
+		[]string{"p2"}, []string{"p1"})
+	// A single partition is read from TiKV only.
+	checkScanned("select /*+ read_from_storage(tikv[t]) */ a from t where a >= 300 and b > 5",
+		[]string{"p3"}, []string{})
+
+	tk.MustExec("set @@tidb_enable_hybrid_store_scan = 0")
+	checkScanned("select /*+ read_from_storage(tikv[t]) */ * from t where b = 3 or a < 0",
+		[]string{"p0", "p1", "p2", "p3"}, []string{})
+}
+
+func TestSnapshotTSConsistency(t *testing.T) {
+	store := testkit.CreateMockStore(t, withMockTiFlash(2))
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec(`create table t (a int, b int) partition by range(a) (
+		partition p0 values less than (100), partition p1 values less than (maxvalue))`)
+	tk.MustExec("alter table t set tiflash replica 1")
+	tb := external.GetTableByName(t, tk, "test", "t")
+	err := domain.GetDomain(tk.Session()).DDL().UpdateTableReplicaInfo(tk.Session(), tb.Meta().ID, true)
+	require.NoError(t, err)
+	tk.MustExec("insert into t values (1, 1), (101, 101)")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec("analyze table t")
+	tk.MustExec("set @@tidb_allow_mpp = 0")
+	tk.MustExec("set @@tidb_enable_hybrid_store_scan = 1")
+	sql := "select /*+ read_from_storage(tikv[t]) */ * from t"
+
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/executor/assertSnapshotTSConsistency", "return"))
+	// The TiKV and TiFlash readers read the same snapshot.
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("1 1", "101 101"))
+
+	// The TiFlash reader reads a different snapshot from the TiKV reader.
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/executor/mockTiFlashSnapshotTSMismatch", "return"))
+	err = tk.ExecToErr(sql)
+	require.ErrorContains(t, err, "mismatches the snapshot ts")
+
+	// The assertion is off by default.
+	require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/executor/assertSnapshotTSConsistency"))
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("1 1", "101 101"))
+	require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/executor/mockTiFlashSnapshotTSMismatch"))
+}
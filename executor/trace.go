@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -39,8 +40,10 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/tracing"
 	"github.com/tikv/client-go/v2/util"
 	"go.uber.org/zap"
 	"golang.org/x/exp/slices"
@@ -100,6 +103,12 @@ func (e *TraceExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	switch e.format {
 	case core.TraceFormatLog:
 		return e.nextTraceLog(ctx, se, req)
+	case core.TraceFormatCopRegions:
+		return e.nextCopRegions(ctx, se, req)
+	case core.TraceFormatLocks:
+		return e.nextLocks(ctx, se, req)
+	case core.TraceFormatOptRules:
+		return e.nextOptRules(ctx, se, req)
 	default:
 		return e.nextRowJSON(ctx, se, req)
 	}
@@ -225,6 +234,62 @@ func (e *TraceExec) nextTraceLog(ctx context.Context, se sqlexec.SQLExecutor, re
 	return nil
 }
 
+func (e *TraceExec) nextCopRegions(ctx context.Context, se sqlexec.SQLExecutor, req *chunk.Chunk) error {
+	details := execdetails.NewCopRegionDetails()
+	ctx = context.WithValue(ctx, execdetails.CopRegionDetailsKey, details)
+	e.executeChild(ctx, se)
+
+	for _, detail := range details.Details() {
+		req.AppendInt64(0, int64(detail.RegionID))
+		req.AppendString(1, detail.StoreAddr)
+		req.AppendInt64(2, int64(detail.Requests))
+		req.AppendString(3, detail.WaitTime.String())
+		req.AppendString(4, detail.ProcessTime.String())
+		req.AppendString(5, detail.BackoffTime.String())
+	}
+	e.exhausted = true
+	return nil
+}
+
+func (e *TraceExec) nextLocks(ctx context.Context, se sqlexec.SQLExecutor, req *chunk.Chunk) error {
+	events := execdetails.NewLockEvents()
+	ctx = context.WithValue(ctx, execdetails.LockEventsKey, events)
+	e.executeChild(ctx, se)
+
+	for _, event := range events.Events() {
+		keys := make([]string, 0, len(event.Keys))
+		for _, key := range event.Keys {
+			keys = append(keys, kv.Key(key).String())
+		}
+		req.AppendString(0, strings.Join(keys, ", "))
+		req.AppendString(1, strconv.FormatBool(event.Waited))
+		req.AppendString(2, event.LockTime.String())
+		req.AppendString(3, event.ResolveTime.String())
+		if event.Err != nil {
+			req.AppendString(4, event.Err.Error())
+		} else {
+			req.AppendString(4, "")
+		}
+	}
+	e.exhausted = true
+	return nil
+}
+
+func (e *TraceExec) nextOptRules(ctx context.Context, se sqlexec.SQLExecutor, req *chunk.Chunk) error {
+	ruleStats := tracing.NewOptRuleStats()
+	ctx = context.WithValue(ctx, tracing.OptRuleStatsKey, ruleStats)
+	e.executeChild(ctx, se)
+
+	for _, stat := range ruleStats.Stats() {
+		req.AppendString(0, stat.Phase)
+		req.AppendString(1, stat.Name)
+		req.AppendInt64(2, int64(stat.Count))
+		req.AppendString(3, stat.Time.String())
+	}
+	e.exhausted = true
+	return nil
+}
+
 func (e *TraceExec) nextRowJSON(ctx context.Context, se sqlexec.SQLExecutor, req *chunk.Chunk) error {
 	store := appdash.NewMemoryStore()
 	tracer := traceImpl.NewTracer(store)
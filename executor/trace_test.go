@@ -15,7 +15,9 @@
 package executor_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tidb/testkit"
 	"github.com/stretchr/testify/require"
@@ -84,3 +86,120 @@ func TestTracePlanStmt(t *testing.T) {
 	require.Len(t, rows[0], 1)
 	require.Regexp(t, ".*zip", rows[0][0])
 }
+
+func TestTraceCopRegions(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v int)")
+	tk.MustQuery("split table t between (0) and (10000) regions 4").Check(testkit.Rows("3 1"))
+	for i := 0; i < 10000; i += 1000 {
+		tk.MustExec("insert into t values (?, ?)", i, i)
+	}
+
+	// Every region of the table is scanned by a coprocessor request.
+	rows := tk.MustQuery("trace format='cop_regions' select * from t").Rows()
+	require.GreaterOrEqual(t, len(rows), 4)
+	regions := make(map[string]struct{}, len(rows))
+	for _, row := range rows {
+		require.Len(t, row, 6)
+		regions[row[0].(string)] = struct{}{}
+		require.NotEmpty(t, row[1])
+		require.NotEqual(t, "0", row[2])
+	}
+	require.Len(t, regions, len(rows))
+
+	// The regions are collected even if the execution info isn't collected for the other statements.
+	tk.MustExec("set global tidb_enable_collect_execution_info = 0")
+	defer tk.MustExec("set global tidb_enable_collect_execution_info = 1")
+	require.Len(t, tk.MustQuery("trace format='cop_regions' select * from t").Rows(), len(rows))
+
+	// A point get doesn't send coprocessor requests.
+	tk.MustQuery("trace format='cop_regions' select * from t where id = 1000").Check(testkit.Rows())
+	tk.MustGetErrMsg("trace format='unknown' select * from t", "trace format should be one of 'row', 'log', 'json', 'cop_regions', 'locks' or 'opt_rules'")
+}
+
+func TestTraceLocks(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+
+	// The lock is acquired without waiting if there is no conflict.
+	tk.MustExec("begin pessimistic")
+	rows := tk.MustQuery("trace format='locks' select * from t where id = 2 for update").Rows()
+	require.Len(t, rows, 1)
+	require.Len(t, rows[0], 5)
+	require.Equal(t, "false", rows[0][1])
+	require.Equal(t, "", rows[0][4])
+	// The keys locked by one request are reported in one row, with the stats of the whole request.
+	rows = tk.MustQuery("trace format='locks' select * from t where id in (1, 2) for update").Rows()
+	require.Len(t, rows, 1)
+	require.Len(t, strings.Split(rows[0][0].(string), ", "), 2)
+	require.Equal(t, "false", rows[0][1])
+	tk.MustExec("rollback")
+
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+	tk1.MustExec("begin pessimistic")
+	tk1.MustExec("update t set v = v + 1 where id = 1")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		time.Sleep(300 * time.Millisecond)
+		tk1.MustExec("commit")
+	}()
+	tk.MustExec("begin pessimistic")
+	rows = tk.MustQuery("trace format='locks' update t set v = v + 10 where id = 1").Rows()
+	<-done
+	tk.MustExec("commit")
+	tk.MustQuery("select v from t where id = 1").Check(testkit.Rows("12"))
+
+	// The key of the row is locked after waiting for the conflicting transaction.
+	require.NotEmpty(t, rows)
+	waited := false
+	for _, row := range rows {
+		require.Equal(t, rows[0][0], row[0])
+		waited = waited || row[1] == "true"
+	}
+	require.True(t, waited)
+}
+
+func TestTraceOptRules(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int, b int)")
+	tk.MustExec("create table t2 (a int, b int)")
+
+	rows := tk.MustQuery("trace format='opt_rules' select t1.a from t1 join t2 on t1.a = t2.a where t1.b > 1 order by t1.a limit 10").Rows()
+	counts := make(map[string]string, len(rows))
+	for _, row := range rows {
+		require.Len(t, row, 4)
+		counts[row[0].(string)+"/"+row[1].(string)] = row[2].(string)
+		_, err := time.ParseDuration(row[3].(string))
+		require.NoError(t, err)
+	}
+	for _, rule := range []string{
+		"logical/column_prune",
+		"logical/predicate_push_down",
+		"logical/join_reorder",
+		"logical/topn_push_down",
+		"physical/find_best_task",
+		"physical/post_optimize",
+	} {
+		require.Contains(t, counts, rule)
+		require.NotEqual(t, "0", counts[rule])
+	}
+	// The rules which aren't needed by the statement are not applied.
+	require.NotContains(t, counts, "logical/aggregation_eliminate")
+
+	// The full optimizer trace is unchanged.
+	rows = tk.MustQuery("trace plan target='estimation' select * from t1 where a = 1").Rows()
+	require.Len(t, rows, 1)
+	require.Contains(t, rows[0][0], "Column Stats-Pseudo-Point")
+	tk.MustGetErrMsg("trace format='opt_rule' select * from t1",
+		"trace format should be one of 'row', 'log', 'json', 'cop_regions', 'locks' or 'opt_rules'")
+}
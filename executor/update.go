@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -70,6 +71,37 @@ type UpdateExec struct {
 	fkChecks map[int64][]*FKCheckExec
 	// fkCascades contains the foreign key cascade. the map is tableID -> []*FKCascadeExec
 	fkCascades map[int64][]*FKCascadeExec
+	// returning is used for single table update with RETURNING clause, it's evaluated on the updated rows.
+	returning *dmlReturning
+	// returningRow is the buffer to evaluate the RETURNING clause on the new row.
+	returningRow chunk.MutRow
+
+	// deferUniqueCheck indicates the rows failed by the duplicated unique keys are retried after all the other rows
+	// are updated, so the unique keys are only required to be unique at the end of the statement.
+	deferUniqueCheck bool
+	// deferredRows are the rows failed by the duplicated unique keys to be retried at the end of the statement.
+	deferredRows []*deferredUpdateRow
+
+	// colStats counts the changed rows of each assigned column, it's only set when tidb_collect_update_column_stats is on.
+	colStats []updateColumnStat
+}
+
+// updateColumnStat is the counter of a column assigned by the UPDATE statement.
+type updateColumnStat struct {
+	variable.UpdateColumnStat
+	// colIdx is the ordinal of the column in the row to update.
+	colIdx int
+}
+
+// deferredUpdateRow is a row failed by the duplicated unique keys when deferUniqueCheck is enabled.
+type deferredUpdateRow struct {
+	tblPos  int
+	handle  kv.Handle
+	oldData []types.Datum
+	newData []types.Datum
+	flags   []bool
+	// newRow is the whole new row to evaluate the RETURNING clause.
+	newRow []types.Datum
 }
 
 // prepare `handles`, `tableUpdatable`, `changed` to avoid re-computations.
@@ -199,6 +231,13 @@ func (e *UpdateExec) exec(ctx context.Context, schema *expression.Schema, row, n
 		fkCascades := e.fkCascades[content.TblID]
 		changed, err1 := updateRecord(ctx, e.ctx, handle, oldData, newTableData, flags, tbl, false, e.memTracker, fkChecks, fkCascades)
 		if err1 == nil {
+			e.collectColumnStats(content, flags)
+			if e.returning != nil {
+				e.returningRow.SetDatums(newData...)
+				if err := e.returning.appendRow(e.returningRow.ToRow()); err != nil {
+					return err
+				}
+			}
 			_, exist := e.updatedRowKeys[content.Start].Get(handle)
 			memDelta := e.updatedRowKeys[content.Start].Set(handle, changed)
 			if !exist {
@@ -213,11 +252,93 @@ func (e *UpdateExec) exec(ctx context.Context, schema *expression.Schema, row, n
 			sc.AppendWarning(err1)
 			continue
 		}
+		if e.deferUniqueCheck && kv.ErrKeyExists.Equal(err1) {
+			// The conflicting row may be updated later in this statement, retry it at the end.
+			e.deferRow(i, handle, oldData, newTableData, bAssignFlag[content.Start:content.End], newData)
+			continue
+		}
 		return err1
 	}
 	return nil
 }
 
+func (e *UpdateExec) deferRow(tblPos int, handle kv.Handle, oldData, newData []types.Datum, flags []bool, newRow []types.Datum) {
+	row := &deferredUpdateRow{
+		tblPos:  tblPos,
+		handle:  handle,
+		oldData: oldData,
+		newData: newData,
+		flags:   append([]bool(nil), flags...),
+		newRow:  newRow,
+	}
+	e.deferredRows = append(e.deferredRows, row)
+	// Mark the row as updated, so it isn't updated again if it's matched multiple times.
+	content := e.tblColPosInfos[tblPos]
+	_, exist := e.updatedRowKeys[content.Start].Get(handle)
+	memDelta := e.updatedRowKeys[content.Start].Set(handle, true)
+	if !exist {
+		memDelta += int64(handle.ExtraMemSize())
+	}
+	memDelta += types.EstimatedMemUsage(oldData, 1) + types.EstimatedMemUsage(newRow, 1) + int64(len(flags))
+	e.memTracker.Consume(memDelta)
+}
+
+// execDeferred retries the deferred rows until all of them are updated, or returns the duplicated key error
+// if none of the remaining rows can be updated, which means the unique keys are still duplicated at the end.
+func (e *UpdateExec) execDeferred(ctx context.Context) error {
+	for len(e.deferredRows) > 0 {
+		var lastErr error
+		remained := make([]*deferredUpdateRow, 0, len(e.deferredRows))
+		// Retry in the reverse order, so a chain of conflicts like `SET a = a + 1` is resolved in one round.
+		for i := len(e.deferredRows) - 1; i >= 0; i-- {
+			row := e.deferredRows[i]
+			content := e.tblColPosInfos[row.tblPos]
+			tbl := e.tblID2table[content.TblID]
+			flags := append([]bool(nil), row.flags...)
+			_, err := updateRecord(ctx, e.ctx, row.handle, row.oldData, row.newData, flags, tbl, false, e.memTracker,
+				e.fkChecks[content.TblID], e.fkCascades[content.TblID])
+			if err == nil {
+				e.collectColumnStats(content, flags)
+				if e.returning != nil {
+					e.returningRow.SetDatums(row.newRow...)
+					if err := e.returning.appendRow(e.returningRow.ToRow()); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if !kv.ErrKeyExists.Equal(err) {
+				return err
+			}
+			lastErr = err
+			remained = append(remained, row)
+		}
+		if len(remained) == len(e.deferredRows) {
+			return lastErr
+		}
+		for i, j := 0, len(remained)-1; i < j; i, j = i+1, j-1 {
+			remained[i], remained[j] = remained[j], remained[i]
+		}
+		e.deferredRows = remained
+	}
+	return nil
+}
+
+// collectColumnStats counts the assigned columns of an updated row, the modified flags are set by updateRecord and
+// are true only if the new values of the columns differ from the old ones.
+func (e *UpdateExec) collectColumnStats(content plannercore.TblColPosInfo, modified []bool) {
+	for i := range e.colStats {
+		stat := &e.colStats[i]
+		if stat.colIdx < content.Start || stat.colIdx >= content.End {
+			continue
+		}
+		stat.AssignedRows++
+		if modified[stat.colIdx-content.Start] {
+			stat.ChangedRows++
+		}
+	}
+}
+
 // unmatchedOuterRow checks the tableCols of a record to decide whether that record
 // can not be updated. The handle is NULL only when it is the inner side of an
 // outer join: the outer row can not match any inner rows, and in this scenario
@@ -254,6 +375,9 @@ func (e *UpdateExec) updateRows(ctx context.Context) (int, error) {
 	if !e.allAssignmentsAreConstant {
 		e.evalBuffer = chunk.MutRowFromTypes(fields)
 	}
+	if e.returning != nil {
+		e.returningRow = chunk.MutRowFromTypes(fields)
+	}
 	composeFunc := e.fastComposeNewRow
 	if !e.allAssignmentsAreConstant {
 		composeFunc = e.composeNewRow
@@ -322,6 +446,9 @@ func (e *UpdateExec) updateRows(ctx context.Context) (int, error) {
 		totalNumRows += chk.NumRows()
 		chk = chunk.Renew(chk, e.maxChunkSize)
 	}
+	if err := e.execDeferred(ctx); err != nil {
+		return 0, err
+	}
 	return totalNumRows, nil
 }
 
@@ -429,6 +556,13 @@ func (e *UpdateExec) composeGeneratedColumns(rowIdx int, newRowData []types.Datu
 func (e *UpdateExec) Close() error {
 	defer e.memTracker.ReplaceBytesUsed(0)
 	e.setMessage()
+	if e.colStats != nil {
+		stats := make([]variable.UpdateColumnStat, 0, len(e.colStats))
+		for _, stat := range e.colStats {
+			stats = append(stats, stat.UpdateColumnStat)
+		}
+		e.ctx.GetSessionVars().LastUpdateColumnStats = stats
+	}
 	if e.runtimeStats != nil && e.stats != nil {
 		txn, err := e.ctx.Txn(false)
 		if err == nil && txn.Valid() && txn.GetSnapshot() != nil {
@@ -443,6 +577,9 @@ func (e *UpdateExec) Close() error {
 func (e *UpdateExec) Open(ctx context.Context) error {
 	e.memTracker = memory.NewTracker(e.id, -1)
 	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	if e.returning != nil {
+		e.returning.rows.GetMemTracker().AttachTo(e.memTracker)
+	}
 
 	return e.children[0].Open(ctx)
 }
@@ -597,3 +597,138 @@ func TestLockUnchangedUniqueKeys(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateReturning(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int primary key, a int, b int as (a * 2) virtual, c int as (a + 1) stored)")
+	tk.MustExec("insert into t(id, a) values (1, 10), (2, 20), (3, 30)")
+	tk.MustQuery("update t set a = a + 1 where id = 1 returning id, a, b, c").Check(testkit.Rows("1 11 22 12"))
+	tk.MustQuery("update t set a = a where id = 2 returning *").Check(testkit.Rows("2 20 40 21"))
+	tk.MustQuery("update t set a = a where id > 100 returning *").Check(testkit.Rows())
+	tk.MustQuery("update t set a = a + 1 where id > 1 order by id desc returning t.*, a + b as d").
+		Check(testkit.Rows("3 31 62 32 93", "2 21 42 22 63"))
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 11 22 12", "2 21 42 22", "3 31 62 32"))
+
+	// The rows are returned in the pessimistic transaction.
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("update t set a = 0 where id = 2 returning id, c").Check(testkit.Rows("2 1"))
+	tk.MustQuery("select a from t where id = 2").Check(testkit.Rows("0"))
+	tk.MustExec("rollback")
+	tk.MustQuery("select a from t where id = 2").Check(testkit.Rows("21"))
+
+	tk.MustQuery("update t set a = (select 100) where id = 3 returning a").Check(testkit.Rows("100"))
+	tk.MustGetErrCode("update t set a = 1 returning (select 1)", errno.ErrNotSupportedYet)
+	tk.MustGetErrCode("update t set a = 1 returning d", errno.ErrBadField)
+
+	// The multiple-table UPDATE is not supported, the joined rows may update a row several times.
+	tk.MustExec("create table t2(id int, x int)")
+	tk.MustExec("insert into t2 values (1, 1), (1, 2)")
+	tk.MustGetErrCode("update t join t2 on t.id = t2.id set t.a = t.a + t2.x returning t.id, t.a", errno.ErrNotSupportedYet)
+	tk.MustGetErrCode("update t, t2 set t.a = t2.x where t.id = t2.id returning t.id", errno.ErrParse)
+	tk.MustGetErrCode("update (t join t2 on t.id = t2.id) set t.a = 1 returning t.id", errno.ErrNotSupportedYet)
+	tk.MustGetErrCode("update t join (select 1 as id) s on t.id = s.id set t.a = 1 returning t.id", errno.ErrNotSupportedYet)
+	tk.MustQuery("select id, a from t where id = 1").Check(testkit.Rows("1 11"))
+}
+
+func TestUpdateDeferUniqueCheck(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int primary key, a int, unique key(a))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+
+	// The unique keys are checked for each row by default.
+	tk.MustGetErrCode("update t set a = a + 1", errno.ErrDupEntry)
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1", "2 2", "3 3"))
+
+	// The unique keys are only required to be unique at the end of the statement.
+	tk.MustExec("set @@tidb_defer_unique_check_in_update = on")
+	tk.MustExec("update t set a = a + 1")
+	require.Equal(t, uint64(3), tk.Session().AffectedRows())
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 2", "2 3", "3 4"))
+	tk.MustQuery("select * from t use index(a) order by a").Check(testkit.Rows("1 2", "2 3", "3 4"))
+	tk.MustExec("update t set a = a - 1 order by a desc")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1", "2 2", "3 3"))
+	tk.MustExec("admin check table t")
+
+	// It works for the handle too.
+	tk.MustExec("update t set id = id + 1")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("2 1", "3 2", "4 3"))
+	tk.MustExec("admin check table t")
+
+	// The duplicated keys at the end of the statement are still reported.
+	tk.MustGetErrCode("update t set a = 1", errno.ErrDupEntry)
+	tk.MustGetErrCode("update t set a = a + 1 where id < 4", errno.ErrDupEntry)
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("2 1", "3 2", "4 3"))
+
+	// It works in the pessimistic and optimistic transactions.
+	for _, mode := range []string{"pessimistic", "optimistic"} {
+		tk.MustExec("begin " + mode)
+		tk.MustExec("update t set a = a + 1")
+		tk.MustExec("update t set a = a - 1")
+		tk.MustExec("commit")
+		tk.MustQuery("select * from t order by id").Check(testkit.Rows("2 1", "3 2", "4 3"))
+		tk.MustExec("admin check table t")
+	}
+
+	// The deferred rows are returned too.
+	tk.MustQuery("update t set a = a + 10 - 9 returning id, a").Sort().Check(testkit.Rows("2 2", "3 3", "4 4"))
+
+	// The hint works as the session variable.
+	tk.MustExec("set @@tidb_defer_unique_check_in_update = off")
+	tk.MustGetErrCode("update t set a = a + 1", errno.ErrDupEntry)
+	tk.MustExec("update /*+ set_var(tidb_defer_unique_check_in_update=on) */ t set a = a + 1")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("2 3", "3 4", "4 5"))
+}
+
+func TestUpdateColumnStats(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int primary key, a int, b varchar(10), c int as (a + 1))")
+	tk.MustExec("create table t2(id int primary key, d int)")
+	tk.MustExec("insert into t(id, a, b) values (1, 1, 'x'), (2, 2, 'y'), (3, 3, 'x'), (4, 4, NULL)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 1), (3, 1)")
+	statsSQL := "select * from information_schema.last_update_column_stats"
+
+	// Nothing is collected by default.
+	tk.MustExec("update t set a = 1")
+	tk.MustQuery(statsSQL).Check(testkit.Rows())
+
+	tk.MustExec("set @@tidb_collect_update_column_stats = on")
+	tk.MustExec("update t set a = 1, b = 'x' where id < 4")
+	tk.MustQuery(statsSQL).Check(testkit.Rows(
+		"test t a 3 0",
+		"test t b 3 1",
+	))
+	// The rows are changed by some of the assigned columns.
+	tk.MustExec("update t set a = id, b = upper(b)")
+	tk.MustQuery(statsSQL).Check(testkit.Rows(
+		"test t a 4 3",
+		"test t b 4 3",
+	))
+	// The columns of the multiple tables are counted separately.
+	tk.MustExec("update t join t2 on t.id = t2.id set t.b = 'X', t2.d = t.a")
+	tk.MustQuery(statsSQL).Check(testkit.Rows(
+		"test t b 3 0",
+		"test t2 d 3 2",
+	))
+	// The stats are kept until the next UPDATE statement.
+	tk.MustQuery("select id, a, b from t where id = 4").Check(testkit.Rows("4 4 <nil>"))
+	tk.MustQuery(statsSQL).Check(testkit.Rows(
+		"test t b 3 0",
+		"test t2 d 3 2",
+	))
+	tk.MustExec("update t set a = a where id > 10")
+	tk.MustQuery(statsSQL).Check(testkit.Rows("test t a 0 0"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+
+	tk.MustExec("set @@tidb_collect_update_column_stats = off")
+	tk.MustExec("update t set a = a + 1")
+	tk.MustQuery(statsSQL).Check(testkit.Rows("test t a 0 0"))
+}
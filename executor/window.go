@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/mathutil"
+	"golang.org/x/sync/errgroup"
 )
 
 // WindowExec is the executor for window functions.
@@ -233,12 +234,93 @@ func (p *aggWindowProcessor) resetPartialResult() {
 	}
 }
 
+// frameExcluder removes the rows excluded by the EXCLUDE clause from the window frame.
+type frameExcluder struct {
+	exclusion   ast.FrameExclusion
+	orderByCols []*expression.Column
+	cmpFuncs    []expression.CompareFunc
+	frameRows   []chunk.Row
+}
+
+// newFrameExcluder returns nil if no rows are excluded from the frame.
+func newFrameExcluder(ctx sessionctx.Context, frame *core.WindowFrame, orderByCols []*expression.Column) *frameExcluder {
+	if frame == nil || frame.Exclusion == ast.ExcludeNoOthers {
+		return nil
+	}
+	cmpFuncs := make([]expression.CompareFunc, len(orderByCols))
+	for i, col := range orderByCols {
+		cmpFuncs[i] = expression.GetCmpFunction(ctx, col, col)
+	}
+	return &frameExcluder{
+		exclusion:   frame.Exclusion,
+		orderByCols: orderByCols,
+		cmpFuncs:    cmpFuncs,
+	}
+}
+
+// isPeer checks whether the two rows are equal on the ORDER BY columns.
+// All the rows of a partition are peers if there is no ORDER BY.
+func (e *frameExcluder) isPeer(ctx sessionctx.Context, lhs, rhs chunk.Row) (bool, error) {
+	for i, col := range e.orderByCols {
+		res, _, err := e.cmpFuncs[i](ctx, col, col, lhs, rhs)
+		if err != nil {
+			return false, err
+		}
+		if res != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// getFrameRows returns the rows in [start, end) which are not excluded for the current row.
+func (e *frameExcluder) getFrameRows(ctx sessionctx.Context, getRow func(uint64) chunk.Row, curRowIdx, start, end uint64) ([]chunk.Row, error) {
+	e.frameRows = e.frameRows[:0]
+	curRow := getRow(curRowIdx)
+	for i := start; i < end; i++ {
+		if i == curRowIdx {
+			// The current row is only kept by EXCLUDE TIES.
+			if e.exclusion == ast.ExcludeTies {
+				e.frameRows = append(e.frameRows, curRow)
+			}
+			continue
+		}
+		row := getRow(i)
+		if e.exclusion != ast.ExcludeCurrentRow {
+			peer, err := e.isPeer(ctx, row, curRow)
+			if err != nil {
+				return nil, err
+			}
+			if peer {
+				continue
+			}
+		}
+		e.frameRows = append(e.frameRows, row)
+	}
+	return e.frameRows, nil
+}
+
+// getFrameRows returns the rows in the frame [start, end) of the current row, excluder can be nil.
+func getFrameRows(ctx sessionctx.Context, excluder *frameExcluder, rows []chunk.Row, curRowIdx, start, end uint64) ([]chunk.Row, error) {
+	if start >= end {
+		return nil, nil
+	}
+	if excluder == nil {
+		return rows[start:end], nil
+	}
+	return excluder.getFrameRows(ctx, func(i uint64) chunk.Row {
+		return rows[i]
+	}, curRowIdx, start, end)
+}
+
 type rowFrameWindowProcessor struct {
 	windowFuncs    []aggfuncs.AggFunc
 	partialResults []aggfuncs.PartialResult
 	start          *core.FrameBound
 	end            *core.FrameBound
 	curRowIdx      uint64
+	// excluder is nil if the frame doesn't exclude any rows.
+	excluder *frameExcluder
 }
 
 func (p *rowFrameWindowProcessor) getStartOffset(numRows uint64) uint64 {
@@ -302,16 +384,24 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 		lastEnd                  uint64
 		shiftStart               uint64
 		shiftEnd                 uint64
+		frameRows                []chunk.Row
 	)
 	slidingWindowAggFuncs := make([]aggfuncs.SlidingWindowAggFunc, len(p.windowFuncs))
-	for i, windowFunc := range p.windowFuncs {
-		if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
-			slidingWindowAggFuncs[i] = slidingWindowAggFunc
+	// The excluded rows break the continuity of the frame, so the window can't be slid.
+	if p.excluder == nil {
+		for i, windowFunc := range p.windowFuncs {
+			if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
+				slidingWindowAggFuncs[i] = slidingWindowAggFunc
+			}
 		}
 	}
 	for ; remained > 0; lastStart, lastEnd = start, end {
 		start = p.getStartOffset(numRows)
 		end = p.getEndOffset(numRows)
+		frameRows, err = getFrameRows(ctx, p.excluder, rows, p.curRowIdx, start, end)
+		if err != nil {
+			return nil, err
+		}
 		p.curRowIdx++
 		remained--
 		shiftStart = start - lastStart
@@ -348,7 +438,7 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 					// Store start inside MaxMinSlidingWindowAggFunc.windowInfo
 					minMaxSlidingWindowAggFunc.SetWindowStart(start)
 				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
+				_, err = windowFunc.UpdatePartialResult(ctx, frameRows, p.partialResults[i])
 			}
 			if err != nil {
 				return nil, err
@@ -386,6 +476,8 @@ type rangeFrameWindowProcessor struct {
 	orderByCols     []*expression.Column
 	// expectedCmpResult is used to decide if one value is included in the frame.
 	expectedCmpResult int64
+	// excluder is nil if the frame doesn't exclude any rows.
+	excluder *frameExcluder
 }
 
 func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
@@ -450,11 +542,15 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 		lastEnd                  uint64
 		shiftStart               uint64
 		shiftEnd                 uint64
+		frameRows                []chunk.Row
 	)
 	slidingWindowAggFuncs := make([]aggfuncs.SlidingWindowAggFunc, len(p.windowFuncs))
-	for i, windowFunc := range p.windowFuncs {
-		if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
-			slidingWindowAggFuncs[i] = slidingWindowAggFunc
+	// The excluded rows break the continuity of the frame, so the window can't be slid.
+	if p.excluder == nil {
+		for i, windowFunc := range p.windowFuncs {
+			if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
+				slidingWindowAggFuncs[i] = slidingWindowAggFunc
+			}
 		}
 	}
 	for ; remained > 0; lastStart, lastEnd = start, end {
@@ -466,6 +562,10 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 		if err != nil {
 			return nil, err
 		}
+		frameRows, err = getFrameRows(ctx, p.excluder, rows, p.curRowIdx, start, end)
+		if err != nil {
+			return nil, err
+		}
 		p.curRowIdx++
 		remained--
 		shiftStart = start - lastStart
@@ -499,7 +599,7 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 				if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
 					minMaxSlidingWindowAggFunc.SetWindowStart(start)
 				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
+				_, err = windowFunc.UpdatePartialResult(ctx, frameRows, p.partialResults[i])
 			}
 			if err != nil {
 				return nil, err
@@ -531,3 +631,66 @@ func (p *rangeFrameWindowProcessor) resetPartialResult() {
 	p.lastStartOffset = 0
 	p.lastEndOffset = 0
 }
+
+// parallelWindowMinRows is the minimum number of rows of a partition to evaluate the window functions in parallel,
+// the small partitions are not worth the cost of the goroutines.
+const parallelWindowMinRows = 1024
+
+// parallelWindowProcessor evaluates each window function by its own processor. The processors of different window
+// functions share the rows of the partition and append the results to different columns of the chunk, so they can
+// be run in parallel without any synchronization.
+type parallelWindowProcessor struct {
+	processors  []windowProcessor
+	concurrency int
+}
+
+func (p *parallelWindowProcessor) run(rows []chunk.Row, f func(processor windowProcessor) ([]chunk.Row, error)) ([]chunk.Row, error) {
+	results := make([][]chunk.Row, len(p.processors))
+	if len(rows) < parallelWindowMinRows {
+		for i, processor := range p.processors {
+			res, err := f(processor)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = res
+		}
+		return results[0], nil
+	}
+	var eg errgroup.Group
+	eg.SetLimit(p.concurrency)
+	for i, processor := range p.processors {
+		i, processor := i, processor
+		eg.Go(func() (err error) {
+			defer func() {
+				if r := recover(); r != nil && err == nil {
+					err = errors.Errorf("%v", r)
+				}
+			}()
+			results[i], err = f(processor)
+			return err
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	// All the processors are of the same kind, so they return the same rows.
+	return results[0], nil
+}
+
+func (p *parallelWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
+	return p.run(rows, func(processor windowProcessor) ([]chunk.Row, error) {
+		return processor.consumeGroupRows(ctx, rows)
+	})
+}
+
+func (p *parallelWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+	return p.run(rows, func(processor windowProcessor) ([]chunk.Row, error) {
+		return processor.appendResult2Chunk(ctx, rows, chk, remained)
+	})
+}
+
+func (p *parallelWindowProcessor) resetPartialResult() {
+	for _, processor := range p.processors {
+		processor.resetPartialResult()
+	}
+}
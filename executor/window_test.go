@@ -16,9 +16,11 @@ package executor_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWindowFunctions(t *testing.T) {
@@ -43,6 +45,79 @@ func TestWindowParallelFunctions(t *testing.T) {
 	doTestWindowFunctions(tk)
 }
 
+func TestWindowParallelFunctionsLargePartition(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_pipelined_window_function = 0")
+	defer func() {
+		tk.MustExec("set @@tidb_enable_pipelined_window_function=1;")
+	}()
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int)")
+	var sb strings.Builder
+	sb.WriteString("insert into t values ")
+	for i := 0; i < 3000; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(fmt.Sprintf("(%d, %d)", i%3, (i*7919)%3001))
+	}
+	tk.MustExec(sb.String())
+
+	sqls := []string{
+		// The window functions without frame.
+		"select a, b, sum(b) over w, count(*) over w, max(b) over w from t window w as () order by b",
+		// The window functions with rows frame.
+		"select a, b, sum(b) over w, avg(b) over w, max(b) over w, min(b) over w from t window w as (order by b rows between 3 preceding and 2 following) order by b",
+		// The window functions with range frame.
+		"select a, b, row_number() over w, rank() over w, sum(b) over w, lead(b) over w, count(a) over w from t window w as (order by a, b) order by b",
+		"select a, b, sum(b) over w, first_value(b) over w, last_value(b) over w from t window w as (order by b range between 10 preceding and 5 following) order by b",
+	}
+	tk.MustExec("set @@tidb_window_concurrency = 4")
+	for _, sql := range sqls {
+		tk.MustExec("set @@tidb_enable_parallel_window_function = 0")
+		expected := tk.MustQuery(sql).Rows()
+		require.Len(t, expected, 3000)
+		tk.MustExec("set @@tidb_enable_parallel_window_function = 1")
+		tk.MustQuery(sql).Check(expected)
+	}
+}
+
+func TestWindowParallelBuildWorkers(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int)")
+	var sb strings.Builder
+	sb.WriteString("insert into t values ")
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(fmt.Sprintf("(%d, %d)", i%37, (i*7919)%1009))
+	}
+	tk.MustExec(sb.String())
+	tk.MustExec("analyze table t")
+	tk.MustExec("set @@tidb_enable_pipelined_window_function = 0")
+	defer func() {
+		tk.MustExec("set @@tidb_enable_pipelined_window_function = 1")
+	}()
+
+	// Every one of the 37 workers of the shuffle builds its own window and sort, by at most 4 goroutines.
+	sql := "select a, b, rank() over w, row_number() over w from t window w as (partition by a order by b)"
+	tk.MustExec("set @@tidb_window_concurrency = 1")
+	expected := tk.MustQuery(sql).Sort().Rows()
+	require.Len(t, expected, 1000)
+	tk.MustExec("set @@tidb_window_concurrency = 64")
+	tk.MustExec("set @@tidb_executor_concurrency = 4")
+	require.True(t, tk.HasPlan(sql, "Shuffle"))
+	for i := 0; i < 3; i++ {
+		tk.MustQuery(sql).Sort().Check(expected)
+	}
+}
+
 func TestPipelinedWindowFunctions(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -498,3 +573,57 @@ func TestIssue29947(t *testing.T) {
 	result.Check(testkit.Rows("2", "3"))
 	tk.MustExec("commit")
 }
+
+func TestWindowFrameExclusion(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, a int, b int)")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 1, 2), (3, 1, 2), (4, 1, 3), (5, 2, 5), (6, 2, 5)")
+	defer func() {
+		tk.MustExec("set @@tidb_enable_pipelined_window_function = 1")
+		tk.MustExec("set @@tidb_window_concurrency = -1")
+	}()
+
+	rowsFrame := "partition by a order by b rows between 1 preceding and 1 following"
+	rangeFrame := "partition by a order by b range between 1 preceding and 1 following"
+	for _, pipelined := range []int{0, 1} {
+		for _, concurrency := range []int{1, 4} {
+			tk.MustExec(fmt.Sprintf("set @@tidb_enable_pipelined_window_function = %d", pipelined))
+			tk.MustExec(fmt.Sprintf("set @@tidb_window_concurrency = %d", concurrency))
+
+			tk.MustQuery("select b, sum(b) over (" + rowsFrame + ") from t").Sort().
+				Check(testkit.Rows("1 3", "2 5", "2 7", "3 5", "5 10", "5 10"))
+			tk.MustQuery("select b, sum(b) over (" + rowsFrame + " exclude no others) from t").Sort().
+				Check(testkit.Rows("1 3", "2 5", "2 7", "3 5", "5 10", "5 10"))
+			tk.MustQuery("select b, sum(b) over (" + rowsFrame + " exclude current row) from t").Sort().
+				Check(testkit.Rows("1 2", "2 3", "2 5", "3 2", "5 5", "5 5"))
+			tk.MustQuery("select b, sum(b) over (" + rowsFrame + " exclude group) from t").Sort().
+				Check(testkit.Rows("1 2", "2 1", "2 3", "3 2", "5 <nil>", "5 <nil>"))
+			tk.MustQuery("select b, sum(b) over (" + rowsFrame + " exclude ties) from t").Sort().
+				Check(testkit.Rows("1 3", "2 3", "2 5", "3 5", "5 5", "5 5"))
+
+			tk.MustQuery("select b, sum(b) over (" + rangeFrame + ") from t").Sort().
+				Check(testkit.Rows("1 5", "2 8", "2 8", "3 7", "5 10", "5 10"))
+			tk.MustQuery("select b, sum(b) over (" + rangeFrame + " exclude current row) from t").Sort().
+				Check(testkit.Rows("1 4", "2 6", "2 6", "3 4", "5 5", "5 5"))
+			tk.MustQuery("select b, sum(b) over (" + rangeFrame + " exclude group), max(b) over (" + rangeFrame + " exclude group) from t").Sort().
+				Check(testkit.Rows("1 4 2", "2 4 3", "2 4 3", "3 4 2", "5 <nil> <nil>", "5 <nil> <nil>"))
+			tk.MustQuery("select b, sum(b) over (" + rangeFrame + " exclude ties) from t").Sort().
+				Check(testkit.Rows("1 5", "2 6", "2 6", "3 7", "5 5", "5 5"))
+
+			// The frame covering the whole partition is kept when some rows are excluded.
+			tk.MustQuery("select b, count(*) over (partition by a order by b range between unbounded preceding and unbounded following exclude current row) from t").Sort().
+				Check(testkit.Rows("1 3", "2 3", "2 3", "3 3", "5 1", "5 1"))
+			// All the rows of the partition are peers without ORDER BY.
+			tk.MustQuery("select a, count(*) over (partition by a rows between unbounded preceding and unbounded following exclude group), " +
+				"count(*) over (partition by a rows between unbounded preceding and unbounded following exclude ties) from t").Sort().
+				Check(testkit.Rows("1 0 1", "1 0 1", "1 0 1", "1 0 1", "2 0 1", "2 0 1"))
+			tk.MustQuery("select b, last_value(b) over (partition by a order by b rows between unbounded preceding and current row exclude current row) from t").Sort().
+				Check(testkit.Rows("1 <nil>", "2 1", "2 2", "3 2", "5 5", "5 <nil>"))
+		}
+	}
+	require.Contains(t, fmt.Sprint(tk.MustQuery("explain select sum(b) over ("+rangeFrame+" exclude ties) from t").Rows()),
+		"range between 1 preceding and 1 following exclude ties")
+}
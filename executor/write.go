@@ -27,10 +27,12 @@ import (
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/tracing"
@@ -299,3 +301,60 @@ func resetErrDataTooLong(colName string, rowIdx int, err error) error {
 	newErr := types.ErrDataTooLong.GenWithStack("Data too long for column '%v' at row %v", colName, rowIdx)
 	return newErr
 }
+
+// dmlReturning evaluates the RETURNING clause of UPDATE and DELETE on the modified rows, the results are buffered
+// and returned to the client after the statement is executed.
+type dmlReturning struct {
+	exprs  []expression.Expression
+	names  types.NameSlice
+	output chunk.MutRow
+	rows   *chunk.List
+}
+
+func newDMLReturning(returning *plannercore.DMLReturning, maxChunkSize int) *dmlReturning {
+	fieldTypes := make([]*types.FieldType, 0, returning.Schema.Len())
+	for _, col := range returning.Schema.Columns {
+		fieldTypes = append(fieldTypes, col.RetType)
+	}
+	return &dmlReturning{
+		exprs:  returning.Exprs,
+		names:  returning.Names,
+		output: chunk.MutRowFromTypes(fieldTypes),
+		rows:   chunk.NewList(fieldTypes, maxChunkSize, maxChunkSize),
+	}
+}
+
+// appendRow evaluates the RETURNING clause on the row, which is laid out as the schema of the SelectPlan.
+func (r *dmlReturning) appendRow(row chunk.Row) error {
+	for i, expr := range r.exprs {
+		d, err := expr.Eval(row)
+		if err != nil {
+			return err
+		}
+		r.output.SetDatum(i, d)
+	}
+	r.rows.AppendRow(r.output.ToRow())
+	return nil
+}
+
+func (r *dmlReturning) resultRows() []chunk.Row {
+	rows := make([]chunk.Row, 0, r.rows.Len())
+	for i := 0; i < r.rows.NumChunks(); i++ {
+		chk := r.rows.GetChunk(i)
+		for j := 0; j < chk.NumRows(); j++ {
+			rows = append(rows, chk.GetRow(j))
+		}
+	}
+	return rows
+}
+
+// getDMLReturning returns the RETURNING clause of the executor, or nil if it doesn't have one.
+func getDMLReturning(e Executor) *dmlReturning {
+	switch x := e.(type) {
+	case *DeleteExec:
+		return x.returning
+	case *UpdateExec:
+		return x.returning
+	}
+	return nil
+}
@@ -163,6 +163,10 @@ func (a *AggFuncDesc) Split(ordinal []int) (partialAggDesc, finalAggDesc *AggFun
 		if finalAggDesc.Name == ast.AggFuncGroupConcat || finalAggDesc.Name == ast.AggFuncApproxPercentile {
 			finalAggDesc.Args = append(finalAggDesc.Args, a.Args[len(a.Args)-1]) // separator
 		}
+		if finalAggDesc.Name == ast.AggFuncGroupConcat {
+			// The final group_concat merges the partial results in order.
+			finalAggDesc.OrderByItems = a.OrderByItems
+		}
 	}
 	return
 }
@@ -630,6 +630,28 @@ func (is *SessionTables) Count() int {
 	return len(is.idx2table)
 }
 
+// SchemaNames returns the names of the schemas which contain the tables.
+func (is *SessionTables) SchemaNames() []model.CIStr {
+	names := make([]model.CIStr, 0, len(is.schemaMap))
+	for _, v := range is.schemaMap {
+		names = append(names, v.dbInfo.Name)
+	}
+	return names
+}
+
+// SchemaTables returns the tables of the schema.
+func (is *SessionTables) SchemaTables(schema model.CIStr) []table.Table {
+	tbls := is.schemaTables(schema)
+	if tbls == nil {
+		return nil
+	}
+	tables := make([]table.Table, 0, len(tbls.tables))
+	for _, tbl := range tbls.tables {
+		tables = append(tables, tbl)
+	}
+	return tables
+}
+
 // SchemaByTable get a table's schema name
 func (is *SessionTables) SchemaByTable(tableInfo *model.TableInfo) (*model.DBInfo, bool) {
 	if tableInfo == nil {
@@ -297,6 +297,12 @@ func TestInfoTables(t *testing.T) {
 		"PLACEMENT_POLICIES",
 		"TRX_SUMMARY",
 		"RESOURCE_GROUPS",
+		"CONNECTION_RESOURCE_USAGE",
+		"CLUSTER_PLAN_CACHE",
+		"TIKV_GC_STATUS",
+		"SESSION_TEMPORARY_TABLES",
+		"COPROCESSOR_CACHE",
+		"LAST_UPDATE_COLUMN_STATS",
 	}
 	for _, tbl := range infoTables {
 		tb, err1 := is.TableByName(util.InformationSchemaName, model.NewCIStr(tbl))
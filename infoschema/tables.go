@@ -202,6 +202,18 @@ const (
 	TableMemoryUsageOpsHistory = "MEMORY_USAGE_OPS_HISTORY"
 	// TableResourceGroups is the metadata of resource groups.
 	TableResourceGroups = "RESOURCE_GROUPS"
+	// TableConnectionResourceUsage is the resource usage of the connections of tidb instance.
+	TableConnectionResourceUsage = "CONNECTION_RESOURCE_USAGE"
+	// TableClusterPlanCache is the plan cache occupancy of all tidb instances.
+	TableClusterPlanCache = "CLUSTER_PLAN_CACHE"
+	// TableTiKVGCStatus is the GC status of the TiKV stores.
+	TableTiKVGCStatus = "TIKV_GC_STATUS"
+	// TableSessionTemporaryTables is the local temporary tables of the current session.
+	TableSessionTemporaryTables = "SESSION_TEMPORARY_TABLES"
+	// TableCoprocessorCache is the coprocessor cache statistics of all tidb instances.
+	TableCoprocessorCache = "COPROCESSOR_CACHE"
+	// TableLastUpdateColumnStats is the per-column changed rows of the last UPDATE statement in the current session.
+	TableLastUpdateColumnStats = "LAST_UPDATE_COLUMN_STATS"
 )
 
 const (
@@ -309,6 +321,12 @@ var tableIDMap = map[string]int64{
 	ClusterTableMemoryUsage:              autoid.InformationSchemaDBID + 86,
 	ClusterTableMemoryUsageOpsHistory:    autoid.InformationSchemaDBID + 87,
 	TableResourceGroups:                  autoid.InformationSchemaDBID + 88,
+	TableConnectionResourceUsage:         autoid.InformationSchemaDBID + 89,
+	TableClusterPlanCache:                autoid.InformationSchemaDBID + 90,
+	TableTiKVGCStatus:                    autoid.InformationSchemaDBID + 91,
+	TableSessionTemporaryTables:          autoid.InformationSchemaDBID + 92,
+	TableCoprocessorCache:                autoid.InformationSchemaDBID + 93,
+	TableLastUpdateColumnStats:           autoid.InformationSchemaDBID + 94,
 }
 
 // columnInfo represents the basic column information of all kinds of INFORMATION_SCHEMA tables
@@ -1604,6 +1622,59 @@ var tableResourceGroupsCols = []columnInfo{
 	{name: "QUERY_LIMIT", tp: mysql.TypeVarchar, size: 256},
 }
 
+var tableConnectionResourceUsageCols = []columnInfo{
+	{name: "ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, deflt: 0},
+	{name: "USER", tp: mysql.TypeVarchar, size: 16, flag: mysql.NotNullFlag, deflt: ""},
+	{name: "HOST", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag, deflt: ""},
+	{name: "RESOURCE_GROUP", tp: mysql.TypeVarchar, size: resourcegroup.MaxGroupNameLength, flag: mysql.NotNullFlag, deflt: ""},
+	{name: "STMT_RU", tp: mysql.TypeDouble, size: 22, comment: "The RU consumed by the current or last statement"},
+	{name: "TOTAL_RU", tp: mysql.TypeDouble, size: 22, comment: "The RU consumed by the connection"},
+	{name: "STMT_MAX_MEM", tp: mysql.TypeLonglong, size: 21, comment: "The peak memory usage of the current or last statement"},
+}
+
+var tableClusterPlanCacheCols = []columnInfo{
+	{name: "INSTANCE", tp: mysql.TypeVarchar, size: 64},
+	{name: "ENTRY_COUNT", tp: mysql.TypeLonglong, size: 21, comment: "The number of the cached plans"},
+	{name: "MEMORY_USAGE", tp: mysql.TypeLonglong, size: 21, comment: "The memory used by the cached plans"},
+	{name: "HIT_RATE", tp: mysql.TypeDouble, size: 22, comment: "The ratio of the plan cache hits to the plan cache lookups"},
+	{name: "EVICTION_COUNT", tp: mysql.TypeLonglong, size: 21, comment: "The number of the plans evicted from the plan cache"},
+}
+
+var tableTiKVGCStatusCols = []columnInfo{
+	{name: "SAFE_POINT", tp: mysql.TypeDatetime, size: 19, comment: "The GC safe point saved by the GC worker, the MVCC versions older than it can be removed"},
+	{name: "STORE_SAFE_POINT", tp: mysql.TypeDatetime, size: 19, comment: "The GC safe point in PD, which the stores have been allowed to GC to"},
+	{name: "LAST_RUN_TIME", tp: mysql.TypeDatetime, size: 19, comment: "The time when the last GC started"},
+	{name: "LIFE_TIME", tp: mysql.TypeVarchar, size: 64},
+	{name: "RUN_INTERVAL", tp: mysql.TypeVarchar, size: 64},
+	{name: "LEADER", tp: mysql.TypeVarchar, size: 256, comment: "The host name and pid of the GC leader"},
+	{name: "UP_STORES", tp: mysql.TypeLonglong, size: 21, comment: "The number of the TiKV stores which are up and run GC"},
+}
+
+var tableSessionTemporaryTablesCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "TIDB_TABLE_ID", tp: mysql.TypeLonglong, size: 21},
+	{name: "TABLE_ROWS", tp: mysql.TypeLonglong, size: 21, comment: "The number of the committed rows"},
+	{name: "MEMORY_USAGE", tp: mysql.TypeLonglong, size: 21, comment: "The approximate memory used by the committed data in bytes"},
+}
+
+var tableCoprocessorCacheCols = []columnInfo{
+	{name: "INSTANCE", tp: mysql.TypeVarchar, size: 64},
+	{name: "CACHE_SIZE", tp: mysql.TypeLonglong, size: 21, comment: "The size of the cached responses in bytes"},
+	{name: "ENTRY_COUNT", tp: mysql.TypeLonglong, size: 21, comment: "The number of the cached responses"},
+	{name: "HIT_COUNT", tp: mysql.TypeLonglong, size: 21, comment: "The number of the coprocessor requests served by the cache"},
+	{name: "MISS_COUNT", tp: mysql.TypeLonglong, size: 21, comment: "The number of the cacheable coprocessor requests missing the cache"},
+	{name: "EVICTION_COUNT", tp: mysql.TypeLonglong, size: 21, comment: "The number of the responses evicted from the cache"},
+}
+
+var tableLastUpdateColumnStatsCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "COLUMN_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "ASSIGNED_ROWS", tp: mysql.TypeLonglong, size: 21, comment: "The number of the updated rows which assign the column"},
+	{name: "CHANGED_ROWS", tp: mysql.TypeLonglong, size: 21, comment: "The number of the updated rows whose value of the column is changed"},
+}
+
 // GetShardingInfo returns a nil or description string for the sharding information of given TableInfo.
 // The returned description string may be:
 //   - "NOT_SHARDED": for tables that SHARD_ROW_ID_BITS is not specified.
@@ -2085,6 +2156,12 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableMemoryUsage:                        tableMemoryUsageCols,
 	TableMemoryUsageOpsHistory:              tableMemoryUsageOpsHistoryCols,
 	TableResourceGroups:                     tableResourceGroupsCols,
+	TableConnectionResourceUsage:            tableConnectionResourceUsageCols,
+	TableClusterPlanCache:                   tableClusterPlanCacheCols,
+	TableTiKVGCStatus:                       tableTiKVGCStatusCols,
+	TableSessionTemporaryTables:             tableSessionTemporaryTablesCols,
+	TableCoprocessorCache:                   tableCoprocessorCacheCols,
+	TableLastUpdateColumnStats:              tableLastUpdateColumnStatsCols,
 }
 
 func createInfoSchemaTable(_ autoid.Allocators, meta *model.TableInfo) (table.Table, error) {
@@ -558,6 +558,9 @@ type Request struct {
 	ClosestReplicaReadAdjuster CoprRequestAdjuster
 	// MatchStoreLabels indicates the labels the store should be matched
 	MatchStoreLabels []*metapb.StoreLabel
+	// AvoidStores indicates the addresses of the stores that the request should keep away from,
+	// it only takes effect when the request can be served by the followers.
+	AvoidStores []string
 	// ResourceGroupTagger indicates the kv request task group tagger.
 	ResourceGroupTagger tikvrpc.ResourceGroupTagger
 	// Paging indicates whether the request is a paging request.
@@ -578,6 +581,9 @@ type Request struct {
 	LimitSize uint64
 	// StoreBusyThreshold is the threshold for the store to return ServerIsBusy
 	StoreBusyThreshold time.Duration
+	// RangesPerTask limits the number of ranges sent in one cop task if it's positive, so the ranges
+	// located in the same region can be sent by multiple cop tasks in parallel.
+	RangesPerTask int
 
 	// ConnID stores the session connection id.
 	ConnID uint64
@@ -25,6 +25,7 @@ var (
 	DistSQLScanKeysHistogram        prometheus.Histogram
 	DistSQLPartialCountHistogram    prometheus.Histogram
 	DistSQLCoprCacheCounter         *prometheus.CounterVec
+	DistSQLCoprCacheUsage           *prometheus.GaugeVec
 	DistSQLCoprClosestReadCounter   *prometheus.CounterVec
 	DistSQLCoprRespBodySize         *prometheus.HistogramVec
 )
@@ -75,6 +76,14 @@ func InitDistSQLMetrics() {
 			Help:      "coprocessor cache hit, evict and miss number",
 		}, []string{LblType})
 
+	DistSQLCoprCacheUsage = NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "distsql",
+			Name:      "copr_cache_usage",
+			Help:      "coprocessor cache size in bytes and number of entries",
+		}, []string{LblType})
+
 	DistSQLCoprClosestReadCounter = NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "tidb",
@@ -49,6 +49,7 @@ var (
 	PlanCacheMissCounter            *prometheus.CounterVec
 	PlanCacheInstanceMemoryUsage    *prometheus.GaugeVec
 	PlanCacheInstancePlanNumCounter *prometheus.GaugeVec
+	PlanCacheEvictionCounter        *prometheus.CounterVec
 	ReadFromTableCacheCounter       prometheus.Counter
 	HandShakeErrorCounter           prometheus.Counter
 	GetTokenDurationHistogram       prometheus.Histogram
@@ -193,6 +194,14 @@ func InitServerMetrics() {
 			Help:      "Counter of plan of all prepared plan cache in a instance",
 		}, []string{LblType})
 
+	PlanCacheEvictionCounter = NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "plan_cache_eviction_total",
+			Help:      "Counter of plan evicted from the plan cache of all sessions in a instance",
+		}, []string{LblType})
+
 	ReadFromTableCacheCounter = NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "tidb",
@@ -2451,6 +2451,8 @@ type DeleteStmt struct {
 	// TableHints represents the table level Optimizer Hint for join type.
 	TableHints []*TableOptimizerHint
 	With       *WithClause
+	// Returning is only used in single table delete statement, the fields are evaluated on the deleted rows.
+	Returning *FieldList
 }
 
 // Restore implements Node interface.
@@ -2541,6 +2543,13 @@ func (n *DeleteStmt) Restore(ctx *format.RestoreCtx) error {
 		}
 	}
 
+	if n.Returning != nil {
+		ctx.WriteKeyWord(" RETURNING ")
+		if err := n.Returning.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore DeleteStmt.Returning")
+		}
+	}
+
 	return nil
 }
 
@@ -2594,6 +2603,13 @@ func (n *DeleteStmt) Accept(v Visitor) (Node, bool) {
 		}
 		n.Limit = node.(*Limit)
 	}
+	if n.Returning != nil {
+		node, ok = n.Returning.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Returning = node.(*FieldList)
+	}
 	return v.Leave(n)
 }
 
@@ -2703,6 +2719,8 @@ type UpdateStmt struct {
 	MultipleTable bool
 	TableHints    []*TableOptimizerHint
 	With          *WithClause
+	// Returning is only used in single table update statement, the fields are evaluated on the updated rows.
+	Returning *FieldList
 }
 
 // Restore implements Node interface.
@@ -2782,6 +2800,13 @@ func (n *UpdateStmt) Restore(ctx *format.RestoreCtx) error {
 		}
 	}
 
+	if n.Returning != nil {
+		ctx.WriteKeyWord(" RETURNING ")
+		if err := n.Returning.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occur while restore UpdateStmt.Returning")
+		}
+	}
+
 	return nil
 }
 
@@ -2832,6 +2857,13 @@ func (n *UpdateStmt) Accept(v Visitor) (Node, bool) {
 		}
 		n.Limit = node.(*Limit)
 	}
+	if n.Returning != nil {
+		node, ok = n.Returning.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Returning = node.(*FieldList)
+	}
 	return v.Leave(n)
 }
 
@@ -3000,6 +3032,7 @@ type ShowStmt struct {
 	Roles             []*auth.RoleIdentity // Used for show grants .. using
 	IfNotExists       bool                 // Used for `show create database if not exists`
 	Extended          bool                 // Used for `show extended columns from ...`
+	LiveStats         bool                 // Used for `show index from ... with live stats`
 	Limit             *Limit               // Used for partial Show STMTs to limit Result Set row numbers.
 
 	CountWarningsOrErrors bool // Used for showing count(*) warnings | errors
@@ -3258,6 +3291,9 @@ func (n *ShowStmt) Restore(ctx *format.RestoreCtx) error {
 			if err := n.Table.Restore(ctx); err != nil {
 				return errors.Annotate(err, "An error occurred while restore ShowStmt.Table")
 			} // TODO: remember to check this case
+			if n.LiveStats {
+				ctx.WriteKeyWord(" WITH LIVE STATS")
+			}
 		case ShowColumns: // equivalent to SHOW FIELDS
 			if n.Extended {
 				ctx.WriteKeyWord("EXTENDED ")
@@ -3534,6 +3570,10 @@ type SelectIntoOption struct {
 	FileName   string
 	FieldsInfo *FieldsClause
 	LinesInfo  *LinesClause
+	// WithHeader indicates whether the column names are written as the first line of the file.
+	WithHeader bool
+	// Compression is the codec to compress the file, such as gzip, zstd and snappy.
+	Compression string
 }
 
 // Restore implements Node interface.
@@ -3555,6 +3595,13 @@ func (n *SelectIntoOption) Restore(ctx *format.RestoreCtx) error {
 			return errors.Annotate(err, "An error occurred while restore SelectInto.LinesInfo")
 		}
 	}
+	if n.WithHeader {
+		ctx.WriteKeyWord(" WITH HEADER")
+	}
+	if n.Compression != "" {
+		ctx.WriteKeyWord(" COMPRESSION ")
+		ctx.WriteString(n.Compression)
+	}
 	return nil
 }
 
@@ -3620,8 +3667,9 @@ const (
 type FrameClause struct {
 	node
 
-	Type   FrameType
-	Extent FrameExtent
+	Type      FrameType
+	Extent    FrameExtent
+	Exclusion FrameExclusion
 }
 
 // Restore implements Node interface.
@@ -3642,6 +3690,17 @@ func (n *FrameClause) Restore(ctx *format.RestoreCtx) error {
 	if err := n.Extent.End.Restore(ctx); err != nil {
 		return errors.Annotate(err, "An error occurred while restore FrameClause.Extent.End")
 	}
+	switch n.Exclusion {
+	case ExcludeNoOthers:
+	case ExcludeCurrentRow:
+		ctx.WriteKeyWord(" EXCLUDE CURRENT ROW")
+	case ExcludeGroup:
+		ctx.WriteKeyWord(" EXCLUDE GROUP")
+	case ExcludeTies:
+		ctx.WriteKeyWord(" EXCLUDE TIES")
+	default:
+		return errors.New("Unsupported window function frame exclusion")
+	}
 
 	return nil
 }
@@ -3666,6 +3725,18 @@ func (n *FrameClause) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// FrameExclusion is the exclusion option of window function frame.
+type FrameExclusion int
+
+// Frame exclusion options.
+// `EXCLUDE NO OTHERS` is the default and excludes nothing.
+const (
+	ExcludeNoOthers = iota
+	ExcludeCurrentRow
+	ExcludeGroup
+	ExcludeTies
+)
+
 // FrameExtent represents frame extent.
 type FrameExtent struct {
 	Start FrameBound
@@ -272,6 +272,8 @@ type PlanReplayerStmt struct {
 	Capture bool
 	// Remove indicates `plan replayer capture remove <sql_digest> <plan_digest>
 	Remove bool
+	// Reset indicates `plan replayer capture reset`, which removes all the capture tasks.
+	Reset bool
 
 	SQLDigest  string
 	PlanDigest string
@@ -302,6 +304,10 @@ func (n *PlanReplayerStmt) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteString(n.PlanDigest)
 		return nil
 	}
+	if n.Reset {
+		ctx.WriteKeyWord("PLAN REPLAYER CAPTURE RESET")
+		return nil
+	}
 	if n.Remove {
 		ctx.WriteKeyWord("PLAN REPLAYER CAPTURE REMOVE ")
 		ctx.WriteString(n.SQLDigest)
@@ -433,7 +439,6 @@ func (n *CompactTableStmt) Restore(ctx *format.RestoreCtx) error {
 	}
 	if n.ReplicaKind != CompactReplicaKindAll {
 		ctx.WriteKeyWord(" ")
-		// Note: There is only TiFlash replica available now. TiKV will be added later.
 		ctx.WriteKeyWord(string(n.ReplicaKind))
 		ctx.WriteKeyWord(" REPLICA")
 	}
@@ -973,6 +978,13 @@ type KillStmt struct {
 	TiDBExtension bool
 
 	Expr ExprNode
+
+	// Digest is set when the grammar is "KILL [TIDB] QUERY DIGEST 'digest'", which terminates all the running
+	// queries whose SQL digest is Digest in the cluster.
+	Digest string
+	// Force is set when the grammar is "KILL [TIDB] [CONNECTION] connectionID FORCE". If the connection does not
+	// terminate in a grace period, its network connection is closed and its transaction is abandoned.
+	Force bool
 }
 
 // Restore implements Node interface.
@@ -984,7 +996,10 @@ func (n *KillStmt) Restore(ctx *format.RestoreCtx) error {
 	if n.Query {
 		ctx.WriteKeyWord(" QUERY")
 	}
-	if n.Expr != nil {
+	if n.Digest != "" {
+		ctx.WriteKeyWord(" DIGEST ")
+		ctx.WriteString(n.Digest)
+	} else if n.Expr != nil {
 		ctx.WriteKeyWord(" ")
 		if err := n.Expr.Restore(ctx); err != nil {
 			return errors.Trace(err)
@@ -992,6 +1007,9 @@ func (n *KillStmt) Restore(ctx *format.RestoreCtx) error {
 	} else {
 		ctx.WritePlainf(" %d", n.ConnectionID)
 	}
+	if n.Force {
+		ctx.WriteKeyWord(" FORCE")
+	}
 	return nil
 }
 
@@ -3182,6 +3200,8 @@ const (
 	BRIEOptionCSVNull
 	BRIEOptionCSVSeparator
 	BRIEOptionCSVTrimLastSeparators
+	// validate options
+	BRIEOptionValidate
 
 	BRIECSVHeaderIsColumns = ^uint64(0)
 )
@@ -3285,6 +3305,8 @@ func (kind BRIEOptionType) String() string {
 		return "UNTIL_TS"
 	case BRIEOptionGCTTL:
 		return "GC_TTL"
+	case BRIEOptionValidate:
+		return "VALIDATE"
 	default:
 		return ""
 	}
@@ -3311,6 +3333,10 @@ type BRIEOption struct {
 
 func (opt *BRIEOption) Restore(ctx *format.RestoreCtx) error {
 	ctx.WriteKeyWord(opt.Tp.String())
+	if opt.Tp == BRIEOptionValidate {
+		// VALIDATE is a flag without value.
+		return nil
+	}
 	ctx.WritePlain(" = ")
 	switch opt.Tp {
 	case BRIEOptionBackupTS, BRIEOptionLastBackupTS, BRIEOptionBackend, BRIEOptionOnDuplicate, BRIEOptionTiKVImporter, BRIEOptionCSVDelimiter, BRIEOptionCSVNull, BRIEOptionCSVSeparator, BRIEOptionFullBackupStorage, BRIEOptionRestoredTS, BRIEOptionStartTS, BRIEOptionUntilTS, BRIEOptionGCTTL:
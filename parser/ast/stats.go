@@ -42,6 +42,12 @@ type AnalyzeTableStmt struct {
 	// ColumnNames indicate the columns whose statistics need to be collected.
 	ColumnNames  []model.CIStr
 	ColumnChoice model.ColumnChoice
+	// HistogramOnly is set in "ANALYZE TABLE ... HISTOGRAM ONLY" statement, which refreshes the histograms
+	// only and keeps the existing TopN.
+	HistogramOnly bool
+	// DryRun is set in "ANALYZE TABLE ... DRY RUN" statement, which reports the sampling plan without collecting
+	// any statistics.
+	DryRun bool
 }
 
 // AnalyzeOptType is the type for analyze options.
@@ -147,6 +153,9 @@ func (n *AnalyzeTableStmt) Restore(ctx *format.RestoreCtx) error {
 			ctx.WriteName(columnName.O)
 		}
 	}
+	if n.HistogramOnly {
+		ctx.WriteKeyWord(" HISTOGRAM ONLY")
+	}
 	if n.IndexFlag {
 		ctx.WriteKeyWord(" INDEX")
 	}
@@ -168,6 +177,9 @@ func (n *AnalyzeTableStmt) Restore(ctx *format.RestoreCtx) error {
 			ctx.WritePlain(AnalyzeOptionString[opt.Type])
 		}
 	}
+	if n.DryRun {
+		ctx.WriteKeyWord(" DRY RUN")
+	}
 	return nil
 }
 
@@ -354,6 +354,7 @@ var tokenMap = map[string]int{
 	"EXEC_ELAPSED":             execElapsed,
 	"EXCEPT":                   except,
 	"EXCHANGE":                 exchange,
+	"EXCLUDE":                  exclude,
 	"EXCLUSIVE":                exclusive,
 	"EXECUTE":                  execute,
 	"EXISTS":                   exists,
@@ -400,6 +401,7 @@ var tokenMap = map[string]int{
 	"HASH":                     hash,
 	"HANDLER":                  handler,
 	"HAVING":                   having,
+	"HEADER":                   header,
 	"HELP":                     help,
 	"HIGH_PRIORITY":            highPriority,
 	"HISTORY":                  history,
@@ -484,6 +486,7 @@ var tokenMap = map[string]int{
 	"LINEAR":                   linear,
 	"LINES":                    lines,
 	"LIST":                     list,
+	"LIVE":                     live,
 	"LOAD":                     load,
 	"LOCAL":                    local,
 	"LOCALTIME":                localTime,
@@ -569,6 +572,7 @@ var tokenMap = map[string]int{
 	"OPTION":                   option,
 	"OPTIONAL":                 optional,
 	"OPTIONALLY":               optionally,
+	"OTHERS":                   others,
 	"OR":                       or,
 	"ORDER":                    order,
 	"OUT":                      out,
@@ -667,6 +671,7 @@ var tokenMap = map[string]int{
 	"RTREE":                    rtree,
 	"HYPO":                     hypo,
 	"RESUME":                   resume,
+	"RETURNING":                returning,
 	"RUN":                      run,
 	"RUNNING":                  running,
 	"S3":                       s3,
@@ -786,11 +791,13 @@ var tokenMap = map[string]int{
 	"TERMINATED":               terminated,
 	"TEXT":                     textType,
 	"THAN":                     than,
+	"TIES":                     ties,
 	"THEN":                     then,
 	"TIDB":                     tidb,
 	"TIDB_CURRENT_TSO":         tidbCurrentTSO,
 	"TIDB_JSON":                tidbJson,
 	"TIFLASH":                  tiFlash,
+	"TIKV":                     tiKV,
 	"TIKV_IMPORTER":            tikvImporter,
 	"TIME":                     timeType,
 	"TIMESTAMP":                timestampType,
@@ -847,6 +854,7 @@ var tokenMap = map[string]int{
 	"UTC_DATE":                 utcDate,
 	"UTC_TIME":                 utcTime,
 	"UTC_TIMESTAMP":            utcTimestamp,
+	"VALIDATE":                 validate,
 	"VALIDATION":               validation,
 	"VALUE":                    value,
 	"VALUES":                   values,
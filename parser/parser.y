@@ -418,6 +418,7 @@ import (
 	events                "EVENTS"
 	evolve                "EVOLVE"
 	exchange              "EXCHANGE"
+	exclude               "EXCLUDE"
 	exclusive             "EXCLUSIVE"
 	execute               "EXECUTE"
 	expansion             "EXPANSION"
@@ -439,6 +440,7 @@ import (
 	grants                "GRANTS"
 	handler               "HANDLER"
 	hash                  "HASH"
+	header                "HEADER"
 	help                  "HELP"
 	histogram             "HISTOGRAM"
 	history               "HISTORY"
@@ -519,6 +521,7 @@ import (
 	only                  "ONLY"
 	open                  "OPEN"
 	optional              "OPTIONAL"
+	others                "OTHERS"
 	packKeys              "PACK_KEYS"
 	pageSym               "PAGE"
 	parser                "PARSER"
@@ -568,6 +571,7 @@ import (
 	restore               "RESTORE"
 	restores              "RESTORES"
 	resume                "RESUME"
+	returning             "RETURNING"
 	reuse                 "REUSE"
 	reverse               "REVERSE"
 	role                  "ROLE"
@@ -638,6 +642,7 @@ import (
 	temptable             "TEMPTABLE"
 	textType              "TEXT"
 	than                  "THAN"
+	ties                  "TIES"
 	tikvImporter          "TIKV_IMPORTER"
 	timestampType         "TIMESTAMP"
 	timeType              "TIME"
@@ -658,6 +663,7 @@ import (
 	unicodeSym            "UNICODE"
 	unknown               "UNKNOWN"
 	user                  "USER"
+	validate              "VALIDATE"
 	validation            "VALIDATION"
 	value                 "VALUE"
 	variables             "VARIABLES"
@@ -806,6 +812,7 @@ import (
 	dry                        "DRY"
 	jobs                       "JOBS"
 	job                        "JOB"
+	live                       "LIVE"
 	nodeID                     "NODE_ID"
 	nodeState                  "NODE_STATE"
 	optimistic                 "OPTIMISTIC"
@@ -828,6 +835,7 @@ import (
 	telemetryID                "TELEMETRY_ID"
 	tidb                       "TIDB"
 	tiFlash                    "TIFLASH"
+	tiKV                       "TIKV"
 	topn                       "TOPN"
 	split                      "SPLIT"
 	width                      "WIDTH"
@@ -1206,6 +1214,7 @@ import (
 	FormatOpt                              "FORMAT 'SQL FILE'..."
 	OfTablesOpt                            "OF table_name [, ...]"
 	OptFull                                "Full or empty"
+	OptLiveStats                           "WITH LIVE STATS or empty"
 	OptTemporary                           "TEMPORARY or empty"
 	OptOrder                               "Optional ordering keyword: ASC/DESC. Default to ASC"
 	Order                                  "Ordering keyword: ASC or DESC"
@@ -1252,6 +1261,7 @@ import (
 	RequireList                            "require list for tls options"
 	RequireListElement                     "require list element for tls option"
 	ResourceGroupNameOption                "resource group name for user"
+	ReturningClauseOptional                "Optional RETURNING clause"
 	Rolename                               "Rolename"
 	RolenameComposed                       "Rolename that composed with more than 1 symbol"
 	RolenameList                           "RolenameList"
@@ -1276,6 +1286,8 @@ import (
 	SelectStmtFromTable                    "SELECT statement from table"
 	SelectStmtGroup                        "SELECT statement optional GROUP BY clause"
 	SelectStmtIntoOption                   "SELECT statement into clause"
+	SelectIntoHeaderOpt                    "SELECT INTO OUTFILE optional WITH HEADER clause"
+	SelectIntoCompressionOpt               "SELECT INTO OUTFILE optional COMPRESSION clause"
 	SequenceOption                         "Create sequence option"
 	SequenceOptionList                     "Create sequence option list"
 	SetRoleOpt                             "Set role options"
@@ -1380,6 +1392,7 @@ import (
 	OptWild                                "Optional Wildcard"
 	OptWindowOrderByClause                 "Optional ORDER BY clause in WINDOW"
 	OptWindowFrameClause                   "Optional FRAME clause in WINDOW"
+	OptWindowFrameExclusion                "Optional EXCLUDE clause in WINDOW frame"
 	OptWindowingClause                     "Optional OVER clause"
 	WindowingClause                        "OVER clause"
 	WindowClauseOptional                   "Optional WINDOW clause"
@@ -1461,6 +1474,7 @@ import (
 	CalibrateResourceWorkloadOption        "Calibrate Resource workload option"
 	AttributesOpt                          "Attributes options"
 	AllColumnsOrPredicateColumnsOpt        "all columns or predicate columns option"
+	AnalyzeDryRunOpt                       "analyze dry run option"
 	StatsOptionsOpt                        "Stats options"
 	DryRunOptions                          "Dry run options"
 	OptionalShardColumn                    "Optional shard column"
@@ -1560,6 +1574,7 @@ import (
 	Symbol                          "Constraint Symbol"
 	ProcedurceLabelOpt              "Optional Procedure label name"
 
+%precedence returning
 %precedence empty
 %precedence as
 %precedence placement
@@ -1681,6 +1696,13 @@ AlterTableStmt:
 			ReplicaKind: ast.CompactReplicaKindTiFlash,
 		}
 	}
+|	"ALTER" IgnoreOptional "TABLE" TableName "COMPACT" "TIKV" "REPLICA"
+	{
+		$$ = &ast.CompactTableStmt{
+			Table:       $4.(*ast.TableName),
+			ReplicaKind: ast.CompactReplicaKindTiKV,
+		}
+	}
 |	"ALTER" IgnoreOptional "TABLE" TableName "COMPACT" "PARTITION" PartitionNameList
 	{
 		$$ = &ast.CompactTableStmt{
@@ -1697,6 +1719,14 @@ AlterTableStmt:
 			ReplicaKind:    ast.CompactReplicaKindTiFlash,
 		}
 	}
+|	"ALTER" IgnoreOptional "TABLE" TableName "COMPACT" "PARTITION" PartitionNameList "TIKV" "REPLICA"
+	{
+		$$ = &ast.CompactTableStmt{
+			Table:          $4.(*ast.TableName),
+			PartitionNames: $7.([]model.CIStr),
+			ReplicaKind:    ast.CompactReplicaKindTiKV,
+		}
+	}
 
 ResourceGroupOptionList:
 	DirectResourceGroupOption
@@ -3012,9 +3042,9 @@ SplitSyntaxOption:
 	}
 
 AnalyzeTableStmt:
-	"ANALYZE" "TABLE" TableNameList AllColumnsOrPredicateColumnsOpt AnalyzeOptionListOpt
+	"ANALYZE" "TABLE" TableNameList AllColumnsOrPredicateColumnsOpt AnalyzeOptionListOpt AnalyzeDryRunOpt
 	{
-		$$ = &ast.AnalyzeTableStmt{TableNames: $3.([]*ast.TableName), ColumnChoice: $4.(model.ColumnChoice), AnalyzeOpts: $5.([]ast.AnalyzeOpt)}
+		$$ = &ast.AnalyzeTableStmt{TableNames: $3.([]*ast.TableName), ColumnChoice: $4.(model.ColumnChoice), AnalyzeOpts: $5.([]ast.AnalyzeOpt), DryRun: $6.(bool)}
 	}
 |	"ANALYZE" "TABLE" TableName "INDEX" IndexNameList AnalyzeOptionListOpt
 	{
@@ -3024,9 +3054,9 @@ AnalyzeTableStmt:
 	{
 		$$ = &ast.AnalyzeTableStmt{TableNames: []*ast.TableName{$4.(*ast.TableName)}, IndexNames: $6.([]model.CIStr), IndexFlag: true, Incremental: true, AnalyzeOpts: $7.([]ast.AnalyzeOpt)}
 	}
-|	"ANALYZE" "TABLE" TableName "PARTITION" PartitionNameList AllColumnsOrPredicateColumnsOpt AnalyzeOptionListOpt
+|	"ANALYZE" "TABLE" TableName "PARTITION" PartitionNameList AllColumnsOrPredicateColumnsOpt AnalyzeOptionListOpt AnalyzeDryRunOpt
 	{
-		$$ = &ast.AnalyzeTableStmt{TableNames: []*ast.TableName{$3.(*ast.TableName)}, PartitionNames: $5.([]model.CIStr), ColumnChoice: $6.(model.ColumnChoice), AnalyzeOpts: $7.([]ast.AnalyzeOpt)}
+		$$ = &ast.AnalyzeTableStmt{TableNames: []*ast.TableName{$3.(*ast.TableName)}, PartitionNames: $5.([]model.CIStr), ColumnChoice: $6.(model.ColumnChoice), AnalyzeOpts: $7.([]ast.AnalyzeOpt), DryRun: $8.(bool)}
 	}
 |	"ANALYZE" "TABLE" TableName "PARTITION" PartitionNameList "INDEX" IndexNameList AnalyzeOptionListOpt
 	{
@@ -3066,6 +3096,14 @@ AnalyzeTableStmt:
 			HistogramOperation: ast.HistogramOperationDrop,
 		}
 	}
+|	"ANALYZE" "TABLE" TableName "HISTOGRAM" "ONLY" AnalyzeOptionListOpt
+	{
+		$$ = &ast.AnalyzeTableStmt{
+			TableNames:    []*ast.TableName{$3.(*ast.TableName)},
+			HistogramOnly: true,
+			AnalyzeOpts:   $6.([]ast.AnalyzeOpt),
+		}
+	}
 |	"ANALYZE" "TABLE" TableName "COLUMNS" IdentList AnalyzeOptionListOpt
 	{
 		$$ = &ast.AnalyzeTableStmt{
@@ -3098,6 +3136,16 @@ AllColumnsOrPredicateColumnsOpt:
 		$$ = model.PredicateColumns
 	}
 
+AnalyzeDryRunOpt:
+	/* empty */
+	{
+		$$ = false
+	}
+|	"DRY" "RUN"
+	{
+		$$ = true
+	}
+
 AnalyzeOptionListOpt:
 	{
 		$$ = []ast.AnalyzeOpt{}
@@ -4891,7 +4939,7 @@ DoStmt:
  *
  *******************************************************************/
 DeleteWithoutUsingStmt:
-	"DELETE" TableOptimizerHintsOpt PriorityOpt QuickOptional IgnoreOptional "FROM" TableName PartitionNameListOpt TableAsNameOpt IndexHintListOpt WhereClauseOptional OrderByOptional LimitClause
+	"DELETE" TableOptimizerHintsOpt PriorityOpt QuickOptional IgnoreOptional "FROM" TableName PartitionNameListOpt TableAsNameOpt IndexHintListOpt WhereClauseOptional OrderByOptional LimitClause ReturningClauseOptional
 	{
 		// Single Table
 		tn := $7.(*ast.TableName)
@@ -4916,6 +4964,9 @@ DeleteWithoutUsingStmt:
 		if $13 != nil {
 			x.Limit = $13.(*ast.Limit)
 		}
+		if $14 != nil {
+			x.Returning = $14.(*ast.FieldList)
+		}
 
 		$$ = x
 	}
@@ -4961,6 +5012,16 @@ DeleteWithUsingStmt:
 		$$ = x
 	}
 
+ReturningClauseOptional:
+	%prec empty
+	{
+		$$ = nil
+	}
+|	"RETURNING" FieldList
+	{
+		$$ = &ast.FieldList{Fields: $2.([]*ast.SelectField)}
+	}
+
 DeleteFromStmt:
 	DeleteWithoutUsingStmt
 |	DeleteWithUsingStmt
@@ -5574,6 +5635,13 @@ BRIEOption:
 			UintValue: $3.(uint64) * 1048576,
 		}
 	}
+|	"VALIDATE"
+	{
+		$$ = &ast.BRIEOption{
+			Tp:        ast.BRIEOptionValidate,
+			UintValue: 1,
+		}
+	}
 |	"CSV_HEADER" EqOpt FieldsOrColumns
 	{
 		$$ = &ast.BRIEOption{
@@ -6498,6 +6566,9 @@ UnReservedKeyword:
 |	"TABLESPACE"
 |	"TEXT"
 |	"THAN"
+|	"TIES"
+|	"OTHERS"
+|	"EXCLUDE"
 |	"TIME" %prec lowerThanStringLitToken
 |	"TIMESTAMP" %prec lowerThanStringLitToken
 |	"TRACE"
@@ -6631,6 +6702,7 @@ UnReservedKeyword:
 |	"DIRECTORY"
 |	"HISTOGRAM"
 |	"HISTORY"
+|	"HEADER"
 |	"LIST"
 |	"NODEGROUP"
 |	"SYSTEM_TIME"
@@ -6718,6 +6790,7 @@ UnReservedKeyword:
 |	"CSV_HEADER"
 |	"CSV_NULL"
 |	"CSV_SEPARATOR"
+|	"VALIDATE"
 |	"ON_DUPLICATE"
 |	"TIKV_IMPORTER"
 |	"REPLICAS"
@@ -6729,6 +6802,7 @@ UnReservedKeyword:
 |	"PERCENT"
 |	"PAUSE"
 |	"RESUME"
+|	"RETURNING"
 |	"OFF"
 |	"OPTIONAL"
 |	"REQUIRED"
@@ -6773,6 +6847,7 @@ TiDBKeyword:
 |	"DRAINER"
 |	"JOBS"
 |	"JOB"
+|	"LIVE"
 |	"NODE_ID"
 |	"NODE_STATE"
 |	"PUMP"
@@ -6792,6 +6867,7 @@ TiDBKeyword:
 |	"TELEMETRY_ID"
 |	"TIDB"
 |	"TIFLASH"
+|	"TIKV"
 |	"TOPN"
 |	"SPLIT"
 |	"OPTIMISTIC"
@@ -9211,14 +9287,36 @@ OptWindowFrameClause:
 	{
 		$$ = nil
 	}
-|	WindowFrameUnits WindowFrameExtent
+|	WindowFrameUnits WindowFrameExtent OptWindowFrameExclusion
 	{
 		$$ = &ast.FrameClause{
-			Type:   $1.(ast.FrameType),
-			Extent: $2.(ast.FrameExtent),
+			Type:      $1.(ast.FrameType),
+			Extent:    $2.(ast.FrameExtent),
+			Exclusion: $3.(ast.FrameExclusion),
 		}
 	}
 
+OptWindowFrameExclusion:
+	{
+		$$ = ast.FrameExclusion(ast.ExcludeNoOthers)
+	}
+|	"EXCLUDE" "CURRENT" "ROW"
+	{
+		$$ = ast.FrameExclusion(ast.ExcludeCurrentRow)
+	}
+|	"EXCLUDE" "GROUP"
+	{
+		$$ = ast.FrameExclusion(ast.ExcludeGroup)
+	}
+|	"EXCLUDE" "TIES"
+	{
+		$$ = ast.FrameExclusion(ast.ExcludeTies)
+	}
+|	"EXCLUDE" "NO" "OTHERS"
+	{
+		$$ = ast.FrameExclusion(ast.ExcludeNoOthers)
+	}
+
 WindowFrameUnits:
 	"ROWS"
 	{
@@ -9881,11 +9979,13 @@ SelectStmtIntoOption:
 	{
 		$$ = nil
 	}
-|	"INTO" "OUTFILE" stringLit Fields Lines
+|	"INTO" "OUTFILE" stringLit Fields Lines SelectIntoHeaderOpt SelectIntoCompressionOpt
 	{
 		x := &ast.SelectIntoOption{
-			Tp:       ast.SelectIntoOutfile,
-			FileName: $3,
+			Tp:          ast.SelectIntoOutfile,
+			FileName:    $3,
+			WithHeader:  $6.(bool),
+			Compression: $7.(string),
 		}
 		if $4 != nil {
 			x.FieldsInfo = $4.(*ast.FieldsClause)
@@ -9897,6 +9997,25 @@ SelectStmtIntoOption:
 		$$ = x
 	}
 
+SelectIntoHeaderOpt:
+	%prec lowerThanWith
+	{
+		$$ = false
+	}
+|	"WITH" "HEADER"
+	{
+		$$ = true
+	}
+
+SelectIntoCompressionOpt:
+	{
+		$$ = ""
+	}
+|	"COMPRESSION" stringLit
+	{
+		$$ = $2
+	}
+
 // See https://dev.mysql.com/doc/refman/5.7/en/subqueries.html
 SubSelect:
 	'(' SelectStmt ')'
@@ -11316,6 +11435,15 @@ FromOrIn:
 	"FROM"
 |	"IN"
 
+OptLiveStats:
+	{
+		$$ = false
+	}
+|	"WITH" "LIVE" "STATS"
+	{
+		$$ = true
+	}
+
 ShowTargetFilterable:
 	"ENGINES"
 	{
@@ -11355,18 +11483,20 @@ ShowTargetFilterable:
 			DBName: $3,
 		}
 	}
-|	ShowIndexKwd FromOrIn TableName
+|	ShowIndexKwd FromOrIn TableName OptLiveStats
 	{
 		$$ = &ast.ShowStmt{
-			Tp:    ast.ShowIndex,
-			Table: $3.(*ast.TableName),
+			Tp:        ast.ShowIndex,
+			Table:     $3.(*ast.TableName),
+			LiveStats: $4.(bool),
 		}
 	}
-|	ShowIndexKwd FromOrIn Identifier FromOrIn Identifier
+|	ShowIndexKwd FromOrIn Identifier FromOrIn Identifier OptLiveStats
 	{
 		show := &ast.ShowStmt{
-			Tp:    ast.ShowIndex,
-			Table: &ast.TableName{Name: model.NewCIStr($3), Schema: model.NewCIStr($5)},
+			Tp:        ast.ShowIndex,
+			Table:     &ast.TableName{Name: model.NewCIStr($3), Schema: model.NewCIStr($5)},
+			LiveStats: $6.(bool),
 		}
 		$$ = show
 	}
@@ -13012,7 +13142,7 @@ UpdateStmt:
 	}
 
 UpdateStmtNoWith:
-	"UPDATE" TableOptimizerHintsOpt PriorityOpt IgnoreOptional TableRef "SET" AssignmentList WhereClauseOptional OrderByOptional LimitClause
+	"UPDATE" TableOptimizerHintsOpt PriorityOpt IgnoreOptional TableRef "SET" AssignmentList WhereClauseOptional OrderByOptional LimitClause ReturningClauseOptional
 	{
 		var refs *ast.Join
 		if x, ok := $5.(*ast.Join); ok {
@@ -13038,6 +13168,9 @@ UpdateStmtNoWith:
 		if $10 != nil {
 			st.Limit = $10.(*ast.Limit)
 		}
+		if $11 != nil {
+			st.Returning = $11.(*ast.FieldList)
+		}
 		$$ = st
 	}
 |	"UPDATE" TableOptimizerHintsOpt PriorityOpt IgnoreOptional TableRefs "SET" AssignmentList WhereClauseOptional
@@ -14484,6 +14617,14 @@ KillStmt:
 			TiDBExtension: $1.(bool),
 		}
 	}
+|	KillOrKillTiDB NUM "FORCE"
+	{
+		$$ = &ast.KillStmt{
+			ConnectionID:  getUint64FromNUM($2),
+			TiDBExtension: $1.(bool),
+			Force:         true,
+		}
+	}
 |	KillOrKillTiDB "CONNECTION" NUM
 	{
 		$$ = &ast.KillStmt{
@@ -14491,6 +14632,14 @@ KillStmt:
 			TiDBExtension: $1.(bool),
 		}
 	}
+|	KillOrKillTiDB "CONNECTION" NUM "FORCE"
+	{
+		$$ = &ast.KillStmt{
+			ConnectionID:  getUint64FromNUM($3),
+			TiDBExtension: $1.(bool),
+			Force:         true,
+		}
+	}
 |	KillOrKillTiDB "QUERY" NUM
 	{
 		$$ = &ast.KillStmt{
@@ -14499,6 +14648,14 @@ KillStmt:
 			TiDBExtension: $1.(bool),
 		}
 	}
+|	KillOrKillTiDB "QUERY" "DIGEST" stringLit
+	{
+		$$ = &ast.KillStmt{
+			Query:         true,
+			Digest:        $4,
+			TiDBExtension: $1.(bool),
+		}
+	}
 |	KillOrKillTiDB BuiltinFunction
 	{
 		$$ = &ast.KillStmt{
@@ -15045,6 +15202,19 @@ PlanReplayerStmt:
 			Limit:      nil,
 		}
 
+		$$ = x
+	}
+|	"PLAN" "REPLAYER" "CAPTURE" "RESET"
+	{
+		x := &ast.PlanReplayerStmt{
+			Stmt:    nil,
+			Analyze: false,
+			Reset:   true,
+			Where:   nil,
+			OrderBy: nil,
+			Limit:   nil,
+		}
+
 		$$ = x
 	}
 
@@ -99,7 +99,7 @@ func TestSimple(t *testing.T) {
 		"following", "preceding", "unbounded", "respect", "nulls", "current", "last", "against", "expansion",
 		"chain", "error", "general", "nvarchar", "pack_keys", "p", "shard_row_id_bits", "pre_split_regions",
 		"constraints", "role", "replicas", "policy", "s3", "strict", "running", "stop", "preserve", "placement", "attributes", "attribute", "resource",
-		"burstable", "calibrate", "rollup",
+		"burstable", "calibrate", "rollup", "header",
 	}
 	for _, kw := range unreservedKws {
 		src := fmt.Sprintf("SELECT %s FROM tbl;", kw)
@@ -732,6 +732,13 @@ func TestDMLStmt(t *testing.T) {
 		{"select a,b,a+b from t into outfile '/tmp/result.txt' fields terminated BY ',' enclosed BY '\"' lines terminated BY '\r'", true, "SELECT `a`,`b`,`a`+`b` FROM `t` INTO OUTFILE '/tmp/result.txt' FIELDS TERMINATED BY ',' ENCLOSED BY '\"' LINES TERMINATED BY '\r'"},
 		{"select a,b,a+b from t into outfile '/tmp/result.txt' fields terminated BY ',' optionally enclosed BY '\"' lines starting by 'xy' terminated BY '\r'", true, "SELECT `a`,`b`,`a`+`b` FROM `t` INTO OUTFILE '/tmp/result.txt' FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '\"' LINES STARTING BY 'xy' TERMINATED BY '\r'"},
 		{"select a,b,a+b from t into outfile '/tmp/result.txt' fields terminated BY ',' enclosed BY '\"' lines starting by 'xy' terminated BY '\r'", true, "SELECT `a`,`b`,`a`+`b` FROM `t` INTO OUTFILE '/tmp/result.txt' FIELDS TERMINATED BY ',' ENCLOSED BY '\"' LINES STARTING BY 'xy' TERMINATED BY '\r'"},
+		{"select a,b from t into outfile '/tmp/result.txt' with header", true, "SELECT `a`,`b` FROM `t` INTO OUTFILE '/tmp/result.txt' WITH HEADER"},
+		{"select a,b from t into outfile '/tmp/result.txt' fields terminated BY ',' enclosed BY '\"' lines terminated BY '\n' with header", true, "SELECT `a`,`b` FROM `t` INTO OUTFILE '/tmp/result.txt' FIELDS TERMINATED BY ',' ENCLOSED BY '\"' LINES TERMINATED BY '\n' WITH HEADER"},
+		{"select a,b from t into outfile '/tmp/result.txt.gz' compression 'gzip'", true, "SELECT `a`,`b` FROM `t` INTO OUTFILE '/tmp/result.txt.gz' COMPRESSION 'gzip'"},
+		{"select a,b from t into outfile '/tmp/result.txt' fields terminated BY ',' with header compression 'zstd'", true, "SELECT `a`,`b` FROM `t` INTO OUTFILE '/tmp/result.txt' FIELDS TERMINATED BY ',' WITH HEADER COMPRESSION 'zstd'"},
+		{"select a,b from t into outfile '/tmp/result.txt' compression 'gzip' with header", false, ""},
+		{"select a,b from t into outfile '/tmp/result.txt' compression gzip", false, ""},
+		{"select a,b from t into outfile '/tmp/result.txt' with", false, ""},
 
 		// from join
 		{"SELECT * from t1, t2, t3", true, "SELECT * FROM ((`t1`) JOIN `t2`) JOIN `t3`"},
@@ -767,6 +774,10 @@ func TestDMLStmt(t *testing.T) {
 		{"delete from t1 where a=26", true, "DELETE FROM `t1` WHERE `a`=26"},
 		{"DELETE from t1 where a=1 limit 1", true, "DELETE FROM `t1` WHERE `a`=1 LIMIT 1"},
 		{"DELETE FROM t1 WHERE t1.a > 0 ORDER BY t1.a LIMIT 1", true, "DELETE FROM `t1` WHERE `t1`.`a`>0 ORDER BY `t1`.`a` LIMIT 1"},
+		{"DELETE FROM t1 WHERE a > 0 LIMIT 1 RETURNING a, b + 1 AS c", true, "DELETE FROM `t1` WHERE `a`>0 LIMIT 1 RETURNING `a`, `b`+1 AS `c`"},
+		{"DELETE FROM t1 RETURNING *", true, "DELETE FROM `t1` RETURNING *"},
+		{"DELETE FROM t1 AS returning RETURNING returning.a", true, "DELETE FROM `t1` AS `returning` RETURNING `returning`.`a`"},
+		{"DELETE t1 FROM t1, t2 WHERE t1.a = t2.a RETURNING t1.a", false, ""},
 		{"DELETE FROM x.y z WHERE z.a > 0", true, "DELETE FROM `x`.`y` AS `z` WHERE `z`.`a`>0"},
 		{"DELETE FROM t1 AS w WHERE a > 0", true, "DELETE FROM `t1` AS `w` WHERE `a`>0"},
 		{"DELETE from t1 partition (p0,p1)", true, "DELETE FROM `t1` PARTITION(`p0`, `p1`)"},
@@ -879,6 +890,9 @@ func TestDMLStmt(t *testing.T) {
 		// for update statement
 		{"UPDATE LOW_PRIORITY IGNORE t SET id = id + 1 ORDER BY id DESC;", true, "UPDATE LOW_PRIORITY IGNORE `t` SET `id`=`id`+1 ORDER BY `id` DESC"},
 		{"UPDATE t SET id = id + 1 ORDER BY id DESC;", true, "UPDATE `t` SET `id`=`id`+1 ORDER BY `id` DESC"},
+		{"UPDATE t SET id = id + 1 WHERE id > 1 RETURNING id, t.c", true, "UPDATE `t` SET `id`=`id`+1 WHERE `id`>1 RETURNING `id`, `t`.`c`"},
+		{"UPDATE t SET returning = 1 RETURNING returning", true, "UPDATE `t` SET `returning`=1 RETURNING `returning`"},
+		{"UPDATE t1, t2 SET t1.a = t2.a RETURNING t1.a", false, ""},
 		{"UPDATE t SET id = id + 1 ORDER BY id DESC limit 3 ;", true, "UPDATE `t` SET `id`=`id`+1 ORDER BY `id` DESC LIMIT 3"},
 		{"UPDATE t SET id = id + 1, name = 'jojo';", true, "UPDATE `t` SET `id`=`id`+1, `name`=_UTF8MB4'jojo'"},
 		{"UPDATE items,month SET items.price=month.price WHERE items.id=month.id;", true, "UPDATE (`items`) JOIN `month` SET `items`.`price`=`month`.`price` WHERE `items`.`id`=`month`.`id`"},
@@ -1146,6 +1160,9 @@ func TestDBAStmt(t *testing.T) {
 		{`SHOW KEYS IN t;`, true, "SHOW INDEX IN `t`"},
 		{`SHOW INDEXES IN t where true;`, true, "SHOW INDEX IN `t` WHERE TRUE"},
 		{`SHOW KEYS FROM t FROM test where true;`, true, "SHOW INDEX IN `test`.`t` WHERE TRUE"},
+		{`SHOW INDEX FROM t WITH LIVE STATS;`, true, "SHOW INDEX IN `t` WITH LIVE STATS"},
+		{`SHOW KEYS FROM t FROM test WITH LIVE STATS where true;`, true, "SHOW INDEX IN `test`.`t` WITH LIVE STATS WHERE TRUE"},
+		{`SHOW INDEX FROM t WITH LIVE;`, false, ""},
 		{`SHOW EVENTS FROM test_db WHERE definer = 'current_user'`, true, "SHOW EVENTS IN `test_db` WHERE `definer`=_UTF8MB4'current_user'"},
 		{`SHOW PLUGINS`, true, "SHOW PLUGINS"},
 		{`SHOW PROFILES`, true, "SHOW PROFILES"},
@@ -2595,6 +2612,8 @@ func TestDDL(t *testing.T) {
 		{`alter table t compact tiflash replica;`, true, "ALTER TABLE `t` COMPACT TIFLASH REPLICA"},
 		{`alter table t compact partition p1,p2;`, true, "ALTER TABLE `t` COMPACT PARTITION `p1`,`p2`"},
 		{`alter table t compact partition p1,p2 tiflash replica;`, true, "ALTER TABLE `t` COMPACT PARTITION `p1`,`p2` TIFLASH REPLICA"},
+		{`alter table t compact tikv replica;`, true, "ALTER TABLE `t` COMPACT TIKV REPLICA"},
+		{`alter table t compact partition p1,p2 tikv replica;`, true, "ALTER TABLE `t` COMPACT PARTITION `p1`,`p2` TIKV REPLICA"},
 		// 3. create db
 		{`create database t primary_region="us";`, false, ""},
 		{`create database t regions="us,3";`, false, ""},
@@ -5505,6 +5524,13 @@ func TestSessionManage(t *testing.T) {
 		{"kill tidb 23123", true, "KILL TIDB 23123"},
 		{"kill tidb connection 23123", true, "KILL TIDB 23123"},
 		{"kill tidb query 23123", true, "KILL TIDB QUERY 23123"},
+		{"kill query digest 'abc'", true, "KILL QUERY DIGEST 'abc'"},
+		{"kill tidb query digest 'abc'", true, "KILL TIDB QUERY DIGEST 'abc'"},
+		{"kill tidb connection digest 'abc'", false, ""},
+		{"kill 23123 force", true, "KILL 23123 FORCE"},
+		{"kill tidb connection 23123 force", true, "KILL TIDB 23123 FORCE"},
+		{"kill query 23123 force", false, ""},
+		{"kill tidb query digest abc", false, ""},
 		{"show processlist", true, "SHOW PROCESSLIST"},
 		{"show full processlist", true, "SHOW FULL PROCESSLIST"},
 		{"shutdown", true, "SHUTDOWN"},
@@ -5644,6 +5670,9 @@ func TestAnalyze(t *testing.T) {
 		{"analyze incremental table t index", true, "ANALYZE INCREMENTAL TABLE `t` INDEX"},
 		{"analyze incremental table t index idx", true, "ANALYZE INCREMENTAL TABLE `t` INDEX `idx`"},
 		{"analyze table t update histogram on b with 1024 buckets", true, "ANALYZE TABLE `t` UPDATE HISTOGRAM ON `b` WITH 1024 BUCKETS"},
+		{"analyze table t histogram only", true, "ANALYZE TABLE `t` HISTOGRAM ONLY"},
+		{"analyze table t histogram only with 64 buckets", true, "ANALYZE TABLE `t` HISTOGRAM ONLY WITH 64 BUCKETS"},
+		{"analyze table t, t1 histogram only", false, ""},
 		{"analyze table t drop histogram on b", true, "ANALYZE TABLE `t` DROP HISTOGRAM ON `b`"},
 		{"analyze table t update histogram on c1, c2;", true, "ANALYZE TABLE `t` UPDATE HISTOGRAM ON `c1`,`c2`"},
 		{"analyze table t drop histogram on c1, c2;", true, "ANALYZE TABLE `t` DROP HISTOGRAM ON `c1`,`c2`"},
@@ -5668,6 +5697,11 @@ func TestAnalyze(t *testing.T) {
 		{"analyze table t index a predicate columns", false, ""},
 		{"analyze table t with 10 samplerate", true, "ANALYZE TABLE `t` WITH 10 SAMPLERATE"},
 		{"analyze table t with 0.1 samplerate", true, "ANALYZE TABLE `t` WITH 0.1 SAMPLERATE"},
+		{"analyze table t dry run", true, "ANALYZE TABLE `t` DRY RUN"},
+		{"analyze table t1,t2 all columns with 0.1 samplerate dry run", true, "ANALYZE TABLE `t1`,`t2` ALL COLUMNS WITH 0.1 SAMPLERATE DRY RUN"},
+		{"analyze table t partition a, b dry run", true, "ANALYZE TABLE `t` PARTITION `a`,`b` DRY RUN"},
+		{"analyze table t index a dry run", false, ""},
+		{"analyze table t dry", false, ""},
 	}
 	RunTest(t, table, false)
 }
@@ -6111,6 +6145,13 @@ func TestWindowFunctions(t *testing.T) {
 		{`SELECT AVG(val) OVER (RANGE BETWEEN INTERVAL 5 DAY PRECEDING AND INTERVAL '2:30' MINUTE_SECOND FOLLOWING) FROM t;`, true, "SELECT AVG(`val`) OVER (RANGE BETWEEN INTERVAL 5 DAY PRECEDING AND INTERVAL _UTF8MB4'2:30' MINUTE_SECOND FOLLOWING) FROM `t`"},
 		{`SELECT AVG(val) OVER (RANGE BETWEEN CURRENT ROW AND CURRENT ROW) FROM t;`, true, "SELECT AVG(`val`) OVER (RANGE BETWEEN CURRENT ROW AND CURRENT ROW) FROM `t`"},
 		{`SELECT AVG(val) OVER (RANGE CURRENT ROW) FROM t;`, true, "SELECT AVG(`val`) OVER (RANGE BETWEEN CURRENT ROW AND CURRENT ROW) FROM `t`"},
+		{`SELECT SUM(val) OVER (ORDER BY time ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE CURRENT ROW) FROM t;`, true, "SELECT SUM(`val`) OVER (ORDER BY `time` ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE CURRENT ROW) FROM `t`"},
+		{`SELECT SUM(val) OVER (ORDER BY time RANGE UNBOUNDED PRECEDING EXCLUDE GROUP) FROM t;`, true, "SELECT SUM(`val`) OVER (ORDER BY `time` RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW EXCLUDE GROUP) FROM `t`"},
+		{`SELECT SUM(val) OVER (ORDER BY time RANGE BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE TIES) FROM t;`, true, "SELECT SUM(`val`) OVER (ORDER BY `time` RANGE BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE TIES) FROM `t`"},
+		{`SELECT SUM(val) OVER (ORDER BY time ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE NO OTHERS) FROM t;`, true, "SELECT SUM(`val`) OVER (ORDER BY `time` ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING) FROM `t`"},
+		{`SELECT SUM(val) OVER (ORDER BY time ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE OTHERS) FROM t;`, false, ""},
+		{`SELECT SUM(val) OVER (ORDER BY time EXCLUDE TIES) FROM t;`, false, ""},
+		{`SELECT exclude, others, ties FROM t;`, true, "SELECT `exclude`,`others`,`ties` FROM `t`"},
 
 		// For named windows.
 		// See https://dev.mysql.com/doc/refman/8.0/en/window-functions-named-windows.html
@@ -6623,6 +6664,9 @@ func TestBRIE(t *testing.T) {
 		{"backup database * to 'noop://' rate_limit 500 MB/second snapshot 5 minute ago", true, "BACKUP DATABASE * TO 'noop://' RATE_LIMIT = 500 MB/SECOND SNAPSHOT = 300000000 MICROSECOND AGO"},
 		{"backup database * to 'noop://' snapshot = '2020-03-18 18:13:54'", true, "BACKUP DATABASE * TO 'noop://' SNAPSHOT = '2020-03-18 18:13:54'"},
 		{"backup database * to 'noop://' snapshot = 1234567890", true, "BACKUP DATABASE * TO 'noop://' SNAPSHOT = 1234567890"},
+		{"backup database * to 'noop://' validate", true, "BACKUP DATABASE * TO 'noop://' VALIDATE"},
+		{"BACKUP TABLE a.b TO 'noop://' VALIDATE CONCURRENCY 4", true, "BACKUP TABLE `a`.`b` TO 'noop://' VALIDATE CONCURRENCY = 4"},
+		{"create table validate (validate int)", true, "CREATE TABLE `validate` (`validate` INT)"},
 		{"restore table g from 'noop://' concurrency 40 checksum 0 online 1", true, "RESTORE TABLE `g` FROM 'noop://' CONCURRENCY = 40 CHECKSUM = OFF ONLINE = 1"},
 		{
 			"backup table x to 's3://bucket/path/?endpoint=https://test-cluster-s3.local&access-key=aaaaaaaaa&secret-access-key=bbbbbbbb&force-path-style=1'",
@@ -6988,6 +7032,8 @@ func TestPlanReplayer(t *testing.T) {
 		{"PLAN REPLAYER DUMP EXPLAIN ANALYZE 'sql.txt'", true, "PLAN REPLAYER DUMP EXPLAIN ANALYZE 'sql.txt'"},
 		{"PLAN REPLAYER CAPTURE '123' '123'", true, "PLAN REPLAYER CAPTURE '123' '123'"},
 		{"PLAN REPLAYER CAPTURE REMOVE '123' '123'", true, "PLAN REPLAYER CAPTURE REMOVE '123' '123'"},
+		{"PLAN REPLAYER CAPTURE RESET", true, "PLAN REPLAYER CAPTURE RESET"},
+		{"PLAN REPLAYER CAPTURE RESET '123'", false, ""},
 	}
 	RunTest(t, table, false)
 
@@ -17,6 +17,8 @@ package core_test
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -57,6 +59,145 @@ func TestExplainCostTrace(t *testing.T) {
 	})
 }
 
+func TestExplainPlanFingerprint(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int, b int, index ia(a), index ib(b))")
+	tk.MustExec("create table t2 (a int, b int)")
+
+	fingerprint := func(sql string) (string, string) {
+		rows := tk.MustQuery("explain format='plan_fingerprint' " + sql).Rows()
+		require.Len(t, rows[0], 6)
+		return rows[0][1].(string), rows[0][5].(string)
+	}
+	indexA := "select /*+ use_index(t1, ia) */ * from t1 where a > 1 and b > 1"
+	indexB := "select /*+ use_index(t1, ib) */ * from t1 where a > 1 and b > 1"
+	join12 := "select /*+ leading(t1, t2), hash_join_build(t2) */ * from t1, t2 where t1.a = t2.a"
+	join21 := "select /*+ leading(t2, t1), hash_join_build(t1) */ * from t1, t2 where t1.a = t2.a"
+
+	pseudoRowsA, pseudoA := fingerprint(indexA)
+	_, pseudoB := fingerprint(indexB)
+	_, pseudo12 := fingerprint(join12)
+	_, pseudo21 := fingerprint(join21)
+	require.NotEqual(t, pseudoA, pseudoB)
+	require.NotEqual(t, pseudo12, pseudo21)
+
+	// the fingerprints don't change with the statistics, as long as the plan shape keeps the same.
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3), (4, 4)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2)")
+	tk.MustExec("analyze table t1, t2")
+	rowsA, fpA := fingerprint(indexA)
+	require.NotEqual(t, pseudoRowsA, rowsA)
+	require.Equal(t, pseudoA, fpA)
+	_, fpB := fingerprint(indexB)
+	require.Equal(t, pseudoB, fpB)
+	_, fp12 := fingerprint(join12)
+	require.Equal(t, pseudo12, fp12)
+	_, fp21 := fingerprint(join21)
+	require.Equal(t, pseudo21, fp21)
+
+	// default explain output is unchanged.
+	require.Len(t, tk.MustQuery("explain " + indexA).Rows()[0], 5)
+	tk.MustGetErrMsg("explain analyze format='plan_fingerprint' "+indexA, "explain format 'plan_fingerprint' is not supported now")
+}
+
+func TestExplainMemory(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int, b int, index ia(a))")
+	tk.MustExec("create table t2 (a int, b int)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3), (4, 4)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2)")
+	tk.MustExec("analyze table t1, t2")
+
+	explainMem := func(sql string) map[string][2]string {
+		rows := tk.MustQuery("explain format='mem' " + sql).Rows()
+		mems := make(map[string][2]string, len(rows))
+		for _, row := range rows {
+			require.Len(t, row, 7)
+			require.NotEqual(t, "N/A", row[5])
+			id := strings.TrimLeft(row[0].(string), "├└─│ ")
+			mems[id[:strings.Index(id, "_")]] = [2]string{row[5].(string), row[6].(string)}
+		}
+		return mems
+	}
+
+	// no buffering operator.
+	mems := explainMem("select /*+ use_index(t1, ia) */ a from t1 where a > 1")
+	require.Len(t, mems, 2)
+	for _, mem := range mems {
+		require.Equal(t, "N/A", mem[1])
+	}
+
+	// the hash join, sort and hash agg in root are estimated.
+	mems = explainMem("select /*+ hash_join(t1, t2), hash_agg() */ t1.a, count(*) from t1, t2 where t1.a = t2.a group by t1.a order by t1.a")
+	for _, op := range []string{"HashJoin", "Sort", "HashAgg"} {
+		require.Contains(t, mems, op)
+		require.NotEqual(t, "N/A", mems[op][1], op)
+	}
+	require.Equal(t, "N/A", mems["TableReader"][1])
+
+	// default explain output is unchanged.
+	require.Len(t, tk.MustQuery("explain select * from t1").Rows()[0], 5)
+	tk.MustGetErrMsg("explain analyze format='mem' select * from t1", "explain format 'mem' is not supported now")
+}
+
+func TestExplainIndexChoice(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, c int, index ia(a), index ib(b), index ibc(b, c))")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d, %d)", i, i%10, i%7))
+	}
+	tk.MustExec("analyze table t")
+
+	explainChoices := func(sql string) (paths []string, chosen string) {
+		rows := tk.MustQuery("explain format='index_choice' " + sql).Rows()
+		minCost := math.MaxFloat64
+		for _, row := range rows {
+			require.Len(t, row, 5)
+			require.Equal(t, "t", row[1])
+			cost, err := strconv.ParseFloat(row[3].(string), 64)
+			require.NoError(t, err)
+			require.Greater(t, cost, 0.0)
+			minCost = math.Min(minCost, cost)
+			paths = append(paths, row[2].(string))
+			if row[4] == "true" {
+				require.Empty(t, chosen)
+				chosen = row[2].(string)
+				require.Equal(t, minCost, cost)
+			}
+		}
+		return paths, chosen
+	}
+
+	// the full table scan and every usable index are candidates, the cheapest one is chosen.
+	paths, chosen := explainChoices("select * from t where a < 5 and b > 8")
+	require.Equal(t, []string{"table", "index:ia", "index:ib", "index:ibc"}, paths)
+	require.NotEmpty(t, chosen)
+	// the index hint leaves only one candidate.
+	paths, chosen = explainChoices("select /*+ use_index(t, ib) */ * from t where a < 5 and b > 8")
+	require.Equal(t, []string{"index:ib"}, paths)
+	require.Equal(t, "index:ib", chosen)
+
+	// every data source of a join is listed.
+	rows := tk.MustQuery("explain format='index_choice' select * from t t1 join t t2 on t1.a = t2.b where t1.c = 1").Rows()
+	tables := make(map[string]int)
+	for _, row := range rows {
+		if row[4] == "true" {
+			tables[row[1].(string)]++
+		}
+	}
+	require.Equal(t, map[string]int{"t1": 1, "t2": 1}, tables)
+
+	// default explain output is unchanged.
+	require.Len(t, tk.MustQuery("explain select * from t").Rows()[0], 5)
+	tk.MustGetErrMsg("explain analyze format='index_choice' select * from t", "explain format 'index_choice' is not supported now")
+}
+
 func TestExplainAnalyze(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"strconv"
 	"strings"
 
@@ -35,6 +36,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/hint"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/plancodec"
@@ -85,6 +87,8 @@ type CheckTable struct {
 	IndexInfos         []*model.IndexInfo
 	IndexLookUpReaders []*PhysicalIndexLookUpReader
 	CheckIndex         bool
+	// CheckFK indicates whether to also check that the foreign key values of the table have parent rows.
+	CheckFK bool
 }
 
 // RecoverIndex is used for backfilling corrupted index data.
@@ -445,6 +449,9 @@ type Update struct {
 
 	FKChecks   map[int64][]*FKCheck
 	FKCascades map[int64][]*FKCascade
+
+	// Returning is the RETURNING clause of the single table update, which is evaluated on the updated rows.
+	Returning *DMLReturning
 }
 
 // MemoryUsage return the memory usage of Update
@@ -487,6 +494,9 @@ type Delete struct {
 
 	FKChecks   map[int64][]*FKCheck
 	FKCascades map[int64][]*FKCascade
+
+	// Returning is the RETURNING clause of the single table delete, which is evaluated on the deleted rows.
+	Returning *DMLReturning
 }
 
 // MemoryUsage return the memory usage of Delete
@@ -505,6 +515,14 @@ func (p *Delete) MemoryUsage() (sum int64) {
 	return
 }
 
+// DMLReturning is the RETURNING clause of the UPDATE and DELETE statements.
+type DMLReturning struct {
+	// Exprs are resolved by the schema of the SelectPlan.
+	Exprs  []expression.Expression
+	Schema *expression.Schema
+	Names  types.NameSlice
+}
+
 // AnalyzeInfo is used to store the database name, table name and partition name of analyze task.
 type AnalyzeInfo struct {
 	DBName        string
@@ -514,6 +532,8 @@ type AnalyzeInfo struct {
 	Incremental   bool
 	StatsVersion  int
 	V2Options     *V2AnalyzeOptions
+	// HistogramOnly indicates that only the histograms are rebuilt and the existing TopN is kept.
+	HistogramOnly bool
 }
 
 // V2AnalyzeOptions is used to hold analyze options information.
@@ -551,6 +571,8 @@ type Analyze struct {
 	IdxTasks   []AnalyzeIndexTask
 	Opts       map[ast.AnalyzeOptionType]uint64
 	OptionsMap map[int64]V2AnalyzeOptions
+	// DryRun indicates that the sampling plan of the tasks is returned instead of collecting the statistics.
+	DryRun bool
 }
 
 // LoadData represents a loaddata plan.
@@ -627,6 +649,7 @@ type PlanReplayer struct {
 
 	Capture    bool
 	Remove     bool
+	Reset      bool
 	SQLDigest  string
 	PlanDigest string
 }
@@ -685,6 +708,9 @@ type SelectInto struct {
 
 	TargetPlan Plan
 	IntoOpt    *ast.SelectIntoOption
+	// TargetNames is the output names of the TargetPlan, it is used to
+	// write the header line when `WITH HEADER` is specified.
+	TargetNames types.NameSlice
 	LineFieldsInfo
 }
 
@@ -828,6 +854,12 @@ func (e *Explain) prepareSchema() error {
 		}
 	case (format == types.ExplainFormatROW || format == types.ExplainFormatBrief || format == types.ExplainFormatPlanCache) && (e.Analyze || e.RuntimeStatsColl != nil):
 		fieldNames = []string{"id", "estRows", "actRows", "task", "access object", "execution info", "operator info", "memory", "disk"}
+	case format == types.ExplainFormatPlanFingerprint && (!e.Analyze && e.RuntimeStatsColl == nil):
+		fieldNames = []string{"id", "estRows", "task", "access object", "operator info", "plan fingerprint"}
+	case format == types.ExplainFormatMem && (!e.Analyze && e.RuntimeStatsColl == nil):
+		fieldNames = []string{"id", "estRows", "task", "access object", "operator info", "plan memory", "estimated memory"}
+	case format == types.ExplainFormatIndexChoice && (!e.Analyze && e.RuntimeStatsColl == nil):
+		fieldNames = []string{"id", "table", "access path", "estCost", "chosen"}
 	case format == types.ExplainFormatDOT:
 		fieldNames = []string{"dot contents"}
 	case format == types.ExplainFormatHint:
@@ -920,6 +952,15 @@ func (e *Explain) RenderResult() error {
 				row[7] = row[7] + "(Total: " + tracker.FormatBytes(tracker.MaxConsumed()) + ")"
 			}
 		}
+	case types.ExplainFormatPlanFingerprint:
+		flat := FlattenPhysicalPlan(e.TargetPlan, true)
+		e.explainFlatPlanWithFingerprint(flat)
+	case types.ExplainFormatMem:
+		flat := FlattenPhysicalPlan(e.TargetPlan, true)
+		e.explainFlatPlanWithMemory(flat)
+	case types.ExplainFormatIndexChoice:
+		flat := FlattenPhysicalPlan(e.TargetPlan, true)
+		e.explainIndexChoices(flat)
 	case types.ExplainFormatDOT:
 		if physicalPlan, ok := e.TargetPlan.(PhysicalPlan); ok {
 			e.prepareDotInfo(physicalPlan)
@@ -968,6 +1009,134 @@ func (e *Explain) explainFlatPlanInRowFormat(flat *FlatPhysicalPlan) {
 	}
 }
 
+// explainFlatPlanWithFingerprint is the same as explainFlatPlanInRowFormat, except that every row
+// is followed by the fingerprint of the subtree rooted at that operator.
+func (e *Explain) explainFlatPlanWithFingerprint(flat *FlatPhysicalPlan) {
+	if flat == nil || len(flat.Main) == 0 || flat.InExplain {
+		return
+	}
+	trees := append([]FlatPlanTree{flat.Main}, flat.CTEs...)
+	for _, tree := range trees {
+		fingerprints := flatPlanTreeFingerprints(tree)
+		for i, flatOp := range tree {
+			rowIdx := len(e.Rows)
+			e.explainFlatOpInRowFormat(flatOp)
+			if len(e.Rows) > rowIdx {
+				e.Rows[rowIdx] = append(e.Rows[rowIdx], fingerprints[i])
+			}
+		}
+	}
+}
+
+// explainFlatPlanWithMemory is the same as explainFlatPlanInRowFormat, except that every row is followed by
+// the memory usage of the operator's plan structure and the estimated runtime memory if the operator buffers
+// its input, which is "N/A" for the other operators.
+func (e *Explain) explainFlatPlanWithMemory(flat *FlatPhysicalPlan) {
+	if flat == nil || len(flat.Main) == 0 || flat.InExplain {
+		return
+	}
+	trees := append([]FlatPlanTree{flat.Main}, flat.CTEs...)
+	for _, tree := range trees {
+		for _, flatOp := range tree {
+			rowIdx := len(e.Rows)
+			e.explainFlatOpInRowFormat(flatOp)
+			if len(e.Rows) <= rowIdx {
+				continue
+			}
+			planMem, estMem := "N/A", "N/A"
+			if pp, ok := flatOp.Origin.(PhysicalPlan); ok {
+				planMem = memory.FormatBytes(pp.MemoryUsage())
+				if mem, ok := estimateBufferingMemory(pp); ok && flatOp.IsRoot {
+					estMem = memory.FormatBytes(mem)
+				}
+			}
+			e.Rows[rowIdx] = append(e.Rows[rowIdx], planMem, estMem)
+		}
+	}
+}
+
+// explainIndexChoices lists the candidate access paths evaluated by the optimizer for every data source
+// in the plan, together with their estimated costs, and marks the chosen one.
+func (e *Explain) explainIndexChoices(flat *FlatPhysicalPlan) {
+	if flat == nil || len(flat.Main) == 0 || flat.InExplain {
+		return
+	}
+	choices := e.SCtx().GetSessionVars().StmtCtx.IndexChoices
+	trees := append([]FlatPlanTree{flat.Main}, flat.CTEs...)
+	for _, tree := range trees {
+		for _, flatOp := range tree {
+			candidates, ok := choices[flatOp.Origin.ID()]
+			if !ok {
+				continue
+			}
+			for _, c := range candidates {
+				e.Rows = append(e.Rows, []string{
+					flatOp.Origin.ExplainID().String(),
+					c.Table,
+					c.AccessPath,
+					strconv.FormatFloat(c.Cost, 'f', 2, 64),
+					strconv.FormatBool(c.Chosen),
+				})
+			}
+		}
+	}
+}
+
+// estimateBufferingMemory estimates the runtime memory of the operator which buffers its input in memory,
+// i.e. the build side of hash join, sort and hash agg, by the estimated rows and the average row size.
+// It's only a best-effort estimation. The second return value is false for the other operators.
+func estimateBufferingMemory(p PhysicalPlan) (int64, bool) {
+	var buffered PhysicalPlan
+	switch x := p.(type) {
+	case *PhysicalHashJoin:
+		buffered = x.children[0]
+		if x.RightIsBuildSide() {
+			buffered = x.children[1]
+		}
+	case *PhysicalSort:
+		buffered = x.children[0]
+	case *PhysicalHashAgg:
+		// The hash agg holds one partial result for every group.
+		buffered = x
+	default:
+		return 0, false
+	}
+	stats := buffered.statsInfo()
+	return int64(stats.RowCount * getAvgRowSize(stats, buffered.Schema().Columns)), true
+}
+
+// flatPlanTreeFingerprints returns the fingerprint of the subtree rooted at every operator of the tree.
+// Only the shape of the plan is hashed: the operator types, the task types, the accessed tables and indexes
+// and the children in order. Estimated row counts, costs and other operator info are ignored, so it keeps
+// stable as long as the plan shape doesn't change.
+func flatPlanTreeFingerprints(tree FlatPlanTree) []string {
+	fingerprints := make([]string, len(tree))
+	// Children are always placed after their parent in the flat plan tree, so compute them backwards.
+	for i := len(tree) - 1; i >= 0; i-- {
+		flatOp := tree[i]
+		h := fnv.New64a()
+		h.Write(hack.Slice(flatOp.Origin.TP()))
+		h.Write([]byte{0})
+		if flatOp.IsRoot {
+			h.Write(hack.Slice("root"))
+		} else {
+			h.Write(hack.Slice(flatOp.ReqType.Name() + "[" + flatOp.StoreType.Name() + "]"))
+		}
+		h.Write([]byte{0})
+		h.Write(hack.Slice(flatOp.Label.String()))
+		h.Write([]byte{0})
+		if accesser, ok := flatOp.Origin.(dataAccesser); ok {
+			h.Write(hack.Slice(accesser.AccessObject().String()))
+		}
+		for _, childIdx := range flatOp.ChildrenIdx {
+			h.Write([]byte{0})
+			h.Write(hack.Slice(fingerprints[childIdx]))
+		}
+		fingerprints[i] = fmt.Sprintf("%016x", h.Sum64())
+	}
+	return fingerprints
+}
+
 func (e *Explain) explainFlatPlanInJSONFormat(flat *FlatPhysicalPlan) (encodes []*ExplainInfoForEncode) {
 	if flat == nil || len(flat.Main) == 0 || flat.InExplain {
 		return
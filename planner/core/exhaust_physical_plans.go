@@ -2740,6 +2740,11 @@ func (lw *LogicalWindow) tryToGetMppWindows(prop *property.PhysicalProperty) []P
 		if !allSupported {
 			return nil
 		}
+		if lw.Frame != nil && lw.Frame.Exclusion != ast.ExcludeNoOthers {
+			lw.SCtx().GetSessionVars().RaiseWarningWhenMPPEnforced(
+				"MPP mode may be blocked because window function frame can't be pushed down, because TiFlash does not support frame exclusion yet.")
+			return nil
+		}
 		if lw.Frame != nil && lw.Frame.Type == ast.Ranges {
 			if _, err := expression.ExpressionsToPBList(lw.SCtx().GetSessionVars().StmtCtx, lw.Frame.Start.CalcFuncs, lw.ctx.GetClient()); err != nil {
 				lw.SCtx().GetSessionVars().RaiseWarningWhenMPPEnforced(
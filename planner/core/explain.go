@@ -582,7 +582,11 @@ func (p *PhysicalHashJoin) explainInfo(normalized bool) string {
 
 	if len(p.EqualConditions) == 0 {
 		if len(p.NAEqualConditions) == 0 {
-			buffer.WriteString("CARTESIAN ")
+			if p.GetJoinBand() != nil {
+				buffer.WriteString("band merge ")
+			} else {
+				buffer.WriteString("CARTESIAN ")
+			}
 		} else {
 			buffer.WriteString("Null-aware ")
 		}
@@ -826,6 +830,14 @@ func (p *PhysicalWindow) ExplainInfo() string {
 		p.formatFrameBound(buffer, p.Frame.Start)
 		buffer.WriteString(" and ")
 		p.formatFrameBound(buffer, p.Frame.End)
+		switch p.Frame.Exclusion {
+		case ast.ExcludeCurrentRow:
+			buffer.WriteString(" exclude current row")
+		case ast.ExcludeGroup:
+			buffer.WriteString(" exclude group")
+		case ast.ExcludeTies:
+			buffer.WriteString(" exclude ties")
+		}
 	}
 	buffer.WriteString(")")
 	if p.TiFlashFineGrainedShuffleStreamCount > 0 {
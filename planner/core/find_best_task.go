@@ -1023,6 +1023,12 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 			}
 		}
 	}()
+	choices := ds.newIndexChoiceRecorder()
+	defer func() {
+		if err == nil {
+			choices.finish(t)
+		}
+	}()
 
 	cntPlan = 0
 	for _, candidate := range candidates {
@@ -1037,6 +1043,9 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 				planCounter.Dec(1)
 			}
 			appendCandidate(ds, idxMergeTask, prop, opt)
+			if err := choices.add(path, idxMergeTask); err != nil {
+				return nil, 0, err
+			}
 
 			curIsBetter, err := compareTaskCost(ds.ctx, idxMergeTask, t, opt)
 			if err != nil {
@@ -1134,6 +1143,9 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 				}
 
 				appendCandidate(ds, pointGetTask, prop, opt)
+				if err := choices.add(path, pointGetTask); err != nil {
+					return nil, 0, err
+				}
 				if !pointGetTask.invalid() {
 					cntPlan++
 					planCounter.Dec(1)
@@ -1172,6 +1184,9 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 				planCounter.Dec(1)
 			}
 			appendCandidate(ds, tblTask, prop, opt)
+			if err := choices.add(path, tblTask); err != nil {
+				return nil, 0, err
+			}
 			curIsBetter, err := compareTaskCost(ds.ctx, tblTask, t, opt)
 			if err != nil {
 				return nil, 0, err
@@ -1197,6 +1212,9 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 			planCounter.Dec(1)
 		}
 		appendCandidate(ds, idxTask, prop, opt)
+		if err := choices.add(path, idxTask); err != nil {
+			return nil, 0, err
+		}
 		curIsBetter, err := compareTaskCost(ds.ctx, idxTask, t, opt)
 		if err != nil {
 			return nil, 0, err
@@ -1524,13 +1542,50 @@ func (ds *DataSource) isIndexCoveringCondition(condition expression.Expression,
 	return true
 }
 
-func (ds *DataSource) isSingleScan(indexColumns []*expression.Column, idxColLens []int) bool {
+// isIndexComputingVirtualColumn checks whether the virtual generated column can be computed by the index reader,
+// that is, all the columns it depends on are stored in the index with the full length.
+func (ds *DataSource) isIndexComputingVirtualColumn(column *expression.Column, index *model.IndexInfo) bool {
+	if column.VirtualExpr == nil || index == nil || index.MVIndex {
+		return false
+	}
+	baseCols := expression.ExtractColumns(column.VirtualExpr)
+	if len(baseCols) == 0 {
+		return false
+	}
+	for _, baseCol := range baseCols {
+		colInfo := FindColumnInfoByID(ds.tableInfo.Columns, baseCol.ID)
+		if colInfo == nil {
+			return false
+		}
+		idxCol := index.FindColumnByName(colInfo.Name.L)
+		if idxCol == nil || idxCol.Length != types.UnspecifiedLength && idxCol.Length != colInfo.GetFlen() {
+			return false
+		}
+	}
+	return true
+}
+
+// isIndexCoveringOrComputingColumns is like isIndexCoveringColumns, but it also regards a virtual generated column
+// as covered if it can be computed by the index reader when tidb_opt_index_reader_virtual_column is on.
+func (ds *DataSource) isIndexCoveringOrComputingColumns(columns, indexColumns []*expression.Column, idxColLens []int, index *model.IndexInfo) bool {
+	for _, col := range columns {
+		if ds.indexCoveringColumn(col, indexColumns, idxColLens, false) {
+			continue
+		}
+		if !ds.ctx.GetSessionVars().OptIndexReaderVirtualColumn || !ds.isIndexComputingVirtualColumn(col, index) {
+			return false
+		}
+	}
+	return true
+}
+
+func (ds *DataSource) isSingleScan(index *model.IndexInfo, indexColumns []*expression.Column, idxColLens []int) bool {
 	if !ds.ctx.GetSessionVars().OptPrefixIndexSingleScan || ds.colsRequiringFullLen == nil {
 		// ds.colsRequiringFullLen is set at (*DataSource).PruneColumns. In some cases we don't reach (*DataSource).PruneColumns
 		// and ds.colsRequiringFullLen is nil, so we fall back to ds.isIndexCoveringColumns(ds.schema.Columns, indexColumns, idxColLens).
-		return ds.isIndexCoveringColumns(ds.schema.Columns, indexColumns, idxColLens)
+		return ds.isIndexCoveringOrComputingColumns(ds.schema.Columns, indexColumns, idxColLens, index)
 	}
-	if !ds.isIndexCoveringColumns(ds.colsRequiringFullLen, indexColumns, idxColLens) {
+	if !ds.isIndexCoveringOrComputingColumns(ds.colsRequiringFullLen, indexColumns, idxColLens, index) {
 		return false
 	}
 	for _, cond := range ds.allConds {
@@ -2676,6 +2731,97 @@ func (p *LogicalCTETable) findBestTask(prop *property.PhysicalProperty, _ *PlanC
 	return t, 1, nil
 }
 
+// indexChoiceRecorder records the estimated costs of the candidate access paths of a data source
+// for "explain format='index_choice'".
+type indexChoiceRecorder struct {
+	ds         *DataSource
+	candidates []stmtctx.IndexChoiceCandidate
+	tasks      []task
+}
+
+func (ds *DataSource) newIndexChoiceRecorder() *indexChoiceRecorder {
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	if !sc.InExplainStmt || strings.ToLower(sc.ExplainFormat) != types.ExplainFormatIndexChoice {
+		return nil
+	}
+	return &indexChoiceRecorder{ds: ds}
+}
+
+func (r *indexChoiceRecorder) add(path *util.AccessPath, t task) error {
+	if r == nil || t == nil || t.invalid() {
+		return nil
+	}
+	cost, _, err := getTaskPlanCost(t, nil)
+	if err != nil {
+		return err
+	}
+	tblName := r.ds.tableInfo.Name.O
+	if r.ds.TableAsName != nil && r.ds.TableAsName.L != "" {
+		tblName = r.ds.TableAsName.O
+	}
+	r.candidates = append(r.candidates, stmtctx.IndexChoiceCandidate{
+		Table:      tblName,
+		AccessPath: accessPathName(path),
+		Cost:       cost,
+	})
+	r.tasks = append(r.tasks, t)
+	return nil
+}
+
+// finish marks the chosen candidate and saves the candidates into the statement context, keyed by
+// the ID of the bottom operator of the chosen task, e.g. the reader of a root task or the scan of a
+// cop task, so that they can be found from the final plan.
+func (r *indexChoiceRecorder) finish(chosen task) {
+	if r == nil || chosen == nil || chosen.invalid() {
+		return
+	}
+	found := false
+	for i, t := range r.tasks {
+		if t == chosen {
+			r.candidates[i].Chosen = true
+			found = true
+		}
+	}
+	if !found {
+		return
+	}
+	var access PhysicalPlan
+	switch x := chosen.(type) {
+	case *copTask:
+		access = x.tablePlan
+		if access == nil {
+			access = x.indexPlan
+		}
+	default:
+		access = chosen.plan()
+	}
+	for access != nil && len(access.Children()) > 0 {
+		access = access.Children()[0]
+	}
+	if access == nil {
+		return
+	}
+	sc := r.ds.ctx.GetSessionVars().StmtCtx
+	if sc.IndexChoices == nil {
+		sc.IndexChoices = make(map[int][]stmtctx.IndexChoiceCandidate)
+	}
+	sc.IndexChoices[access.ID()] = r.candidates
+}
+
+func accessPathName(path *util.AccessPath) string {
+	switch {
+	case path.PartialIndexPaths != nil:
+		return "index_merge"
+	case path.IsTablePath():
+		if path.StoreType == kv.TiFlash {
+			return "table(tiflash)"
+		}
+		return "table"
+	default:
+		return "index:" + path.Index.Name.O
+	}
+}
+
 func appendCandidate(lp LogicalPlan, task task, prop *property.PhysicalProperty, opt *physicalOptimizeOp) {
 	if task == nil || task.invalid() {
 		return
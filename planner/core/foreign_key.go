@@ -176,7 +176,7 @@ func (p *Insert) buildOnInsertFKTriggers(ctx sessionctx.Context, is infoschema.I
 			continue
 		}
 		failedErr := ErrNoReferencedRow2.FastGenByArgs(fk.String(dbName, tblInfo.Name.L))
-		fkCheck, err := buildFKCheckOnModifyChildTable(ctx, is, fk, failedErr)
+		fkCheck, err := BuildFKCheckOnModifyChildTable(ctx, is, fk, failedErr)
 		if err != nil {
 			return err
 		}
@@ -320,7 +320,7 @@ func buildOnUpdateChildFKChecks(ctx sessionctx.Context, is infoschema.InfoSchema
 			continue
 		}
 		failedErr := ErrNoReferencedRow2.FastGenByArgs(fk.String(dbName, tblInfo.Name.L))
-		fkCheck, err := buildFKCheckOnModifyChildTable(ctx, is, fk, failedErr)
+		fkCheck, err := BuildFKCheckOnModifyChildTable(ctx, is, fk, failedErr)
 		if err != nil {
 			return nil, err
 		}
@@ -405,7 +405,9 @@ func isMapContainAnyCols(colsMap map[string]struct{}, cols ...model.CIStr) bool
 	return false
 }
 
-func buildFKCheckOnModifyChildTable(ctx sessionctx.Context, is infoschema.InfoSchema, fk *model.FKInfo, failedErr error) (*FKCheck, error) {
+// BuildFKCheckOnModifyChildTable builds the checker which checks the foreign key values of the child table exist in the
+// referred table. It returns nil if the referred table doesn't exist.
+func BuildFKCheckOnModifyChildTable(ctx sessionctx.Context, is infoschema.InfoSchema, fk *model.FKInfo, failedErr error) (*FKCheck, error) {
 	referTable, err := is.TableByName(fk.RefSchema, fk.RefTable)
 	if err != nil {
 		return nil, nil
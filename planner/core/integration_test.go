@@ -3206,7 +3206,7 @@ func TestIssue29221(t *testing.T) {
 	tk.MustExec("set @@session.sql_select_limit=3;")
 	tk.MustQuery("explain format = 'brief' select * from t where a = 1 or b = 1;").Check(testkit.Rows(
 		"Limit 3.00 root  offset:0, count:3",
-		"└─IndexMerge 3.00 root  type: union",
+		"└─IndexMerge 3.00 root  type: union, limit embedded(offset:0, count:3)",
 		"  ├─Limit(Build) 1.50 cop[tikv]  offset:0, count:3",
 		"  │ └─IndexRangeScan 1.50 cop[tikv] table:t, index:idx_a(a) range:[1,1], keep order:false, stats:pseudo",
 		"  ├─Limit(Build) 1.50 cop[tikv]  offset:0, count:3",
@@ -3214,7 +3214,7 @@ func TestIssue29221(t *testing.T) {
 		"  └─TableRowIDScan(Probe) 3.00 cop[tikv] table:t keep order:false, stats:pseudo"))
 	tk.MustQuery("explain format = 'brief' select /*+ use_index_merge(t) */ * from t where a = 1 or b = 1;").Check(testkit.Rows(
 		"Limit 3.00 root  offset:0, count:3",
-		"└─IndexMerge 3.00 root  type: union",
+		"└─IndexMerge 3.00 root  type: union, limit embedded(offset:0, count:3)",
 		"  ├─Limit(Build) 1.50 cop[tikv]  offset:0, count:3",
 		"  │ └─IndexRangeScan 1.50 cop[tikv] table:t, index:idx_a(a) range:[1,1], keep order:false, stats:pseudo",
 		"  ├─Limit(Build) 1.50 cop[tikv]  offset:0, count:3",
@@ -3228,7 +3228,7 @@ func TestIssue29221(t *testing.T) {
 		"└─TableRowIDScan(Probe) 19.99 cop[tikv] table:t keep order:false, stats:pseudo"))
 	tk.MustQuery("explain format = 'brief' select * from t where a = 1 or b = 1 limit 3;").Check(testkit.Rows(
 		"Limit 3.00 root  offset:0, count:3",
-		"└─IndexMerge 3.00 root  type: union",
+		"└─IndexMerge 3.00 root  type: union, limit embedded(offset:0, count:3)",
 		"  ├─Limit(Build) 1.50 cop[tikv]  offset:0, count:3",
 		"  │ └─IndexRangeScan 1.50 cop[tikv] table:t, index:idx_a(a) range:[1,1], keep order:false, stats:pseudo",
 		"  ├─Limit(Build) 1.50 cop[tikv]  offset:0, count:3",
@@ -3241,7 +3241,7 @@ func TestIssue29221(t *testing.T) {
 		"└─TableRowIDScan(Probe) 19.99 cop[tikv] table:t keep order:false, stats:pseudo"))
 	tk.MustQuery("explain format = 'brief' select /*+ use_index_merge(t) */ * from t where a = 1 or b = 1 limit 3;").Check(testkit.Rows(
 		"Limit 3.00 root  offset:0, count:3",
-		"└─IndexMerge 3.00 root  type: union",
+		"└─IndexMerge 3.00 root  type: union, limit embedded(offset:0, count:3)",
 		"  ├─Limit(Build) 1.50 cop[tikv]  offset:0, count:3",
 		"  │ └─IndexRangeScan 1.50 cop[tikv] table:t, index:idx_a(a) range:[1,1], keep order:false, stats:pseudo",
 		"  ├─Limit(Build) 1.50 cop[tikv]  offset:0, count:3",
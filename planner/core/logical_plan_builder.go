@@ -4787,6 +4787,11 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 		// otherwise we need to check global stats initialized for each partition table
 		if !b.ctx.GetSessionVars().IsDynamicPartitionPruneEnabled() {
 			b.optFlag = b.optFlag | flagPartitionProcessor
+		} else if b.ctx.GetSessionVars().ForceStaticPartitionPrune {
+			// The static prune mode is forced to be compared with the dynamic one, so all the partitioned tables in
+			// the query are accessed in static prune mode.
+			b.optFlag = b.optFlag | flagPartitionProcessor
+			b.ctx.GetSessionVars().StmtCtx.UseDynamicPruneMode = false
 		} else {
 			if !b.ctx.GetSessionVars().StmtCtx.UseDynamicPruneMode {
 				b.optFlag = b.optFlag | flagPartitionProcessor
@@ -5315,7 +5320,8 @@ func (b *PlanBuilder) buildMemTable(_ context.Context, dbName model.CIStr, table
 		p.Extractor = newMetricTableExtractor()
 	case util2.InformationSchemaName.L:
 		switch strings.ToUpper(tableInfo.Name.O) {
-		case infoschema.TableClusterConfig, infoschema.TableClusterLoad, infoschema.TableClusterHardware, infoschema.TableClusterSystemInfo:
+		case infoschema.TableClusterConfig, infoschema.TableClusterLoad, infoschema.TableClusterHardware, infoschema.TableClusterSystemInfo,
+			infoschema.TableClusterPlanCache, infoschema.TableCoprocessorCache:
 			p.Extractor = &ClusterTableExtractor{}
 		case infoschema.TableClusterLog:
 			p.Extractor = &ClusterLogTableExtractor{}
@@ -5346,6 +5352,8 @@ func (b *PlanBuilder) buildMemTable(_ context.Context, dbName model.CIStr, table
 			p.Extractor = &ColumnsTableExtractor{}
 		case infoschema.TableTiKVRegionStatus:
 			p.Extractor = &TiKVRegionStatusExtractor{tablesID: make([]int64, 0)}
+		case infoschema.TableConnectionResourceUsage:
+			p.Extractor = &ConnectionResourceUsageExtractor{}
 		}
 	}
 	return p, nil
@@ -5780,6 +5788,11 @@ func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (
 		}
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, dbName, t.Name.L, "", nil)
 	}
+	if update.Returning != nil && (update.TableRefs.TableRefs.Right != nil || len(tableList) != 1) {
+		// A row of the table may be joined and updated several times, so the RETURNING clause is only
+		// supported by the single table UPDATE.
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("RETURNING clause in the multiple-table UPDATE")
+	}
 
 	oldSchemaLen := p.Schema().Len()
 	if update.Where != nil {
@@ -5824,6 +5837,16 @@ func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (
 	proj.SetChildren(p)
 	p = proj
 
+	var returning *DMLReturning
+	if update.Returning != nil {
+		// The RETURNING clause is built on the columns of the table, rather than the ones of the subqueries in the
+		// assignments.
+		returning, err = b.buildDMLReturning(ctx, p, update.Returning.Fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	utlr := &updatableTableListResolver{}
 	update.Accept(utlr)
 	orderedList, np, allAssignmentsAreConstant, err := b.buildUpdateLists(ctx, utlr.updatableTableList, update.List, p)
@@ -5836,6 +5859,7 @@ func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (
 		OrderedList:               orderedList,
 		AllAssignmentsAreConstant: allAssignmentsAreConstant,
 		VirtualAssignmentsOffset:  len(update.List),
+		Returning:                 returning,
 	}.Init(b.ctx)
 	updt.names = p.OutputNames()
 	// We cannot apply projection elimination when building the subplan, because
@@ -5848,6 +5872,9 @@ func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (
 	if err != nil {
 		return nil, err
 	}
+	if err = updt.Returning.resolveIndices(updt.SelectPlan.Schema()); err != nil {
+		return nil, err
+	}
 	tblID2Handle, err := resolveIndicesForTblID2Handle(b.handleHelper.tailMap(), updt.SelectPlan.Schema())
 	if err != nil {
 		return nil, err
@@ -6150,6 +6177,84 @@ func IsDefaultExprSameColumn(names types.NameSlice, node ast.ExprNode) bool {
 	return false
 }
 
+// buildDMLReturning builds the RETURNING clause of the single table UPDATE and DELETE on the rows produced by p.
+func (b *PlanBuilder) buildDMLReturning(ctx context.Context, p LogicalPlan, fields []*ast.SelectField) (*DMLReturning, error) {
+	b.curClause = fieldList
+	unfoldedFields := make([]*ast.SelectField, 0, len(fields))
+	for _, field := range fields {
+		if field.WildCard == nil {
+			unfoldedFields = append(unfoldedFields, field)
+			continue
+		}
+		list := unfoldWildStar(field, p.OutputNames(), p.Schema().Columns)
+		if len(list) == 0 {
+			return nil, ErrBadTable.GenWithStackByArgs(field.WildCard.Table)
+		}
+		unfoldedFields = append(unfoldedFields, list...)
+	}
+	returning := &DMLReturning{
+		Exprs:  make([]expression.Expression, 0, len(unfoldedFields)),
+		Schema: expression.NewSchema(make([]*expression.Column, 0, len(unfoldedFields))...),
+		Names:  make(types.NameSlice, 0, len(unfoldedFields)),
+	}
+	for _, field := range unfoldedFields {
+		// The uncorrelated subquery may be evaluated into a constant by the rewriter,
+		// so it's checked on the AST rather than on the rewritten plan.
+		checker := &subqueryChecker{}
+		field.Expr.Accept(checker)
+		if checker.hasSubquery {
+			return nil, ErrNotSupportedYet.GenWithStackByArgs("subquery in the RETURNING clause")
+		}
+		expr, _, err := b.rewrite(ctx, field.Expr, p, nil, true)
+		if err != nil {
+			return nil, err
+		}
+		_, name, err := b.buildProjectionField(ctx, p, field, expr)
+		if err != nil {
+			return nil, err
+		}
+		returning.Exprs = append(returning.Exprs, expr)
+		returning.Schema.Append(&expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  expr.GetType(),
+		})
+		returning.Names = append(returning.Names, name)
+	}
+	return returning, nil
+}
+
+// subqueryChecker checks whether the expression contains a subquery.
+type subqueryChecker struct {
+	hasSubquery bool
+}
+
+// Enter implements Visitor interface.
+func (c *subqueryChecker) Enter(in ast.Node) (ast.Node, bool) {
+	if _, ok := in.(*ast.SubqueryExpr); ok {
+		c.hasSubquery = true
+		return in, true
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (*subqueryChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (r *DMLReturning) resolveIndices(schema *expression.Schema) (err error) {
+	if r == nil {
+		return nil
+	}
+	for i, expr := range r.Exprs {
+		r.Exprs[i], err = expr.ResolveIndices(schema)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (b *PlanBuilder) buildDelete(ctx context.Context, ds *ast.DeleteStmt) (Plan, error) {
 	b.pushSelectOffset(0)
 	b.pushTableHints(ds.TableHints, 0)
@@ -6213,7 +6318,7 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, ds *ast.DeleteStmt) (Plan
 	}
 
 	// If the delete is non-qualified it does not require Select Priv
-	if ds.Where == nil && ds.Order == nil {
+	if ds.Where == nil && ds.Order == nil && ds.Returning == nil {
 		b.popVisitInfo()
 	}
 	var authErr error
@@ -6245,10 +6350,19 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, ds *ast.DeleteStmt) (Plan
 	}.Init(b.ctx)
 
 	del.names = p.OutputNames()
+	if ds.Returning != nil {
+		del.Returning, err = b.buildDMLReturning(ctx, p, ds.Returning.Fields)
+		if err != nil {
+			return nil, err
+		}
+	}
 	del.SelectPlan, _, err = DoOptimize(ctx, b.ctx, b.optFlag, p)
 	if err != nil {
 		return nil, err
 	}
+	if err = del.Returning.resolveIndices(del.SelectPlan.Schema()); err != nil {
+		return nil, err
+	}
 
 	tblID2Handle, err := resolveIndicesForTblID2Handle(handleColsMap, del.SelectPlan.Schema())
 	if err != nil {
@@ -6632,7 +6746,7 @@ func (b *PlanBuilder) buildWindowFunctionFrame(ctx context.Context, spec *ast.Wi
 	if frameClause == nil {
 		return nil, nil
 	}
-	frame := &WindowFrame{Type: frameClause.Type}
+	frame := &WindowFrame{Type: frameClause.Type, Exclusion: frameClause.Exclusion}
 	var err error
 	frame.Start, err = b.buildWindowFunctionFrameBound(ctx, spec, orderByItems, &frameClause.Extent.Start)
 	if err != nil {
@@ -6915,8 +7029,9 @@ func (b *PlanBuilder) handleDefaultFrame(spec *ast.WindowSpec, windowFuncName st
 		}
 		return &newSpec, true
 	}
-	// "RANGE/ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING" is equivalent to empty frame.
-	if needFrame && spec.Frame != nil &&
+	// "RANGE/ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING" is equivalent to empty frame,
+	// unless some rows are excluded from the frame.
+	if needFrame && spec.Frame != nil && spec.Frame.Exclusion == ast.ExcludeNoOthers &&
 		spec.Frame.Extent.Start.UnBounded && spec.Frame.Extent.End.UnBounded {
 		newSpec := *spec
 		newSpec.Frame = nil
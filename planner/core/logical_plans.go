@@ -1628,7 +1628,7 @@ func (ds *DataSource) Convert2Gathers() (gathers []LogicalPlan) {
 			path.FullIdxCols, path.FullIdxColLens = expression.IndexInfo2Cols(ds.Columns, ds.schema.Columns, path.Index)
 			path.IdxCols, path.IdxColLens = expression.IndexInfo2PrefixCols(ds.Columns, ds.schema.Columns, path.Index)
 			// If index columns can cover all of the needed columns, we can use a IndexGather + IndexScan.
-			if ds.isSingleScan(path.FullIdxCols, path.FullIdxColLens) {
+			if ds.isSingleScan(path.Index, path.FullIdxCols, path.FullIdxColLens) {
 				gathers = append(gathers, ds.buildIndexGather(path))
 			}
 			// TODO: If index columns can not cover the schema, use IndexLookUpGather.
@@ -1993,6 +1993,9 @@ type WindowFrame struct {
 	Type  ast.FrameType
 	Start *FrameBound
 	End   *FrameBound
+	// Exclusion is the EXCLUDE option of the frame, rows excluded are not
+	// taken into account when evaluating the window function.
+	Exclusion ast.FrameExclusion
 }
 
 // Clone copies a window frame totally.
@@ -2089,6 +2092,7 @@ func (p *LogicalWindow) EqualFrame(ctx sessionctx.Context, newWindow *LogicalWin
 		return true
 	}
 	if p.Frame.Type != newWindow.Frame.Type ||
+		p.Frame.Exclusion != newWindow.Frame.Exclusion ||
 		p.Frame.Start.Type != newWindow.Frame.Start.Type ||
 		p.Frame.Start.UnBounded != newWindow.Frame.Start.UnBounded ||
 		p.Frame.Start.Num != newWindow.Frame.Start.Num ||
@@ -2209,8 +2213,17 @@ type ShowContents struct {
 	IfNotExists bool       // Used for `show create database if not exists`.
 	GlobalScope bool       // Used by show variables.
 	Extended    bool       // Used for `show extended columns from ...`
+	LiveStats   bool       // Used for `show index from ... with live stats`
 	Limit       *ast.Limit // Used for limit Result Set row number.
 
+	HealthyThreshold *int64 // Used for `show stats_healthy where healthy < N`
+	// StatsDBFilter and StatsTableFilter are used for `show stats_locked where db_name = 'x' and table_name = 'y'`.
+	StatsDBFilter    string
+	StatsTableFilter string
+	// StatsBucketLower and StatsBucketUpper are the inclusive bounds used for `show stats_buckets where bucket_id between a and b`.
+	StatsBucketLower *int64
+	StatsBucketUpper *int64
+
 	ImportJobID *int64 // Used for SHOW LOAD DATA JOB <jobID>
 }
 
@@ -2223,7 +2236,7 @@ func (s *ShowContents) MemoryUsage() (sum int64) {
 	}
 
 	sum = emptyShowContentsSize + int64(len(s.DBName)) + s.Partition.MemoryUsage() + s.IndexName.MemoryUsage() +
-		int64(cap(s.Roles))*size.SizeOfPointer
+		int64(cap(s.Roles))*size.SizeOfPointer + int64(len(s.StatsDBFilter)+len(s.StatsTableFilter))
 	return
 }
 
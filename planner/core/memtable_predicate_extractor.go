@@ -1166,6 +1166,9 @@ type SlowQueryExtractor struct {
 	// current slow-log file.
 	Enable bool
 	Desc   bool
+	// Limit is the count of the slowest queries to retrieve, which is captured from the pushed down
+	// `ORDER BY query_time DESC LIMIT N`. Limit is 0 means all the slow queries should be retrieved.
+	Limit uint64
 }
 
 // TimeRange is used to check whether a given log should be extracted.
@@ -1677,3 +1680,45 @@ func (e *TiKVRegionStatusExtractor) explainInfo(_ *PhysicalMemTable) string {
 func (e *TiKVRegionStatusExtractor) GetTablesID() []int64 {
 	return e.tablesID
 }
+
+// ConnectionResourceUsageExtractor is used to extract some predicates of `connection_resource_usage` table.
+type ConnectionResourceUsageExtractor struct {
+	extractHelper
+
+	// SkipRequest means the where clause always false, we don't need to read any connection
+	SkipRequest bool
+
+	// ConnectionIDs represents all connection ids we should filter in memtable.
+	// e.g: SELECT * FROM connection_resource_usage WHERE id in (1, 2)
+	ConnectionIDs []uint64
+}
+
+// Extract implements the MemTablePredicateExtractor Extract interface
+func (e *ConnectionResourceUsageExtractor) Extract(_ sessionctx.Context,
+	schema *expression.Schema,
+	names []*types.FieldName,
+	predicates []expression.Expression,
+) []expression.Expression {
+	remained, skipRequest, connIDs := e.extractCol(schema, names, predicates, "id", false)
+	e.SkipRequest = skipRequest
+	if e.SkipRequest {
+		return nil
+	}
+	e.ConnectionIDs = e.parseUint64(connIDs)
+	// None of the given ids is a valid connection id.
+	if len(connIDs) > 0 && len(e.ConnectionIDs) == 0 {
+		e.SkipRequest = true
+		return nil
+	}
+	return remained
+}
+
+func (e *ConnectionResourceUsageExtractor) explainInfo(_ *PhysicalMemTable) string {
+	if e.SkipRequest {
+		return "skip_request:true"
+	}
+	if len(e.ConnectionIDs) > 0 {
+		return fmt.Sprintf("ids:[%s]", extractStringFromUint64Slice(e.ConnectionIDs))
+	}
+	return ""
+}
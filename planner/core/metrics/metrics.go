@@ -30,6 +30,7 @@ var (
 	nonPreparedPlanCacheUnsupportedCounter prometheus.Counter
 	sessionPlanCacheInstancePlanNumCounter prometheus.Gauge
 	sessionPlanCacheInstanceMemoryUsage    prometheus.Gauge
+	sessionPlanCacheEvictionCounter        prometheus.Counter
 )
 
 func init() {
@@ -48,6 +49,7 @@ func InitMetricsVars() {
 	nonPreparedPlanCacheUnsupportedCounter = metrics.PlanCacheMissCounter.WithLabelValues("non-prepared-unsupported")
 	sessionPlanCacheInstancePlanNumCounter = metrics.PlanCacheInstancePlanNumCounter.WithLabelValues(" session-plan-cache")
 	sessionPlanCacheInstanceMemoryUsage = metrics.PlanCacheInstanceMemoryUsage.WithLabelValues(" session-plan-cache")
+	sessionPlanCacheEvictionCounter = metrics.PlanCacheEvictionCounter.WithLabelValues(" session-plan-cache")
 }
 
 // GetPlanCacheHitCounter get different plan cache hit counter
@@ -80,3 +82,8 @@ func GetPlanCacheInstanceNumCounter() prometheus.Gauge {
 func GetPlanCacheInstanceMemoryUsage() prometheus.Gauge {
 	return sessionPlanCacheInstanceMemoryUsage
 }
+
+// GetPlanCacheEvictionCounter get the plan eviction counter of plan cache
+func GetPlanCacheEvictionCounter() prometheus.Counter {
+	return sessionPlanCacheEvictionCounter
+}
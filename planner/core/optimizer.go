@@ -311,14 +311,19 @@ func DoOptimizeAndLogicAsRet(ctx context.Context, sctx sessionctx.Context, flag
 	if planCounter == 0 {
 		planCounter = -1
 	}
+	ruleStats, _ := ctx.Value(tracing.OptRuleStatsKey).(*tracing.OptRuleStats)
+	start := time.Now()
 	physical, cost, err := physicalOptimize(logic, &planCounter)
 	if err != nil {
 		return nil, nil, 0, err
 	}
+	ruleStats.Record(tracing.OptRulePhasePhysical, "find_best_task", time.Since(start))
+	start = time.Now()
 	finalPlan, err := postOptimize(ctx, sctx, physical)
 	if err != nil {
 		return nil, nil, 0, err
 	}
+	ruleStats.Record(tracing.OptRulePhasePhysical, "post_optimize", time.Since(start))
 
 	if sessVars.StmtCtx.EnableOptimizerCETrace {
 		refineCETrace(sctx)
@@ -1126,6 +1131,8 @@ func logicalOptimize(ctx context.Context, flag uint64, logic LogicalPlan) (Logic
 			vars.StmtCtx.OptimizeTracer.Logical = tracer
 		}()
 	}
+	// ruleStats is only set by `TRACE FORMAT='opt_rules'`.
+	ruleStats, _ := ctx.Value(tracing.OptRuleStatsKey).(*tracing.OptRuleStats)
 	var err error
 	for i, rule := range optRuleList {
 		// The order of flags is same as the order of optRule in the list.
@@ -1135,10 +1142,12 @@ func logicalOptimize(ctx context.Context, flag uint64, logic LogicalPlan) (Logic
 			continue
 		}
 		opt.appendBeforeRuleOptimize(i, rule.name(), logic)
+		start := time.Now()
 		logic, err = rule.optimize(ctx, logic, opt)
 		if err != nil {
 			return nil, err
 		}
+		ruleStats.Record(tracing.OptRulePhaseLogical, rule.name(), time.Since(start))
 	}
 	opt.recordFinalLogicalPlan(logic)
 	return logic, err
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tipb/go-tipb"
 )
@@ -58,6 +59,7 @@ func (b *PBPlanBuilder) Build(executors []*tipb.Executor) (p PhysicalPlan, err e
 		src = curr
 	}
 	_, src = b.predicatePushDown(src, nil)
+	b.topNPushDown(src)
 	return src, nil
 }
 
@@ -259,6 +261,29 @@ func (*PBPlanBuilder) pbToKill(e *tipb.Executor) (PhysicalPlan, error) {
 	return &PhysicalSimpleWrapper{Inner: simple}, nil
 }
 
+// topNPushDown captures the `ORDER BY query_time DESC LIMIT N` right above the slow log scan,
+// so that only the slowest N queries of this instance are kept and sent back.
+func (*PBPlanBuilder) topNPushDown(physicalPlan PhysicalPlan) {
+	topN, ok := physicalPlan.(*PhysicalTopN)
+	if !ok || len(topN.ByItems) != 1 || !topN.ByItems[0].Desc {
+		return
+	}
+	memTable, ok := topN.Children()[0].(*PhysicalMemTable)
+	if !ok {
+		return
+	}
+	extractor, ok := memTable.Extractor.(*SlowQueryExtractor)
+	if !ok {
+		return
+	}
+	col, ok := topN.ByItems[0].Expr.(*expression.Column)
+	if !ok || col.Index >= len(memTable.Columns) ||
+		!strings.EqualFold(memTable.Columns[col.Index].Name.O, variable.SlowLogQueryTimeStr) {
+		return
+	}
+	extractor.Limit = topN.Offset + topN.Count
+}
+
 func (b *PBPlanBuilder) predicatePushDown(physicalPlan PhysicalPlan, predicates []expression.Expression) ([]expression.Expression, PhysicalPlan) {
 	if physicalPlan == nil {
 		return predicates, physicalPlan
@@ -343,11 +343,59 @@ func (p *PhysicalIndexReader) SetSchema(_ *expression.Schema) {
 		default:
 			is := p.IndexPlans[0].(*PhysicalIndexScan)
 			p.schema = is.dataSourceSchema
+			if p.expandVirtualColumn(is) {
+				return
+			}
 		}
 		p.OutputColumns = p.schema.Clone().Columns
 	}
 }
 
+// expandVirtualColumn handles the virtual generated columns which are not stored in the index but can be computed
+// from the index columns. They are moved to the end of the schema and excluded from the OutputColumns, and the
+// columns they depend on are appended to both of them, so the executor can compute them in TiDB.
+func (p *PhysicalIndexReader) expandVirtualColumn(is *PhysicalIndexScan) bool {
+	idxSchema := is.Schema()
+	isStoredInIndex := func(col *expression.Column) bool {
+		for _, idxCol := range idxSchema.Columns {
+			if idxCol.ID == col.ID || col.EqualByExprAndID(nil, idxCol) {
+				return true
+			}
+		}
+		return false
+	}
+	var computedCols []*expression.Column
+	cols := make([]*expression.Column, 0, is.dataSourceSchema.Len())
+	for _, col := range is.dataSourceSchema.Columns {
+		if col.VirtualExpr != nil && !isStoredInIndex(col) {
+			computedCols = append(computedCols, col)
+			continue
+		}
+		cols = append(cols, col)
+	}
+	if len(computedCols) == 0 {
+		return false
+	}
+	outputCols := util.CloneCols(cols)
+	for _, computedCol := range computedCols {
+		for _, baseCol := range expression.ExtractColumns(computedCol.VirtualExpr) {
+			if expression.NewSchema(cols...).Contains(baseCol) {
+				continue
+			}
+			for _, idxCol := range idxSchema.Columns {
+				if idxCol.ID == baseCol.ID {
+					cols = append(cols, baseCol)
+					outputCols = append(outputCols, idxCol.Clone().(*expression.Column))
+					break
+				}
+			}
+		}
+	}
+	p.schema = expression.NewSchema(append(cols, computedCols...)...)
+	p.OutputColumns = outputCols
+	return true
+}
+
 // SetChildren overrides PhysicalPlan SetChildren interface.
 func (p *PhysicalIndexReader) SetChildren(children ...PhysicalPlan) {
 	p.indexPlan = children[0]
@@ -1385,6 +1433,137 @@ func (p *PhysicalHashJoin) RightIsBuildSide() bool {
 	return p.InnerChildIdx != 0
 }
 
+// JoinBand is the range join condition `Lower <= Point <= Upper` of a band merge join, the
+// Point column belongs to the outer child at OuterIdx and the bound columns belong to the
+// inner child.
+type JoinBand struct {
+	OuterIdx int
+	Point    *expression.Column
+	Lower    *expression.Column
+	Upper    *expression.Column
+}
+
+// GetJoinBand returns the join band if the hash join is executed by the band merge join, it
+// returns nil if tidb_enable_band_join is off or the join conditions aren't all ranges
+// between the two children.
+func (p *PhysicalHashJoin) GetJoinBand() *JoinBand {
+	if !p.SCtx().GetSessionVars().EnableBandJoin || p.storeTp == kv.TiFlash {
+		return nil
+	}
+	if len(p.EqualConditions) > 0 || len(p.NAEqualConditions) > 0 || len(p.OtherConditions) == 0 {
+		return nil
+	}
+	var outerIdxs []int
+	switch p.JoinType {
+	case InnerJoin:
+		// Either child can be the outer side of the inner join.
+		outerIdxs = []int{0, 1}
+	case LeftOuterJoin, SemiJoin, AntiSemiJoin:
+		outerIdxs = []int{0}
+	case RightOuterJoin:
+		outerIdxs = []int{1}
+	default:
+		// LeftOuterSemiJoin and AntiLeftOuterSemiJoin distinguish the null results of the join conditions
+		// from false, but the inner rows with null bounds are never taken as the candidates.
+		return nil
+	}
+	for _, outerIdx := range outerIdxs {
+		band := p.extractJoinBand(outerIdx)
+		if band == nil {
+			continue
+		}
+		innerFilter := p.RightConditions
+		if outerIdx == 1 {
+			innerFilter = p.LeftConditions
+		}
+		if len(innerFilter) > 0 {
+			return nil
+		}
+		return band
+	}
+	return nil
+}
+
+// extractJoinBand extracts the join band from the other conditions of the join, the point
+// column is chosen from the child at outerIdx. It returns nil if any condition isn't a range
+// between the columns of the two children, or there is no point column bounded on both sides.
+func (p *PhysicalHashJoin) extractJoinBand(outerIdx int) *JoinBand {
+	outerSchema, innerSchema := p.children[outerIdx].Schema(), p.children[1-outerIdx].Schema()
+	var points []*expression.Column
+	lowers := make(map[int64]*expression.Column)
+	uppers := make(map[int64]*expression.Column)
+	for _, cond := range p.OtherConditions {
+		sf, ok := cond.(*expression.ScalarFunction)
+		if !ok {
+			return nil
+		}
+		funcName := sf.FuncName.L
+		if funcName != ast.GE && funcName != ast.GT && funcName != ast.LE && funcName != ast.LT {
+			return nil
+		}
+		args := sf.GetArgs()
+		lCol, lOk := args[0].(*expression.Column)
+		rCol, rOk := args[1].(*expression.Column)
+		if !lOk || !rOk {
+			return nil
+		}
+		point, bound := lCol, rCol
+		if !outerSchema.Contains(point) {
+			// Make the outer column be the left argument.
+			point, bound = rCol, lCol
+			switch funcName {
+			case ast.GE:
+				funcName = ast.LE
+			case ast.GT:
+				funcName = ast.LT
+			case ast.LE:
+				funcName = ast.GE
+			case ast.LT:
+				funcName = ast.GT
+			}
+		}
+		if !outerSchema.Contains(point) || !innerSchema.Contains(bound) {
+			return nil
+		}
+		bounds := uppers
+		if funcName == ast.GE || funcName == ast.GT {
+			bounds = lowers
+		}
+		if _, ok := lowers[point.UniqueID]; !ok {
+			if _, ok := uppers[point.UniqueID]; !ok {
+				points = append(points, point)
+			}
+		}
+		if _, ok := bounds[point.UniqueID]; !ok {
+			bounds[point.UniqueID] = bound
+		}
+	}
+	for _, point := range points {
+		lower, upper := lowers[point.UniqueID], uppers[point.UniqueID]
+		if lower == nil || upper == nil || !bandColumnsComparable(point, lower, upper) {
+			continue
+		}
+		return &JoinBand{OuterIdx: outerIdx, Point: point, Lower: lower, Upper: upper}
+	}
+	return nil
+}
+
+// bandColumnsComparable checks whether the columns are compared in the same way by the
+// join conditions and by the sort, so the order of the rows is consistent with the conditions.
+func bandColumnsComparable(cols ...*expression.Column) bool {
+	tp := cols[0].GetType()
+	for _, col := range cols {
+		ft := col.GetType()
+		if ft.Hybrid() || ft.EvalType() == types.ETJson || ft.EvalType() != tp.EvalType() {
+			return false
+		}
+		if ft.EvalType() == types.ETString && ft.GetCollate() != tp.GetCollate() {
+			return false
+		}
+	}
+	return true
+}
+
 // NewPhysicalHashJoin creates a new PhysicalHashJoin from LogicalJoin.
 func NewPhysicalHashJoin(p *LogicalJoin, innerIdx int, useOuterToBuild bool, newStats *property.StatsInfo, prop ...*property.PhysicalProperty) *PhysicalHashJoin {
 	leftJoinKeys, rightJoinKeys, isNullEQ, _ := p.GetJoinKeys()
@@ -168,6 +168,12 @@ func GetPlanFromSessionPlanCache(ctx context.Context, sctx sessionctx.Context,
 			stmtCtx.SetSkipPlanCache(errors.Errorf("ignore plan cache by binding"))
 		}
 	}
+	if stmtAst.CachedPlan != nil && stmt.PointPlanBindSQL != bindSQL {
+		// The binding of this statement has been changed since the point plan was cached,
+		// so the cached point plan and executor are out of date and the plan should be re-optimized.
+		stmtAst.CachedPlan = nil
+		stmt.Executor = nil
+	}
 
 	// In rc or for update read, we need the latest schema version to decide whether we need to
 	// rebuild the plan. So we set this value in rc or for update read. In other cases, let it be 0.
@@ -304,7 +310,7 @@ func generateNewPlan(ctx context.Context, sctx sessionctx.Context, isNonPrepared
 	if err != nil {
 		return nil, nil, err
 	}
-	err = tryCachePointPlan(ctx, sctx, stmt, is, p)
+	err = tryCachePointPlan(ctx, sctx, stmt, is, p, bindSQL)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -746,7 +752,7 @@ func CheckPreparedPriv(sctx sessionctx.Context, stmt *PlanCacheStmt, is infosche
 // tryCachePointPlan will try to cache point execution plan, there may be some
 // short paths for these executions, currently "point select" and "point update"
 func tryCachePointPlan(_ context.Context, sctx sessionctx.Context,
-	stmt *PlanCacheStmt, _ infoschema.InfoSchema, p Plan) error {
+	stmt *PlanCacheStmt, _ infoschema.InfoSchema, p Plan, bindSQL string) error {
 	if !sctx.GetSessionVars().StmtCtx.UseCache {
 		return nil
 	}
@@ -769,6 +775,7 @@ func tryCachePointPlan(_ context.Context, sctx sessionctx.Context,
 		// just cache point plan now
 		stmtAst.CachedPlan = p
 		stmtAst.CachedNames = names
+		stmt.PointPlanBindSQL = bindSQL
 		stmt.NormalizedPlan, stmt.PlanDigest = NormalizePlan(p)
 		sctx.GetSessionVars().StmtCtx.SetPlan(p)
 		sctx.GetSessionVars().StmtCtx.SetPlanDigest(stmt.NormalizedPlan, stmt.PlanDigest)
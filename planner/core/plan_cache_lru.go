@@ -220,6 +220,7 @@ func (l *LRUPlanCache) removeOldest() {
 	if l.onEvict != nil {
 		l.onEvict(lru.Value.(*planCacheEntry).PlanKey, lru.Value.(*planCacheEntry).PlanValue)
 	}
+	core_metrics.GetPlanCacheEvictionCounter().Inc()
 
 	l.updateInstanceMetric(nil, lru.Value.(*planCacheEntry))
 	l.lruList.Remove(lru)
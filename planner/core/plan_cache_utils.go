@@ -433,6 +433,9 @@ type PlanCacheStmt struct {
 	// Notice that we should only cache the PointGetExecutor that have a snapshot with MaxTS in it.
 	// If the current plan is not PointGet or does not use MaxTS optimization, this value should be nil here.
 	Executor interface{}
+	// PointPlanBindSQL is the bindSQL used when the point plan was cached in PreparedAst.CachedPlan,
+	// the cached point plan is invalidated once the binding of this statement changes.
+	PointPlanBindSQL string
 
 	StmtCacheable     bool   // Whether this stmt is cacheable.
 	UncacheableReason string // Why this stmt is uncacheable.
@@ -1989,8 +1989,9 @@ func (b *PlanBuilder) buildAdminCheckTable(ctx context.Context, as *ast.AdminStm
 		return nil, infoschema.ErrTableNotExists.GenWithStackByArgs(tblName.DBInfo.Name.O, tableInfo.Name.O)
 	}
 	p := &CheckTable{
-		DBName: tblName.Schema.O,
-		Table:  tbl,
+		DBName:  tblName.Schema.O,
+		Table:   tbl,
+		CheckFK: as.Tp == ast.AdminCheckTable && b.ctx.GetSessionVars().AdminCheckForeignKey,
 	}
 	var readerPlans []Plan
 	var indexInfos []*model.IndexInfo
@@ -2459,6 +2460,7 @@ func (b *PlanBuilder) buildAnalyzeFullSamplingTask(
 			TableID:       statistics.AnalyzeTableID{TableID: tbl.TableInfo.ID, PartitionID: id},
 			Incremental:   false,
 			StatsVersion:  version,
+			HistogramOnly: as.HistogramOnly,
 		}
 		if optsV2, ok := optionsMap[physicalID]; ok {
 			info.V2Options = &optsV2
@@ -3030,6 +3032,9 @@ func (b *PlanBuilder) buildAnalyze(as *ast.AnalyzeTableStmt) (Plan, error) {
 	if b.ctx.GetSessionVars().EnableFastAnalyze && statsVersion >= statistics.Version2 {
 		return nil, errors.Errorf("Fast analyze hasn't reached General Availability and only support analyze version 1 currently")
 	}
+	if as.HistogramOnly && (statsVersion < statistics.Version2 || b.ctx.GetSessionVars().EnableFastAnalyze) {
+		return nil, errors.Errorf("Only the analyze version 2 supports analyzing the histogram only")
+	}
 	for _, tbl := range as.TableNames {
 		user := b.ctx.GetSessionVars().User
 		var insertErr, selectErr error
@@ -3050,7 +3055,29 @@ func (b *PlanBuilder) buildAnalyze(as *ast.AnalyzeTableStmt) (Plan, error) {
 		}
 		return b.buildAnalyzeIndex(as, opts, statsVersion)
 	}
-	return b.buildAnalyzeTable(as, opts, statsVersion)
+	p, err := b.buildAnalyzeTable(as, opts, statsVersion)
+	if err != nil {
+		return nil, err
+	}
+	if as.DryRun {
+		analyze := p.(*Analyze)
+		analyze.DryRun = true
+		analyze.setSchemaAndNames(buildAnalyzeDryRunFields())
+	}
+	return p, nil
+}
+
+func buildAnalyzeDryRunFields() (*expression.Schema, types.NameSlice) {
+	schema := newColumnsWithNames(8)
+	schema.Append(buildColumnWithName("", "Table_schema", mysql.TypeVarchar, mysql.MaxDatabaseNameLength))
+	schema.Append(buildColumnWithName("", "Table_name", mysql.TypeVarchar, mysql.MaxTableNameLength))
+	schema.Append(buildColumnWithName("", "Partition_name", mysql.TypeVarchar, mysql.MaxTableNameLength))
+	schema.Append(buildColumnWithName("", "Job_info", mysql.TypeVarchar, 256))
+	schema.Append(buildColumnWithName("", "Sample_rate", mysql.TypeDouble, 22))
+	schema.Append(buildColumnWithName("", "Sample_size", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "Sample_step", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "Est_memory", mysql.TypeLonglong, 4))
+	return schema.col2Schema(), schema.names
 }
 
 func buildShowNextRowID() (*expression.Schema, types.NameSlice) {
@@ -3223,13 +3250,39 @@ func buildBackupRestoreSchema(kind ast.BRIEKind) (*expression.Schema, types.Name
 	return schema.col2Schema(), schema.names
 }
 
-func buildBRIESchema(kind ast.BRIEKind) (*expression.Schema, types.NameSlice) {
-	switch kind {
+func buildBackupValidateSchema() (*expression.Schema, types.NameSlice) {
+	longlongSize, _ := mysql.GetDefaultFieldLengthAndDecimal(mysql.TypeLonglong)
+
+	schema := newColumnsWithNames(4)
+	schema.Append(buildColumnWithName("", "Name", mysql.TypeVarchar, 255))
+	schema.Append(buildColumnWithName("", "Size", mysql.TypeLonglong, longlongSize))
+	schema.Append(buildColumnWithName("", "Result", mysql.TypeVarchar, 8))
+	schema.Append(buildColumnWithName("", "Message", mysql.TypeVarchar, 1024))
+	return schema.col2Schema(), schema.names
+}
+
+func isBackupValidate(stmt *ast.BRIEStmt) bool {
+	if stmt.Kind != ast.BRIEKindBackup {
+		return false
+	}
+	for _, opt := range stmt.Options {
+		if opt.Tp == ast.BRIEOptionValidate {
+			return true
+		}
+	}
+	return false
+}
+
+func buildBRIESchema(stmt *ast.BRIEStmt) (*expression.Schema, types.NameSlice) {
+	switch kind := stmt.Kind; kind {
 	case ast.BRIEKindShowBackupMeta:
 		return buildShowBackupMetaSchema()
 	case ast.BRIEKindShowQuery:
 		return buildShowBackupQuerySchema()
 	case ast.BRIEKindBackup, ast.BRIEKindRestore:
+		if isBackupValidate(stmt) {
+			return buildBackupValidateSchema()
+		}
 		return buildBackupRestoreSchema(kind)
 	default:
 		s := newColumnsWithNames(0)
@@ -3314,6 +3367,182 @@ func splitWhere(where ast.ExprNode) []ast.ExprNode {
 	return conditions
 }
 
+// extractStatsHealthyThreshold extracts the upper bound of the healthy from the `healthy < N` or `healthy <= N`
+// conditions of `show stats_healthy`. The conditions are still evaluated by the selection, so the threshold only
+// needs to skip the tables which are filtered out for sure.
+func extractStatsHealthyThreshold(where ast.ExprNode) *int64 {
+	var threshold *int64
+	for _, cond := range splitWhere(where) {
+		expr, ok := cond.(*ast.BinaryOperationExpr)
+		if !ok {
+			continue
+		}
+		op, col, val := expr.Op, expr.L, expr.R
+		if _, ok := col.(*ast.ColumnNameExpr); !ok {
+			// Normalize `N > healthy` to `healthy < N`.
+			switch op {
+			case opcode.GT:
+				op = opcode.LT
+			case opcode.GE:
+				op = opcode.LE
+			default:
+				continue
+			}
+			col, val = val, col
+		}
+		colName, ok := col.(*ast.ColumnNameExpr)
+		if !ok || colName.Name.Table.L != "" || colName.Name.Name.L != "healthy" {
+			continue
+		}
+		valExpr, ok := val.(*driver.ValueExpr)
+		if !ok || valExpr.Kind() != types.KindInt64 && valExpr.Kind() != types.KindUint64 {
+			continue
+		}
+		bound := valExpr.GetInt64()
+		if valExpr.Kind() == types.KindUint64 && valExpr.GetUint64() > math.MaxInt64 {
+			continue
+		}
+		switch op {
+		case opcode.LT:
+		case opcode.LE:
+			if bound == math.MaxInt64 {
+				continue
+			}
+			bound++
+		default:
+			continue
+		}
+		if threshold == nil || bound < *threshold {
+			threshold = &bound
+		}
+	}
+	return threshold
+}
+
+// extractStatsTableFilter extracts the names from the `db_name = 'x'` and `table_name = 'y'` conditions of
+// `show stats_locked`, lower-cased to be compared with the lower-cased schema names. The conditions are still
+// evaluated by the selection with their own collation, the names only skip the tables which are filtered out for sure.
+func extractStatsTableFilter(where ast.ExprNode) (dbName, tblName string) {
+	for _, cond := range splitWhere(where) {
+		expr, ok := cond.(*ast.BinaryOperationExpr)
+		if !ok || expr.Op != opcode.EQ {
+			continue
+		}
+		col, val := expr.L, expr.R
+		if _, ok := col.(*ast.ColumnNameExpr); !ok {
+			col, val = val, col
+		}
+		colName, ok := col.(*ast.ColumnNameExpr)
+		if !ok || colName.Name.Table.L != "" {
+			continue
+		}
+		valExpr, ok := val.(*driver.ValueExpr)
+		if !ok || valExpr.Kind() != types.KindString {
+			continue
+		}
+		switch colName.Name.Name.L {
+		case "db_name":
+			dbName = strings.ToLower(valExpr.GetString())
+		case "table_name":
+			tblName = strings.ToLower(valExpr.GetString())
+		}
+	}
+	return dbName, tblName
+}
+
+// extractStatsBucketRange extracts the inclusive bounds of the bucket_id from the `bucket_id between a and b` and
+// the comparison conditions of `show stats_buckets`. The conditions are still evaluated by the selection, so the
+// bounds only skip the buckets which are filtered out for sure.
+func extractStatsBucketRange(where ast.ExprNode) (lower, upper *int64) {
+	setLower := func(v int64) {
+		if lower == nil || v > *lower {
+			lower = &v
+		}
+	}
+	setUpper := func(v int64) {
+		if upper == nil || v < *upper {
+			upper = &v
+		}
+	}
+	for _, cond := range splitWhere(where) {
+		switch expr := cond.(type) {
+		case *ast.BetweenExpr:
+			if expr.Not || !isStatsBucketIDColumn(expr.Expr) {
+				continue
+			}
+			if v, ok := statsBucketIDBound(expr.Left); ok {
+				setLower(v)
+			}
+			if v, ok := statsBucketIDBound(expr.Right); ok {
+				setUpper(v)
+			}
+		case *ast.BinaryOperationExpr:
+			op, col, val := expr.Op, expr.L, expr.R
+			if !isStatsBucketIDColumn(col) {
+				// Normalize `N > bucket_id` to `bucket_id < N`.
+				switch op {
+				case opcode.GT:
+					op = opcode.LT
+				case opcode.GE:
+					op = opcode.LE
+				case opcode.LT:
+					op = opcode.GT
+				case opcode.LE:
+					op = opcode.GE
+				}
+				col, val = val, col
+			}
+			if !isStatsBucketIDColumn(col) {
+				continue
+			}
+			v, ok := statsBucketIDBound(val)
+			if !ok {
+				continue
+			}
+			switch op {
+			case opcode.EQ:
+				setLower(v)
+				setUpper(v)
+			case opcode.GT:
+				if v < math.MaxInt64 {
+					setLower(v + 1)
+				}
+			case opcode.GE:
+				setLower(v)
+			case opcode.LT:
+				if v > math.MinInt64 {
+					setUpper(v - 1)
+				}
+			case opcode.LE:
+				setUpper(v)
+			}
+		}
+	}
+	return lower, upper
+}
+
+func isStatsBucketIDColumn(expr ast.ExprNode) bool {
+	colName, ok := expr.(*ast.ColumnNameExpr)
+	return ok && colName.Name.Table.L == "" && colName.Name.Name.L == "bucket_id"
+}
+
+func statsBucketIDBound(expr ast.ExprNode) (int64, bool) {
+	valExpr, ok := expr.(*driver.ValueExpr)
+	if !ok {
+		return 0, false
+	}
+	switch valExpr.Kind() {
+	case types.KindInt64:
+		return valExpr.GetInt64(), true
+	case types.KindUint64:
+		if valExpr.GetUint64() > math.MaxInt64 {
+			return 0, false
+		}
+		return valExpr.GetInt64(), true
+	}
+	return 0, false
+}
+
 func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan, error) {
 	p := LogicalShow{
 		ShowContents: ShowContents{
@@ -3332,6 +3561,7 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 			IfNotExists:           show.IfNotExists,
 			GlobalScope:           show.GlobalScope,
 			Extended:              show.Extended,
+			LiveStats:             show.LiveStats,
 			Limit:                 show.Limit,
 			ImportJobID:           show.ImportJobID,
 		},
@@ -3404,6 +3634,7 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 				p.Extractor = extractor
 				buildPattern = false
 			}
+			p.HealthyThreshold = extractStatsHealthyThreshold(show.Where)
 		}
 	case ast.ShowStatsBuckets, ast.ShowStatsHistograms, ast.ShowStatsMeta, ast.ShowStatsLocked:
 		var err error
@@ -3411,6 +3642,17 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 			err = ErrTableaccessDenied.GenWithStackByArgs("SHOW", user.AuthUsername, user.AuthHostname, show.Table.Name.L)
 		}
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, show.Table.Schema.L, show.Table.Name.L, "", err)
+		switch show.Tp {
+		case ast.ShowStatsLocked:
+			p.StatsDBFilter, p.StatsTableFilter = extractStatsTableFilter(show.Where)
+		case ast.ShowStatsBuckets:
+			p.StatsBucketLower, p.StatsBucketUpper = extractStatsBucketRange(show.Where)
+		}
+	case ast.ShowProfile:
+		// Only the runtime stats of the last statement are kept in the session.
+		if show.ShowProfileArgs != nil {
+			return nil, ErrNotSupportedYet.GenWithStackByArgs("SHOW PROFILE FOR QUERY")
+		}
 	case ast.ShowRegions:
 		tableInfo, err := b.is.TableByName(show.Table.Schema, show.Table.Name)
 		if err != nil {
@@ -3446,8 +3688,12 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 			return nil, err
 		}
 	}
-	if show.Limit != nil {
-		np, err = b.buildLimit(np, show.Limit)
+	limit := show.Limit
+	if show.Tp == ast.ShowProfile {
+		limit = show.ShowProfileLimit
+	}
+	if limit != nil {
+		np, err = b.buildLimit(np, limit)
 		if err != nil {
 			return nil, err
 		}
@@ -3500,7 +3746,7 @@ func (b *PlanBuilder) buildSimple(ctx context.Context, node ast.StmtNode) (Plan,
 			return nil, err
 		}
 	case *ast.BRIEStmt:
-		p.setSchemaAndNames(buildBRIESchema(raw.Kind))
+		p.setSchemaAndNames(buildBRIESchema(raw))
 		if raw.Kind == ast.BRIEKindRestore {
 			err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or RESTORE_ADMIN")
 			b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "RESTORE_ADMIN", false, err)
@@ -3529,6 +3775,20 @@ func (b *PlanBuilder) buildSimple(ctx context.Context, node ast.StmtNode) (Plan,
 			return nil, err
 		}
 	case *ast.KillStmt:
+		if raw.Digest != "" {
+			// Killing the queries by digest may terminate the queries of any user in the cluster.
+			err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or CONNECTION_ADMIN")
+			b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "CONNECTION_ADMIN", false, err)
+			if sm := b.ctx.GetSessionManager(); sm != nil {
+				digest := strings.ToLower(raw.Digest)
+				for _, pi := range sm.ShowProcessList() {
+					if pi.Digest == digest {
+						b.visitInfo = appendVisitInfoIsRestrictedUser(b.visitInfo, b.ctx, &auth.UserIdentity{Username: pi.User, Hostname: pi.Host}, "RESTRICTED_CONNECTION_ADMIN")
+					}
+				}
+			}
+			break
+		}
 		// All users can kill their own connections regardless.
 		// If you have the SUPER privilege, you can kill all threads and statements unless SEM is enabled.
 		// In which case you require RESTRICTED_CONNECTION_ADMIN to kill connections that belong to RESTRICTED_USER_ADMIN users.
@@ -4324,6 +4584,10 @@ var (
 	importIntoSchemaFTypes = []byte{mysql.TypeLonglong, mysql.TypeString, mysql.TypeString, mysql.TypeLonglong,
 		mysql.TypeString, mysql.TypeString, mysql.TypeString, mysql.TypeLonglong,
 		mysql.TypeString, mysql.TypeTimestamp, mysql.TypeTimestamp, mysql.TypeTimestamp, mysql.TypeString}
+	// importFileSchemaNames are appended to the schema of `SHOW IMPORT JOB <ID>`, one row per source file.
+	importFileSchemaNames  = []string{"File_Path", "Subtask_ID", "File_Read_Bytes", "File_Imported_Rows", "File_Status", "File_Error"}
+	importFileSchemaFTypes = []byte{mysql.TypeString, mysql.TypeLonglong, mysql.TypeLonglong, mysql.TypeLonglong,
+		mysql.TypeString, mysql.TypeString}
 )
 
 func (b *PlanBuilder) buildImportInto(ctx context.Context, ld *ast.ImportIntoStmt) (Plan, error) {
@@ -4997,6 +5261,12 @@ const (
 	TraceFormatJSON = "json"
 	// TraceFormatLog indicates log tracing format.
 	TraceFormatLog = "log"
+	// TraceFormatCopRegions indicates tracing the coprocessor requests per region.
+	TraceFormatCopRegions = "cop_regions"
+	// TraceFormatLocks indicates tracing the pessimistic lock attempts.
+	TraceFormatLocks = "locks"
+	// TraceFormatOptRules indicates tracing the application counts and time of the optimization rules.
+	TraceFormatOptRules = "opt_rules"
 
 	// TracePlanTargetEstimation indicates CE trace target for optimizer trace.
 	TracePlanTargetEstimation = "estimation"
@@ -5058,8 +5328,35 @@ func (*PlanBuilder) buildTrace(trace *ast.TraceStmt) (Plan, error) {
 		schema.Append(buildColumnWithName("", "spanName", mysql.TypeString, mysql.MaxBlobWidth))
 		p.SetSchema(schema.col2Schema())
 		p.names = schema.names
+	case TraceFormatCopRegions:
+		schema := newColumnsWithNames(6)
+		schema.Append(buildColumnWithName("", "region_id", mysql.TypeLonglong, 21))
+		schema.Append(buildColumnWithName("", "store", mysql.TypeString, mysql.MaxBlobWidth))
+		schema.Append(buildColumnWithName("", "requests", mysql.TypeLonglong, 21))
+		schema.Append(buildColumnWithName("", "wait_time", mysql.TypeString, mysql.MaxBlobWidth))
+		schema.Append(buildColumnWithName("", "process_time", mysql.TypeString, mysql.MaxBlobWidth))
+		schema.Append(buildColumnWithName("", "backoff_time", mysql.TypeString, mysql.MaxBlobWidth))
+		p.SetSchema(schema.col2Schema())
+		p.names = schema.names
+	case TraceFormatLocks:
+		schema := newColumnsWithNames(5)
+		schema.Append(buildColumnWithName("", "keys", mysql.TypeString, mysql.MaxBlobWidth))
+		schema.Append(buildColumnWithName("", "waited", mysql.TypeString, mysql.MaxBlobWidth))
+		schema.Append(buildColumnWithName("", "lock_time", mysql.TypeString, mysql.MaxBlobWidth))
+		schema.Append(buildColumnWithName("", "resolve_time", mysql.TypeString, mysql.MaxBlobWidth))
+		schema.Append(buildColumnWithName("", "conflict", mysql.TypeString, mysql.MaxBlobWidth))
+		p.SetSchema(schema.col2Schema())
+		p.names = schema.names
+	case TraceFormatOptRules:
+		schema := newColumnsWithNames(4)
+		schema.Append(buildColumnWithName("", "phase", mysql.TypeString, mysql.MaxBlobWidth))
+		schema.Append(buildColumnWithName("", "rule", mysql.TypeString, mysql.MaxBlobWidth))
+		schema.Append(buildColumnWithName("", "count", mysql.TypeLonglong, 21))
+		schema.Append(buildColumnWithName("", "time", mysql.TypeString, mysql.MaxBlobWidth))
+		p.SetSchema(schema.col2Schema())
+		p.names = schema.names
 	default:
-		return nil, errors.New("trace format should be one of 'row', 'log' or 'json'")
+		return nil, errors.New("trace format should be one of 'row', 'log', 'json', 'cop_regions', 'locks' or 'opt_rules'")
 	}
 	return p, nil
 }
@@ -5125,7 +5422,7 @@ func (b *PlanBuilder) buildSelectInto(ctx context.Context, sel *ast.SelectStmt)
 	}
 	selectIntoInfo := sel.SelectIntoOpt
 	sel.SelectIntoOpt = nil
-	targetPlan, _, err := OptimizeAstNode(ctx, b.ctx, sel, b.is)
+	targetPlan, names, err := OptimizeAstNode(ctx, b.ctx, sel, b.is)
 	if err != nil {
 		return nil, err
 	}
@@ -5133,6 +5430,7 @@ func (b *PlanBuilder) buildSelectInto(ctx context.Context, sel *ast.SelectStmt)
 	return &SelectInto{
 		TargetPlan:     targetPlan,
 		IntoOpt:        selectIntoInfo,
+		TargetNames:    names,
 		LineFieldsInfo: NewLineFieldsInfo(selectIntoInfo.FieldsInfo, selectIntoInfo.LinesInfo),
 	}, nil
 }
@@ -5348,6 +5646,9 @@ func buildShowSchema(s *ast.ShowStmt, isView bool, isSequence bool) (schema *exp
 	case ast.ShowProfiles: // ShowProfiles is deprecated.
 		names = []string{"Query_ID", "Duration", "Query"}
 		ftypes = []byte{mysql.TypeLong, mysql.TypeDouble, mysql.TypeVarchar}
+	case ast.ShowProfile:
+		names = []string{"Status", "Duration", "Rows"}
+		ftypes = []byte{mysql.TypeVarchar, mysql.TypeDouble, mysql.TypeLonglong}
 	case ast.ShowMasterStatus:
 		names = []string{"File", "Position", "Binlog_Do_DB", "Binlog_Ignore_DB", "Executed_Gtid_Set"}
 		ftypes = []byte{mysql.TypeVarchar, mysql.TypeLonglong, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar}
@@ -5383,6 +5684,10 @@ func buildShowSchema(s *ast.ShowStmt, isView bool, isSequence bool) (schema *exp
 	case ast.ShowImportJobs:
 		names = importIntoSchemaNames
 		ftypes = importIntoSchemaFTypes
+		if s.ImportJobID != nil {
+			names = append(append([]string{}, importIntoSchemaNames...), importFileSchemaNames...)
+			ftypes = append(append([]byte{}, importIntoSchemaFTypes...), importFileSchemaFTypes...)
+		}
 	}
 	return convert2OutputSchemasAndNames(names, ftypes)
 }
@@ -5413,7 +5718,7 @@ func convert2OutputSchemasAndNames(names []string, ftypes []byte) (schema *expre
 
 func (*PlanBuilder) buildPlanReplayer(pc *ast.PlanReplayerStmt) Plan {
 	p := &PlanReplayer{ExecStmt: pc.Stmt, Analyze: pc.Analyze, Load: pc.Load, File: pc.File,
-		Capture: pc.Capture, Remove: pc.Remove, SQLDigest: pc.SQLDigest, PlanDigest: pc.PlanDigest}
+		Capture: pc.Capture, Remove: pc.Remove, Reset: pc.Reset, SQLDigest: pc.SQLDigest, PlanDigest: pc.PlanDigest}
 	schema := newColumnsWithNames(1)
 	schema.Append(buildColumnWithName("", "File_token", mysql.TypeVarchar, 128))
 	p.SetSchema(schema.col2Schema())
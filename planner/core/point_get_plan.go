@@ -55,6 +55,7 @@ import (
 	"github.com/pingcap/tipb/go-tipb"
 	tikvstore "github.com/tikv/client-go/v2/kv"
 	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
 )
 
 // PointGetPlan is a fast plan for simple point get.
@@ -315,6 +316,9 @@ type BatchPointGetPlan struct {
 	IndexValues      [][]types.Datum
 	IndexValueParams [][]*expression.Constant // record all Parameters for Plan-Cache
 	IndexColTypes    []*types.FieldType
+	// IndexPrefix indicates the IndexValues are only the values of a prefix of the unique index columns, so every
+	// value is a range of the index. The prefix columns cover a unique key, so a range matches at most one row.
+	IndexPrefix      bool
 	AccessConditions []expression.Expression
 	IdxCols          []*expression.Column
 	IdxColLens       []int
@@ -430,6 +434,9 @@ func (p *BatchPointGetPlan) OperatorInfo(normalized bool) string {
 	if p.Lock {
 		buffer.WriteString(", lock")
 	}
+	if p.IndexPrefix {
+		buffer.WriteString(", index prefix")
+	}
 	return buffer.String()
 }
 
@@ -554,7 +561,7 @@ func TryFastPlan(ctx sessionctx.Context, node ast.Node) (p Plan) {
 		}()
 		// Try to convert the `SELECT a, b, c FROM t WHERE (a, b, c) in ((1, 2, 4), (1, 3, 5))` to
 		// `PhysicalUnionAll` which children are `PointGet` if exists an unique key (a, b, c) in table `t`
-		if fp := tryWhereIn2BatchPointGet(ctx, x); fp != nil {
+		if fp := tryWhereIn2BatchPointGet(ctx, x, x.LockInfo == nil || x.LockInfo.LockType == ast.SelectLockNone); fp != nil {
 			if checkFastPlanPrivilege(ctx, fp.dbName, fp.TblInfo.Name.L, mysql.SelectPriv) != nil {
 				return
 			}
@@ -627,7 +634,7 @@ func getLockWaitTime(ctx sessionctx.Context, lockInfo *ast.SelectLockInfo) (lock
 func newBatchPointGetPlan(
 	ctx sessionctx.Context, patternInExpr *ast.PatternInExpr,
 	handleCol *model.ColumnInfo, tbl *model.TableInfo, schema *expression.Schema,
-	names []*types.FieldName, whereColNames []string, indexHints []*ast.IndexHint,
+	names []*types.FieldName, whereColNames []string, indexHints []*ast.IndexHint, allowIndexPrefix bool,
 ) *BatchPointGetPlan {
 	stmtCtx := ctx.GetSessionVars().StmtCtx
 	statsInfo := &property.StatsInfo{RowCount: float64(len(patternInExpr.List))}
@@ -722,8 +729,6 @@ func newBatchPointGetPlan(
 	}
 
 	// The columns in where clause should be covered by unique index
-	var matchIdxInfo *model.IndexInfo
-	permutations := make([]int, len(whereColNames))
 	colInfos := make([]*model.ColumnInfo, len(whereColNames))
 	for i, innerCol := range whereColNames {
 		for _, col := range tbl.Columns {
@@ -732,34 +737,15 @@ func newBatchPointGetPlan(
 			}
 		}
 	}
-	for _, idxInfo := range tbl.Indices {
-		if !idxInfo.Unique || idxInfo.State != model.StatePublic || idxInfo.Invisible || idxInfo.MVIndex ||
-			!indexIsAvailableByHints(idxInfo, indexHints) {
-			continue
-		}
-		if len(idxInfo.Columns) != len(whereColNames) || idxInfo.HasPrefixIndex() {
-			continue
-		}
-		// TODO: not sure is there any function to reuse
-		matched := true
-		for whereColIndex, innerCol := range whereColNames {
-			var found bool
-			for i, col := range idxInfo.Columns {
-				if innerCol == col.Name.L {
-					permutations[whereColIndex] = i
-					found = true
-					break
-				}
-			}
-			if !found {
-				matched = false
-				break
-			}
-		}
-		if matched {
-			matchIdxInfo = idxInfo
-			break
-		}
+	matchIdxInfo, permutations := matchUniqueIndexForBatchPointGet(tbl, whereColNames, indexHints, false)
+	isIndexPrefix := false
+	// The columns in where clause may also be the prefix of a composite unique index, then every value is a
+	// range of the index. It's only allowed for the reads without lock, a range can't be locked by the keys.
+	// The prefix must be unique by itself, otherwise it's a range scan and should be planned by the optimizer.
+	if matchIdxInfo == nil && allowIndexPrefix && partitionExpr == nil && tbl.TempTableType == model.TempTableNone &&
+		colsCoverUniqueKey(tbl, whereColNames) {
+		matchIdxInfo, permutations = matchUniqueIndexForBatchPointGet(tbl, whereColNames, indexHints, true)
+		isIndexPrefix = matchIdxInfo != nil
 	}
 	if matchIdxInfo == nil {
 		return nil
@@ -903,6 +889,7 @@ func newBatchPointGetPlan(
 		IndexValues:      indexValues,
 		IndexValueParams: indexValueParams,
 		IndexColTypes:    indexTypes,
+		IndexPrefix:      isIndexPrefix,
 		PartitionColPos:  pos,
 		PartitionExpr:    partitionExpr,
 		PartitionInfos:   partitionInfos,
@@ -911,7 +898,79 @@ func newBatchPointGetPlan(
 	return p.Init(ctx, statsInfo, schema, names, 0)
 }
 
-func tryWhereIn2BatchPointGet(ctx sessionctx.Context, selStmt *ast.SelectStmt) *BatchPointGetPlan {
+// matchUniqueIndexForBatchPointGet finds the unique index whose columns are exactly the columns in where clause,
+// or whose leading columns are the columns in where clause if prefix is true. The permutations map the columns in
+// where clause to their offsets in the index.
+func matchUniqueIndexForBatchPointGet(tbl *model.TableInfo, whereColNames []string, indexHints []*ast.IndexHint, prefix bool) (*model.IndexInfo, []int) {
+	permutations := make([]int, len(whereColNames))
+	for _, idxInfo := range tbl.Indices {
+		if !idxInfo.Unique || idxInfo.State != model.StatePublic || idxInfo.Invisible || idxInfo.MVIndex ||
+			!indexIsAvailableByHints(idxInfo, indexHints) {
+			continue
+		}
+		if idxInfo.HasPrefixIndex() {
+			continue
+		}
+		idxCols := idxInfo.Columns
+		if prefix {
+			// The clustered primary key is read by the row keys, which aren't scanned by prefix.
+			if len(idxCols) <= len(whereColNames) || (idxInfo.Primary && tbl.IsCommonHandle) {
+				continue
+			}
+			idxCols = idxCols[:len(whereColNames)]
+		} else if len(idxCols) != len(whereColNames) {
+			continue
+		}
+		// TODO: not sure is there any function to reuse
+		matched := true
+		for whereColIndex, innerCol := range whereColNames {
+			var found bool
+			for i, col := range idxCols {
+				if innerCol == col.Name.L {
+					permutations[whereColIndex] = i
+					found = true
+					break
+				}
+			}
+			if !found {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return idxInfo, permutations
+		}
+	}
+	return nil, nil
+}
+
+// colsCoverUniqueKey checks whether the columns cover the handle or all the columns of a unique key, so the
+// values of the columns match at most one row.
+func colsCoverUniqueKey(tbl *model.TableInfo, colNames []string) bool {
+	if tbl.PKIsHandle {
+		if pkCol := tbl.GetPkColInfo(); pkCol != nil && slices.Contains(colNames, pkCol.Name.L) {
+			return true
+		}
+	}
+	for _, idxInfo := range tbl.Indices {
+		if !idxInfo.Unique || idxInfo.State != model.StatePublic || idxInfo.MVIndex || idxInfo.HasPrefixIndex() {
+			continue
+		}
+		covered := true
+		for _, col := range idxInfo.Columns {
+			if !slices.Contains(colNames, col.Name.L) {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
+
+func tryWhereIn2BatchPointGet(ctx sessionctx.Context, selStmt *ast.SelectStmt, allowIndexPrefix bool) *BatchPointGetPlan {
 	if selStmt.OrderBy != nil || selStmt.GroupBy != nil ||
 		selStmt.Limit != nil || selStmt.Having != nil || selStmt.Distinct ||
 		len(selStmt.WindowSpecs) > 0 {
@@ -993,7 +1052,7 @@ func tryWhereIn2BatchPointGet(ctx sessionctx.Context, selStmt *ast.SelectStmt) *
 		return nil
 	}
 
-	p := newBatchPointGetPlan(ctx, in, handleCol, tbl, schema, names, whereColNames, tblName.IndexHints)
+	p := newBatchPointGetPlan(ctx, in, handleCol, tbl, schema, names, whereColNames, tblName.IndexHints, allowIndexPrefix)
 	if p == nil {
 		return nil
 	}
@@ -1550,6 +1609,10 @@ func findInPairs(colName string, pairs []nameValuePair) int {
 }
 
 func tryUpdatePointPlan(ctx sessionctx.Context, updateStmt *ast.UpdateStmt) Plan {
+	// The RETURNING clause is built by the normal plan builder.
+	if updateStmt.Returning != nil {
+		return nil
+	}
 	// avoid using the point_get when assignment_list contains the subquery in the UPDATE.
 	for _, list := range updateStmt.List {
 		if _, ok := list.Expr.(*ast.SubqueryExpr); ok {
@@ -1575,7 +1638,7 @@ func tryUpdatePointPlan(ctx sessionctx.Context, updateStmt *ast.UpdateStmt) Plan
 		}
 		return buildPointUpdatePlan(ctx, pointGet, pointGet.dbName, pointGet.TblInfo, updateStmt)
 	}
-	batchPointGet := tryWhereIn2BatchPointGet(ctx, selStmt)
+	batchPointGet := tryWhereIn2BatchPointGet(ctx, selStmt, false)
 	if batchPointGet != nil {
 		if ctx.GetSessionVars().TxnCtx.IsPessimistic {
 			batchPointGet.Lock, batchPointGet.LockWaitTime = getLockWaitTime(ctx, &ast.SelectLockInfo{LockType: ast.SelectLockForUpdate})
@@ -1679,7 +1742,7 @@ func buildOrderedList(ctx sessionctx.Context, plan Plan, list []*ast.Assignment,
 }
 
 func tryDeletePointPlan(ctx sessionctx.Context, delStmt *ast.DeleteStmt) Plan {
-	if delStmt.IsMultiTable {
+	if delStmt.IsMultiTable || delStmt.Returning != nil {
 		return nil
 	}
 	selStmt := &ast.SelectStmt{
@@ -1700,7 +1763,7 @@ func tryDeletePointPlan(ctx sessionctx.Context, delStmt *ast.DeleteStmt) Plan {
 		}
 		return buildPointDeletePlan(ctx, pointGet, pointGet.dbName, pointGet.TblInfo)
 	}
-	if batchPointGet := tryWhereIn2BatchPointGet(ctx, selStmt); batchPointGet != nil {
+	if batchPointGet := tryWhereIn2BatchPointGet(ctx, selStmt, false); batchPointGet != nil {
 		if ctx.GetSessionVars().TxnCtx.IsPessimistic {
 			batchPointGet.Lock, batchPointGet.LockWaitTime = getLockWaitTime(ctx, &ast.SelectLockInfo{LockType: ast.SelectLockForUpdate})
 		}
@@ -314,7 +314,8 @@ func (p *PhysicalIndexReader) ResolveIndices() (err error) {
 		}
 		p.OutputColumns[i] = newCol.(*expression.Column)
 	}
-	return
+	// The columns after the OutputColumns are the virtual columns computed by the index reader.
+	return resolveIndicesForVirtualColumn(p.schema.Columns[len(p.OutputColumns):], p.schema)
 }
 
 // ResolveIndices implements Plan interface.
@@ -845,8 +845,9 @@ func (p *PhysicalLimit) attach2Task(tasks ...task) task {
 			sunk = p.sinkIntoIndexLookUp(t)
 		} else if !cop.idxMergeIsIntersection {
 			// We only support push part of the order prop down to index merge case.
+			var newCount uint64
 			if !cop.keepOrder && !cop.indexPlanFinished && len(cop.rootTaskConds) == 0 {
-				newCount := p.Offset + p.Count
+				newCount = p.Offset + p.Count
 				limitChildren := make([]PhysicalPlan, 0, len(cop.idxMergePartPlans))
 				for _, partialScan := range cop.idxMergePartPlans {
 					childProfile := partialScan.statsInfo()
@@ -859,6 +860,13 @@ func (p *PhysicalLimit) attach2Task(tasks ...task) task {
 				cop.idxMergePartPlans = limitChildren
 			}
 			t = cop.convertToRootTask(p.ctx)
+			if newCount > 0 && len(p.GetPartitionBy()) == 0 {
+				// Every handle is output as a row since there are no filters on the table side, so the
+				// IndexMergeReader can stop reading the partial plans after newCount distinct handles.
+				if indexMerge := getIndexMergeReader(t.(*rootTask).p); indexMerge != nil {
+					indexMerge.PushedLimit = &PushedDownLimit{Count: newCount}
+				}
+			}
 		} else {
 			// Whatever the remained case is, we directly convert to it to root task.
 			t = cop.convertToRootTask(p.ctx)
@@ -883,6 +891,15 @@ func (p *PhysicalLimit) attach2Task(tasks ...task) task {
 	return attachPlan2Task(p, t)
 }
 
+// getIndexMergeReader returns the IndexMergeReader of the root plan which may be wrapped by an extra projection.
+func getIndexMergeReader(p PhysicalPlan) *PhysicalIndexMergeReader {
+	if proj, ok := p.(*PhysicalProjection); ok {
+		p = proj.Children()[0]
+	}
+	indexMerge, _ := p.(*PhysicalIndexMergeReader)
+	return indexMerge
+}
+
 func (p *PhysicalLimit) sinkIntoIndexLookUp(t task) bool {
 	root := t.(*rootTask)
 	reader, isDoubleRead := root.p.(*PhysicalIndexLookUpReader)
@@ -193,6 +193,14 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 		} else {
 			fp = core.TryFastPlan(sctx, node)
 		}
+		if fp != nil && sessVars.StmtCtx.InPreparedPlanBuilding {
+			// The point plan of a prepared statement is cached and reused, so let the binding of the
+			// statement take effect on it rather than ignoring the binding like other point plans.
+			stmtNode, _ := node.(ast.StmtNode)
+			if bindRecord, _, match := matchSQLBinding(sctx, stmtNode); match && bindRecord.HasEnabledBinding() {
+				fp = nil
+			}
+		}
 		if fp != nil {
 			return fp, fp.OutputNames(), nil
 		}
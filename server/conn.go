@@ -1735,14 +1735,48 @@ func (cc *clientConn) handleLoadStats(ctx context.Context, loadStatsInfo *execut
 	if loadStatsInfo == nil {
 		return errors.New("load stats: info is empty")
 	}
-	data, err := cc.getDataFromPath(ctx, loadStatsInfo.Path)
+	err := cc.writeReq(ctx, loadStatsInfo.Path)
 	if err != nil {
 		return err
 	}
-	if len(data) == 0 {
-		return nil
-	}
-	return loadStatsInfo.Update(data)
+
+	var (
+		// use Pipe to convert cc.readPacket to io.Reader, so the stats file is decoded as a stream
+		r, w = io.Pipe()
+		wg   sync.WaitGroup
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		//nolint: errcheck
+		defer w.Close()
+
+		// Always read the data from client conn until the empty packet is received, otherwise the connection will
+		// be reset. The data which can't be written is drained, e.g. the loader stops reading when it fails or meets
+		// the data after the stats.
+		var writeErr error
+		for {
+			data, err2 := cc.readPacket()
+			if err2 != nil {
+				w.CloseWithError(err2)
+				return
+			}
+			if len(data) == 0 {
+				return
+			}
+			if writeErr != nil {
+				continue
+			}
+			if _, writeErr = w.Write(data); writeErr != nil && writeErr != io.ErrClosedPipe {
+				logutil.Logger(ctx).Error("write data meet error", zap.Error(writeErr))
+			}
+		}
+	}()
+
+	err = loadStatsInfo.UpdateFromReader(r)
+	_ = r.Close()
+	wg.Wait()
+	return err
 }
 
 // handleIndexAdvise does the index advise work and returns the advise result for index.
@@ -23,6 +23,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"net"
 	"path/filepath"
 	"strings"
 	"sync/atomic"
@@ -777,6 +778,71 @@ func TestShutDown(t *testing.T) {
 	require.Equal(t, exeerrors.ErrQueryInterrupted, err)
 }
 
+func TestKillConnForce(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	srv := CreateMockServer(t, store)
+	defer srv.Close()
+
+	originCfg := config.GetGlobalConfig()
+	newCfg := *originCfg
+	newCfg.EnableGlobalKill = false
+	config.StoreGlobalConfig(&newCfg)
+	defer config.StoreGlobalConfig(originCfg)
+
+	cc := CreateMockConn(t, srv).(*mockConn).clientConn
+	clientSide, serverSide := net.Pipe()
+	defer func() {
+		require.NoError(t, clientSide.Close())
+	}()
+	cc.bufReadConn = newBufferedReadConn(serverSide)
+	tk := testkit.NewTestKitWithSession(t, store, cc.getCtx().Session)
+	killer := testkit.NewTestKitWithSession(t, store, CreateMockConn(t, srv).Context().Session)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int)")
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (1)")
+
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/session/mockStmtSlow", "return(2000)"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/session/mockStmtSlow"))
+	}()
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/executor/mockKillForceGracePeriod", "return(100)"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/executor/mockKillForceGracePeriod"))
+	}()
+
+	// The statement is stuck in the failpoint and does not respond to the kill signal.
+	atomic.StoreUint32(&tk.Session().GetSessionVars().CommandValue, uint32(mysql.ComQuery))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		rs, err := tk.Exec("select /* sleep */ 1")
+		if err == nil && rs != nil {
+			require.NoError(t, rs.Close())
+		}
+	}()
+	require.Eventually(t, func() bool {
+		pi, ok := srv.GetProcessInfo(cc.connectionID)
+		return ok && pi != nil && strings.Contains(pi.Info, "sleep")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	killer.MustExec(fmt.Sprintf("kill tidb connection %d force", cc.connectionID))
+	select {
+	case <-done:
+		require.Fail(t, "the statement should still be stuck")
+	default:
+	}
+	// The network connection is closed without waiting for the statement.
+	_, err := clientSide.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, int32(connStatusWaitShutdown), cc.getStatus())
+
+	// Once the statement returns, the connection is closed and the transaction is rolled back.
+	<-done
+	require.NoError(t, cc.Close())
+	killer.MustQuery("select count(*) from test.t").Check(testkit.Rows("0"))
+}
+
 type snapshotCache interface {
 	SnapCacheHitCount() int
 }
@@ -825,6 +825,28 @@ func (s *Server) Kill(connectionID uint64, query bool, maxExecutionTime bool) {
 	killQuery(conn, maxExecutionTime)
 }
 
+// KillForce implements the SessionManager interface.
+func (s *Server) KillForce(connectionID uint64) {
+	logutil.BgLogger().Warn("force kill", zap.Uint64("conn", connectionID))
+
+	s.rwlock.RLock()
+	defer s.rwlock.RUnlock()
+	conn, ok := s.clients[connectionID]
+	if !ok {
+		return
+	}
+	conn.setStatus(connStatusWaitShutdown)
+	killQuery(conn, false)
+	// The running statement does not respond to the kill, so close the network connection to free
+	// the client. The session is not touched here, clientConn.Run closes it and rolls back the
+	// transaction once the statement returns.
+	if conn.bufReadConn != nil {
+		if err := conn.bufReadConn.Close(); err != nil {
+			logutil.BgLogger().Warn("error closing connection for force kill.", zap.Error(err))
+		}
+	}
+}
+
 // UpdateTLSConfig implements the SessionManager interface.
 func (s *Server) UpdateTLSConfig(cfg *tls.Config) {
 	atomic.StorePointer(&s.tlsConfig, unsafe.Pointer(cfg))
@@ -388,11 +388,15 @@ func TestErrorNoRollback(t *testing.T) {
 	}
 	err = cli.runTestTLSConnection(t, connOverrider)
 	require.NoError(t, err)
+	oldTLSCfg := server.getTLSConfig()
 	os.Remove("/tmp/server-key-rollback.pem")
 	err = cli.runReloadTLS(t, connOverrider, false)
 	require.Error(t, err)
+	// The old TLS config is kept when the new certificates fail to load.
 	tlsCfg := server.getTLSConfig()
-	require.NotNil(t, tlsCfg)
+	require.Same(t, oldTLSCfg, tlsCfg)
+	err = cli.runTestTLSConnection(t, connOverrider)
+	require.NoError(t, err)
 	err = cli.runReloadTLS(t, connOverrider, true)
 	require.NoError(t, err)
 	tlsCfg = server.getTLSConfig()
@@ -604,6 +604,16 @@ const (
        KEY (create_time),
        KEY (create_user));`
 
+	// CreateLoadDataCheckpoints is a table that resumable LOAD DATA uses to record
+	// the committed position of each source file.
+	CreateLoadDataCheckpoints = `CREATE TABLE IF NOT EXISTS mysql.tidb_load_data_checkpoints (
+		resume_token VARCHAR(64) NOT NULL,
+		file_path VARCHAR(512) NOT NULL,
+		file_offset BIGINT(64) NOT NULL,
+		row_id BIGINT(64) NOT NULL,
+		update_time TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		PRIMARY KEY (resume_token, file_path) CLUSTERED);`
+
 	// CreateImportJobs is a table that IMPORT INTO uses.
 	CreateImportJobs = `CREATE TABLE IF NOT EXISTS mysql.tidb_import_jobs (
 		id bigint(64) NOT NULL AUTO_INCREMENT,
@@ -915,11 +925,13 @@ const (
 	// version 167 add column `step` to `mysql.tidb_background_subtask`
 	version167 = 167
 	version168 = 168
+	// version 169 creates mysql.tidb_load_data_checkpoints table for resumable LOAD DATA
+	version169 = 169
 )
 
 // currentBootstrapVersion is defined as a variable, so we can modify its value for testing.
 // please make sure this is the largest version
-var currentBootstrapVersion int64 = version168
+var currentBootstrapVersion int64 = version169
 
 // DDL owner key's expired time is ManagerSessionTTL seconds, we should wait the time and give more time to have a chance to finish it.
 var internalSQLTimeout = owner.ManagerSessionTTL + 15
@@ -1056,6 +1068,7 @@ var (
 		upgradeToVer146,
 		upgradeToVer167,
 		upgradeToVer168,
+		upgradeToVer169,
 	}
 )
 
@@ -2695,6 +2708,13 @@ func upgradeToVer168(s Session, ver int64) {
 	mustExecute(s, CreateImportJobs)
 }
 
+func upgradeToVer169(s Session, ver int64) {
+	if ver >= version169 {
+		return
+	}
+	mustExecute(s, CreateLoadDataCheckpoints)
+}
+
 func writeOOMAction(s Session) {
 	comment := "oom-action is `log` by default in v3.0.x, `cancel` by default in v4.0.11+"
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO %n.%n VALUES (%?, %?, %?) ON DUPLICATE KEY UPDATE VARIABLE_VALUE= %?`,
@@ -2811,6 +2831,8 @@ func doDDLWorks(s Session) {
 	mustExecute(s, CreateLoadDataJobs)
 	// Create tidb_import_jobs
 	mustExecute(s, CreateImportJobs)
+	// Create tidb_load_data_checkpoints
+	mustExecute(s, CreateLoadDataCheckpoints)
 }
 
 // doBootstrapSQLFile executes SQL commands in a file as the last stage of bootstrap.
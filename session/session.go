@@ -113,6 +113,7 @@ import (
 	"github.com/tikv/client-go/v2/oracle"
 	tikvutil "github.com/tikv/client-go/v2/util"
 	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
 )
 
 // Session context, it is consistent with the lifecycle of a client connection.
@@ -1504,6 +1505,50 @@ func (s *session) SetGlobalSysVarOnly(ctx context.Context, name string, value st
 	return s.replaceGlobalVariablesTableValue(ctx, sv.Name, value, updateLocal)
 }
 
+// SetGlobalSysVarsOnly implements GlobalVarAccessor.SetGlobalSysVarsOnly interface.
+// The values are written by one statement, so either all or none of them are changed in the storage.
+func (s *session) SetGlobalSysVarsOnly(ctx context.Context, values map[string]string) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		sv := variable.GetSysVar(name)
+		if sv == nil {
+			return variable.ErrUnknownSystemVar.GenWithStackByArgs(name)
+		}
+		if !sv.HasInstanceScope() { // skip for INSTANCE scope
+			names = append(names, sv.Name)
+		}
+	}
+	if len(names) > 0 {
+		slices.Sort(names)
+		var sql strings.Builder
+		args := make([]interface{}, 0, 2+2*len(names))
+		sql.WriteString("REPLACE INTO %n.%n (variable_name, variable_value) VALUES ")
+		args = append(args, mysql.SystemDB, mysql.GlobalVariablesTable)
+		for i, name := range names {
+			if i > 0 {
+				sql.WriteString(", ")
+			}
+			sql.WriteString("(%?, %?)")
+			args = append(args, name, values[name])
+		}
+		internalCtx := kv.WithInternalSourceType(ctx, kv.InternalTxnSysVar)
+		if _, _, err := s.ExecRestrictedSQL(internalCtx, nil, sql.String(), args...); err != nil {
+			return err
+		}
+		domain.GetDomain(s).NotifyUpdateSysVarCache(true)
+	}
+	for name, value := range values {
+		sv := variable.GetSysVar(name)
+		if err := sv.SetGlobalFromHook(ctx, s.sessionVars, value, true); err != nil {
+			return err
+		}
+		if sv.GlobalConfigName != "" {
+			domain.GetDomain(s).NotifyGlobalConfigChange(sv.GlobalConfigName, variable.OnOffToTrueFalse(value))
+		}
+	}
+	return nil
+}
+
 // SetTiDBTableValue implements GlobalVarAccessor.SetTiDBTableValue interface.
 func (s *session) SetTiDBTableValue(name, value, comment string) error {
 	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnSysVar)
@@ -1577,6 +1622,7 @@ func (s *session) SetProcessInfo(sql string, t time.Time, command byte, maxExecu
 		RefCountOfStmtCtx:     &s.sessionVars.RefCountOfStmtCtx,
 		MemTracker:            s.sessionVars.MemTracker,
 		DiskTracker:           s.sessionVars.DiskTracker,
+		RUUsage:               s.sessionVars.RUUsage,
 		StatsInfo:             plannercore.GetStatsInfo,
 		OOMAlarmVariablesInfo: s.getOomAlarmVariablesInfo(),
 		MaxExecutionTime:      maxExecutionTime,
@@ -1401,6 +1401,43 @@ func TestInvalidSysVar(t *testing.T) {
 	tk.MustQuery("select @@max_prepared_stmt_count").Check(testkit.Rows("-1"))
 }
 
+func TestMigrateSession(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	sv := server.CreateMockServer(t, store)
+	defer sv.Close()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test1")
+	tk.MustExec("create table test1.t1(id int primary key, v int)")
+	tk.MustExec("insert into test1.t1 values(1, 10), (2, 20)")
+
+	newSession := func() (*testkit.TestKit, server.MockConn) {
+		conn := server.CreateMockConn(t, sv)
+		conn.Context().Session.GetSessionVars().User = nil
+		return testkit.NewTestKitWithSession(t, store, conn.Context().Session), conn
+	}
+	tk1, conn1 := newSession()
+	defer conn1.Close()
+	tk1.MustExec("use test1")
+	tk1.MustExec("set @@tidb_distsql_scan_concurrency=7, @@sql_mode='ANSI_QUOTES'")
+	tk1.MustExec("set @a=2")
+	tk1.MustExec("prepare stmt from 'select v from t1 where id=?'")
+
+	// The session states contain session variables, user variables, prepared statements and the current DB.
+	tk2, conn2 := newSession()
+	defer conn2.Close()
+	showSessionStatesAndSet(t, tk1, tk2)
+	tk2.MustQuery("select database()").Check(testkit.Rows("test1"))
+	tk2.MustQuery("select @@tidb_distsql_scan_concurrency, @@sql_mode").Check(testkit.Rows("7 ANSI_QUOTES"))
+	tk2.MustQuery("execute stmt using @a").Check(testkit.Rows("20"))
+
+	// The migrated session can be migrated again.
+	tk3, conn3 := newSession()
+	defer conn3.Close()
+	showSessionStatesAndSet(t, tk2, tk3)
+	tk3.MustQuery("select database(), @a").Check(testkit.Rows("test1 2"))
+	tk3.MustQuery("execute stmt using @a").Check(testkit.Rows("20"))
+}
+
 func showSessionStatesAndSet(t *testing.T, tk1, tk2 *testkit.TestKit) {
 	rows := tk1.MustQuery("show session_states").Rows()
 	require.Len(t, rows, 1)
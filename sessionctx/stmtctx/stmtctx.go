@@ -231,6 +231,10 @@ type StatementContext struct {
 	PrevAffectedRows int64
 	// PrevLastInsertID is the last insert ID of previous statement.
 	PrevLastInsertID uint64
+	// PrevPlan and PrevRuntimeStatsColl are the plan and runtime stats of the previous statement,
+	// they are only kept for the `SHOW PROFILE` statement.
+	PrevPlan             interface{}
+	PrevRuntimeStatsColl *execdetails.RuntimeStatsColl
 	// LastInsertID is the auto-generated ID in the current statement.
 	LastInsertID uint64
 	// InsertID is the given insert ID of an auto_increment column.
@@ -314,6 +318,9 @@ type StatementContext struct {
 
 	// InVerboseExplain indicates the statement is "explain format='verbose' ...".
 	InVerboseExplain bool
+	// IndexChoices records the candidate access paths of the data sources for "explain format='index_choice' ...",
+	// keyed by the ID of the access operator of the chosen path.
+	IndexChoices map[int][]IndexChoiceCandidate
 
 	// EnableOptimizeTrace indicates whether enable optimizer trace by 'trace plan statement'
 	EnableOptimizeTrace bool
@@ -662,6 +669,14 @@ func (sc *StatementContext) SetSkipPlanCache(reason error) {
 	}
 }
 
+// IndexChoiceCandidate is a candidate access path of a data source evaluated by the optimizer.
+type IndexChoiceCandidate struct {
+	Table      string
+	AccessPath string
+	Cost       float64
+	Chosen     bool
+}
+
 // TableEntry presents table in db.
 type TableEntry struct {
 	DB    string
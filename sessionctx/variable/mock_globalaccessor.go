@@ -96,6 +96,19 @@ func (m *MockGlobalAccessor) SetGlobalSysVarOnly(ctx context.Context, name strin
 	return nil
 }
 
+// SetGlobalSysVarsOnly implements GlobalVarAccessor.SetGlobalSysVarsOnly interface.
+func (m *MockGlobalAccessor) SetGlobalSysVarsOnly(ctx context.Context, values map[string]string) error {
+	for name := range values {
+		if GetSysVar(name) == nil {
+			return ErrUnknownSystemVar.GenWithStackByArgs(name)
+		}
+	}
+	for name, value := range values {
+		m.vals[name] = value
+	}
+	return nil
+}
+
 // GetTiDBTableValue implements GlobalVarAccessor.GetTiDBTableValue interface.
 func (m *MockGlobalAccessor) GetTiDBTableValue(name string) (string, error) {
 	// add for test tidb_gc_max_wait_time validation
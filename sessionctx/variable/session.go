@@ -513,6 +513,17 @@ type TableSnapshot struct {
 	Err  error
 }
 
+// UpdateColumnStat is the number of rows whose assigned column is changed by the UPDATE statement.
+type UpdateColumnStat struct {
+	DBName     string
+	TableName  string
+	ColumnName string
+	// AssignedRows is the number of the updated rows which assign the column.
+	AssignedRows int64
+	// ChangedRows is the number of the updated rows whose new value of the column differs from the old one.
+	ChangedRows int64
+}
+
 type txnIsolationLevelOneShotState uint
 
 // RewritePhaseInfo records some information about the rewrite phase
@@ -960,6 +971,9 @@ type SessionVars struct {
 	// BatchInsert indicates if we should split insert data into multiple batches.
 	BatchInsert bool
 
+	// InsertSortByHandle indicates if we should sort the insert rows by their handles before writing them.
+	InsertSortByHandle bool
+
 	// BatchDelete indicates if we should split delete data into multiple batches.
 	BatchDelete bool
 
@@ -997,6 +1011,9 @@ type SessionVars struct {
 	// EnablePipelinedWindowExec enables executing window functions in a pipelined manner.
 	EnablePipelinedWindowExec bool
 
+	// EnableParallelWindowExec enables evaluating the window functions of a large partition in parallel.
+	EnableParallelWindowExec bool
+
 	// AllowProjectionPushDown enables pushdown projection on TiKV.
 	AllowProjectionPushDown bool
 
@@ -1204,6 +1221,13 @@ type SessionVars struct {
 	// PartitionPruneMode indicates how and when to prune partitions.
 	PartitionPruneMode atomic2.String
 
+	// ForceStaticPartitionPrune indicates whether to access the partitioned tables in static prune mode regardless of
+	// PartitionPruneMode.
+	ForceStaticPartitionPrune bool
+
+	// WarnOnSynthJoinDefaults indicates whether to warn when the default values of an outer join are synthesized.
+	WarnOnSynthJoinDefaults bool
+
 	// TxnScope indicates the scope of the transactions. It should be `global` or equal to the value of key `zone` in config.Labels.
 	TxnScope kv.TxnScopeVar
 
@@ -1222,6 +1246,10 @@ type SessionVars struct {
 	// AnalyzeVersion indicates how TiDB collect and use analyzed statistics.
 	AnalyzeVersion int
 
+	// AnalyzeSkipFreshStatsWindow indicates the window within which the analyzed stats are regarded as fresh and
+	// not analyzed again.
+	AnalyzeSkipFreshStatsWindow time.Duration
+
 	// EnableIndexMergeJoin indicates whether to enable index merge join.
 	EnableIndexMergeJoin bool
 
@@ -1403,6 +1431,8 @@ type SessionVars struct {
 	AnalyzePartitionConcurrency int
 	// AnalyzePartitionMergeConcurrency indicates concurrency for merging partition stats
 	AnalyzePartitionMergeConcurrency int
+	// AnalyzeSampleMemQuota indicates the memory quota of the samples held by all the tasks of an analyze statement.
+	AnalyzeSampleMemQuota int64
 
 	// EnableExternalTSRead indicates whether to enable read through external ts
 	EnableExternalTSRead bool
@@ -1414,11 +1444,87 @@ type SessionVars struct {
 	// MemDBDBFootprint tracks the memory footprint of memdb, and is attached to `MemTracker`
 	MemDBFootprint *memory.Tracker
 	DiskTracker    *memory.Tracker
+	// RUUsage records the RU consumed by the current session.
+	RUUsage *execdetails.ConnRUUsage
 
 	// OptPrefixIndexSingleScan indicates whether to do some optimizations to avoid double scan for prefix index.
 	// When set to true, `col is (not) null`(`col` is index prefix column) is regarded as index filter rather than table filter.
 	OptPrefixIndexSingleScan bool
 
+	// OptIndexReaderVirtualColumn indicates whether a covering index reader can compute the virtual generated columns
+	// from the columns stored in the index.
+	OptIndexReaderVirtualColumn bool
+
+	// CopMaxConcurrentTasks caps the number of outstanding cop tasks of a table reader, 0 means no cap.
+	CopMaxConcurrentTasks int
+
+	// TSFetchRetry is the max number of times a reader retries fetching the snapshot timestamp after a failure.
+	TSFetchRetry int
+
+	// EnableHybridStoreScan indicates whether a TiKV table reader over a range partitioned table can read the
+	// cold partitions from TiFlash.
+	EnableHybridStoreScan bool
+
+	// BatchPointGetMaxSize is the max number of the deduplicated keys a batch point get can read.
+	BatchPointGetMaxSize int
+
+	// AdminCheckForeignKey indicates whether `admin check table` also checks the foreign key consistency.
+	AdminCheckForeignKey bool
+
+	// HashJoinRadixBits is the number of the hash key bits used to partition the build side of hash join.
+	HashJoinRadixBits int
+
+	// StaleReadPreferFollower indicates whether the stale read statements read from the followers.
+	StaleReadPreferFollower bool
+
+	// DeferUniqueCheckInUpdate indicates whether the UPDATE statements check the duplicated unique keys at the end.
+	DeferUniqueCheckInUpdate bool
+
+	// AutoIDBatchSize is the max number of the auto IDs which the INSERT statements pre-allocate at a time.
+	AutoIDBatchSize int
+
+	// HashJoinProbePrefetch is the number of the probe side chunks which hash join reads ahead.
+	HashJoinProbePrefetch int
+
+	// LoadDataConcurrency is the number of the files which LOAD DATA reads and encodes concurrently.
+	LoadDataConcurrency int
+
+	// VerifyRowChecksumOnRead indicates whether the point get and batch point get verify the row level checksums.
+	VerifyRowChecksumOnRead bool
+
+	// CopSplitLargeRegions indicates whether the table reader splits the range located in a single region into sub-ranges.
+	CopSplitLargeRegions bool
+
+	// AnalyzeDumpSamples is the table which ANALYZE writes the sampled rows into, empty means the samples are not dumped.
+	AnalyzeDumpSamples string
+
+	// EnableBandJoin indicates whether the range-only hash join is executed by the band merge join.
+	EnableBandJoin bool
+
+	// MemTableMaxResultBytes is the max bytes of the rows retrieved by a memory table reader, 0 means no limit.
+	MemTableMaxResultBytes int64
+
+	// IndexAdvisorFeedback indicates whether the index readers record the observed selectivities of the index scans.
+	IndexAdvisorFeedback bool
+
+	// AssertOperatorRows maps the operator ids to their expected actual rows, which are checked by EXPLAIN ANALYZE.
+	AssertOperatorRows map[string]int64
+
+	// EnableStreamingAggPreview indicates whether the hash aggregation emits its intermediate results periodically.
+	EnableStreamingAggPreview bool
+
+	// ChaosBuildLatencyMs is the latency in milliseconds injected when building the executors, 0 means no latency.
+	// It's only used in the chaos tests.
+	ChaosBuildLatencyMs int64
+
+	// CollectUpdateColumnStats indicates whether to count the changed rows of each assigned column in the UPDATE statements.
+	CollectUpdateColumnStats bool
+	// LastUpdateColumnStats is the per-column changed rows of the last UPDATE statement which collects them.
+	LastUpdateColumnStats []UpdateColumnStat
+
+	// PointGetFollowerRead indicates whether the non-locking point gets read from the followers.
+	PointGetFollowerRead bool
+
 	// ChunkPool Several chunks and columns are cached
 	ChunkPool ReuseChunkPool
 	// EnableReuseCheck indicates  request chunk whether use chunk alloc
@@ -1966,6 +2072,9 @@ func NewSessionVars(hctx HookContext) *SessionVars {
 		mppVersion:                    kv.MppVersionUnspecified,
 		EnableLateMaterialization:     DefTiDBOptEnableLateMaterialization,
 		TiFlashComputeDispatchPolicy:  tiflashcompute.DispatchPolicyConsistentHash,
+		TSFetchRetry:                  DefTiDBTSFetchRetry,
+		BatchPointGetMaxSize:          DefTiDBBatchPointGetMaxSize,
+		MemTableMaxResultBytes:        DefTiDBMemTableMaxResultBytes,
 	}
 	vars.KVVars = tikvstore.NewVariables(&vars.Killed)
 	vars.Concurrency = Concurrency{
@@ -1988,14 +2097,18 @@ func NewSessionVars(hctx HookContext) *SessionVars {
 		MemQuotaApplyCache: DefTiDBMemQuotaApplyCache,
 	}
 	vars.BatchSize = BatchSize{
-		IndexJoinBatchSize: DefIndexJoinBatchSize,
-		IndexLookupSize:    DefIndexLookupSize,
-		InitChunkSize:      DefInitChunkSize,
-		MaxChunkSize:       DefMaxChunkSize,
-		MinPagingSize:      DefMinPagingSize,
-		MaxPagingSize:      DefMaxPagingSize,
+		IndexJoinBatchSize:     DefIndexJoinBatchSize,
+		IndexLookupSize:        DefIndexLookupSize,
+		IndexLookupFetchAhead:  DefIndexLookupFetchAhead,
+		TableReaderHandleBatch: DefTableReaderHandleBatch,
+		InitChunkSize:          DefInitChunkSize,
+		MaxChunkSize:           DefMaxChunkSize,
+		MinPagingSize:          DefMinPagingSize,
+		MaxPagingSize:          DefMaxPagingSize,
 	}
 	vars.DMLBatchSize = DefDMLBatchSize
+	vars.AutoIDBatchSize = DefTiDBAutoIDBatchSize
+	vars.LoadDataConcurrency = DefTiDBLoadDataConcurrency
 	vars.AllowBatchCop = DefTiDBAllowBatchCop
 	vars.allowMPPExecution = DefTiDBAllowMPPExecution
 	vars.HashExchangeWithNewCollation = DefTiDBHashExchangeWithNewCollation
@@ -2009,6 +2122,7 @@ func NewSessionVars(hctx HookContext) *SessionVars {
 	vars.DiskTracker = disk.NewTracker(memory.LabelForSession, -1)
 	vars.MemTracker = memory.NewTracker(memory.LabelForSession, vars.MemQuotaQuery)
 	vars.MemTracker.IsRootTrackerOfSess = true
+	vars.RUUsage = &execdetails.ConnRUUsage{}
 
 	for _, engine := range config.GetGlobalConfig().IsolationRead.Engines {
 		switch engine {
@@ -2530,8 +2644,10 @@ func (s *SessionVars) GetPrevStmtDigest() string {
 }
 
 // LazyCheckKeyNotExists returns if we can lazy check key not exists.
+// The UPDATE statements deferring the unique check need to check the keys in place to retry the conflicting rows.
 func (s *SessionVars) LazyCheckKeyNotExists() bool {
-	return s.PresumeKeyNotExists || (s.TxnCtx != nil && s.TxnCtx.IsPessimistic && !s.StmtCtx.DupKeyAsWarning)
+	return s.PresumeKeyNotExists || (s.TxnCtx != nil && s.TxnCtx.IsPessimistic && !s.StmtCtx.DupKeyAsWarning &&
+		!(s.StmtCtx.InUpdateStmt && s.DeferUniqueCheckInUpdate))
 }
 
 // GetTemporaryTable returns a TempTable by tableInfo.
@@ -2875,6 +2991,12 @@ type BatchSize struct {
 	// IndexLookupSize is the number of handles for an index lookup task in index double read executor.
 	IndexLookupSize int
 
+	// IndexLookupFetchAhead is the number of index lookup tasks which can be fetched ahead in index double read executor.
+	IndexLookupFetchAhead int
+
+	// TableReaderHandleBatch is the number of handles a table reader built from handles reads in one pass.
+	TableReaderHandleBatch int
+
 	// InitChunkSize defines init row count of a Chunk during query execution.
 	InitChunkSize int
 
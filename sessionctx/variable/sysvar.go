@@ -217,6 +217,10 @@ var defaultSysVars = []*SysVar{
 		s.BatchInsert = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeSession, Name: TiDBInsertSortByHandle, Value: BoolToOnOff(DefTiDBInsertSortByHandle), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.InsertSortByHandle = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeSession, Name: TiDBBatchDelete, Value: BoolToOnOff(DefBatchDelete), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.BatchDelete = TiDBOptOn(val)
 		return nil
@@ -1575,6 +1579,10 @@ var defaultSysVars = []*SysVar{
 		s.distSQLScanConcurrency = tidbOptPositiveInt32(val, DefDistSQLScanConcurrency)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBCopMaxConcurrentTasks, Value: strconv.Itoa(DefCopMaxConcurrentTasks), Type: TypeUnsigned, MinValue: 0, MaxValue: MaxConfigurableConcurrency, SetSession: func(s *SessionVars, val string) error {
+		s.CopMaxConcurrentTasks = TidbOptInt(val, DefCopMaxConcurrentTasks)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptInSubqToJoinAndAgg, Value: BoolToOnOff(DefOptInSubqToJoinAndAgg), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.SetAllowInSubqToJoinAndAgg(TiDBOptOn(val))
 		return nil
@@ -1655,6 +1663,14 @@ var defaultSysVars = []*SysVar{
 		s.IndexLookupSize = tidbOptPositiveInt32(val, DefIndexLookupSize)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBIndexLookupFetchAhead, Value: strconv.Itoa(DefIndexLookupFetchAhead), Type: TypeUnsigned, MinValue: 1, MaxValue: 1024, SetSession: func(s *SessionVars, val string) error {
+		s.IndexLookupFetchAhead = tidbOptPositiveInt32(val, DefIndexLookupFetchAhead)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBTableReaderHandleBatch, Value: strconv.Itoa(DefTableReaderHandleBatch), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
+		s.TableReaderHandleBatch = TidbOptInt(val, DefTableReaderHandleBatch)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBIndexLookupConcurrency, Value: strconv.Itoa(DefIndexLookupConcurrency), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency, AllowAutoValue: true, SetSession: func(s *SessionVars, val string) error {
 		s.indexLookupConcurrency = tidbOptPositiveInt32(val, ConcurrencyUnset)
 		return nil
@@ -1809,6 +1825,10 @@ var defaultSysVars = []*SysVar{
 		s.EnablePipelinedWindowExec = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableParallelWindowFunction, Value: BoolToOnOff(DefEnableParallelWindowFunction), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableParallelWindowExec = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableStrictDoubleTypeCheck, Value: BoolToOnOff(DefEnableStrictDoubleTypeCheck), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EnableStrictDoubleTypeCheck = TiDBOptOn(val)
 		return nil
@@ -1973,6 +1993,14 @@ var defaultSysVars = []*SysVar{
 		}
 		return nil
 	}},
+	{Scope: ScopeSession, Name: TiDBForceStaticPartitionPrune, Value: BoolToOnOff(DefTiDBForceStaticPartitionPrune), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.ForceStaticPartitionPrune = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeSession, Name: TiDBWarnOnSynthJoinDefaults, Value: BoolToOnOff(DefTiDBWarnOnSynthJoinDefaults), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.WarnOnSynthJoinDefaults = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBRedactLog, Value: BoolToOnOff(DefTiDBRedactLog), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EnableRedactLog = TiDBOptOn(val)
 		errors.RedactLogEnabled.Store(s.EnableRedactLog)
@@ -2014,6 +2042,14 @@ var defaultSysVars = []*SysVar{
 		s.AnalyzeVersion = tidbOptPositiveInt32(val, DefTiDBAnalyzeVersion)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAnalyzeSkipFreshStatsWindow, Value: DefTiDBAnalyzeSkipFreshStatsWindow.String(), Type: TypeDuration, MaxValue: uint64(time.Hour * 24 * 365), SetSession: func(s *SessionVars, val string) error {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		s.AnalyzeSkipFreshStatsWindow = d
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableIndexMergeJoin, Value: BoolToOnOff(DefTiDBEnableIndexMergeJoin), Hidden: true, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EnableIndexMergeJoin = TiDBOptOn(val)
 		return nil
@@ -2256,6 +2292,11 @@ var defaultSysVars = []*SysVar{
 			s.AnalyzePartitionConcurrency = int(TidbOptInt64(val, DefTiDBAnalyzePartitionConcurrency))
 			return nil
 		}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAnalyzeSampleMemQuota, Value: strconv.Itoa(DefTiDBAnalyzeSampleMemQuota), Type: TypeInt, MinValue: -1, MaxValue: math.MaxInt64,
+		SetSession: func(s *SessionVars, val string) error {
+			s.AnalyzeSampleMemQuota = TidbOptInt64(val, DefTiDBAnalyzeSampleMemQuota)
+			return nil
+		}},
 	{
 		Scope: ScopeGlobal | ScopeSession, Name: TiDBMergePartitionStatsConcurrency, Value: strconv.FormatInt(DefTiDBMergePartitionStatsConcurrency, 10), Type: TypeInt, MinValue: 1, MaxValue: MaxConfigurableConcurrency,
 		SetSession: func(s *SessionVars, val string) error {
@@ -2268,6 +2309,111 @@ var defaultSysVars = []*SysVar{
 		s.OptPrefixIndexSingleScan = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptIndexReaderVirtualColumn, Value: BoolToOnOff(DefTiDBOptIndexReaderVirtualColumn), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.OptIndexReaderVirtualColumn = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBTSFetchRetry, Value: strconv.Itoa(DefTiDBTSFetchRetry), Type: TypeUnsigned, MinValue: 0, MaxValue: 10, SetSession: func(s *SessionVars, val string) error {
+		s.TSFetchRetry = TidbOptInt(val, DefTiDBTSFetchRetry)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableHybridStoreScan, Value: BoolToOnOff(DefTiDBEnableHybridStoreScan), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableHybridStoreScan = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBBatchPointGetMaxSize, Value: strconv.Itoa(DefTiDBBatchPointGetMaxSize), Type: TypeUnsigned, MinValue: 1, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
+		s.BatchPointGetMaxSize = TidbOptInt(val, DefTiDBBatchPointGetMaxSize)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAdminCheckForeignKey, Value: BoolToOnOff(DefTiDBAdminCheckForeignKey), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.AdminCheckForeignKey = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBHashJoinRadixBits, Value: strconv.Itoa(DefTiDBHashJoinRadixBits), Type: TypeUnsigned, MinValue: 0, MaxValue: 8, SetSession: func(s *SessionVars, val string) error {
+		s.HashJoinRadixBits = TidbOptInt(val, DefTiDBHashJoinRadixBits)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBStaleReadPreferFollower, Value: BoolToOnOff(DefTiDBStaleReadPreferFollower), Type: TypeBool, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
+		s.StaleReadPreferFollower = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBDeferUniqueCheckInUpdate, Value: BoolToOnOff(DefTiDBDeferUniqueCheckInUpdate), Type: TypeBool, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
+		s.DeferUniqueCheckInUpdate = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBCopStoreBreakerEnable, Value: BoolToOnOff(DefTiDBCopStoreBreakerEnable), Type: TypeBool, SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+		EnableCopStoreBreaker.Store(TiDBOptOn(val))
+		return nil
+	}, GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+		return BoolToOnOff(EnableCopStoreBreaker.Load()), nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAutoIDBatchSize, Value: strconv.Itoa(DefTiDBAutoIDBatchSize), Type: TypeUnsigned, MinValue: 1, MaxValue: math.MaxInt32, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
+		s.AutoIDBatchSize = tidbOptPositiveInt32(val, DefTiDBAutoIDBatchSize)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBHashJoinProbePrefetch, Value: strconv.Itoa(DefTiDBHashJoinProbePrefetch), Type: TypeUnsigned, MinValue: 0, MaxValue: 64, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
+		s.HashJoinProbePrefetch = TidbOptInt(val, DefTiDBHashJoinProbePrefetch)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBLoadDataConcurrency, Value: strconv.Itoa(DefTiDBLoadDataConcurrency), Type: TypeUnsigned, MinValue: 1, MaxValue: 256, SetSession: func(s *SessionVars, val string) error {
+		s.LoadDataConcurrency = tidbOptPositiveInt32(val, DefTiDBLoadDataConcurrency)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBVerifyRowChecksumOnRead, Value: BoolToOnOff(DefTiDBVerifyRowChecksumOnRead), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.VerifyRowChecksumOnRead = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBCopSplitLargeRegions, Value: BoolToOnOff(DefTiDBCopSplitLargeRegions), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.CopSplitLargeRegions = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeSession, Name: TiDBAnalyzeDumpSamples, Value: DefTiDBAnalyzeDumpSamples, Type: TypeStr, SetSession: func(s *SessionVars, val string) error {
+		s.AnalyzeDumpSamples = val
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableBandJoin, Value: BoolToOnOff(DefTiDBEnableBandJoin), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableBandJoin = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMemTableMaxResultBytes, Value: strconv.Itoa(DefTiDBMemTableMaxResultBytes), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
+		s.MemTableMaxResultBytes = TidbOptInt64(val, DefTiDBMemTableMaxResultBytes)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBIndexAdvisorFeedback, Value: BoolToOnOff(DefTiDBIndexAdvisorFeedback), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.IndexAdvisorFeedback = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeSession, Name: TiDBAssertOperatorRows, Value: DefTiDBAssertOperatorRows, Type: TypeStr, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		if normalizedValue == "" {
+			return normalizedValue, nil
+		}
+		if err := json.Unmarshal([]byte(normalizedValue), &map[string]int64{}); err != nil {
+			return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(TiDBAssertOperatorRows, originalValue)
+		}
+		return normalizedValue, nil
+	}, SetSession: func(s *SessionVars, val string) error {
+		s.AssertOperatorRows = nil
+		if val == "" {
+			return nil
+		}
+		return json.Unmarshal([]byte(val), &s.AssertOperatorRows)
+	}},
+	{Scope: ScopeSession, Name: TiDBEnableStreamingAggPreview, Value: BoolToOnOff(DefTiDBEnableStreamingAggPreview), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableStreamingAggPreview = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeSession, Name: TiDBChaosBuildLatencyMs, Value: strconv.Itoa(DefTiDBChaosBuildLatencyMs), Type: TypeInt, MinValue: 0, MaxValue: 3600000, SetSession: func(s *SessionVars, val string) error {
+		s.ChaosBuildLatencyMs = TidbOptInt64(val, DefTiDBChaosBuildLatencyMs)
+		return nil
+	}},
+	{Scope: ScopeSession, Name: TiDBCollectUpdateColumnStats, Value: BoolToOnOff(DefTiDBCollectUpdateColumnStats), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.CollectUpdateColumnStats = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBPointGetFollowerRead, Value: BoolToOnOff(DefTiDBPointGetFollowerRead), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.PointGetFollowerRead = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeGlobal, Name: TiDBExternalTS, Value: strconv.FormatInt(DefTiDBExternalTS, 10), SetGlobal: func(ctx context.Context, s *SessionVars, val string) error {
 		ts, err := parseTSFromNumberOrTime(s, val)
 		if err != nil {
@@ -119,6 +119,10 @@ const (
 	// insert data into multiple batches and use a single txn for each batch. This will be helpful when inserting large data.
 	TiDBBatchInsert = "tidb_batch_insert"
 
+	// TiDBInsertSortByHandle is used to sort the rows of each insert batch by their handles before writing them.
+	// It only takes effect on the tables with a clustered primary key, and trades a little memory for write locality.
+	TiDBInsertSortByHandle = "tidb_insert_sort_by_handle"
+
 	// TiDBBatchDelete is used to enable/disable auto-split delete data. If set this option on, delete executor will automatically
 	// split data into multiple batches and use a single txn for each batch. This will be helpful when deleting large data.
 	TiDBBatchDelete = "tidb_batch_delete"
@@ -288,6 +292,10 @@ const (
 	// If the query has a LIMIT clause, high concurrency makes the system do much more work than needed.
 	TiDBDistSQLScanConcurrency = "tidb_distsql_scan_concurrency"
 
+	// TiDBCopMaxConcurrentTasks is used to cap the number of outstanding cop tasks of a table reader.
+	// 0 means there is no extra limit and the concurrency falls back to tidb_distsql_scan_concurrency.
+	TiDBCopMaxConcurrentTasks = "tidb_cop_max_concurrent_tasks"
+
 	// TiDBOptInSubqToJoinAndAgg is used to enable/disable the optimizer rule of rewriting IN subquery.
 	TiDBOptInSubqToJoinAndAgg = "tidb_opt_insubq_to_join_and_agg"
 
@@ -342,6 +350,17 @@ const (
 	// Large value may do more work than needed if the query has a limit.
 	TiDBIndexLookupSize = "tidb_index_lookup_size"
 
+	// TiDBIndexLookupFetchAhead is used for index lookup executor.
+	// The index worker of the index lookup executor keeps fetching the handle batches while the table rows of the
+	// previous batches are not consumed, this value controls how many batches can be fetched ahead.
+	// Large value may hide the latency of the storage but consumes more memory.
+	TiDBIndexLookupFetchAhead = "tidb_index_lookup_fetch_ahead"
+
+	// TiDBTableReaderHandleBatch is used for the table readers built from handles, like the ones of index lookup and
+	// index join. If the number of handles exceeds this value, the handles are sorted and turned into ranges in
+	// batches of this size, and the batches are read one after another. 0 means the handles are read in one pass.
+	TiDBTableReaderHandleBatch = "tidb_table_reader_handle_batch"
+
 	// TiDBIndexLookupConcurrency is used for index lookup executor.
 	// A lookup task may have 'tidb_index_lookup_size' of handles at maximum, the handles may be distributed
 	// in many TiKV nodes, we execute multiple concurrent index lookup tasks concurrently to reduce the time
@@ -524,6 +543,10 @@ const (
 	// TiDBEnablePipelinedWindowFunction is used to control whether to use pipelined window function, it only works when tidb_enable_window_function = true.
 	TiDBEnablePipelinedWindowFunction = "tidb_enable_pipelined_window_function"
 
+	// TiDBEnableParallelWindowFunction is used to control whether to evaluate the window functions of a large partition
+	// in parallel, the concurrency is controlled by tidb_window_concurrency.
+	TiDBEnableParallelWindowFunction = "tidb_enable_parallel_window_function"
+
 	// TiDBEnableStrictDoubleTypeCheck is used to control table field double type syntax check.
 	TiDBEnableStrictDoubleTypeCheck = "tidb_enable_strict_double_type_check"
 
@@ -613,6 +636,14 @@ const (
 	// TiDBPartitionPruneMode indicates the partition prune mode used.
 	TiDBPartitionPruneMode = "tidb_partition_prune_mode"
 
+	// TiDBForceStaticPartitionPrune indicates whether to access the partitioned tables in static prune mode even if
+	// the dynamic prune mode is used, which helps to diagnose the issues of dynamic prune mode.
+	TiDBForceStaticPartitionPrune = "tidb_force_static_partition_prune"
+
+	// TiDBWarnOnSynthJoinDefaults indicates whether to append a warning when the executor builder synthesizes the
+	// default values of the inner side of an outer join, which helps to catch the planner bugs missing them.
+	TiDBWarnOnSynthJoinDefaults = "tidb_warn_on_synth_join_defaults"
+
 	// TiDBRedactLog indicates that whether redact log.
 	TiDBRedactLog = "tidb_redact_log"
 
@@ -648,6 +679,10 @@ const (
 	// TiDBAnalyzeVersion indicates how tidb collects the analyzed statistics and how use to it.
 	TiDBAnalyzeVersion = "tidb_analyze_version"
 
+	// TiDBAnalyzeSkipFreshStatsWindow indicates that ANALYZE skips the columns and indexes whose stats are analyzed within
+	// the window, if the modify ratio of the table doesn't exceed tidb_auto_analyze_ratio. 0 means never skipping them.
+	TiDBAnalyzeSkipFreshStatsWindow = "tidb_analyze_skip_fresh_stats_window"
+
 	// TiDBAutoAnalyzePartitionBatchSize indicates the batch size for partition tables for auto analyze in dynamic mode
 	TiDBAutoAnalyzePartitionBatchSize = "tidb_auto_analyze_partition_batch_size"
 
@@ -823,11 +858,106 @@ const (
 	TiDBAnalyzePartitionConcurrency = "tidb_analyze_partition_concurrency"
 	// TiDBMergePartitionStatsConcurrency indicates the concurrency when merge partition stats into global stats
 	TiDBMergePartitionStatsConcurrency = "tidb_merge_partition_stats_concurrency"
+	// TiDBAnalyzeSampleMemQuota indicates the memory quota of the samples held by all the tasks of an analyze statement.
+	// The tasks reduce the samples in flight instead of failing when the quota is exceeded.
+	TiDBAnalyzeSampleMemQuota = "tidb_analyze_sample_mem_quota"
 
 	// TiDBOptPrefixIndexSingleScan indicates whether to do some optimizations to avoid double scan for prefix index.
 	// When set to true, `col is (not) null`(`col` is index prefix column) is regarded as index filter rather than table filter.
 	TiDBOptPrefixIndexSingleScan = "tidb_opt_prefix_index_single_scan"
 
+	// TiDBOptIndexReaderVirtualColumn indicates whether a covering index reader can serve a virtual generated column
+	// by computing it in TiDB, when all the columns it depends on are stored in the index.
+	TiDBOptIndexReaderVirtualColumn = "tidb_opt_index_reader_virtual_column"
+
+	// TiDBTSFetchRetry is the max number of times a reader retries fetching the snapshot timestamp after a failure.
+	TiDBTSFetchRetry = "tidb_ts_fetch_retry"
+
+	// TiDBEnableHybridStoreScan indicates whether a TiKV table reader over a range partitioned table can read the
+	// cold partitions from TiFlash and only the latest partition from TiKV. It's experimental.
+	TiDBEnableHybridStoreScan = "tidb_enable_hybrid_store_scan"
+
+	// TiDBBatchPointGetMaxSize is the max number of the deduplicated keys a batch point get can read,
+	// the statement is rejected when it's exceeded.
+	TiDBBatchPointGetMaxSize = "tidb_batch_point_get_max_size"
+
+	// TiDBAdminCheckForeignKey indicates whether `admin check table` also checks that every foreign key value of
+	// the table has a matching row in the referenced table.
+	TiDBAdminCheckForeignKey = "tidb_admin_check_foreign_key"
+
+	// TiDBHashJoinRadixBits is the number of the hash key bits used to partition the build side of hash join,
+	// 0 means the build side is not partitioned. It's experimental.
+	TiDBHashJoinRadixBits = "tidb_hash_join_radix_bits"
+
+	// TiDBStaleReadPreferFollower indicates whether the stale read statements read from the followers
+	// even if the replica read of the session is leader.
+	TiDBStaleReadPreferFollower = "tidb_stale_read_prefer_follower"
+
+	// TiDBDeferUniqueCheckInUpdate indicates whether the UPDATE statements check the duplicated unique keys
+	// at the end of the statement, so the rows conflicting with the rows not updated yet can be updated.
+	TiDBDeferUniqueCheckInUpdate = "tidb_defer_unique_check_in_update"
+
+	// TiDBCopStoreBreakerEnable indicates whether the coprocessor requests reading from the leaders keep away from
+	// the TiKV stores failing repeatedly, and are served by the replicas on the other stores for a while.
+	TiDBCopStoreBreakerEnable = "tidb_cop_store_breaker_enable"
+
+	// TiDBAutoIDBatchSize is the max number of the auto-increment or auto-random IDs which the INSERT statements
+	// pre-allocate at a time, 1 means allocating the IDs row by row.
+	TiDBAutoIDBatchSize = "tidb_auto_id_batch_size"
+
+	// TiDBHashJoinProbePrefetch is the number of the probe side chunks which hash join reads ahead
+	// while the probe workers process the current ones, 0 means reading the probe side on demand.
+	TiDBHashJoinProbePrefetch = "tidb_hash_join_probe_prefetch"
+
+	// TiDBLoadDataConcurrency is the number of the files which LOAD DATA reads and encodes concurrently
+	// when the file location matches multiple files.
+	TiDBLoadDataConcurrency = "tidb_load_data_concurrency"
+
+	// TiDBVerifyRowChecksumOnRead indicates whether the point get and batch point get verify the
+	// row level checksums of the rows they read, the rows without checksums are not verified.
+	TiDBVerifyRowChecksumOnRead = "tidb_verify_row_checksum_on_read"
+
+	// TiDBCopSplitLargeRegions indicates whether the table reader splits the range located in a single
+	// region into sub-ranges by the handle histogram, so the region can be scanned by parallel cop tasks.
+	TiDBCopSplitLargeRegions = "tidb_cop_split_large_regions"
+
+	// TiDBAnalyzeDumpSamples is the table, in the form of `db.table` or `table`, which ANALYZE writes the
+	// sampled rows into for diagnosis, empty means the samples are not dumped.
+	TiDBAnalyzeDumpSamples = "tidb_analyze_dump_samples"
+
+	// TiDBEnableBandJoin indicates whether the hash join without equal conditions, whose join condition is a range
+	// like `a.t BETWEEN b.lo AND b.hi`, is executed by the band merge join.
+	TiDBEnableBandJoin = "tidb_enable_band_join"
+
+	// TiDBMemTableMaxResultBytes is the max bytes of the rows retrieved by a memory table reader,
+	// the query fails once the rows exceed it, 0 means no limit.
+	TiDBMemTableMaxResultBytes = "tidb_memtable_max_result_bytes"
+
+	// TiDBIndexAdvisorFeedback indicates whether the index readers record the observed selectivities of
+	// the index scans in the session, which are taken into account by the index advisor.
+	TiDBIndexAdvisorFeedback = "tidb_index_advisor_feedback"
+
+	// TiDBAssertOperatorRows is a JSON object which maps the operator ids, like `HashAgg_5`, to their expected
+	// actual rows. EXPLAIN ANALYZE fails if the actual rows of an operator mismatch. It's only used in tests.
+	TiDBAssertOperatorRows = "tidb_assert_operator_rows"
+
+	// TiDBEnableStreamingAggPreview indicates whether the hash aggregation emits its intermediate results
+	// periodically to the client protocols which accept them, it's an experimental feature.
+	TiDBEnableStreamingAggPreview = "tidb_enable_streaming_agg_preview"
+
+	// TiDBChaosBuildLatencyMs is the latency in milliseconds injected when building the executors of a statement,
+	// it simulates the slow startup of the statements. It's only used in the chaos tests.
+	TiDBChaosBuildLatencyMs = "tidb_chaos_build_latency_ms"
+
+	// TiDBCollectUpdateColumnStats indicates whether to count the rows whose assigned columns are really changed
+	// in the UPDATE statements, the counts of the last statement are shown in `information_schema.LAST_UPDATE_COLUMN_STATS`.
+	TiDBCollectUpdateColumnStats = "tidb_collect_update_column_stats"
+
+	// TiDBPointGetFollowerRead indicates whether the non-locking point gets read from the followers to offload the
+	// leaders of the hot keys when the replica read of the session is leader. The followers still return the data at
+	// the read ts, but they may need to wait for catching up with the leaders, which adds the latency of the reads.
+	TiDBPointGetFollowerRead = "tidb_point_get_follower_read"
+
 	// TiDBEnableExternalTSRead indicates whether to enable read through an external ts
 	TiDBEnableExternalTSRead = "tidb_enable_external_ts_read"
 
@@ -1070,7 +1200,10 @@ const (
 	DefIndexSerialScanConcurrency                  = 1
 	DefIndexJoinBatchSize                          = 25000
 	DefIndexLookupSize                             = 20000
+	DefIndexLookupFetchAhead                       = 50
+	DefTableReaderHandleBatch                      = 100000
 	DefDistSQLScanConcurrency                      = 15
+	DefCopMaxConcurrentTasks                       = 0
 	DefBuildStatsConcurrency                       = 4
 	DefAutoAnalyzeRatio                            = 0.5
 	DefAutoAnalyzeStartTime                        = "00:00 +0000"
@@ -1103,6 +1236,7 @@ const (
 	DefOptInSubqToJoinAndAgg                       = true
 	DefOptPreferRangeScan                          = false
 	DefBatchInsert                                 = false
+	DefTiDBInsertSortByHandle                      = false
 	DefBatchDelete                                 = false
 	DefBatchCommit                                 = false
 	DefCurretTS                                    = 0
@@ -1157,6 +1291,7 @@ const (
 	DefTiDBForcePriority                           = mysql.NoPriority
 	DefEnableWindowFunction                        = true
 	DefEnablePipelinedWindowFunction               = true
+	DefEnableParallelWindowFunction                = false
 	DefEnableStrictDoubleTypeCheck                 = true
 	DefEnableVectorizedExpression                  = true
 	DefTiDBOptJoinReorderThreshold                 = 0
@@ -1192,11 +1327,14 @@ const (
 	DefTiDBEnableTelemetry                         = false
 	DefTiDBEnableParallelApply                     = false
 	DefTiDBPartitionPruneMode                      = "dynamic"
+	DefTiDBForceStaticPartitionPrune               = false
+	DefTiDBWarnOnSynthJoinDefaults                 = false
 	DefTiDBEnableRateLimitAction                   = false
 	DefTiDBEnableAsyncCommit                       = false
 	DefTiDBEnable1PC                               = false
 	DefTiDBGuaranteeLinearizability                = true
 	DefTiDBAnalyzeVersion                          = 2
+	DefTiDBAnalyzeSkipFreshStatsWindow             = time.Duration(0)
 	DefTiDBAutoAnalyzePartitionBatchSize           = 1
 	DefTiDBEnableIndexMergeJoin                    = false
 	DefTiDBTrackAggregateMemoryUsage               = true
@@ -1289,6 +1427,7 @@ const (
 	DefTiDBForeignKeyChecks                      = true
 	DefTiDBOptAdvancedJoinHint                   = true
 	DefTiDBAnalyzePartitionConcurrency           = 1
+	DefTiDBAnalyzeSampleMemQuota                 = -1
 	DefTiDBOptRangeMaxSize                       = 64 * int64(size.MB) // 64 MB
 	DefTiDBCostModelVer                          = 2
 	DefTiDBServerMemoryLimitSessMinSize          = 128 << 20
@@ -1298,6 +1437,29 @@ const (
 	// DefTiDBGOGCTunerThreshold is to limit TiDBGOGCTunerThreshold.
 	DefTiDBGOGCTunerThreshold                 float64 = 0.6
 	DefTiDBOptPrefixIndexSingleScan                   = true
+	DefTiDBOptIndexReaderVirtualColumn                = false
+	DefTiDBTSFetchRetry                               = 2
+	DefTiDBEnableHybridStoreScan                      = false
+	DefTiDBBatchPointGetMaxSize                       = 10000000
+	DefTiDBAdminCheckForeignKey                       = false
+	DefTiDBHashJoinRadixBits                          = 0
+	DefTiDBStaleReadPreferFollower                    = false
+	DefTiDBDeferUniqueCheckInUpdate                   = false
+	DefTiDBCopStoreBreakerEnable                      = false
+	DefTiDBAutoIDBatchSize                            = 1
+	DefTiDBHashJoinProbePrefetch                      = 0
+	DefTiDBLoadDataConcurrency                        = 1
+	DefTiDBVerifyRowChecksumOnRead                    = false
+	DefTiDBCopSplitLargeRegions                       = false
+	DefTiDBAnalyzeDumpSamples                         = ""
+	DefTiDBEnableBandJoin                             = false
+	DefTiDBMemTableMaxResultBytes                     = 4 << 30
+	DefTiDBIndexAdvisorFeedback                       = false
+	DefTiDBAssertOperatorRows                         = ""
+	DefTiDBEnableStreamingAggPreview                  = false
+	DefTiDBChaosBuildLatencyMs                        = 0
+	DefTiDBCollectUpdateColumnStats                   = false
+	DefTiDBPointGetFollowerRead                       = false
 	DefTiDBExternalTS                                 = 0
 	DefTiDBEnableExternalTSRead                       = false
 	DefTiDBEnableReusechunk                           = true
@@ -1359,6 +1521,7 @@ var (
 	EnablePProfSQLCPU             = atomic.NewBool(false)
 	EnableBatchDML                = atomic.NewBool(false)
 	EnableTmpStorageOnOOM         = atomic.NewBool(DefTiDBEnableTmpStorageOnOOM)
+	EnableCopStoreBreaker         = atomic.NewBool(DefTiDBCopStoreBreakerEnable)
 	ddlReorgWorkerCounter   int32 = DefTiDBDDLReorgWorkerCount
 	ddlReorgBatchSize       int32 = DefTiDBDDLReorgBatchSize
 	ddlFlashbackConcurrency int32 = DefTiDBDDLFlashbackConcurrency
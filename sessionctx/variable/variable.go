@@ -633,6 +633,9 @@ type GlobalVarAccessor interface {
 	SetGlobalSysVar(ctx context.Context, name string, value string) error
 	// SetGlobalSysVarOnly sets the global system variable without calling the validation function or updating aliases.
 	SetGlobalSysVarOnly(ctx context.Context, name string, value string, updateLocal bool) error
+	// SetGlobalSysVarsOnly sets several global system variables in one transaction, without calling the validation
+	// functions or updating aliases.
+	SetGlobalSysVarsOnly(ctx context.Context, values map[string]string) error
 	// GetTiDBTableValue gets a value from mysql.tidb for the key 'name'
 	GetTiDBTableValue(name string) (string, error)
 	// SetTiDBTableValue sets a value+comment for the mysql.tidb key 'name'
@@ -54,6 +54,8 @@ func TestNewSessionVars(t *testing.T) {
 
 	require.Equal(t, DefIndexJoinBatchSize, vars.IndexJoinBatchSize)
 	require.Equal(t, DefIndexLookupSize, vars.IndexLookupSize)
+	require.Equal(t, DefIndexLookupFetchAhead, vars.IndexLookupFetchAhead)
+	require.Equal(t, DefTableReaderHandleBatch, vars.TableReaderHandleBatch)
 	require.Equal(t, ConcurrencyUnset, vars.indexLookupConcurrency)
 	require.Equal(t, DefIndexSerialScanConcurrency, vars.indexSerialScanConcurrency)
 	require.Equal(t, ConcurrencyUnset, vars.indexLookupJoinConcurrency)
@@ -285,6 +285,8 @@ func (p *baseTxnContextProvider) ActivateTxn() (kv.Transaction, error) {
 	txnFuture := p.sctx.GetPreparedTxnFuture()
 	txn, err := txnFuture.Wait(p.ctx, p.sctx)
 	if err != nil {
+		// The failed future has been consumed, prepare a new one if the txn is activated again.
+		p.isTxnPrepared = false
 		return nil, err
 	}
 
@@ -494,6 +496,12 @@ func newOracleFuture(ctx context.Context, sctx sessionctx.Context, scope string)
 		sessiontxn.TsoRequestCountInc(sctx)
 	})
 
+	failpoint.InjectContext(ctx, "mockOracleFutureErr", func() {
+		failpoint.Return(funcFuture(func() (uint64, error) {
+			return 0, errors.New("mock oracle future error")
+		}))
+	})
+
 	oracleStore := sctx.GetStore().GetOracle()
 	option := &oracle.Option{TxnScope: scope}
 
@@ -187,6 +187,8 @@ func (p *PessimisticRCTxnContextProvider) getStmtTS() (ts uint64, err error) {
 	p.prepareStmtTS()
 	start := time.Now()
 	if ts, err = p.stmtTSFuture.Wait(); err != nil {
+		// Request a new ts if it's fetched again.
+		p.stmtTSFuture = nil
 		return 0, err
 	}
 	p.sctx.GetSessionVars().DurationWaitTS += time.Since(start)
@@ -285,6 +285,10 @@ func parseAndValidateAsOf(ctx context.Context, sctx sessionctx.Context, asOf *as
 		return 0, err
 	}
 
+	if ts, err = pickBoundedStalenessTS(sctx, asOf.TsExpr, ts); err != nil {
+		return 0, err
+	}
+
 	if err = sessionctx.ValidateStaleReadTS(ctx, sctx, ts); err != nil {
 		return 0, err
 	}
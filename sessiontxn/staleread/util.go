@@ -19,13 +19,16 @@ import (
 	"time"
 
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/mathutil"
 	"github.com/tikv/client-go/v2/oracle"
 )
 
@@ -40,6 +43,44 @@ func CalculateAsOfTsExpr(ctx context.Context, sctx sessionctx.Context, tsExpr as
 		// this can be more accurate than `time.Now() - staleness`, because TiDB's local time can drift.
 		return sctx.GetStore().GetOracle().GetStaleTimestamp(ctx, oracle.GlobalTxnScope, 0)
 	})
+	return evalAsOfTime(sctx, tsExpr)
+}
+
+// pickBoundedStalenessTS picks the freshest ts in the window of the bounded staleness read which can be served by
+// the closest replicas without waiting, the replicas in other scopes may lag behind and hold the ts resolved by
+// tidb_bounded_staleness back. The ts is picked before the statement is planned, so the schema and the snapshot
+// share it. The given ts is kept if it's not a bounded staleness read or no fresher ts is available in the window.
+func pickBoundedStalenessTS(sctx sessionctx.Context, tsExpr ast.ExprNode, ts uint64) (uint64, error) {
+	fn, ok := tsExpr.(*ast.FuncCallExpr)
+	if !ok || fn.FnName.L != ast.TiDBBoundedStaleness || len(fn.Args) != 2 {
+		return ts, nil
+	}
+	scope := config.GetTxnScopeFromConfig()
+	if !sctx.GetSessionVars().GetReplicaRead().IsClosestRead() || scope == kv.GlobalReplicaScope {
+		return ts, nil
+	}
+	minTS, err := evalAsOfTime(sctx, fn.Args[0])
+	if err != nil {
+		return 0, err
+	}
+	maxTS, err := evalAsOfTime(sctx, fn.Args[1])
+	if err != nil {
+		return 0, err
+	}
+	var safeTS uint64
+	if store := sctx.GetStore(); store != nil {
+		safeTS = store.GetMinSafeTS(scope)
+	}
+	failpoint.Inject("injectBoundedStalenessSafeTS", func(val failpoint.Value) {
+		safeTS = uint64(val.(int))
+	})
+	if safeTS >= minTS {
+		ts = mathutil.Max(ts, mathutil.Min(safeTS, maxTS))
+	}
+	return ts, nil
+}
+
+func evalAsOfTime(sctx sessionctx.Context, tsExpr ast.ExprNode) (uint64, error) {
 	tsVal, err := expression.EvalAstExpr(sctx, tsExpr)
 	if err != nil {
 		return 0, err
@@ -404,6 +404,80 @@ func BuildHistAndTopN(
 	return hg, topn, nil
 }
 
+// BuildHistWithTopN rebuilds the histogram from the samples while keeping the given TopN unchanged.
+// The samples whose values are in the TopN are excluded, and the rest are used to build the histogram.
+func BuildHistWithTopN(
+	ctx sessionctx.Context,
+	numBuckets int,
+	id int64,
+	collector *SampleCollector,
+	tp *types.FieldType,
+	isColumn bool,
+	memTracker *memory.Tracker,
+	topn *TopN,
+) (*Histogram, *TopN, error) {
+	topn = topn.Copy()
+	count := collector.Count
+	ndv := collector.FMSketch.NDV()
+	nullCount := collector.NullCount
+	if ndv > count {
+		ndv = count
+	}
+	if count == 0 || len(collector.Samples) == 0 || ndv == 0 {
+		return NewHistogram(id, ndv, nullCount, 0, tp, 0, collector.TotalSize), topn, nil
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	samples, err := SortSampleItems(sc, collector.Samples)
+	if err != nil {
+		return nil, nil, err
+	}
+	hg := NewHistogram(id, ndv, nullCount, 0, tp, numBuckets, collector.TotalSize)
+	sampleNum := int64(len(samples))
+	if isColumn {
+		var corrXYSum float64
+		for i := int64(0); i < sampleNum; i++ {
+			corrXYSum += float64(i) * float64(samples[i].Ordinal)
+		}
+		hg.Correlation = calcCorrelation(sampleNum, corrXYSum)
+	}
+
+	// Exclude the values in the TopN from samples.
+	rest := make([]*SampleItem, 0, len(samples))
+	for _, sample := range samples {
+		var encoded []byte
+		if isColumn {
+			encoded, err = codec.EncodeKey(sc, nil, sample.Value)
+			if err != nil {
+				return nil, nil, errors.Trace(err)
+			}
+		} else {
+			encoded = sample.Value.GetBytes()
+		}
+		if _, found := topn.QueryTopN(nil, encoded); found {
+			continue
+		}
+		rest = append(rest, sample)
+	}
+	if len(rest) == 0 {
+		return hg, topn, nil
+	}
+	// The counts of the TopN are collected from the old data, so the row count of the histogram is
+	// estimated by the proportion of the rest samples.
+	restCount := int64(float64(count) * float64(len(rest)) / float64(sampleNum))
+	restNDV := ndv - int64(topn.Num())
+	if restCount < 1 {
+		restCount = 1
+	}
+	if restNDV < 1 {
+		restNDV = 1
+	}
+	_, err = buildHist(sc, hg, rest, restCount, restNDV, int64(numBuckets), memTracker)
+	if err != nil {
+		return nil, nil, err
+	}
+	return hg, topn, nil
+}
+
 // pruneTopNItem tries to prune the least common values in the top-n list if it is not significantly more common than the values not in the list.
 //
 //	We assume that the ones not in the top-n list's selectivity is 1/remained_ndv which is the internal implementation of EqualRowCount
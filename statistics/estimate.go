@@ -28,10 +28,22 @@ func calculateEstimateNDV(h *topNHelper, rowCount uint64) (ndv uint64, scaleRati
 	if onlyOnceItems == sampleSize {
 		// Assume this is a unique column, so do not scale up the count of elements
 		return rowCount, 1
+	}
+	return EstimateNDVBySample(sampleSize, sampleNDV, onlyOnceItems, rowCount), scaleRatio
+}
+
+// EstimateNDVBySample estimates the ndv of the data with rowCount rows by a sample of it. sampleNDV is the ndv of the
+// sample, and onlyOnceItems is the number of the values occurring only once in the sample.
+func EstimateNDVBySample(sampleSize, sampleNDV, onlyOnceItems, rowCount uint64) uint64 {
+	if sampleSize == 0 {
+		return 0
+	}
+	if onlyOnceItems == sampleSize {
+		// Assume this is a unique column.
+		return mathutil.Max(rowCount, sampleSize)
 	} else if onlyOnceItems == 0 {
 		// Assume data only consists of sampled data
-		// Nothing to do, no change with scale ratio
-		return sampleNDV, scaleRatio
+		return sampleNDV
 	}
 	// Charikar, Moses, et al. "Towards estimation error guarantees for distinct values."
 	// Proceedings of the nineteenth ACM SIGMOD-SIGACT-SIGART symposium on Principles of database systems. ACM, 2000.
@@ -44,8 +56,8 @@ func calculateEstimateNDV(h *topNHelper, rowCount uint64) (ndv uint64, scaleRati
 	N := float64(rowCount)
 	d := float64(sampleNDV)
 
-	ndv = uint64(math.Sqrt(N/n)*f1 + d - f1 + 0.5)
+	ndv := uint64(math.Sqrt(N/n)*f1 + d - f1 + 0.5)
 	ndv = mathutil.Max(ndv, sampleNDV)
 	ndv = mathutil.Min(ndv, rowCount)
-	return ndv, scaleRatio
+	return ndv
 }
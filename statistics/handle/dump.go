@@ -38,17 +38,19 @@ import (
 )
 
 // JSONTable is used for dumping statistics.
+// The table level fields are placed before the columns and indices, so that the loader decoding the dumped file
+// as a stream knows which table the columns and indices belong to when it meets them.
 type JSONTable struct {
-	Columns           map[string]*jsonColumn `json:"columns"`
-	Indices           map[string]*jsonColumn `json:"indices"`
-	Partitions        map[string]*JSONTable  `json:"partitions"`
 	DatabaseName      string                 `json:"database_name"`
 	TableName         string                 `json:"table_name"`
-	ExtStats          []*jsonExtendedStats   `json:"ext_stats"`
 	Count             int64                  `json:"count"`
 	ModifyCount       int64                  `json:"modify_count"`
 	Version           uint64                 `json:"version"`
 	IsHistoricalStats bool                   `json:"is_historical_stats"`
+	ExtStats          []*jsonExtendedStats   `json:"ext_stats"`
+	Columns           map[string]*jsonColumn `json:"columns"`
+	Indices           map[string]*jsonColumn `json:"indices"`
+	Partitions        map[string]*JSONTable  `json:"partitions"`
 }
 
 type jsonExtendedStats struct {
@@ -418,26 +420,7 @@ func TableStatsFromJSON(tableInfo *model.TableInfo, physicalID int64, jsonTbl *J
 			if idxInfo.Name.L != id {
 				continue
 			}
-			hist := statistics.HistogramFromProto(jsonIdx.Histogram)
-			hist.ID, hist.NullCount, hist.LastUpdateVersion, hist.Correlation = idxInfo.ID, jsonIdx.NullCount, jsonIdx.LastUpdateVersion, jsonIdx.Correlation
-			cm, topN := statistics.CMSketchAndTopNFromProto(jsonIdx.CMSketch)
-			statsVer := int64(statistics.Version0)
-			if jsonIdx.StatsVer != nil {
-				statsVer = *jsonIdx.StatsVer
-			} else if jsonIdx.Histogram.Ndv > 0 || jsonIdx.NullCount > 0 {
-				// If the statistics are collected without setting stats version(which happens in v4.0 and earlier versions),
-				// we set it to 1.
-				statsVer = int64(statistics.Version1)
-			}
-			idx := &statistics.Index{
-				Histogram:         *hist,
-				CMSketch:          cm,
-				TopN:              topN,
-				Info:              idxInfo,
-				StatsVer:          statsVer,
-				PhysicalID:        physicalID,
-				StatsLoadedStatus: statistics.NewStatsFullLoadStatus(),
-			}
+			idx := indexStatsFromJSON(idxInfo, physicalID, jsonIdx)
 			tbl.Indices[idx.ID] = idx
 		}
 	}
@@ -447,45 +430,10 @@ func TableStatsFromJSON(tableInfo *model.TableInfo, physicalID int64, jsonTbl *J
 			if colInfo.Name.L != id {
 				continue
 			}
-			hist := statistics.HistogramFromProto(jsonCol.Histogram)
-			sc := &stmtctx.StatementContext{TimeZone: time.UTC}
-			tmpFT := colInfo.FieldType
-			// For new collation data, when storing the bounds of the histogram, we store the collate key instead of the
-			// original value.
-			// But there's additional conversion logic for new collation data, and the collate key might be longer than
-			// the FieldType.flen.
-			// If we use the original FieldType here, there might be errors like "Invalid utf8mb4 character string"
-			// or "Data too long".
-			// So we change it to TypeBlob to bypass those logics here.
-			if colInfo.FieldType.EvalType() == types.ETString && colInfo.FieldType.GetType() != mysql.TypeEnum && colInfo.FieldType.GetType() != mysql.TypeSet {
-				tmpFT = *types.NewFieldType(mysql.TypeBlob)
-			}
-			hist, err := hist.ConvertTo(sc, &tmpFT)
+			col, err := columnStatsFromJSON(tableInfo, colInfo, physicalID, jsonCol)
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
-			cm, topN := statistics.CMSketchAndTopNFromProto(jsonCol.CMSketch)
-			fms := statistics.FMSketchFromProto(jsonCol.FMSketch)
-			hist.ID, hist.NullCount, hist.LastUpdateVersion, hist.TotColSize, hist.Correlation = colInfo.ID, jsonCol.NullCount, jsonCol.LastUpdateVersion, jsonCol.TotColSize, jsonCol.Correlation
-			statsVer := int64(statistics.Version0)
-			if jsonCol.StatsVer != nil {
-				statsVer = *jsonCol.StatsVer
-			} else if jsonCol.Histogram.Ndv > 0 || jsonCol.NullCount > 0 {
-				// If the statistics are collected without setting stats version(which happens in v4.0 and earlier versions),
-				// we set it to 1.
-				statsVer = int64(statistics.Version1)
-			}
-			col := &statistics.Column{
-				PhysicalID:        physicalID,
-				Histogram:         *hist,
-				CMSketch:          cm,
-				TopN:              topN,
-				FMSketch:          fms,
-				Info:              colInfo,
-				IsHandle:          tableInfo.PKIsHandle && mysql.HasPriKeyFlag(colInfo.GetFlag()),
-				StatsVer:          statsVer,
-				StatsLoadedStatus: statistics.NewStatsFullLoadStatus(),
-			}
 			tbl.Columns[col.ID] = col
 		}
 	}
@@ -493,6 +441,71 @@ func TableStatsFromJSON(tableInfo *model.TableInfo, physicalID int64, jsonTbl *J
 	return tbl, nil
 }
 
+func indexStatsFromJSON(idxInfo *model.IndexInfo, physicalID int64, jsonIdx *jsonColumn) *statistics.Index {
+	hist := statistics.HistogramFromProto(jsonIdx.Histogram)
+	hist.ID, hist.NullCount, hist.LastUpdateVersion, hist.Correlation = idxInfo.ID, jsonIdx.NullCount, jsonIdx.LastUpdateVersion, jsonIdx.Correlation
+	cm, topN := statistics.CMSketchAndTopNFromProto(jsonIdx.CMSketch)
+	statsVer := int64(statistics.Version0)
+	if jsonIdx.StatsVer != nil {
+		statsVer = *jsonIdx.StatsVer
+	} else if jsonIdx.Histogram.Ndv > 0 || jsonIdx.NullCount > 0 {
+		// If the statistics are collected without setting stats version(which happens in v4.0 and earlier versions),
+		// we set it to 1.
+		statsVer = int64(statistics.Version1)
+	}
+	return &statistics.Index{
+		Histogram:         *hist,
+		CMSketch:          cm,
+		TopN:              topN,
+		Info:              idxInfo,
+		StatsVer:          statsVer,
+		PhysicalID:        physicalID,
+		StatsLoadedStatus: statistics.NewStatsFullLoadStatus(),
+	}
+}
+
+func columnStatsFromJSON(tableInfo *model.TableInfo, colInfo *model.ColumnInfo, physicalID int64, jsonCol *jsonColumn) (*statistics.Column, error) {
+	hist := statistics.HistogramFromProto(jsonCol.Histogram)
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	tmpFT := colInfo.FieldType
+	// For new collation data, when storing the bounds of the histogram, we store the collate key instead of the
+	// original value.
+	// But there's additional conversion logic for new collation data, and the collate key might be longer than
+	// the FieldType.flen.
+	// If we use the original FieldType here, there might be errors like "Invalid utf8mb4 character string"
+	// or "Data too long".
+	// So we change it to TypeBlob to bypass those logics here.
+	if colInfo.FieldType.EvalType() == types.ETString && colInfo.FieldType.GetType() != mysql.TypeEnum && colInfo.FieldType.GetType() != mysql.TypeSet {
+		tmpFT = *types.NewFieldType(mysql.TypeBlob)
+	}
+	hist, err := hist.ConvertTo(sc, &tmpFT)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cm, topN := statistics.CMSketchAndTopNFromProto(jsonCol.CMSketch)
+	fms := statistics.FMSketchFromProto(jsonCol.FMSketch)
+	hist.ID, hist.NullCount, hist.LastUpdateVersion, hist.TotColSize, hist.Correlation = colInfo.ID, jsonCol.NullCount, jsonCol.LastUpdateVersion, jsonCol.TotColSize, jsonCol.Correlation
+	statsVer := int64(statistics.Version0)
+	if jsonCol.StatsVer != nil {
+		statsVer = *jsonCol.StatsVer
+	} else if jsonCol.Histogram.Ndv > 0 || jsonCol.NullCount > 0 {
+		// If the statistics are collected without setting stats version(which happens in v4.0 and earlier versions),
+		// we set it to 1.
+		statsVer = int64(statistics.Version1)
+	}
+	return &statistics.Column{
+		PhysicalID:        physicalID,
+		Histogram:         *hist,
+		CMSketch:          cm,
+		TopN:              topN,
+		FMSketch:          fms,
+		Info:              colInfo,
+		IsHandle:          tableInfo.PKIsHandle && mysql.HasPriKeyFlag(colInfo.GetFlag()),
+		StatsVer:          statsVer,
+		StatsLoadedStatus: statistics.NewStatsFullLoadStatus(),
+	}, nil
+}
+
 // JSONTableToBlocks convert JSONTable to json, then compresses it to blocks by gzip.
 func JSONTableToBlocks(jsTable *JSONTable, blockSize int) ([][]byte, error) {
 	data, err := json.Marshal(jsTable)
@@ -0,0 +1,382 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/util/memory"
+)
+
+// LoadStatsFromJSONReader loads the statistic dumped as a JSONTable from r, and saves it to the storage.
+// Different from LoadStatsFromJSON, the file is decoded as a stream: every column and index is saved once it's
+// decoded, so only the items which are waiting for being saved are kept in memory, and their memory usage is tracked
+// by memTracker. If it fails halfway, the columns and indices which have been saved are kept, and the error tells
+// which one fails. The data after the stats is rejected before the meta of the table is saved.
+func (h *Handle) LoadStatsFromJSONReader(is infoschema.InfoSchema, r io.Reader, memTracker *memory.Tracker) error {
+	if memTracker == nil {
+		memTracker = memory.NewTracker(memory.LabelForLoadStats, -1)
+	}
+	l := &jsonTableStreamLoader{h: h, is: is, dec: json.NewDecoder(r), memTracker: memTracker}
+	tok, err := l.dec.Token()
+	if err == io.EOF {
+		// The file is empty, there is nothing to load.
+		return nil
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = expectDelim(tok, '{'); err != nil {
+		return err
+	}
+	err = l.loadTable(&jsonTableStreamState{})
+	// Make the items saved before visible even if it fails halfway.
+	if updateErr := h.Update(is); err == nil {
+		err = updateErr
+	}
+	return err
+}
+
+// jsonTableStreamLoader decodes a dumped JSONTable as a stream, and saves the columns and indices one by one.
+type jsonTableStreamLoader struct {
+	h          *Handle
+	is         infoschema.InfoSchema
+	dec        *json.Decoder
+	memTracker *memory.Tracker
+}
+
+// jsonTableStreamState is the state of a JSONTable object being decoded, it's either the table or one of its
+// partitions.
+type jsonTableStreamState struct {
+	// partition is the key of the object in the partitions of its parent, it's empty for the table.
+	partition   string
+	isPartition bool
+
+	dbName, tblName string
+	count           int64
+	modifyCount     int64
+	countSeen       bool
+	extStats        []*jsonExtendedStats
+	hasPartitions   bool
+
+	resolved bool
+	// deferred means whether the items are saved is unknown until the object ends.
+	deferred   bool
+	skip       bool
+	tableInfo  *model.TableInfo
+	physicalID int64
+	// pending are the items decoded before the object is resolved.
+	pending []*jsonStreamItem
+}
+
+// jsonStreamItem is a decoded column or index.
+type jsonStreamItem struct {
+	name    string
+	isIndex bool
+	col     *jsonColumn
+	memSize int64
+}
+
+func (item *jsonStreamItem) String() string {
+	if item.isIndex {
+		return "index " + item.name
+	}
+	return "column " + item.name
+}
+
+func (s *jsonTableStreamState) String() string {
+	name := s.dbName + "." + s.tblName
+	if s.isPartition {
+		name += " partition " + s.partition
+	}
+	return name
+}
+
+// expectEOF checks there is nothing but white spaces after the dumped table.
+func (l *jsonTableStreamLoader) expectEOF() error {
+	tok, err := l.dec.Token()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Errorf("Load Stats: unexpected token %v after the end of the stats", tok)
+}
+
+func expectDelim(tok json.Token, delim json.Delim) error {
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return errors.Errorf("Load Stats: unexpected token %v, expect %v", tok, delim)
+	}
+	return nil
+}
+
+// loadTable decodes the fields of a JSONTable object whose '{' has been consumed.
+func (l *jsonTableStreamLoader) loadTable(s *jsonTableStreamState) error {
+	for l.dec.More() {
+		key, err := l.decodeKey()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "database_name":
+			err = l.dec.Decode(&s.dbName)
+		case "table_name":
+			err = l.dec.Decode(&s.tblName)
+		case "count":
+			err = l.dec.Decode(&s.count)
+			s.countSeen = true
+		case "modify_count":
+			err = l.dec.Decode(&s.modifyCount)
+		case "ext_stats":
+			err = l.dec.Decode(&s.extStats)
+		case "columns", "indices":
+			err = l.loadItems(s, key == "indices")
+		case "partitions":
+			err = l.loadPartitions(s)
+		default:
+			// The fields like version are not used when loading.
+			var ignored json.RawMessage
+			err = l.dec.Decode(&ignored)
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !s.resolved && s.dbName != "" && s.tblName != "" && s.countSeen {
+			if err := l.resolve(s); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := l.dec.Token(); err != nil {
+		return errors.Trace(err)
+	}
+	if !s.isPartition {
+		// Reject the data after the stats before the rest of the table is saved.
+		if err := l.expectEOF(); err != nil {
+			l.release(s.pending)
+			s.pending = nil
+			return err
+		}
+	}
+	if !s.resolved {
+		if err := l.resolve(s); err != nil {
+			return err
+		}
+	}
+	if !s.isPartition && s.tableInfo.GetPartitionInfo() != nil {
+		// Like LoadStatsFromJSON, the table level items of a partitioned table are loaded only if there are no
+		// partitions dumped.
+		if s.hasPartitions {
+			l.release(s.pending)
+			s.pending = nil
+			return nil
+		}
+		s.deferred, s.physicalID = false, s.tableInfo.ID
+	}
+	if err := l.savePending(s); err != nil {
+		return err
+	}
+	if s.skip {
+		return nil
+	}
+	if err := l.h.SaveExtendedStatsToStorage(s.physicalID, extendedStatsFromJSON(s.extStats), true); err != nil {
+		return errors.Annotatef(err, "Load Stats: failed to load the extended stats of %s", s)
+	}
+	if err := l.h.SaveMetaToStorage(s.physicalID, s.count, s.modifyCount, StatsMetaHistorySourceLoadStats); err != nil {
+		return errors.Annotatef(err, "Load Stats: failed to load the meta of %s", s)
+	}
+	return nil
+}
+
+// resolve finds out which physical table the items of the object belong to.
+func (l *jsonTableStreamLoader) resolve(s *jsonTableStreamState) error {
+	s.resolved = true
+	table, err := l.is.TableByName(model.NewCIStr(s.dbName), model.NewCIStr(s.tblName))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.tableInfo = table.Meta()
+	pi := s.tableInfo.GetPartitionInfo()
+	switch {
+	case !s.isPartition:
+		if pi != nil {
+			s.deferred = true
+			return nil
+		}
+		s.physicalID = s.tableInfo.ID
+	case pi == nil:
+		s.skip = true
+	case s.partition == "global":
+		s.physicalID = s.tableInfo.ID
+	default:
+		s.skip = true
+		for _, def := range pi.Definitions {
+			if def.Name.L == s.partition {
+				s.physicalID, s.skip = def.ID, false
+				break
+			}
+		}
+	}
+	return l.savePending(s)
+}
+
+func (l *jsonTableStreamLoader) decodeKey() (string, error) {
+	tok, err := l.dec.Token()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", errors.Errorf("Load Stats: unexpected token %v, expect a key", tok)
+	}
+	return key, nil
+}
+
+// isNull consumes the next token, which is either null or the beginning of an object.
+func (l *jsonTableStreamLoader) isNull() (bool, error) {
+	tok, err := l.dec.Token()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if tok == nil {
+		return true, nil
+	}
+	return false, expectDelim(tok, '{')
+}
+
+func (l *jsonTableStreamLoader) loadItems(s *jsonTableStreamState, isIndex bool) error {
+	if null, err := l.isNull(); err != nil || null {
+		return err
+	}
+	for l.dec.More() {
+		name, err := l.decodeKey()
+		if err != nil {
+			return err
+		}
+		item := &jsonStreamItem{name: name, isIndex: isIndex}
+		start := l.dec.InputOffset()
+		if err := l.dec.Decode(&item.col); err != nil {
+			return errors.Annotatef(err, "Load Stats: failed to decode %s of %s", item, s)
+		}
+		if err := l.consume(item, l.dec.InputOffset()-start); err != nil {
+			return errors.Annotatef(err, "Load Stats: failed to decode %s of %s", item, s)
+		}
+		s.pending = append(s.pending, item)
+		if err := l.savePending(s); err != nil {
+			return err
+		}
+	}
+	_, err := l.dec.Token()
+	return errors.Trace(err)
+}
+
+func (l *jsonTableStreamLoader) loadPartitions(s *jsonTableStreamState) error {
+	if null, err := l.isNull(); err != nil || null {
+		return err
+	}
+	s.hasPartitions = true
+	for l.dec.More() {
+		name, err := l.decodeKey()
+		if err != nil {
+			return err
+		}
+		if null, err := l.isNull(); err != nil {
+			return err
+		} else if null {
+			continue
+		}
+		if err := l.loadTable(&jsonTableStreamState{partition: name, isPartition: true}); err != nil {
+			return err
+		}
+	}
+	_, err := l.dec.Token()
+	return errors.Trace(err)
+}
+
+// consume tracks the memory of the decoded item, it's estimated by the length of its json.
+func (l *jsonTableStreamLoader) consume(item *jsonStreamItem, size int64) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if str, ok := r.(string); !ok || !strings.HasPrefix(str, memory.PanicMemoryExceedWarnMsg) {
+			panic(r)
+		}
+		err = errors.Errorf("%v", r)
+	}()
+	item.memSize = size
+	l.memTracker.Consume(size)
+	return nil
+}
+
+func (l *jsonTableStreamLoader) release(items []*jsonStreamItem) {
+	for _, item := range items {
+		l.memTracker.Consume(-item.memSize)
+	}
+}
+
+// savePending saves the pending items if the object has been resolved.
+func (l *jsonTableStreamLoader) savePending(s *jsonTableStreamState) error {
+	if !s.resolved || s.deferred {
+		return nil
+	}
+	for len(s.pending) > 0 {
+		item := s.pending[0]
+		s.pending = s.pending[1:]
+		var err error
+		if !s.skip && item.col != nil {
+			err = l.saveItem(s, item)
+		}
+		l.release([]*jsonStreamItem{item})
+		if err != nil {
+			l.release(s.pending)
+			s.pending = nil
+			return errors.Annotatef(err, "Load Stats: failed to load %s of %s", item, s)
+		}
+	}
+	return nil
+}
+
+func (l *jsonTableStreamLoader) saveItem(s *jsonTableStreamState, item *jsonStreamItem) error {
+	// The table level count and modify_count would be overridden by the SaveMetaToStorage when the object ends.
+	if item.isIndex {
+		for _, idxInfo := range s.tableInfo.Indices {
+			if idxInfo.Name.L != item.name {
+				continue
+			}
+			idx := indexStatsFromJSON(idxInfo, s.physicalID, item.col)
+			return l.h.SaveStatsToStorage(s.physicalID, s.count, 0, 1, &idx.Histogram, idx.CMSketch, idx.TopN, int(idx.StatsVer), 1, false, StatsMetaHistorySourceLoadStats)
+		}
+		return nil
+	}
+	for _, colInfo := range s.tableInfo.Columns {
+		if colInfo.Name.L != item.name {
+			continue
+		}
+		col, err := columnStatsFromJSON(s.tableInfo, colInfo, s.physicalID, item.col)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return l.h.SaveStatsToStorage(s.physicalID, s.count, 0, 0, &col.Histogram, col.CMSketch, col.TopN, int(col.StatsVer), 1, false, StatsMetaHistorySourceLoadStats)
+	}
+	return nil
+}
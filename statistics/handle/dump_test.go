@@ -15,8 +15,10 @@
 package handle_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pingcap/tidb/domain"
@@ -26,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/statistics/handle/internal"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/stretchr/testify/require"
 )
 
@@ -534,3 +537,95 @@ func TestLoadStatsFromOldVersion(t *testing.T) {
 		require.False(t, idx.IsStatsInitialized())
 	}
 }
+
+func TestLoadStatsFromJSONReader(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b varchar(20), index ia(a))")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, 'value%d')", i, i%7))
+	}
+	tk.MustExec("analyze table t")
+	is := dom.InfoSchema()
+	h := dom.StatsHandle()
+	require.NoError(t, h.Update(is))
+	table, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tableInfo := table.Meta()
+	originTbl := h.GetTableStats(tableInfo)
+	jsonTbl, err := h.DumpStatsToJSON("test", tableInfo, nil, true)
+	require.NoError(t, err)
+	columns := make(map[string]json.RawMessage)
+	for name, col := range jsonTbl.Columns {
+		columns[name], err = json.Marshal(col)
+		require.NoError(t, err)
+	}
+	indices, err := json.Marshal(jsonTbl.Indices)
+	require.NoError(t, err)
+
+	// Synthesize a large stats file, the columns not existing in the table are decoded and dropped.
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `{"database_name":"test","table_name":"t","count":%d,"modify_count":%d,"columns":{`, jsonTbl.Count, jsonTbl.ModifyCount)
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&buf, `"not_exist_%d":%s,`, i, columns["a"])
+	}
+	fmt.Fprintf(&buf, `"a":%s,"b":%s},"indices":%s}`, columns["a"], columns["b"], indices)
+	data := buf.Bytes()
+
+	cleanStats(tk, dom)
+	tk.MustExec("create table t(a int, b varchar(20), index ia(a))")
+	is = dom.InfoSchema()
+	table, err = is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tableInfo = table.Meta()
+	memTracker := memory.NewTracker(memory.LabelForLoadStats, -1)
+	require.NoError(t, h.LoadStatsFromJSONReader(is, bytes.NewReader(data), memTracker))
+	requireTableEqual(t, h.GetTableStats(tableInfo), originTbl)
+	// Only the item being decoded is held in memory.
+	require.Less(t, memTracker.MaxConsumed(), int64(len(data)/1000))
+	require.Equal(t, int64(0), memTracker.BytesConsumed())
+
+	// The stats dumped by the old versions put the columns before the table name, they're held until the table is known.
+	oldVersion, err := json.Marshal(map[string]interface{}{
+		"columns":       jsonTbl.Columns,
+		"indices":       jsonTbl.Indices,
+		"database_name": "test",
+		"table_name":    "t",
+		"count":         jsonTbl.Count,
+		"modify_count":  jsonTbl.ModifyCount,
+	})
+	require.NoError(t, err)
+	require.NoError(t, h.LoadStatsFromJSONReader(is, bytes.NewReader(oldVersion), nil))
+	requireTableEqual(t, h.GetTableStats(tableInfo), originTbl)
+
+	// The columns loaded before the failure are kept.
+	tk.MustExec("delete from mysql.stats_meta")
+	tk.MustExec("delete from mysql.stats_histograms")
+	tk.MustExec("delete from mysql.stats_buckets")
+	h.Clear()
+	truncated := fmt.Sprintf(`{"database_name":"test","table_name":"t","count":%d,"modify_count":0,"columns":{"a":%s,"b":%s`,
+		jsonTbl.Count, columns["a"], columns["b"][:len(columns["b"])/2])
+	err = h.LoadStatsFromJSONReader(is, strings.NewReader(truncated), nil)
+	require.ErrorContains(t, err, "Load Stats: failed to decode column b of test.t")
+	statsTbl := h.GetTableStats(tableInfo)
+	for _, col := range statsTbl.Columns {
+		if col.Info.Name.L == "a" {
+			require.True(t, statistics.HistogramEqual(&originTbl.Columns[col.ID].Histogram, &col.Histogram, false))
+		} else {
+			require.False(t, col.IsStatsInitialized())
+		}
+	}
+
+	// An empty file loads nothing.
+	require.NoError(t, h.LoadStatsFromJSONReader(is, strings.NewReader(""), nil))
+
+	// The data after the stats is rejected before the meta of the table is saved.
+	tk.MustExec("delete from mysql.stats_meta")
+	trailing := fmt.Sprintf(`{"database_name":"test","table_name":"t","count":%d,"modify_count":77,"columns":{"a":%s}} {}`,
+		jsonTbl.Count, columns["a"])
+	err = h.LoadStatsFromJSONReader(is, strings.NewReader(trailing), nil)
+	require.ErrorContains(t, err, "Load Stats: unexpected token { after the end of the stats")
+	tk.MustQuery(fmt.Sprintf("select modify_count from mysql.stats_meta where table_id = %d", tableInfo.ID)).Check(testkit.Rows("0"))
+	require.NoError(t, h.LoadStatsFromJSONReader(is, strings.NewReader(string(oldVersion)+" \n"), nil))
+}
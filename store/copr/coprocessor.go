@@ -57,6 +57,7 @@ import (
 	"github.com/tikv/client-go/v2/txnkv/txnsnapshot"
 	"github.com/tikv/client-go/v2/util"
 	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
 )
 
 // Maximum total sleep time(in ms) for kv/cop commands.
@@ -325,6 +326,9 @@ func buildCopTasks(bo *Backoffer, ranges *KeyRanges, opt *buildCopTaskOpt) ([]*c
 	}
 
 	rangesPerTaskLimit := rangesPerTask
+	if req.RangesPerTask > 0 && req.RangesPerTask < rangesPerTaskLimit {
+		rangesPerTaskLimit = req.RangesPerTask
+	}
 	failpoint.Inject("setRangesPerTask", func(val failpoint.Value) {
 		if v, ok := val.(int); ok {
 			rangesPerTaskLimit = v
@@ -725,6 +729,9 @@ type copResponse struct {
 	err      error
 	respSize int64
 	respTime time.Duration
+	// calleeAddr and metStoreErr are recorded no matter whether the execution info is collected.
+	calleeAddr  string
+	metStoreErr bool
 }
 
 const sizeofExecDetails = int(unsafe.Sizeof(execdetails.ExecDetails{}))
@@ -743,6 +750,12 @@ func (rs *copResponse) GetCopRuntimeStats() *CopRuntimeStats {
 	return rs.detail
 }
 
+// GetCalleeStore returns the address of the store serving the response, and whether the request has met
+// store errors before it's served.
+func (rs *copResponse) GetCalleeStore() (string, bool) {
+	return rs.calleeAddr, rs.metStoreErr
+}
+
 // MemSize returns how many bytes of memory this response use
 func (rs *copResponse) MemSize() int64 {
 	if rs.respSize != 0 {
@@ -1118,9 +1131,7 @@ func (worker *copIteratorWorker) handleTask(ctx context.Context, task *copTask,
 			remainTasks = remainTasks[1:]
 		}
 	}
-	if worker.store.coprCache != nil && worker.store.coprCache.cache.Metrics != nil {
-		copr_metrics.CoprCacheCounterEvict.Add(float64(worker.store.coprCache.cache.Metrics.KeysEvicted()))
-	}
+	worker.store.coprCache.updateMetrics()
 }
 
 // handleTaskOnce handles single copTask, successful results are send to channel.
@@ -1149,6 +1160,16 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *Backoffer, task *copTask, ch
 	cacheKey, cacheValue := worker.buildCacheKey(task, &copReq)
 
 	replicaRead := worker.req.ReplicaRead
+	var avoidOps []tikv.StoreSelectorOption
+	if len(worker.req.AvoidStores) > 0 && task.storeType == kv.TiKV && replicaRead == kv.ReplicaReadLeader &&
+		worker.leaderOnAvoidedStores(bo, task) {
+		// Only the task whose leader is on an avoided store is served by the replicas on the other stores,
+		// the leader is still chosen if all the other replicas are on the avoided stores.
+		if stores := worker.store.GetRegionCache().GetTiKVStoresExcept(worker.req.AvoidStores); len(stores) > 0 {
+			replicaRead = kv.ReplicaReadMixed
+			avoidOps = append(avoidOps, tikv.WithMatchStores(stores))
+		}
+	}
 	req := tikvrpc.NewReplicaReadRequest(task.cmdType, &copReq, options.GetTiKVReplicaReadType(replicaRead), &worker.replicaReadSeed, kvrpcpb.Context{
 		IsolationLevel: isolationLevelToPB(worker.req.IsolationLevel),
 		Priority:       priorityToPB(worker.req.Priority),
@@ -1183,6 +1204,7 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *Backoffer, task *copTask, ch
 	if len(worker.req.MatchStoreLabels) > 0 {
 		ops = append(ops, tikv.WithMatchLabels(worker.req.MatchStoreLabels))
 	}
+	ops = append(ops, avoidOps...)
 	if task.redirect2Replica != nil {
 		req.ReplicaRead = true
 		req.ReplicaReadType = options.GetTiKVReplicaReadType(kv.ReplicaReadFollower)
@@ -1370,6 +1392,7 @@ func (worker *copIteratorWorker) handleCopResponse(bo *Backoffer, rpcCtx *tikv.R
 	} else if task.ranges != nil && task.ranges.Len() > 0 {
 		resp.startKey = task.ranges.At(0).StartKey
 	}
+	worker.recordCalleeStore(bo, rpcCtx, resp)
 	worker.handleCollectExecutionInfo(bo, rpcCtx, resp)
 	resp.respTime = costTime
 
@@ -1659,6 +1682,35 @@ func (worker *copIteratorWorker) getLockResolverDetails() *util.ResolveLockDetai
 	return &util.ResolveLockDetail{}
 }
 
+// leaderOnAvoidedStores checks whether the leader of the task's region is on the stores the request keeps away from.
+func (worker *copIteratorWorker) leaderOnAvoidedStores(bo *Backoffer, task *copTask) bool {
+	rpcCtx, err := worker.store.GetRegionCache().GetTiKVRPCContext(bo.TiKVBackoffer(), task.region,
+		options.GetTiKVReplicaReadType(kv.ReplicaReadLeader), 0)
+	return err == nil && rpcCtx != nil && slices.Contains(worker.req.AvoidStores, rpcCtx.Addr)
+}
+
+// copStoreErrBackoffTypes are the backoff types caused by the store errors.
+var copStoreErrBackoffTypes = []string{tikv.BoTiKVRPC().String(), "tikvServerBusy"}
+
+// recordCalleeStore records the store serving the response, and whether the store errors are met before.
+func (worker *copIteratorWorker) recordCalleeStore(bo *Backoffer, rpcCtx *tikv.RPCContext, resp *copResponse) {
+	if rpcCtx == nil {
+		return
+	}
+	resp.calleeAddr = rpcCtx.Addr
+	backoffTimes := bo.GetBackoffTimes()
+	for _, tp := range copStoreErrBackoffTypes {
+		if backoffTimes[tp] > 0 {
+			resp.metStoreErr = true
+		}
+	}
+	failpoint.Inject("mockCopStoreErr", func(val failpoint.Value) {
+		if resp.calleeAddr == val.(string) {
+			resp.metStoreErr = true
+		}
+	})
+}
+
 func (worker *copIteratorWorker) handleCollectExecutionInfo(bo *Backoffer, rpcCtx *tikv.RPCContext, resp *copResponse) {
 	defer func() {
 		worker.kvclient.Stats = nil
@@ -1685,6 +1737,7 @@ func (worker *copIteratorWorker) handleCollectExecutionInfo(bo *Backoffer, rpcCt
 	}
 	if rpcCtx != nil {
 		resp.detail.CalleeAddress = rpcCtx.Addr
+		resp.detail.RegionID = rpcCtx.Region.GetID()
 	}
 	sd := &util.ScanDetail{}
 	td := util.TimeDetail{}
@@ -1716,6 +1769,7 @@ type CopRuntimeStats struct {
 	execdetails.ExecDetails
 	tikv.RegionRequestRuntimeStats
 
+	RegionID     uint64
 	CoprCacheHit bool
 }
 
@@ -19,12 +19,14 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
+	"sync/atomic"
 	"time"
 	"unsafe"
 
 	"github.com/dgraph-io/ristretto"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/coprocessor"
+	copr_metrics "github.com/pingcap/tidb/store/copr/metrics"
 	"github.com/tikv/client-go/v2/config"
 )
 
@@ -33,6 +35,8 @@ type coprCache struct {
 	admissionMaxRanges      int
 	admissionMaxSize        int
 	admissionMinProcessTime time.Duration
+	// keysEvicted is the number of the evicted keys which have been reported to the metrics.
+	keysEvicted atomic.Uint64
 }
 
 type coprCacheValue struct {
@@ -81,17 +85,18 @@ func newCoprCache(config *config.CoprocessorCache) (*coprCache, error) {
 		NumCounters: estimatedEntities * 10,
 		MaxCost:     capacityInBytes,
 		BufferItems: 64,
+		Metrics:     true,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	c := coprCache{
+	c := &coprCache{
 		cache:                   cache,
 		admissionMaxRanges:      int(config.AdmissionMaxRanges),
 		admissionMaxSize:        int(maxEntityInBytes),
 		admissionMinProcessTime: time.Duration(config.AdmissionMinProcessMs) * time.Millisecond,
 	}
-	return &c, nil
+	return c, nil
 }
 
 func coprCacheBuildKey(copReq *coprocessor.Request) ([]byte, error) {
@@ -173,6 +178,29 @@ func (c *coprCache) Get(key []byte) *coprCacheValue {
 	return typedValue
 }
 
+// updateMetrics reports the size, the number of entries and the evictions of the cache to the metrics.
+func (c *coprCache) updateMetrics() {
+	if c == nil || c.cache.Metrics == nil {
+		return
+	}
+	m := c.cache.Metrics
+	copr_metrics.CoprCacheSize.Set(float64(m.CostAdded() - m.CostEvicted()))
+	copr_metrics.CoprCacheEntries.Set(float64(m.KeysAdded() - m.KeysEvicted()))
+	keysEvicted := m.KeysEvicted()
+	// The concurrent calls may read the evicted keys in any order, so only the
+	// one advancing the reported count reports the delta.
+	for {
+		reported := c.keysEvicted.Load()
+		if keysEvicted <= reported {
+			return
+		}
+		if c.keysEvicted.CompareAndSwap(reported, keysEvicted) {
+			copr_metrics.CoprCacheCounterEvict.Add(float64(keysEvicted - reported))
+			return
+		}
+	}
+}
+
 // CheckRequestAdmission checks whether a response item is worth caching.
 func (c *coprCache) CheckRequestAdmission(ranges int) bool {
 	if c == nil {
@@ -24,6 +24,8 @@ var (
 	CoprCacheCounterEvict prometheus.Counter
 	CoprCacheCounterHit   prometheus.Counter
 	CoprCacheCounterMiss  prometheus.Counter
+	CoprCacheSize         prometheus.Gauge
+	CoprCacheEntries      prometheus.Gauge
 )
 
 func init() {
@@ -35,4 +37,6 @@ func InitMetricsVars() {
 	CoprCacheCounterEvict = metrics.DistSQLCoprCacheCounter.WithLabelValues("evict")
 	CoprCacheCounterHit = metrics.DistSQLCoprCacheCounter.WithLabelValues("hit")
 	CoprCacheCounterMiss = metrics.DistSQLCoprCacheCounter.WithLabelValues("miss")
+	CoprCacheSize = metrics.DistSQLCoprCacheUsage.WithLabelValues("size")
+	CoprCacheEntries = metrics.DistSQLCoprCacheUsage.WithLabelValues("entries")
 }
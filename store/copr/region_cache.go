@@ -30,7 +30,9 @@ import (
 	"github.com/pingcap/tidb/util/mathutil"
 	"github.com/tikv/client-go/v2/metrics"
 	"github.com/tikv/client-go/v2/tikv"
+	"github.com/tikv/client-go/v2/tikvrpc"
 	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
 )
 
 // RegionCache wraps tikv.RegionCache.
@@ -211,6 +213,18 @@ func (c *RegionCache) OnSendFailForBatchRegions(bo *Backoffer, store *tikv.Store
 	}
 }
 
+// GetTiKVStoresExcept returns the IDs of the TiKV stores whose addresses are not in the given addresses.
+func (c *RegionCache) GetTiKVStoresExcept(addrs []string) []uint64 {
+	stores := c.GetStoresByType(tikvrpc.TiKV)
+	storeIDs := make([]uint64, 0, len(stores))
+	for _, store := range stores {
+		if !slices.Contains(addrs, store.GetAddr()) {
+			storeIDs = append(storeIDs, store.StoreID())
+		}
+	}
+	return storeIDs
+}
+
 // BuildBatchTask fetches store and peer info for cop task, wrap it as `batchedCopTask`.
 func (c *RegionCache) BuildBatchTask(bo *Backoffer, req *kv.Request, task *copTask, replicaRead kv.ReplicaReadType) (*batchedCopTask, error) {
 	var (
@@ -107,6 +107,10 @@ func (msm *MockSessionManager) GetProcessInfo(id uint64) (*util.ProcessInfo, boo
 func (*MockSessionManager) Kill(uint64, bool, bool) {
 }
 
+// KillForce implements the SessionManager.KillForce interface.
+func (*MockSessionManager) KillForce(uint64) {
+}
+
 // KillAllConnections implements the SessionManager.KillAllConnections interface.
 func (*MockSessionManager) KillAllConnections() {
 }
@@ -63,6 +63,15 @@ func (s *mockGCSSuite) compareJobInfoWithoutTime(jobInfo *importer.JobInfo, row
 	s.Equal(jobInfo.CreatedBy, row[12])
 }
 
+func (s *mockGCSSuite) checkImportFileStatus(row []interface{}, path, readBytes, importedRows, status string) {
+	// the file columns follow the 13 job columns.
+	s.Equal(path, row[13])
+	s.Equal(readBytes, row[15])
+	s.Equal(importedRows, row[16])
+	s.Equal(status, row[17])
+	s.Equal("", row[18])
+}
+
 func (s *mockGCSSuite) TestShowJob() {
 	s.prepareAndUseDB("test_show_job")
 	s.tk.MustExec("CREATE TABLE t1 (i INT PRIMARY KEY);")
@@ -205,18 +214,25 @@ func (s *mockGCSSuite) TestShowJob() {
 			ErrorMessage: "",
 		}
 		tk2 := testkit.NewTestKit(s.T(), s.store)
+		// one row per file, only the file of the finished subtask has progress.
 		rows = tk2.MustQuery(fmt.Sprintf("show import job %d", importer.TestLastImportJobID.Load())).Rows()
-		s.Len(rows, 1)
+		s.Len(rows, 2)
 		s.compareJobInfoWithoutTime(jobInfo, rows[0])
+		s.compareJobInfoWithoutTime(jobInfo, rows[1])
+		s.checkImportFileStatus(rows[0], "t.csv", "3", "2", "succeed")
+		s.checkImportFileStatus(rows[1], "t2.csv", "0", "0", "pending")
 
 		// resume the scheduler
 		scheduler.TestSyncChan <- struct{}{}
 		// wait second subtask finish
 		<-scheduler.TestSyncChan
 		rows = tk2.MustQuery(fmt.Sprintf("show import job %d", importer.TestLastImportJobID.Load())).Rows()
-		s.Len(rows, 1)
+		s.Len(rows, 2)
 		jobInfo.Summary.ImportedRows = 4
 		s.compareJobInfoWithoutTime(jobInfo, rows[0])
+		s.compareJobInfoWithoutTime(jobInfo, rows[1])
+		s.checkImportFileStatus(rows[0], "t.csv", "3", "2", "succeed")
+		s.checkImportFileStatus(rows[1], "t2.csv", "3", "2", "succeed")
 		// resume the scheduler
 		scheduler.TestSyncChan <- struct{}{}
 	}()
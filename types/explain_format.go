@@ -39,6 +39,14 @@ var (
 	ExplainFormatCostTrace = "cost_trace"
 	// ExplainFormatPlanCache prints the reason why can't use non-prepared plan cache by warning
 	ExplainFormatPlanCache = "plan_cache"
+	// ExplainFormatPlanFingerprint presents the output in tabular format with a fingerprint of every operator's subtree.
+	ExplainFormatPlanFingerprint = "plan_fingerprint"
+	// ExplainFormatMem presents the output in tabular format with the memory usage of every operator's plan structure
+	// and the estimated runtime memory of the operators which buffer their input.
+	ExplainFormatMem = "mem"
+	// ExplainFormatIndexChoice lists the candidate access paths of every data source with their estimated costs,
+	// and marks the chosen one.
+	ExplainFormatIndexChoice = "index_choice"
 
 	// ExplainFormats stores the valid formats for explain statement, used by validator.
 	ExplainFormats = []string{
@@ -54,5 +62,8 @@ var (
 		ExplainFormatTiDBJSON,
 		ExplainFormatCostTrace,
 		ExplainFormatPlanCache,
+		ExplainFormatPlanFingerprint,
+		ExplainFormatMem,
+		ExplainFormatIndexChoice,
 	}
 )
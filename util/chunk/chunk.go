@@ -155,6 +155,18 @@ func (c *Chunk) MemoryUsage() (sum int64) {
 	return
 }
 
+// DataSize returns the size of the data held by the chunk. Unlike MemoryUsage,
+// it doesn't count the unused capacity of the buffers.
+func (c *Chunk) DataSize() (sum int64) {
+	if c == nil {
+		return 0
+	}
+	for _, col := range c.columns {
+		sum += int64(len(col.nullBitmap)) + int64(len(col.offsets)*8) + int64(len(col.data))
+	}
+	return
+}
+
 // RequiredRows returns how many rows is considered full.
 func (c *Chunk) RequiredRows() int {
 	return c.requiredRows
@@ -612,6 +612,25 @@ func TestChunkMemoryUsage(t *testing.T) {
 	require.Equal(t, int64(expectedUsage), chk.MemoryUsage())
 }
 
+func TestChunkDataSize(t *testing.T) {
+	fieldTypes := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong), types.NewFieldType(mysql.TypeVarchar)}
+	chk := NewChunkWithCapacity(fieldTypes, 1024)
+	// The offsets of the var-length column start with 0.
+	require.Equal(t, int64(8), chk.DataSize())
+
+	chk.AppendInt64(0, 1)
+	chk.AppendString(1, "abc")
+	chk.AppendInt64(0, 2)
+	chk.AppendNull(1)
+	// len(c.nullBitmap) + len(c.offsets)*8 + len(c.data)
+	require.Equal(t, int64((1+0+2*8)+(1+3*8+3)), chk.DataSize())
+	require.Less(t, chk.DataSize(), chk.MemoryUsage())
+
+	// The capacity of the buffers reused is not counted.
+	chk.Reset()
+	require.Equal(t, int64(8), chk.DataSize())
+}
+
 func TestSwapColumn(t *testing.T) {
 	fieldTypes := make([]*types.FieldType, 0, 2)
 	fieldTypes = append(fieldTypes, types.NewFieldType(mysql.TypeFloat))
@@ -31,8 +31,14 @@ type List struct {
 
 	memTracker  *memory.Tracker // track memory usage.
 	consumedIdx int             // chunk index in "chunks", has been consumed.
+
+	// alloc allocates the new chunks of the List if it's not nil.
+	alloc ListAllocFunc
 }
 
+// ListAllocFunc allocates a chunk with the field types and capacity for the List.
+type ListAllocFunc = func(fieldTypes []*types.FieldType, capacity, maxChunkSize int) *Chunk
+
 // RowPtr is used to get a row from a list.
 // It is only valid for the list that returns it.
 type RowPtr struct {
@@ -52,6 +58,13 @@ func NewList(fieldTypes []*types.FieldType, initChunkSize, maxChunkSize int) *Li
 	return l
 }
 
+// NewListWithAlloc creates a new List whose new chunks are allocated by alloc.
+func NewListWithAlloc(fieldTypes []*types.FieldType, initChunkSize, maxChunkSize int, alloc ListAllocFunc) *List {
+	l := NewList(fieldTypes, initChunkSize, maxChunkSize)
+	l.alloc = alloc
+	return l
+}
+
 // GetMemTracker returns the memory tracker of this List.
 func (l *List) GetMemTracker() *memory.Tracker {
 	return l.memTracker
@@ -128,6 +141,13 @@ func (l *List) allocChunk() (chk *Chunk) {
 		chk.Reset()
 		return
 	}
+	if l.alloc != nil {
+		capacity := l.initChunkSize
+		if len(l.chunks) > 0 {
+			capacity = reCalcCapacity(l.chunks[len(l.chunks)-1], l.maxChunkSize)
+		}
+		return l.alloc(l.fieldTypes, capacity, l.maxChunkSize)
+	}
 	if len(l.chunks) > 0 {
 		return Renew(l.chunks[len(l.chunks)-1], l.maxChunkSize)
 	}
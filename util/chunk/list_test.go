@@ -81,6 +81,36 @@ func TestList(t *testing.T) {
 	require.Equal(t, expected, results)
 }
 
+func TestListWithAlloc(t *testing.T) {
+	fields := []*types.FieldType{
+		types.NewFieldType(mysql.TypeLonglong),
+	}
+	alloc := NewAllocator()
+	var capacities []int
+	l := NewListWithAlloc(fields, 2, 8, func(fieldTypes []*types.FieldType, capacity, maxChunkSize int) *Chunk {
+		capacities = append(capacities, capacity)
+		return alloc.Alloc(fieldTypes, capacity, maxChunkSize)
+	})
+	srcChunk := NewChunkWithCapacity(fields, 32)
+	srcChunk.AppendInt64(0, 1)
+	srcRow := srcChunk.GetRow(0)
+
+	for i := 0; i < 14; i++ {
+		l.AppendRow(srcRow)
+	}
+	require.Equal(t, 3, l.NumChunks())
+	require.Equal(t, 14, l.Len())
+	require.Equal(t, []int{2, 4, 8}, capacities)
+
+	// The chunks in the freelist are reused before allocating.
+	l.Reset()
+	for i := 0; i < 14; i++ {
+		l.AppendRow(srcRow)
+	}
+	require.Equal(t, []int{2, 4, 8}, capacities)
+	require.Equal(t, int64(1), l.GetRow(RowPtr{ChkIdx: 0, RowIdx: 7}).GetInt64(0))
+}
+
 func TestListMemoryUsage(t *testing.T) {
 	fieldTypes := make([]*types.FieldType, 0, 5)
 	fieldTypes = append(fieldTypes, types.NewFieldType(mysql.TypeFloat))
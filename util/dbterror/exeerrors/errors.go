@@ -58,12 +58,17 @@ var (
 	ErrInstanceScope                 = dbterror.ClassExecutor.NewStd(mysql.ErrInstanceScope)
 	ErrSettingNoopVariable           = dbterror.ClassExecutor.NewStd(mysql.ErrSettingNoopVariable)
 	ErrLazyUniquenessCheckFailure    = dbterror.ClassExecutor.NewStd(mysql.ErrLazyUniquenessCheckFailure)
+	ErrFileExists                    = dbterror.ClassExecutor.NewStd(mysql.ErrFileExists)
 
 	ErrBRIEBackupFailed               = dbterror.ClassExecutor.NewStd(mysql.ErrBRIEBackupFailed)
 	ErrBRIERestoreFailed              = dbterror.ClassExecutor.NewStd(mysql.ErrBRIERestoreFailed)
 	ErrBRIEImportFailed               = dbterror.ClassExecutor.NewStd(mysql.ErrBRIEImportFailed)
 	ErrBRIEExportFailed               = dbterror.ClassExecutor.NewStd(mysql.ErrBRIEExportFailed)
 	ErrBRJobNotFound                  = dbterror.ClassExecutor.NewStd(mysql.ErrBRJobNotFound)
+	ErrBatchPointGetTooLarge          = dbterror.ClassExecutor.NewStd(mysql.ErrBatchPointGetTooLarge)
+	ErrAdminCheckForeignKey           = dbterror.ClassExecutor.NewStd(mysql.ErrAdminCheckForeignKey)
+	ErrRowChecksumMismatch            = dbterror.ClassExecutor.NewStd(mysql.ErrRowChecksumMismatch)
+	ErrMemTableResultTooLarge         = dbterror.ClassExecutor.NewStd(mysql.ErrMemTableResultTooLarge)
 	ErrCTEMaxRecursionDepth           = dbterror.ClassExecutor.NewStd(mysql.ErrCTEMaxRecursionDepth)
 	ErrNotSupportedWithSem            = dbterror.ClassOptimizer.NewStd(mysql.ErrNotSupportedWithSem)
 	ErrPluginIsNotLoaded              = dbterror.ClassExecutor.NewStd(mysql.ErrPluginIsNotLoaded)
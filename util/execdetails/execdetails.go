@@ -60,6 +60,118 @@ type StmtExecDetails struct {
 	WriteSQLRespDuration time.Duration
 }
 
+type copRegionDetailsKeyType struct{}
+
+// CopRegionDetailsKey used to carry CopRegionDetails in context.Context.
+var CopRegionDetailsKey = copRegionDetailsKeyType{}
+
+// CopRegionDetail contains the execution detail of the coprocessor requests sent to a region on a store.
+type CopRegionDetail struct {
+	RegionID    uint64
+	StoreAddr   string
+	Requests    int
+	WaitTime    time.Duration
+	ProcessTime time.Duration
+	BackoffTime time.Duration
+}
+
+// CopRegionDetails collects the coprocessor execution details per region and store.
+type CopRegionDetails struct {
+	mu      sync.Mutex
+	details map[CopRegionDetail]*CopRegionDetail
+}
+
+// NewCopRegionDetails creates a new CopRegionDetails.
+func NewCopRegionDetails() *CopRegionDetails {
+	return &CopRegionDetails{details: make(map[CopRegionDetail]*CopRegionDetail)}
+}
+
+// Record records a coprocessor response of the region from the store.
+func (d *CopRegionDetails) Record(regionID uint64, storeAddr string, waitTime, processTime, backoffTime time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := CopRegionDetail{RegionID: regionID, StoreAddr: storeAddr}
+	detail, ok := d.details[key]
+	if !ok {
+		detail = &CopRegionDetail{RegionID: regionID, StoreAddr: storeAddr}
+		d.details[key] = detail
+	}
+	detail.Requests++
+	detail.WaitTime += waitTime
+	detail.ProcessTime += processTime
+	detail.BackoffTime += backoffTime
+}
+
+// Details returns the collected details ordered by region ID and store address.
+func (d *CopRegionDetails) Details() []CopRegionDetail {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	details := make([]CopRegionDetail, 0, len(d.details))
+	for _, detail := range d.details {
+		details = append(details, *detail)
+	}
+	slices.SortFunc(details, func(i, j CopRegionDetail) bool {
+		if i.RegionID != j.RegionID {
+			return i.RegionID < j.RegionID
+		}
+		return i.StoreAddr < j.StoreAddr
+	})
+	return details
+}
+
+type lockEventsKeyType struct{}
+
+// LockEventsKey used to carry LockEvents in context.Context.
+var LockEventsKey = lockEventsKeyType{}
+
+// LockEvent is an attempt to acquire the pessimistic locks of a batch of keys. The runtime stats
+// of the lock request are only known for the whole batch, so they're reported once per batch.
+type LockEvent struct {
+	Keys [][]byte
+	// Waited indicates whether the attempt ran into the lock of another transaction.
+	Waited      bool
+	LockTime    time.Duration
+	ResolveTime time.Duration
+	Err         error
+}
+
+// LockEvents collects the pessimistic lock attempts of a statement in order.
+type LockEvents struct {
+	mu     sync.Mutex
+	events []LockEvent
+}
+
+// NewLockEvents creates a new LockEvents.
+func NewLockEvents() *LockEvents {
+	return &LockEvents{}
+}
+
+// Record records an attempt to lock the keys, the stats may be nil if no lock request is sent.
+func (e *LockEvents) Record(keys [][]byte, stats *util.LockKeysDetails, lockTime time.Duration, err error) {
+	var resolveTime time.Duration
+	waited := err != nil
+	if stats != nil {
+		resolveTime = time.Duration(stats.ResolveLock.ResolveLockTime)
+		waited = waited || stats.LockedWithConflictCount > 0 || resolveTime > 0 || stats.BackoffTime > 0
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, LockEvent{
+		Keys:        keys,
+		Waited:      waited,
+		LockTime:    lockTime,
+		ResolveTime: resolveTime,
+		Err:         err,
+	})
+}
+
+// Events returns the recorded lock events.
+func (e *LockEvents) Events() []LockEvent {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return slices.Clone(e.events)
+}
+
 const (
 	// CopTimeStr represents the sum of cop-task time spend in TiDB distSQL.
 	CopTimeStr = "Cop_time"
@@ -1185,3 +1297,49 @@ func getUnit(d time.Duration) time.Duration {
 	}
 	return time.Nanosecond
 }
+
+// ConnRUUsage records the RU consumed by a connection. It's updated by the session goroutine
+// and read by the other sessions, so all the methods are thread-safe.
+type ConnRUUsage struct {
+	mu sync.Mutex
+	// stmtStats collects the RU of the current or last statement. It may be shared by all the
+	// statements in the same transaction, so stmtBase records its RU when the statement starts.
+	stmtStats *util.RURuntimeStats
+	stmtBase  float64
+	// consumed is the RU consumed by the statements before the current or last one.
+	consumed float64
+}
+
+// AttachStmtRUStats sets the RU runtime stats of a new statement. The RU of the previous statement
+// is accumulated into the consumed RU of the connection.
+func (u *ConnRUUsage) AttachStmtRUStats(stats *util.RURuntimeStats) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.consumed += u.stmtRULocked()
+	u.stmtStats = stats
+	u.stmtBase = 0
+	if stats != nil {
+		u.stmtBase = stats.RRU() + stats.WRU()
+	}
+}
+
+// StmtRU returns the RU consumed by the current or last statement.
+func (u *ConnRUUsage) StmtRU() float64 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.stmtRULocked()
+}
+
+// TotalRU returns the RU consumed by the connection.
+func (u *ConnRUUsage) TotalRU() float64 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.consumed + u.stmtRULocked()
+}
+
+func (u *ConnRUUsage) stmtRULocked() float64 {
+	if u.stmtStats == nil {
+		return 0
+	}
+	return u.stmtStats.RRU() + u.stmtStats.WRU() - u.stmtBase
+}
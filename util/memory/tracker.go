@@ -857,6 +857,12 @@ const (
 	LabelForSession int = -27
 	// LabelForMemDB represents the label of the MemDB
 	LabelForMemDB int = -28
+	// LabelForLoadStats represents the label of the load stats
+	LabelForLoadStats int = -29
+	// LabelForAnalyzeSamples represents the label of the samples held by all the tasks of an analyze statement
+	LabelForAnalyzeSamples int = -30
+	// LabelForTableReaderHandles represents the label of the handles a table reader is built from
+	LabelForTableReaderHandles int = -31
 )
 
 // MetricsTypes is used to get label for metrics
@@ -50,6 +50,7 @@ type ProcessInfo struct {
 	DiskTracker           *disk.Tracker
 	StatsInfo             func(interface{}) map[string]uint64
 	RuntimeStatsColl      *execdetails.RuntimeStatsColl
+	RUUsage               *execdetails.ConnRUUsage
 	DB                    string
 	Digest                string
 	Host                  string
@@ -186,6 +187,9 @@ type SessionManager interface {
 	ShowTxnList() []*txninfo.TxnInfo
 	GetProcessInfo(id uint64) (*ProcessInfo, bool)
 	Kill(connectionID uint64, query bool, maxExecutionTime bool)
+	// KillForce closes the network connection of connectionID without waiting for its running statement,
+	// whose transaction is rolled back in the background once the statement returns.
+	KillForce(connectionID uint64)
 	KillAllConnections()
 	UpdateTLSConfig(cfg *tls.Config)
 	ServerID() uint64
@@ -14,7 +14,11 @@
 
 package tracing
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+	"time"
+)
 
 // PlanTrace indicates for the Plan trace information
 type PlanTrace struct {
@@ -294,3 +298,58 @@ func (d *PhysicalPlanCostDetail) Exists(k string) bool {
 	_, ok := d.Params[k]
 	return ok
 }
+
+const (
+	// OptRulePhaseLogical is the phase of the logical optimization rules.
+	OptRulePhaseLogical = "logical"
+	// OptRulePhasePhysical is the phase of the physical optimization.
+	OptRulePhasePhysical = "physical"
+)
+
+type optRuleStatsKeyType struct{}
+
+// OptRuleStatsKey is used to carry OptRuleStats in context.Context.
+var OptRuleStatsKey = optRuleStatsKeyType{}
+
+// OptRuleStat is the application count and the total time of an optimization rule.
+type OptRuleStat struct {
+	Phase string
+	Name  string
+	Count int
+	Time  time.Duration
+}
+
+// OptRuleStats collects the optimization rules applied to a statement in the order of their first applications.
+type OptRuleStats struct {
+	mu    sync.Mutex
+	stats []OptRuleStat
+}
+
+// NewOptRuleStats creates a new OptRuleStats.
+func NewOptRuleStats() *OptRuleStats {
+	return &OptRuleStats{}
+}
+
+// Record records an application of the rule, it does nothing if the OptRuleStats is nil.
+func (s *OptRuleStats) Record(phase, name string, d time.Duration) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.stats {
+		if s.stats[i].Phase == phase && s.stats[i].Name == name {
+			s.stats[i].Count++
+			s.stats[i].Time += d
+			return
+		}
+	}
+	s.stats = append(s.stats, OptRuleStat{Phase: phase, Name: name, Count: 1, Time: d})
+}
+
+// Stats returns the recorded stats of the rules.
+func (s *OptRuleStats) Stats() []OptRuleStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]OptRuleStat(nil), s.stats...)
+}